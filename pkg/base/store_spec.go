@@ -179,6 +179,11 @@ type StoreSpec struct {
 	// ExtraOptions is a serialized protobuf set by Go CCL code and passed through
 	// to C CCL code.
 	ExtraOptions []byte
+	// RaftPath, if set, is the directory of a separate engine instance holding
+	// the store's Raft log and HardState. This allows the log, which sees a
+	// write-heavy, fsync-dominated workload, to be placed on a different disk
+	// than the state machine. Only on-disk stores support it.
+	RaftPath string
 }
 
 // String returns a fully parsable version of the store spec.
@@ -187,6 +192,9 @@ func (ss StoreSpec) String() string {
 	if len(ss.Path) != 0 {
 		fmt.Fprintf(&buffer, "path=%s,", ss.Path)
 	}
+	if len(ss.RaftPath) != 0 {
+		fmt.Fprintf(&buffer, "raft-path=%s,", ss.RaftPath)
+	}
 	if ss.InMemory {
 		fmt.Fprint(&buffer, "type=mem,")
 	}
@@ -243,6 +251,8 @@ var fractionRegex = regexp.MustCompile(`^([-]?([0-9]+\.[0-9]*|[0-9]*\.[0-9]+|[0-
 //   - 20%             -> 20% of the available space
 //   - 0.2             -> 20% of the available space
 // - attrs=xxx:yyy:zzz A colon separated list of optional attributes.
+// - raft-path=xxx The optional directory in which a separate engine holding
+//   the Raft log is located. Not available for in memory stores.
 // Note that commas are forbidden within any field name or value.
 func NewStoreSpec(value string) (StoreSpec, error) {
 	const pathField = "path"
@@ -318,6 +328,12 @@ func NewStoreSpec(value string) (StoreSpec, error) {
 			}
 		case "rocksdb":
 			ss.RocksDBOptions = value
+		case "raft-path":
+			var err error
+			ss.RaftPath, err = GetAbsoluteStorePath(field, value)
+			if err != nil {
+				return StoreSpec{}, err
+			}
 		default:
 			return StoreSpec{}, fmt.Errorf("%s is not a valid store field", field)
 		}
@@ -330,8 +346,13 @@ func NewStoreSpec(value string) (StoreSpec, error) {
 		if ss.Size.Percent == 0 && ss.Size.InBytes == 0 {
 			return StoreSpec{}, fmt.Errorf("size must be specified for an in memory store")
 		}
+		if ss.RaftPath != "" {
+			return StoreSpec{}, fmt.Errorf("raft-path specified for in memory store")
+		}
 	} else if ss.Path == "" {
 		return StoreSpec{}, fmt.Errorf("no path specified")
+	} else if ss.RaftPath != "" && filepath.Clean(ss.RaftPath) == filepath.Clean(ss.Path) {
+		return StoreSpec{}, fmt.Errorf("raft-path must differ from path")
 	}
 	return ss, nil
 }
@@ -121,6 +121,14 @@ func TestNewStoreSpec(t *testing.T) {
 		// RocksDB
 		{"path=/,rocksdb=key1=val1;key2=val2", "", StoreSpec{Path: "/", RocksDBOptions: "key1=val1;key2=val2"}},
 
+		// raft-path
+		{"path=/mnt/hda1,raft-path=/mnt/hdb1", "", StoreSpec{Path: "/mnt/hda1", RaftPath: "/mnt/hdb1"}},
+		{"raft-path=/mnt/hdb1,path=/mnt/hda1", "", StoreSpec{Path: "/mnt/hda1", RaftPath: "/mnt/hdb1"}},
+		{"path=/mnt/hda1,raft-path=", "no value specified for raft-path", StoreSpec{}},
+		{"path=/mnt/hda1,raft-path=/mnt/hda1/", "raft-path must differ from path", StoreSpec{}},
+		{"raft-path=/mnt/hdb1", "no path specified", StoreSpec{}},
+		{"type=mem,size=20GiB,raft-path=/mnt/hdb1", "raft-path specified for in memory store", StoreSpec{}},
+
 		// all together
 		{"path=/mnt/hda1,attrs=hdd:ssd,size=20GiB", "", StoreSpec{
 			Path:       "/mnt/hda1",
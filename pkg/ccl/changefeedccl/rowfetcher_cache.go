@@ -100,6 +100,7 @@ func (c *rowFetcherCache) RowFetcherForTableDesc(
 	if err := rf.Init(
 		false, /* reverse */
 		sqlbase.ScanLockingStrength_FOR_NONE,
+		sqlbase.ScanLockingWaitPolicy_BLOCK,
 		false, /* returnRangeInfo */
 		false, /* isCheck */
 		&c.a,
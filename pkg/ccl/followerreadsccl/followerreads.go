@@ -73,6 +73,10 @@ func evalFollowerReadOffset(clusterID uuid.UUID, st *cluster.Settings) (time.Dur
 	return getFollowerReadDuration(st), nil
 }
 
+func checkBoundedStalenessReadsEnabled(clusterID uuid.UUID, st *cluster.Settings) error {
+	return checkEnterpriseEnabled(clusterID, st)
+}
+
 // batchCanBeEvaluatedOnFollower determines if a batch consists exclusively of
 // requests that can be evaluated on a follower replica.
 func batchCanBeEvaluatedOnFollower(ba roachpb.BatchRequest) bool {
@@ -141,5 +145,6 @@ var followerReadAwareChoice = replicaoracle.RegisterPolicy(newOracleFactory)
 func init() {
 	sql.ReplicaOraclePolicy = followerReadAwareChoice
 	builtins.EvalFollowerReadOffset = evalFollowerReadOffset
+	builtins.CheckBoundedStalenessReadsEnabled = checkBoundedStalenessReadsEnabled
 	kv.CanSendToFollower = canSendToFollower
 }
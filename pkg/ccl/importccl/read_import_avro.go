@@ -152,6 +152,8 @@ var familyToAvroT = map[types.Family][]string{
 	types.CollatedStringFamily: {"string"},
 	types.INetFamily:           {"string"},
 	types.JsonFamily:           {"string"},
+	types.TSVectorFamily:       {"string"},
+	types.TSQueryFamily:        {"string"},
 	types.BitFamily:            {"string"},
 	types.DecimalFamily:        {"string"},
 }
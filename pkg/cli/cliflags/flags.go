@@ -707,6 +707,15 @@ memory that the store may consume, for example:
   --store=type=mem,size=90%
 
 </PRE>
+The "raft-path" field can be used to keep the Raft logs of an on-disk store in a
+separate directory, typically on a different device, for example:
+<PRE>
+
+  --store=path=/mnt/hda1,raft-path=/mnt/ssd01/raft
+
+</PRE>
+Once a store has been started with a "raft-path", it can't be started without
+it anymore.
 Commas are forbidden in all values, since they are used to separate fields.
 Also, if you use equal signs in the file path to a store, you must use the
 "path" field label.`,
@@ -826,6 +835,13 @@ is the prefix for range local keys. The hex format takes an encoded MVCCKey.`}
 		Description: "Restrict scan to replicated data.",
 	}
 
+	RaftPath = FlagInfo{
+		Name: "raft-path",
+		Description: `
+Directory of the separate engine holding the store's Raft log, for stores
+started with the raft-path field of the --store flag.`,
+	}
+
 	GossipInputFile = FlagInfo{
 		Name:      "file",
 		Shorthand: "f",
@@ -97,6 +97,7 @@ func initCLIDefaults() {
 	debugCtx.values = false
 	debugCtx.sizes = false
 	debugCtx.replicated = false
+	debugCtx.raftPath = ""
 	debugCtx.inputFile = ""
 	debugCtx.printSystemConfig = false
 	debugCtx.maxResults = 1000
@@ -278,6 +279,7 @@ var debugCtx struct {
 	values            bool
 	sizes             bool
 	replicated        bool
+	raftPath          string
 	inputFile         string
 	ballastSize       base.SizeSpec
 	printSystemConfig bool
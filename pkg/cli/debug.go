@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cli/cliflags"
 	"github.com/cockroachdb/cockroach/pkg/cli/syncbench"
 	"github.com/cockroachdb/cockroach/pkg/config"
 	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
@@ -38,12 +39,11 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/server/status/statuspb"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/storage"
-	"github.com/cockroachdb/cockroach/pkg/storage/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/storage/gc"
+	"github.com/cockroachdb/cockroach/pkg/storage/loqrecovery"
 	"github.com/cockroachdb/cockroach/pkg/storage/rditer"
-	"github.com/cockroachdb/cockroach/pkg/storage/stateloader"
 	"github.com/cockroachdb/cockroach/pkg/storage/storagepb"
 	"github.com/cockroachdb/cockroach/pkg/ts/tspb"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
@@ -176,6 +176,46 @@ func OpenEngine(dir string, stopper *stop.Stopper, opts OpenEngineOptions) (engi
 	return db, nil
 }
 
+// openExistingRaftEngine returns the engine holding the Raft logs and
+// HardStates of the store whose engine is db. This is db itself, unless the
+// store keeps its Raft log in a separate engine, which is then opened
+// (read-only) from the directory passed with --raft-path.
+func openExistingRaftEngine(
+	ctx context.Context, db engine.Engine, stopper *stop.Stopper,
+) (engine.Engine, error) {
+	separate, err := storage.UsesSeparateRaftEngine(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	if !separate {
+		if debugCtx.raftPath != "" {
+			return nil, errors.Errorf("store does not keep its Raft log in a separate engine, "+
+				"but --%s was specified", cliflags.RaftPath.Name)
+		}
+		return db, nil
+	}
+	if debugCtx.raftPath == "" {
+		return nil, errors.Errorf("store keeps its Raft log in a separate engine, "+
+			"whose directory must be specified with --%s", cliflags.RaftPath.Name)
+	}
+	raftEng, err := OpenExistingStore(debugCtx.raftPath, stopper, true /* readOnly */)
+	if err != nil {
+		return nil, errors.Wrap(err, "opening Raft engine")
+	}
+	ident, err := storage.ReadStoreIdent(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	raftIdent, err := storage.ReadStoreIdent(ctx, raftEng)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading store ident of Raft engine")
+	}
+	if raftIdent != ident {
+		return nil, errors.Errorf("Raft engine belongs to store %+v, not %+v", raftIdent, ident)
+	}
+	return raftEng, nil
+}
+
 func printKey(kv engine.MVCCKeyValue) (bool, error) {
 	fmt.Printf("%s %s: ", kv.Key.Timestamp, kv.Key.Key)
 	if debugCtx.sizes {
@@ -290,6 +330,13 @@ func runDebugRangeData(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	var raftEng engine.Engine
+	if !debugCtx.replicated {
+		if raftEng, err = openExistingRaftEngine(context.Background(), db, stopper); err != nil {
+			return err
+		}
+	}
+
 	iter := rditer.NewReplicaDataIterator(&desc, db, debugCtx.replicated, false /* seekEnd */)
 	defer iter.Close()
 	for ; ; iter.Next() {
@@ -303,7 +350,21 @@ func runDebugRangeData(cmd *cobra.Command, args []string) error {
 			Value: iter.Value(),
 		})
 	}
-	return nil
+	if raftEng == nil || raftEng == db {
+		return nil
+	}
+
+	// The HardState and Raft log live in the separate Raft engine.
+	printKV := func(kv engine.MVCCKeyValue) (bool, error) {
+		storage.PrintKeyValue(kv)
+		return false, nil
+	}
+	hsKey := keys.RaftHardStateKey(rangeID)
+	if err := raftEng.Iterate(hsKey, hsKey.Next(), printKV); err != nil {
+		return err
+	}
+	logPrefix := keys.RaftLogPrefix(rangeID)
+	return raftEng.Iterate(logPrefix, logPrefix.PrefixEnd(), printKV)
 }
 
 var debugRangeDescriptorsCmd = &cobra.Command{
@@ -445,11 +506,55 @@ Decode and print a hexadecimal-encoded key-value pair.
 	},
 }
 
+var debugConsistencyDiffCmd = &cobra.Command{
+	Use:   "consistency-diff <directory> [<report>]",
+	Short: "print a consistency diff report",
+	Long: `
+Pretty-prints a report written by the consistency checker upon finding an
+inconsistency. Reports are placed in the auxiliary/consistency-diffs
+directory of the store that ran the check, which is usually the store
+holding the lease for the inconsistent range, and are read through the
+store so that reports on encrypted stores can be printed too. The report
+can be given by its path or by its name; if it is omitted, the names of
+the store's reports are listed instead.
+`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: MaybeDecorateGRPCError(runDebugConsistencyDiff),
+}
+
+func runDebugConsistencyDiff(cmd *cobra.Command, args []string) error {
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.Background())
+
+	db, err := OpenExistingStore(args[0], stopper, true /* readOnly */)
+	if err != nil {
+		return err
+	}
+	if len(args) == 1 {
+		paths, err := storage.ListConsistencyDiffReports(db)
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			fmt.Println(filepath.Base(path))
+		}
+		return nil
+	}
+	report, err := storage.ReadConsistencyDiffReport(db, args[1])
+	if err != nil {
+		return err
+	}
+	_, err = report.WriteTo(os.Stdout)
+	return err
+}
+
 var debugRaftLogCmd = &cobra.Command{
 	Use:   "raft-log <directory> <range id>",
 	Short: "print the raft log for a range",
 	Long: `
-Prints all log entries in a store for the given range.
+Prints all log entries in a store for the given range. If the store keeps its
+Raft log in a separate engine, the directory of that engine must be passed with
+--raft-path.
 `,
 	Args: cobra.ExactArgs(2),
 	RunE: MaybeDecorateGRPCError(runDebugRaftLog),
@@ -463,6 +568,10 @@ func runDebugRaftLog(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	raftEng, err := openExistingRaftEngine(context.Background(), db, stopper)
+	if err != nil {
+		return err
+	}
 
 	rangeID, err := parseRangeID(args[1])
 	if err != nil {
@@ -476,7 +585,7 @@ func runDebugRaftLog(cmd *cobra.Command, args []string) error {
 		string(engine.EncodeKey(engine.MakeMVCCMetadataKey(start))),
 		string(engine.EncodeKey(engine.MakeMVCCMetadataKey(end))))
 
-	return db.Iterate(start, end, func(kv engine.MVCCKeyValue) (bool, error) {
+	return raftEng.Iterate(start, end, func(kv engine.MVCCKeyValue) (bool, error) {
 		storage.PrintKeyValue(kv)
 		return false, nil
 	})
@@ -917,7 +1026,9 @@ var debugUnsafeRemoveDeadReplicasCmd = &cobra.Command{
 This command is UNSAFE and should only be used with the supervision of
 a Cockroach Labs engineer. It is a last-resort option to recover data
 after multiple node failures. The recovered data is not guaranteed to
-be consistent.
+be consistent. Consider using 'cockroach debug recover' instead, which
+plans the recovery across all surviving stores at once and picks the
+most up-to-date replica of each range.
 
 The --dead-store-ids flag takes a comma-separated list of dead store
 IDs and scans this store for any ranges whose only live replica is on
@@ -1068,90 +1179,14 @@ func removeDeadReplicas(
 
 	batch := db.NewBatch()
 	for _, desc := range newDescs {
-		// Write the rewritten descriptor to the range-local descriptor
-		// key. We do not update the meta copies of the descriptor.
-		// Instead, we leave them in a temporarily inconsistent state and
-		// they will be overwritten when the cluster recovers and
-		// up-replicates this range from its single copy to multiple
-		// copies. We rely on the fact that all range descriptor updates
-		// start with a CPut on the range-local copy followed by a blind
-		// Put to the meta copy.
-		//
-		// For example, if we have replicas on s1-s4 but s3 and s4 are
-		// dead, we will rewrite the replica on s2 to have s2 as its only
-		// member only. When the cluster is restarted (and the dead nodes
-		// remain dead), the rewritten replica will be the only one able
-		// to make progress. It will elect itself leader and upreplicate.
-		//
-		// The old replica on s1 is untouched by this process. It will
-		// eventually either be overwritten by a new replica when s2
-		// upreplicates, or it will be destroyed by the replica GC queue
-		// after upreplication has happened and s1 is no longer a member.
-		// (Note that in the latter case, consistency between s1 and s2 no
-		// longer matters; the consistency checker will only run on nodes
-		// that the new leader believes are members of the range).
-		//
-		// Note that this tool does not guarantee fully consistent
-		// results; the most recent writes to the raft log may have been
-		// lost. In the most unfortunate cases, this means that we would
-		// be "winding back" a split or a merge, which is almost certainly
-		// to result in irrecoverable corruption (for example, not only
-		// will individual values stored in the meta ranges diverge, but
-		// there will be keys not represented by any ranges or vice
-		// versa).
-		key := keys.RangeDescriptorKey(desc.StartKey)
-		sl := stateloader.Make(desc.RangeID)
-		ms, err := sl.LoadMVCCStats(ctx, batch)
+		abortedTxn, err := loqrecovery.RewriteRangeDescriptor(ctx, batch, desc, clock.Now())
 		if err != nil {
-			return nil, errors.Wrap(err, "loading MVCCStats")
-		}
-		err = engine.MVCCPutProto(ctx, batch, &ms, key, clock.Now(), nil /* txn */, &desc)
-		if wiErr, ok := err.(*roachpb.WriteIntentError); ok {
-			if len(wiErr.Intents) != 1 {
-				return nil, errors.Errorf("expected 1 intent, found %d: %s", len(wiErr.Intents), wiErr)
-			}
-			intent := wiErr.Intents[0]
-			// We rely on the property that transactions involving the range
-			// descriptor always start on the range-local descriptor's key.
-			// This guarantees that when the transaction commits, the intent
-			// will be resolved synchronously. If we see an intent on this
-			// key, we know that the transaction did not commit and we can
-			// abort it.
-			//
-			// TODO(nvanbenschoten): This need updating for parallel
-			// commits. If the transaction record is in the STAGING state,
-			// we can't just delete it. Simplest solution to this is to
-			// avoid parallel commits for membership change transactions; if
-			// we can't do that I don't think we'll be able to recover them
-			// with an offline tool.
-			fmt.Printf("Conflicting intent found on %s. Aborting txn %s to resolve.\n", key, intent.Txn.ID)
-
-			// A crude form of the intent resolution process: abort the
-			// transaction by deleting its record.
-			txnKey := keys.TransactionKey(intent.Txn.Key, intent.Txn.ID)
-			if err := engine.MVCCDelete(ctx, batch, &ms, txnKey, hlc.Timestamp{}, nil); err != nil {
-				return nil, err
-			}
-			update := roachpb.LockUpdate{
-				Span:       roachpb.Span{Key: intent.Key},
-				Txn:        intent.Txn,
-				Status:     roachpb.ABORTED,
-				Durability: lock.Replicated,
-			}
-			if _, err := engine.MVCCResolveWriteIntent(ctx, batch, &ms, update); err != nil {
-				return nil, err
-			}
-			// With the intent resolved, we can try again.
-			if err := engine.MVCCPutProto(ctx, batch, &ms, key, clock.Now(),
-				nil /* txn */, &desc); err != nil {
-				return nil, err
-			}
-		} else if err != nil {
 			batch.Close()
 			return nil, err
 		}
-		if err := sl.SetMVCCStats(ctx, batch, &ms); err != nil {
-			return nil, errors.Wrap(err, "updating MVCCStats")
+		if abortedTxn != nil {
+			fmt.Printf("Conflicting intent found on %s. Aborted txn %s to resolve.\n",
+				keys.RangeDescriptorKey(desc.StartKey), abortedTxn.ID)
 		}
 	}
 
@@ -1216,6 +1251,7 @@ var debugCmds = append(DebugCmdsForRocksDB,
 	debugBallastCmd,
 	debugDecodeKeyCmd,
 	debugDecodeValueCmd,
+	debugConsistencyDiffCmd,
 	debugRocksDBCmd,
 	debugSSTDumpCmd,
 	debugGossipValuesCmd,
@@ -1223,6 +1259,7 @@ var debugCmds = append(DebugCmdsForRocksDB,
 	debugSyncBenchCmd,
 	debugSyncTestCmd,
 	debugUnsafeRemoveDeadReplicasCmd,
+	debugRecoverCmd,
 	debugEnvCmd,
 	debugZipCmd,
 	debugMergeLogsCommand,
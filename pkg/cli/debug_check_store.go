@@ -226,6 +226,10 @@ func checkStoreRaftState(
 	if err != nil {
 		return err
 	}
+	raftEng, err := openExistingRaftEngine(ctx, db, stopper)
+	if err != nil {
+		return err
+	}
 
 	// Iterate over the entire range-id-local space.
 	start := roachpb.Key(keys.LocalRangeIDPrefix)
@@ -240,60 +244,69 @@ func checkStoreRaftState(
 		return replicaInfo[rangeID]
 	}
 
-	if _, err := engine.MVCCIterate(ctx, db, start, end, hlc.MaxTimestamp,
-		engine.MVCCScanOptions{Inconsistent: true}, func(kv roachpb.KeyValue) (bool, error) {
-			rangeID, _, suffix, detail, err := keys.DecodeRangeIDKey(kv.Key)
+	visit := func(kv roachpb.KeyValue) (bool, error) {
+		rangeID, _, suffix, detail, err := keys.DecodeRangeIDKey(kv.Key)
+		if err != nil {
+			return false, err
+		}
+
+		switch {
+		case bytes.Equal(suffix, keys.LocalRaftHardStateSuffix):
+			var hs raftpb.HardState
+			if err := kv.Value.GetProto(&hs); err != nil {
+				return false, err
+			}
+			getReplicaInfo(rangeID).committedIndex = hs.Commit
+		case bytes.Equal(suffix, keys.LocalRaftTruncatedStateLegacySuffix):
+			var trunc roachpb.RaftTruncatedState
+			if err := kv.Value.GetProto(&trunc); err != nil {
+				return false, err
+			}
+			getReplicaInfo(rangeID).truncatedIndex = trunc.Index
+		case bytes.Equal(suffix, keys.LocalRangeAppliedStateSuffix):
+			var state enginepb.RangeAppliedState
+			if err := kv.Value.GetProto(&state); err != nil {
+				return false, err
+			}
+			getReplicaInfo(rangeID).appliedIndex = state.RaftAppliedIndex
+		case bytes.Equal(suffix, keys.LocalRaftAppliedIndexLegacySuffix):
+			idx, err := kv.Value.GetInt()
 			if err != nil {
 				return false, err
 			}
-
-			switch {
-			case bytes.Equal(suffix, keys.LocalRaftHardStateSuffix):
-				var hs raftpb.HardState
-				if err := kv.Value.GetProto(&hs); err != nil {
-					return false, err
-				}
-				getReplicaInfo(rangeID).committedIndex = hs.Commit
-			case bytes.Equal(suffix, keys.LocalRaftTruncatedStateLegacySuffix):
-				var trunc roachpb.RaftTruncatedState
-				if err := kv.Value.GetProto(&trunc); err != nil {
-					return false, err
-				}
-				getReplicaInfo(rangeID).truncatedIndex = trunc.Index
-			case bytes.Equal(suffix, keys.LocalRangeAppliedStateSuffix):
-				var state enginepb.RangeAppliedState
-				if err := kv.Value.GetProto(&state); err != nil {
-					return false, err
-				}
-				getReplicaInfo(rangeID).appliedIndex = state.RaftAppliedIndex
-			case bytes.Equal(suffix, keys.LocalRaftAppliedIndexLegacySuffix):
-				idx, err := kv.Value.GetInt()
-				if err != nil {
-					return false, err
-				}
-				getReplicaInfo(rangeID).appliedIndex = uint64(idx)
-			case bytes.Equal(suffix, keys.LocalRaftLogSuffix):
-				_, index, err := encoding.DecodeUint64Ascending(detail)
-				if err != nil {
-					return false, err
-				}
-				ri := getReplicaInfo(rangeID)
-				if ri.firstIndex == 0 {
-					ri.firstIndex = index
-					ri.lastIndex = index
-				} else {
-					if index != ri.lastIndex+1 {
-						printf("range %s: log index anomaly: %v followed by %v\n",
-							rangeID, ri.lastIndex, index)
-					}
-					ri.lastIndex = index
+			getReplicaInfo(rangeID).appliedIndex = uint64(idx)
+		case bytes.Equal(suffix, keys.LocalRaftLogSuffix):
+			_, index, err := encoding.DecodeUint64Ascending(detail)
+			if err != nil {
+				return false, err
+			}
+			ri := getReplicaInfo(rangeID)
+			if ri.firstIndex == 0 {
+				ri.firstIndex = index
+				ri.lastIndex = index
+			} else {
+				if index != ri.lastIndex+1 {
+					printf("range %s: log index anomaly: %v followed by %v\n",
+						rangeID, ri.lastIndex, index)
 				}
+				ri.lastIndex = index
 			}
+		}
 
-			return false, nil
-		}); err != nil {
+		return false, nil
+	}
+	if _, err := engine.MVCCIterate(ctx, db, start, end, hlc.MaxTimestamp,
+		engine.MVCCScanOptions{Inconsistent: true}, visit); err != nil {
 		return err
 	}
+	// If the store keeps its Raft log in a separate engine, the HardStates
+	// and log entries are found there.
+	if raftEng != db {
+		if _, err := engine.MVCCIterate(ctx, raftEng, start, end, hlc.MaxTimestamp,
+			engine.MVCCScanOptions{Inconsistent: true}, visit); err != nil {
+			return err
+		}
+	}
 
 	for rangeID, info := range replicaInfo {
 		if info.truncatedIndex != 0 && info.truncatedIndex != info.firstIndex-1 {
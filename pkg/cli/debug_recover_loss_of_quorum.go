@@ -0,0 +1,389 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"bufio"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/loqrecovery"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var debugRecoverCmd = &cobra.Command{
+	Use:   "recover [command]",
+	Short: "commands to recover unavailable ranges in case of quorum loss",
+	Long: `Set of commands to recover unavailable ranges.
+
+If a majority of the replicas of a range are permanently lost, the range
+can't make progress. These commands turn the most up-to-date surviving
+replica of each such range into its only member, after which the range
+recovers and up-replicates from that replica.
+
+These commands are UNSAFE and should only be used with the supervision
+of a Cockroach Labs engineer. They are a last-resort option to recover
+data after multiple node failures. The recovered data is not guaranteed
+to be consistent.
+
+The recovery proceeds as follows:
+
+1. Stop all surviving nodes.
+
+2. Run 'cockroach debug recover collect-info' against the stores of each
+   surviving node and gather the resulting files in one place.
+
+3. Run 'cockroach debug recover make-plan' on the collected files to
+   compute the recovery plan.
+
+4. Run 'cockroach debug recover apply-plan' with the plan against the
+   stores of each surviving node.
+
+5. Restart the surviving nodes, and once the cluster is available, run
+   'cockroach debug recover verify' with the plan to check the recovered
+   ranges for consistency.
+
+The dead stores must never rejoin the cluster after a plan was applied,
+or data may be corrupted.
+`,
+	RunE: usageAndErr,
+}
+
+var debugRecoverCollectInfoCmd = &cobra.Command{
+	Use:   "collect-info <store-dir> [<store-dir>...]",
+	Short: "collect the replica info of the stores of a node",
+	Long: `
+Collects the descriptors and raft state of all replicas found on the given
+stores, and writes them to standard output (or the file given with --out)
+for use by 'cockroach debug recover make-plan'.
+
+The stores must not be in use by a running node.
+`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: MaybeDecorateGRPCError(runDebugRecoverCollectInfo),
+}
+
+var debugRecoverCollectInfoOpts struct {
+	outputFile string
+}
+
+func runDebugRecoverCollectInfo(cmd *cobra.Command, args []string) error {
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.Background())
+
+	var info loqrecovery.NodeReplicaInfo
+	for _, dir := range args {
+		db, err := OpenExistingStore(dir, stopper, true /* readOnly */)
+		if err != nil {
+			return errors.Wrapf(err, "opening store %s", dir)
+		}
+		replicas, err := loqrecovery.CollectReplicaInfo(context.Background(), db)
+		if err != nil {
+			return errors.Wrapf(err, "collecting replica info from store %s", dir)
+		}
+		fmt.Fprintf(stderr, "Collected info on %d replicas from store %s\n", len(replicas), dir)
+		info.Replicas = append(info.Replicas, replicas...)
+	}
+	return writeRecoveryJSON(debugRecoverCollectInfoOpts.outputFile, info)
+}
+
+var debugRecoverMakePlanCmd = &cobra.Command{
+	Use:   "make-plan <info-file> [<info-file>...]",
+	Short: "compute a recovery plan from the replica info of all surviving stores",
+	Long: `
+Computes the plan that recovers all ranges that lost quorum from the
+replica info files produced by 'cockroach debug recover collect-info' on
+all surviving nodes, and writes it to standard output (or the file given
+with --out).
+
+Stores that are referenced by range descriptors but from which no info
+was collected are considered dead. If --dead-store-ids is given, it must
+match that set of stores.
+
+For each range that lost quorum, the surviving replica that applied the
+most of the raft log is chosen as the new sole member of the range. The
+plan is refused if the chosen replicas do not cover the keyspace exactly
+once, unless --force is given.
+`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: MaybeDecorateGRPCError(runDebugRecoverMakePlan),
+}
+
+var debugRecoverMakePlanOpts struct {
+	outputFile   string
+	deadStoreIDs []int
+	force        bool
+}
+
+func runDebugRecoverMakePlan(cmd *cobra.Command, args []string) error {
+	var nodes []loqrecovery.NodeReplicaInfo
+	for _, filename := range args {
+		var info loqrecovery.NodeReplicaInfo
+		if err := readRecoveryJSON(filename, &info); err != nil {
+			return err
+		}
+		nodes = append(nodes, info)
+	}
+
+	var deadStoreIDs []roachpb.StoreID
+	for _, id := range debugRecoverMakePlanOpts.deadStoreIDs {
+		deadStoreIDs = append(deadStoreIDs, roachpb.StoreID(id))
+	}
+	plan, problems, err := loqrecovery.PlanReplicas(context.Background(), nodes, deadStoreIDs)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stderr, "Dead stores: %v\n", plan.DeadStoreIDs)
+	for _, update := range plan.Updates {
+		fmt.Fprintf(stderr, "Recovering r%d from replica %s\n", update.RangeID, update.NewReplica)
+	}
+	if len(problems) > 0 {
+		fmt.Fprintf(stderr, "Found problems with the surviving replicas:\n")
+		for _, problem := range problems {
+			fmt.Fprintf(stderr, "  %s\n", problem)
+		}
+		if !debugRecoverMakePlanOpts.force {
+			return errors.New("refusing to make a plan; use --force to override")
+		}
+		fmt.Fprintf(stderr, "Ignoring problems as requested by --force\n")
+	}
+	if len(plan.Updates) == 0 {
+		fmt.Fprintf(stderr, "No ranges lost quorum; nothing to do\n")
+	}
+	return writeRecoveryJSON(debugRecoverMakePlanOpts.outputFile, plan)
+}
+
+var debugRecoverApplyPlanCmd = &cobra.Command{
+	Use:   "apply-plan <plan-file> <store-dir> [<store-dir>...]",
+	Short: "apply a recovery plan to the stores of a node",
+	Long: `
+Applies the plan produced by 'cockroach debug recover make-plan' to the
+given stores, rewriting the descriptors of the replicas that the plan
+chose to recover their ranges.
+
+The stores must not be in use by a running node. This command will
+prompt for confirmation before committing its changes.
+
+After this command is used, the node should not be restarted until at
+least 10 seconds have passed since it was stopped. Restarting it too
+early may lead to things getting stuck (if it happens, it can be fixed
+by restarting a second time).
+`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: MaybeDecorateGRPCError(runDebugRecoverApplyPlan),
+}
+
+func runDebugRecoverApplyPlan(cmd *cobra.Command, args []string) error {
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.Background())
+
+	var plan loqrecovery.ReplicaUpdatePlan
+	if err := readRecoveryJSON(args[0], &plan); err != nil {
+		return err
+	}
+
+	clock := hlc.NewClock(hlc.UnixNano, 0)
+	var batches []engine.Batch
+	defer func() {
+		for _, batch := range batches {
+			batch.Close()
+		}
+	}()
+	for _, dir := range args[1:] {
+		db, err := OpenExistingStore(dir, stopper, false /* readOnly */)
+		if err != nil {
+			return errors.Wrapf(err, "opening store %s", dir)
+		}
+		batch, reports, err := loqrecovery.PrepareUpdateReplicas(
+			context.Background(), db, plan, clock.Now())
+		if err != nil {
+			return errors.Wrapf(err, "preparing updates for store %s", dir)
+		}
+		if batch == nil {
+			fmt.Printf("Nothing to do for store %s\n", dir)
+			continue
+		}
+		batches = append(batches, batch)
+		for _, report := range reports {
+			fmt.Printf("Replica %s -> %s\n", &report.OldDesc, &report.NewDesc)
+			if report.AbortedTxn != nil {
+				fmt.Printf("Conflicting intent found on descriptor of r%d. Aborted txn %s to resolve.\n",
+					report.NewDesc.RangeID, report.AbortedTxn.ID)
+			}
+		}
+	}
+	if len(batches) == 0 {
+		return nil
+	}
+
+	fmt.Printf("Proceed with the above rewrites? [y/N] ")
+
+	reader := bufio.NewReader(os.Stdin)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n")
+	if line[0] != 'y' && line[0] != 'Y' {
+		fmt.Printf("Aborting\n")
+		return nil
+	}
+	fmt.Printf("Committing\n")
+	for _, batch := range batches {
+		if err := batch.Commit(true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var debugRecoverVerifyCmd = &cobra.Command{
+	Use:   "verify <plan-file>",
+	Short: "verify the consistency of the ranges recovered by a plan",
+	Long: `
+Runs a consistency check on each range recovered by the given plan, once
+the cluster has been restarted after the plan was applied. Ranges whose
+check reports an inconsistency, or whose consistency could not be
+determined, are listed and cause the command to fail.
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: MaybeDecorateGRPCError(runDebugRecoverVerify),
+}
+
+var errRecoveredRangesUnverified = errors.New("not all recovered ranges could be verified")
+
+func runDebugRecoverVerify(cmd *cobra.Command, args []string) error {
+	var plan loqrecovery.ReplicaUpdatePlan
+	if err := readRecoveryJSON(args[0], &plan); err != nil {
+		return err
+	}
+	if len(plan.Updates) == 0 {
+		fmt.Printf("Plan recovered no ranges; nothing to verify\n")
+		return nil
+	}
+
+	conn, err := makeSQLClient("cockroach debug recover", useSystemDb)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	failed := false
+	for _, update := range plan.Updates {
+		status, detail, err := checkRecoveredRange(conn, update.RangeID)
+		if err != nil {
+			return err
+		}
+		switch status {
+		case roachpb.CheckConsistencyResponse_RANGE_CONSISTENT.String(),
+			roachpb.CheckConsistencyResponse_RANGE_CONSISTENT_STATS_ESTIMATED.String():
+			fmt.Printf("r%d: %s\n", update.RangeID, status)
+		default:
+			failed = true
+			fmt.Printf("r%d: %s %s\n", update.RangeID, status, detail)
+		}
+	}
+	if failed {
+		return errRecoveredRangesUnverified
+	}
+	return nil
+}
+
+// checkRecoveredRange runs a full consistency check on the given range and
+// returns its status and detail.
+func checkRecoveredRange(conn *sqlConn, rangeID roachpb.RangeID) (string, string, error) {
+	vals, err := conn.QueryRow(
+		`SELECT start_key, end_key FROM crdb_internal.ranges_no_leases WHERE range_id = $1`,
+		[]driver.Value{int64(rangeID)})
+	if err == io.EOF {
+		return "", "", errors.Errorf("r%d not found", rangeID)
+	} else if err != nil {
+		return "", "", err
+	}
+
+	rows, err := conn.Query(
+		`SELECT range_id, status, detail FROM crdb_internal.check_consistency(false, $1, $2)`,
+		[]driver.Value{vals[0], vals[1]})
+	if err != nil {
+		return "", "", err
+	}
+	defer func() { _ = rows.Close() }()
+	row := make([]driver.Value, 3)
+	for {
+		if err := rows.Next(row); err == io.EOF {
+			break
+		} else if err != nil {
+			return "", "", err
+		}
+		if id, ok := row[0].(int64); ok && roachpb.RangeID(id) == rangeID {
+			status, _ := row[1].(string)
+			detail, _ := row[2].(string)
+			return status, detail, nil
+		}
+	}
+	return roachpb.CheckConsistencyResponse_RANGE_INDETERMINATE.String(), "range was not checked", nil
+}
+
+func readRecoveryJSON(filename string, v interface{}) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return errors.Wrapf(err, "parsing %s", filename)
+	}
+	return nil
+}
+
+func writeRecoveryJSON(filename string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if filename == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	return ioutil.WriteFile(filename, data, 0600)
+}
+
+func init() {
+	debugRecoverCmd.AddCommand(
+		debugRecoverCollectInfoCmd,
+		debugRecoverMakePlanCmd,
+		debugRecoverApplyPlanCmd,
+		debugRecoverVerifyCmd,
+	)
+
+	f := debugRecoverCollectInfoCmd.Flags()
+	f.StringVar(&debugRecoverCollectInfoOpts.outputFile, "out", "",
+		"file to write the replica info to; defaults to standard output")
+
+	f = debugRecoverMakePlanCmd.Flags()
+	f.StringVar(&debugRecoverMakePlanOpts.outputFile, "out", "",
+		"file to write the plan to; defaults to standard output")
+	f.IntSliceVar(&debugRecoverMakePlanOpts.deadStoreIDs, "dead-store-ids", nil,
+		"list of dead store IDs; defaults to all stores from which no info was collected")
+	f.BoolVar(&debugRecoverMakePlanOpts.force, "force", false,
+		"make a plan even if the surviving replicas do not cover the keyspace")
+}
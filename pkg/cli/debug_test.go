@@ -36,8 +36,10 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
 
 func createStore(t *testing.T, path string) {
@@ -133,6 +135,80 @@ func TestOpenReadOnlyStore(t *testing.T) {
 	}
 }
 
+func TestOpenExistingRaftEngine(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	baseDir, dirCleanupFn := testutils.TempDir(t)
+	defer dirCleanupFn()
+
+	// writeIdent initializes the store at dir with the given ident, and marks
+	// it as keeping its Raft log in a separate engine if requested.
+	writeIdent := func(dir string, ident roachpb.StoreIdent, separate bool) {
+		t.Helper()
+		stopper := stop.NewStopper()
+		defer stopper.Stop(ctx)
+		createStore(t, dir)
+		db, err := OpenExistingStore(dir, stopper, false /* readOnly */)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := engine.MVCCPutProto(ctx, db, nil /* ms */, keys.StoreIdentKey(),
+			hlc.Timestamp{}, nil /* txn */, &ident); err != nil {
+			t.Fatal(err)
+		}
+		if separate {
+			if err := engine.MVCCPutProto(ctx, db, nil /* ms */, keys.StoreRaftEngineKey(),
+				hlc.Timestamp{}, nil /* txn */, &ident); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	ident := roachpb.StoreIdent{ClusterID: uuid.MakeV4(), NodeID: 1, StoreID: 1}
+	otherIdent := ident
+	otherIdent.StoreID = 2
+
+	sharedDir := filepath.Join(baseDir, "shared")
+	separateDir := filepath.Join(baseDir, "separate")
+	raftDir := filepath.Join(baseDir, "raft")
+	otherRaftDir := filepath.Join(baseDir, "other-raft")
+	writeIdent(sharedDir, ident, false /* separate */)
+	writeIdent(separateDir, ident, true /* separate */)
+	writeIdent(raftDir, ident, false /* separate */)
+	writeIdent(otherRaftDir, otherIdent, false /* separate */)
+
+	defer func(raftPath string) { debugCtx.raftPath = raftPath }(debugCtx.raftPath)
+	for _, test := range []struct {
+		dir, raftPath string
+		expSeparate   bool
+		expErr        string
+	}{
+		{dir: sharedDir},
+		{dir: sharedDir, raftPath: raftDir, expErr: "does not keep its Raft log in a separate engine"},
+		{dir: separateDir, expErr: "must be specified with --raft-path"},
+		{dir: separateDir, raftPath: raftDir, expSeparate: true},
+		{dir: separateDir, raftPath: otherRaftDir, expErr: "Raft engine belongs to store"},
+	} {
+		t.Run(fmt.Sprintf("dir=%s,raft-path=%s", test.dir, test.raftPath), func(t *testing.T) {
+			stopper := stop.NewStopper()
+			defer stopper.Stop(ctx)
+			db, err := OpenExistingStore(test.dir, stopper, true /* readOnly */)
+			if err != nil {
+				t.Fatal(err)
+			}
+			debugCtx.raftPath = test.raftPath
+			raftEng, err := openExistingRaftEngine(ctx, db, stopper)
+			if !testutils.IsError(err, test.expErr) {
+				t.Fatalf("wanted %s but got %v", test.expErr, err)
+			}
+			if err == nil && (raftEng != db) != test.expSeparate {
+				t.Fatalf("expected separate Raft engine: %t", test.expSeparate)
+			}
+		})
+	}
+}
+
 func TestRemoveDeadReplicas(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
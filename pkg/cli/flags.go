@@ -451,6 +451,7 @@ func init() {
 
 	clientCmds := []*cobra.Command{
 		debugGossipValuesCmd,
+		debugRecoverVerifyCmd,
 		debugTimeSeriesDumpCmd,
 		debugZipCmd,
 		dumpCmd,
@@ -634,6 +635,10 @@ func init() {
 		f := debugRangeDataCmd.Flags()
 		BoolFlag(f, &debugCtx.replicated, cliflags.Replicated, debugCtx.replicated)
 	}
+	for _, cmd := range []*cobra.Command{debugRangeDataCmd, debugRaftLogCmd, debugCheckStoreCmd} {
+		f := cmd.Flags()
+		StringFlag(f, &debugCtx.raftPath, cliflags.RaftPath, debugCtx.raftPath)
+	}
 	{
 		f := debugGossipValuesCmd.Flags()
 		StringFlag(f, &debugCtx.inputFile, cliflags.GossipInputFile, debugCtx.inputFile)
@@ -128,6 +128,7 @@ requesting data for debug/rangelog... writing: debug/rangelog.json
 requesting data for debug/liveness... writing: debug/liveness.json
 requesting data for debug/settings... writing: debug/settings.json
 requesting data for debug/reports/problemranges... writing: debug/reports/problemranges.json
+retrieving SQL data for crdb_internal.cluster_contention_events... writing: debug/crdb_internal.cluster_contention_events.txt
 retrieving SQL data for crdb_internal.cluster_queries... writing: debug/crdb_internal.cluster_queries.txt
 retrieving SQL data for crdb_internal.cluster_sessions... writing: debug/crdb_internal.cluster_sessions.txt
 retrieving SQL data for crdb_internal.cluster_settings... writing: debug/crdb_internal.cluster_settings.txt
@@ -217,6 +218,7 @@ requesting table details for system.replication_stats... writing: debug/schema/s
 requesting table details for system.reports_meta... writing: debug/schema/system/reports_meta.json
 requesting table details for system.role_members... writing: debug/schema/system/role_members.json
 requesting table details for system.settings... writing: debug/schema/system/settings.json
+requesting table details for system.statement_hints... writing: debug/schema/system/statement_hints.json
 requesting table details for system.table_statistics... writing: debug/schema/system/table_statistics.json
 requesting table details for system.ui... writing: debug/schema/system/ui.json
 requesting table details for system.users... writing: debug/schema/system/users.json
@@ -300,6 +302,7 @@ requesting table details for system.replication_stats... writing: debug/schema/s
 requesting table details for system.reports_meta... writing: debug/schema/system-1/reports_meta.json
 requesting table details for system.role_members... writing: debug/schema/system-1/role_members.json
 requesting table details for system.settings... writing: debug/schema/system-1/settings.json
+requesting table details for system.statement_hints... writing: debug/schema/system-1/statement_hints.json
 requesting table details for system.table_statistics... writing: debug/schema/system-1/table_statistics.json
 requesting table details for system.ui... writing: debug/schema/system-1/ui.json
 requesting table details for system.users... writing: debug/schema/system-1/users.json
@@ -405,6 +408,7 @@ requesting data for debug/rangelog... writing: debug/rangelog.json
 requesting data for debug/liveness... writing: debug/liveness.json
 requesting data for debug/settings... writing: debug/settings.json
 requesting data for debug/reports/problemranges... writing: debug/reports/problemranges.json
+retrieving SQL data for crdb_internal.cluster_contention_events... writing: debug/crdb_internal.cluster_contention_events.txt
 retrieving SQL data for crdb_internal.cluster_queries... writing: debug/crdb_internal.cluster_queries.txt
 retrieving SQL data for crdb_internal.cluster_sessions... writing: debug/crdb_internal.cluster_sessions.txt
 retrieving SQL data for crdb_internal.cluster_settings... writing: debug/crdb_internal.cluster_settings.txt
@@ -522,6 +526,7 @@ requesting data for debug/rangelog... writing: debug/rangelog.json
 requesting data for debug/liveness... writing: debug/liveness.json
 requesting data for debug/settings... writing: debug/settings.json
 requesting data for debug/reports/problemranges... writing: debug/reports/problemranges.json
+retrieving SQL data for crdb_internal.cluster_contention_events... writing: debug/crdb_internal.cluster_contention_events.txt
 retrieving SQL data for crdb_internal.cluster_queries... writing: debug/crdb_internal.cluster_queries.txt
 retrieving SQL data for crdb_internal.cluster_sessions... writing: debug/crdb_internal.cluster_sessions.txt
 retrieving SQL data for crdb_internal.cluster_settings... writing: debug/crdb_internal.cluster_settings.txt
@@ -709,6 +714,7 @@ requesting table details for system.replication_stats... writing: debug/schema/s
 requesting table details for system.reports_meta... writing: debug/schema/system/reports_meta.json
 requesting table details for system.role_members... writing: debug/schema/system/role_members.json
 requesting table details for system.settings... writing: debug/schema/system/settings.json
+requesting table details for system.statement_hints... writing: debug/schema/system/statement_hints.json
 requesting table details for system.table_statistics... writing: debug/schema/system/table_statistics.json
 requesting table details for system.ui... writing: debug/schema/system/ui.json
 requesting table details for system.users... writing: debug/schema/system/users.json
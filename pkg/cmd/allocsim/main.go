@@ -48,14 +48,21 @@ var configFile = flag.String("f", "", "config file that specifies an allocsim wo
 type Configuration struct {
 	NumWorkers int        `json:"NumWorkers"`
 	Localities []Locality `json:"Localities"`
+	// ClusterSettings are applied to the cluster before the workload starts,
+	// e.g. to choose which dimensions of load are balanced across stores.
+	ClusterSettings map[string]string `json:"ClusterSettings"`
 }
 
 // Locality defines the properties of a single locality as part of a Configuration.
 type Locality struct {
-	Name              string `json:"Name"`
-	LocalityStr       string `json:"LocalityStr"`
-	NumNodes          int    `json:"NumNodes"`
-	NumWorkers        int    `json:"NumWorkers"`
+	Name        string `json:"Name"`
+	LocalityStr string `json:"LocalityStr"`
+	NumNodes    int    `json:"NumNodes"`
+	NumWorkers  int    `json:"NumWorkers"`
+	// BlockSize, if set, overrides the block size written by the workers in the
+	// locality. Few workers writing large blocks generate write-heavy load with
+	// few requests.
+	BlockSize         int `json:"BlockSize"`
 	OutgoingLatencies []*struct {
 		Name    string       `json:"Name"`
 		Latency jsonDuration `json:"Latency"`
@@ -125,6 +132,9 @@ type allocStats struct {
 	leases         []int
 	replicaAdds    []int
 	leaseTransfers []int
+	qps            []float64
+	writeBytes     []float64
+	cpu            []float64
 }
 
 func newAllocSim(c *localcluster.Cluster) *allocSim {
@@ -152,10 +162,14 @@ func (a *allocSim) runWithConfig(config Configuration) {
 
 	firstNodeInLocality := 0
 	for _, locality := range config.Localities {
+		blockSize := *blockSize
+		if locality.BlockSize != 0 {
+			blockSize = locality.BlockSize
+		}
 		for i := 0; i < locality.NumWorkers; i++ {
 			node := firstNodeInLocality + (i % locality.NumNodes)
 			startNum := firstNodeInLocality + i
-			go a.worker(node, startNum, numWorkers)
+			go a.worker(node, startNum, numWorkers, blockSize)
 		}
 		firstNodeInLocality += locality.NumNodes
 	}
@@ -167,6 +181,15 @@ func (a *allocSim) runWithConfig(config Configuration) {
 	a.monitor(time.Second)
 }
 
+func (a *allocSim) applyClusterSettings(settings map[string]string) {
+	db := a.Nodes[0].DB()
+	for name, value := range settings {
+		if _, err := db.Exec(fmt.Sprintf("SET CLUSTER SETTING %s = %s", name, value)); err != nil {
+			log.Fatal(context.Background(), err)
+		}
+	}
+}
+
 func (a *allocSim) setup() {
 	db := a.Nodes[0].DB()
 	if _, err := db.Exec("CREATE DATABASE IF NOT EXISTS allocsim"); err != nil {
@@ -196,12 +219,12 @@ func (a *allocSim) maybeLogError(err error) {
 
 const insertStmt = `INSERT INTO allocsim.blocks (id, num, data) VALUES ($1, $2, repeat('a', $3)::bytes)`
 
-func (a *allocSim) worker(dbIdx, startNum, workers int) {
+func (a *allocSim) worker(dbIdx, startNum, workers, blockSize int) {
 	r, _ := randutil.NewPseudoRand()
 	db := a.Nodes[dbIdx%len(a.Nodes)].DB()
 	for num := startNum; true; num += workers {
 		now := timeutil.Now()
-		if _, err := db.Exec(insertStmt, r.Int63(), num, *blockSize); err != nil {
+		if _, err := db.Exec(insertStmt, r.Int63(), num, blockSize); err != nil {
 			a.maybeLogError(err)
 		} else {
 			atomic.AddUint64(&a.stats.ops, 1)
@@ -233,6 +256,9 @@ func (a *allocSim) rangeInfo() allocStats {
 		replicaAdds:    make([]int, len(a.Nodes)),
 		leases:         make([]int, len(a.Nodes)),
 		leaseTransfers: make([]int, len(a.Nodes)),
+		qps:            make([]float64, len(a.Nodes)),
+		writeBytes:     make([]float64, len(a.Nodes)),
+		cpu:            make([]float64, len(a.Nodes)),
 	}
 
 	// Retrieve the metrics for each node and extract the replica and leaseholder
@@ -272,6 +298,15 @@ func (a *allocSim) rangeInfo() allocStats {
 				if v, ok := storeMetrics["leases.transfers.success"]; ok {
 					stats.leaseTransfers[i] += int(v.(float64))
 				}
+				if v, ok := storeMetrics["rebalancing.queriespersecond"]; ok {
+					stats.qps[i] += v.(float64)
+				}
+				if v, ok := storeMetrics["rebalancing.writebytespersecond"]; ok {
+					stats.writeBytes[i] += v.(float64)
+				}
+				if v, ok := storeMetrics["rebalancing.cpunanospersecond"]; ok {
+					stats.cpu[i] += v.(float64)
+				}
 			}
 		}(i)
 	}
@@ -370,10 +405,10 @@ func (a *allocSim) finalStatus() {
 
 	fmt.Println(formatHeader("___stats___________________________", len(a.ranges.stats.replicas), a.localities))
 
-	genStats := func(name string, counts []int) {
+	genStats := func(name string, counts []float64) {
 		var total float64
 		for _, count := range counts {
-			total += float64(count)
+			total += count
 		}
 		mean := total / float64(len(counts))
 		var buf bytes.Buffer
@@ -381,15 +416,27 @@ func (a *allocSim) finalStatus() {
 		for _, count := range counts {
 			var percent, fromMean float64
 			if total != 0 {
-				percent = float64(count) / total * 100
-				fromMean = (float64(count) - mean) / total * 100
+				percent = count / total * 100
+				fromMean = (count - mean) / total * 100
 			}
 			fmt.Fprintf(&buf, " %9.9s", fmt.Sprintf("%.0f/%.0f", percent, fromMean))
 		}
 		fmt.Println(buf.String())
 	}
-	genStats("replicas", a.ranges.stats.replicas)
-	genStats("leases", a.ranges.stats.leases)
+	toFloats := func(counts []int) []float64 {
+		res := make([]float64, len(counts))
+		for i, count := range counts {
+			res[i] = float64(count)
+		}
+		return res
+	}
+	genStats("replicas", toFloats(a.ranges.stats.replicas))
+	genStats("leases", toFloats(a.ranges.stats.leases))
+	// The load-based rebalancing stats show how evenly the dimensions of load
+	// balanced by the store rebalancer are spread across the nodes.
+	genStats("qps", a.ranges.stats.qps)
+	genStats("wbytes", a.ranges.stats.writeBytes)
+	genStats("cpu", a.ranges.stats.cpu)
 }
 
 func handleStart() bool {
@@ -544,6 +591,7 @@ func main() {
 	if err != nil {
 		log.Fatal(context.Background(), err)
 	}
+	a.applyClusterSettings(config.ClusterSettings)
 	if len(config.Localities) != 0 {
 		a.runWithConfig(config)
 	} else {
@@ -532,10 +532,6 @@ var pgjdbcBlackList20_1 = blacklist{
 	"org.postgresql.test.jdbc2.BatchExecuteTest.testSelectInBatchThrowsAutoCommit[binary = FORCE, insertRewrite = true]":                                                                                                                                                     "41514",
 	"org.postgresql.test.jdbc2.BatchExecuteTest.testSelectInBatchThrowsAutoCommit[binary = REGULAR, insertRewrite = false]":                                                                                                                                                  "41514",
 	"org.postgresql.test.jdbc2.BatchExecuteTest.testSelectInBatchThrowsAutoCommit[binary = REGULAR, insertRewrite = true]":                                                                                                                                                   "41514",
-	"org.postgresql.test.jdbc2.BatchExecuteTest.testSelectInBatch[binary = FORCE, insertRewrite = false]":                                                                                                                                                                    "40195",
-	"org.postgresql.test.jdbc2.BatchExecuteTest.testSelectInBatch[binary = FORCE, insertRewrite = true]":                                                                                                                                                                     "40195",
-	"org.postgresql.test.jdbc2.BatchExecuteTest.testSelectInBatch[binary = REGULAR, insertRewrite = false]":                                                                                                                                                                  "40195",
-	"org.postgresql.test.jdbc2.BatchExecuteTest.testSelectInBatch[binary = REGULAR, insertRewrite = true]":                                                                                                                                                                   "40195",
 	"org.postgresql.test.jdbc2.BatchExecuteTest.testSmallBatchUpdateFailureSimple[binary = FORCE, insertRewrite = false]":                                                                                                                                                    "41514",
 	"org.postgresql.test.jdbc2.BatchExecuteTest.testSmallBatchUpdateFailureSimple[binary = REGULAR, insertRewrite = false]":                                                                                                                                                  "41514",
 	"org.postgresql.test.jdbc2.BatchFailureTest.run[105: batchTest(mode=FAIL_VIA_DUP_KEY, position=SECOND_ROW, autoCommit=YES, batchType=PREPARED, generateKeys=YES, binary=REGULAR, insertRewrite=false)]":                                                                  "41514",
@@ -676,8 +672,6 @@ var pgjdbcBlackList20_1 = blacklist{
 	"org.postgresql.test.jdbc2.CopyTest.testCopyOutByRow":                                                                                                                      "41608",
 	"org.postgresql.test.jdbc2.CopyTest.testCopyQuery":                                                                                                                         "41608",
 	"org.postgresql.test.jdbc2.CopyTest.testLockReleaseOnCancelFailure":                                                                                                        "41608",
-	"org.postgresql.test.jdbc2.CursorFetchTest.testMultistatement[binary = FORCE]":                                                                                             "40195",
-	"org.postgresql.test.jdbc2.CursorFetchTest.testMultistatement[binary = REGULAR]":                                                                                           "40195",
 	"org.postgresql.test.jdbc2.DatabaseEncodingTest.testEncoding":                                                                                                              "41771",
 	"org.postgresql.test.jdbc2.DatabaseMetaDataTest.testArrayInt4DoubleDim":                                                                                                    "17511",
 	"org.postgresql.test.jdbc2.DatabaseMetaDataTest.testArrayTypeInfo":                                                                                                         "17511",
@@ -168,6 +168,7 @@ func NewZoneConfig() *ZoneConfig {
 	return &ZoneConfig{
 		InheritedConstraints:      true,
 		InheritedLeasePreferences: true,
+		InheritedVoterConstraints: true,
 	}
 }
 
@@ -181,6 +182,7 @@ func EmptyCompleteZoneConfig() *ZoneConfig {
 		GC:                        &GCPolicy{TTLSeconds: 0},
 		InheritedConstraints:      true,
 		InheritedLeasePreferences: true,
+		InheritedVoterConstraints: true,
 	}
 }
 
@@ -244,7 +246,39 @@ func DefaultSystemZoneConfigRef() *ZoneConfig {
 func (z *ZoneConfig) IsComplete() bool {
 	return ((z.NumReplicas != nil) && (z.RangeMinBytes != nil) &&
 		(z.RangeMaxBytes != nil) && (z.GC != nil) &&
-		(!z.InheritedConstraints) && (!z.InheritedLeasePreferences))
+		(!z.InheritedConstraints) && (!z.InheritedLeasePreferences) &&
+		(!z.InheritedVoterConstraints))
+}
+
+// GetNumVoters returns the number of voting replicas configured for the zone.
+// If num_voters is unset, all replicas are voters.
+func (z *ZoneConfig) GetNumVoters() int32 {
+	if z.NumVoters != nil && *z.NumVoters > 0 {
+		return *z.NumVoters
+	}
+	if z.NumReplicas == nil {
+		return 0
+	}
+	return *z.NumReplicas
+}
+
+// GetNumNonVoters returns the number of non-voting replicas configured for the
+// zone.
+func (z *ZoneConfig) GetNumNonVoters() int32 {
+	if z.NumReplicas == nil {
+		return 0
+	}
+	return *z.NumReplicas - z.GetNumVoters()
+}
+
+// GetVoterConstraints returns the constraints that apply to the voting
+// replicas of the zone, falling back to the constraints of the zone when no
+// voter constraints are configured.
+func (z *ZoneConfig) GetVoterConstraints() []Constraints {
+	if len(z.VoterConstraints) > 0 {
+		return z.VoterConstraints
+	}
+	return z.Constraints
 }
 
 // ValidateTandemFields returns an error if the ZoneConfig to be written
@@ -259,6 +293,12 @@ func (z *ZoneConfig) ValidateTandemFields() error {
 	if numConstrainedRepls > 0 && z.NumReplicas == nil {
 		return fmt.Errorf("when per-replica constraints are set, num_replicas must be set as well")
 	}
+	if z.NumVoters != nil && z.NumReplicas == nil {
+		return fmt.Errorf("when num_voters is set, num_replicas must be set as well")
+	}
+	if len(z.VoterConstraints) > 0 && z.NumVoters == nil {
+		return fmt.Errorf("when voter_constraints are set, num_voters must be set as well")
+	}
 	if (z.RangeMinBytes != nil || z.RangeMaxBytes != nil) &&
 		(z.RangeMinBytes == nil || z.RangeMaxBytes == nil) {
 		return fmt.Errorf("range_min_bytes and range_max_bytes must be set together")
@@ -313,11 +353,49 @@ func (z *ZoneConfig) Validate() error {
 		return fmt.Errorf("GC.TTLSeconds %d less than minimum allowed 1", z.GC.TTLSeconds)
 	}
 
-	for _, constraints := range z.Constraints {
-		for _, constraint := range constraints.Constraints {
+	if z.NumVoters != nil {
+		switch {
+		case *z.NumVoters <= 0:
+			return fmt.Errorf("at least one voting replica is required")
+		case *z.NumVoters == 2:
+			return fmt.Errorf("at least 3 voting replicas are required for multi-replica configurations")
+		case z.NumReplicas != nil && *z.NumVoters > *z.NumReplicas:
+			return fmt.Errorf("num_voters (%d) cannot be greater than num_replicas (%d)",
+				*z.NumVoters, *z.NumReplicas)
+		}
+	}
+
+	if err := validateConstraints("constraints", z.Constraints, z.NumReplicas); err != nil {
+		return err
+	}
+	if err := validateConstraints("voter_constraints", z.VoterConstraints, z.NumVoters); err != nil {
+		return err
+	}
+
+	for _, leasePref := range z.LeasePreferences {
+		if len(leasePref.Constraints) == 0 {
+			return fmt.Errorf("every lease preference must include at least one constraint")
+		}
+		for _, constraint := range leasePref.Constraints {
+			if constraint.Type == Constraint_DEPRECATED_POSITIVE {
+				return fmt.Errorf("lease preference constraints must either be required " +
+					"(prefixed with a '+') or prohibited (prefixed with a '-')")
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateConstraints validates the given set of constraints against the
+// number of replicas they apply to. The name of the field is used in error
+// messages.
+func validateConstraints(field string, constraints []Constraints, numReplicas *int32) error {
+	for _, cs := range constraints {
+		for _, constraint := range cs.Constraints {
 			if constraint.Type == Constraint_DEPRECATED_POSITIVE {
-				return fmt.Errorf("constraints must either be required (prefixed with a '+') or " +
-					"prohibited (prefixed with a '-')")
+				return fmt.Errorf("%s must either be required (prefixed with a '+') or "+
+					"prohibited (prefixed with a '-')", field)
 			}
 		}
 	}
@@ -325,41 +403,28 @@ func (z *ZoneConfig) Validate() error {
 	// We only need to further validate constraints if per-replica constraints
 	// are in use. The old style of constraints that apply to all replicas don't
 	// require validation.
-	if len(z.Constraints) > 1 || (len(z.Constraints) == 1 && z.Constraints[0].NumReplicas != 0) {
+	if len(constraints) > 1 || (len(constraints) == 1 && constraints[0].NumReplicas != 0) {
 		var numConstrainedRepls int64
-		for _, constraints := range z.Constraints {
-			if constraints.NumReplicas <= 0 {
-				return fmt.Errorf("constraints must apply to at least one replica")
+		for _, cs := range constraints {
+			if cs.NumReplicas <= 0 {
+				return fmt.Errorf("%s must apply to at least one replica", field)
 			}
-			numConstrainedRepls += int64(constraints.NumReplicas)
-			for _, constraint := range constraints.Constraints {
+			numConstrainedRepls += int64(cs.NumReplicas)
+			for _, constraint := range cs.Constraints {
 				// TODO(a-robinson): Relax this constraint to allow prohibited replicas,
 				// as discussed on #23014.
-				if constraint.Type != Constraint_REQUIRED && z.NumReplicas != nil && constraints.NumReplicas != *z.NumReplicas {
+				if constraint.Type != Constraint_REQUIRED && numReplicas != nil && cs.NumReplicas != *numReplicas {
 					return fmt.Errorf(
 						"only required constraints (prefixed with a '+') can be applied to a subset of replicas")
 				}
 			}
 		}
-		if z.NumReplicas != nil && numConstrainedRepls > int64(*z.NumReplicas) {
-			return fmt.Errorf("the number of replicas specified in constraints (%d) cannot be greater "+
+		if numReplicas != nil && numConstrainedRepls > int64(*numReplicas) {
+			return fmt.Errorf("the number of replicas specified in %s (%d) cannot be greater "+
 				"than the number of replicas configured for the zone (%d)",
-				numConstrainedRepls, *z.NumReplicas)
+				field, numConstrainedRepls, *numReplicas)
 		}
 	}
-
-	for _, leasePref := range z.LeasePreferences {
-		if len(leasePref.Constraints) == 0 {
-			return fmt.Errorf("every lease preference must include at least one constraint")
-		}
-		for _, constraint := range leasePref.Constraints {
-			if constraint.Type == Constraint_DEPRECATED_POSITIVE {
-				return fmt.Errorf("lease preference constraints must either be required " +
-					"(prefixed with a '+') or prohibited (prefixed with a '-')")
-			}
-		}
-	}
-
 	return nil
 }
 
@@ -399,6 +464,17 @@ func (z *ZoneConfig) InheritFromParent(parent *ZoneConfig) {
 			z.InheritedLeasePreferences = false
 		}
 	}
+	if z.NumVoters == nil {
+		if parent.NumVoters != nil {
+			z.NumVoters = proto.Int32(*parent.NumVoters)
+		}
+	}
+	if z.InheritedVoterConstraints {
+		if !parent.InheritedVoterConstraints {
+			z.VoterConstraints = parent.VoterConstraints
+			z.InheritedVoterConstraints = false
+		}
+	}
 }
 
 // CopyFromZone copies over the specified fields from the other zone.
@@ -437,6 +513,16 @@ func (z *ZoneConfig) CopyFromZone(other ZoneConfig, fieldList []tree.Name) {
 			z.LeasePreferences = other.LeasePreferences
 			z.InheritedLeasePreferences = other.InheritedLeasePreferences
 		}
+		if fieldName == "num_voters" {
+			z.NumVoters = nil
+			if other.NumVoters != nil {
+				z.NumVoters = proto.Int32(*other.NumVoters)
+			}
+		}
+		if fieldName == "voter_constraints" {
+			z.VoterConstraints = other.VoterConstraints
+			z.InheritedVoterConstraints = other.InheritedVoterConstraints
+		}
 	}
 }
 
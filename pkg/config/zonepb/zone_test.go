@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"math/rand"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -216,6 +217,70 @@ func TestZoneConfigValidate(t *testing.T) {
 			},
 			"lease preference constraints must either be required .+ or prohibited .+",
 		},
+		{
+			ZoneConfig{
+				NumReplicas:   proto.Int32(5),
+				NumVoters:     proto.Int32(0),
+				RangeMaxBytes: DefaultZoneConfig().RangeMaxBytes,
+				GC:            &GCPolicy{TTLSeconds: 1},
+			},
+			"at least one voting replica is required",
+		},
+		{
+			ZoneConfig{
+				NumReplicas:   proto.Int32(5),
+				NumVoters:     proto.Int32(2),
+				RangeMaxBytes: DefaultZoneConfig().RangeMaxBytes,
+				GC:            &GCPolicy{TTLSeconds: 1},
+			},
+			"at least 3 voting replicas are required for multi-replica configurations",
+		},
+		{
+			ZoneConfig{
+				NumReplicas:   proto.Int32(3),
+				NumVoters:     proto.Int32(5),
+				RangeMaxBytes: DefaultZoneConfig().RangeMaxBytes,
+				GC:            &GCPolicy{TTLSeconds: 1},
+			},
+			`num_voters \(5\) cannot be greater than num_replicas \(3\)`,
+		},
+		{
+			ZoneConfig{
+				NumReplicas:   proto.Int32(5),
+				NumVoters:     proto.Int32(3),
+				RangeMaxBytes: DefaultZoneConfig().RangeMaxBytes,
+				GC:            &GCPolicy{TTLSeconds: 1},
+				VoterConstraints: []Constraints{
+					{
+						Constraints: []Constraint{{Value: "a", Type: Constraint_REQUIRED}},
+						NumReplicas: 4,
+					},
+				},
+			},
+			`the number of replicas specified in voter_constraints \(4\) cannot be greater than ` +
+				`the number of replicas configured for the zone \(3\)`,
+		},
+		{
+			ZoneConfig{
+				NumReplicas:   proto.Int32(5),
+				NumVoters:     proto.Int32(3),
+				RangeMaxBytes: DefaultZoneConfig().RangeMaxBytes,
+				GC:            &GCPolicy{TTLSeconds: 1},
+				Constraints: []Constraints{
+					{
+						Constraints: []Constraint{{Value: "b", Type: Constraint_REQUIRED}},
+						NumReplicas: 2,
+					},
+				},
+				VoterConstraints: []Constraints{
+					{
+						Constraints: []Constraint{{Value: "a", Type: Constraint_REQUIRED}},
+						NumReplicas: 3,
+					},
+				},
+			},
+			"",
+		},
 		{
 			ZoneConfig{
 				NumReplicas:   proto.Int32(1),
@@ -272,6 +337,23 @@ func TestZoneConfigValidateTandemFields(t *testing.T) {
 			},
 			"when per-replica constraints are set, num_replicas must be set as well",
 		},
+		{
+			ZoneConfig{
+				NumVoters: proto.Int32(3),
+			},
+			"when num_voters is set, num_replicas must be set as well",
+		},
+		{
+			ZoneConfig{
+				NumReplicas: proto.Int32(5),
+				VoterConstraints: []Constraints{
+					{
+						Constraints: []Constraint{{Value: "a", Type: Constraint_REQUIRED}},
+					},
+				},
+			},
+			"when voter_constraints are set, num_voters must be set as well",
+		},
 		{
 			ZoneConfig{
 				InheritedConstraints:      true,
@@ -771,6 +853,69 @@ func TestConstraintsListYAML(t *testing.T) {
 	}
 }
 
+// TestZoneConfigVoterFieldsYAML makes sure that num_voters and
+// voter_constraints are only marshaled when set, and survive a round-trip.
+func TestZoneConfigVoterFieldsYAML(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	original := ZoneConfig{
+		RangeMinBytes: proto.Int64(1),
+		RangeMaxBytes: proto.Int64(1),
+		GC: &GCPolicy{
+			TTLSeconds: 1,
+		},
+		NumReplicas: proto.Int32(5),
+		NumVoters:   proto.Int32(3),
+		VoterConstraints: []Constraints{
+			{
+				Constraints: []Constraint{
+					{
+						Type:  Constraint_REQUIRED,
+						Key:   "region",
+						Value: "us",
+					},
+				},
+			},
+		},
+	}
+	const expected = `range_min_bytes: 1
+range_max_bytes: 1
+gc:
+  ttlseconds: 1
+num_replicas: 5
+constraints: []
+lease_preferences: []
+num_voters: 3
+voter_constraints: [+region=us]
+`
+
+	body, err := yaml.Marshal(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != expected {
+		t.Fatalf("yaml.Marshal(%+v)\ngot:\n%s\nwant:\n%s", original, body, expected)
+	}
+
+	var unmarshaled ZoneConfig
+	if err := yaml.UnmarshalStrict(body, &unmarshaled); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(&unmarshaled, &original) {
+		t.Errorf("yaml.UnmarshalStrict(%q)\ngot:\n%+v\nwant:\n%+v", body, unmarshaled, original)
+	}
+
+	// Inherited voter constraints are not part of the output.
+	original.InheritedVoterConstraints = true
+	body, err = yaml.Marshal(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(body), "voter_constraints") {
+		t.Errorf("expected inherited voter constraints to be omitted, got:\n%s", body)
+	}
+}
+
 func TestMarshalableZoneConfigRoundTrip(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
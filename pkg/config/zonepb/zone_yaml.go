@@ -208,6 +208,8 @@ type marshalableZoneConfig struct {
 	ExperimentalLeasePreferences []LeasePreference `json:"experimental_lease_preferences" yaml:"experimental_lease_preferences,flow,omitempty"`
 	Subzones                     []Subzone         `json:"subzones" yaml:"-"`
 	SubzoneSpans                 []SubzoneSpan     `json:"subzone_spans" yaml:"-"`
+	NumVoters                    *int32            `json:"num_voters" yaml:"num_voters,omitempty"`
+	VoterConstraints             ConstraintsList   `json:"voter_constraints" yaml:"voter_constraints,flow,omitempty"`
 }
 
 func zoneConfigToMarshalable(c ZoneConfig) marshalableZoneConfig {
@@ -233,6 +235,14 @@ func zoneConfigToMarshalable(c ZoneConfig) marshalableZoneConfig {
 	// want to return yaml containing it.
 	m.Subzones = c.Subzones
 	m.SubzoneSpans = c.SubzoneSpans
+	if c.NumVoters != nil && *c.NumVoters != 0 {
+		m.NumVoters = proto.Int32(*c.NumVoters)
+	}
+	// Voter constraints are only emitted when they are explicitly set so that
+	// the output for zones without non-voting replicas is unchanged.
+	if !c.InheritedVoterConstraints && len(c.VoterConstraints) > 0 {
+		m.VoterConstraints = ConstraintsList{c.VoterConstraints, false}
+	}
 	return m
 }
 
@@ -273,6 +283,13 @@ func zoneConfigFromMarshalable(m marshalableZoneConfig, c ZoneConfig) ZoneConfig
 	}
 	c.Subzones = m.Subzones
 	c.SubzoneSpans = m.SubzoneSpans
+	if m.NumVoters != nil {
+		c.NumVoters = proto.Int32(*m.NumVoters)
+	}
+	if m.VoterConstraints.Constraints != nil {
+		c.VoterConstraints = m.VoterConstraints.Constraints
+		c.InheritedVoterConstraints = m.VoterConstraints.Inherited
+	}
 	return c
 }
 
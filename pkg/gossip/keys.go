@@ -87,6 +87,11 @@ const (
 	// progress.
 	KeyTableDisableMergesPrefix = "table-disable-merges"
 
+	// KeySQLNotificationPrefix is the prefix for keys that carry the most recent
+	// SQL notifications (NOTIFY / pg_notify) published by each node. Each node
+	// gossips a bounded window of its recent notifications under its own key.
+	KeySQLNotificationPrefix = "sql-notification"
+
 	// KeyGossipClientsPrefix is the prefix for keys that indicate which gossip
 	// client connections a node has open. This is used by other nodes in the
 	// cluster to build a map of the gossip network.
@@ -208,6 +213,12 @@ func MakeTableDisableMergesKey(tableID uint32) string {
 	return MakeKey(KeyTableDisableMergesPrefix, strconv.FormatUint(uint64(tableID), 10 /* base */))
 }
 
+// MakeSQLNotificationKey returns the gossip key under which the given node
+// publishes its recent SQL notifications.
+func MakeSQLNotificationKey(nodeID roachpb.NodeID) string {
+	return MakeKey(KeySQLNotificationPrefix, nodeID.String())
+}
+
 // removePrefixFromKey removes the key prefix and separator and returns what's
 // left. Returns an error if the key doesn't have this prefix.
 func removePrefixFromKey(key, prefix string) (string, error) {
@@ -633,6 +633,40 @@ func (db *DB) AdminRelocateRange(
 	return getOneErr(db.Run(ctx, b), b)
 }
 
+// QueryResolvedTimestamp returns the resolved timestamp of the provided key
+// spans, i.e. the newest timestamp at or below which reads over all of the
+// spans will neither block on intents nor observe future writes. If nearest
+// is set, the resolved timestamp of each range is computed by the replica
+// nearest to this node instead of by the range's leaseholder, in which case
+// it is bounded by that replica's closed timestamp.
+func (db *DB) QueryResolvedTimestamp(
+	ctx context.Context, spans []roachpb.Span, nearest bool,
+) (hlc.Timestamp, error) {
+	if len(spans) == 0 {
+		return hlc.Timestamp{}, errors.Errorf("no spans provided to QueryResolvedTimestamp")
+	}
+	b := &Batch{}
+	for _, sp := range spans {
+		b.AddRawRequest(&roachpb.QueryResolvedTimestampRequest{
+			RequestHeader: roachpb.RequestHeaderFromSpan(sp),
+		})
+	}
+	if nearest {
+		b.Header.RoutingPolicy = roachpb.RoutingPolicy_NEAREST
+	}
+	if err := db.Run(ctx, b); err != nil {
+		return hlc.Timestamp{}, err
+	}
+	var resolvedTS hlc.Timestamp
+	for i, ru := range b.RawResponse().Responses {
+		resp := ru.GetInner().(*roachpb.QueryResolvedTimestampResponse)
+		if i == 0 || resp.ResolvedTS.Less(resolvedTS) {
+			resolvedTS = resp.ResolvedTS
+		}
+	}
+	return resolvedTS, nil
+}
+
 // WriteBatch applies the operations encoded in a BatchRepr, which is the
 // serialized form of a RocksDB Batch. The command cannot span Ranges and must
 // be run on an empty keyrange.
@@ -72,9 +72,23 @@ type Txn struct {
 		// The txn has to be committed by this deadline. A nil value indicates no
 		// deadline.
 		deadline *hlc.Timestamp
+
+		// routingPolicy is the routing policy used for batches sent through the
+		// txn that don't specify one. See SetRoutingPolicy.
+		routingPolicy roachpb.RoutingPolicy
+
+		// contentionEvents accumulates the contention events encountered by the
+		// batches sent through the txn until they are retrieved through
+		// TakeContentionEvents. At most maxContentionEvents are retained.
+		contentionEvents []roachpb.ContentionEvent
 	}
 }
 
+// maxContentionEvents is the maximum number of contention events buffered by a
+// Txn between two calls to TakeContentionEvents. Events beyond this limit are
+// dropped.
+const maxContentionEvents = 128
+
 // NewTxn returns a new RootTxn.
 // Note: for SQL usage, prefer NewTxnWithSteppingEnabled() below.
 //
@@ -866,9 +880,15 @@ func (txn *Txn) Send(
 	txn.mu.Lock()
 	requestTxnID := txn.mu.ID
 	sender := txn.mu.sender
+	if ba.RoutingPolicy == roachpb.RoutingPolicy_LEASEHOLDER {
+		ba.RoutingPolicy = txn.mu.routingPolicy
+	}
 	txn.mu.Unlock()
 	br, pErr := txn.db.sendUsingSender(ctx, ba, sender)
 	if pErr == nil {
+		if len(br.ContentionEvents) > 0 {
+			txn.recordContentionEvents(br.ContentionEvents)
+		}
 		return br, nil
 	}
 
@@ -1077,6 +1097,43 @@ func (txn *Txn) SetFixedTimestamp(ctx context.Context, ts hlc.Timestamp) {
 	txn.mu.sender.SetFixedTimestamp(ctx, ts)
 }
 
+// SetRoutingPolicy sets the policy used to route the transaction's batches to
+// the replicas of each range. Routing to the nearest replica is only valid for
+// read-only transactions run at a fixed timestamp at or below the closed
+// timestamp of every range they read from, which is how bounded staleness
+// reads are served.
+func (txn *Txn) SetRoutingPolicy(policy roachpb.RoutingPolicy) {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	txn.mu.routingPolicy = policy
+}
+
+// recordContentionEvents buffers the contention events returned in a batch
+// response until they are retrieved through TakeContentionEvents.
+func (txn *Txn) recordContentionEvents(events []roachpb.ContentionEvent) {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	if n := maxContentionEvents - len(txn.mu.contentionEvents); n < len(events) {
+		events = events[:n]
+	}
+	txn.mu.contentionEvents = append(txn.mu.contentionEvents, events...)
+}
+
+// TakeContentionEvents returns the contention events encountered by the
+// batches sent through the txn since the last call, i.e. the intents of other
+// transactions that these batches had to wait on, and clears them.
+//
+// Only the events of batches sent through this Txn object are returned; the
+// events encountered by leaf txns on remote nodes are not propagated back to
+// the root txn.
+func (txn *Txn) TakeContentionEvents() []roachpb.ContentionEvent {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	events := txn.mu.contentionEvents
+	txn.mu.contentionEvents = nil
+	return events
+}
+
 // GenerateForcedRetryableError returns a TransactionRetryWithProtoRefreshError that will
 // cause the txn to be retried.
 //
@@ -80,6 +80,9 @@ var (
 	localRangeFrozenStatusSuffix = []byte("fzn-")
 	// LocalRangeLastGCSuffix is the suffix for the last GC.
 	LocalRangeLastGCSuffix = []byte("lgc-")
+	// LocalRangeMVCCRangeTombstonesSuffix is the suffix for the key marking
+	// that a range may contain MVCC range tombstones.
+	LocalRangeMVCCRangeTombstonesSuffix = []byte("lrtm")
 	// LocalRangeAppliedStateSuffix is the suffix for the range applied state
 	// key.
 	LocalRangeAppliedStateSuffix = []byte("rask")
@@ -148,6 +151,11 @@ var (
 	// LocalRangeDescriptorSuffix is the suffix for keys storing
 	// range descriptors. The value is a struct of type RangeDescriptor.
 	LocalRangeDescriptorSuffix = roachpb.RKey("rdsc")
+	// LocalMVCCRangeTombstoneSuffix is the suffix for keys storing MVCC
+	// range tombstones. The key is versioned at the timestamp of the tombstone
+	// and its value holds the (exclusive) end key of the deleted span,
+	// which starts at the key the range-local key is made from.
+	LocalMVCCRangeTombstoneSuffix = roachpb.RKey("rtmb")
 	// LocalTransactionSuffix specifies the key suffix for
 	// transaction records. The additional detail is the transaction id.
 	// NOTE: if this value changes, it must be updated in C++
@@ -174,6 +182,10 @@ var (
 	// localStoreIdentSuffix stores an immutable identifier for this
 	// store, created when the store is first bootstrapped.
 	localStoreIdentSuffix = []byte("iden")
+	// localStoreRaftEngineSuffix marks a store whose Raft logs and HardStates
+	// have been moved into a separate engine. It is written to the store's
+	// main engine once that move has completed.
+	localStoreRaftEngineSuffix = []byte("rfte")
 	// localStoreLastUpSuffix stores the last timestamp that a store's node
 	// acknowledged that it was still running. This value will be regularly
 	// refreshed on all stores for a running node; the intention of this value
@@ -361,6 +373,8 @@ const (
 	ProtectedTimestampsMetaTableID    = 31
 	ProtectedTimestampsRecordsTableID = 32
 
+	StatementHintsTableID = 33
+
 	// CommentType is type for system.comments
 	DatabaseCommentType = 0
 	TableCommentType    = 1
@@ -193,6 +193,7 @@ var _ = [...]interface{}{
 	QueueLastProcessedKey,   // "qlpt"
 	RangeDescriptorJointKey, // "rdjt"
 	RangeDescriptorKey,      // "rdsc"
+	MVCCRangeTombstoneKey,   // "rtmb"
 	TransactionKey,          // "txn-"
 
 	//   4. Store local keys: These contain metadata about an individual store.
@@ -203,6 +204,7 @@ var _ = [...]interface{}{
 	StoreGossipKey,              // "goss"
 	StoreHLCUpperBoundKey,       // "hlcu"
 	StoreIdentKey,               // "iden"
+	StoreRaftEngineKey,          // "rfte"
 	StoreLastUpKey,              // "uptm"
 
 	// The global keyspace includes the meta{1,2}, system, and SQL keys.
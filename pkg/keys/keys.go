@@ -70,6 +70,12 @@ func StoreLastUpKey() roachpb.Key {
 	return MakeStoreKey(localStoreLastUpSuffix, nil)
 }
 
+// StoreRaftEngineKey returns the store-local key marking that the store keeps
+// its Raft logs and HardStates in a separate engine.
+func StoreRaftEngineKey() roachpb.Key {
+	return MakeStoreKey(localStoreRaftEngineSuffix, nil)
+}
+
 // StoreHLCUpperBoundKey returns the store-local key for storing an upper bound
 // to the wall time used by HLC.
 func StoreHLCUpperBoundKey() roachpb.Key {
@@ -265,6 +271,13 @@ func RangeLastGCKey(rangeID roachpb.RangeID) roachpb.Key {
 	return MakeRangeIDPrefixBuf(rangeID).RangeLastGCKey()
 }
 
+// RangeMVCCRangeTombstonesKey returns a system-local key which is present if
+// the range may contain MVCC range tombstones. Reads skip looking up range
+// tombstones on ranges that don't have this key.
+func RangeMVCCRangeTombstonesKey(rangeID roachpb.RangeID) roachpb.Key {
+	return MakeRangeIDPrefixBuf(rangeID).RangeMVCCRangeTombstonesKey()
+}
+
 // MakeRangeIDUnreplicatedPrefix creates a range-local key prefix from
 // rangeID for all unreplicated data.
 func MakeRangeIDUnreplicatedPrefix(rangeID roachpb.RangeID) roachpb.Key {
@@ -379,6 +392,12 @@ func RangeDescriptorJointKey(key roachpb.RKey) roachpb.Key {
 
 var _ = RangeDescriptorJointKey // silence unused check
 
+// MVCCRangeTombstoneKey returns a range-local key for the MVCC range
+// tombstones starting at the specified key.
+func MVCCRangeTombstoneKey(key roachpb.RKey) roachpb.Key {
+	return MakeRangeKey(key, LocalMVCCRangeTombstoneSuffix, nil)
+}
+
 // TransactionKey returns a transaction key based on the provided
 // transaction key and ID. The base key is encoded in order to
 // guarantee that all transaction records for a range sort together.
@@ -897,6 +916,12 @@ func (b RangeIDPrefixBuf) RangeLastGCKey() roachpb.Key {
 	return append(b.replicatedPrefix(), LocalRangeLastGCSuffix...)
 }
 
+// RangeMVCCRangeTombstonesKey returns a system-local key marking that the
+// range may contain MVCC range tombstones.
+func (b RangeIDPrefixBuf) RangeMVCCRangeTombstonesKey() roachpb.Key {
+	return append(b.replicatedPrefix(), LocalRangeMVCCRangeTombstonesSuffix...)
+}
+
 // RangeTombstoneKey returns a system-local key for a range tombstone.
 func (b RangeIDPrefixBuf) RangeTombstoneKey() roachpb.Key {
 	return append(b.unreplicatedPrefix(), LocalRangeTombstoneSuffix...)
@@ -165,6 +165,7 @@ var (
 		{name: "RangeLease", suffix: LocalRangeLeaseSuffix},
 		{name: "RangeStats", suffix: LocalRangeStatsLegacySuffix},
 		{name: "RangeLastGC", suffix: LocalRangeLastGCSuffix},
+		{name: "RangeMVCCRangeTombstones", suffix: LocalRangeMVCCRangeTombstonesSuffix},
 	}
 
 	rangeSuffixDict = []struct {
@@ -173,6 +174,7 @@ var (
 		atEnd  bool
 	}{
 		{name: "RangeDescriptor", suffix: LocalRangeDescriptorSuffix, atEnd: true},
+		{name: "MVCCRangeTombstone", suffix: LocalMVCCRangeTombstoneSuffix, atEnd: true},
 		{name: "Transaction", suffix: LocalTransactionSuffix, atEnd: false},
 		{name: "QueueLastProcessed", suffix: LocalQueueLastProcessedSuffix, atEnd: false},
 	}
@@ -186,6 +188,7 @@ var constSubKeyDict = []struct {
 	{"/gossipBootstrap", localStoreGossipSuffix},
 	{"/clusterVersion", localStoreClusterVersionSuffix},
 	{"/suggestedCompaction", localStoreSuggestedCompactionSuffix},
+	{"/raftEngine", localStoreRaftEngineSuffix},
 }
 
 func suggestedCompactionKeyPrint(key roachpb.Key) string {
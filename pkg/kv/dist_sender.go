@@ -495,16 +495,26 @@ func (ds *DistSender) getDescriptor(
 func (ds *DistSender) sendSingleRange(
 	ctx context.Context, ba roachpb.BatchRequest, desc *roachpb.RangeDescriptor, withCommit bool,
 ) (*roachpb.BatchResponse, *roachpb.Error) {
+	// Requests using the NEAREST routing policy are sent to the closest replica
+	// first, regardless of whether it holds the lease. If it cannot serve the
+	// request, it will redirect us to the leaseholder.
+	canSendToFollower := ba.RoutingPolicy == roachpb.RoutingPolicy_NEAREST ||
+		(ds.clusterID != nil && CanSendToFollower(ds.clusterID.Get(), ds.st, ba))
+
 	// Try to send the call. Learner replicas won't serve reads/writes, so send
 	// only to the `Voters` replicas. This is just an optimization to save a
-	// network hop, everything would still work if we had `All` here.
-	replicas := NewReplicaSlice(ds.gossip, desc.Replicas().Voters())
+	// network hop, everything would still work if we had `All` here. Non-voting
+	// replicas can serve follower reads, so they are included if the request
+	// may be sent to a follower.
+	replicaDescs := desc.Replicas().Voters()
+	if canSendToFollower {
+		replicaDescs = desc.Replicas().VotersAndNonVoters()
+	}
+	replicas := NewReplicaSlice(ds.gossip, replicaDescs)
 
 	// If this request needs to go to a lease holder and we know who that is, move
 	// it to the front.
 	var cachedLeaseHolder roachpb.ReplicaDescriptor
-	canSendToFollower := ds.clusterID != nil &&
-		CanSendToFollower(ds.clusterID.Get(), ds.st, ba)
 	if !canSendToFollower && ba.RequiresLeaseHolder() {
 		if storeID, ok := ds.leaseHolderCache.Lookup(ctx, desc.RangeID); ok {
 			if i := replicas.FindReplica(storeID); i >= 0 {
@@ -743,9 +753,11 @@ func (ds *DistSender) Send(
 		for _, rpl := range rplChunks[1:] {
 			reply.Responses = append(reply.Responses, rpl.Responses...)
 			reply.CollectedSpans = append(reply.CollectedSpans, rpl.CollectedSpans...)
+			reply.ContentionEvents = append(reply.ContentionEvents, rpl.ContentionEvents...)
 		}
 		lastHeader := rplChunks[len(rplChunks)-1].BatchResponse_Header
 		lastHeader.CollectedSpans = reply.CollectedSpans
+		lastHeader.ContentionEvents = reply.ContentionEvents
 		reply.BatchResponse_Header = lastHeader
 	}
 
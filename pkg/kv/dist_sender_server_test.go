@@ -587,6 +587,79 @@ func TestMultiRangeBoundedBatchScanSortedOverlapping(t *testing.T) {
 	checkScanResults(t, spans, b.Results, expResults, nil /* satisfied */, checkOptions{mode: Strict})
 }
 
+// TestMultiRangeBoundedBatchScanRangeTombstones verifies that the keys
+// deleted by MVCC range tombstones don't count against the limit of bounded
+// scans spanning multiple ranges: every page but the last one has exactly as
+// many keys as the limit allows.
+func TestMultiRangeBoundedBatchScanRangeTombstones(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	s, _ := startNoSplitMergeServer(t)
+	ctx := context.TODO()
+	defer s.Stopper().Stop(ctx)
+
+	db := s.DB()
+	if err := setupMultipleRanges(ctx, db, "a", "b", "c", "d"); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"a1", "a2", "a3", "b1", "b2", "b3", "c1", "c2", "d1"} {
+		if err := db.Put(ctx, key, "value"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Delete the keys from a2 to b2, across the boundary of the first two
+	// ranges.
+	var delBatch client.Batch
+	delBatch.AddRawRequest(&roachpb.DeleteRangeRequest{
+		RequestHeader: roachpb.RequestHeader{
+			Key:    roachpb.Key("a2"),
+			EndKey: roachpb.Key("b3"),
+		},
+		UseRangeTombstone: true,
+	})
+	if err := db.Run(ctx, &delBatch); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, reverse := range []bool{false, true} {
+		expKeys := []string{"a1", "b3", "c1", "c2", "d1"}
+		if reverse {
+			expKeys = []string{"d1", "c2", "c1", "b3", "a1"}
+		}
+		for bound := 1; bound <= len(expKeys)+1; bound++ {
+			t.Run(fmt.Sprintf("reverse=%t/bound=%d", reverse, bound), func(t *testing.T) {
+				var keys []string
+				span := roachpb.Span{Key: roachpb.Key("a"), EndKey: roachpb.Key("e")}
+				for {
+					b := &client.Batch{}
+					b.Header.MaxSpanRequestKeys = int64(bound)
+					if reverse {
+						b.ReverseScan(span.Key, span.EndKey)
+					} else {
+						b.Scan(span.Key, span.EndKey)
+					}
+					if err := db.Run(ctx, b); err != nil {
+						t.Fatal(err)
+					}
+					res := b.Results[0]
+					expLen := len(expKeys) - len(keys)
+					if expLen > bound {
+						expLen = bound
+					}
+					require.Len(t, res.Rows, expLen, "after %v", keys)
+					for _, row := range res.Rows {
+						keys = append(keys, string(row.Key))
+					}
+					if res.ResumeSpan == nil {
+						break
+					}
+					span = *res.ResumeSpan
+				}
+				require.Equal(t, expKeys, keys)
+			})
+		}
+	}
+}
+
 // check ResumeSpan in the DelRange results.
 func checkResumeSpanDelRangeResults(
 	t *testing.T, spans [][]string, results []client.Result, expResults [][]string, expCount int,
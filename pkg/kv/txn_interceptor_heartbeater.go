@@ -392,8 +392,9 @@ func (h *txnHeartbeater) abortTxnAsyncLocked(ctx context.Context) {
 }
 
 // firstWriteIndex returns the index of the first transactional write in the
-// BatchRequest. Returns -1 if the batch has not intention to write. It also
-// verifies that if an EndTxnRequest is included, then it is the last request
+// BatchRequest. Locking reads are treated as writes because the locks they
+// acquire need the transaction to be kept alive and eventually released.
+// Returns -1 if the batch has not intention to write. It also verifies that if an EndTxnRequest is included, then it is the last request
 // in the batch.
 func firstWriteIndex(ba *roachpb.BatchRequest) (int, *roachpb.Error) {
 	for i, ru := range ba.Requests {
@@ -403,7 +404,7 @@ func firstWriteIndex(ba *roachpb.BatchRequest) (int, *roachpb.Error) {
 				return -1, roachpb.NewErrorf("%s sent as non-terminal call", args.Method())
 			}
 		}
-		if roachpb.IsTransactionWrite(args) {
+		if roachpb.IsTransactionWrite(args) || roachpb.IsLocking(args) {
 			return i, nil
 		}
 	}
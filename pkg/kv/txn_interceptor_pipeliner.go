@@ -265,6 +265,10 @@ func (tp *txnPipeliner) attachWritesToEndTxn(
 				w := roachpb.SequencedWrite{Key: h.Key, Sequence: h.Sequence}
 				et.InFlightWrites = append(et.InFlightWrites, w)
 			}
+		} else if roachpb.IsLocking(req) {
+			// Locking reads acquire unreplicated locks, which are released
+			// along with the transaction's intents.
+			et.IntentSpans = append(et.IntentSpans, h.Span())
 		}
 	}
 
@@ -503,6 +507,13 @@ func (tp *txnPipeliner) updateWriteTracking(
 					tp.footprint.insert(sp)
 				}
 			}
+		} else if roachpb.IsLocking(req) {
+			// If the request was a locking read, track the locks that it
+			// acquired so that they are released when the transaction is
+			// finalized.
+			if sp, ok := roachpb.ActualSpan(req, resp); ok {
+				tp.footprint.insert(sp)
+			}
 		}
 	}
 }
@@ -100,6 +100,7 @@ const (
 	updatesTSCacheOnErr             // commands which make read data available on errors
 	needsRefresh                    // commands which require refreshes to avoid serializable retries
 	canBackpressure                 // commands which deserve backpressure when a Range grows too large
+	isLocking                       // locking read cmds acquire unreplicated locks for their transaction
 )
 
 // IsReadOnly returns true iff the request is read-only.
@@ -127,6 +128,12 @@ func IsTransactionWrite(args Request) bool {
 	return (args.flags() & isTxnWrite) != 0
 }
 
+// IsLocking returns true if the request is a read that acquires locks on the
+// keys it returns when used within a transaction.
+func IsLocking(args Request) bool {
+	return (args.flags() & isLocking) != 0
+}
+
 // IsRange returns true if the command is range-based and must include
 // a start and an end key.
 func IsRange(args Request) bool {
@@ -263,6 +270,21 @@ func (avptr *AdminVerifyProtectedTimestampResponse) combine(c combinable) error
 	return nil
 }
 
+var _ combinable = &QueryResolvedTimestampResponse{}
+
+// combine implements the combinable interface. The resolved timestamp of a
+// set of key spans is the minimum of the resolved timestamps of its parts.
+func (r *QueryResolvedTimestampResponse) combine(c combinable) error {
+	other := c.(*QueryResolvedTimestampResponse)
+	if r != nil {
+		r.ResolvedTS.Backward(other.ResolvedTS)
+		if err := r.ResponseHeader.combine(other.Header()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 var _ combinable = &ScanResponse{}
 
 // combine implements the combinable interface.
@@ -407,6 +429,7 @@ func (h *BatchResponse_Header) combine(o BatchResponse_Header) error {
 	}
 	h.Now.Forward(o.Now)
 	h.CollectedSpans = append(h.CollectedSpans, o.CollectedSpans...)
+	h.ContentionEvents = append(h.ContentionEvents, o.ContentionEvents...)
 	return nil
 }
 
@@ -607,6 +630,9 @@ func (*RangeStatsRequest) Method() Method { return RangeStats }
 // Method implements the Request interface.
 func (*AdminVerifyProtectedTimestampRequest) Method() Method { return AdminVerifyProtectedTimestamp }
 
+// Method implements the Request interface.
+func (*QueryResolvedTimestampRequest) Method() Method { return QueryResolvedTimestamp }
+
 // ShallowCopy implements the Request interface.
 func (gr *GetRequest) ShallowCopy() Request {
 	shallowCopy := *gr
@@ -871,6 +897,12 @@ func (r *AdminVerifyProtectedTimestampRequest) ShallowCopy() Request {
 	return &shallowCopy
 }
 
+// ShallowCopy implements the Request interface.
+func (r *QueryResolvedTimestampRequest) ShallowCopy() Request {
+	shallowCopy := *r
+	return &shallowCopy
+}
+
 // NewGet returns a Request initialized to get the value at key.
 func NewGet(key Key) Request {
 	return &GetRequest{
@@ -1048,6 +1080,12 @@ func (drr *DeleteRangeRequest) flags() int {
 	if drr.Inline {
 		return isWrite | isRange | isAlone
 	}
+	// Range tombstones cannot be written transactionally either. Since they
+	// aren't consulted by later writes, the request updates the timestamp cache
+	// to prevent anybody from writing under it.
+	if drr.UseRangeTombstone {
+		return isWrite | isRange | isAlone | consultsTSCache | updatesTSCache
+	}
 	// DeleteRange updates the timestamp cache as it doesn't leave intents or
 	// tombstones for keys which don't yet exist, but still wants to prevent
 	// anybody from writing under it. Note that, even if we didn't update the ts
@@ -1064,9 +1102,22 @@ func (*ClearRangeRequest) flags() int { return isWrite | isRange | isAlone }
 // they clear all MVCC versions above their target time.
 func (*RevertRangeRequest) flags() int { return isWrite | isRange }
 
-func (*ScanRequest) flags() int { return isRead | isRange | isTxn | updatesTSCache | needsRefresh }
-func (*ReverseScanRequest) flags() int {
-	return isRead | isRange | isReverse | isTxn | updatesTSCache | needsRefresh
+func (r *ScanRequest) flags() int {
+	maybeLocking := flagForLockStrength(r.KeyLocking)
+	return isRead | isRange | isTxn | maybeLocking | updatesTSCache | needsRefresh
+}
+func (r *ReverseScanRequest) flags() int {
+	maybeLocking := flagForLockStrength(r.KeyLocking)
+	return isRead | isRange | isReverse | isTxn | maybeLocking | updatesTSCache | needsRefresh
+}
+
+// flagForLockStrength returns the isLocking flag for reads that acquire locks
+// with the provided strength.
+func flagForLockStrength(str lock.Strength) int {
+	if str != lock.None {
+		return isLocking
+	}
+	return 0
 }
 
 // EndTxn updates the timestamp cache to prevent replays.
@@ -1144,6 +1195,11 @@ func (r *RefreshRangeRequest) flags() int {
 func (*SubsumeRequest) flags() int    { return isRead | isAlone | updatesTSCache }
 func (*RangeStatsRequest) flags() int { return isRead }
 
+// QueryResolvedTimestampRequest does not update the timestamp cache. It
+// doesn't read any values, it only reports on the state of the range that
+// serves it.
+func (*QueryResolvedTimestampRequest) flags() int { return isRead | isRange }
+
 // IsParallelCommit returns whether the EndTxn request is attempting to perform
 // a parallel commit. See txn_interceptor_committer.go for a discussion about
 // parallel commits.
@@ -1256,6 +1312,18 @@ func (rc ReplicationChanges) Removals() []ReplicationTarget {
 	return rc.byType(REMOVE_REPLICA)
 }
 
+// NonVoterAdditions returns a slice of all contained replication changes that
+// add non-voting replicas.
+func (rc ReplicationChanges) NonVoterAdditions() []ReplicationTarget {
+	return rc.byType(ADD_NON_VOTER)
+}
+
+// NonVoterRemovals returns a slice of all contained replication changes that
+// remove non-voting replicas.
+func (rc ReplicationChanges) NonVoterRemovals() []ReplicationTarget {
+	return rc.byType(REMOVE_NON_VOTER)
+}
+
 // Changes returns the changes requested by this AdminChangeReplicasRequest, taking
 // the deprecated method of doing so into account.
 func (acrr *AdminChangeReplicasRequest) Changes() []ReplicationChange {
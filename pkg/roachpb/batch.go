@@ -91,6 +91,12 @@ func (ba *BatchRequest) IsWrite() bool {
 	return ba.hasFlag(isWrite)
 }
 
+// IsLocking returns true iff the BatchRequest contains a read that acquires
+// locks.
+func (ba *BatchRequest) IsLocking() bool {
+	return ba.hasFlag(isLocking)
+}
+
 // IsReadOnly returns true if all requests within are read-only.
 func (ba *BatchRequest) IsReadOnly() bool {
 	return len(ba.Requests) > 0 && !ba.hasFlag(isWrite|isAdmin)
@@ -160,6 +166,20 @@ func (ba *BatchRequest) IsSingleQueryTxnRequest() bool {
 	return false
 }
 
+// IsAllQueryResolvedTimestamp returns true iff the batch is non-empty and
+// contains only QueryResolvedTimestamp requests.
+func (ba *BatchRequest) IsAllQueryResolvedTimestamp() bool {
+	if len(ba.Requests) == 0 {
+		return false
+	}
+	for _, union := range ba.Requests {
+		if _, ok := union.GetInner().(*QueryResolvedTimestampRequest); !ok {
+			return false
+		}
+	}
+	return true
+}
+
 // IsSingleHeartbeatTxnRequest returns true iff the batch contains a single
 // request, and that request is a HeartbeatTxn.
 func (ba *BatchRequest) IsSingleHeartbeatTxnRequest() bool {
@@ -348,14 +368,15 @@ func (br *BatchResponse) String() string {
 }
 
 // IntentSpanIterate calls the passed method with the key ranges of the
-// transactional writes contained in the batch. Usually the key spans
-// contained in the requests are used, but when a response contains a
-// ResumeSpan the ResumeSpan is subtracted from the request span to provide a
-// more minimal span of keys affected by the request.
+// transactional writes and locking reads contained in the batch, on which the
+// transaction holds intents or locks that need to be released when it is
+// finalized. Usually the key spans contained in the requests are used, but
+// when a response contains a ResumeSpan the ResumeSpan is subtracted from the
+// request span to provide a more minimal span of keys affected by the request.
 func (ba *BatchRequest) IntentSpanIterate(br *BatchResponse, fn func(Span)) {
 	for i, arg := range ba.Requests {
 		req := arg.GetInner()
-		if !IsTransactionWrite(req) {
+		if !IsTransactionWrite(req) && !IsLocking(req) {
 			continue
 		}
 		var resp Response
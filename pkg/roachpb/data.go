@@ -1409,7 +1409,7 @@ func confChangeImpl(
 				Type:   raftpb.ConfChangeAddLearnerNode,
 				NodeID: uint64(rDesc.ReplicaID),
 			})
-		case LEARNER:
+		case LEARNER, NON_VOTER:
 			// A learner could in theory show up in the descriptor if the
 			// removal was really a demotion and no joint consensus is used.
 			// But etcd/raft currently forces us to go through joint consensus
@@ -1453,6 +1453,10 @@ func confChangeImpl(
 			// Demotions (i.e. transitioning from voter to learner) are not
 			// represented in `added`; they're handled in `removed` above.
 			changeType = raftpb.ConfChangeAddLearnerNode
+		case NON_VOTER:
+			// We're adding a non-voting replica, which raft treats as a
+			// learner.
+			changeType = raftpb.ConfChangeAddLearnerNode
 		default:
 			// A voter that is demoting was just removed and re-added in the
 			// `removals` handler. We should not see it again here.
@@ -55,6 +55,13 @@ func ReplicaTypeLearner() *ReplicaType {
 	return &t
 }
 
+// ReplicaTypeNonVoter returns a NON_VOTER pointer suitable for use in
+// a nullable proto field.
+func ReplicaTypeNonVoter() *ReplicaType {
+	t := NON_VOTER
+	return &t
+}
+
 // ReplicaDescriptors is a set of replicas, usually the nodes/stores on which
 // replicas of a range are stored.
 type ReplicaDescriptors struct {
@@ -108,6 +115,14 @@ func predLearner(rDesc ReplicaDescriptor) bool {
 	return rDesc.GetType() == LEARNER
 }
 
+func predNonVoter(rDesc ReplicaDescriptor) bool {
+	return rDesc.GetType() == NON_VOTER
+}
+
+func predVoterFullOrIncomingOrNonVoter(rDesc ReplicaDescriptor) bool {
+	return predVoterFullOrIncoming(rDesc) || predNonVoter(rDesc)
+}
+
 // Voters returns the current and future voter replicas in the set. This means
 // that during an atomic replication change, only the replicas that will be
 // voters once the change completes will be returned; "outgoing" voters will not
@@ -140,10 +155,10 @@ func (d ReplicaDescriptors) Voters() []ReplicaDescriptor {
 // then a second ConfChange promotes it to a full replica.
 //
 // This means that learners are currently always expected to have a short
-// lifetime, approximately the time it takes to send a snapshot. Ideas have been
-// kicked around to use learners with follower reads, which could be a cheap way
-// to allow many geographies to have local reads without affecting write
-// latencies. If implemented, these learners would have long lifetimes.
+// lifetime, approximately the time it takes to send a snapshot. Long-lived
+// replicas that don't vote, which allow many geographies to have local
+// follower reads without affecting write latencies, are instead represented
+// by the NON_VOTER type; see NonVoters.
 //
 // For simplicity, CockroachDB treats learner replicas the same as voter
 // replicas as much as possible, but there are a few exceptions:
@@ -215,6 +230,30 @@ func (d ReplicaDescriptors) Learners() []ReplicaDescriptor {
 	return d.Filter(predLearner)
 }
 
+// NonVoters returns the non-voting replicas in the set. This may allocate, but
+// it also may return the underlying slice as a performance optimization, so
+// it's not safe to modify the returned value.
+//
+// Non-voting replicas are raft learners, but unlike the replicas returned by
+// Learners they are not a transient state: they are placed by the allocator
+// according to the num_voters and voter_constraints fields of the zone config
+// and are kept around for as long as the zone config asks for them. They
+// receive the raft log, so they can serve follower reads, but they don't
+// affect quorum, don't count towards the replication factor used to determine
+// whether a range is under- or over-replicated, and can't hold the lease.
+func (d ReplicaDescriptors) NonVoters() []ReplicaDescriptor {
+	return d.Filter(predNonVoter)
+}
+
+// VotersAndNonVoters returns the current and future voter replicas (see
+// Voters) as well as the non-voting replicas in the set. These are the
+// replicas that may serve follower reads. This may allocate, but it also may
+// return the underlying slice as a performance optimization, so it's not safe
+// to modify the returned value.
+func (d ReplicaDescriptors) VotersAndNonVoters() []ReplicaDescriptor {
+	return d.Filter(predVoterFullOrIncomingOrNonVoter)
+}
+
 // Filter returns only the replica descriptors for which the supplied method
 // returns true. The memory returned may be shared with the receiver.
 func (d ReplicaDescriptors) Filter(pred func(rDesc ReplicaDescriptor) bool) []ReplicaDescriptor {
@@ -287,7 +326,7 @@ func (d ReplicaDescriptors) InAtomicReplicationChange() bool {
 		switch rDesc.GetType() {
 		case VOTER_INCOMING, VOTER_OUTGOING, VOTER_DEMOTING:
 			return true
-		case VOTER_FULL, LEARNER:
+		case VOTER_FULL, LEARNER, NON_VOTER:
 		default:
 			panic(fmt.Sprintf("unknown replica type %d", rDesc.GetType()))
 		}
@@ -318,7 +357,7 @@ func (d ReplicaDescriptors) ConfState() raftpb.ConfState {
 		case VOTER_DEMOTING:
 			cs.VotersOutgoing = append(cs.VotersOutgoing, id)
 			cs.LearnersNext = append(cs.LearnersNext, id)
-		case LEARNER:
+		case LEARNER, NON_VOTER:
 			cs.Learners = append(cs.Learners, id)
 		default:
 			panic(fmt.Sprintf("unknown ReplicaType %d", typ))
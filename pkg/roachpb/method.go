@@ -155,4 +155,7 @@ const (
 	// VerifyProtectedTimestamp determines whether the specified protection record
 	// will be respected by this Range.
 	AdminVerifyProtectedTimestamp
+	// QueryResolvedTimestamp requests the resolved timestamp of the key span it
+	// is issued over.
+	QueryResolvedTimestamp
 )
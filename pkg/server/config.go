@@ -419,13 +419,38 @@ func (e *Engines) Close() {
 	*e = nil
 }
 
-// CreateEngines creates Engines based on the specs in cfg.Stores.
-func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
+// RaftEngines maps the engine of a store which keeps its Raft log in a
+// separate engine to that engine. Stores that don't are absent from the map.
+type RaftEngines map[engine.Engine]engine.Engine
+
+// Close closes all the Raft engines.
+func (e *RaftEngines) Close() {
+	for _, eng := range *e {
+		eng.Close()
+	}
+	*e = nil
+}
+
+// get returns the engine holding the Raft log of the store backed by eng,
+// which is eng itself unless a separate engine was configured.
+func (e RaftEngines) get(eng engine.Engine) engine.Engine {
+	if raftEng, ok := e[eng]; ok {
+		return raftEng
+	}
+	return eng
+}
+
+// CreateEngines creates Engines based on the specs in cfg.Stores. For stores
+// whose spec has a RaftPath, a separate engine holding the Raft log is created
+// as well; the returned RaftEngines map each such store's engine to it.
+func (cfg *Config) CreateEngines(ctx context.Context) (Engines, RaftEngines, error) {
 	engines := Engines(nil)
 	defer engines.Close()
+	raftEngines := RaftEngines(nil)
+	defer raftEngines.Close()
 
 	if cfg.enginesCreated {
-		return Engines{}, errors.Errorf("engines already created")
+		return Engines{}, nil, errors.Errorf("engines already created")
 	}
 	cfg.enginesCreated = true
 
@@ -448,10 +473,14 @@ func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 		if !spec.InMemory {
 			physicalStores++
 		}
+		if spec.RaftPath != "" {
+			// The Raft engine needs its share of open files too.
+			physicalStores++
+		}
 	}
 	openFileLimitPerStore, err := setOpenFileLimit(physicalStores)
 	if err != nil {
-		return Engines{}, err
+		return Engines{}, nil, err
 	}
 
 	log.Event(ctx, "initializing engines")
@@ -465,12 +494,12 @@ func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 			if spec.Size.Percent > 0 {
 				sysMem, err := status.GetTotalMemory(ctx)
 				if err != nil {
-					return Engines{}, errors.Errorf("could not retrieve system memory")
+					return Engines{}, nil, errors.Errorf("could not retrieve system memory")
 				}
 				sizeInBytes = int64(float64(sysMem) * spec.Size.Percent / 100)
 			}
 			if sizeInBytes != 0 && !skipSizeCheck && sizeInBytes < base.MinimumStoreSize {
-				return Engines{}, errors.Errorf("%f%% of memory is only %s bytes, which is below the minimum requirement of %s",
+				return Engines{}, nil, errors.Errorf("%f%% of memory is only %s bytes, which is below the minimum requirement of %s",
 					spec.Size.Percent, humanizeutil.IBytes(sizeInBytes), humanizeutil.IBytes(base.MinimumStoreSize))
 			}
 			details = append(details, fmt.Sprintf("store %d: in-memory, size %s",
@@ -480,7 +509,7 @@ func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 					ctx, spec.StickyInMemoryEngineID, cfg.StorageEngine, spec.Attributes, sizeInBytes,
 				)
 				if err != nil {
-					return Engines{}, err
+					return Engines{}, nil, err
 				}
 				engines = append(engines, e)
 			} else {
@@ -490,80 +519,38 @@ func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 			if spec.Size.Percent > 0 {
 				fileSystemUsage := gosigar.FileSystemUsage{}
 				if err := fileSystemUsage.Get(spec.Path); err != nil {
-					return Engines{}, err
+					return Engines{}, nil, err
 				}
 				sizeInBytes = int64(float64(fileSystemUsage.Total) * spec.Size.Percent / 100)
 			}
 			if sizeInBytes != 0 && !skipSizeCheck && sizeInBytes < base.MinimumStoreSize {
-				return Engines{}, errors.Errorf("%f%% of %s's total free space is only %s bytes, which is below the minimum requirement of %s",
+				return Engines{}, nil, errors.Errorf("%f%% of %s's total free space is only %s bytes, which is below the minimum requirement of %s",
 					spec.Size.Percent, spec.Path, humanizeutil.IBytes(sizeInBytes), humanizeutil.IBytes(base.MinimumStoreSize))
 			}
 
 			details = append(details, fmt.Sprintf("store %d: RocksDB, max size %s, max open file limit %d",
 				i, humanizeutil.IBytes(sizeInBytes), openFileLimitPerStore))
 
-			var eng engine.Engine
-			var err error
-			storageConfig := base.StorageConfig{
-				Attrs:           spec.Attributes,
-				Dir:             spec.Path,
-				MaxSize:         sizeInBytes,
-				Settings:        cfg.Settings,
-				UseFileRegistry: spec.UseFileRegistry,
-				ExtraOptions:    spec.ExtraOptions,
+			eng, err := cfg.openOnDiskEngine(
+				ctx, spec, spec.Path, sizeInBytes, openFileLimitPerStore, cache, pebbleCache,
+			)
+			if err != nil {
+				return Engines{}, nil, err
 			}
-			if cfg.StorageEngine == enginepb.EngineTypePebble {
-				// TODO(itsbilal): Tune these options, and allow them to be overridden
-				// in the spec (similar to the existing spec.RocksDBOptions and others).
-				pebbleConfig := engine.PebbleConfig{
-					StorageConfig: storageConfig,
-					Opts:          engine.DefaultPebbleOptions(),
-				}
-				pebbleConfig.Opts.Cache = pebbleCache
-				pebbleConfig.Opts.MaxOpenFiles = int(openFileLimitPerStore)
-				eng, err = engine.NewPebble(ctx, pebbleConfig)
-			} else if cfg.StorageEngine == enginepb.EngineTypeRocksDB {
-				rocksDBConfig := engine.RocksDBConfig{
-					StorageConfig:           storageConfig,
-					MaxOpenFiles:            openFileLimitPerStore,
-					WarnLargeBatchThreshold: 500 * time.Millisecond,
-					RocksDBOptions:          spec.RocksDBOptions,
-				}
-
-				eng, err = engine.NewRocksDB(rocksDBConfig, cache)
-			} else {
-				// cfg.StorageEngine == enginepb.EngineTypeTeePebbleRocksDB
-				pebbleConfig := engine.PebbleConfig{
-					StorageConfig: storageConfig,
-					Opts:          engine.DefaultPebbleOptions(),
-				}
-				pebbleConfig.Dir = filepath.Join(pebbleConfig.Dir, "pebble")
-				pebbleConfig.Opts.Cache = pebbleCache
-				pebbleConfig.Opts.MaxOpenFiles = int(openFileLimitPerStore)
-				pebbleEng, err := engine.NewPebble(ctx, pebbleConfig)
+			engines = append(engines, eng)
+			if spec.RaftPath != "" {
+				details = append(details, fmt.Sprintf("store %d: Raft log in %s", i, spec.RaftPath))
+				raftEng, err := cfg.openOnDiskEngine(
+					ctx, spec, spec.RaftPath, 0 /* sizeInBytes */, openFileLimitPerStore, cache, pebbleCache,
+				)
 				if err != nil {
-					return nil, err
-				}
-
-				rocksDBConfig := engine.RocksDBConfig{
-					StorageConfig:           storageConfig,
-					MaxOpenFiles:            openFileLimitPerStore,
-					WarnLargeBatchThreshold: 500 * time.Millisecond,
-					RocksDBOptions:          spec.RocksDBOptions,
+					return Engines{}, nil, err
 				}
-				rocksDBConfig.Dir = filepath.Join(rocksDBConfig.Dir, "rocksdb")
-
-				rocksdbEng, err := engine.NewRocksDB(rocksDBConfig, cache)
-				if err != nil {
-					return nil, err
+				if raftEngines == nil {
+					raftEngines = RaftEngines{}
 				}
-
-				eng = engine.NewTee(ctx, rocksdbEng, pebbleEng)
+				raftEngines[eng] = raftEng
 			}
-			if err != nil {
-				return Engines{}, err
-			}
-			engines = append(engines, eng)
 		}
 	}
 
@@ -572,9 +559,85 @@ func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 	for _, s := range details {
 		log.Info(ctx, s)
 	}
-	enginesCopy := engines
-	engines = nil
-	return enginesCopy, nil
+	enginesCopy, raftEnginesCopy := engines, raftEngines
+	engines, raftEngines = nil, nil
+	return enginesCopy, raftEnginesCopy, nil
+}
+
+// openOnDiskEngine opens the on-disk engine for the given store spec, rooted
+// at dir. It is used both for a store's main engine and, if the spec asks for
+// one, for the separate engine holding its Raft log.
+func (cfg *Config) openOnDiskEngine(
+	ctx context.Context,
+	spec base.StoreSpec,
+	dir string,
+	sizeInBytes int64,
+	openFileLimitPerStore uint64,
+	cache engine.RocksDBCache,
+	pebbleCache *pebble.Cache,
+) (engine.Engine, error) {
+	var eng engine.Engine
+	var err error
+	storageConfig := base.StorageConfig{
+		Attrs:           spec.Attributes,
+		Dir:             dir,
+		MaxSize:         sizeInBytes,
+		Settings:        cfg.Settings,
+		UseFileRegistry: spec.UseFileRegistry,
+		ExtraOptions:    spec.ExtraOptions,
+	}
+	if cfg.StorageEngine == enginepb.EngineTypePebble {
+		// TODO(itsbilal): Tune these options, and allow them to be overridden
+		// in the spec (similar to the existing spec.RocksDBOptions and others).
+		pebbleConfig := engine.PebbleConfig{
+			StorageConfig: storageConfig,
+			Opts:          engine.DefaultPebbleOptions(),
+		}
+		pebbleConfig.Opts.Cache = pebbleCache
+		pebbleConfig.Opts.MaxOpenFiles = int(openFileLimitPerStore)
+		eng, err = engine.NewPebble(ctx, pebbleConfig)
+	} else if cfg.StorageEngine == enginepb.EngineTypeRocksDB {
+		rocksDBConfig := engine.RocksDBConfig{
+			StorageConfig:           storageConfig,
+			MaxOpenFiles:            openFileLimitPerStore,
+			WarnLargeBatchThreshold: 500 * time.Millisecond,
+			RocksDBOptions:          spec.RocksDBOptions,
+		}
+
+		eng, err = engine.NewRocksDB(rocksDBConfig, cache)
+	} else {
+		// cfg.StorageEngine == enginepb.EngineTypeTeePebbleRocksDB
+		pebbleConfig := engine.PebbleConfig{
+			StorageConfig: storageConfig,
+			Opts:          engine.DefaultPebbleOptions(),
+		}
+		pebbleConfig.Dir = filepath.Join(pebbleConfig.Dir, "pebble")
+		pebbleConfig.Opts.Cache = pebbleCache
+		pebbleConfig.Opts.MaxOpenFiles = int(openFileLimitPerStore)
+		pebbleEng, err := engine.NewPebble(ctx, pebbleConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		rocksDBConfig := engine.RocksDBConfig{
+			StorageConfig:           storageConfig,
+			MaxOpenFiles:            openFileLimitPerStore,
+			WarnLargeBatchThreshold: 500 * time.Millisecond,
+			RocksDBOptions:          spec.RocksDBOptions,
+		}
+		rocksDBConfig.Dir = filepath.Join(rocksDBConfig.Dir, "rocksdb")
+
+		rocksdbEng, err := engine.NewRocksDB(rocksDBConfig, cache)
+		if err != nil {
+			return nil, err
+		}
+
+		eng = engine.NewTee(ctx, rocksdbEng, pebbleEng)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return eng, nil
 }
 
 // InitNode parses node attributes and initializes the gossip bootstrap
@@ -168,6 +168,9 @@ type Node struct {
 	lastUp      int64
 	initialBoot bool // True if this is the first time this node has started.
 	txnMetrics  kv.TxnMetrics
+	// raftEngines holds the separate Raft engines of those stores configured
+	// to use one, keyed by the stores' engines.
+	raftEngines RaftEngines
 
 	perReplicaServer storage.Server
 }
@@ -424,7 +427,7 @@ func (n *Node) start(
 
 	// Create stores from the engines that were already bootstrapped.
 	for _, e := range initializedEngines {
-		s := storage.NewStore(ctx, n.storeCfg, e, &n.Descriptor)
+		s := storage.NewStoreWithRaftEngine(ctx, n.storeCfg, e, n.raftEngines.get(e), &n.Descriptor)
 		if err := s.Start(ctx, n.stopper); err != nil {
 			return errors.Errorf("failed to start store: %s", err)
 		}
@@ -622,7 +625,7 @@ func (n *Node) bootstrapStores(
 				return err
 			}
 
-			s := storage.NewStore(ctx, n.storeCfg, eng, &n.Descriptor)
+			s := storage.NewStoreWithRaftEngine(ctx, n.storeCfg, eng, n.raftEngines.get(eng), &n.Descriptor)
 			if err := s.Start(ctx, stopper); err != nil {
 				return err
 			}
@@ -50,6 +50,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/contention"
 	"github.com/cockroachdb/cockroach/pkg/sql/distsql"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
@@ -197,11 +198,16 @@ type Server struct {
 	debug            *debug.Server
 	// sessionRegistry can be queried for info on running SQL sessions. It is
 	// shared between the sql.Server and the statusServer.
-	sessionRegistry     *sql.SessionRegistry
+	sessionRegistry *sql.SessionRegistry
+	// contentionRegistry records the contention events encountered by the SQL
+	// statements executed on this node. It is shared between the sql.Server and
+	// the statusServer.
+	contentionRegistry  *contention.Registry
 	jobRegistry         *jobs.Registry
 	statsRefresher      *stats.Refresher
 	replicationReporter *reports.Reporter
 	engines             Engines
+	raftEngines         RaftEngines
 	internalMemMetrics  sql.MemoryMetrics
 	adminMemMetrics     sql.MemoryMetrics
 	// sqlMemMetrics are used to track memory usage of sql sessions.
@@ -563,6 +569,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		s.ClusterSettings(), s.nodeLiveness, internalExecutor)
 
 	s.sessionRegistry = sql.NewSessionRegistry()
+	s.contentionRegistry = contention.NewRegistry()
 	var jobAdoptionStopFile string
 	for _, spec := range s.cfg.Stores.Specs {
 		if !spec.InMemory && spec.Path != "" {
@@ -680,6 +687,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		s.node.stores,
 		s.stopper,
 		s.sessionRegistry,
+		s.contentionRegistry,
 	)
 	s.authentication = newAuthenticationServer(s)
 	for _, gw := range []grpcGatewayServer{s.admin, s.status, s.authentication, &s.tsServer} {
@@ -740,6 +748,8 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		RangeDescriptorCache:    s.distSender.RangeDescriptorCache(),
 		LeaseHolderCache:        s.distSender.LeaseHolderCache(),
 		RoleMemberCache:         &sql.MembershipCache{},
+		StatementHintsCache:     &sql.StatementHintsCache{},
+		ContentionRegistry:      s.contentionRegistry,
 		TestingKnobs:            sqlExecutorTestingKnobs,
 
 		DistSQLPlanner: sql.NewDistSQLPlanner(
@@ -852,6 +862,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 	s.leaseMgr.SetInternalExecutor(execCfg.InternalExecutor)
 	s.leaseMgr.RefreshLeases(s.stopper, s.db, s.gossip)
 	s.leaseMgr.PeriodicallyRefreshSomeLeases()
+	execCfg.StatementHintsCache.Start(ctx, s.stopper, s.gossip)
 
 	s.node.InitLogger(&execCfg)
 	s.cfg.DefaultZoneConfig = cfg.DefaultZoneConfig
@@ -1351,11 +1362,13 @@ func (s *Server) Start(ctx context.Context) error {
 	}
 	s.mux.Handle("/health", gwMux)
 
-	s.engines, err = s.cfg.CreateEngines(ctx)
+	s.engines, s.raftEngines, err = s.cfg.CreateEngines(ctx)
 	if err != nil {
 		return errors.Wrap(err, "failed to create engines")
 	}
 	s.stopper.AddCloser(&s.engines)
+	s.stopper.AddCloser(&s.raftEngines)
+	s.node.raftEngines = s.raftEngines
 
 	s.node.startAssertEngineHealth(ctx, s.engines)
 
@@ -48,6 +48,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/contention"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/storagepb"
 	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
@@ -128,19 +129,20 @@ func propagateGatewayMetadata(ctx context.Context) context.Context {
 type statusServer struct {
 	log.AmbientContext
 
-	st              *cluster.Settings
-	cfg             *base.Config
-	admin           *adminServer
-	db              *client.DB
-	gossip          *gossip.Gossip
-	metricSource    metricMarshaler
-	nodeLiveness    *storage.NodeLiveness
-	storePool       *storage.StorePool
-	rpcCtx          *rpc.Context
-	stores          *storage.Stores
-	stopper         *stop.Stopper
-	sessionRegistry *sql.SessionRegistry
-	si              systemInfoOnce
+	st                 *cluster.Settings
+	cfg                *base.Config
+	admin              *adminServer
+	db                 *client.DB
+	gossip             *gossip.Gossip
+	metricSource       metricMarshaler
+	nodeLiveness       *storage.NodeLiveness
+	storePool          *storage.StorePool
+	rpcCtx             *rpc.Context
+	stores             *storage.Stores
+	stopper            *stop.Stopper
+	sessionRegistry    *sql.SessionRegistry
+	contentionRegistry *contention.Registry
+	si                 systemInfoOnce
 }
 
 // newStatusServer allocates and returns a statusServer.
@@ -158,22 +160,24 @@ func newStatusServer(
 	stores *storage.Stores,
 	stopper *stop.Stopper,
 	sessionRegistry *sql.SessionRegistry,
+	contentionRegistry *contention.Registry,
 ) *statusServer {
 	ambient.AddLogTag("status", nil)
 	server := &statusServer{
-		AmbientContext:  ambient,
-		st:              st,
-		cfg:             cfg,
-		admin:           adminServer,
-		db:              db,
-		gossip:          gossip,
-		metricSource:    metricSource,
-		nodeLiveness:    nodeLiveness,
-		storePool:       storePool,
-		rpcCtx:          rpcCtx,
-		stores:          stores,
-		stopper:         stopper,
-		sessionRegistry: sessionRegistry,
+		AmbientContext:     ambient,
+		st:                 st,
+		cfg:                cfg,
+		admin:              adminServer,
+		db:                 db,
+		gossip:             gossip,
+		metricSource:       metricSource,
+		nodeLiveness:       nodeLiveness,
+		storePool:          storePool,
+		rpcCtx:             rpcCtx,
+		stores:             stores,
+		stopper:            stopper,
+		sessionRegistry:    sessionRegistry,
+		contentionRegistry: contentionRegistry,
 	}
 
 	return server
@@ -1706,6 +1710,65 @@ func (s *statusServer) ListSessions(
 	return response, nil
 }
 
+// ListLocalContentionEvents returns the contention events recorded on this
+// node, aggregated by statement fingerprint and index.
+func (s *statusServer) ListLocalContentionEvents(
+	ctx context.Context, req *serverpb.ListContentionEventsRequest,
+) (*serverpb.ListContentionEventsResponse, error) {
+	ctx = propagateGatewayMetadata(ctx)
+	if _, err := s.admin.requireAdminUser(ctx); err != nil {
+		return nil, err
+	}
+
+	events := s.contentionRegistry.Serialize()
+	nodeID := s.gossip.NodeID.Get()
+	for i := range events {
+		events[i].NodeID = nodeID
+	}
+	return &serverpb.ListContentionEventsResponse{Events: events}, nil
+}
+
+// ListContentionEvents returns the contention events recorded on all nodes in
+// the cluster, aggregated by statement fingerprint and index.
+func (s *statusServer) ListContentionEvents(
+	ctx context.Context, req *serverpb.ListContentionEventsRequest,
+) (*serverpb.ListContentionEventsResponse, error) {
+	if _, err := s.admin.requireAdminUser(ctx); err != nil {
+		return nil, err
+	}
+
+	ctx = propagateGatewayMetadata(ctx)
+	ctx = s.AnnotateCtx(ctx)
+
+	response := &serverpb.ListContentionEventsResponse{
+		Events: make([]serverpb.IndexContention, 0),
+		Errors: make([]serverpb.ListContentionEventsError, 0),
+	}
+
+	dialFn := func(ctx context.Context, nodeID roachpb.NodeID) (interface{}, error) {
+		client, err := s.dialNode(ctx, nodeID)
+		return client, err
+	}
+	nodeFn := func(ctx context.Context, client interface{}, _ roachpb.NodeID) (interface{}, error) {
+		status := client.(serverpb.StatusClient)
+		return status.ListLocalContentionEvents(ctx, req)
+	}
+	responseFn := func(_ roachpb.NodeID, nodeResp interface{}) {
+		events := nodeResp.(*serverpb.ListContentionEventsResponse)
+		response.Events = append(response.Events, events.Events...)
+	}
+	errorFn := func(nodeID roachpb.NodeID, err error) {
+		errResponse := serverpb.ListContentionEventsError{NodeID: nodeID, Message: err.Error()}
+		response.Errors = append(response.Errors, errResponse)
+	}
+
+	if err := s.iterateNodes(ctx, "contention events", dialFn, nodeFn, responseFn, errorFn); err != nil {
+		err := serverpb.ListContentionEventsError{Message: err.Error()}
+		response.Errors = append(response.Errors, err)
+	}
+	return response, nil
+}
+
 // CancelSession responds to a session cancellation request by canceling the
 // target session's associated context.
 func (s *statusServer) CancelSession(
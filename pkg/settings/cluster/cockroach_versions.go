@@ -53,6 +53,15 @@ const (
 	VersionRootPassword
 	VersionNoExplicitForeignKeyIndexIDs
 	VersionHashShardedIndexes
+	VersionStatementHints
+	VersionNonVotingReplicas
+	VersionNestedArrays
+	VersionMultiColumnStats
+	VersionLockWaitPolicies
+	VersionQueryResolvedTimestamp
+	VersionMVCCRangeTombstones
+	VersionFullTextSearch
+	VersionArrayInvertedIndexes
 
 	// Add new versions here (step one of two).
 )
@@ -408,6 +417,70 @@ var versionsSingleton = keyedVersions([]keyedVersion{
 		Key:     VersionHashShardedIndexes,
 		Version: roachpb.Version{Major: 19, Minor: 2, Unstable: 12},
 	},
+	{
+		// VersionStatementHints introduces the system.statement_hints table, which
+		// binds statement fingerprints to optimizer hints.
+		//
+		// In this version and later the system.statement_hints table is part of
+		// the system bootstrap schema.
+		Key:     VersionStatementHints,
+		Version: roachpb.Version{Major: 19, Minor: 2, Unstable: 13},
+	},
+	{
+		// VersionNonVotingReplicas enables the NON_VOTER replica type, which
+		// receives the raft log but does not participate in quorum, along with
+		// the num_voters and voter_constraints zone config fields.
+		Key:     VersionNonVotingReplicas,
+		Version: roachpb.Version{Major: 19, Minor: 2, Unstable: 14},
+	},
+	{
+		// VersionNestedArrays allows nested array types and array slices to be sent
+		// to other nodes as part of distributed flows. Nodes running previous
+		// versions can neither unmarshal the former nor evaluate the latter.
+		Key:     VersionNestedArrays,
+		Version: roachpb.Version{Major: 19, Minor: 2, Unstable: 15},
+	},
+	{
+		// VersionMultiColumnStats is the version from which the samplers of all nodes
+		// can build sketches of multiple columns, so CREATE STATISTICS can request
+		// multi-column statistics.
+		Key:     VersionMultiColumnStats,
+		Version: roachpb.Version{Major: 19, Minor: 2, Unstable: 16},
+	},
+	{
+		// VersionLockWaitPolicies is the version from which all nodes support the
+		// SkipLocked and Error lock wait policies on BatchRequests and acquire
+		// unreplicated locks in locking scans.
+		Key:     VersionLockWaitPolicies,
+		Version: roachpb.Version{Major: 19, Minor: 2, Unstable: 17},
+	},
+	{
+		// VersionQueryResolvedTimestamp is the version from which all nodes support the
+		// QueryResolvedTimestamp request and route batches according to the
+		// RoutingPolicy header field, which bounded staleness reads rely on.
+		Key:     VersionQueryResolvedTimestamp,
+		Version: roachpb.Version{Major: 19, Minor: 2, Unstable: 18},
+	},
+	{
+		// VersionMVCCRangeTombstones is the version from which all nodes honor MVCC
+		// range tombstones, which DeleteRange requests can write when their
+		// use_range_tombstone field is set.
+		Key:     VersionMVCCRangeTombstones,
+		Version: roachpb.Version{Major: 19, Minor: 2, Unstable: 19},
+	},
+	{
+		// VersionFullTextSearch is the version from which all nodes support the TSVECTOR
+		// and TSQUERY types, which can then be used for table columns and indexed with
+		// inverted indexes.
+		Key:     VersionFullTextSearch,
+		Version: roachpb.Version{Major: 19, Minor: 2, Unstable: 20},
+	},
+	{
+		// VersionArrayInvertedIndexes is the version from which all nodes support
+		// inverted indexes on ARRAY columns.
+		Key:     VersionArrayInvertedIndexes,
+		Version: roachpb.Version{Major: 19, Minor: 2, Unstable: 21},
+	},
 
 	// Add new versions here (step two of two).
 
@@ -29,11 +29,20 @@ func _() {
 	_ = x[VersionRootPassword-18]
 	_ = x[VersionNoExplicitForeignKeyIndexIDs-19]
 	_ = x[VersionHashShardedIndexes-20]
+	_ = x[VersionStatementHints-21]
+	_ = x[VersionNonVotingReplicas-22]
+	_ = x[VersionNestedArrays-23]
+	_ = x[VersionMultiColumnStats-24]
+	_ = x[VersionLockWaitPolicies-25]
+	_ = x[VersionQueryResolvedTimestamp-26]
+	_ = x[VersionMVCCRangeTombstones-27]
+	_ = x[VersionFullTextSearch-28]
+	_ = x[VersionArrayInvertedIndexes-29]
 }
 
-const _VersionKey_name = "Version19_1VersionStart19_2VersionLearnerReplicasVersionTopLevelForeignKeysVersionAtomicChangeReplicasTriggerVersionAtomicChangeReplicasVersionTableDescModificationTimeFromMVCCVersionPartitionedBackupVersion19_2VersionStart20_1VersionContainsEstimatesCounterVersionChangeReplicasDemotionVersionSecondaryIndexColumnFamiliesVersionNamespaceTableWithSchemasVersionProtectedTimestampsVersionPrimaryKeyChangesVersionAuthLocalAndTrustRejectMethodsVersionPrimaryKeyColumnsOutOfFamilyZeroVersionRootPasswordVersionNoExplicitForeignKeyIndexIDsVersionHashShardedIndexes"
+const _VersionKey_name = "Version19_1VersionStart19_2VersionLearnerReplicasVersionTopLevelForeignKeysVersionAtomicChangeReplicasTriggerVersionAtomicChangeReplicasVersionTableDescModificationTimeFromMVCCVersionPartitionedBackupVersion19_2VersionStart20_1VersionContainsEstimatesCounterVersionChangeReplicasDemotionVersionSecondaryIndexColumnFamiliesVersionNamespaceTableWithSchemasVersionProtectedTimestampsVersionPrimaryKeyChangesVersionAuthLocalAndTrustRejectMethodsVersionPrimaryKeyColumnsOutOfFamilyZeroVersionRootPasswordVersionNoExplicitForeignKeyIndexIDsVersionHashShardedIndexesVersionStatementHintsVersionNonVotingReplicasVersionNestedArraysVersionMultiColumnStatsVersionLockWaitPoliciesVersionQueryResolvedTimestampVersionMVCCRangeTombstonesVersionFullTextSearchVersionArrayInvertedIndexes"
 
-var _VersionKey_index = [...]uint16{0, 11, 27, 49, 75, 109, 136, 176, 200, 211, 227, 258, 287, 322, 354, 380, 404, 441, 480, 499, 534, 559}
+var _VersionKey_index = [...]uint16{0, 11, 27, 49, 75, 109, 136, 176, 200, 211, 227, 258, 287, 322, 354, 380, 404, 441, 480, 499, 534, 559, 580, 604, 623, 646, 669, 698, 724, 745, 772}
 
 func (i VersionKey) String() string {
 	if i < 0 || i >= VersionKey(len(_VersionKey_index)-1) {
@@ -139,6 +139,9 @@ func (n *alterTableNode) startExec(params runParams) error {
 			d = newDef
 
 			telemetry.Inc(sqltelemetry.SchemaNewTypeCounter(d.Type.TelemetryName()))
+			if err := checkColumnTypeIsSupported(params.ctx, params.ExecCfg().Settings, d.Type); err != nil {
+				return err
+			}
 			col, idx, expr, err := sqlbase.MakeColumnDefDescs(d, &params.p.semaCtx)
 			if err != nil {
 				return err
@@ -1068,6 +1071,9 @@ func applyColumnMutation(
 		if err != nil {
 			return err
 		}
+		if err := checkColumnTypeIsSupported(params.ctx, params.ExecCfg().Settings, typ); err != nil {
+			return err
+		}
 
 		// No-op if the types are Identical.  We don't use Equivalent here because
 		// the user may be trying to change the type of the column without changing
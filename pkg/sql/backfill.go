@@ -31,6 +31,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/rowexec"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -1215,9 +1216,9 @@ func (sc *SchemaChanger) validateInvertedIndexes(
 			select {
 			case <-countReady[i]:
 				if idxLen != expectedCount[i] {
-					// JSON columns cannot have unique indexes, so if the expected and
-					// actual counts do not match, it's always a bug rather than a
-					// uniqueness violation.
+					// Inverted indexes cannot be unique, so if the expected and actual
+					// counts do not match, it's always a bug rather than a uniqueness
+					// violation.
 					return errors.AssertionFailedf(
 						"validation of index %s failed: expected %d rows, found %d",
 						idx.Name, errors.Safe(expectedCount[i]), errors.Safe(idxLen))
@@ -1237,14 +1238,35 @@ func (sc *SchemaChanger) validateInvertedIndexes(
 					idx.Name, idx.ColumnNames))
 			}
 			col := idx.ColumnNames[0]
+			colDesc, _, err := tableDesc.FindColumnByName(tree.Name(col))
+			if err != nil {
+				return err
+			}
+			// The counting query may be distributed to nodes that don't know
+			// about crdb_internal.num_inverted_index_entries yet, so JSON columns,
+			// which these nodes can index, keep using the JSON-specific builtin.
+			// The types that gained inverted index support only use the new
+			// builtin once the version that introduced their support is active.
+			countFn := "crdb_internal.json_num_index_entries"
+			if key, ok := invertedIndexVersion(&colDesc.Type); ok {
+				if !cluster.Version.IsActive(ctx, sc.settings, key) {
+					return pgerror.Newf(pgcode.FeatureNotSupported,
+						"inverted index %s can only be validated once the cluster is fully upgraded to version %s",
+						idx.Name, cluster.VersionByKey(key))
+				}
+				countFn = "crdb_internal.num_inverted_index_entries"
+			} else if colDesc.Type.Family() != types.JsonFamily {
+				return errors.AssertionFailedf("unexpected type %s of inverted index %s",
+					colDesc.Type.SQLString(), idx.Name)
+			}
 
 			if err := runHistoricalTxn(ctx, func(ctx context.Context, txn *client.Txn, evalCtx *extendedEvalContext) error {
 				ie := evalCtx.InternalExecutor.(*InternalExecutor)
 				row, err := ie.QueryRowEx(ctx, "verify-inverted-idx-count", txn,
 					sqlbase.InternalExecutorSessionDataOverride{},
 					fmt.Sprintf(
-						`SELECT coalesce(sum_int(crdb_internal.json_num_index_entries(%s)), 0) FROM [%d AS t]`,
-						col, tableDesc.ID,
+						`SELECT coalesce(sum_int(%s(%s)), 0) FROM [%d AS t]`,
+						countFn, col, tableDesc.ID,
 					),
 				)
 				if err != nil {
@@ -1255,7 +1277,7 @@ func (sc *SchemaChanger) validateInvertedIndexes(
 			}); err != nil {
 				return err
 			}
-			log.Infof(ctx, "JSON column %s/%s expected inverted index count = %d, took %s",
+			log.Infof(ctx, "column %s/%s expected inverted index count = %d, took %s",
 				tableDesc.Name, col, expectedCount[i], timeutil.Since(start))
 			return nil
 		})
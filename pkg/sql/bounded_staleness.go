@@ -0,0 +1,122 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// negotiateBoundedStalenessTimestamp determines the timestamp at which a
+// bounded staleness read (i.e. a SELECT with an AS OF SYSTEM TIME clause using
+// with_min_timestamp or with_max_staleness) is run, once the statement has
+// been planned and the spans it reads are known.
+//
+// The statement is run at the newest timestamp that the replicas nearest to
+// this node are able to serve for all of these spans, provided that timestamp
+// satisfies the staleness bound. In that case the transaction is fixed to the
+// negotiated timestamp, its reads are routed to the nearest replicas and true
+// is returned. Otherwise, the statement is run as a regular read at the
+// current timestamp against the leaseholders and false is returned.
+func (ex *connExecutor) negotiateBoundedStalenessTimestamp(
+	ctx context.Context, p *planner,
+) (bool, error) {
+	txn := ex.state.mu.txn
+	// Reset the routing policy in case the transaction is being retried.
+	txn.SetRoutingPolicy(roachpb.RoutingPolicy_LEASEHOLDER)
+
+	spans, err := p.boundedStalenessSpans(ctx)
+	if err != nil {
+		return false, err
+	}
+	if len(spans) == 0 {
+		// Nothing is scanned from the KV layer, so there is nothing to gain.
+		return false, nil
+	}
+	if !cluster.Version.IsActive(ctx, ex.server.cfg.Settings, cluster.VersionQueryResolvedTimestamp) {
+		// Nodes running older versions don't understand QueryResolvedTimestamp
+		// requests and ignore the routing policy. A read at the current
+		// timestamp trivially satisfies the staleness bound.
+		log.VEventf(ctx, 2, "bounded staleness: not supported until the cluster is upgraded")
+		return false, nil
+	}
+	if txn.Active() {
+		// The transaction has already performed reads during planning at its
+		// current timestamp, so it can no longer be moved into the past.
+		log.VEventf(ctx, 2, "bounded staleness: transaction already active")
+		return false, nil
+	}
+
+	// The statement was planned using the leased versions of the descriptors of
+	// the tables it reads, which must be valid at the negotiated timestamp.
+	minTS := *p.semaCtx.AsOfBoundedStalenessMinTimestamp
+	for _, table := range p.Tables().leasedTables {
+		minTS.Forward(table.ModificationTime)
+	}
+
+	resolvedTS, err := ex.server.cfg.DB.QueryResolvedTimestamp(ctx, spans, true /* nearest */)
+	if err != nil {
+		return false, err
+	}
+	ts := ex.state.getReadTimestamp()
+	ts.Backward(resolvedTS)
+	if ts.Less(minTS) {
+		log.VEventf(ctx, 2,
+			"bounded staleness: resolved timestamp %s below minimum timestamp %s, reading from leaseholders",
+			resolvedTS, minTS)
+		return false, nil
+	}
+
+	log.VEventf(ctx, 2, "bounded staleness: reading from nearest replicas at %s", ts)
+	ex.state.setHistoricalTimestamp(ctx, ts)
+	txn.SetRoutingPolicy(roachpb.RoutingPolicy_NEAREST)
+	return true, nil
+}
+
+// boundedStalenessSpans returns the key spans scanned by the current plan and
+// its subqueries. An error is returned if the plan performs any writes, as
+// bounded staleness reads must be read-only.
+//
+// The keys looked up by index and lookup joins are only known during
+// execution, and the spans of the indexes they read from would usually cover
+// entire tables, so they are not included. Such lookups are still performed
+// at the negotiated timestamp; they are served by the nearest replica if it
+// has closed the timestamp and are redirected to the leaseholder otherwise.
+func (p *planner) boundedStalenessSpans(ctx context.Context) ([]roachpb.Span, error) {
+	var spans []roachpb.Span
+	observer := planObserver{
+		enterNode: func(ctx context.Context, _ string, plan planNode) (bool, error) {
+			switch n := plan.(type) {
+			case *scanNode:
+				if !n.desc.IsVirtualTable() {
+					spans = append(spans, n.spans...)
+				}
+			case *insertNode, *insertFastPathNode, *updateNode, *upsertNode,
+				*deleteNode, *deleteRangeNode:
+				return false, tree.ErrBoundedStalenessNotSupported
+			}
+			return true, nil
+		},
+	}
+	if err := walkPlan(ctx, p.curPlan.plan, observer); err != nil {
+		return nil, err
+	}
+	for i := range p.curPlan.subqueryPlans {
+		if err := walkPlan(ctx, p.curPlan.subqueryPlans[i].plan, observer); err != nil {
+			return nil, err
+		}
+	}
+	return spans, nil
+}
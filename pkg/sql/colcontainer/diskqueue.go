@@ -50,7 +50,8 @@ type file struct {
 	totalSize    int
 	// finishedWriting specifies whether this file will be written to in the
 	// future or not. If finishedWriting is true and the reader reaches the end of
-	// the file, the file represented by this struct should be closed and removed.
+	// the file, the file represented by this struct should be closed and (if the
+	// disk queue is not rewindable) removed.
 	finishedWriting bool
 }
 
@@ -157,6 +158,10 @@ type diskQueue struct {
 	seqNo int
 
 	done bool
+	// rewindable indicates whether the files should be kept around once all
+	// the batches from them have been dequeued so that the queue could be
+	// rewound.
+	rewindable bool
 
 	serializer *colserde.FileSerializer
 	// numBufferedBatches is the number of batches buffered that haven't been
@@ -178,7 +183,7 @@ type diskQueue struct {
 	scratchDecompressedReadBytes []byte
 }
 
-var _ Queue = &diskQueue{}
+var _ RewindableQueue = &diskQueue{}
 
 // Queue describes a simple queue interface to which coldata.Batches can be
 // Enqueued and Dequeued.
@@ -198,6 +203,16 @@ type Queue interface {
 	Close() error
 }
 
+// RewindableQueue is a Queue that can be read from multiple times. Note that
+// in order for this Queue to function correctly, all data must have been
+// enqueued before any data is dequeued.
+type RewindableQueue interface {
+	Queue
+	// Rewind resets the Queue so that it Dequeues all Enqueued batches from the
+	// start.
+	Rewind() error
+}
+
 const (
 	// These values were chosen by running BenchmarkQueue.
 	defaultBufferSizeBytes  = 128 << 10 /* 128 KiB */
@@ -249,6 +264,23 @@ func (cfg *DiskQueueCfg) EnsureDefaults() error {
 // NewDiskQueue creates a Queue that spills to disk.
 // TODO(asubiotto): Plumb down a monitor for disk space.
 func NewDiskQueue(typs []coltypes.T, cfg DiskQueueCfg) (Queue, error) {
+	d, err := newDiskQueue(typs, cfg, false /* rewindable */)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// NewRewindableDiskQueue creates a RewindableQueue that spills to disk.
+func NewRewindableDiskQueue(typs []coltypes.T, cfg DiskQueueCfg) (RewindableQueue, error) {
+	d, err := newDiskQueue(typs, cfg, true /* rewindable */)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func newDiskQueue(typs []coltypes.T, cfg DiskQueueCfg, rewindable bool) (*diskQueue, error) {
 	if err := cfg.EnsureDefaults(); err != nil {
 		return nil, err
 	}
@@ -256,10 +288,11 @@ func NewDiskQueue(typs []coltypes.T, cfg DiskQueueCfg) (Queue, error) {
 		cfg.OnNewDiskQueueCb()
 	}
 	d := &diskQueue{
-		dirName: uuid.FastMakeV4().String(),
-		typs:    typs,
-		cfg:     cfg,
-		files:   make([]file, 0, 4),
+		dirName:    uuid.FastMakeV4().String(),
+		typs:       typs,
+		cfg:        cfg,
+		files:      make([]file, 0, 4),
+		rewindable: rewindable,
 	}
 	if err := cfg.FS.CreateDir(filepath.Join(cfg.Path, d.dirName)); err != nil {
 		return nil, err
@@ -420,12 +453,15 @@ func (d *diskQueue) maybeInitDeserializer() (bool, error) {
 		// either the region to read from next is currently being written to or the
 		// writer has rotated to a new file.
 		if fileToRead.finishedWriting {
-			// Close and remove current file.
+			// Close and remove current file (unless the queue is rewindable, in
+			// which case the file will be removed in Close).
 			if err := d.readFile.Close(); err != nil {
 				return false, err
 			}
-			if err := d.cfg.FS.DeleteFile(d.files[d.readFileIdx].name); err != nil {
-				return false, err
+			if !d.rewindable {
+				if err := d.cfg.FS.DeleteFile(d.files[d.readFileIdx].name); err != nil {
+					return false, err
+				}
 			}
 			d.readFile = nil
 			// Read next file.
@@ -564,3 +600,25 @@ func (d *diskQueue) Dequeue(b coldata.Batch) (bool, error) {
 
 	return true, nil
 }
+
+// Rewind is part of the RewindableQueue interface.
+func (d *diskQueue) Rewind() error {
+	if d.deserializerState.FileDeserializer != nil {
+		if err := d.deserializerState.Close(); err != nil {
+			return err
+		}
+		d.deserializerState.FileDeserializer = nil
+	}
+	d.deserializerState.curBatch = 0
+	if d.readFile != nil {
+		if err := d.readFile.Close(); err != nil {
+			return err
+		}
+		d.readFile = nil
+	}
+	d.readFileIdx = 0
+	for i := range d.files {
+		d.files[i].curOffsetIdx = 0
+	}
+	return nil
+}
@@ -42,80 +42,105 @@ func TestDiskQueue(t *testing.T) {
 	}
 
 	rng, _ := randutil.NewPseudoRand()
-	for _, bufferSizeBytes := range []int{0, 16<<10 + rng.Intn(1<<20) /* 16 KiB up to 1 MiB */} {
-		for _, maxFileSizeBytes := range []int{10 << 10 /* 10 KiB */, 1<<20 + rng.Intn(64<<20) /* 1 MiB up to 64 MiB */} {
-			alwaysCompress := rng.Float64() < 0.5
-			t.Run(fmt.Sprintf("AlwaysCompress=%t/BufferSizeBytes=%s/MaxFileSizeBytes=%s", alwaysCompress, humanizeutil.IBytes(int64(bufferSizeBytes)), humanizeutil.IBytes(int64(maxFileSizeBytes))), func(t *testing.T) {
-				// Create random input.
-				batches := make([]coldata.Batch, 0, 1+rng.Intn(2048))
-				op := colexec.NewRandomDataOp(testAllocator, rng, colexec.RandomDataOpArgs{
-					AvailableTyps: availableTyps,
-					NumBatches:    cap(batches),
-					BatchSize:     1 + rng.Intn(int(coldata.BatchSize())),
-					Nulls:         true,
-					BatchAccumulator: func(b coldata.Batch) {
-						batches = append(batches, colexec.CopyBatch(testAllocator, b))
-					},
-				})
-				typs := op.Typs()
-
-				// Create queue.
-				queueCfg.TestingKnobs.AlwaysCompress = alwaysCompress
-				q, err := colcontainer.NewDiskQueue(typs, queueCfg)
-				require.NoError(t, err)
-
-				// Verify that a directory was created.
-				directories, err := queueCfg.FS.ListDir(queueCfg.Path)
-				require.NoError(t, err)
-				require.Equal(t, 1, len(directories))
-
-				// Run verification.
-				ctx := context.Background()
-				for {
-					b := op.Next(ctx)
-					require.NoError(t, q.Enqueue(b))
-					if b.Length() == 0 {
-						break
+	for _, rewindable := range []bool{false, true} {
+		for _, bufferSizeBytes := range []int{0, 16<<10 + rng.Intn(1<<20) /* 16 KiB up to 1 MiB */} {
+			for _, maxFileSizeBytes := range []int{10 << 10 /* 10 KiB */, 1<<20 + rng.Intn(64<<20) /* 1 MiB up to 64 MiB */} {
+				alwaysCompress := rng.Float64() < 0.5
+				t.Run(fmt.Sprintf("Rewindable=%t/AlwaysCompress=%t/BufferSizeBytes=%s/MaxFileSizeBytes=%s", rewindable, alwaysCompress, humanizeutil.IBytes(int64(bufferSizeBytes)), humanizeutil.IBytes(int64(maxFileSizeBytes))), func(t *testing.T) {
+					// Create random input.
+					batches := make([]coldata.Batch, 0, 1+rng.Intn(2048))
+					op := colexec.NewRandomDataOp(testAllocator, rng, colexec.RandomDataOpArgs{
+						AvailableTyps: availableTyps,
+						NumBatches:    cap(batches),
+						BatchSize:     1 + rng.Intn(int(coldata.BatchSize())),
+						Nulls:         true,
+						BatchAccumulator: func(b coldata.Batch) {
+							batches = append(batches, colexec.CopyBatch(testAllocator, b))
+						},
+					})
+					typs := op.Typs()
+
+					// Create queue.
+					queueCfg.TestingKnobs.AlwaysCompress = alwaysCompress
+					var (
+						q   colcontainer.Queue
+						rq  colcontainer.RewindableQueue
+						err error
+					)
+					if rewindable {
+						rq, err = colcontainer.NewRewindableDiskQueue(typs, queueCfg)
+						q = rq
+					} else {
+						q, err = colcontainer.NewDiskQueue(typs, queueCfg)
 					}
-					if rng.Float64() < 0.5 {
-						if ok, err := q.Dequeue(b); !ok {
-							t.Fatal("queue incorrectly considered empty")
-						} else if err != nil {
-							t.Fatal(err)
+					require.NoError(t, err)
+
+					// Verify that a directory was created.
+					directories, err := queueCfg.FS.ListDir(queueCfg.Path)
+					require.NoError(t, err)
+					require.Equal(t, 1, len(directories))
+
+					// Run verification.
+					ctx := context.Background()
+					for {
+						b := op.Next(ctx)
+						require.NoError(t, q.Enqueue(b))
+						if b.Length() == 0 {
+							break
+						}
+						// A rewindable queue requires all batches to be enqueued
+						// before any of them are dequeued.
+						if !rewindable && rng.Float64() < 0.5 {
+							if ok, err := q.Dequeue(b); !ok {
+								t.Fatal("queue incorrectly considered empty")
+							} else if err != nil {
+								t.Fatal(err)
+							}
+							coldata.AssertEquivalentBatches(t, batches[0], b)
+							batches = batches[1:]
 						}
-						coldata.AssertEquivalentBatches(t, batches[0], b)
-						batches = batches[1:]
 					}
-				}
-				b := coldata.NewMemBatch(typs)
-				i := 0
-				for len(batches) > 0 {
-					if ok, err := q.Dequeue(b); !ok {
-						t.Fatal("queue incorrectly considered empty")
-					} else if err != nil {
-						t.Fatal(err)
+					expected := batches
+					numReadIterations := 1
+					if rewindable {
+						// Read all batches twice to verify that rewinding works.
+						numReadIterations = 2
 					}
-					coldata.AssertEquivalentBatches(t, batches[0], b)
-					batches = batches[1:]
-					i++
-				}
-
-				if ok, err := q.Dequeue(b); ok {
-					if b.Length() != 0 {
-						t.Fatal("queue should be empty")
+					b := coldata.NewMemBatch(typs)
+					for iteration := 0; iteration < numReadIterations; iteration++ {
+						batches = expected
+						for len(batches) > 0 {
+							if ok, err := q.Dequeue(b); !ok {
+								t.Fatal("queue incorrectly considered empty")
+							} else if err != nil {
+								t.Fatal(err)
+							}
+							coldata.AssertEquivalentBatches(t, batches[0], b)
+							batches = batches[1:]
+						}
+
+						if ok, err := q.Dequeue(b); ok {
+							if b.Length() != 0 {
+								t.Fatal("queue should be empty")
+							}
+						} else if err != nil {
+							t.Fatal(err)
+						}
+
+						if rewindable {
+							require.NoError(t, rq.Rewind())
+						}
 					}
-				} else if err != nil {
-					t.Fatal(err)
-				}
-
-				// Close queue.
-				require.NoError(t, q.Close())
-
-				// Verify no directories are left over.
-				directories, err = queueCfg.FS.ListDir(queueCfg.Path)
-				require.NoError(t, err)
-				require.Equal(t, 0, len(directories))
-			})
+
+					// Close queue.
+					require.NoError(t, q.Close())
+
+					// Verify no directories are left over.
+					directories, err = queueCfg.FS.ListDir(queueCfg.Path)
+					require.NoError(t, err)
+					require.Equal(t, 0, len(directories))
+				})
+			}
 		}
 	}
 }
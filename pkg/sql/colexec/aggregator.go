@@ -15,8 +15,10 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/typeconv"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/errors"
 )
@@ -34,6 +36,7 @@ var SupportedAggFns = []execinfrapb.AggregatorSpec_Func{
 	execinfrapb.AggregatorSpec_MAX,
 	execinfrapb.AggregatorSpec_BOOL_AND,
 	execinfrapb.AggregatorSpec_BOOL_OR,
+	execinfrapb.AggregatorSpec_STRING_AGG,
 }
 
 // aggregateFunc is an aggregate function that performs computation on a batch
@@ -142,6 +145,10 @@ type orderedAggregator struct {
 	groupCol []bool
 	// aggregateFuncs are the aggregator's aggregate function operators.
 	aggregateFuncs []aggregateFunc
+	// argFilters[i], if non-nil, removes the inputs of aggregateFuncs[i] that
+	// must not contribute to the aggregation because of the FILTER or DISTINCT
+	// clauses.
+	argFilters []*aggArgFilter
 	// isScalar indicates whether an aggregator is in scalar context.
 	isScalar bool
 	// seenNonEmptyBatch indicates whether a non-empty input batch has been
@@ -151,37 +158,37 @@ type orderedAggregator struct {
 
 var _ Operator = &orderedAggregator{}
 
-// NewOrderedAggregator creates an ordered aggregator on the given grouping
-// columns. aggCols is a slice where each index represents a new aggregation
-// function. The slice at that index specifies the columns of the input batch
-// that the aggregate function should work on.
+// NewOrderedAggregator creates an ordered aggregator described by spec on
+// top of the input with the given column types. The input must be ordered on
+// the grouping columns. constArguments[i] contains the constant arguments of
+// the ith aggregation of spec (like the delimiter of string_agg), and
+// constArguments can be nil if no aggregation has constant arguments.
 func NewOrderedAggregator(
 	allocator *Allocator,
 	input Operator,
 	colTypes []coltypes.T,
-	aggFns []execinfrapb.AggregatorSpec_Func,
-	groupCols []uint32,
-	aggCols [][]uint32,
-	isScalar bool,
+	spec *execinfrapb.AggregatorSpec,
+	constArguments []tree.Datums,
 ) (Operator, error) {
-	if len(aggFns) != len(aggCols) {
+	if constArguments != nil && len(constArguments) != len(spec.Aggregations) {
 		return nil,
 			errors.Errorf(
-				"mismatched aggregation lengths: aggFns(%d), aggCols(%d)",
-				len(aggFns),
-				len(aggCols),
+				"mismatched aggregation lengths: aggregations(%d), constArguments(%d)",
+				len(spec.Aggregations),
+				len(constArguments),
 			)
 	}
 
+	aggFns, aggCols := extractAggFnsAndCols(spec)
 	aggTypes := extractAggTypes(aggCols, colTypes)
 
-	op, groupCol, err := OrderedDistinctColsToOperators(input, groupCols, colTypes)
+	op, groupCol, err := OrderedDistinctColsToOperators(input, spec.GroupCols, colTypes)
 	if err != nil {
 		return nil, err
 	}
 
 	a := &orderedAggregator{}
-	if len(groupCols) == 0 {
+	if len(spec.GroupCols) == 0 {
 		// If there were no groupCols, we can't rely on the distinct operators to
 		// mark the first row as distinct, so we have to do it ourselves. Set up a
 		// oneShotOp to set the first row to distinct.
@@ -208,10 +215,10 @@ func NewOrderedAggregator(
 		aggCols:   aggCols,
 		aggTypes:  aggTypes,
 		groupCol:  groupCol,
-		isScalar:  isScalar,
+		isScalar:  execinfrapb.IsScalarAggregate(spec),
 	}
 
-	a.aggregateFuncs, a.outputTypes, err = makeAggregateFuncs(a.allocator, aggTypes, aggFns)
+	a.aggregateFuncs, a.outputTypes, err = makeAggregateFuncs(a.allocator, aggTypes, aggFns, constArguments)
 
 	if err != nil {
 		return nil, errors.AssertionFailedf(
@@ -219,11 +226,38 @@ func NewOrderedAggregator(
 		)
 	}
 
+	a.argFilters, err = makeAggArgFilters(a.allocator, spec, aggTypes)
+	if err != nil {
+		return nil, err
+	}
+
 	return a, nil
 }
 
+// extractAggFnsAndCols returns the aggregate functions of all aggregations in
+// spec as well as the columns that they operate on. Note that COUNT_ROWS with
+// a FILTER clause is replaced with COUNT on the filter column: the rows for
+// which the filter is not true are removed as NULLs (see aggArgFilter), so
+// counting the non-NULL values of the filter column gives the desired result.
+func extractAggFnsAndCols(
+	spec *execinfrapb.AggregatorSpec,
+) ([]execinfrapb.AggregatorSpec_Func, [][]uint32) {
+	aggFns := make([]execinfrapb.AggregatorSpec_Func, len(spec.Aggregations))
+	aggCols := make([][]uint32, len(spec.Aggregations))
+	for i, agg := range spec.Aggregations {
+		aggFns[i], aggCols[i] = agg.Func, agg.ColIdx
+		if agg.Func == execinfrapb.AggregatorSpec_COUNT_ROWS && agg.FilterColIdx != nil {
+			aggFns[i], aggCols[i] = execinfrapb.AggregatorSpec_COUNT, []uint32{*agg.FilterColIdx}
+		}
+	}
+	return aggFns, aggCols
+}
+
 func makeAggregateFuncs(
-	allocator *Allocator, aggTyps [][]coltypes.T, aggFns []execinfrapb.AggregatorSpec_Func,
+	allocator *Allocator,
+	aggTyps [][]coltypes.T,
+	aggFns []execinfrapb.AggregatorSpec_Func,
+	constArguments []tree.Datums,
 ) ([]aggregateFunc, []coltypes.T, error) {
 	funcs := make([]aggregateFunc, len(aggFns))
 	outTyps := make([]coltypes.T, len(aggFns))
@@ -249,6 +283,12 @@ func makeAggregateFuncs(
 			funcs[i] = newBoolAndAgg()
 		case execinfrapb.AggregatorSpec_BOOL_OR:
 			funcs[i] = newBoolOrAgg()
+		case execinfrapb.AggregatorSpec_STRING_AGG:
+			var arguments tree.Datums
+			if constArguments != nil {
+				arguments = constArguments[i]
+			}
+			funcs[i], err = newStringAggAgg(allocator, aggTyps[i][0], arguments)
 		default:
 			return nil, nil, errors.Errorf("unsupported columnar aggregate function %s", aggFns[i].String())
 		}
@@ -283,12 +323,19 @@ func (a *orderedAggregator) initWithInputAndOutputBatchSize(inputSize, outputSiz
 	// overflow when outputting.
 	a.scratch.inputSize = inputSize * 2
 	a.scratch.outputSize = outputSize
+}
+
+// allocateBatches allocates the output batches of the aggregator. It is done
+// lazily on the first call to Next (rather than in Init) so that a hash
+// aggregator that runs out of memory right away could still fall back to the
+// disk-backed one.
+func (a *orderedAggregator) allocateBatches() {
 	a.scratch.Batch = a.allocator.NewMemBatchWithSize(a.outputTypes, a.scratch.inputSize)
 	for i := 0; i < len(a.outputTypes); i++ {
 		vec := a.scratch.ColVec(i)
 		a.aggregateFuncs[i].Init(a.groupCol, vec)
 	}
-	a.unsafeBatch = a.allocator.NewMemBatchWithSize(a.outputTypes, outputSize)
+	a.unsafeBatch = a.allocator.NewMemBatchWithSize(a.outputTypes, a.scratch.outputSize)
 }
 
 func (a *orderedAggregator) Init() {
@@ -296,6 +343,9 @@ func (a *orderedAggregator) Init() {
 }
 
 func (a *orderedAggregator) Next(ctx context.Context) coldata.Batch {
+	if a.unsafeBatch == nil {
+		a.allocateBatches()
+	}
 	a.unsafeBatch.ResetInternalBatch()
 	if a.scratch.shouldResetInternalBatch {
 		a.scratch.ResetInternalBatch()
@@ -374,7 +424,11 @@ func (a *orderedAggregator) Next(ctx context.Context) coldata.Batch {
 			}
 		} else {
 			for i, fn := range a.aggregateFuncs {
-				fn.Compute(batch, a.aggCols[i])
+				if f := a.argFilters[i]; f != nil {
+					fn.Compute(f.filter(batch, a.aggCols[i][0], a.groupCol), a.aggCols[i])
+				} else {
+					fn.Compute(batch, a.aggCols[i])
+				}
 			}
 			a.scratch.resumeIdx = a.aggregateFuncs[0].CurrentOutputIndex()
 		}
@@ -428,6 +482,140 @@ func (a *orderedAggregator) reset() {
 	for _, fn := range a.aggregateFuncs {
 		fn.Reset()
 	}
+	for _, f := range a.argFilters {
+		if f != nil {
+			f.reset()
+		}
+	}
+}
+
+// ExportBuffered is part of the bufferingInMemoryOperator interface. Only the
+// orderedAggregator that is created by NewHashAggregator buffers tuples (in
+// its hashGrouper input), so it is the only one that supports this method.
+func (a *orderedAggregator) ExportBuffered(input Operator) coldata.Batch {
+	grouper, ok := a.input.(*hashGrouper)
+	if !ok {
+		execerror.VectorizedInternalPanic(errors.AssertionFailedf(
+			"unexpectedly ExportBuffered is called on the ordered aggregator with %T input", a.input,
+		))
+	}
+	return grouper.ExportBuffered(input)
+}
+
+// aggArgFilter removes the inputs of an aggregate function that must not
+// contribute to the aggregation, either because they don't pass the FILTER
+// clause or because the aggregate is DISTINCT and the same value has already
+// been seen in the current group. The inputs are removed by marking them as
+// NULLs in a window into the argument column which has its own nulls bitmap.
+// This is correct because all aggregate functions that can have FILTER or
+// DISTINCT clauses ignore NULL inputs (COUNT_ROWS with a FILTER clause is
+// planned as COUNT on the filter column).
+type aggArgFilter struct {
+	argType coltypes.T
+	// filterColIdx is the index of the boolean column that contains the result
+	// of the FILTER clause or -1 if the aggregate doesn't have one.
+	filterColIdx int
+	// distinctHelper is non-nil if the aggregate is DISTINCT.
+	distinctHelper aggDistinctHelper
+	batch          filteredArgBatch
+}
+
+// makeAggArgFilters returns the aggArgFilters for all aggregations in spec (nil
+// for those without FILTER and DISTINCT clauses). aggTypes are the input types
+// of the aggregations.
+func makeAggArgFilters(
+	allocator *Allocator, spec *execinfrapb.AggregatorSpec, aggTypes [][]coltypes.T,
+) ([]*aggArgFilter, error) {
+	filters := make([]*aggArgFilter, len(spec.Aggregations))
+	for i, agg := range spec.Aggregations {
+		if agg.FilterColIdx == nil && !agg.Distinct {
+			continue
+		}
+		if len(aggTypes[i]) != 1 {
+			return nil, errors.AssertionFailedf(
+				"unexpectedly aggregate with FILTER or DISTINCT has %d arguments", len(aggTypes[i]),
+			)
+		}
+		f := &aggArgFilter{argType: aggTypes[i][0], filterColIdx: -1}
+		if agg.FilterColIdx != nil {
+			f.filterColIdx = int(*agg.FilterColIdx)
+		}
+		if agg.Distinct {
+			var err error
+			if f.distinctHelper, err = newAggDistinctHelper(allocator, f.argType); err != nil {
+				return nil, err
+			}
+		}
+		filters[i] = f
+	}
+	return filters, nil
+}
+
+// filter returns a batch that is the same as the given one except for the
+// argument column at argIdx from which the inputs that must not contribute to
+// the aggregation have been removed. groups[i] is true if the ith tuple is the
+// first one of a new group.
+func (f *aggArgFilter) filter(batch coldata.Batch, argIdx uint32, groups []bool) coldata.Batch {
+	n := batch.Length()
+	if n == 0 {
+		return batch
+	}
+	sel := batch.Selection()
+	// The window must include all tuples that are referenced by the selection
+	// vector.
+	end := int(n)
+	if sel != nil {
+		end = 0
+		for _, i := range sel[:n] {
+			if int(i) >= end {
+				end = int(i) + 1
+			}
+		}
+	}
+	arg := batch.ColVec(int(argIdx)).Window(f.argType, 0, uint64(end))
+	if f.filterColIdx >= 0 {
+		filterVec := batch.ColVec(f.filterColIdx)
+		filterCol, filterNulls, argNulls := filterVec.Bool(), filterVec.Nulls(), arg.Nulls()
+		if sel != nil {
+			for _, i := range sel[:n] {
+				if filterNulls.NullAt(i) || !filterCol[i] {
+					argNulls.SetNull(i)
+				}
+			}
+		} else {
+			for i := uint16(0); i < n; i++ {
+				if filterNulls.NullAt(i) || !filterCol[i] {
+					argNulls.SetNull(i)
+				}
+			}
+		}
+	}
+	if f.distinctHelper != nil {
+		f.distinctHelper.removeDuplicates(arg, sel, n, groups)
+	}
+	f.batch.Batch, f.batch.argIdx, f.batch.arg = batch, int(argIdx), arg
+	return &f.batch
+}
+
+func (f *aggArgFilter) reset() {
+	if f.distinctHelper != nil {
+		f.distinctHelper.reset()
+	}
+}
+
+// filteredArgBatch is a coldata.Batch that replaces a single column of the
+// wrapped batch with arg.
+type filteredArgBatch struct {
+	coldata.Batch
+	argIdx int
+	arg    coldata.Vec
+}
+
+func (b *filteredArgBatch) ColVec(i int) coldata.Vec {
+	if i == b.argIdx {
+		return b.arg
+	}
+	return b.Batch.ColVec(i)
 }
 
 // extractAggTypes returns a nested array representing the input types
@@ -469,16 +657,27 @@ func isAggregateSupported(
 		if inputTypes[0].Width() != 64 {
 			return false, errors.Newf("sum_int is only supported on Int64 through vectorized")
 		}
+	case execinfrapb.AggregatorSpec_STRING_AGG:
+		if len(inputTypes) != 1 {
+			return false, errors.Newf("string_agg is only supported with a constant delimiter")
+		}
 	}
 	_, outputTypes, err := makeAggregateFuncs(
 		nil, /* allocator */
 		[][]coltypes.T{aggTypes},
 		[]execinfrapb.AggregatorSpec_Func{aggFn},
+		nil, /* constArguments */
 	)
 	if err != nil {
 		return false, err
 	}
-	_, retType, err := execinfrapb.GetAggregateInfo(aggFn, inputTypes...)
+	argTypes := inputTypes
+	if aggFn == execinfrapb.AggregatorSpec_STRING_AGG {
+		// The delimiter of string_agg is a constant argument of the same type as
+		// the input.
+		argTypes = append(inputTypes[:len(inputTypes):len(inputTypes)], inputTypes[0])
+	}
+	_, retType, err := execinfrapb.GetAggregateInfo(aggFn, argTypes...)
 	if err != nil {
 		return false, err
 	}
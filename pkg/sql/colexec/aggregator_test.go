@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
 )
@@ -50,6 +51,48 @@ type aggregatorTestCase struct {
 	// encountered, a best effort is made to convert that string to an
 	// apd.Decimal.
 	convToDecimal bool
+
+	// aggDistinct and aggFilterCols, if not nil, specify whether each of the
+	// aggregate functions is DISTINCT and the index of its FILTER column (-1
+	// meaning that there is no FILTER clause), respectively.
+	aggDistinct   []bool
+	aggFilterCols []int
+	// constArguments are the constant arguments of each of the aggregate
+	// functions.
+	constArguments []tree.Datums
+}
+
+// makeAggregatorSpec returns a non-scalar aggregator spec for the given
+// aggregate functions, grouping columns and arguments of the functions.
+func makeAggregatorSpec(
+	aggFns []execinfrapb.AggregatorSpec_Func, groupCols []uint32, aggCols [][]uint32,
+) *execinfrapb.AggregatorSpec {
+	spec := &execinfrapb.AggregatorSpec{
+		Type:         execinfrapb.AggregatorSpec_NON_SCALAR,
+		GroupCols:    groupCols,
+		Aggregations: make([]execinfrapb.AggregatorSpec_Aggregation, len(aggFns)),
+	}
+	for i, aggFn := range aggFns {
+		spec.Aggregations[i].Func = aggFn
+		spec.Aggregations[i].ColIdx = aggCols[i]
+	}
+	return spec
+}
+
+// spec returns the aggregator spec of the test case. It must be called after
+// init.
+func (tc *aggregatorTestCase) spec() *execinfrapb.AggregatorSpec {
+	spec := makeAggregatorSpec(tc.aggFns, tc.groupCols, tc.aggCols)
+	for i := range spec.Aggregations {
+		if tc.aggDistinct != nil {
+			spec.Aggregations[i].Distinct = tc.aggDistinct[i]
+		}
+		if tc.aggFilterCols != nil && tc.aggFilterCols[i] != -1 {
+			filterColIdx := uint32(tc.aggFilterCols[i])
+			spec.Aggregations[i].FilterColIdx = &filterColIdx
+		}
+	}
+	return spec
 }
 
 // aggType is a helper struct that allows tests to test both the ordered and
@@ -59,10 +102,8 @@ type aggType struct {
 		allocator *Allocator,
 		input Operator,
 		colTypes []coltypes.T,
-		aggFns []execinfrapb.AggregatorSpec_Func,
-		groupCols []uint32,
-		aggCols [][]uint32,
-		isScalar bool,
+		spec *execinfrapb.AggregatorSpec,
+		constArguments []tree.Datums,
 	) (Operator, error)
 	name string
 }
@@ -272,10 +313,8 @@ func TestAggregatorOneFunc(t *testing.T) {
 				testAllocator,
 				tupleSource,
 				tc.colTypes,
-				tc.aggFns,
-				tc.groupCols,
-				tc.aggCols,
-				false, /* isScalar */
+				tc.spec(),
+				tc.constArguments,
 			)
 			if err != nil {
 				t.Fatal(err)
@@ -299,10 +338,8 @@ func TestAggregatorOneFunc(t *testing.T) {
 									testAllocator,
 									input[0],
 									tc.colTypes,
-									tc.aggFns,
-									tc.groupCols,
-									tc.aggCols,
-									false, /* isScalar */
+									tc.spec(),
+									tc.constArguments,
 								)
 							})
 					})
@@ -420,7 +457,7 @@ func TestAggregatorMultiFunc(t *testing.T) {
 				}
 				runTests(t, []tuples{tc.input}, tc.expected, unorderedVerifier,
 					func(input []Operator) (Operator, error) {
-						return agg.new(testAllocator, input[0], tc.colTypes, tc.aggFns, tc.groupCols, tc.aggCols, false /* isScalar */)
+						return agg.new(testAllocator, input[0], tc.colTypes, tc.spec(), tc.constArguments)
 					})
 			})
 		}
@@ -508,13 +545,187 @@ func TestAggregatorAllFunctions(t *testing.T) {
 					tc.expected,
 					orderedVerifier,
 					func(input []Operator) (Operator, error) {
-						return agg.new(testAllocator, input[0], tc.colTypes, tc.aggFns, tc.groupCols, tc.aggCols, false /* isScalar */)
+						return agg.new(testAllocator, input[0], tc.colTypes, tc.spec(), tc.constArguments)
 					})
 			})
 		}
 	}
 }
 
+func TestAggregatorDistinctAndFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	testCases := []aggregatorTestCase{
+		{
+			aggFns: []execinfrapb.AggregatorSpec_Func{
+				execinfrapb.AggregatorSpec_ANY_NOT_NULL,
+				execinfrapb.AggregatorSpec_COUNT,
+				execinfrapb.AggregatorSpec_SUM_INT,
+				execinfrapb.AggregatorSpec_COUNT,
+			},
+			aggCols:     [][]uint32{{0}, {1}, {1}, {1}},
+			aggDistinct: []bool{false, true, true, false},
+			input: tuples{
+				{0, 1},
+				{0, 1},
+				{0, 2},
+				{0, nil},
+				{0, 2},
+				{1, 3},
+				{1, 3},
+				{2, nil},
+			},
+			expected: tuples{
+				{0, 2, 3, 4},
+				{1, 1, 3, 2},
+				{2, 0, nil, 0},
+			},
+			name: "Distinct",
+		},
+		{
+			aggFns: []execinfrapb.AggregatorSpec_Func{
+				execinfrapb.AggregatorSpec_ANY_NOT_NULL,
+				execinfrapb.AggregatorSpec_SUM_INT,
+				execinfrapb.AggregatorSpec_COUNT,
+				execinfrapb.AggregatorSpec_COUNT_ROWS,
+				execinfrapb.AggregatorSpec_SUM_INT,
+			},
+			aggCols:       [][]uint32{{0}, {1}, {1}, {}, {1}},
+			aggFilterCols: []int{-1, 2, 2, 2, -1},
+			colTypes:      []coltypes.T{coltypes.Int64, coltypes.Int64, coltypes.Bool},
+			input: tuples{
+				{0, 1, true},
+				{0, 2, false},
+				{0, 3, nil},
+				{0, 4, true},
+				{1, 5, false},
+				{1, nil, true},
+				{2, 6, true},
+			},
+			expected: tuples{
+				{0, 5, 2, 2, 10},
+				{1, nil, 0, 1, 5},
+				{2, 6, 1, 1, 6},
+			},
+			name: "Filter",
+		},
+		{
+			aggFns: []execinfrapb.AggregatorSpec_Func{
+				execinfrapb.AggregatorSpec_ANY_NOT_NULL,
+				execinfrapb.AggregatorSpec_COUNT,
+			},
+			aggCols:       [][]uint32{{0}, {1}},
+			aggDistinct:   []bool{false, true},
+			aggFilterCols: []int{-1, 2},
+			colTypes:      []coltypes.T{coltypes.Int64, coltypes.Int64, coltypes.Bool},
+			input: tuples{
+				{0, 1, false},
+				{0, 1, true},
+				{0, 2, true},
+				{0, 2, true},
+				{1, 3, false},
+			},
+			expected: tuples{
+				{0, 2},
+				{1, 0},
+			},
+			name: "DistinctAndFilter",
+		},
+		{
+			aggFns: []execinfrapb.AggregatorSpec_Func{
+				execinfrapb.AggregatorSpec_ANY_NOT_NULL,
+				execinfrapb.AggregatorSpec_STRING_AGG,
+			},
+			aggCols:  [][]uint32{{0}, {1}},
+			colTypes: []coltypes.T{coltypes.Int64, coltypes.Bytes},
+			// The order of the values within a group is not deterministic with the
+			// hash aggregator, so every group consists of equal values.
+			input: tuples{
+				{0, "a"},
+				{0, nil},
+				{0, "a"},
+				{1, nil},
+				{2, "c"},
+			},
+			expected: tuples{
+				{0, "a-a"},
+				{1, nil},
+				{2, "c"},
+			},
+			constArguments: []tree.Datums{nil, {tree.NewDString("-")}},
+			name:           "StringAgg",
+		},
+	}
+
+	for _, agg := range aggTypes {
+		for _, tc := range testCases {
+			t.Run(fmt.Sprintf("%s/%s", agg.name, tc.name), func(t *testing.T) {
+				if err := tc.init(); err != nil {
+					t.Fatal(err)
+				}
+				runTests(
+					t,
+					[]tuples{tc.input},
+					tc.expected,
+					unorderedVerifier,
+					func(input []Operator) (Operator, error) {
+						return agg.new(testAllocator, input[0], tc.colTypes, tc.spec(), tc.constArguments)
+					})
+			})
+		}
+	}
+}
+
+// TestAggregatorDistinctAndStringAggMemoryAccounting verifies that the memory
+// used by the DISTINCT aggregates to keep track of the values seen so far and
+// by STRING_AGG to build up its result is accounted for.
+func TestAggregatorDistinctAndStringAggMemoryAccounting(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	const numTuples = 100
+	input := make(tuples, numTuples)
+	totalBytes := int64(0)
+	for i := range input {
+		v := fmt.Sprintf("%0100d", i)
+		input[i] = tuple{0, v}
+		totalBytes += int64(len(v))
+	}
+	tc := aggregatorTestCase{
+		aggFns: []execinfrapb.AggregatorSpec_Func{
+			execinfrapb.AggregatorSpec_COUNT,
+			execinfrapb.AggregatorSpec_STRING_AGG,
+		},
+		groupCols:      []uint32{0},
+		aggCols:        [][]uint32{{1}, {1}},
+		aggDistinct:    []bool{true, false},
+		colTypes:       []coltypes.T{coltypes.Int64, coltypes.Bytes},
+		input:          input,
+		constArguments: []tree.Datums{nil, {tree.NewDString("")}},
+	}
+	if err := tc.init(); err != nil {
+		t.Fatal(err)
+	}
+	for _, agg := range aggTypes {
+		t.Run(agg.name, func(t *testing.T) {
+			acc := testMemMonitor.MakeBoundAccount()
+			defer acc.Close(ctx)
+			allocator := NewAllocator(ctx, &acc)
+			source := newOpTestInput(uint16(coldata.BatchSize()), tc.input, tc.colTypes)
+			a, err := agg.new(allocator, source, tc.colTypes, tc.spec(), tc.constArguments)
+			if err != nil {
+				t.Fatal(err)
+			}
+			a.Init()
+			for b := a.Next(ctx); b.Length() != 0; b = a.Next(ctx) {
+			}
+			// Both the set of the distinct values and the result of STRING_AGG
+			// contain every input value.
+			if used := acc.Used(); used < 2*totalBytes {
+				t.Fatalf("expected at least %d bytes to be accounted for, found %d", 2*totalBytes, used)
+			}
+		})
+	}
+}
+
 func TestAggregatorRandom(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	// This test aggregates random inputs, keeping track of the expected results
@@ -579,16 +790,18 @@ func TestAggregatorRandom(t *testing.T) {
 								testAllocator,
 								source,
 								typs,
-								[]execinfrapb.AggregatorSpec_Func{
-									execinfrapb.AggregatorSpec_COUNT_ROWS,
-									execinfrapb.AggregatorSpec_COUNT,
-									execinfrapb.AggregatorSpec_SUM_INT,
-									execinfrapb.AggregatorSpec_MIN,
-									execinfrapb.AggregatorSpec_MAX,
-									execinfrapb.AggregatorSpec_AVG},
-								[]uint32{0},
-								[][]uint32{{}, {1}, {1}, {1}, {1}, {1}},
-								false, /* isScalar */
+								makeAggregatorSpec(
+									[]execinfrapb.AggregatorSpec_Func{
+										execinfrapb.AggregatorSpec_COUNT_ROWS,
+										execinfrapb.AggregatorSpec_COUNT,
+										execinfrapb.AggregatorSpec_SUM_INT,
+										execinfrapb.AggregatorSpec_MIN,
+										execinfrapb.AggregatorSpec_MAX,
+										execinfrapb.AggregatorSpec_AVG},
+									[]uint32{0},
+									[][]uint32{{}, {1}, {1}, {1}, {1}, {1}},
+								),
+								nil, /* constArguments */
 							)
 							if err != nil {
 								t.Fatal(err)
@@ -750,10 +963,12 @@ func BenchmarkAggregator(b *testing.B) {
 											testAllocator,
 											source,
 											colTypes,
-											[]execinfrapb.AggregatorSpec_Func{aggFn},
-											[]uint32{0},
-											[][]uint32{[]uint32{1}[:nCols]},
-											false, /* isScalar */
+											makeAggregatorSpec(
+												[]execinfrapb.AggregatorSpec_Func{aggFn},
+												[]uint32{0},
+												[][]uint32{[]uint32{1}[:nCols]},
+											),
+											nil, /* constArguments */
 										)
 										if err != nil {
 											b.Skip()
@@ -895,7 +1110,7 @@ func TestHashAggregator(t *testing.T) {
 			t.Fatal(err)
 		}
 		runTests(t, []tuples{tc.input}, tc.expected, unorderedVerifier, func(sources []Operator) (Operator, error) {
-			return NewHashAggregator(testAllocator, sources[0], tc.colTypes, tc.aggFns, tc.groupCols, tc.aggCols, false /* isScalar */)
+			return NewHashAggregator(testAllocator, sources[0], tc.colTypes, tc.spec(), tc.constArguments)
 		})
 	}
 }
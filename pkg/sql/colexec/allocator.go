@@ -151,6 +151,19 @@ func (a *Allocator) PerformOperation(destVecs []coldata.Vec, operation func()) {
 	}
 }
 
+// AdjustMemoryUsage adjusts the number of bytes currently allocated through
+// this allocator by delta bytes (which can be both positive or negative). It
+// is used to account for memory that is not held in columnar vectors.
+func (a *Allocator) AdjustMemoryUsage(delta int64) {
+	if delta > 0 {
+		if err := a.acc.Grow(a.ctx, delta); err != nil {
+			execerror.VectorizedInternalPanic(err)
+		}
+	} else {
+		a.acc.Shrink(a.ctx, -delta)
+	}
+}
+
 // Used returns the number of bytes currently allocated through this allocator.
 func (a *Allocator) Used() int64 {
 	return a.acc.Used()
@@ -127,7 +127,7 @@ func newColBatchScan(
 	fetcher := cFetcher{}
 	if _, _, err := initCRowFetcher(
 		allocator, &fetcher, &spec.Table, int(spec.IndexIdx), columnIdxMap, spec.Reverse,
-		neededColumns, spec.IsCheck, spec.Visibility, spec.LockingStrength,
+		neededColumns, spec.IsCheck, spec.Visibility, spec.LockingStrength, spec.LockingWaitPolicy,
 	); err != nil {
 		return nil, err
 	}
@@ -158,6 +158,7 @@ func initCRowFetcher(
 	isCheck bool,
 	scanVisibility execinfrapb.ScanVisibility,
 	lockStr sqlbase.ScanLockingStrength,
+	lockWaitPolicy sqlbase.ScanLockingWaitPolicy,
 ) (index *sqlbase.IndexDescriptor, isSecondaryIndex bool, err error) {
 	immutDesc := sqlbase.NewImmutableTableDescriptor(*desc)
 	index, isSecondaryIndex, err = immutDesc.FindIndexByIndexIdx(indexIdx)
@@ -178,7 +179,7 @@ func initCRowFetcher(
 		ValNeededForCol:  valNeededForCol,
 	}
 	if err := fetcher.Init(
-		allocator, reverseScan, lockStr, true /* returnRangeInfo */, isCheck, tableArgs,
+		allocator, reverseScan, lockStr, lockWaitPolicy, true /* returnRangeInfo */, isCheck, tableArgs,
 	); err != nil {
 		return nil, false, err
 	}
@@ -0,0 +1,405 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/colcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/errors"
+)
+
+// crossJoinerState indicates the current state of the cross joiner.
+type crossJoinerState int
+
+const (
+	// crossJoinerBuilding indicates that the cross joiner is consuming the right
+	// input and is buffering all of its tuples.
+	crossJoinerBuilding crossJoinerState = iota
+	// crossJoinerEmittingLeft indicates that the cross joiner is emitting the
+	// tuples from the left input as they are (in case of LEFT SEMI and LEFT
+	// ANTI joins) or with NULL values on the right side (in case of LEFT and
+	// FULL OUTER joins when the right input is empty).
+	crossJoinerEmittingLeft
+	// crossJoinerEmittingRight indicates that the cross joiner is emitting the
+	// buffered tuples from the right input with NULL values on the left side
+	// (in case of RIGHT and FULL OUTER joins when the left input is empty).
+	crossJoinerEmittingRight
+	// crossJoinerEmittingProduct indicates that the cross joiner is emitting
+	// the cartesian product of the left and right inputs.
+	crossJoinerEmittingProduct
+	// crossJoinerDone indicates that the cross joiner has emitted all tuples.
+	crossJoinerDone
+)
+
+// crossJoiner is an operator that computes the cartesian product of its two
+// inputs (i.e. it performs a join without equality columns). The right input
+// is fully buffered in a spilling queue (which spills to disk once its memory
+// limit is reached), and then for every batch from the left input the whole
+// buffered right input is read and the product of the left batch with every
+// right batch is emitted.
+//
+// All join types are supported, but ON expressions are not: in case of INNER
+// join, the ON expression can be evaluated by a filter planned on top of the
+// cross joiner.
+type crossJoiner struct {
+	twoInputNode
+
+	allocator *Allocator
+	joinType  sqlbase.JoinType
+	leftTypes []coltypes.T
+	// rightTypes are the types of the right input.
+	rightTypes []coltypes.T
+	state      crossJoinerState
+
+	// rightTuples buffers all tuples from the right input.
+	rightTuples *spillingQueue
+	// numRightTuples is the total number of buffered tuples from the right
+	// input.
+	numRightTuples uint64
+
+	// leftBatch is the current batch from the left input.
+	leftBatch coldata.Batch
+	// rightBatch is the current batch from the buffered right input.
+	rightBatch coldata.Batch
+	// leftIdx and rightIdx are the indices of the tuples in leftBatch and
+	// rightBatch, respectively, from which the emission should be resumed.
+	leftIdx, rightIdx uint16
+
+	output          coldata.Batch
+	outputBatchSize uint16
+	// leftSel and rightSel are the scratch selection vectors used to
+	// populate the output batch in crossJoinerEmittingProduct state.
+	leftSel, rightSel []uint16
+}
+
+var _ Operator = &crossJoiner{}
+
+// newCrossJoiner returns a new cross joiner. unlimitedAllocator is used for
+// buffering the right input, and if it reports that memoryLimit is exceeded,
+// the tuples are spilled to disk using diskQueueCfg.
+func newCrossJoiner(
+	allocator *Allocator,
+	unlimitedAllocator *Allocator,
+	joinType sqlbase.JoinType,
+	left, right Operator,
+	leftTypes, rightTypes []coltypes.T,
+	memoryLimit int64,
+	diskQueueCfg colcontainer.DiskQueueCfg,
+) (Operator, error) {
+	switch joinType {
+	case sqlbase.JoinType_INNER, sqlbase.JoinType_LEFT_OUTER, sqlbase.JoinType_RIGHT_OUTER,
+		sqlbase.JoinType_FULL_OUTER, sqlbase.JoinType_LEFT_SEMI, sqlbase.JoinType_LEFT_ANTI:
+	default:
+		return nil, errors.Errorf("cross join of type %s not supported", joinType)
+	}
+	return &crossJoiner{
+		twoInputNode: newTwoInputNode(left, right),
+		allocator:    allocator,
+		joinType:     joinType,
+		leftTypes:    leftTypes,
+		rightTypes:   rightTypes,
+		rightTuples: newRewindableSpillingQueue(
+			unlimitedAllocator, rightTypes, memoryLimit, diskQueueCfg, int(coldata.BatchSize()),
+		),
+		outputBatchSize: coldata.BatchSize(),
+	}, nil
+}
+
+func (c *crossJoiner) Init() {
+	c.inputOne.Init()
+	c.inputTwo.Init()
+	c.state = crossJoinerBuilding
+}
+
+// outputsRightColumns returns whether the output of the cross joiner contains
+// the columns from the right input.
+func (c *crossJoiner) outputsRightColumns() bool {
+	return c.joinType != sqlbase.JoinType_LEFT_SEMI && c.joinType != sqlbase.JoinType_LEFT_ANTI
+}
+
+func (c *crossJoiner) Next(ctx context.Context) coldata.Batch {
+	for {
+		switch c.state {
+		case crossJoinerBuilding:
+			c.build(ctx)
+			if c.outputsRightColumns() {
+				outputTypes := append(append([]coltypes.T{}, c.leftTypes...), c.rightTypes...)
+				c.output = c.allocator.NewMemBatch(outputTypes)
+				c.leftSel = make([]uint16, coldata.BatchSize())
+				c.rightSel = make([]uint16, coldata.BatchSize())
+			}
+			c.state = c.stateAfterBuilding(ctx)
+
+		case crossJoinerEmittingLeft:
+			batch := c.inputOne.Next(ctx)
+			if batch.Length() == 0 {
+				c.finish()
+				continue
+			}
+			if !c.outputsRightColumns() {
+				// The tuples from the left are emitted as they are.
+				return batch
+			}
+			return c.emitLeftWithNulls(batch)
+
+		case crossJoinerEmittingRight:
+			batch, err := c.rightTuples.dequeue()
+			if err != nil {
+				execerror.VectorizedInternalPanic(err)
+			}
+			if batch.Length() == 0 {
+				c.finish()
+				continue
+			}
+			return c.emitRightWithNulls(batch)
+
+		case crossJoinerEmittingProduct:
+			if c.leftBatch == nil {
+				c.leftBatch = c.inputOne.Next(ctx)
+				if c.leftBatch.Length() == 0 {
+					c.finish()
+					continue
+				}
+				c.leftIdx = 0
+			}
+			if c.rightBatch == nil {
+				var err error
+				c.rightBatch, err = c.rightTuples.dequeue()
+				if err != nil {
+					execerror.VectorizedInternalPanic(err)
+				}
+				if c.rightBatch.Length() == 0 {
+					// We have computed the product of the current left batch with all
+					// right tuples, so we need to rewind the right tuples and move on
+					// to the next left batch.
+					if err := c.rightTuples.rewind(); err != nil {
+						execerror.VectorizedInternalPanic(err)
+					}
+					c.leftBatch, c.rightBatch = nil, nil
+					continue
+				}
+				c.leftIdx, c.rightIdx = 0, 0
+			}
+			if c.emitProduct() {
+				// We have computed the product of the current left batch with the
+				// current right batch.
+				c.rightBatch = nil
+			}
+			if c.output.Length() > 0 {
+				return c.output
+			}
+
+		case crossJoinerDone:
+			return coldata.ZeroBatch
+
+		default:
+			execerror.VectorizedInternalPanic(fmt.Sprintf("unexpected crossJoinerState %d", c.state))
+			// This code is unreachable, but the compiler cannot infer that.
+			return nil
+		}
+	}
+}
+
+// build buffers all tuples from the right input.
+func (c *crossJoiner) build(ctx context.Context) {
+	for {
+		batch := c.inputTwo.Next(ctx)
+		n := batch.Length()
+		if n == 0 {
+			return
+		}
+		if !c.outputsRightColumns() {
+			// In case of LEFT SEMI and LEFT ANTI joins we only need to know
+			// whether the right input is empty.
+			c.numRightTuples += uint64(n)
+			return
+		}
+		// The batches that are enqueued into the spilling queue might be kept
+		// by reference, so we need to copy the tuples.
+		allocator := c.rightTuples.unlimitedAllocator
+		b := allocator.NewMemBatchWithSize(c.rightTypes, int(n))
+		allocator.PerformOperation(b.ColVecs(), func() {
+			for colIdx, t := range c.rightTypes {
+				b.ColVec(colIdx).Copy(coldata.CopySliceArgs{
+					SliceArgs: coldata.SliceArgs{
+						ColType:   t,
+						Src:       batch.ColVec(colIdx),
+						Sel:       batch.Selection(),
+						SrcEndIdx: uint64(n),
+					},
+				})
+			}
+			b.SetLength(n)
+		})
+		if err := c.rightTuples.enqueue(b); err != nil {
+			execerror.VectorizedInternalPanic(err)
+		}
+		c.numRightTuples += uint64(n)
+	}
+}
+
+// stateAfterBuilding returns the state that the cross joiner should transition
+// to once the right input has been fully consumed.
+func (c *crossJoiner) stateAfterBuilding(ctx context.Context) crossJoinerState {
+	rightEmpty := c.numRightTuples == 0
+	switch c.joinType {
+	case sqlbase.JoinType_LEFT_SEMI:
+		if rightEmpty {
+			return crossJoinerDone
+		}
+		return crossJoinerEmittingLeft
+	case sqlbase.JoinType_LEFT_ANTI:
+		if rightEmpty {
+			return crossJoinerEmittingLeft
+		}
+		return crossJoinerDone
+	}
+	if rightEmpty {
+		switch c.joinType {
+		case sqlbase.JoinType_LEFT_OUTER, sqlbase.JoinType_FULL_OUTER:
+			return crossJoinerEmittingLeft
+		default:
+			return crossJoinerDone
+		}
+	}
+	// The right input is not empty, so we need to check whether the left input
+	// is empty.
+	c.leftBatch = c.inputOne.Next(ctx)
+	if c.leftBatch.Length() == 0 {
+		c.leftBatch = nil
+		switch c.joinType {
+		case sqlbase.JoinType_RIGHT_OUTER, sqlbase.JoinType_FULL_OUTER:
+			return crossJoinerEmittingRight
+		default:
+			return crossJoinerDone
+		}
+	}
+	c.leftIdx = 0
+	return crossJoinerEmittingProduct
+}
+
+// emitProduct populates the output batch with the product of the current left
+// and right batches starting from the tuples at leftIdx and rightIdx. It
+// returns whether the product has been fully emitted. The left tuples are
+// used in the outer loop so that every left tuple is repeated for all of the
+// right tuples.
+func (c *crossJoiner) emitProduct() bool {
+	leftLen, rightLen := c.leftBatch.Length(), c.rightBatch.Length()
+	leftSel := c.leftBatch.Selection()
+	nResults := uint16(0)
+	for nResults < c.outputBatchSize && c.leftIdx < leftLen {
+		leftRowIdx := c.leftIdx
+		if leftSel != nil {
+			leftRowIdx = leftSel[c.leftIdx]
+		}
+		for ; nResults < c.outputBatchSize && c.rightIdx < rightLen; c.rightIdx++ {
+			c.leftSel[nResults] = leftRowIdx
+			// The buffered right batches don't have a selection vector.
+			c.rightSel[nResults] = c.rightIdx
+			nResults++
+		}
+		if c.rightIdx == rightLen {
+			c.leftIdx++
+			c.rightIdx = 0
+		}
+	}
+	c.output.ResetInternalBatch()
+	c.allocator.PerformOperation(c.output.ColVecs(), func() {
+		for colIdx, t := range c.leftTypes {
+			c.output.ColVec(colIdx).Copy(coldata.CopySliceArgs{
+				SliceArgs: coldata.SliceArgs{
+					ColType:   t,
+					Src:       c.leftBatch.ColVec(colIdx),
+					Sel:       c.leftSel,
+					SrcEndIdx: uint64(nResults),
+				},
+			})
+		}
+		colOffset := len(c.leftTypes)
+		for colIdx, t := range c.rightTypes {
+			c.output.ColVec(colOffset + colIdx).Copy(coldata.CopySliceArgs{
+				SliceArgs: coldata.SliceArgs{
+					ColType:   t,
+					Src:       c.rightBatch.ColVec(colIdx),
+					Sel:       c.rightSel,
+					SrcEndIdx: uint64(nResults),
+				},
+			})
+		}
+		c.output.SetLength(nResults)
+	})
+	return c.leftIdx == leftLen
+}
+
+// emitLeftWithNulls returns the output batch containing all tuples from batch
+// (which comes from the left input) with NULL values on the right side.
+func (c *crossJoiner) emitLeftWithNulls(batch coldata.Batch) coldata.Batch {
+	n := batch.Length()
+	c.output.ResetInternalBatch()
+	c.allocator.PerformOperation(c.output.ColVecs(), func() {
+		for colIdx, t := range c.leftTypes {
+			c.output.ColVec(colIdx).Copy(coldata.CopySliceArgs{
+				SliceArgs: coldata.SliceArgs{
+					ColType:   t,
+					Src:       batch.ColVec(colIdx),
+					Sel:       batch.Selection(),
+					SrcEndIdx: uint64(n),
+				},
+			})
+		}
+		colOffset := len(c.leftTypes)
+		for colIdx := range c.rightTypes {
+			c.output.ColVec(colOffset + colIdx).Nulls().SetNulls()
+		}
+		c.output.SetLength(n)
+	})
+	return c.output
+}
+
+// emitRightWithNulls returns the output batch containing all tuples from batch
+// (which comes from the buffered right input) with NULL values on the left
+// side.
+func (c *crossJoiner) emitRightWithNulls(batch coldata.Batch) coldata.Batch {
+	n := batch.Length()
+	c.output.ResetInternalBatch()
+	c.allocator.PerformOperation(c.output.ColVecs(), func() {
+		for colIdx := range c.leftTypes {
+			c.output.ColVec(colIdx).Nulls().SetNulls()
+		}
+		colOffset := len(c.leftTypes)
+		for colIdx, t := range c.rightTypes {
+			c.output.ColVec(colOffset + colIdx).Copy(coldata.CopySliceArgs{
+				SliceArgs: coldata.SliceArgs{
+					ColType:   t,
+					Src:       batch.ColVec(colIdx),
+					SrcEndIdx: uint64(n),
+				},
+			})
+		}
+		c.output.SetLength(n)
+	})
+	return c.output
+}
+
+// finish releases the disk resources of the cross joiner and transitions it
+// to the done state.
+func (c *crossJoiner) finish() {
+	if err := c.rightTuples.close(); err != nil {
+		execerror.VectorizedInternalPanic(err)
+	}
+	c.state = crossJoinerDone
+}
@@ -0,0 +1,144 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/testutils/colcontainerutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+type crossJoinTestCase struct {
+	joinType    sqlbase.JoinType
+	leftTuples  tuples
+	rightTuples tuples
+	expected    tuples
+}
+
+func getCrossJoinTestCases() []crossJoinTestCase {
+	// manyRightTuples is used to make sure that the right side is split into
+	// multiple batches.
+	var manyRightTuples, manyExpected tuples
+	for i := 0; i < 50; i++ {
+		manyRightTuples = append(manyRightTuples, tuple{i})
+	}
+	for _, l := range []int{0, 1} {
+		for i := 0; i < 50; i++ {
+			manyExpected = append(manyExpected, tuple{l, i})
+		}
+	}
+	return []crossJoinTestCase{
+		{
+			joinType:    sqlbase.JoinType_INNER,
+			leftTuples:  tuples{{0}, {1}},
+			rightTuples: tuples{{2}, {nil}, {4}},
+			expected:    tuples{{0, 2}, {0, nil}, {0, 4}, {1, 2}, {1, nil}, {1, 4}},
+		},
+		{
+			joinType:    sqlbase.JoinType_INNER,
+			leftTuples:  tuples{{0}, {1}},
+			rightTuples: manyRightTuples,
+			expected:    manyExpected,
+		},
+		{
+			joinType:    sqlbase.JoinType_INNER,
+			leftTuples:  tuples{{0}, {1}},
+			rightTuples: tuples{},
+			expected:    tuples{},
+		},
+		{
+			joinType:    sqlbase.JoinType_LEFT_OUTER,
+			leftTuples:  tuples{{0}, {1}},
+			rightTuples: tuples{{2}, {3}},
+			expected:    tuples{{0, 2}, {0, 3}, {1, 2}, {1, 3}},
+		},
+		{
+			joinType:    sqlbase.JoinType_LEFT_OUTER,
+			leftTuples:  tuples{{0}, {nil}},
+			rightTuples: tuples{},
+			expected:    tuples{{0, nil}, {nil, nil}},
+		},
+		{
+			joinType:    sqlbase.JoinType_RIGHT_OUTER,
+			leftTuples:  tuples{},
+			rightTuples: tuples{{2}, {3}},
+			expected:    tuples{{nil, 2}, {nil, 3}},
+		},
+		{
+			joinType:    sqlbase.JoinType_FULL_OUTER,
+			leftTuples:  tuples{{0}, {1}},
+			rightTuples: tuples{},
+			expected:    tuples{{0, nil}, {1, nil}},
+		},
+		{
+			joinType:    sqlbase.JoinType_FULL_OUTER,
+			leftTuples:  tuples{},
+			rightTuples: tuples{{2}, {3}},
+			expected:    tuples{{nil, 2}, {nil, 3}},
+		},
+		{
+			joinType:    sqlbase.JoinType_LEFT_SEMI,
+			leftTuples:  tuples{{0}, {1}},
+			rightTuples: tuples{{2}, {3}},
+			expected:    tuples{{0}, {1}},
+		},
+		{
+			joinType:    sqlbase.JoinType_LEFT_SEMI,
+			leftTuples:  tuples{{0}, {1}},
+			rightTuples: tuples{},
+			expected:    tuples{},
+		},
+		{
+			joinType:    sqlbase.JoinType_LEFT_ANTI,
+			leftTuples:  tuples{{0}, {1}},
+			rightTuples: tuples{{2}, {3}},
+			expected:    tuples{},
+		},
+		{
+			joinType:    sqlbase.JoinType_LEFT_ANTI,
+			leftTuples:  tuples{{0}, {1}},
+			rightTuples: tuples{},
+			expected:    tuples{{0}, {1}},
+		},
+	}
+}
+
+func TestCrossJoiner(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	queueCfg, cleanup := colcontainerutils.NewTestingDiskQueueCfg(t, true /* inMem */)
+	defer cleanup()
+
+	typs := []coltypes.T{coltypes.Int64}
+	// A memory limit of 1 byte forces the right side to be spilled to disk.
+	for _, memoryLimit := range []int64{1, 64 << 20 /* 64 MiB */} {
+		for _, tc := range getCrossJoinTestCases() {
+			t.Run(fmt.Sprintf("%s/memoryLimit=%d", tc.joinType, memoryLimit), func(t *testing.T) {
+				// The cross joiner doesn't look at the values of the tuples, so
+				// the all nulls injection test is omitted.
+				runTestsWithoutAllNullsInjection(
+					t, []tuples{tc.leftTuples, tc.rightTuples}, [][]coltypes.T{typs, typs},
+					tc.expected, orderedVerifier,
+					func(inputs []Operator) (Operator, error) {
+						return newCrossJoiner(
+							testAllocator, testAllocator, tc.joinType, inputs[0], inputs[1],
+							typs, typs, memoryLimit, queueCfg,
+						)
+					},
+				)
+			})
+		}
+	}
+}
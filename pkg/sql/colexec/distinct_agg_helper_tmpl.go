@@ -0,0 +1,189 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// {{/*
+// +build execgen_template
+//
+// This file is the execgen template for distinct_agg_helper.eg.go. It's
+// formatted in a special way, so it's both valid Go and a valid text/template
+// input. This permits editing this file with editor support.
+//
+// */}}
+
+package colexec
+
+import (
+	"math"
+	"unsafe"
+
+	"github.com/cockroachdb/apd"
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execgen"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/pkg/errors"
+)
+
+// aggDistinctHelper keeps track of the values of the argument of a DISTINCT
+// aggregate function that have already been seen in the current group.
+type aggDistinctHelper interface {
+	// removeDuplicates marks as NULL every non-NULL value among the first n
+	// tuples of vec (according to sel) that has already been seen in its group.
+	// groups[i] is true if the ith tuple is the first one of a new group.
+	removeDuplicates(vec coldata.Vec, sel []uint16, n uint16, groups []bool)
+	// reset forgets all of the values seen so far.
+	reset()
+}
+
+func newAggDistinctHelper(allocator *Allocator, t coltypes.T) (aggDistinctHelper, error) {
+	switch t {
+	// {{range .}}
+	case _TYPES_T:
+		return &aggDistinct_TYPEHelper{allocator: allocator, seen: make(map[_KEYTYPE]struct{})}, nil
+		// {{end}}
+	default:
+		return nil, errors.Errorf("unsupported distinct aggregate type %s", t)
+	}
+}
+
+// {{/*
+
+// Declarations to make the template compile properly.
+
+// Dummy import to pull in "apd" package.
+var _ apd.Decimal
+
+// Dummy import to pull in "duration" package.
+var _ duration.Duration
+
+// Dummy import to pull in "math" package.
+var _ = math.NaN
+
+// Dummy import to pull in "unsafe" package.
+var _ = unsafe.Sizeof
+
+// _KEYTYPE is the template type variable for the type of the keys in the set
+// of seen values. It will be replaced by the Go type that has the same
+// equality semantics as the SQL equality for each type in coltypes.T.
+type _KEYTYPE interface{}
+
+// _TYPES_T is the template type variable for coltypes.T. It will be replaced by
+// coltypes.Foo for each type Foo in the coltypes.T type.
+const _TYPES_T = coltypes.Unhandled
+
+// */}}
+
+// Use execgen package to remove unused import warning.
+var _ interface{} = execgen.UNSAFEGET
+
+// {{range .}}
+
+// aggDistinct_TYPEHelper is the aggDistinctHelper for _TYPES_T type.
+type aggDistinct_TYPEHelper struct {
+	allocator *Allocator
+	seen      map[_KEYTYPE]struct{}
+	// maxSeen is the largest number of entries seen has ever had. The memory of
+	// the map is accounted for based on it because deleting the entries on reset
+	// doesn't shrink the map.
+	maxSeen int
+	// keyBytes is the number of bytes referenced by the keys currently in seen
+	// that are not a part of the map itself (the contents of the strings).
+	keyBytes int64
+}
+
+var _ aggDistinctHelper = &aggDistinct_TYPEHelper{}
+
+func (h *aggDistinct_TYPEHelper) removeDuplicates(
+	vec coldata.Vec, sel []uint16, n uint16, groups []bool,
+) {
+	col, nulls := vec._TemplateType(), vec.Nulls()
+	if sel != nil {
+		for _, i := range sel[:n] {
+			_REMOVE_DUPLICATE(h, col, nulls, i, groups)
+		}
+	} else {
+		for i := uint16(0); i < n; i++ {
+			_REMOVE_DUPLICATE(h, col, nulls, i, groups)
+		}
+	}
+}
+
+func (h *aggDistinct_TYPEHelper) reset() {
+	for k := range h.seen {
+		delete(h.seen, k)
+	}
+	h.allocator.AdjustMemoryUsage(-h.keyBytes)
+	h.keyBytes = 0
+}
+
+// {{end}}
+
+// {{/*
+// _REMOVE_DUPLICATE marks the ith value as NULL if it has already been seen in
+// the current group and remembers it otherwise.
+func _REMOVE_DUPLICATE(
+	h *aggDistinct_TYPEHelper, col interface{}, nulls *coldata.Nulls, i uint16, groups []bool,
+) { // */}}
+	// {{define "removeDuplicate" -}}
+
+	if groups[i] {
+		// A new group starts with this tuple, so all of the values seen so far
+		// belong to the previous groups.
+		h.reset()
+	}
+	if !nulls.NullAt(i) {
+		v := execgen.UNSAFEGET(col, int(i))
+		var key _KEYTYPE
+		// {{ if eq .LTyp.String "Bytes" }}
+		// Note that the conversion makes a copy of the value which is necessary
+		// because col might be reused on the next batches.
+		key = string(v)
+		// {{ else if eq .LTyp.String "Decimal" }}
+		// Decimals that differ only in the number of trailing zeroes (or in the
+		// sign of zero) are equal.
+		var reduced apd.Decimal
+		reduced.Reduce(&v)
+		if reduced.IsZero() {
+			reduced.Negative = false
+		}
+		key = reduced.String()
+		// {{ else if eq .LTyp.String "Float64" }}
+		// All NaNs are equal to each other and -0 is equal to 0.
+		if math.IsNaN(v) {
+			v = math.NaN()
+		} else if v == 0 {
+			v = 0
+		}
+		key = math.Float64bits(v)
+		// {{ else if eq .LTyp.String "Timestamp" }}
+		// time.Time values representing the same instant might differ in the
+		// location, so we use the instant itself as the key.
+		key = [2]int64{v.Unix(), int64(v.Nanosecond())}
+		// {{ else }}
+		key = v
+		// {{ end }}
+		if _, seen := h.seen[key]; seen {
+			nulls.SetNull(i)
+		} else {
+			h.seen[key] = struct{}{}
+			// {{ if or (eq .LTyp.String "Bytes") (eq .LTyp.String "Decimal") }}
+			h.keyBytes += int64(len(key))
+			h.allocator.AdjustMemoryUsage(int64(len(key)))
+			// {{ end }}
+			if len(h.seen) > h.maxSeen {
+				h.maxSeen = len(h.seen)
+				h.allocator.AdjustMemoryUsage(int64(unsafe.Sizeof(key)))
+			}
+		}
+	}
+	// {{end}}
+
+	// {{/*
+} // */}}
@@ -0,0 +1,69 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package main
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+	"text/template"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// distinctAggKeyType returns the name of the Go type that is used as the key
+// in the set of seen values of type t by the DISTINCT aggregates.
+func distinctAggKeyType(t coltypes.T) string {
+	switch t {
+	case coltypes.Bytes, coltypes.Decimal:
+		return "string"
+	case coltypes.Float64:
+		return "uint64"
+	case coltypes.Timestamp:
+		return "[2]int64"
+	default:
+		return t.GoTypeName()
+	}
+}
+
+func genDistinctAggHelper(wr io.Writer) error {
+	t, err := ioutil.ReadFile("pkg/sql/colexec/distinct_agg_helper_tmpl.go")
+	if err != nil {
+		return err
+	}
+
+	s := string(t)
+
+	s = strings.Replace(s, "_KEYTYPE", "{{distinctAggKeyType .LTyp}}", -1)
+	s = strings.Replace(s, "_TYPES_T", "coltypes.{{.LTyp}}", -1)
+	s = strings.Replace(s, "_TYPE", "{{.LTyp}}", -1)
+	s = strings.Replace(s, "_TemplateType", "{{.LTyp}}", -1)
+
+	removeDuplicate := makeFunctionRegex("_REMOVE_DUPLICATE", 5)
+	s = removeDuplicate.ReplaceAllString(s, `{{template "removeDuplicate" buildDict "Global" . "LTyp" .LTyp}}`)
+
+	s = replaceManipulationFuncs(".LTyp", s)
+
+	tmpl, err := template.New("distinct_agg_helper").Funcs(template.FuncMap{
+		"buildDict":          buildDict,
+		"distinctAggKeyType": distinctAggKeyType,
+	}).Parse(s)
+	if err != nil {
+		return err
+	}
+
+	return tmpl.Execute(wr, sameTypeComparisonOpToOverloads[tree.EQ])
+}
+
+func init() {
+	registerGenerator(genDistinctAggHelper, "distinct_agg_helper.eg.go")
+}
@@ -113,6 +113,9 @@ type NewColOperatorArgs struct {
 		// MaxNumberPartitions determines the maximum number of "active"
 		// partitions for Partitioner interface.
 		MaxNumberPartitions int
+		// LookupJoinMemoryLimit, if positive, overrides the limit on the memory
+		// used by the looked up tuples of a single lookup of the lookup join.
+		LookupJoinMemoryLimit int64
 	}
 }
 
@@ -144,16 +147,36 @@ func isSupported(spec *execinfrapb.ProcessorSpec) (bool, error) {
 		}
 		return true, nil
 
+	case core.JoinReader != nil:
+		if core.JoinReader.IndexIdx != 0 && len(core.JoinReader.LookupColumns) == 0 {
+			return false, errors.Newf("index join against a secondary index is not supported")
+		}
+		if !core.JoinReader.OnExpr.Empty() {
+			return false, errors.Newf("lookup join with ON expression is not supported")
+		}
+		switch core.JoinReader.Type {
+		case sqlbase.JoinType_INNER, sqlbase.JoinType_LEFT_OUTER,
+			sqlbase.JoinType_LEFT_SEMI, sqlbase.JoinType_LEFT_ANTI:
+		default:
+			return false, errors.Newf("lookup join of type %s is not supported", core.JoinReader.Type)
+		}
+		return true, nil
+
 	case core.Aggregator != nil:
 		aggSpec := core.Aggregator
 		for _, agg := range aggSpec.Aggregations {
-			if agg.Distinct {
-				return false, errors.Newf("distinct aggregation not supported")
+			if agg.Distinct && len(agg.ColIdx) != 1 {
+				return false, errors.Newf("distinct aggregation is only supported with a single argument")
 			}
 			if agg.FilterColIdx != nil {
-				return false, errors.Newf("filtering aggregation not supported")
+				if len(agg.ColIdx) > 1 {
+					return false, errors.Newf("filtering aggregation is only supported with at most one argument")
+				}
+				if spec.Input[0].ColumnTypes[*agg.FilterColIdx].Family() != types.BoolFamily {
+					return false, errors.Newf("filtering aggregation is only supported with boolean filter column")
+				}
 			}
-			if len(agg.Arguments) > 0 {
+			if len(agg.Arguments) > 0 && agg.Func != execinfrapb.AggregatorSpec_STRING_AGG {
 				return false, errors.Newf("aggregates with arguments not supported")
 			}
 			var inputTypes []types.T
@@ -173,18 +196,23 @@ func isSupported(spec *execinfrapb.ProcessorSpec) (bool, error) {
 		return true, nil
 
 	case core.HashJoiner != nil:
-		if !core.HashJoiner.OnExpr.Empty() &&
-			core.HashJoiner.Type != sqlbase.JoinType_INNER {
-			return false, errors.Newf("can't plan non-inner hash join with on expressions")
+		if !core.HashJoiner.OnExpr.Empty() {
+			switch core.HashJoiner.Type {
+			case sqlbase.JoinType_INNER, sqlbase.JoinType_LEFT_OUTER,
+				sqlbase.JoinType_RIGHT_OUTER, sqlbase.JoinType_FULL_OUTER:
+			default:
+				return false, errors.Newf("can't plan %s hash join with on expressions", core.HashJoiner.Type)
+			}
 		}
 		return true, nil
 
 	case core.MergeJoiner != nil:
 		if !core.MergeJoiner.OnExpr.Empty() {
 			switch core.MergeJoiner.Type {
-			case sqlbase.JoinType_INNER, sqlbase.JoinType_LEFT_SEMI, sqlbase.JoinType_LEFT_ANTI:
+			case sqlbase.JoinType_INNER, sqlbase.JoinType_LEFT_SEMI, sqlbase.JoinType_LEFT_ANTI,
+				sqlbase.JoinType_LEFT_OUTER, sqlbase.JoinType_RIGHT_OUTER, sqlbase.JoinType_FULL_OUTER:
 			default:
-				return false, errors.Errorf("can only plan INNER, LEFT SEMI, and LEFT ANTI merge joins with ON expressions")
+				return false, errors.Errorf("can't plan %s merge join with ON expressions", core.MergeJoiner.Type)
 			}
 		}
 		return true, nil
@@ -193,26 +221,12 @@ func isSupported(spec *execinfrapb.ProcessorSpec) (bool, error) {
 		return true, nil
 
 	case core.Windower != nil:
-		if len(core.Windower.WindowFns) != 1 {
-			return false, errors.Newf("only a single window function is currently supported")
-		}
-		wf := core.Windower.WindowFns[0]
-		if wf.Frame != nil &&
-			(wf.Frame.Mode != execinfrapb.WindowerSpec_Frame_RANGE ||
-				wf.Frame.Bounds.Start.BoundType != execinfrapb.WindowerSpec_Frame_UNBOUNDED_PRECEDING ||
-				(wf.Frame.Bounds.End != nil && wf.Frame.Bounds.End.BoundType != execinfrapb.WindowerSpec_Frame_CURRENT_ROW)) {
-			return false, errors.Newf("window functions with non-default window frames are not supported")
-		}
-		if wf.Func.AggregateFunc != nil {
-			return false, errors.Newf("aggregate functions used as window functions are not supported")
-		}
-
-		switch *wf.Func.WindowFunc {
-		case execinfrapb.WindowerSpec_ROW_NUMBER:
-		case execinfrapb.WindowerSpec_RANK:
-		case execinfrapb.WindowerSpec_DENSE_RANK:
-		default:
-			return false, errors.Newf("window function %s is not supported", wf.String())
+		for i := range core.Windower.WindowFns {
+			if err := checkWindowFnSupported(
+				&core.Windower.WindowFns[i], spec.Input[0].ColumnTypes,
+			); err != nil {
+				return false, err
+			}
 		}
 		return true, nil
 
@@ -366,6 +380,61 @@ func NewColOperator(
 			result.Op = NewCancelChecker(result.Op)
 			returnMutations := core.TableReader.Visibility == execinfrapb.ScanVisibility_PUBLIC_AND_NOT_PUBLIC
 			result.ColumnTypes = core.TableReader.Table.ColumnTypesWithMutations(returnMutations)
+		case core.JoinReader != nil:
+			if err := checkNumIn(inputs, 1); err != nil {
+				return result, err
+			}
+			joinReaderSpec := core.JoinReader
+			returnMutations := joinReaderSpec.Visibility == execinfrapb.ScanVisibility_PUBLIC_AND_NOT_PUBLIC
+			tableTypes := joinReaderSpec.Table.ColumnTypesWithMutations(returnMutations)
+			if len(joinReaderSpec.LookupColumns) == 0 {
+				var indexJoinOp *colIndexJoin
+				indexJoinOp, err = newColIndexJoin(
+					NewAllocator(ctx, streamingMemAccount), flowCtx, inputs[0],
+					spec.Input[0].ColumnTypes, joinReaderSpec, post,
+				)
+				if err != nil {
+					return result, err
+				}
+				result.Op, result.IsStreaming = indexJoinOp, true
+				result.MetadataSources = append(result.MetadataSources, indexJoinOp)
+				result.ColumnTypes = tableTypes
+			} else {
+				// The lookup join buffers the looked up tuples for a single input
+				// batch at a time.
+				lookupJoinMemMonitorName := fmt.Sprintf("lookup-join-%d", spec.ProcessorID)
+				var lookupJoinMemAccount *mon.BoundAccount
+				if useStreamingMemAccountForBuffering {
+					lookupJoinMemAccount = streamingMemAccount
+				} else {
+					lookupJoinMemAccount = result.createBufferingMemAccount(
+						ctx, flowCtx, lookupJoinMemMonitorName,
+					)
+				}
+				// Half of the memory limit is left for the input and the output
+				// batches as well as for the batches of the fetcher.
+				lookupJoinMemoryLimit := execinfra.GetWorkMemLimit(flowCtx.Cfg) / 2
+				if args.TestingKnobs.LookupJoinMemoryLimit > 0 {
+					lookupJoinMemoryLimit = args.TestingKnobs.LookupJoinMemoryLimit
+				}
+				var lookupJoinOp *colLookupJoin
+				lookupJoinOp, err = newColLookupJoin(
+					NewAllocator(ctx, lookupJoinMemAccount), flowCtx, inputs[0],
+					spec.Input[0].ColumnTypes, joinReaderSpec, post, lookupJoinMemoryLimit,
+				)
+				if err != nil {
+					return result, err
+				}
+				result.Op = lookupJoinOp
+				result.MetadataSources = append(result.MetadataSources, lookupJoinOp)
+				result.ColumnTypes = spec.Input[0].ColumnTypes
+				switch joinReaderSpec.Type {
+				case sqlbase.JoinType_LEFT_SEMI, sqlbase.JoinType_LEFT_ANTI:
+				default:
+					result.ColumnTypes = append(result.ColumnTypes[:len(result.ColumnTypes):len(result.ColumnTypes)], tableTypes...)
+				}
+			}
+
 		case core.Aggregator != nil:
 			if err := checkNumIn(inputs, 1); err != nil {
 				return result, err
@@ -413,18 +482,34 @@ func NewColOperator(
 				return result, errors.AssertionFailedf("ordered cols must be a subset of grouping cols")
 			}
 
-			aggTyps := make([][]types.T, len(aggSpec.Aggregations))
-			aggCols := make([][]uint32, len(aggSpec.Aggregations))
-			aggFns := make([]execinfrapb.AggregatorSpec_Func, len(aggSpec.Aggregations))
+			var constArguments []tree.Datums
 			result.ColumnTypes = make([]types.T, len(aggSpec.Aggregations))
 			for i, agg := range aggSpec.Aggregations {
-				aggTyps[i] = make([]types.T, len(agg.ColIdx))
+				argTypes := make([]types.T, len(agg.ColIdx)+len(agg.Arguments))
 				for j, colIdx := range agg.ColIdx {
-					aggTyps[i][j] = spec.Input[0].ColumnTypes[colIdx]
+					argTypes[j] = spec.Input[0].ColumnTypes[colIdx]
 				}
-				aggCols[i] = agg.ColIdx
-				aggFns[i] = agg.Func
-				_, retType, err := execinfrapb.GetAggregateInfo(agg.Func, aggTyps[i]...)
+				if len(agg.Arguments) > 0 {
+					if constArguments == nil {
+						constArguments = make([]tree.Datums, len(aggSpec.Aggregations))
+					}
+					constArguments[i] = make(tree.Datums, len(agg.Arguments))
+					for j, argument := range agg.Arguments {
+						h := execinfra.ExprHelper{}
+						// Pass nil types and row - there are no variables in these
+						// expressions.
+						if err := h.Init(argument, nil /* types */, flowCtx.EvalCtx); err != nil {
+							return result, errors.Wrapf(err, "%s", argument)
+						}
+						d, err := h.Eval(nil /* row */)
+						if err != nil {
+							return result, errors.Wrapf(err, "%s", argument)
+						}
+						argTypes[len(agg.ColIdx)+j] = *d.ResolvedType()
+						constArguments[i][j] = d
+					}
+				}
+				_, retType, err := execinfrapb.GetAggregateInfo(agg.Func, argTypes...)
 				if err != nil {
 					return result, err
 				}
@@ -436,18 +521,60 @@ func NewColOperator(
 				return result, err
 			}
 			if needHash {
+				hashAggregatorMemMonitorName := fmt.Sprintf("hash-aggregator-%d", spec.ProcessorID)
 				hashAggregatorMemAccount := streamingMemAccount
 				if !useStreamingMemAccountForBuffering {
-					hashAggregatorMemAccount = result.createBufferingMemAccount(ctx, flowCtx, "hash-aggregator")
+					hashAggregatorMemAccount = result.createBufferingMemAccount(
+						ctx, flowCtx, hashAggregatorMemMonitorName,
+					)
 				}
-				result.Op, err = NewHashAggregator(
-					NewAllocator(ctx, hashAggregatorMemAccount), inputs[0], typs, aggFns,
-					aggSpec.GroupCols, aggCols, execinfrapb.IsScalarAggregate(aggSpec),
+				var inMemoryHashAggregator Operator
+				inMemoryHashAggregator, err = NewHashAggregator(
+					NewAllocator(ctx, hashAggregatorMemAccount), inputs[0], typs, aggSpec,
+					constArguments,
 				)
+				if err != nil {
+					return result, err
+				}
+				if args.TestingKnobs.DiskSpillingDisabled {
+					// We will not be creating a disk-backed hash aggregator because
+					// we're running a test that explicitly asked for only in-memory
+					// hash aggregator.
+					result.Op = inMemoryHashAggregator
+				} else {
+					result.Op = newOneInputDiskSpiller(
+						inputs[0], inMemoryHashAggregator.(bufferingInMemoryOperator),
+						hashAggregatorMemMonitorName,
+						func(input Operator) Operator {
+							monitorNamePrefix := fmt.Sprintf("external-hash-aggregator-%d", spec.ProcessorID)
+							unlimitedAllocator := NewAllocator(
+								ctx, result.createBufferingUnlimitedMemAccount(
+									ctx, flowCtx, monitorNamePrefix,
+								))
+							// The in-memory hash aggregators that process the partitions
+							// get the default limit explicitly since we don't want to use
+							// the default memory limit of 1 if ForceDiskSpill is true.
+							inMemMainOpAllocator := NewAllocator(
+								ctx, result.createBufferingMemAccountWithLimit(
+									ctx, flowCtx, monitorNamePrefix, execinfra.GetWorkMemLimit(flowCtx.Cfg),
+								))
+							return newExternalHashAggregator(
+								unlimitedAllocator, inMemMainOpAllocator, monitorNamePrefix+"-limited",
+								input, typs, aggSpec, constArguments,
+								result.makeDiskBackedSorterConstructor(
+									ctx, flowCtx, args, typs, monitorNamePrefix+"-sorter",
+								),
+								args.TestingKnobs.MaxNumberPartitions,
+								args.DiskQueueCfg,
+							)
+						},
+						args.TestingKnobs.SpillingCallbackFn,
+					)
+				}
 			} else {
 				result.Op, err = NewOrderedAggregator(
-					NewAllocator(ctx, streamingMemAccount), inputs[0], typs, aggFns,
-					aggSpec.GroupCols, aggCols, execinfrapb.IsScalarAggregate(aggSpec),
+					NewAllocator(ctx, streamingMemAccount), inputs[0], typs, aggSpec,
+					constArguments,
 				)
 				result.IsStreaming = true
 			}
@@ -484,10 +611,53 @@ func NewColOperator(
 				result.Op, err = NewOrderedDistinct(inputs[0], core.Distinct.OrderedColumns, typs)
 				result.IsStreaming = true
 			} else {
-				result.Op = NewUnorderedDistinct(
-					NewAllocator(ctx, streamingMemAccount), inputs[0],
+				distinctMemMonitorName := fmt.Sprintf("distinct-%d", spec.ProcessorID)
+				distinctMemAccount := streamingMemAccount
+				if !useStreamingMemAccountForBuffering {
+					distinctMemAccount = result.createBufferingMemAccount(
+						ctx, flowCtx, distinctMemMonitorName,
+					)
+				}
+				inMemoryUnorderedDistinct := NewUnorderedDistinct(
+					NewAllocator(ctx, distinctMemAccount), inputs[0],
 					core.Distinct.DistinctColumns, typs,
 				)
+				if args.TestingKnobs.DiskSpillingDisabled {
+					// We will not be creating a disk-backed unordered distinct
+					// because we're running a test that explicitly asked for only
+					// in-memory unordered distinct.
+					result.Op = inMemoryUnorderedDistinct
+				} else {
+					result.Op = newOneInputDiskSpiller(
+						inputs[0], inMemoryUnorderedDistinct.(bufferingInMemoryOperator),
+						distinctMemMonitorName,
+						func(input Operator) Operator {
+							monitorNamePrefix := fmt.Sprintf("external-distinct-%d", spec.ProcessorID)
+							unlimitedAllocator := NewAllocator(
+								ctx, result.createBufferingUnlimitedMemAccount(
+									ctx, flowCtx, monitorNamePrefix,
+								))
+							// The in-memory unordered distincts that process the
+							// partitions get the default limit explicitly since we don't
+							// want to use the default memory limit of 1 if ForceDiskSpill
+							// is true.
+							inMemMainOpAllocator := NewAllocator(
+								ctx, result.createBufferingMemAccountWithLimit(
+									ctx, flowCtx, monitorNamePrefix, execinfra.GetWorkMemLimit(flowCtx.Cfg),
+								))
+							return newExternalDistinct(
+								unlimitedAllocator, inMemMainOpAllocator, monitorNamePrefix+"-limited",
+								input, core.Distinct.DistinctColumns, typs,
+								result.makeDiskBackedSorterConstructor(
+									ctx, flowCtx, args, typs, monitorNamePrefix+"-sorter",
+								),
+								args.TestingKnobs.MaxNumberPartitions,
+								args.DiskQueueCfg,
+							)
+						},
+						args.TestingKnobs.SpillingCallbackFn,
+					)
+				}
 			}
 		case core.Ordinality != nil:
 			if err := checkNumIn(inputs, 1); err != nil {
@@ -512,65 +682,108 @@ func NewColOperator(
 				return result, err
 			}
 
-			hashJoinerMemMonitorName := fmt.Sprintf("hash-joiner-%d", spec.ProcessorID)
-			var hashJoinerMemAccount *mon.BoundAccount
-			if useStreamingMemAccountForBuffering {
-				hashJoinerMemAccount = streamingMemAccount
-			} else {
-				hashJoinerMemAccount = result.createBufferingMemAccount(
-					ctx, flowCtx, hashJoinerMemMonitorName,
+			if len(core.HashJoiner.LeftEqColumns) == 0 &&
+				(core.HashJoiner.OnExpr.Empty() || core.HashJoiner.Type == sqlbase.JoinType_INNER) {
+				// There are no equality columns, so we plan a cross joiner (the
+				// ON expression, if present, is planned on top of it below).
+				crossJoinerMemMonitorName := fmt.Sprintf("cross-joiner-%d", spec.ProcessorID)
+				var crossJoinerMemAccount *mon.BoundAccount
+				if useStreamingMemAccountForBuffering {
+					crossJoinerMemAccount = streamingMemAccount
+				} else {
+					crossJoinerMemAccount = result.createBufferingMemAccount(
+						ctx, flowCtx, crossJoinerMemMonitorName,
+					)
+				}
+				result.Op, err = newCrossJoiner(
+					NewAllocator(ctx, crossJoinerMemAccount),
+					NewAllocator(ctx, result.createBufferingUnlimitedMemAccount(
+						ctx, flowCtx, crossJoinerMemMonitorName+"-unlimited",
+					)),
+					core.HashJoiner.Type,
+					inputs[0], inputs[1],
+					leftPhysTypes, rightPhysTypes,
+					execinfra.GetWorkMemLimit(flowCtx.Cfg),
+					args.DiskQueueCfg,
 				)
-			}
-			// It is valid for empty set of equality columns to be considered as
-			// "key" (for example, the input has at most 1 row). However, hash
-			// joiner, in order to handle NULL values correctly, needs to think
-			// that an empty set of equality columns doesn't form a key.
-			rightEqColsAreKey := core.HashJoiner.RightEqColumnsAreKey && len(core.HashJoiner.RightEqColumns) > 0
-			hjSpec, err := makeHashJoinerSpec(
-				core.HashJoiner.Type,
-				core.HashJoiner.LeftEqColumns,
-				core.HashJoiner.RightEqColumns,
-				leftPhysTypes,
-				rightPhysTypes,
-				rightEqColsAreKey,
-			)
-			if err != nil {
-				return result, err
-			}
-			inMemoryHashJoiner := newHashJoiner(
-				NewAllocator(ctx, hashJoinerMemAccount), hjSpec, inputs[0], inputs[1],
-			)
-			if args.TestingKnobs.DiskSpillingDisabled {
-				// We will not be creating a disk-backed hash joiner because we're
-				// running a test that explicitly asked for only in-memory hash
-				// joiner.
-				result.Op = inMemoryHashJoiner
+				if err != nil {
+					return result, err
+				}
 			} else {
-				result.Op = newTwoInputDiskSpiller(
-					inputs[0], inputs[1], inMemoryHashJoiner.(bufferingInMemoryOperator),
-					hashJoinerMemMonitorName,
-					func(inputOne, inputTwo Operator) Operator {
-						monitorNamePrefix := "external-hash-joiner-"
-						allocator := NewAllocator(
-							// Pass in the default limit explicitly since we don't want to
-							// use the default memory limit of 1 if ForceDiskSpill is true, to
-							// allow for the external hash join to have a normal amount of
-							// memory (for initialization and internal joining).
-							ctx, result.createBufferingMemAccountWithLimit(
-								ctx, flowCtx, monitorNamePrefix, execinfra.GetWorkMemLimit(flowCtx.Cfg),
-							))
-						diskQueuesUnlimitedAllocator := NewAllocator(
-							ctx, result.createBufferingUnlimitedMemAccount(
-								ctx, flowCtx, monitorNamePrefix+"disk-queues",
-							))
-						return newExternalHashJoiner(
-							allocator, hjSpec,
-							inputOne, inputTwo,
-							diskQueuesUnlimitedAllocator,
-						)
-					},
-					args.TestingKnobs.SpillingCallbackFn,
+				hashJoinerMemMonitorName := fmt.Sprintf("hash-joiner-%d", spec.ProcessorID)
+				var hashJoinerMemAccount *mon.BoundAccount
+				if useStreamingMemAccountForBuffering {
+					hashJoinerMemAccount = streamingMemAccount
+				} else {
+					hashJoinerMemAccount = result.createBufferingMemAccount(
+						ctx, flowCtx, hashJoinerMemMonitorName,
+					)
+				}
+				// It is valid for empty set of equality columns to be considered as
+				// "key" (for example, the input has at most 1 row). However, hash
+				// joiner, in order to handle NULL values correctly, needs to think
+				// that an empty set of equality columns doesn't form a key.
+				rightEqColsAreKey := core.HashJoiner.RightEqColumnsAreKey && len(core.HashJoiner.RightEqColumns) > 0
+				hjSpec, err := makeHashJoinerSpec(
+					core.HashJoiner.Type,
+					core.HashJoiner.LeftEqColumns,
+					core.HashJoiner.RightEqColumns,
+					leftPhysTypes,
+					rightPhysTypes,
+					rightEqColsAreKey,
+				)
+				if err != nil {
+					return result, err
+				}
+				if !core.HashJoiner.OnExpr.Empty() && core.HashJoiner.Type != sqlbase.JoinType_INNER {
+					// The tuples that don't satisfy the ON expression must be
+					// NULL-extended, so the hash joiner evaluates the ON expression
+					// itself.
+					onExpr := core.HashJoiner.OnExpr
+					hjSpec.onExprFilterConstructor = func(op Operator) (Operator, error) {
+						r := NewColOperatorResult{
+							Op:          op,
+							ColumnTypes: append(leftLogTypes, rightLogTypes...),
+						}
+						err := r.planFilterExpr(ctx, flowCtx.NewEvalCtx(), onExpr, streamingMemAccount)
+						return r.Op, err
+					}
+				}
+				inMemoryHashJoiner := newHashJoiner(
+					NewAllocator(ctx, hashJoinerMemAccount), hjSpec, inputs[0], inputs[1],
 				)
+				if args.TestingKnobs.DiskSpillingDisabled {
+					// We will not be creating a disk-backed hash joiner because we're
+					// running a test that explicitly asked for only in-memory hash
+					// joiner.
+					result.Op = inMemoryHashJoiner
+				} else {
+					result.Op = newTwoInputDiskSpiller(
+						inputs[0], inputs[1], inMemoryHashJoiner.(bufferingInMemoryOperator),
+						hashJoinerMemMonitorName,
+						func(inputOne, inputTwo Operator) Operator {
+							monitorNamePrefix := "external-hash-joiner-"
+							allocator := NewAllocator(
+								// Pass in the default limit explicitly since we don't want to
+								// use the default memory limit of 1 if ForceDiskSpill is true, to
+								// allow for the external hash join to have a normal amount of
+								// memory (for initialization and internal joining).
+								ctx, result.createBufferingMemAccountWithLimit(
+									ctx, flowCtx, monitorNamePrefix, execinfra.GetWorkMemLimit(flowCtx.Cfg),
+								))
+							diskQueuesUnlimitedAllocator := NewAllocator(
+								ctx, result.createBufferingUnlimitedMemAccount(
+									ctx, flowCtx, monitorNamePrefix+"disk-queues",
+								))
+							return newExternalHashJoiner(
+								allocator, hjSpec,
+								inputOne, inputTwo,
+								diskQueuesUnlimitedAllocator,
+							)
+						},
+						args.TestingKnobs.SpillingCallbackFn,
+					)
+				}
 			}
 			result.ColumnTypes = append(leftLogTypes, rightLogTypes...)
 
@@ -648,13 +861,31 @@ func NewColOperator(
 				// Whether the merge joiner is streaming is already set above.
 				mergeJoinerMemAccount = result.createBufferingMemAccount(ctx, flowCtx, "merge-joiner")
 			}
+			leftInput, rightInput := inputs[0], inputs[1]
+			mjLeftPhysTypes, mjRightPhysTypes := leftPhysTypes, rightPhysTypes
+			outerJoinWithOnExpr := onExpr != nil && filterConstructor == nil && joinType != sqlbase.JoinType_INNER
+			if outerJoinWithOnExpr {
+				// In order to evaluate the ON expression for outer joins, the merge
+				// joiner performs the join only on the equality columns, and the
+				// operator planned on top of it needs to be able to tell which
+				// tuples come from the same input tuple, so we append the ordinality
+				// columns to both inputs.
+				leftInput = NewOrdinalityOp(
+					NewAllocator(ctx, streamingMemAccount), leftInput, len(leftPhysTypes),
+				)
+				rightInput = NewOrdinalityOp(
+					NewAllocator(ctx, streamingMemAccount), rightInput, len(rightPhysTypes),
+				)
+				mjLeftPhysTypes = append(append([]coltypes.T{}, leftPhysTypes...), coltypes.Int64)
+				mjRightPhysTypes = append(append([]coltypes.T{}, rightPhysTypes...), coltypes.Int64)
+			}
 			result.Op, err = NewMergeJoinOp(
 				NewAllocator(ctx, mergeJoinerMemAccount),
 				core.MergeJoiner.Type,
-				inputs[0],
-				inputs[1],
-				leftPhysTypes,
-				rightPhysTypes,
+				leftInput,
+				rightInput,
+				mjLeftPhysTypes,
+				mjRightPhysTypes,
 				core.MergeJoiner.LeftOrdering.Columns,
 				core.MergeJoiner.RightOrdering.Columns,
 				filterConstructor,
@@ -663,6 +894,28 @@ func NewColOperator(
 			if err != nil {
 				return result, err
 			}
+			if outerJoinWithOnExpr {
+				// We will plan another Operator on top of the merge joiner, so we
+				// need to account for the internal memory explicitly.
+				if internalMemOp, ok := result.Op.(InternalMemoryOperator); ok {
+					result.InternalMemUsage += internalMemOp.InternalMemoryUsage()
+				}
+				result.Op, err = newMergeJoinOuterFilterOp(
+					NewAllocator(ctx, mergeJoinerMemAccount), result.Op, joinType,
+					leftPhysTypes, rightPhysTypes,
+					func(op Operator) (Operator, error) {
+						r := NewColOperatorResult{
+							Op:          op,
+							ColumnTypes: append(leftLogTypes, rightLogTypes...),
+						}
+						err := r.planFilterExpr(ctx, flowCtx.NewEvalCtx(), *onExpr, streamingMemAccount)
+						return r.Op, err
+					},
+				)
+				if err != nil {
+					return result, err
+				}
+			}
 
 			result.ColumnTypes = append(leftLogTypes, rightLogTypes...)
 
@@ -794,70 +1047,212 @@ func NewColOperator(
 			if err := checkNumIn(inputs, 1); err != nil {
 				return result, err
 			}
-			wf := core.Windower.WindowFns[0]
 			input := inputs[0]
-			var typs []coltypes.T
-			typs, err = typeconv.FromColumnTypes(spec.Input[0].ColumnTypes)
-			if err != nil {
-				return result, err
-			}
-			tempPartitionColOffset, partitionColIdx := 0, -1
-			if len(core.Windower.PartitionBy) > 0 {
-				// TODO(yuzefovich): add support for hashing partitioner (probably by
-				// leveraging hash routers once we can distribute). The decision about
-				// which kind of partitioner to use should come from the optimizer.
-				windowSortingPartitionerMemAccount := streamingMemAccount
-				if !useStreamingMemAccountForBuffering {
-					windowSortingPartitionerMemAccount = result.createBufferingMemAccount(ctx, flowCtx, "window-sorting-partitioner")
+			windowFns := core.Windower.WindowFns
+			partitionBy := core.Windower.PartitionBy
+			numInputCols := len(spec.Input[0].ColumnTypes)
+			// curTypes are the types of the columns that are output by the chain of
+			// operators planned so far. Every stage appends the outputs of its
+			// window functions to the columns of its input.
+			curTypes := make([]types.T, numInputCols, numInputCols+len(windowFns))
+			copy(curTypes, spec.Input[0].ColumnTypes)
+			// windowFnOutputCols contains the index of the column which the output
+			// of each window function is written into.
+			windowFnOutputCols := make([]uint32, len(windowFns))
+			// The window functions that have the same ORDER BY clause are computed
+			// together in a single "stage", and each stage re-sorts its input.
+			// TODO(yuzefovich): the stages could be ordered so that the sorts can
+			// reuse the ordering established by the previous stage.
+			for stageIdx, stage := range groupWindowFnsByOrdering(windowFns) {
+				stageFns := make([]execinfrapb.WindowerSpec_WindowFn, len(stage))
+				for i, fnIdx := range stage {
+					stageFns[i] = windowFns[fnIdx]
 				}
-				input, err = NewWindowSortingPartitioner(
-					NewAllocator(ctx, windowSortingPartitionerMemAccount), input, typs,
-					core.Windower.PartitionBy, wf.Ordering.Columns, int(wf.OutputColIdx),
-				)
-				tempPartitionColOffset, partitionColIdx = 1, int(wf.OutputColIdx)
-			} else {
-				if len(wf.Ordering.Columns) > 0 {
-					windowSorterMemAccount := streamingMemAccount
-					if !useStreamingMemAccountForBuffering {
-						windowSorterMemAccount = result.createBufferingMemAccount(ctx, flowCtx, "window-sorter")
+				ordering := stageFns[0].Ordering
+				stageInputTypes := curTypes
+				var typs []coltypes.T
+				typs, err = typeconv.FromColumnTypes(stageInputTypes)
+				if err != nil {
+					return result, err
+				}
+				// The input is sorted on the PARTITION BY columns first (the
+				// direction doesn't matter) and on the ORDER BY columns second.
+				var sortCols []execinfrapb.Ordering_Column
+				for _, colIdx := range partitionBy {
+					sortCols = append(sortCols, execinfrapb.Ordering_Column{
+						ColIdx:    colIdx,
+						Direction: execinfrapb.Ordering_Column_ASC,
+					})
+				}
+				sortCols = append(sortCols, ordering.Columns...)
+				if len(sortCols) > 0 {
+					// TODO(yuzefovich): add support for hashing partitioner (probably
+					// by leveraging hash routers once we can distribute). The decision
+					// about which kind of partitioner to use should come from the
+					// optimizer.
+					input, err = result.planDiskBackedSorter(
+						ctx, flowCtx, args, input, typs,
+						execinfrapb.Ordering{Columns: sortCols},
+						fmt.Sprintf("window-sort-%d-stage-%d", spec.ProcessorID, stageIdx),
+					)
+					if err != nil {
+						return result, err
 					}
-					input, err = NewSorter(
-						NewAllocator(ctx, windowSorterMemAccount), input, typs,
-						wf.Ordering.Columns,
+				}
+				partitionColIdx := -1
+				if len(partitionBy) > 0 {
+					// The partitioner appends a temporary boolean column that marks
+					// the beginning of every partition.
+					partitionColIdx = len(typs)
+					input, err = NewWindowPartitioner(
+						NewAllocator(ctx, streamingMemAccount), input, typs,
+						partitionBy, partitionColIdx,
 					)
+					if err != nil {
+						return result, err
+					}
+					typs = append(typs, coltypes.Bool)
+				}
+				fnsOutputStart := len(typs)
+				fnsOutputTypes := make([]types.T, len(stageFns))
+				fnsOutputPhysTypes := make([]coltypes.T, len(stageFns))
+				for i := range stageFns {
+					argTypes := make([]types.T, len(stageFns[i].ArgsIdxs))
+					for j, argIdx := range stageFns[i].ArgsIdxs {
+						argTypes[j] = stageInputTypes[argIdx]
+					}
+					outputType, err := GetWindowFnOutputType(stageFns[i].Func, argTypes)
+					if err != nil {
+						return result, err
+					}
+					fnsOutputTypes[i] = *outputType
+					fnsOutputPhysTypes[i] = typeconv.FromColumnType(outputType)
+					// The window functions of the stage write their outputs into the
+					// columns that are appended after all of the input columns.
+					stageFns[i].OutputColIdx = uint32(fnsOutputStart + i)
+				}
+				orderingCols := make([]uint32, len(ordering.Columns))
+				for i, col := range ordering.Columns {
+					orderingCols[i] = col.ColIdx
 				}
-				// TODO(yuzefovich): when both PARTITION BY and ORDER BY clauses are
-				// omitted, the window function operator is actually streaming.
-			}
-			if err != nil {
-				return result, err
-			}
 
-			orderingCols := make([]uint32, len(wf.Ordering.Columns))
-			for i, col := range wf.Ordering.Columns {
-				orderingCols[i] = col.ColIdx
-			}
-			switch *wf.Func.WindowFunc {
-			case execinfrapb.WindowerSpec_ROW_NUMBER:
-				result.Op = NewRowNumberOperator(NewAllocator(ctx, streamingMemAccount), input, int(wf.OutputColIdx)+tempPartitionColOffset, partitionColIdx)
-			case execinfrapb.WindowerSpec_RANK:
-				result.Op, err = NewRankOperator(NewAllocator(ctx, streamingMemAccount), input, typs, false /* dense */, orderingCols, int(wf.OutputColIdx)+tempPartitionColOffset, partitionColIdx)
-			case execinfrapb.WindowerSpec_DENSE_RANK:
-				result.Op, err = NewRankOperator(NewAllocator(ctx, streamingMemAccount), input, typs, true /* dense */, orderingCols, int(wf.OutputColIdx)+tempPartitionColOffset, partitionColIdx)
-			}
+				allStreaming := true
+				for i := range stageFns {
+					allStreaming = allStreaming && isStreamingWindowFn(&stageFns[i])
+				}
+				if allStreaming {
+					for i := range stageFns {
+						outputColIdx := int(stageFns[i].OutputColIdx)
+						switch *stageFns[i].Func.WindowFunc {
+						case execinfrapb.WindowerSpec_ROW_NUMBER:
+							input = NewRowNumberOperator(
+								NewAllocator(ctx, streamingMemAccount), input, outputColIdx, partitionColIdx,
+							)
+						case execinfrapb.WindowerSpec_RANK:
+							input, err = NewRankOperator(
+								NewAllocator(ctx, streamingMemAccount), input, typs,
+								false /* dense */, orderingCols, outputColIdx, partitionColIdx,
+							)
+						case execinfrapb.WindowerSpec_DENSE_RANK:
+							input, err = NewRankOperator(
+								NewAllocator(ctx, streamingMemAccount), input, typs,
+								true /* dense */, orderingCols, outputColIdx, partitionColIdx,
+							)
+						}
+						if err != nil {
+							return result, err
+						}
+					}
+				} else {
+					windowMemMonitorName := fmt.Sprintf("window-%d-stage-%d", spec.ProcessorID, stageIdx)
+					var windowMemAccount *mon.BoundAccount
+					if useStreamingMemAccountForBuffering {
+						windowMemAccount = streamingMemAccount
+					} else {
+						windowMemAccount = result.createBufferingMemAccount(
+							ctx, flowCtx, windowMemMonitorName,
+						)
+					}
+					var inMemoryWindower Operator
+					inMemoryWindower, err = NewBufferedWindowOperator(
+						NewAllocator(ctx, windowMemAccount), input, typs,
+						fnsOutputPhysTypes, stageFns, orderingCols, partitionColIdx,
+					)
+					if err != nil {
+						return result, err
+					}
+					if args.TestingKnobs.DiskSpillingDisabled || processorConstructor == nil {
+						input = inMemoryWindower
+					} else {
+						windowerInputTypes := stageInputTypes
+						if partitionColIdx != -1 {
+							windowerInputTypes = append(windowerInputTypes[:len(windowerInputTypes):len(windowerInputTypes)], *types.Bool)
+						}
+						input = newOneInputDiskSpiller(
+							input, inMemoryWindower.(bufferingInMemoryOperator),
+							windowMemMonitorName,
+							func(input Operator) Operator {
+								// TODO(yuzefovich): implement a vectorized window operator
+								// that buffers the partitions on disk instead of falling back
+								// to the row-by-row windower.
+								windower, err := result.wrapDiskBackedWindower(
+									ctx, flowCtx, spec.ProcessorID, input, windowerInputTypes,
+									&execinfrapb.WindowerSpec{PartitionBy: partitionBy, WindowFns: stageFns},
+									processorConstructor, streamingMemAccount,
+								)
+								if err != nil {
+									execerror.VectorizedInternalPanic(err)
+								}
+								return windower
+							},
+							args.TestingKnobs.SpillingCallbackFn,
+						)
+					}
+				}
 
-			if partitionColIdx != -1 {
-				// Window partitioner will append a temporary column to the batch which
-				// we want to project out.
-				projection := make([]uint32, 0, wf.OutputColIdx+1)
-				for i := uint32(0); i < wf.OutputColIdx; i++ {
-					projection = append(projection, i)
+				if partitionColIdx != -1 {
+					// Window partitioner has appended a temporary column to the batch
+					// which we want to project out.
+					projection := make([]uint32, 0, len(typs)-1+len(stageFns))
+					for i := 0; i < partitionColIdx; i++ {
+						projection = append(projection, uint32(i))
+					}
+					for i := range stageFns {
+						projection = append(projection, uint32(fnsOutputStart+i))
+					}
+					input = NewSimpleProjectOp(input, len(typs)+len(stageFns), projection)
+				}
+				for i, fnIdx := range stage {
+					windowFnOutputCols[fnIdx] = uint32(len(curTypes) + i)
 				}
-				projection = append(projection, wf.OutputColIdx+1)
-				result.Op = NewSimpleProjectOp(result.Op, int(wf.OutputColIdx+1), projection)
+				curTypes = append(curTypes, fnsOutputTypes...)
+			}
+
+			// Finally, we need to put the outputs of the window functions into the
+			// columns requested by the spec.
+			result.Op = input
+			result.ColumnTypes = make([]types.T, len(curTypes))
+			projection := make([]uint32, len(curTypes))
+			isIdentity := true
+			for i := 0; i < numInputCols; i++ {
+				projection[i] = uint32(i)
+				result.ColumnTypes[i] = curTypes[i]
+			}
+			for i := range windowFns {
+				outputColIdx := windowFns[i].OutputColIdx
+				if int(outputColIdx) < numInputCols || int(outputColIdx) >= len(curTypes) {
+					return result, errors.AssertionFailedf(
+						"unexpected output column index %d of window function %s",
+						outputColIdx, windowFns[i].String(),
+					)
+				}
+				projection[outputColIdx] = windowFnOutputCols[i]
+				result.ColumnTypes[outputColIdx] = curTypes[windowFnOutputCols[i]]
+				isIdentity = isIdentity && outputColIdx == windowFnOutputCols[i]
+			}
+			if !isIdentity {
+				result.Op = NewSimpleProjectOp(result.Op, len(curTypes), projection)
 			}
-
-			result.ColumnTypes = append(spec.Input[0].ColumnTypes, *types.Int)
 
 		default:
 			return result, errors.Newf("unsupported processor core %q", core)
@@ -1033,6 +1428,155 @@ func (r *NewColOperatorResult) createStandaloneMemAccount(
 	return &standaloneMemAccount
 }
 
+// makeDiskBackedSorterConstructor returns a function that creates a
+// disk-backed sorter that orders the input on the given columns in ascending
+// order. All sorters created by the returned function share the same memory
+// accounts, so they must be used one at a time.
+func (r *NewColOperatorResult) makeDiskBackedSorterConstructor(
+	ctx context.Context,
+	flowCtx *execinfra.FlowCtx,
+	args NewColOperatorArgs,
+	inputTypes []coltypes.T,
+	monitorNamePrefix string,
+) func(input Operator, orderingCols []uint32) Operator {
+	unlimitedAllocator := NewAllocator(
+		ctx, r.createBufferingUnlimitedMemAccount(
+			ctx, flowCtx, monitorNamePrefix,
+		))
+	standaloneAllocator := NewAllocator(
+		ctx, r.createStandaloneMemAccount(
+			ctx, flowCtx, monitorNamePrefix,
+		))
+	diskQueuesUnlimitedAllocator := NewAllocator(
+		ctx, r.createBufferingUnlimitedMemAccount(
+			ctx, flowCtx, monitorNamePrefix+"-disk-queues",
+		))
+	return func(input Operator, orderingCols []uint32) Operator {
+		var ordering execinfrapb.Ordering
+		ordering.Columns = make([]execinfrapb.Ordering_Column, len(orderingCols))
+		for i, col := range orderingCols {
+			ordering.Columns[i] = execinfrapb.Ordering_Column{
+				ColIdx:    col,
+				Direction: execinfrapb.Ordering_Column_ASC,
+			}
+		}
+		// The previously created sorter (if any) will not be used anymore, so
+		// we need to make sure that the memory it has registered with the
+		// standalone allocator doesn't affect the new one.
+		standaloneAllocator.Clear()
+		return newExternalSorter(
+			unlimitedAllocator,
+			standaloneAllocator,
+			input, inputTypes, ordering,
+			execinfra.GetWorkMemLimit(flowCtx.Cfg),
+			args.TestingKnobs.MaxNumberPartitions,
+			diskQueuesUnlimitedAllocator,
+			args.DiskQueueCfg,
+		)
+	}
+}
+
+// planDiskBackedSorter plans a sorter that orders the input according to the
+// given ordering and that falls back to the external sort once its in-memory
+// buffer exceeds the memory limit.
+func (r *NewColOperatorResult) planDiskBackedSorter(
+	ctx context.Context,
+	flowCtx *execinfra.FlowCtx,
+	args NewColOperatorArgs,
+	input Operator,
+	inputTypes []coltypes.T,
+	ordering execinfrapb.Ordering,
+	sorterMemMonitorName string,
+) (Operator, error) {
+	var sorterMemAccount *mon.BoundAccount
+	if args.TestingKnobs.UseStreamingMemAccountForBuffering {
+		sorterMemAccount = args.StreamingMemAccount
+	} else {
+		sorterMemAccount = r.createBufferingMemAccount(ctx, flowCtx, sorterMemMonitorName)
+	}
+	inMemorySorter, err := NewSorter(
+		NewAllocator(ctx, sorterMemAccount), input, inputTypes, ordering.Columns,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if args.TestingKnobs.DiskSpillingDisabled {
+		return inMemorySorter, nil
+	}
+	return newOneInputDiskSpiller(
+		input, inMemorySorter.(bufferingInMemoryOperator),
+		sorterMemMonitorName,
+		func(input Operator) Operator {
+			monitorNamePrefix := sorterMemMonitorName + "-external"
+			unlimitedAllocator := NewAllocator(
+				ctx, r.createBufferingUnlimitedMemAccount(
+					ctx, flowCtx, monitorNamePrefix,
+				))
+			standaloneAllocator := NewAllocator(
+				ctx, r.createStandaloneMemAccount(
+					ctx, flowCtx, monitorNamePrefix,
+				))
+			diskQueuesUnlimitedAllocator := NewAllocator(
+				ctx, r.createBufferingUnlimitedMemAccount(
+					ctx, flowCtx, monitorNamePrefix+"-disk-queues",
+				))
+			return newExternalSorter(
+				unlimitedAllocator,
+				standaloneAllocator,
+				input, inputTypes, ordering,
+				execinfra.GetWorkMemLimit(flowCtx.Cfg),
+				args.TestingKnobs.MaxNumberPartitions,
+				diskQueuesUnlimitedAllocator,
+				args.DiskQueueCfg,
+			)
+		},
+		args.TestingKnobs.SpillingCallbackFn,
+	), nil
+}
+
+// wrapDiskBackedWindower wraps a row-by-row windower processor (which is able
+// to spill to disk on its own) that computes the given window functions on the
+// input. It is used as the disk-backed fallback of the buffered window
+// operator.
+func (r *NewColOperatorResult) wrapDiskBackedWindower(
+	ctx context.Context,
+	flowCtx *execinfra.FlowCtx,
+	processorID int32,
+	input Operator,
+	inputTypes []types.T,
+	windowerSpec *execinfrapb.WindowerSpec,
+	processorConstructor execinfra.ProcessorConstructor,
+	acc *mon.BoundAccount,
+) (Operator, error) {
+	core := &execinfrapb.ProcessorCoreUnion{Windower: windowerSpec}
+	c, err := wrapRowSources(
+		ctx, flowCtx, []Operator{input}, [][]types.T{inputTypes}, acc,
+		func(inputs []execinfra.RowSource) (execinfra.RowSource, error) {
+			proc, err := processorConstructor(
+				ctx, flowCtx, processorID, core, &execinfrapb.PostProcessSpec{}, inputs,
+				[]execinfra.RowReceiver{nil}, /* outputs */
+				nil,                          /* localProcessors */
+			)
+			if err != nil {
+				return nil, err
+			}
+			rs, ok := proc.(execinfra.RowSource)
+			if !ok {
+				return nil, errors.AssertionFailedf(
+					"processor %s is not an execinfra.RowSource", core.String(),
+				)
+			}
+			return rs, nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	windower := &diskBackedWindower{Columnarizer: c}
+	r.MetadataSources = append(r.MetadataSources, windower)
+	return windower, nil
+}
+
 func (r *NewColOperatorResult) planFilterExpr(
 	ctx context.Context,
 	evalCtx *tree.EvalContext,
@@ -0,0 +1,66 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/colcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+)
+
+// newExternalDistinct returns a disk-backed unordered distinct. It partitions
+// the input on the distinct columns (see hashBasedPartitioner for more
+// details) and uses the in-memory unordered distinct on each of the
+// partitions. The partitions that cannot be made smaller by repartitioning
+// are sorted by the disk-backed sorter (created by createDiskBackedSorter) and
+// are processed by the ordered distinct.
+//
+// The input specifications to this function are the same as that of the
+// NewUnorderedDistinct function. See newHashBasedPartitioner for the
+// description of the other arguments.
+func newExternalDistinct(
+	unlimitedAllocator *Allocator,
+	inMemMainOpAllocator *Allocator,
+	inMemMainOpMonitorName string,
+	input Operator,
+	distinctCols []uint32,
+	colTypes []coltypes.T,
+	createDiskBackedSorter func(input Operator, orderingCols []uint32) Operator,
+	maxNumberPartitions int,
+	diskQueueCfg colcontainer.DiskQueueCfg,
+) Operator {
+	inMemMainOpConstructor := func(allocator *Allocator, partitionedInput Operator) bufferingInMemoryOperator {
+		return NewUnorderedDistinct(
+			allocator, partitionedInput, distinctCols, colTypes,
+		).(bufferingInMemoryOperator)
+	}
+	diskBackedFallbackOpConstructor := func(partitionedInput Operator) Operator {
+		op, err := NewOrderedDistinct(
+			createDiskBackedSorter(partitionedInput, distinctCols), distinctCols, colTypes,
+		)
+		if err != nil {
+			execerror.VectorizedInternalPanic(err)
+		}
+		return op
+	}
+	return newHashBasedPartitioner(
+		unlimitedAllocator,
+		inMemMainOpAllocator,
+		inMemMainOpMonitorName,
+		input,
+		colTypes,
+		distinctCols,
+		inMemMainOpConstructor,
+		diskBackedFallbackOpConstructor,
+		maxNumberPartitions,
+		diskQueueCfg,
+	)
+}
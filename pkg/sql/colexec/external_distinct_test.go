@@ -0,0 +1,176 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/colcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils/colcontainerutils"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExternalDistinct(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	defer evalCtx.Stop(ctx)
+	flowCtx := &execinfra.FlowCtx{
+		EvalCtx: &evalCtx,
+		Cfg: &execinfra.ServerConfig{
+			Settings: st,
+		},
+	}
+	queueCfg, cleanup := colcontainerutils.NewTestingDiskQueueCfg(t, true /* inMem */)
+	defer cleanup()
+	rng, _ := randutil.NewPseudoRand()
+	nTups := int(coldata.BatchSize()*4 + 1)
+
+	var (
+		memAccounts []*mon.BoundAccount
+		memMonitors []*mon.BytesMonitor
+	)
+	// In order to increase test coverage of recursive repartitioning, we use
+	// the smallest possible number of partitions.
+	const maxNumberPartitions = 2
+	// Interesting disk spilling scenarios:
+	// 1) The unordered distinct is forced to spill to disk as soon as
+	//    possible, but every partition is small enough to be processed in
+	//    memory.
+	// 2) The memory limit is set to 128KiB, this will allow the in-memory
+	//    unordered distinct to buffer some tuples before hitting the memory
+	//    limit, and some of the partitions will need to be repartitioned.
+	// 3) The memory limit is set to 1, so all partitions will be repartitioned
+	//    until they consist of a single distinct tuple and then will be
+	//    processed by the sort-based fallback.
+	for _, tk := range []execinfra.TestingKnobs{
+		{ForceDiskSpill: true},
+		{MemoryLimitBytes: 128 << 10},
+		{MemoryLimitBytes: 1},
+	} {
+		flowCtx.Cfg.TestingKnobs = tk
+		for _, maxVal := range []int{1, 3, nTups} {
+			if tk.MemoryLimitBytes == 1 && maxVal == nTups {
+				// Every distinct tuple would end up being processed by a separate
+				// fallback operator which makes the test too slow.
+				continue
+			}
+			namePrefix := "MemoryLimit=" + humanizeutil.IBytes(tk.MemoryLimitBytes)
+			if tk.ForceDiskSpill {
+				namePrefix = "ForceDiskSpill=true"
+			}
+			name := fmt.Sprintf("%s/maxVal=%d", namePrefix, maxVal)
+			t.Run(name, func(t *testing.T) {
+				tups, expected := generateRandomDataForTestExternalDistinct(rng, nTups, maxVal)
+				var spilled bool
+				runTests(
+					t,
+					[]tuples{tups},
+					expected,
+					unorderedVerifier,
+					func(input []Operator) (Operator, error) {
+						op, accounts, monitors, err := createDiskBackedDistinct(
+							ctx, flowCtx, input, func() { spilled = true }, maxNumberPartitions, queueCfg,
+						)
+						memAccounts = append(memAccounts, accounts...)
+						memMonitors = append(memMonitors, monitors...)
+						return op, err
+					})
+				if tk.ForceDiskSpill || tk.MemoryLimitBytes == 1 {
+					require.True(t, spilled, "expected the unordered distinct to spill to disk")
+				}
+			})
+		}
+	}
+	for _, account := range memAccounts {
+		account.Close(ctx)
+	}
+	for _, monitor := range memMonitors {
+		monitor.Stop(ctx)
+	}
+}
+
+// generateRandomDataForTestExternalDistinct returns nTups random tuples of two
+// columns, with the values in range [0, maxVal) or NULL, as well as the
+// distinct tuples among them (in no particular order).
+func generateRandomDataForTestExternalDistinct(
+	rng *rand.Rand, nTups, maxVal int,
+) (tups, expected tuples) {
+	seen := make(map[string]struct{})
+	tups = make(tuples, nTups)
+	for i := range tups {
+		tups[i] = make(tuple, 2)
+		for j := range tups[i] {
+			if rng.Float64() < nullProbability {
+				tups[i][j] = nil
+			} else {
+				tups[i][j] = int64(rng.Intn(maxVal))
+			}
+		}
+		key := fmt.Sprintf("%v", tups[i])
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			expected = append(expected, tups[i])
+		}
+	}
+	return tups, expected
+}
+
+// createDiskBackedDistinct is a helper function that instantiates a
+// disk-backed unordered distinct on both columns of the input of two integer
+// columns. The desired memory limit must have been already set on flowCtx. It
+// returns an operator and an error as well as memory monitors and memory
+// accounts that will need to be closed once the caller is done with the
+// operator.
+func createDiskBackedDistinct(
+	ctx context.Context,
+	flowCtx *execinfra.FlowCtx,
+	input []Operator,
+	spillingCallbackFn func(),
+	maxNumberPartitions int,
+	queueCfg colcontainer.DiskQueueCfg,
+) (Operator, []*mon.BoundAccount, []*mon.BytesMonitor, error) {
+	spec := &execinfrapb.ProcessorSpec{
+		Input: []execinfrapb.InputSyncSpec{{ColumnTypes: []types.T{*types.Int, *types.Int}}},
+		Core: execinfrapb.ProcessorCoreUnion{
+			Distinct: &execinfrapb.DistinctSpec{
+				DistinctColumns: []uint32{0, 1},
+			},
+		},
+	}
+	args := NewColOperatorArgs{
+		Spec:                spec,
+		Inputs:              input,
+		StreamingMemAccount: testMemAcc,
+		DiskQueueCfg:        queueCfg,
+	}
+	// The external distinct relies on the memory limit of the buffering
+	// memory accounts to understand when to spill, so we will not use the
+	// streaming memory account.
+	args.TestingKnobs.SpillingCallbackFn = spillingCallbackFn
+	args.TestingKnobs.MaxNumberPartitions = maxNumberPartitions
+	result, err := NewColOperator(ctx, flowCtx, args)
+	return result.Op, result.BufferingOpMemAccounts, result.BufferingOpMemMonitors, err
+}
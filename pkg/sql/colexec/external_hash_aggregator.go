@@ -0,0 +1,72 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/colcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// newExternalHashAggregator returns a disk-backed hash aggregator. It
+// partitions the input on the grouping columns (see hashBasedPartitioner for
+// more details) and uses the in-memory hash aggregator to aggregate each of
+// the partitions. The partitions that cannot be made smaller by
+// repartitioning are sorted by the disk-backed sorter (created by
+// createDiskBackedSorter) and are aggregated by the ordered aggregator.
+//
+// The input specifications to this function are the same as that of the
+// NewHashAggregator function. See newHashBasedPartitioner for the description
+// of the other arguments.
+func newExternalHashAggregator(
+	unlimitedAllocator *Allocator,
+	inMemMainOpAllocator *Allocator,
+	inMemMainOpMonitorName string,
+	input Operator,
+	colTypes []coltypes.T,
+	spec *execinfrapb.AggregatorSpec,
+	constArguments []tree.Datums,
+	createDiskBackedSorter func(input Operator, orderingCols []uint32) Operator,
+	maxNumberPartitions int,
+	diskQueueCfg colcontainer.DiskQueueCfg,
+) Operator {
+	inMemMainOpConstructor := func(allocator *Allocator, partitionedInput Operator) bufferingInMemoryOperator {
+		op, err := NewHashAggregator(allocator, partitionedInput, colTypes, spec, constArguments)
+		if err != nil {
+			execerror.VectorizedInternalPanic(err)
+		}
+		return op.(bufferingInMemoryOperator)
+	}
+	diskBackedFallbackOpConstructor := func(partitionedInput Operator) Operator {
+		op, err := NewOrderedAggregator(
+			unlimitedAllocator, createDiskBackedSorter(partitionedInput, spec.GroupCols),
+			colTypes, spec, constArguments,
+		)
+		if err != nil {
+			execerror.VectorizedInternalPanic(err)
+		}
+		return op
+	}
+	return newHashBasedPartitioner(
+		unlimitedAllocator,
+		inMemMainOpAllocator,
+		inMemMainOpMonitorName,
+		input,
+		colTypes,
+		spec.GroupCols,
+		inMemMainOpConstructor,
+		diskBackedFallbackOpConstructor,
+		maxNumberPartitions,
+		diskQueueCfg,
+	)
+}
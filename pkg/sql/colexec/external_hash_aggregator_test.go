@@ -0,0 +1,199 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/colcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils/colcontainerutils"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExternalHashAggregator(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	defer evalCtx.Stop(ctx)
+	flowCtx := &execinfra.FlowCtx{
+		EvalCtx: &evalCtx,
+		Cfg: &execinfra.ServerConfig{
+			Settings: st,
+		},
+	}
+	queueCfg, cleanup := colcontainerutils.NewTestingDiskQueueCfg(t, true /* inMem */)
+	defer cleanup()
+	rng, _ := randutil.NewPseudoRand()
+	nTups := int(coldata.BatchSize()*4 + 1)
+
+	var (
+		memAccounts []*mon.BoundAccount
+		memMonitors []*mon.BytesMonitor
+	)
+	// In order to increase test coverage of recursive repartitioning, we use
+	// the smallest possible number of partitions.
+	const maxNumberPartitions = 2
+	// Interesting disk spilling scenarios:
+	// 1) The hash aggregator is forced to spill to disk as soon as possible,
+	//    but every partition is small enough to be aggregated in memory.
+	// 2) The memory limit is set to 128KiB, this will allow the in-memory hash
+	//    aggregator to buffer some tuples before hitting the memory limit, and
+	//    some of the partitions will need to be repartitioned.
+	// 3) The memory limit is set to 1, so all partitions will be repartitioned
+	//    until they consist of a single group and then will be processed by
+	//    the sort-based fallback.
+	for _, tk := range []execinfra.TestingKnobs{
+		{ForceDiskSpill: true},
+		{MemoryLimitBytes: 128 << 10},
+		{MemoryLimitBytes: 1},
+	} {
+		flowCtx.Cfg.TestingKnobs = tk
+		for _, numGroups := range []int{1, 7, nTups} {
+			if tk.MemoryLimitBytes == 1 && numGroups == nTups {
+				// Every group would end up being processed by a separate fallback
+				// operator which makes the test too slow.
+				continue
+			}
+			namePrefix := "MemoryLimit=" + humanizeutil.IBytes(tk.MemoryLimitBytes)
+			if tk.ForceDiskSpill {
+				namePrefix = "ForceDiskSpill=true"
+			}
+			name := fmt.Sprintf("%s/numGroups=%d", namePrefix, numGroups)
+			t.Run(name, func(t *testing.T) {
+				tups, expected := generateRandomDataForTestExternalHashAggregator(rng, nTups, numGroups)
+				var spilled bool
+				runTests(
+					t,
+					[]tuples{tups},
+					expected,
+					unorderedVerifier,
+					func(input []Operator) (Operator, error) {
+						op, accounts, monitors, err := createDiskBackedHashAggregator(
+							ctx, flowCtx, input, func() { spilled = true }, maxNumberPartitions, queueCfg,
+						)
+						memAccounts = append(memAccounts, accounts...)
+						memMonitors = append(memMonitors, monitors...)
+						return op, err
+					})
+				if tk.ForceDiskSpill || tk.MemoryLimitBytes == 1 {
+					require.True(t, spilled, "expected the hash aggregator to spill to disk")
+				}
+			})
+		}
+	}
+	for _, account := range memAccounts {
+		account.Close(ctx)
+	}
+	for _, monitor := range memMonitors {
+		monitor.Stop(ctx)
+	}
+}
+
+// generateRandomDataForTestExternalHashAggregator returns nTups random tuples
+// of two columns, with the first column taking numGroups distinct values, as
+// well as the expected output of "SELECT ANY_NOT_NULL(@1), SUM_INT(@2),
+// COUNT_ROWS() GROUP BY @1" (in no particular order).
+func generateRandomDataForTestExternalHashAggregator(
+	rng *rand.Rand, nTups, numGroups int,
+) (tups, expected tuples) {
+	type groupResult struct {
+		sum       int64
+		sumIsNull bool
+		count     int64
+	}
+	results := make(map[int64]*groupResult)
+	var groups []int64
+	tups = make(tuples, nTups)
+	for i := range tups {
+		group := int64(rng.Intn(numGroups))
+		res, ok := results[group]
+		if !ok {
+			res = &groupResult{sumIsNull: true}
+			results[group] = res
+			groups = append(groups, group)
+		}
+		res.count++
+		if rng.Float64() < nullProbability {
+			tups[i] = tuple{group, nil}
+			continue
+		}
+		val := rng.Int63() % 2048
+		res.sum += val
+		res.sumIsNull = false
+		tups[i] = tuple{group, val}
+	}
+	expected = make(tuples, len(groups))
+	for i, group := range groups {
+		res := results[group]
+		var sum interface{}
+		if !res.sumIsNull {
+			sum = res.sum
+		}
+		expected[i] = tuple{group, sum, res.count}
+	}
+	return tups, expected
+}
+
+// createDiskBackedHashAggregator is a helper function that instantiates a
+// disk-backed hash aggregator computing "ANY_NOT_NULL(@1), SUM_INT(@2),
+// COUNT_ROWS() GROUP BY @1" on top of the input of two integer columns. The
+// desired memory limit must have been already set on flowCtx. It returns an
+// operator and an error as well as memory monitors and memory accounts that
+// will need to be closed once the caller is done with the operator.
+func createDiskBackedHashAggregator(
+	ctx context.Context,
+	flowCtx *execinfra.FlowCtx,
+	input []Operator,
+	spillingCallbackFn func(),
+	maxNumberPartitions int,
+	queueCfg colcontainer.DiskQueueCfg,
+) (Operator, []*mon.BoundAccount, []*mon.BytesMonitor, error) {
+	spec := &execinfrapb.ProcessorSpec{
+		Input: []execinfrapb.InputSyncSpec{{ColumnTypes: []types.T{*types.Int, *types.Int}}},
+		Core: execinfrapb.ProcessorCoreUnion{
+			Aggregator: &execinfrapb.AggregatorSpec{
+				GroupCols: []uint32{0},
+				Aggregations: []execinfrapb.AggregatorSpec_Aggregation{
+					{Func: execinfrapb.AggregatorSpec_ANY_NOT_NULL, ColIdx: []uint32{0}},
+					{Func: execinfrapb.AggregatorSpec_SUM_INT, ColIdx: []uint32{1}},
+					{Func: execinfrapb.AggregatorSpec_COUNT_ROWS},
+				},
+			},
+		},
+	}
+	args := NewColOperatorArgs{
+		Spec:                spec,
+		Inputs:              input,
+		StreamingMemAccount: testMemAcc,
+		DiskQueueCfg:        queueCfg,
+	}
+	// The external hash aggregator relies on the memory limit of the
+	// buffering memory accounts to understand when to spill, so we will not
+	// use the streaming memory account.
+	args.TestingKnobs.SpillingCallbackFn = spillingCallbackFn
+	args.TestingKnobs.MaxNumberPartitions = maxNumberPartitions
+	result, err := NewColOperator(ctx, flowCtx, args)
+	return result.Op, result.BufferingOpMemAccounts, result.BufferingOpMemMonitors, err
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/colcontainer"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/errors"
 )
 
 // Partitioner is the abstraction for on-disk storage.
@@ -450,6 +451,99 @@ func (d *dummyPartitioner) Close() error {
 	return nil
 }
 
+// newDiskQueuePartitioner returns a Partitioner that stores every partition in
+// a separate disk queue created with cfg. All batches of a partition must be
+// enqueued before the first batch is dequeued from it.
+func newDiskQueuePartitioner(types []coltypes.T, cfg colcontainer.DiskQueueCfg) Partitioner {
+	return &diskQueuePartitioner{types: types, cfg: cfg}
+}
+
+// diskQueuePartition is a single partition of diskQueuePartitioner.
+type diskQueuePartition struct {
+	// queue is nil if nothing has been enqueued into the partition or if it
+	// has been fully dequeued (and its files have been removed).
+	queue colcontainer.Queue
+	// readStarted indicates whether the partition has been dequeued from, at
+	// which point no more batches can be enqueued into it.
+	readStarted bool
+}
+
+// diskQueuePartitioner is an implementation of Partitioner interface that
+// spills all partitions to disk using colcontainer.Queue's. The files of a
+// partition are removed as soon as it has been fully dequeued.
+type diskQueuePartitioner struct {
+	types      []coltypes.T
+	cfg        colcontainer.DiskQueueCfg
+	partitions []diskQueuePartition
+}
+
+var _ Partitioner = &diskQueuePartitioner{}
+
+func (d *diskQueuePartitioner) Enqueue(partitionIdx int, batch coldata.Batch) error {
+	if batch.Length() == 0 {
+		return nil
+	}
+	if len(d.partitions) <= partitionIdx {
+		d.partitions = append(d.partitions, make([]diskQueuePartition, partitionIdx-len(d.partitions)+1)...)
+	}
+	partition := &d.partitions[partitionIdx]
+	if partition.readStarted {
+		return errors.AssertionFailedf("enqueueing into partition %d after it has been dequeued from", partitionIdx)
+	}
+	if partition.queue == nil {
+		q, err := colcontainer.NewDiskQueue(d.types, d.cfg)
+		if err != nil {
+			return err
+		}
+		partition.queue = q
+	}
+	return partition.queue.Enqueue(batch)
+}
+
+func (d *diskQueuePartitioner) Dequeue(partitionIdx int, batch coldata.Batch) error {
+	if partitionIdx >= len(d.partitions) || d.partitions[partitionIdx].queue == nil {
+		batch.SetLength(0)
+		return nil
+	}
+	partition := &d.partitions[partitionIdx]
+	if !partition.readStarted {
+		// A zero-length batch tells the queue that nothing else will be
+		// enqueued, so that all the batches can be read back.
+		if err := partition.queue.Enqueue(coldata.ZeroBatch); err != nil {
+			return err
+		}
+		partition.readStarted = true
+	}
+	ok, err := partition.queue.Dequeue(batch)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.AssertionFailedf("partition %d unexpectedly has no data to dequeue", partitionIdx)
+	}
+	if batch.Length() == 0 {
+		// The partition has been fully dequeued, so we remove its files right
+		// away rather than when the whole partitioner is closed.
+		err = partition.queue.Close()
+		partition.queue = nil
+	}
+	return err
+}
+
+func (d *diskQueuePartitioner) Close() error {
+	var retErr error
+	for i := range d.partitions {
+		if d.partitions[i].queue == nil {
+			continue
+		}
+		if err := d.partitions[i].queue.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+		d.partitions[i].queue = nil
+	}
+	return retErr
+}
+
 func newDummyQueue(allocator *Allocator, types []coltypes.T) *dummyQueue {
 	return &dummyQueue{allocator: allocator, types: types}
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/errors"
 )
@@ -50,22 +51,31 @@ import (
 // assume usage in the ordered aggregator paradigm, which is why it hasn't been
 // tried yet.
 
-// NewHashAggregator creates a hash aggregator on the given grouping
-// columns. The input specifications to this function are the same as that of
-// the NewOrderedAggregator function.
+// NewHashAggregator creates a hash aggregator described by spec. The input
+// specifications to this function are the same as that of the
+// NewOrderedAggregator function except that the input doesn't need to be
+// ordered.
 func NewHashAggregator(
 	allocator *Allocator,
 	input Operator,
 	colTypes []coltypes.T,
-	aggFns []execinfrapb.AggregatorSpec_Func,
-	groupCols []uint32,
-	aggCols [][]uint32,
-	isScalar bool,
+	spec *execinfrapb.AggregatorSpec,
+	constArguments []tree.Datums,
 ) (Operator, error) {
+	if constArguments != nil && len(constArguments) != len(spec.Aggregations) {
+		return nil,
+			errors.Errorf(
+				"mismatched aggregation lengths: aggregations(%d), constArguments(%d)",
+				len(spec.Aggregations),
+				len(constArguments),
+			)
+	}
+
+	aggFns, aggCols := extractAggFnsAndCols(spec)
 	aggTyps := extractAggTypes(aggCols, colTypes)
 
 	// Only keep relevant output columns, those that are used as input to an
-	// aggregation.
+	// aggregation (either as an argument or as a filter).
 	nCols := uint32(len(colTypes))
 	var keepCol util.FastIntSet
 
@@ -79,6 +89,11 @@ func NewHashAggregator(
 			keepCol.Add(int(col))
 		}
 	}
+	for _, agg := range spec.Aggregations {
+		if agg.FilterColIdx != nil {
+			keepCol.Add(int(*agg.FilterColIdx))
+		}
+	}
 
 	// Map the corresponding aggCols to the new output column indices.
 	nOutCols := uint32(0)
@@ -101,25 +116,35 @@ func NewHashAggregator(
 		allocator,
 		hashTableNumBuckets,
 		colTypes,
-		groupCols,
+		spec.GroupCols,
 		outCols,
 		true, /* allowNullEquality */
 	)
 
-	funcs, outTyps, err := makeAggregateFuncs(allocator, aggTyps, aggFns)
+	funcs, outTyps, err := makeAggregateFuncs(allocator, aggTyps, aggFns, constArguments)
 	if err != nil {
 		return nil, errors.AssertionFailedf(
 			"this error should have been checked in isAggregateSupported\n%+v", err,
 		)
 	}
 
+	argFilters, err := makeAggArgFilters(allocator, spec, aggTyps)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range argFilters {
+		if f != nil && f.filterColIdx >= 0 {
+			f.filterColIdx = int(compressed[f.filterColIdx])
+		}
+	}
+
 	distinctCol := make([]bool, coldata.BatchSize())
 
 	grouper := &hashGrouper{
 		OneInputNode: NewOneInputNode(input),
 		ht:           ht,
+		colTypes:     colTypes,
 		distinctCol:  distinctCol,
-		batch:        allocator.NewMemBatch(ht.outTypes),
 	}
 
 	orderedAgg := &orderedAggregator{
@@ -129,8 +154,9 @@ func NewHashAggregator(
 		aggTypes:       aggTyps,
 		groupCol:       distinctCol,
 		aggregateFuncs: funcs,
+		argFilters:     argFilters,
 		outputTypes:    outTyps,
-		isScalar:       isScalar,
+		isScalar:       execinfrapb.IsScalarAggregate(spec),
 	}
 
 	return orderedAgg, nil
@@ -144,7 +170,8 @@ func NewHashAggregator(
 type hashGrouper struct {
 	OneInputNode
 
-	ht *hashTable
+	ht       *hashTable
+	colTypes []coltypes.T
 
 	// sel is an ordered list of indices to select representing the input rows.
 	// This selection vector is much bigger than coldata.BatchSize() and should be
@@ -162,15 +189,28 @@ type hashGrouper struct {
 	batch      coldata.Batch
 
 	buildFinished bool
+
+	exportBufferedState struct {
+		windowedBatch coldata.Batch
+		exported      uint64
+	}
 }
 
-var _ Operator = &hashGrouper{}
+var _ bufferingInMemoryOperator = &hashGrouper{}
 
 func (op *hashGrouper) Init() {
 	op.input.Init()
 }
 
 func (op *hashGrouper) Next(ctx context.Context) coldata.Batch {
+	if op.batch == nil {
+		// We allocate the batches lazily so that the hash aggregator that runs
+		// out of memory right away could still fall back to the disk-backed one.
+		// Note that nothing has been buffered at this point, so the export batch
+		// will not be needed if either of these allocations fails.
+		op.exportBufferedState.windowedBatch = op.ht.newExportBatch(op.colTypes)
+		op.batch = op.ht.allocator.NewMemBatch(op.ht.outTypes)
+	}
 	op.batch.ResetInternalBatch()
 	// First, build the hash table.
 	if !op.buildFinished {
@@ -254,12 +294,31 @@ func (op *hashGrouper) Next(ctx context.Context) coldata.Batch {
 	return op.batch
 }
 
+// ExportBuffered returns the tuples that have been buffered in the hash table
+// so far. The memory limit can only be reached while the hash table is being
+// built, so none of the buffered tuples have been emitted yet.
+func (op *hashGrouper) ExportBuffered(Operator) coldata.Batch {
+	if op.exportBufferedState.exported == op.ht.vals.length {
+		return coldata.ZeroBatch
+	}
+	newExported := op.exportBufferedState.exported + uint64(coldata.BatchSize())
+	if newExported > op.ht.vals.length {
+		newExported = op.ht.vals.length
+	}
+	b := op.ht.exportVals(
+		op.exportBufferedState.windowedBatch, op.exportBufferedState.exported, newExported,
+	)
+	op.exportBufferedState.exported = newExported
+	return b
+}
+
 // Reset resets the hashGrouper for another run. Primarily used for
 // benchmarks.
 func (op *hashGrouper) reset() {
 	op.batchStart = 0
 	op.ht.vals.reset()
 	op.buildFinished = false
+	op.exportBufferedState.exported = 0
 }
 
 var _ Operator = &hashGrouper{}
@@ -0,0 +1,344 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/colcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// hashBasedPartitionerState indicates the current state of the hash-based
+// partitioner.
+type hashBasedPartitionerState int
+
+const (
+	// hbpInitialPartitioning indicates that the operator is currently reading
+	// batches from its input and distributing tuples to different partitions
+	// based on the hash values. Once the input is exhausted, the operator
+	// transitions to hbpProcessNewPartition state.
+	hbpInitialPartitioning hashBasedPartitionerState = iota
+	// hbpProcessNewPartition indicates that the operator should pick the next
+	// partition to process and set up either the in-memory "main" operator or
+	// the disk-backed fallback operator on top of it. If there are no
+	// partitions left, the operator transitions to hbpFinished state.
+	hbpProcessNewPartition
+	// hbpProcessingUsingMain indicates that the operator is currently emitting
+	// the output of the in-memory main operator for the current partition. If
+	// the main operator hits its memory limit, the operator transitions to
+	// hbpRepartitioning state.
+	hbpProcessingUsingMain
+	// hbpRepartitioning indicates that the current partition didn't fit into
+	// memory, so all of its tuples (both the ones buffered by the main
+	// operator and the ones that haven't been read yet) need to be distributed
+	// into new partitions using a different hash function.
+	hbpRepartitioning
+	// hbpProcessingUsingFallback indicates that the operator is currently
+	// emitting the output of the disk-backed fallback operator for the current
+	// partition.
+	hbpProcessingUsingFallback
+	// hbpFinished indicates that the operator has emitted all tuples already
+	// and only zero-length batch will be emitted from now on.
+	hbpFinished
+)
+
+// TODO(yuzefovich): make this tunable. Ideally, we would calculate this number
+// based on the cardinality of the input.
+const hbpDefaultNumPartitions = 16
+
+// hbpPartitionInfo describes a single partition of the hash-based
+// partitioner.
+type hbpPartitionInfo struct {
+	idx int
+	// level is the number of times the tuples of this partition have been
+	// repartitioned. It determines the hash function that is used if the
+	// partition needs to be repartitioned again.
+	level     int
+	numTuples int
+	// useFallback indicates whether the partition should be processed by the
+	// fallback operator because the repartitioning didn't make it smaller.
+	useFallback bool
+}
+
+// hashBasedPartitioner is a one-input operator that can spill to disk and is
+// used by the external hash aggregator and the external unordered distinct.
+// The high level view is that it partitions the input into buckets by a hash
+// function A on hashCols, writes those buckets to disk, and then processes
+// each of the buckets separately by a fresh in-memory "main" operator (that
+// uses a different hash function B internally). Since all tuples that are
+// equal on hashCols end up in the same partition, the union of the outputs of
+// the main operators is the output of the whole operator.
+//
+// In order to get different hash functions, we're using the same family of
+// hash functions that in-memory hash table uses, but we will seed it with a
+// different initial hash value.
+//
+// If the main operator runs out of memory while processing a partition, all
+// tuples of that partition are recursively repartitioned with yet another hash
+// function. If the repartitioning doesn't make the partition smaller (which
+// happens when all tuples in the partition are equal on hashCols, or, rarely,
+// due to hash collisions), the partition is processed by the disk-backed
+// fallback operator instead.
+type hashBasedPartitioner struct {
+	OneInputNode
+	NonExplainable
+
+	state              hashBasedPartitionerState
+	unlimitedAllocator *Allocator
+	inputTypes         []coltypes.T
+	hashCols           []uint32
+
+	// inMemMainOpAllocator is the allocator used by the main operators. It
+	// must have been created with a memory account derived from a limited
+	// memory monitor with inMemMainOpMonitorName name.
+	inMemMainOpAllocator   *Allocator
+	inMemMainOpMonitorName string
+	inMemMainOpConstructor func(*Allocator, Operator) bufferingInMemoryOperator
+	// diskBackedFallbackOpConstructor returns the operator that processes the
+	// partitions that cannot be made smaller by repartitioning. The operators
+	// returned by it are used one at a time.
+	diskBackedFallbackOpConstructor func(input Operator) Operator
+
+	partitioner   Partitioner
+	numPartitions int
+	// numPartitionsCreated is the total number of partitions that have been
+	// created so far. It is used to assign indices to new partitions.
+	numPartitionsCreated int
+	// partitionsToProcess is a stack of non-empty partitions that haven't been
+	// processed yet.
+	partitionsToProcess []hbpPartitionInfo
+	scratchBatch        coldata.Batch
+
+	// curPartition is the partition that is currently being processed.
+	curPartition hbpPartitionInfo
+	// partitionInput reads the tuples of curPartition.
+	partitionInput *partitionerToOperator
+	inMemMainOp    bufferingInMemoryOperator
+	fallbackOp     Operator
+}
+
+var _ Operator = &hashBasedPartitioner{}
+
+// newHashBasedPartitioner returns a new hashBasedPartitioner.
+// - unlimitedAllocator must have been created with a memory account derived
+// from an unlimited memory monitor. It is used for the internal scratch
+// batches.
+// - inMemMainOpAllocator and inMemMainOpMonitorName describe the memory
+// account that the main operators use, and the memory of this account is
+// released every time a partition has been processed.
+// - maxNumberPartitions (when non-zero) overrides the number of partitions
+// that every partition is split into. It should be non-zero only in tests.
+// - diskQueueCfg is used to create the disk queues that the partitions are
+// spilled to.
+func newHashBasedPartitioner(
+	unlimitedAllocator *Allocator,
+	inMemMainOpAllocator *Allocator,
+	inMemMainOpMonitorName string,
+	input Operator,
+	inputTypes []coltypes.T,
+	hashCols []uint32,
+	inMemMainOpConstructor func(*Allocator, Operator) bufferingInMemoryOperator,
+	diskBackedFallbackOpConstructor func(input Operator) Operator,
+	maxNumberPartitions int,
+	diskQueueCfg colcontainer.DiskQueueCfg,
+) *hashBasedPartitioner {
+	numPartitions := maxNumberPartitions
+	if numPartitions == 0 {
+		numPartitions = hbpDefaultNumPartitions
+	}
+	// In order to make progress when repartitioning we have to have at least
+	// two partitions.
+	if numPartitions < 2 {
+		numPartitions = 2
+	}
+	partitioner := newDiskQueuePartitioner(inputTypes, diskQueueCfg)
+	return &hashBasedPartitioner{
+		OneInputNode:                    NewOneInputNode(input),
+		unlimitedAllocator:              unlimitedAllocator,
+		inputTypes:                      inputTypes,
+		hashCols:                        hashCols,
+		inMemMainOpAllocator:            inMemMainOpAllocator,
+		inMemMainOpMonitorName:          inMemMainOpMonitorName,
+		inMemMainOpConstructor:          inMemMainOpConstructor,
+		diskBackedFallbackOpConstructor: diskBackedFallbackOpConstructor,
+		partitioner:                     partitioner,
+		numPartitions:                   numPartitions,
+		scratchBatch:                    unlimitedAllocator.NewMemBatch(inputTypes),
+		partitionInput: newPartitionerToOperator(
+			unlimitedAllocator, inputTypes, partitioner, 0, /* partitionIdx */
+		),
+	}
+}
+
+func (op *hashBasedPartitioner) Init() {
+	op.input.Init()
+	op.state = hbpInitialPartitioning
+}
+
+// distribute consumes the source fully and distributes all of its tuples
+// into op.numPartitions new partitions using the hash function determined by
+// level. The new non-empty partitions are pushed onto partitionsToProcess
+// stack.
+func (op *hashBasedPartitioner) distribute(ctx context.Context, source Operator, level int) {
+	// The main operators will use the default init hash value, so in order to
+	// use a "different" hash function on each level of partitioning we use
+	// different init hash values.
+	tupleDistributor := newTupleHashDistributor(
+		defaultInitHashValue+1+uint64(level), op.numPartitions,
+	)
+	firstPartitionIdx := op.numPartitionsCreated
+	op.numPartitionsCreated += op.numPartitions
+	numTuples := make([]int, op.numPartitions)
+	for batch := source.Next(ctx); batch.Length() > 0; batch = source.Next(ctx) {
+		selections := tupleDistributor.distribute(ctx, batch, op.inputTypes, op.hashCols)
+		for partitionIdx, sel := range selections {
+			if len(sel) == 0 {
+				continue
+			}
+			op.scratchBatch.ResetInternalBatch()
+			// The partitioner expects the batches without a selection vector, so
+			// we need to copy the tuples according to the selection vector into a
+			// scratch batch.
+			op.unlimitedAllocator.PerformOperation(op.scratchBatch.ColVecs(), func() {
+				for i, colvec := range op.scratchBatch.ColVecs() {
+					colvec.Copy(coldata.CopySliceArgs{
+						SliceArgs: coldata.SliceArgs{
+							ColType:   op.inputTypes[i],
+							Src:       batch.ColVec(i),
+							Sel:       sel,
+							SrcEndIdx: uint64(len(sel)),
+						},
+					})
+				}
+				op.scratchBatch.SetLength(uint16(len(sel)))
+			})
+			if err := op.partitioner.Enqueue(firstPartitionIdx+partitionIdx, op.scratchBatch); err != nil {
+				execerror.VectorizedInternalPanic(err)
+			}
+			numTuples[partitionIdx] += len(sel)
+		}
+	}
+	// We push the partitions in the reverse order so that they are processed
+	// in the order of their indices.
+	for i := op.numPartitions - 1; i >= 0; i-- {
+		if numTuples[i] > 0 {
+			op.partitionsToProcess = append(op.partitionsToProcess, hbpPartitionInfo{
+				idx:       firstPartitionIdx + i,
+				level:     level,
+				numTuples: numTuples[i],
+			})
+		}
+	}
+}
+
+// releaseInMemMainOp releases all of the memory registered with the allocator
+// of the current main operator. It is safe to do so because the main operator
+// is never used again.
+func (op *hashBasedPartitioner) releaseInMemMainOp() {
+	op.inMemMainOp = nil
+	op.inMemMainOpAllocator.Clear()
+}
+
+func (op *hashBasedPartitioner) Next(ctx context.Context) coldata.Batch {
+	for {
+		switch op.state {
+		case hbpInitialPartitioning:
+			op.distribute(ctx, op.input, 0 /* level */)
+			op.state = hbpProcessNewPartition
+
+		case hbpProcessNewPartition:
+			if len(op.partitionsToProcess) == 0 {
+				// All partitions have been processed, so we clean up the disk
+				// infrastructure and transition to finished state.
+				if err := op.partitioner.Close(); err != nil {
+					execerror.VectorizedInternalPanic(err)
+				}
+				op.state = hbpFinished
+				continue
+			}
+			op.curPartition = op.partitionsToProcess[len(op.partitionsToProcess)-1]
+			op.partitionsToProcess = op.partitionsToProcess[:len(op.partitionsToProcess)-1]
+			op.partitionInput.partitionIdx = op.curPartition.idx
+			if op.curPartition.useFallback {
+				op.fallbackOp = op.diskBackedFallbackOpConstructor(op.partitionInput)
+				op.fallbackOp.Init()
+				op.state = hbpProcessingUsingFallback
+				continue
+			}
+			op.inMemMainOp = op.inMemMainOpConstructor(op.inMemMainOpAllocator, op.partitionInput)
+			op.inMemMainOp.Init()
+			op.state = hbpProcessingUsingMain
+
+		case hbpProcessingUsingMain:
+			var b coldata.Batch
+			if err := execerror.CatchVectorizedRuntimeError(
+				func() {
+					b = op.inMemMainOp.Next(ctx)
+				},
+			); err != nil {
+				if sqlbase.IsOutOfMemoryError(err) &&
+					strings.Contains(err.Error(), op.inMemMainOpMonitorName) {
+					op.state = hbpRepartitioning
+					continue
+				}
+				// Either not an out of memory error or an OOM error coming from a
+				// different operator, so we propagate it further.
+				execerror.VectorizedInternalPanic(err)
+			}
+			if b.Length() == 0 {
+				op.releaseInMemMainOp()
+				op.state = hbpProcessNewPartition
+				continue
+			}
+			return b
+
+		case hbpRepartitioning:
+			// The main operator buffers up the tuples it has read from
+			// partitionInput, so we need to export those first and then proceed
+			// to reading the rest of the partition.
+			numPartitionsToProcess := len(op.partitionsToProcess)
+			op.distribute(
+				ctx, newBufferExportingOperator(op.inMemMainOp, op.partitionInput),
+				op.curPartition.level+1,
+			)
+			for i := numPartitionsToProcess; i < len(op.partitionsToProcess); i++ {
+				if op.partitionsToProcess[i].numTuples == op.curPartition.numTuples {
+					// All tuples ended up in the same partition, so further
+					// repartitioning is unlikely to help.
+					op.partitionsToProcess[i].useFallback = true
+				}
+			}
+			op.releaseInMemMainOp()
+			op.state = hbpProcessNewPartition
+
+		case hbpProcessingUsingFallback:
+			b := op.fallbackOp.Next(ctx)
+			if b.Length() == 0 {
+				op.fallbackOp = nil
+				op.state = hbpProcessNewPartition
+				continue
+			}
+			return b
+
+		case hbpFinished:
+			return coldata.ZeroBatch
+
+		default:
+			execerror.VectorizedInternalPanic(fmt.Sprintf("unexpected hashBasedPartitionerState %d", op.state))
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/testutils/colcontainerutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHashBasedPartitionerSpillsToDisk verifies that the partitions of the
+// hash-based partitioner are written to the temporary storage directory and
+// that their files are removed once all of them have been processed.
+func TestHashBasedPartitionerSpillsToDisk(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	queueCfg, cleanup := colcontainerutils.NewTestingDiskQueueCfg(t, true /* inMem */)
+	defer cleanup()
+
+	nTups := int(coldata.BatchSize())*4 + 1
+	tups := make(tuples, nTups)
+	for i := range tups {
+		tups[i] = tuple{int64(i), int64(i % 3)}
+	}
+	typs := []coltypes.T{coltypes.Int64, coltypes.Int64}
+	op := newExternalDistinct(
+		testAllocator, testAllocator, "test-limited",
+		newOpTestInput(coldata.BatchSize(), tups, typs), []uint32{0, 1}, typs,
+		func(Operator, []uint32) Operator {
+			t.Fatal("unexpected use of the fallback operator")
+			return nil
+		},
+		0 /* maxNumberPartitions */, queueCfg,
+	)
+	op.Init()
+
+	// The first call to Next distributes the whole input into the partitions,
+	// so all of them must be on disk at this point.
+	b := op.Next(ctx)
+	require.True(t, b.Length() > 0)
+	dirs, err := queueCfg.FS.ListDir(queueCfg.Path)
+	require.NoError(t, err)
+	require.NotEmpty(t, dirs, "expected the partitions to be spilled to disk")
+	for _, dir := range dirs {
+		files, err := queueCfg.FS.ListDir(filepath.Join(queueCfg.Path, dir))
+		require.NoError(t, err)
+		require.NotEmpty(t, files, "expected partition %s to have files on disk", dir)
+	}
+
+	numTuples := int(b.Length())
+	for b = op.Next(ctx); b.Length() > 0; b = op.Next(ctx) {
+		numTuples += int(b.Length())
+	}
+	require.Equal(t, nTups, numTuples)
+
+	dirs, err = queueCfg.FS.ListDir(queueCfg.Path)
+	require.NoError(t, err)
+	require.Empty(t, dirs, "expected the files of all partitions to be removed")
+}
@@ -52,6 +52,13 @@ type hashJoinerSpec struct {
 	// rightDistinct indicates whether or not the build table equality column
 	// tuples are distinct. If they are distinct, performance can be optimized.
 	rightDistinct bool
+
+	// onExprFilterConstructor, if not nil, plans the filter that evaluates the
+	// ON expression on the joined tuples. It is only used with LEFT, RIGHT, and
+	// FULL OUTER joins since the tuples that don't satisfy the ON expression
+	// must be NULL-extended. (In case of INNER join, the filter is planned on
+	// top of the hash joiner.)
+	onExprFilterConstructor func(Operator) (Operator, error)
 }
 
 type hashJoinerSourceSpec struct {
@@ -155,6 +162,14 @@ type hashJoinerSourceSpec struct {
 // emitUnmatched is performed after the probing ends. This is done by gathering
 // all build table rows that have never been matched and stitching it together
 // with NULL values on the probe side.
+//
+// In the case that an outer join has an ON expression, only the matching rows
+// are collected, and the ON expression is evaluated on the joined tuples
+// afterwards. Only the tuples that satisfy the expression are emitted, and
+// only such tuples mark the probe and build rows as matched. Once all joined
+// tuples for the probe batch have been emitted, the probe rows without a match
+// are emitted with NULL values on the build side (in the case of an outer join
+// on the probe side).
 type hashJoiner struct {
 	twoInputNode
 
@@ -172,6 +187,11 @@ type hashJoiner struct {
 	// outputBatchSize specifies the desired length of the output batch which by
 	// default is coldata.BatchSize() but can be varied in tests.
 	outputBatchSize uint16
+	// onExprFilter, if not nil, evaluates the ON expression of an outer join.
+	onExprFilter *joinerBatchFilter
+	// outputColIdxs are the indices of the columns in output that are passed
+	// to onExprFilter.
+	outputColIdxs []int
 
 	// probeState is used in hjProbing state.
 	probeState struct {
@@ -205,6 +225,24 @@ type hashJoiner struct {
 		// collection from. It is used only in case of non-distinct build source
 		// (every probe row can have multiple matching build rows).
 		prevBatchResumeIdx uint16
+
+		// The following fields are used only in case of an outer join on the
+		// probe side with an ON expression.
+		//
+		// probeRowMatched marks (by the physical indices) the rows of the current
+		// probe batch that have at least one match that satisfies the ON
+		// expression.
+		probeRowMatched []bool
+		// unmatchedProbeBatch, if not nil, indicates that all of the joined
+		// tuples have been emitted for this probe batch, but the rows from it
+		// without a match haven't been (fully) emitted yet.
+		unmatchedProbeBatch coldata.Batch
+		// unmatchedProbeResumeIdx indicates the index of the probe row to resume
+		// emitting the unmatched rows from.
+		unmatchedProbeResumeIdx uint16
+		// filterSel is the scratch space for the indices of the joined tuples on
+		// which the ON expression is evaluated.
+		filterSel []uint16
 	}
 
 	// emittingUnmatchedState is used in hjEmittingUnmatched state.
@@ -224,6 +262,9 @@ var _ resetter = &hashJoiner{}
 func (hj *hashJoiner) Init() {
 	hj.inputOne.Init()
 	hj.inputTwo.Init()
+	if hj.onExprFilter != nil {
+		hj.onExprFilter.init()
+	}
 
 	hj.ht = newHashTable(
 		hj.allocator,
@@ -345,23 +386,28 @@ func (hj *hashJoiner) emitUnmatched() {
 func (hj *hashJoiner) exec(ctx context.Context) {
 	hj.output.SetLength(0)
 
-	if batch := hj.probeState.prevBatch; batch != nil {
-		// The previous result was bigger than the maximum batch size, so we didn't
-		// finish outputting it in the last call to probe. Continue outputting the
-		// result from the previous batch.
-		hj.probeState.prevBatch = nil
-		batchSize := batch.Length()
-		sel := batch.Selection()
+	for {
+		if hj.probeState.unmatchedProbeBatch != nil {
+			// All joined tuples of the previous probe batch have been emitted, and
+			// now we need to emit the probe rows that didn't have a match
+			// satisfying the ON expression.
+			hj.emitUnmatchedProbeRows()
+		} else if batch := hj.probeState.prevBatch; batch != nil {
+			// The previous result was bigger than the maximum batch size, so we didn't
+			// finish outputting it in the last call to probe. Continue outputting the
+			// result from the previous batch.
+			hj.probeState.prevBatch = nil
+			batchSize := batch.Length()
+			sel := batch.Selection()
 
-		nResults := hj.collect(batch, batchSize, sel)
-		hj.congregate(nResults, batch, batchSize)
-	} else {
-		for {
+			nResults := hj.collect(batch, batchSize, sel)
+			hj.congregate(ctx, nResults, batch, batchSize)
+		} else {
 			batch := hj.inputOne.Next(ctx)
 			batchSize := batch.Length()
 
 			if batchSize == 0 {
-				break
+				return
 			}
 
 			for i, colIdx := range hj.spec.left.eqCols {
@@ -407,6 +453,12 @@ func (hj *hashJoiner) exec(ctx context.Context) {
 				nToCheck = batchSize
 			}
 
+			if hj.probeState.probeRowMatched != nil {
+				// We're processing a new batch, so we need to reset the matches from
+				// the previous one.
+				copy(hj.probeState.probeRowMatched, zeroBoolColumn)
+			}
+
 			var nResults uint16
 
 			if hj.spec.rightDistinct {
@@ -433,11 +485,11 @@ func (hj *hashJoiner) exec(ctx context.Context) {
 				nResults = hj.collect(batch, batchSize, sel)
 			}
 
-			hj.congregate(nResults, batch, batchSize)
+			hj.congregate(ctx, nResults, batch, batchSize)
+		}
 
-			if hj.output.Length() > 0 {
-				break
-			}
+		if hj.output.Length() > 0 {
+			return
 		}
 	}
 }
@@ -445,7 +497,9 @@ func (hj *hashJoiner) exec(ctx context.Context) {
 // congregate uses the probeIdx and buildIdx pairs to stitch together the
 // resulting join rows and add them to the output batch with the left table
 // columns preceding the right table columns.
-func (hj *hashJoiner) congregate(nResults uint16, batch coldata.Batch, batchSize uint16) {
+func (hj *hashJoiner) congregate(
+	ctx context.Context, nResults uint16, batch coldata.Batch, batchSize uint16,
+) {
 	// NOTE: Copy() calls are not accounted for because we don't want for memory
 	// limit error to occur at this point - we have already built the hash
 	// table and now are only consuming the left source one batch at a time,
@@ -480,7 +534,7 @@ func (hj *hashJoiner) congregate(nResults uint16, batch coldata.Batch, batchSize
 			)
 		}
 	}
-	if hj.spec.left.outer {
+	if hj.spec.left.outer && hj.onExprFilter == nil {
 		// Add in the nulls we needed to set for the outer join.
 		for outColIdx := range hj.ht.outCols {
 			outCol := hj.output.ColVec(outColIdx + rightColOffset)
@@ -511,6 +565,13 @@ func (hj *hashJoiner) congregate(nResults uint16, batch coldata.Batch, batchSize
 		)
 	}
 
+	hj.output.SetLength(nResults)
+
+	if hj.onExprFilter != nil {
+		hj.applyOnExprFilter(ctx, nResults, batch)
+		return
+	}
+
 	if hj.spec.right.outer {
 		// In order to determine which rows to emit for the outer join on the build
 		// table in the end, we need to mark the matched build table rows.
@@ -526,7 +587,83 @@ func (hj *hashJoiner) congregate(nResults uint16, batch coldata.Batch, batchSize
 			}
 		}
 	}
+}
+
+// applyOnExprFilter evaluates the ON expression on the nResults joined tuples
+// that have just been put into the output batch and updates the selection
+// vector of the output so that only the tuples satisfying the expression are
+// emitted. Only such tuples mark the probe and build rows as matched. If all
+// joined tuples for the probe batch have been collected, and an outer join on
+// the probe side is performed, it sets up the emission of the unmatched probe
+// rows of batch.
+func (hj *hashJoiner) applyOnExprFilter(ctx context.Context, nResults uint16, batch coldata.Batch) {
+	sel := hj.probeState.filterSel[:nResults]
+	for i := range sel {
+		sel[i] = uint16(i)
+	}
+	sel = hj.onExprFilter.filterBatch(ctx, hj.output, hj.outputColIdxs, sel)
+	for _, i := range sel {
+		if hj.spec.left.outer {
+			hj.probeState.probeRowMatched[hj.probeState.probeIdx[i]] = true
+		}
+		if hj.spec.right.outer {
+			hj.probeState.buildRowMatched[hj.probeState.buildIdx[i]] = true
+		}
+	}
+	if len(sel) < int(nResults) {
+		hj.output.SetSelection(true)
+		copy(hj.output.Selection(), sel)
+		hj.output.SetLength(uint16(len(sel)))
+	}
+	if hj.spec.left.outer && hj.probeState.prevBatch == nil {
+		hj.probeState.unmatchedProbeBatch = batch
+		hj.probeState.unmatchedProbeResumeIdx = 0
+	}
+}
+
+// emitUnmatchedProbeRows emits the rows of the current probe batch that
+// didn't have a match satisfying the ON expression with NULL values on the
+// build side.
+func (hj *hashJoiner) emitUnmatchedProbeRows() {
+	batch := hj.probeState.unmatchedProbeBatch
+	batchSize := batch.Length()
+	sel := batch.Selection()
+	nResults := uint16(0)
+	i := hj.probeState.unmatchedProbeResumeIdx
+	for ; i < batchSize && nResults < hj.outputBatchSize; i++ {
+		rowIdx := i
+		if sel != nil {
+			rowIdx = sel[i]
+		}
+		if !hj.probeState.probeRowMatched[rowIdx] {
+			hj.probeState.probeIdx[nResults] = rowIdx
+			nResults++
+		}
+	}
+	if i == batchSize {
+		hj.probeState.unmatchedProbeBatch = nil
+	} else {
+		hj.probeState.unmatchedProbeResumeIdx = i
+	}
 
+	// NOTE: this Copy is not accounted for for the same reasons as the Copy
+	// calls in congregate.
+	for outColIdx, inColIdx := range hj.spec.left.outCols {
+		hj.output.ColVec(outColIdx).Copy(
+			coldata.CopySliceArgs{
+				SliceArgs: coldata.SliceArgs{
+					ColType:   hj.spec.left.sourceTypes[inColIdx],
+					Src:       batch.ColVec(int(inColIdx)),
+					Sel:       hj.probeState.probeIdx,
+					SrcEndIdx: uint64(nResults),
+				},
+			},
+		)
+	}
+	rightColOffset := len(hj.spec.left.outCols)
+	for outColIdx := range hj.spec.right.outCols {
+		hj.output.ColVec(rightColOffset + outColIdx).Nulls().SetNulls()
+	}
 	hj.output.SetLength(nResults)
 }
 
@@ -592,6 +729,8 @@ func (hj *hashJoiner) reset() {
 	if hj.spec.left.outer {
 		copy(hj.probeState.probeRowUnmatched[:coldata.BatchSize()], zeroBoolColumn)
 	}
+	hj.probeState.prevBatch = nil
+	hj.probeState.unmatchedProbeBatch = nil
 	// hj.probeState.buildRowMatched is reset after building the hash table is
 	// complete in build() method.
 	hj.emittingUnmatchedState.rowIdx = 0
@@ -691,5 +830,31 @@ func newHashJoiner(
 	for i, colIdx := range spec.left.eqCols {
 		hj.probeState.keyTypes[i] = spec.left.sourceTypes[colIdx]
 	}
+	if spec.onExprFilterConstructor != nil {
+		// The ON expression refers to all columns from both sources, so we
+		// (temporarily) require that all columns are output.
+		if len(spec.left.outCols) != len(spec.left.sourceTypes) ||
+			len(spec.right.outCols) != len(spec.right.sourceTypes) {
+			execerror.VectorizedInternalPanic(
+				"hash joiner with ON expression must output all columns from both sources",
+			)
+		}
+		var err error
+		hj.onExprFilter, err = newJoinerBatchFilter(
+			append(append([]coltypes.T{}, spec.left.sourceTypes...), spec.right.sourceTypes...),
+			spec.onExprFilterConstructor,
+		)
+		if err != nil {
+			execerror.VectorizedInternalPanic(err)
+		}
+		hj.outputColIdxs = make([]int, len(spec.left.sourceTypes)+len(spec.right.sourceTypes))
+		for i := range hj.outputColIdxs {
+			hj.outputColIdxs[i] = i
+		}
+		hj.probeState.filterSel = make([]uint16, coldata.BatchSize())
+		if spec.left.outer {
+			hj.probeState.probeRowMatched = make([]bool, coldata.BatchSize())
+		}
+	}
 	return hj
 }
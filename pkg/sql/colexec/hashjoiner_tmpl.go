@@ -201,7 +201,10 @@ func _DISTINCT_COLLECT_PROBE_NO_OUTER(
 func (hj *hashJoiner) collect(batch coldata.Batch, batchSize uint16, sel []uint16) uint16 {
 	nResults := uint16(0)
 
-	if hj.spec.left.outer {
+	// In case of an ON expression, the unmatched probe rows are emitted only
+	// after the expression has been evaluated on all joined tuples, so we
+	// collect only the matches here.
+	if hj.spec.left.outer && hj.onExprFilter == nil {
 		if sel != nil {
 			_COLLECT_PROBE_OUTER(hj, batchSize, nResults, batch, true)
 		} else {
@@ -234,7 +237,7 @@ func (hj *hashJoiner) collect(batch coldata.Batch, batchSize uint16, sel []uint1
 func (hj *hashJoiner) distinctCollect(batch coldata.Batch, batchSize uint16, sel []uint16) uint16 {
 	nResults := uint16(0)
 
-	if hj.spec.left.outer {
+	if hj.spec.left.outer && hj.onExprFilter == nil {
 		nResults = batchSize
 
 		if sel != nil {
@@ -469,3 +469,35 @@ func (ht *hashTable) reset() {
 	copy(ht.headID[:coldata.BatchSize()], zeroUint64Column)
 	copy(ht.differs[:coldata.BatchSize()], zeroBoolColumn)
 }
+
+// newExportBatch returns a batch of sourceTypes schema to be used with
+// exportVals. The columns that are not stored in the hashTable will always
+// contain only NULL values.
+func (ht *hashTable) newExportBatch(sourceTypes []coltypes.T) coldata.Batch {
+	b := ht.allocator.NewMemBatchWithSize(sourceTypes, 0 /* size */)
+	isValCol := make([]bool, len(sourceTypes))
+	for _, colIdx := range ht.valCols {
+		isValCol[colIdx] = true
+	}
+	for i, t := range sourceTypes {
+		if !isValCol[i] {
+			vec := ht.allocator.NewMemColumn(t, int(coldata.BatchSize()))
+			vec.Nulls().SetNulls()
+			b.ReplaceCol(vec, i)
+		}
+	}
+	return b
+}
+
+// exportVals updates b (which must have been created by newExportBatch) to
+// contain the tuples of ht.vals in [startIdx, endIdx) range.
+func (ht *hashTable) exportVals(b coldata.Batch, startIdx, endIdx uint64) coldata.Batch {
+	// We don't need to worry about selection vectors on ht.vals because the
+	// tuples have been already selected during building of the hash table.
+	for i, colIdx := range ht.valCols {
+		window := ht.vals.colVecs[i].Window(ht.valTypes[i], startIdx, endIdx)
+		b.ReplaceCol(window, int(colIdx))
+	}
+	b.SetLength(uint16(endIdx - startIdx))
+	return b
+}
@@ -0,0 +1,172 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/span"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// colIndexJoin is the Operator implementation of the index join. It performs
+// a join between the input (which is usually a scan of a secondary index) and
+// the primary index of the same table in order to retrieve the columns that
+// are not stored in the secondary index. The first columns of the input must
+// be the primary key columns.
+//
+// For every batch from the input, the primary index is scanned over the spans
+// of all tuples in the batch in the order of the input, so the output
+// preserves the order of the input.
+type colIndexJoin struct {
+	OneInputNode
+
+	flowCtx *execinfra.FlowCtx
+	rf      *cFetcher
+	spanGen lookupSpanGenerator
+	// spans are the spans of the primary index for the current input batch.
+	spans roachpb.Spans
+	// fetcherReady indicates that we have started a scan of the primary index
+	// and there are potentially more tuples to retrieve.
+	fetcherReady bool
+
+	// init is true after Init() has been called.
+	init bool
+}
+
+var _ Operator = &colIndexJoin{}
+var _ execinfrapb.MetadataSource = &colIndexJoin{}
+
+// newColIndexJoin creates a new index join operator.
+func newColIndexJoin(
+	allocator *Allocator,
+	flowCtx *execinfra.FlowCtx,
+	input Operator,
+	inputTypes []types.T,
+	spec *execinfrapb.JoinReaderSpec,
+	post *execinfrapb.PostProcessSpec,
+) (*colIndexJoin, error) {
+	if spec.IndexIdx != 0 {
+		return nil, errors.Errorf("index join must be against primary index")
+	}
+	numKeyCols := len(spec.Table.PrimaryIndex.ColumnIDs)
+	if len(inputTypes) < numKeyCols {
+		return nil, errors.Errorf(
+			"index join input has %d columns, expected at least %d", len(inputTypes), numKeyCols,
+		)
+	}
+	returnMutations := spec.Visibility == execinfrapb.ScanVisibility_PUBLIC_AND_NOT_PUBLIC
+	helper := execinfra.ProcOutputHelper{}
+	if err := helper.Init(
+		post,
+		spec.Table.ColumnTypesWithMutations(returnMutations),
+		flowCtx.NewEvalCtx(),
+		nil, /* output */
+	); err != nil {
+		return nil, err
+	}
+	neededColumns := helper.NeededColumns()
+
+	fetcher := cFetcher{}
+	if _, _, err := initCRowFetcher(
+		allocator, &fetcher, &spec.Table, 0 /* indexIdx */, spec.Table.ColumnIdxMapWithMutations(returnMutations),
+		false /* reverseScan */, neededColumns, false /* isCheck */, spec.Visibility, spec.LockingStrength,
+		spec.LockingWaitPolicy,
+	); err != nil {
+		return nil, err
+	}
+
+	// There may be extra columns in the input, e.g. to allow an ordered
+	// synchronizer to interleave multiple input streams, so we use only the
+	// first numKeyCols.
+	keyCols := make([]uint32, numKeyCols)
+	for i := range keyCols {
+		keyCols[i] = uint32(i)
+	}
+	spanBuilder := span.MakeBuilder(&spec.Table, &spec.Table.PrimaryIndex)
+	spanBuilder.SetNeededColumns(neededColumns)
+	return &colIndexJoin{
+		OneInputNode: NewOneInputNode(input),
+		flowCtx:      flowCtx,
+		rf:           &fetcher,
+		spanGen:      makeLookupSpanGenerator(spanBuilder, inputTypes, keyCols),
+	}, nil
+}
+
+func (s *colIndexJoin) Init() {
+	s.init = true
+	s.input.Init()
+}
+
+func (s *colIndexJoin) Next(ctx context.Context) coldata.Batch {
+	for {
+		if !s.fetcherReady {
+			batch := s.input.Next(ctx)
+			n := batch.Length()
+			if n == 0 {
+				return coldata.ZeroBatch
+			}
+			sel := batch.Selection()
+			s.spans = s.spans[:0]
+			numKeyCols := len(s.spanGen.keyCols)
+			for i := uint16(0); i < n; i++ {
+				rowIdx := i
+				if sel != nil {
+					rowIdx = sel[i]
+				}
+				span, containsNull, err := s.spanGen.generateSpan(batch, rowIdx)
+				if err != nil {
+					execerror.VectorizedInternalPanic(err)
+				}
+				s.spans = s.spanGen.spanBuilder.MaybeSplitSpanIntoSeparateFamilies(
+					s.spans, span, numKeyCols, containsNull,
+				)
+			}
+			// The spans are not sorted, so the looked up tuples are returned in
+			// the order of the input.
+			if err := s.rf.StartScan(
+				ctx, s.flowCtx.Txn, s.spans, false /* limitBatches */, 0, /* limitHint */
+				s.flowCtx.TraceKV,
+			); err != nil {
+				execerror.VectorizedInternalPanic(err)
+			}
+			s.fetcherReady = true
+		}
+		batch, err := s.rf.NextBatch(ctx)
+		if err != nil {
+			execerror.VectorizedInternalPanic(err)
+		}
+		if batch.Length() == 0 {
+			// Done with the current input batch.
+			s.fetcherReady = false
+			continue
+		}
+		return batch
+	}
+}
+
+// DrainMeta is part of the MetadataSource interface.
+func (s *colIndexJoin) DrainMeta(ctx context.Context) []execinfrapb.ProducerMetadata {
+	if !s.init {
+		return nil
+	}
+	var trailingMeta []execinfrapb.ProducerMetadata
+	if tfs := execinfra.GetLeafTxnFinalState(ctx, s.flowCtx.Txn); tfs != nil {
+		trailingMeta = append(trailingMeta, execinfrapb.ProducerMetadata{LeafTxnFinalState: tfs})
+	}
+	return trailingMeta
+}
@@ -144,3 +144,81 @@ func (f *joinerFilter) setInputBatch(lBatch, rBatch coldata.Batch, lIdx, rIdx in
 	f.input.batch.SetLength(1)
 	f.input.batch.SetSelection(false)
 }
+
+// newJoinerBatchFilter creates a new joinerBatchFilter that evaluates the ON
+// expression (planned by filterConstructor) on the joined tuples that have the
+// schema of sourceTypes (which are the types of the left source followed by
+// the types of the right source).
+func newJoinerBatchFilter(
+	sourceTypes []coltypes.T, filterConstructor func(Operator) (Operator, error),
+) (*joinerBatchFilter, error) {
+	// The columns of the feed batch will be replaced with the vectors of the
+	// batches to be filtered, so we don't allocate any memory for them.
+	input := &filterFeedOperator{
+		batch: coldata.NewMemBatchNoCols(sourceTypes, int(coldata.BatchSize())),
+	}
+	filter, err := filterConstructor(input)
+	return &joinerBatchFilter{
+		filter:  filter,
+		input:   input,
+		numCols: len(sourceTypes),
+	}, err
+}
+
+// joinerBatchFilter is a side chain of Operators needed to support ON
+// expressions for outer joins. Unlike joinerFilter which evaluates the filter
+// on a single "double" tuple at a time, it evaluates the filter on many
+// already joined tuples at once.
+type joinerBatchFilter struct {
+	filter Operator
+	input  *filterFeedOperator
+	// numCols is the number of columns that the ON expression refers to. Any
+	// columns after that in the feed batch have been appended by the filter
+	// itself in order to store the projections.
+	numCols int
+}
+
+func (f *joinerBatchFilter) init() {
+	f.filter.Init()
+}
+
+// filterBatch evaluates the ON expression on the tuples of batch that are specified
+// by sel (which contains the physical indices). The columns of batch that
+// correspond to the columns of the ON expression are specified by colIdxs. It
+// returns a selection vector containing the indices of the tuples from sel
+// that satisfy the expression. The returned slice is only valid until the next
+// call to filterBatch.
+// NOTE: batch itself is not modified.
+func (f *joinerBatchFilter) filterBatch(
+	ctx context.Context, batch coldata.Batch, colIdxs []int, sel []uint16,
+) []uint16 {
+	if len(sel) == 0 {
+		return sel
+	}
+	feedBatch := f.input.batch
+	for i, colIdx := range colIdxs {
+		feedBatch.ReplaceCol(batch.ColVec(colIdx), i)
+	}
+	// The projections appended by the filter from the previous evaluation must
+	// be reset. Note that we cannot use ResetInternalBatch since the vectors
+	// of the other columns are shared with batch.
+	for i := f.numCols; i < feedBatch.Width(); i++ {
+		vec := feedBatch.ColVec(i)
+		if vec.Type() == coltypes.Unhandled {
+			continue
+		}
+		vec.Nulls().UnsetNulls()
+		if vec.Type() == coltypes.Bytes {
+			vec.Bytes().Reset()
+		}
+	}
+	feedBatch.SetSelection(true)
+	copy(feedBatch.Selection(), sel)
+	feedBatch.SetLength(uint16(len(sel)))
+	f.input.nexted = false
+	b := f.filter.Next(ctx)
+	if b.Length() == 0 {
+		return sel[:0]
+	}
+	return b.Selection()[:b.Length()]
+}
@@ -0,0 +1,612 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"sort"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/typeconv"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/span"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/errors"
+)
+
+// lookupSpanGenerator generates the spans to look up in an index from the
+// tuples of a batch.
+type lookupSpanGenerator struct {
+	spanBuilder *span.Builder
+	// typs are the logical types of all columns of the batches.
+	typs []types.T
+	// keyCols are the indices of the columns of the batches that correspond to
+	// the leading columns of the index.
+	keyCols []uint32
+	keyRow  sqlbase.EncDatumRow
+	da      sqlbase.DatumAlloc
+}
+
+func makeLookupSpanGenerator(
+	spanBuilder *span.Builder, typs []types.T, keyCols []uint32,
+) lookupSpanGenerator {
+	return lookupSpanGenerator{
+		spanBuilder: spanBuilder,
+		typs:        typs,
+		keyCols:     keyCols,
+		keyRow:      make(sqlbase.EncDatumRow, len(keyCols)),
+	}
+}
+
+// hasNullKey returns whether any of the key columns of the tuple at position
+// rowIdx is NULL.
+func (g *lookupSpanGenerator) hasNullKey(batch coldata.Batch, rowIdx uint16) bool {
+	for _, colIdx := range g.keyCols {
+		vec := batch.ColVec(int(colIdx))
+		if vec.MaybeHasNulls() && vec.Nulls().NullAt(rowIdx) {
+			return true
+		}
+	}
+	return false
+}
+
+// generateSpan returns the span of the index that contains the keys that have
+// the prefix equal to the values in the key columns of the tuple at position
+// rowIdx. It also returns whether any of the values is NULL.
+func (g *lookupSpanGenerator) generateSpan(
+	batch coldata.Batch, rowIdx uint16,
+) (_ roachpb.Span, containsNull bool, _ error) {
+	for i, colIdx := range g.keyCols {
+		datum := PhysicalTypeColElemToDatum(batch.ColVec(int(colIdx)), rowIdx, g.da, &g.typs[colIdx])
+		g.keyRow[i] = sqlbase.DatumToEncDatum(&g.typs[colIdx], datum)
+	}
+	return g.spanBuilder.SpanFromEncDatums(g.keyRow, len(g.keyCols))
+}
+
+const (
+	sizeOfUint64      = int64(unsafe.Sizeof(uint64(0)))
+	sizeOfUint64Slice = int64(unsafe.Sizeof([]uint64(nil)))
+)
+
+// lookupJoinState represents the state of colLookupJoin.
+type lookupJoinState int
+
+const (
+	// lookupJoinReading is the state in which colLookupJoin reads the next
+	// batch from the input.
+	lookupJoinReading lookupJoinState = iota
+	// lookupJoinLookingUp is the state in which colLookupJoin performs the
+	// index lookup for the next chunk of the tuples of the current input batch.
+	lookupJoinLookingUp
+	// lookupJoinEmitting is the state in which colLookupJoin emits the results
+	// of the join of the current chunk of the input batch with the looked up
+	// tuples.
+	lookupJoinEmitting
+)
+
+// colLookupJoin is the Operator implementation of the lookup join. For every
+// batch from the input, it performs a single scan of the index over the spans
+// derived from the lookup columns of all tuples in the batch, buffers the
+// looked up tuples, and joins them with the input tuples. The output preserves
+// the order of the input.
+//
+// If the looked up tuples of the whole input batch don't fit into the memory
+// limit, the input batch is split into smaller chunks which are looked up one
+// at a time. A single input tuple is never split, so the memory limit can be
+// exceeded if a single input tuple has too many matches, in which case only
+// the limit of the memory account applies.
+//
+// Only INNER, LEFT OUTER, LEFT SEMI and LEFT ANTI joins without ON expression
+// are supported. The output of INNER and LEFT OUTER joins consists of the input
+// columns followed by all of the table columns (only the needed ones are
+// populated), and the output of LEFT SEMI and LEFT ANTI joins consists of the
+// input columns only.
+type colLookupJoin struct {
+	OneInputNode
+
+	allocator *Allocator
+	flowCtx   *execinfra.FlowCtx
+	rf        *cFetcher
+	joinType  sqlbase.JoinType
+	// limitBatches indicates whether the fetcher should limit the size of the
+	// KV batches. It is false when every lookup returns at most one tuple, so
+	// that the lookups can be parallelized.
+	limitBatches bool
+	// memoryLimit is the limit on the memory used by the looked up tuples of a
+	// single lookup (and by the indices of their matches).
+	memoryLimit int64
+
+	inputTypes []coltypes.T
+	tableTypes []coltypes.T
+	// neededTableCols are the indices of the table columns that are decoded by
+	// the fetcher. Only these columns are buffered and copied into the output.
+	neededTableCols []int
+
+	inputSpanGen    lookupSpanGenerator
+	lookedUpSpanGen lookupSpanGenerator
+
+	state lookupJoinState
+	// batch is the input batch that is currently being joined.
+	batch coldata.Batch
+	// chunkStart and chunkEnd are the ordinals of the first input tuple of the
+	// current chunk of the input batch and of the first input tuple after it.
+	chunkStart, chunkEnd uint16
+	spans                roachpb.Spans
+	// keyToInputRowIdxs is a map from the key of a lookup span to the ordinals
+	// (i.e. the positions within the selection vector, if present) of the
+	// input tuples that produced that span.
+	keyToInputRowIdxs map[string][]uint16
+	// inputRowIdxToLookedUpRowIdxs contains the indices of the matching looked
+	// up tuples for every input tuple of the current batch. For LEFT SEMI and
+	// LEFT ANTI joins, at most a single sentinel value is stored because only
+	// the existence of a match is important.
+	inputRowIdxToLookedUpRowIdxs [][]uint64
+	// matchesMemUsage is the memory used by inputRowIdxToLookedUpRowIdxs that
+	// has been registered with the allocator.
+	matchesMemUsage int64
+	// lookedUp contains the needed columns of the looked up tuples.
+	lookedUp *bufferedBatch
+	// lookedUpMemUsage is the estimated memory used by the looked up tuples of
+	// the current lookup and by the indices of their matches.
+	lookedUpMemUsage int64
+
+	// emitCursor contains information about which output tuple to emit next.
+	emitCursor struct {
+		// inputRowIdx is the ordinal of the current input tuple.
+		inputRowIdx uint16
+		// matchIdx is the index into inputRowIdxToLookedUpRowIdxs of the current
+		// input tuple of the next match to emit.
+		matchIdx int
+	}
+	output coldata.Batch
+	// inputSel and lookedUpSel contain the indices of the input tuples and of
+	// the looked up tuples that form the tuples of the output batch.
+	inputSel    []uint16
+	lookedUpSel []uint64
+	// unmatched contains the positions of the output tuples that have NULLs in
+	// the table columns (only used for LEFT OUTER join).
+	unmatched []uint16
+
+	// init is true after Init() has been called.
+	init bool
+}
+
+var _ Operator = &colLookupJoin{}
+var _ execinfrapb.MetadataSource = &colLookupJoin{}
+
+// newColLookupJoin creates a new lookup join operator. The input tuples are
+// matched with the tuples of the index in spec.IndexIdx on the input columns
+// spec.LookupColumns.
+func newColLookupJoin(
+	allocator *Allocator,
+	flowCtx *execinfra.FlowCtx,
+	input Operator,
+	inputTypes []types.T,
+	spec *execinfrapb.JoinReaderSpec,
+	post *execinfrapb.PostProcessSpec,
+	memoryLimit int64,
+) (*colLookupJoin, error) {
+	switch spec.Type {
+	case sqlbase.JoinType_INNER, sqlbase.JoinType_LEFT_OUTER,
+		sqlbase.JoinType_LEFT_SEMI, sqlbase.JoinType_LEFT_ANTI:
+	default:
+		return nil, errors.AssertionFailedf("unsupported lookup join type %s", spec.Type)
+	}
+	if !spec.OnExpr.Empty() {
+		return nil, errors.AssertionFailedf("lookup join with ON expression is not supported")
+	}
+	returnMutations := spec.Visibility == execinfrapb.ScanVisibility_PUBLIC_AND_NOT_PUBLIC
+	tableTypes := spec.Table.ColumnTypesWithMutations(returnMutations)
+	internalTypes := inputTypes
+	isPartialJoin := spec.Type == sqlbase.JoinType_LEFT_SEMI || spec.Type == sqlbase.JoinType_LEFT_ANTI
+	if !isPartialJoin {
+		internalTypes = append(inputTypes[:len(inputTypes):len(inputTypes)], tableTypes...)
+	}
+	helper := execinfra.ProcOutputHelper{}
+	if err := helper.Init(post, internalTypes, flowCtx.NewEvalCtx(), nil /* output */); err != nil {
+		return nil, err
+	}
+	// Get the needed columns of the table and shift them over by the number of
+	// the input columns.
+	neededCols := helper.NeededColumns()
+	neededTableCols := util.MakeFastIntSet()
+	for i, ok := neededCols.Next(len(inputTypes)); ok; i, ok = neededCols.Next(i + 1) {
+		neededTableCols.Add(i - len(inputTypes))
+	}
+
+	index, isSecondary, err := spec.Table.FindIndexByIndexIdx(int(spec.IndexIdx))
+	if err != nil {
+		return nil, err
+	}
+	colIdxMap := spec.Table.ColumnIdxMapWithMutations(returnMutations)
+	if isSecondary && !neededTableCols.SubsetOf(getIndexColSet(index, colIdxMap)) {
+		return nil, errors.Errorf("lookup join index does not cover all columns")
+	}
+	indexColIDs, _ := index.FullColumnIDs()
+	if len(spec.LookupColumns) > len(indexColIDs) {
+		return nil, errors.Errorf(
+			"%d lookup columns specified, expecting at most %d", len(spec.LookupColumns), len(indexColIDs),
+		)
+	}
+	// The fetcher must decode the index columns that are matched with the
+	// lookup columns in order for us to find the input tuples that correspond
+	// to the looked up ones.
+	lookedUpKeyCols := make([]uint32, len(spec.LookupColumns))
+	for i := range lookedUpKeyCols {
+		colIdx := colIdxMap[indexColIDs[i]]
+		lookedUpKeyCols[i] = uint32(colIdx)
+		neededTableCols.Add(colIdx)
+	}
+
+	fetcher := cFetcher{}
+	if _, _, err := initCRowFetcher(
+		allocator, &fetcher, &spec.Table, int(spec.IndexIdx), colIdxMap, false, /* reverseScan */
+		neededTableCols, false /* isCheck */, spec.Visibility, spec.LockingStrength,
+		spec.LockingWaitPolicy,
+	); err != nil {
+		return nil, err
+	}
+
+	inputPhysTypes, err := typeconv.FromColumnTypes(inputTypes)
+	if err != nil {
+		return nil, err
+	}
+	tablePhysTypes, err := typeconv.FromColumnTypes(tableTypes)
+	if err != nil {
+		return nil, err
+	}
+	spanBuilder := span.MakeBuilder(&spec.Table, index)
+	spanBuilder.SetNeededColumns(neededTableCols)
+	return &colLookupJoin{
+		OneInputNode:    NewOneInputNode(input),
+		allocator:       allocator,
+		flowCtx:         flowCtx,
+		rf:              &fetcher,
+		joinType:        spec.Type,
+		limitBatches:    !spec.LookupColumnsAreKey,
+		memoryLimit:     memoryLimit,
+		inputTypes:      inputPhysTypes,
+		tableTypes:      tablePhysTypes,
+		neededTableCols: neededTableCols.Ordered(),
+		inputSpanGen:    makeLookupSpanGenerator(spanBuilder, inputTypes, spec.LookupColumns),
+		lookedUpSpanGen: makeLookupSpanGenerator(spanBuilder, tableTypes, lookedUpKeyCols),
+	}, nil
+}
+
+// getIndexColSet returns a set of all column indices for the given index.
+func getIndexColSet(
+	index *sqlbase.IndexDescriptor, colIdxMap map[sqlbase.ColumnID]int,
+) util.FastIntSet {
+	cols := util.MakeFastIntSet()
+	err := index.RunOverAllColumns(func(id sqlbase.ColumnID) error {
+		cols.Add(colIdxMap[id])
+		return nil
+	})
+	if err != nil {
+		// This path should never be hit since the column function never returns an
+		// error.
+		execerror.VectorizedInternalPanic(err)
+	}
+	return cols
+}
+
+func (j *colLookupJoin) isPartialJoin() bool {
+	return j.joinType == sqlbase.JoinType_LEFT_SEMI || j.joinType == sqlbase.JoinType_LEFT_ANTI
+}
+
+func (j *colLookupJoin) Init() {
+	j.init = true
+	j.input.Init()
+
+	outputTypes := j.inputTypes
+	if !j.isPartialJoin() {
+		outputTypes = append(outputTypes[:len(outputTypes):len(outputTypes)], j.tableTypes...)
+		lookedUpTypes := make([]coltypes.T, len(j.neededTableCols))
+		for i, colIdx := range j.neededTableCols {
+			lookedUpTypes[i] = j.tableTypes[colIdx]
+		}
+		j.lookedUp = newBufferedBatch(j.allocator, lookedUpTypes, 0 /* initialSize */)
+	}
+	j.output = j.allocator.NewMemBatch(outputTypes)
+	j.keyToInputRowIdxs = make(map[string][]uint16)
+}
+
+func (j *colLookupJoin) Next(ctx context.Context) coldata.Batch {
+	for {
+		switch j.state {
+		case lookupJoinReading:
+			j.batch = j.input.Next(ctx)
+			if j.batch.Length() == 0 {
+				return coldata.ZeroBatch
+			}
+			j.chunkEnd = 0
+			j.state = lookupJoinLookingUp
+		case lookupJoinLookingUp:
+			// Try to look up all of the remaining input tuples at once, and halve
+			// the chunk every time the looked up tuples don't fit into the memory
+			// limit.
+			j.chunkStart, j.chunkEnd = j.chunkEnd, j.batch.Length()
+			for !j.performLookup(ctx) {
+				j.chunkEnd = j.chunkStart + (j.chunkEnd-j.chunkStart)/2
+			}
+			j.emitCursor.inputRowIdx = j.chunkStart
+			j.emitCursor.matchIdx = 0
+			j.state = lookupJoinEmitting
+		case lookupJoinEmitting:
+			j.emit()
+			if j.emitCursor.inputRowIdx == j.chunkEnd {
+				if j.chunkEnd == j.batch.Length() {
+					j.state = lookupJoinReading
+				} else {
+					j.state = lookupJoinLookingUp
+				}
+			}
+			if j.output.Length() > 0 {
+				return j.output
+			}
+		default:
+			execerror.VectorizedInternalPanic("unexpected lookupJoinState")
+		}
+	}
+}
+
+// performLookup scans the index over the spans generated from the current
+// chunk of the input batch and finds the matching looked up tuples for every
+// input tuple of the chunk. It returns false if the lookup was abandoned
+// because the looked up tuples exceeded the memory limit and the chunk
+// consists of more than one tuple.
+func (j *colLookupJoin) performLookup(ctx context.Context) bool {
+	defer j.accountForMatches()
+	n := j.batch.Length()
+	sel := j.batch.Selection()
+	for key := range j.keyToInputRowIdxs {
+		delete(j.keyToInputRowIdxs, key)
+	}
+	if cap(j.inputRowIdxToLookedUpRowIdxs) >= int(n) {
+		j.inputRowIdxToLookedUpRowIdxs = j.inputRowIdxToLookedUpRowIdxs[:n]
+		for i := range j.inputRowIdxToLookedUpRowIdxs {
+			j.inputRowIdxToLookedUpRowIdxs[i] = j.inputRowIdxToLookedUpRowIdxs[i][:0]
+		}
+	} else {
+		j.inputRowIdxToLookedUpRowIdxs = make([][]uint64, n)
+	}
+	if j.lookedUp != nil {
+		j.lookedUp.reset()
+	}
+	j.lookedUpMemUsage = 0
+
+	// Generate the lookup spans. Several input tuples might map to the same
+	// span, and we look up each span only once.
+	j.spans = j.spans[:0]
+	numLookupCols := len(j.inputSpanGen.keyCols)
+	for i := j.chunkStart; i < j.chunkEnd; i++ {
+		rowIdx := i
+		if sel != nil {
+			rowIdx = sel[i]
+		}
+		if j.inputSpanGen.hasNullKey(j.batch, rowIdx) {
+			// NULL never matches anything.
+			continue
+		}
+		span, containsNull, err := j.inputSpanGen.generateSpan(j.batch, rowIdx)
+		if err != nil {
+			execerror.VectorizedInternalPanic(err)
+		}
+		inputRowIdxs, ok := j.keyToInputRowIdxs[string(span.Key)]
+		if !ok {
+			j.spans = j.inputSpanGen.spanBuilder.MaybeSplitSpanIntoSeparateFamilies(
+				j.spans, span, numLookupCols, containsNull,
+			)
+		}
+		j.keyToInputRowIdxs[string(span.Key)] = append(inputRowIdxs, i)
+	}
+	if len(j.spans) == 0 {
+		// All of the input tuples have NULLs in the lookup columns.
+		return true
+	}
+	// Sort the spans so that we can rely upon the fetcher to limit the number of
+	// results per batch. It's safe to reorder the spans here because the
+	// original order of the input is restored when emitting the output.
+	sort.Sort(j.spans)
+	if err := j.rf.StartScan(
+		ctx, j.flowCtx.Txn, j.spans, j.limitBatches, 0 /* limitHint */, j.flowCtx.TraceKV,
+	); err != nil {
+		execerror.VectorizedInternalPanic(err)
+	}
+
+	isPartialJoin := j.isPartialJoin()
+	for {
+		lookedUpBatch, err := j.rf.NextBatch(ctx)
+		if err != nil {
+			execerror.VectorizedInternalPanic(err)
+		}
+		lookedUpLength := lookedUpBatch.Length()
+		if lookedUpLength == 0 {
+			return true
+		}
+		for i := uint16(0); i < lookedUpLength; i++ {
+			span, _, err := j.lookedUpSpanGen.generateSpan(lookedUpBatch, i)
+			if err != nil {
+				execerror.VectorizedInternalPanic(err)
+			}
+			for _, inputRowIdx := range j.keyToInputRowIdxs[string(span.Key)] {
+				if isPartialJoin {
+					// We only need to know whether there is a match.
+					if len(j.inputRowIdxToLookedUpRowIdxs[inputRowIdx]) == 0 {
+						j.inputRowIdxToLookedUpRowIdxs[inputRowIdx] = append(
+							j.inputRowIdxToLookedUpRowIdxs[inputRowIdx], 0,
+						)
+					}
+				} else {
+					j.inputRowIdxToLookedUpRowIdxs[inputRowIdx] = append(
+						j.inputRowIdxToLookedUpRowIdxs[inputRowIdx], j.lookedUp.length+uint64(i),
+					)
+					j.lookedUpMemUsage += sizeOfUint64
+				}
+			}
+		}
+		if !isPartialJoin {
+			j.allocator.PerformOperation(j.lookedUp.colVecs, func() {
+				for i, colIdx := range j.neededTableCols {
+					j.lookedUp.colVecs[i].Append(coldata.SliceArgs{
+						ColType:   j.lookedUp.colVecs[i].Type(),
+						Src:       lookedUpBatch.ColVec(colIdx),
+						DestIdx:   j.lookedUp.length,
+						SrcEndIdx: uint64(lookedUpLength),
+					})
+				}
+				j.lookedUp.length += uint64(lookedUpLength)
+			})
+			j.lookedUpMemUsage += j.estimateLookedUpSize(lookedUpBatch)
+			if j.lookedUpMemUsage > j.memoryLimit && j.chunkEnd-j.chunkStart > 1 {
+				return false
+			}
+		}
+	}
+}
+
+// estimateLookedUpSize returns the estimated memory used by the needed columns
+// of the looked up tuples in batch once they are buffered.
+func (j *colLookupJoin) estimateLookedUpSize(batch coldata.Batch) int64 {
+	n := int(batch.Length())
+	var size int64
+	for _, colIdx := range j.neededTableCols {
+		if typ := j.tableTypes[colIdx]; typ == coltypes.Bytes {
+			col := batch.ColVec(colIdx).Bytes()
+			for i := 0; i < n; i++ {
+				size += int64(len(col.Get(i)) + sizeOfInt32)
+			}
+		} else {
+			size += int64(estimateBatchSizeBytes([]coltypes.T{typ}, n))
+		}
+	}
+	return size
+}
+
+// accountForMatches registers the memory used by inputRowIdxToLookedUpRowIdxs
+// with the allocator.
+func (j *colLookupJoin) accountForMatches() {
+	size := int64(cap(j.inputRowIdxToLookedUpRowIdxs)) * sizeOfUint64Slice
+	for _, matches := range j.inputRowIdxToLookedUpRowIdxs[:cap(j.inputRowIdxToLookedUpRowIdxs)] {
+		size += int64(cap(matches)) * sizeOfUint64
+	}
+	j.allocator.AdjustMemoryUsage(size - j.matchesMemUsage)
+	j.matchesMemUsage = size
+}
+
+// emit populates the output batch with the next tuples of the join of the
+// current input batch with the looked up tuples.
+func (j *colLookupJoin) emit() {
+	j.output.ResetInternalBatch()
+	j.output.SetLength(0)
+	j.inputSel = j.inputSel[:0]
+	j.lookedUpSel = j.lookedUpSel[:0]
+	j.unmatched = j.unmatched[:0]
+	sel := j.batch.Selection()
+	batchSize := int(coldata.BatchSize())
+	for len(j.inputSel) < batchSize && j.emitCursor.inputRowIdx < j.chunkEnd {
+		inputRowIdx := j.emitCursor.inputRowIdx
+		rowIdx := inputRowIdx
+		if sel != nil {
+			rowIdx = sel[inputRowIdx]
+		}
+		matches := j.inputRowIdxToLookedUpRowIdxs[inputRowIdx]
+		switch j.joinType {
+		case sqlbase.JoinType_LEFT_SEMI:
+			if len(matches) > 0 {
+				j.inputSel = append(j.inputSel, rowIdx)
+			}
+		case sqlbase.JoinType_LEFT_ANTI:
+			if len(matches) == 0 {
+				j.inputSel = append(j.inputSel, rowIdx)
+			}
+		default:
+			if len(matches) == 0 {
+				if j.joinType == sqlbase.JoinType_LEFT_OUTER {
+					j.unmatched = append(j.unmatched, uint16(len(j.inputSel)))
+					j.inputSel = append(j.inputSel, rowIdx)
+					// The looked up tuple is irrelevant since the table columns
+					// will be set to NULL.
+					j.lookedUpSel = append(j.lookedUpSel, 0)
+				}
+				break
+			}
+			for ; j.emitCursor.matchIdx < len(matches) && len(j.inputSel) < batchSize; j.emitCursor.matchIdx++ {
+				j.inputSel = append(j.inputSel, rowIdx)
+				j.lookedUpSel = append(j.lookedUpSel, matches[j.emitCursor.matchIdx])
+			}
+			if j.emitCursor.matchIdx < len(matches) {
+				// The output batch is full, and we'll continue emitting the
+				// matches of the current input tuple on the next call.
+				continue
+			}
+		}
+		j.emitCursor.inputRowIdx++
+		j.emitCursor.matchIdx = 0
+	}
+
+	outputLength := uint16(len(j.inputSel))
+	if outputLength == 0 {
+		return
+	}
+	j.allocator.PerformOperation(j.output.ColVecs(), func() {
+		for i, typ := range j.inputTypes {
+			j.output.ColVec(i).Copy(coldata.CopySliceArgs{
+				SliceArgs: coldata.SliceArgs{
+					ColType:   typ,
+					Src:       j.batch.ColVec(i),
+					Sel:       j.inputSel,
+					SrcEndIdx: uint64(outputLength),
+				},
+			})
+		}
+		if j.isPartialJoin() {
+			return
+		}
+		numInputCols := len(j.inputTypes)
+		for i, colIdx := range j.neededTableCols {
+			outputVec := j.output.ColVec(numInputCols + colIdx)
+			if j.lookedUp.length > 0 {
+				outputVec.Copy(coldata.CopySliceArgs{
+					SliceArgs: coldata.SliceArgs{
+						ColType:   j.tableTypes[colIdx],
+						Src:       j.lookedUp.colVecs[i],
+						SrcEndIdx: uint64(outputLength),
+					},
+					Sel64: j.lookedUpSel,
+				})
+			}
+			for _, outputIdx := range j.unmatched {
+				outputVec.Nulls().SetNull(outputIdx)
+			}
+		}
+	})
+	j.output.SetLength(outputLength)
+}
+
+// DrainMeta is part of the MetadataSource interface.
+func (j *colLookupJoin) DrainMeta(ctx context.Context) []execinfrapb.ProducerMetadata {
+	if !j.init {
+		return nil
+	}
+	var trailingMeta []execinfrapb.ProducerMetadata
+	if tfs := execinfra.GetLeafTxnFinalState(ctx, j.flowCtx.Txn); tfs != nil {
+		trailingMeta = append(trailingMeta, execinfrapb.ProducerMetadata{LeafTxnFinalState: tfs})
+	}
+	return trailingMeta
+}
@@ -0,0 +1,449 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// mergeJoinOuterFilterOp is an operator that is planned on top of a merge
+// joiner in order to support ON expressions for LEFT, RIGHT, and FULL OUTER
+// joins. The merge joiner underneath must perform the outer join on the
+// equality columns only, and both of its inputs must have an ordinality
+// column appended (at the end), so that the input to this operator has the
+// schema [left columns, left ordinal, right columns, right ordinal].
+//
+// The operator evaluates the ON expression on all tuples that had a match on
+// the equality columns (those have both ordinals non-NULL) and emits only the
+// ones that pass. The tuples that didn't have a match on the equality columns
+// are emitted as is. Additionally, the operator keeps track of whether each
+// left tuple ("run" of the output tuples with the same left ordinal) and each
+// right tuple (within the group of tuples with the same equality columns) had
+// at least one match that satisfied the ON expression, and if not, the tuple
+// is NULL-extended and emitted.
+//
+// The merge joiner emits the cross product of a group of tuples with the same
+// equality columns in "left-major" order, so the first run of a group contains
+// all right tuples from that group. Those right tuples are buffered (only if
+// unmatched right tuples need to be emitted) until the group is finished.
+//
+// The output of this operator consists of the left and the right columns
+// (without the ordinals).
+type mergeJoinOuterFilterOp struct {
+	OneInputNode
+
+	allocator  *Allocator
+	filter     *joinerBatchFilter
+	leftTypes  []coltypes.T
+	rightTypes []coltypes.T
+
+	emitUnmatchedLeft  bool
+	emitUnmatchedRight bool
+
+	// leftOrdColIdx and rightOrdColIdx are the indices of the ordinality
+	// columns in the input.
+	leftOrdColIdx  int
+	rightOrdColIdx int
+	// filterColIdxs are the indices of the input columns that are referenced
+	// by the ON expression (i.e. all columns except for the ordinals).
+	filterColIdxs []int
+
+	output          coldata.Batch
+	outputBatchSize uint16
+	// outputLen is the number of tuples already written into the output.
+	outputLen uint16
+	// toEmit contains the physical indices of the tuples from the current
+	// input batch that are yet to be copied into the output.
+	toEmit []uint16
+
+	batch     coldata.Batch
+	batchIdx  uint16
+	inputDone bool
+	// passed stores whether the tuple at the corresponding physical index of
+	// the current input batch satisfied the ON expression.
+	passed    []bool
+	filterSel []uint16
+
+	// run describes the current run of tuples with the same left tuple.
+	run struct {
+		active   bool
+		leftOrd  int64
+		hasMatch bool
+		// leftTuple stores the left tuple of the run (only when unmatched left
+		// tuples need to be emitted).
+		leftTuple *bufferedBatch
+		// emittingUnmatched indicates that the run has been finished without
+		// a match and that the left tuple needs to be emitted.
+		emittingUnmatched bool
+	}
+
+	// group describes the current group of tuples with the same equality
+	// columns. It is only used when unmatched right tuples need to be emitted.
+	group struct {
+		active        bool
+		firstRightOrd int64
+		// collecting indicates whether the first run of the group is being
+		// processed, so the right tuples are being buffered.
+		collecting        bool
+		rightTuples       *bufferedBatch
+		matched           []bool
+		emittingUnmatched bool
+		emitIdx           uint64
+	}
+}
+
+var _ Operator = &mergeJoinOuterFilterOp{}
+
+// newMergeJoinOuterFilterOp returns a new mergeJoinOuterFilterOp. input must
+// be a merge joiner of the specified joinType (which must be one of LEFT,
+// RIGHT, or FULL OUTER) that has the ordinality columns appended to both
+// sides. filterConstructor is used to plan the ON expression on top of the
+// tuples with the schema of leftTypes followed by rightTypes.
+func newMergeJoinOuterFilterOp(
+	allocator *Allocator,
+	input Operator,
+	joinType sqlbase.JoinType,
+	leftTypes []coltypes.T,
+	rightTypes []coltypes.T,
+	filterConstructor func(Operator) (Operator, error),
+) (Operator, error) {
+	switch joinType {
+	case sqlbase.JoinType_LEFT_OUTER, sqlbase.JoinType_RIGHT_OUTER, sqlbase.JoinType_FULL_OUTER:
+	default:
+		execerror.VectorizedInternalPanic(
+			"mergeJoinOuterFilterOp only supports LEFT, RIGHT, and FULL OUTER joins",
+		)
+	}
+	sourceTypes := make([]coltypes.T, 0, len(leftTypes)+len(rightTypes))
+	sourceTypes = append(sourceTypes, leftTypes...)
+	sourceTypes = append(sourceTypes, rightTypes...)
+	filter, err := newJoinerBatchFilter(sourceTypes, filterConstructor)
+	if err != nil {
+		return nil, err
+	}
+	o := &mergeJoinOuterFilterOp{
+		OneInputNode:       NewOneInputNode(input),
+		allocator:          allocator,
+		filter:             filter,
+		leftTypes:          leftTypes,
+		rightTypes:         rightTypes,
+		emitUnmatchedLeft:  joinType != sqlbase.JoinType_RIGHT_OUTER,
+		emitUnmatchedRight: joinType != sqlbase.JoinType_LEFT_OUTER,
+		leftOrdColIdx:      len(leftTypes),
+		rightOrdColIdx:     len(leftTypes) + 1 + len(rightTypes),
+		filterColIdxs:      make([]int, 0, len(sourceTypes)),
+		outputBatchSize:    coldata.BatchSize(),
+	}
+	for i := range leftTypes {
+		o.filterColIdxs = append(o.filterColIdxs, i)
+	}
+	for i := range rightTypes {
+		o.filterColIdxs = append(o.filterColIdxs, o.leftOrdColIdx+1+i)
+	}
+	return o, nil
+}
+
+func (o *mergeJoinOuterFilterOp) Init() {
+	o.input.Init()
+	o.filter.init()
+	o.output = o.allocator.NewMemBatch(append(append([]coltypes.T{}, o.leftTypes...), o.rightTypes...))
+	o.toEmit = make([]uint16, 0, coldata.BatchSize())
+	o.passed = make([]bool, coldata.BatchSize())
+	o.filterSel = make([]uint16, 0, coldata.BatchSize())
+	if o.emitUnmatchedLeft {
+		o.run.leftTuple = newBufferedBatch(o.allocator, o.leftTypes, 1 /* initialSize */)
+	}
+	if o.emitUnmatchedRight {
+		o.group.rightTuples = newBufferedBatch(o.allocator, o.rightTypes, 0 /* initialSize */)
+	}
+}
+
+func (o *mergeJoinOuterFilterOp) Next(ctx context.Context) coldata.Batch {
+	o.output.ResetInternalBatch()
+	o.outputLen = 0
+	for o.outputLen+uint16(len(o.toEmit)) < o.outputBatchSize {
+		if o.run.emittingUnmatched {
+			o.flush()
+			o.emitUnmatchedLeftTuple()
+			continue
+		}
+		if o.group.emittingUnmatched {
+			o.flush()
+			o.emitUnmatchedRightTuples()
+			continue
+		}
+		if o.batch == nil || o.batchIdx == o.batch.Length() {
+			// toEmit refers to the current batch, so we need to flush it before
+			// getting the next one.
+			o.flush()
+			if o.inputDone {
+				break
+			}
+			o.batch = o.input.Next(ctx)
+			o.batchIdx = 0
+			if o.batch.Length() == 0 {
+				o.inputDone = true
+				o.finishRun()
+				o.finishGroup()
+				continue
+			}
+			o.evaluateFilter(ctx)
+		}
+		o.processTuple()
+	}
+	o.flush()
+	o.output.SetLength(o.outputLen)
+	return o.output
+}
+
+// evaluateFilter evaluates the ON expression on all tuples of the current
+// batch that had a match on the equality columns and populates o.passed
+// accordingly.
+func (o *mergeJoinOuterFilterOp) evaluateFilter(ctx context.Context) {
+	n := o.batch.Length()
+	sel := o.batch.Selection()
+	leftOrdNulls := o.batch.ColVec(o.leftOrdColIdx).Nulls()
+	rightOrdNulls := o.batch.ColVec(o.rightOrdColIdx).Nulls()
+	o.filterSel = o.filterSel[:0]
+	for i := uint16(0); i < n; i++ {
+		rowIdx := i
+		if sel != nil {
+			rowIdx = sel[i]
+		}
+		o.passed[rowIdx] = false
+		if leftOrdNulls.NullAt(rowIdx) || rightOrdNulls.NullAt(rowIdx) {
+			continue
+		}
+		o.filterSel = append(o.filterSel, rowIdx)
+	}
+	for _, rowIdx := range o.filter.filterBatch(ctx, o.batch, o.filterColIdxs, o.filterSel) {
+		o.passed[rowIdx] = true
+	}
+}
+
+// processTuple processes the tuple at o.batchIdx of the current batch. Note
+// that it might only finish the current run or group without advancing to the
+// next tuple.
+func (o *mergeJoinOuterFilterOp) processTuple() {
+	rowIdx := o.batchIdx
+	if sel := o.batch.Selection(); sel != nil {
+		rowIdx = sel[o.batchIdx]
+	}
+	leftOrdVec := o.batch.ColVec(o.leftOrdColIdx)
+	rightOrdVec := o.batch.ColVec(o.rightOrdColIdx)
+	if leftOrdVec.Nulls().NullAt(rowIdx) || rightOrdVec.Nulls().NullAt(rowIdx) {
+		// The tuple didn't have a match on the equality columns, so it is
+		// emitted as is, but the current run and group must be finished first.
+		if o.run.active {
+			o.finishRun()
+			return
+		}
+		if o.group.active {
+			o.finishGroup()
+			return
+		}
+		o.toEmit = append(o.toEmit, rowIdx)
+		o.batchIdx++
+		return
+	}
+	leftOrd := leftOrdVec.Int64()[rowIdx]
+	rightOrd := rightOrdVec.Int64()[rowIdx]
+	if o.run.active && leftOrd != o.run.leftOrd {
+		o.finishRun()
+		return
+	}
+	if !o.run.active {
+		if o.emitUnmatchedRight {
+			if o.group.active && rightOrd != o.group.firstRightOrd {
+				o.finishGroup()
+				return
+			}
+			if o.group.active {
+				// This is not the first run of the group, so all right tuples have
+				// already been buffered.
+				o.group.collecting = false
+			} else {
+				o.startGroup(rightOrd)
+			}
+		}
+		o.startRun(leftOrd, rowIdx)
+	}
+	if o.group.collecting {
+		o.bufferRightTuple(rowIdx)
+	}
+	if o.passed[rowIdx] {
+		o.toEmit = append(o.toEmit, rowIdx)
+		o.run.hasMatch = true
+		if o.emitUnmatchedRight {
+			o.group.matched[rightOrd-o.group.firstRightOrd] = true
+		}
+	}
+	o.batchIdx++
+}
+
+func (o *mergeJoinOuterFilterOp) startRun(leftOrd int64, rowIdx uint16) {
+	o.run.active = true
+	o.run.leftOrd = leftOrd
+	o.run.hasMatch = false
+	if o.emitUnmatchedLeft {
+		o.run.leftTuple.reset()
+		o.allocator.PerformOperation(o.run.leftTuple.colVecs, func() {
+			for i, t := range o.leftTypes {
+				o.run.leftTuple.colVecs[i].Append(coldata.SliceArgs{
+					ColType:     t,
+					Src:         o.batch.ColVec(i),
+					SrcStartIdx: uint64(rowIdx),
+					SrcEndIdx:   uint64(rowIdx) + 1,
+				})
+			}
+		})
+		o.run.leftTuple.length = 1
+	}
+}
+
+func (o *mergeJoinOuterFilterOp) finishRun() {
+	if !o.run.active {
+		return
+	}
+	o.run.active = false
+	if o.emitUnmatchedLeft && !o.run.hasMatch {
+		o.run.emittingUnmatched = true
+	}
+}
+
+func (o *mergeJoinOuterFilterOp) startGroup(firstRightOrd int64) {
+	o.group.active = true
+	o.group.firstRightOrd = firstRightOrd
+	o.group.collecting = true
+	o.group.rightTuples.reset()
+	o.group.matched = o.group.matched[:0]
+}
+
+func (o *mergeJoinOuterFilterOp) finishGroup() {
+	if !o.group.active {
+		return
+	}
+	o.group.active = false
+	o.group.collecting = false
+	if o.emitUnmatchedRight {
+		o.group.emittingUnmatched = true
+		o.group.emitIdx = 0
+	}
+}
+
+func (o *mergeJoinOuterFilterOp) bufferRightTuple(rowIdx uint16) {
+	tuples := o.group.rightTuples
+	o.allocator.PerformOperation(tuples.colVecs, func() {
+		for i, t := range o.rightTypes {
+			tuples.colVecs[i].Append(coldata.SliceArgs{
+				ColType:     t,
+				Src:         o.batch.ColVec(o.leftOrdColIdx + 1 + i),
+				DestIdx:     tuples.length,
+				SrcStartIdx: uint64(rowIdx),
+				SrcEndIdx:   uint64(rowIdx) + 1,
+			})
+		}
+	})
+	tuples.length++
+	o.group.matched = append(o.group.matched, false)
+}
+
+// flush copies all tuples from toEmit into the output.
+func (o *mergeJoinOuterFilterOp) flush() {
+	if len(o.toEmit) == 0 {
+		return
+	}
+	o.allocator.PerformOperation(o.output.ColVecs(), func() {
+		for i, t := range o.leftTypes {
+			o.output.ColVec(i).Copy(coldata.CopySliceArgs{
+				SliceArgs: coldata.SliceArgs{
+					ColType:   t,
+					Src:       o.batch.ColVec(i),
+					Sel:       o.toEmit,
+					DestIdx:   uint64(o.outputLen),
+					SrcEndIdx: uint64(len(o.toEmit)),
+				},
+			})
+		}
+		for i, t := range o.rightTypes {
+			o.output.ColVec(len(o.leftTypes) + i).Copy(coldata.CopySliceArgs{
+				SliceArgs: coldata.SliceArgs{
+					ColType:   t,
+					Src:       o.batch.ColVec(o.leftOrdColIdx + 1 + i),
+					Sel:       o.toEmit,
+					DestIdx:   uint64(o.outputLen),
+					SrcEndIdx: uint64(len(o.toEmit)),
+				},
+			})
+		}
+	})
+	o.outputLen += uint16(len(o.toEmit))
+	o.toEmit = o.toEmit[:0]
+}
+
+// emitUnmatchedLeftTuple emits the left tuple of the last run with NULLs in
+// the right columns.
+func (o *mergeJoinOuterFilterOp) emitUnmatchedLeftTuple() {
+	o.allocator.PerformOperation(o.output.ColVecs(), func() {
+		for i, t := range o.leftTypes {
+			o.output.ColVec(i).Copy(coldata.CopySliceArgs{
+				SliceArgs: coldata.SliceArgs{
+					ColType:   t,
+					Src:       o.run.leftTuple.colVecs[i],
+					DestIdx:   uint64(o.outputLen),
+					SrcEndIdx: 1,
+				},
+			})
+		}
+	})
+	for i := range o.rightTypes {
+		o.output.ColVec(len(o.leftTypes) + i).Nulls().SetNull(o.outputLen)
+	}
+	o.outputLen++
+	o.run.emittingUnmatched = false
+}
+
+// emitUnmatchedRightTuples emits the buffered right tuples of the last group
+// that didn't have a match with NULLs in the left columns. It emits as many
+// tuples as fit into the output batch.
+func (o *mergeJoinOuterFilterOp) emitUnmatchedRightTuples() {
+	tuples := o.group.rightTuples
+	for ; o.group.emitIdx < tuples.length && o.outputLen < o.outputBatchSize; o.group.emitIdx++ {
+		if o.group.matched[o.group.emitIdx] {
+			continue
+		}
+		o.allocator.PerformOperation(o.output.ColVecs(), func() {
+			for i, t := range o.rightTypes {
+				o.output.ColVec(len(o.leftTypes) + i).Copy(coldata.CopySliceArgs{
+					SliceArgs: coldata.SliceArgs{
+						ColType:     t,
+						Src:         tuples.colVecs[i],
+						DestIdx:     uint64(o.outputLen),
+						SrcStartIdx: o.group.emitIdx,
+						SrcEndIdx:   o.group.emitIdx + 1,
+					},
+				})
+			}
+		})
+		for i := range o.leftTypes {
+			o.output.ColVec(i).Nulls().SetNull(o.outputLen)
+		}
+		o.outputLen++
+	}
+	if o.group.emitIdx == tuples.length {
+		o.group.emittingUnmatched = false
+	}
+}
@@ -1559,6 +1559,125 @@ var mjTestCases = []joinTestCase{
 		onExpr:       execinfrapb.Expression{Expr: "@2 + @3 < 50"},
 		expected:     tuples{{nil, 0}, {2, 20}, {3, nil}},
 	},
+	{
+		description:  "LEFT OUTER JOIN test with ON expression (filter only on right)",
+		joinType:     sqlbase.JoinType_LEFT_OUTER,
+		leftTypes:    []coltypes.T{coltypes.Int64, coltypes.Int64},
+		rightTypes:   []coltypes.T{coltypes.Int64, coltypes.Int64},
+		leftTuples:   tuples{{nil, 0}, {1, 10}, {2, 20}, {3, nil}, {4, 40}},
+		rightTuples:  tuples{{1, nil}, {3, 13}, {4, 14}},
+		leftOutCols:  []uint32{0, 1},
+		rightOutCols: []uint32{0, 1},
+		leftEqCols:   []uint32{0},
+		rightEqCols:  []uint32{0},
+		onExpr:       execinfrapb.Expression{Expr: "@4 < 14"},
+		expected: tuples{
+			{nil, 0, nil, nil},
+			{1, 10, nil, nil},
+			{2, 20, nil, nil},
+			{3, nil, 3, 13},
+			{4, 40, nil, nil},
+		},
+	},
+	{
+		description:  "RIGHT OUTER JOIN test with ON expression (filter only on right)",
+		joinType:     sqlbase.JoinType_RIGHT_OUTER,
+		leftTypes:    []coltypes.T{coltypes.Int64, coltypes.Int64},
+		rightTypes:   []coltypes.T{coltypes.Int64, coltypes.Int64},
+		leftTuples:   tuples{{nil, 0}, {1, 10}, {2, 20}, {3, nil}, {4, 40}},
+		rightTuples:  tuples{{1, nil}, {3, 13}, {4, 14}},
+		leftOutCols:  []uint32{0, 1},
+		rightOutCols: []uint32{0, 1},
+		leftEqCols:   []uint32{0},
+		rightEqCols:  []uint32{0},
+		onExpr:       execinfrapb.Expression{Expr: "@4 < 14"},
+		expected: tuples{
+			{nil, nil, 1, nil},
+			{3, nil, 3, 13},
+			{nil, nil, 4, 14},
+		},
+	},
+	{
+		description:  "FULL OUTER JOIN test with ON expression (filter only on left)",
+		joinType:     sqlbase.JoinType_FULL_OUTER,
+		leftTypes:    []coltypes.T{coltypes.Int64, coltypes.Int64},
+		rightTypes:   []coltypes.T{coltypes.Int64, coltypes.Int64},
+		leftTuples:   tuples{{nil, 0}, {1, 10}, {2, 20}, {3, nil}, {4, 40}},
+		rightTuples:  tuples{{1, nil}, {3, 13}, {4, 14}},
+		leftOutCols:  []uint32{0, 1},
+		rightOutCols: []uint32{0, 1},
+		leftEqCols:   []uint32{0},
+		rightEqCols:  []uint32{0},
+		onExpr:       execinfrapb.Expression{Expr: "@2 < 40"},
+		expected: tuples{
+			{nil, 0, nil, nil},
+			{1, 10, 1, nil},
+			{2, 20, nil, nil},
+			{3, nil, nil, nil},
+			{nil, nil, 3, 13},
+			{4, 40, nil, nil},
+			{nil, nil, 4, 14},
+		},
+	},
+	{
+		description:  "LEFT OUTER JOIN test with ON expression (filter on both, duplicate keys)",
+		joinType:     sqlbase.JoinType_LEFT_OUTER,
+		leftTypes:    []coltypes.T{coltypes.Int64, coltypes.Int64},
+		rightTypes:   []coltypes.T{coltypes.Int64, coltypes.Int64},
+		leftTuples:   tuples{{1, 1}, {1, 2}, {1, 4}, {2, 3}},
+		rightTuples:  tuples{{1, 1}, {1, 2}, {1, 3}, {2, 5}},
+		leftOutCols:  []uint32{0, 1},
+		rightOutCols: []uint32{0, 1},
+		leftEqCols:   []uint32{0},
+		rightEqCols:  []uint32{0},
+		onExpr:       execinfrapb.Expression{Expr: "@2 = @4"},
+		expected: tuples{
+			{1, 1, 1, 1},
+			{1, 2, 1, 2},
+			{1, 4, nil, nil},
+			{2, 3, nil, nil},
+		},
+	},
+	{
+		description:  "RIGHT OUTER JOIN test with ON expression (filter on both, duplicate keys)",
+		joinType:     sqlbase.JoinType_RIGHT_OUTER,
+		leftTypes:    []coltypes.T{coltypes.Int64, coltypes.Int64},
+		rightTypes:   []coltypes.T{coltypes.Int64, coltypes.Int64},
+		leftTuples:   tuples{{1, 1}, {1, 2}, {1, 4}, {2, 3}},
+		rightTuples:  tuples{{1, 1}, {1, 2}, {1, 3}, {2, 5}},
+		leftOutCols:  []uint32{0, 1},
+		rightOutCols: []uint32{0, 1},
+		leftEqCols:   []uint32{0},
+		rightEqCols:  []uint32{0},
+		onExpr:       execinfrapb.Expression{Expr: "@2 = @4"},
+		expected: tuples{
+			{1, 1, 1, 1},
+			{1, 2, 1, 2},
+			{nil, nil, 1, 3},
+			{nil, nil, 2, 5},
+		},
+	},
+	{
+		description:  "FULL OUTER JOIN test with ON expression (filter on both, duplicate keys)",
+		joinType:     sqlbase.JoinType_FULL_OUTER,
+		leftTypes:    []coltypes.T{coltypes.Int64, coltypes.Int64},
+		rightTypes:   []coltypes.T{coltypes.Int64, coltypes.Int64},
+		leftTuples:   tuples{{1, 1}, {1, 2}, {1, 4}, {2, 3}},
+		rightTuples:  tuples{{1, 1}, {1, 2}, {1, 3}, {2, 5}},
+		leftOutCols:  []uint32{0, 1},
+		rightOutCols: []uint32{0, 1},
+		leftEqCols:   []uint32{0},
+		rightEqCols:  []uint32{0},
+		onExpr:       execinfrapb.Expression{Expr: "@2 = @4"},
+		expected: tuples{
+			{1, 1, 1, 1},
+			{1, 2, 1, 2},
+			{1, 4, nil, nil},
+			{nil, nil, 1, 3},
+			{2, 3, nil, nil},
+			{nil, nil, 2, 5},
+		},
+	},
 }
 
 func TestMergeJoiner(t *testing.T) {
@@ -1587,7 +1706,7 @@ func TestMergeJoiner(t *testing.T) {
 				output.input.Init()
 			}
 			verify := output.Verify
-			if _, isFullOuter := output.input.(*mergeJoinFullOuterOp); isFullOuter {
+			if tc.joinType == sqlbase.JoinType_FULL_OUTER {
 				// FULL OUTER JOIN doesn't guarantee any ordering on its output (since
 				// it is ambiguous), so we're comparing the outputs as sets.
 				verify = output.VerifyAnyOrder
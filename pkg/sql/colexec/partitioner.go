@@ -15,39 +15,26 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
-	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 )
 
-// NewWindowSortingPartitioner creates a new exec.Operator that orders input
-// first based on the partitionIdxs columns and second on ordCols (i.e. it
-// handles both PARTITION BY and ORDER BY clauses of a window function) and
-// puts true in partitionColIdx'th column (which is appended if needed) for
-// every tuple that is the first within its partition.
-func NewWindowSortingPartitioner(
+// NewWindowPartitioner creates a new exec.Operator that puts true in
+// partitionColIdx'th column (which is appended if needed) for every tuple that
+// is the first within its partition (i.e. it handles PARTITION BY clause of a
+// window function). The input must be ordered on the partitionIdxs columns.
+func NewWindowPartitioner(
 	allocator *Allocator,
 	input Operator,
 	inputTyps []coltypes.T,
 	partitionIdxs []uint32,
-	ordCols []execinfrapb.Ordering_Column,
 	partitionColIdx int,
 ) (op Operator, err error) {
-	partitionAndOrderingCols := make([]execinfrapb.Ordering_Column, len(partitionIdxs)+len(ordCols))
-	for i, idx := range partitionIdxs {
-		partitionAndOrderingCols[i] = execinfrapb.Ordering_Column{ColIdx: idx}
-	}
-	copy(partitionAndOrderingCols[len(partitionIdxs):], ordCols)
-	input, err = NewSorter(allocator, input, inputTyps, partitionAndOrderingCols)
-	if err != nil {
-		return nil, err
-	}
-
 	var distinctCol []bool
 	input, distinctCol, err = OrderedDistinctColsToOperators(input, partitionIdxs, inputTyps)
 	if err != nil {
 		return nil, err
 	}
 
-	return &windowSortingPartitioner{
+	return &windowPartitioner{
 		OneInputNode:    NewOneInputNode(input),
 		allocator:       allocator,
 		distinctCol:     distinctCol,
@@ -55,7 +42,7 @@ func NewWindowSortingPartitioner(
 	}, nil
 }
 
-type windowSortingPartitioner struct {
+type windowPartitioner struct {
 	OneInputNode
 
 	allocator *Allocator
@@ -66,11 +53,11 @@ type windowSortingPartitioner struct {
 	partitionColIdx int
 }
 
-func (p *windowSortingPartitioner) Init() {
+func (p *windowPartitioner) Init() {
 	p.input.Init()
 }
 
-func (p *windowSortingPartitioner) Next(ctx context.Context) coldata.Batch {
+func (p *windowPartitioner) Next(ctx context.Context) coldata.Batch {
 	b := p.input.Next(ctx)
 	if b.Length() == 0 {
 		return coldata.ZeroBatch
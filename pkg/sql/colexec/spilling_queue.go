@@ -35,6 +35,10 @@ import (
 // batches are unsafe for reuse, it is assumed that the previously returned
 // batch is not kept around and thus its referenced memory will be GCed as soon
 // as the batch is updated.
+// A spillingQueue can also be created as rewindable, in which case all batches
+// must be enqueued before any of them are dequeued, and the queue can be read
+// from the start multiple times using rewind. The in-memory batches are not
+// released when dequeued in this mode.
 type spillingQueue struct {
 	unlimitedAllocator *Allocator
 	maxMemoryLimit     int64
@@ -48,6 +52,15 @@ type spillingQueue struct {
 
 	diskQueueCfg colcontainer.DiskQueueCfg
 	diskQueue    colcontainer.Queue
+
+	rewindable      bool
+	rewindableState struct {
+		numItemsDequeued int
+		// diskBatch is the batch into which the batches from disk are
+		// deserialized. It is only used by a rewindable queue since all
+		// in-memory items must be kept intact.
+		diskBatch coldata.Batch
+	}
 }
 
 // newSpillingQueue creates a new spillingQueue. An unlimited allocator must be
@@ -83,11 +96,29 @@ func newSpillingQueue(
 	}
 }
 
+// newRewindableSpillingQueue creates a new spillingQueue that can be rewound
+// in order to dequeue all enqueued batches from the start again.
+func newRewindableSpillingQueue(
+	unlimitedAllocator *Allocator,
+	typs []coltypes.T,
+	memoryLimit int64,
+	cfg colcontainer.DiskQueueCfg,
+	batchSize int,
+) *spillingQueue {
+	q := newSpillingQueue(unlimitedAllocator, typs, memoryLimit, cfg, batchSize)
+	q.rewindable = true
+	return q
+}
+
 func (q *spillingQueue) enqueue(batch coldata.Batch) error {
 	if batch.Length() == 0 {
 		return nil
 	}
 
+	if q.rewindable && q.rewindableState.numItemsDequeued > 0 {
+		execerror.VectorizedInternalPanic("attempted to enqueue to rewindable spillingQueue after dequeue has been called")
+	}
+
 	if q.numOnDiskItems > 0 || q.unlimitedAllocator.Used() > q.maxMemoryLimit || q.numInMemoryItems == len(q.items) {
 		// In this case, there is not enough memory available to keep this batch in
 		// memory, or the in-memory circular buffer has no slots available (we do
@@ -116,6 +147,10 @@ func (q *spillingQueue) dequeue() (coldata.Batch, error) {
 		return coldata.ZeroBatch, nil
 	}
 
+	if q.rewindable {
+		return q.dequeueRewindable()
+	}
+
 	if q.numInMemoryItems == 0 {
 		// No more in-memory items. Fill the circular buffer as much as possible.
 		// Note that there must be at least one element on disk.
@@ -165,12 +200,46 @@ func (q *spillingQueue) dequeue() (coldata.Batch, error) {
 	return res, nil
 }
 
+// dequeueRewindable dequeues the next batch from a rewindable queue. All
+// in-memory items are returned first (since they were enqueued first),
+// followed by the items on disk.
+func (q *spillingQueue) dequeueRewindable() (coldata.Batch, error) {
+	if q.rewindableState.numItemsDequeued < q.numInMemoryItems {
+		res := q.items[q.rewindableState.numItemsDequeued]
+		q.rewindableState.numItemsDequeued++
+		return res, nil
+	}
+	if q.rewindableState.diskBatch == nil {
+		q.rewindableState.diskBatch = q.unlimitedAllocator.NewMemBatchWithSize(q.typs, 0 /* size */)
+	}
+	// Release the batch to make space for a new batch from disk.
+	q.unlimitedAllocator.ReleaseBatch(q.rewindableState.diskBatch)
+	ok, err := q.diskQueue.Dequeue(q.rewindableState.diskBatch)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		execerror.VectorizedInternalPanic("disk queue was not empty but failed to dequeue element in spillingQueue")
+	}
+	q.unlimitedAllocator.RetainBatch(q.rewindableState.diskBatch)
+	q.rewindableState.numItemsDequeued++
+	return q.rewindableState.diskBatch, nil
+}
+
 func (q *spillingQueue) maybeSpillToDisk() error {
 	if q.diskQueue != nil {
 		return nil
 	}
 	log.VEvent(context.TODO(), 1, "spilled to disk")
-	diskQueue, err := colcontainer.NewDiskQueue(q.typs, q.diskQueueCfg)
+	var (
+		diskQueue colcontainer.Queue
+		err       error
+	)
+	if q.rewindable {
+		diskQueue, err = colcontainer.NewRewindableDiskQueue(q.typs, q.diskQueueCfg)
+	} else {
+		diskQueue, err = colcontainer.NewDiskQueue(q.typs, q.diskQueueCfg)
+	}
 	if err != nil {
 		return err
 	}
@@ -180,6 +249,9 @@ func (q *spillingQueue) maybeSpillToDisk() error {
 
 // empty returns whether there are currently no items to be dequeued.
 func (q *spillingQueue) empty() bool {
+	if q.rewindable {
+		return q.rewindableState.numItemsDequeued == q.numInMemoryItems+q.numOnDiskItems
+	}
 	return q.numInMemoryItems == 0 && q.numOnDiskItems == 0
 }
 
@@ -194,6 +266,21 @@ func (q *spillingQueue) close() error {
 	return nil
 }
 
+// rewind resets a rewindable queue so that all the enqueued batches are
+// dequeued from the start.
+func (q *spillingQueue) rewind() error {
+	if !q.rewindable {
+		execerror.VectorizedInternalPanic("unexpectedly rewind() called when spilling queue is not rewindable")
+	}
+	if q.diskQueue != nil {
+		if err := q.diskQueue.(colcontainer.RewindableQueue).Rewind(); err != nil {
+			return err
+		}
+	}
+	q.rewindableState.numItemsDequeued = 0
+	return nil
+}
+
 func (q *spillingQueue) reset() {
 	if err := q.close(); err != nil {
 		execerror.VectorizedInternalPanic(err)
@@ -203,4 +290,5 @@ func (q *spillingQueue) reset() {
 	q.numOnDiskItems = 0
 	q.curHeadIdx = 0
 	q.curTailIdx = 0
+	q.rewindableState.numItemsDequeued = 0
 }
@@ -0,0 +1,155 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+// newStringAggAgg creates a STRING_AGG aggregate that concatenates all
+// non-null values of the input column separated by the delimiter. arguments
+// are the constant arguments of the aggregate, and the delimiter is the first
+// of them (if present and not NULL).
+func newStringAggAgg(
+	allocator *Allocator, t coltypes.T, arguments tree.Datums,
+) (aggregateFunc, error) {
+	if t != coltypes.Bytes {
+		return nil, errors.Errorf("unsupported string_agg agg type %s", t)
+	}
+	var delimiter []byte
+	if len(arguments) > 0 {
+		switch d := arguments[0].(type) {
+		case *tree.DString:
+			delimiter = []byte(*d)
+		case *tree.DBytes:
+			delimiter = []byte(*d)
+		}
+	}
+	return &stringAggAgg{allocator: allocator, delimiter: delimiter}, nil
+}
+
+// stringAggAgg implements the STRING_AGG aggregate with a constant delimiter.
+type stringAggAgg struct {
+	allocator *Allocator
+	delimiter []byte
+	done      bool
+	groups    []bool
+	vec       coldata.Vec
+	col       *coldata.Bytes
+	nulls     *coldata.Nulls
+	curIdx    int
+	// curAgg is the concatenation of the values of the current group seen so
+	// far.
+	curAgg                      []byte
+	foundNonNullForCurrentGroup bool
+}
+
+var _ aggregateFunc = &stringAggAgg{}
+
+func (a *stringAggAgg) Init(groups []bool, vec coldata.Vec) {
+	a.groups = groups
+	a.vec = vec
+	a.col = vec.Bytes()
+	a.nulls = vec.Nulls()
+	a.Reset()
+}
+
+func (a *stringAggAgg) Reset() {
+	a.curIdx = -1
+	a.curAgg = a.curAgg[:0]
+	a.done = false
+	a.foundNonNullForCurrentGroup = false
+	a.nulls.UnsetNulls()
+}
+
+func (a *stringAggAgg) CurrentOutputIndex() int {
+	return a.curIdx
+}
+
+func (a *stringAggAgg) SetOutputIndex(idx int) {
+	if a.curIdx != -1 {
+		a.curIdx = idx
+		a.nulls.UnsetNullsAfter(uint16(idx + 1))
+	}
+}
+
+// flush writes the result of the current group into the output.
+func (a *stringAggAgg) flush() {
+	if !a.foundNonNullForCurrentGroup {
+		a.nulls.SetNull(uint16(a.curIdx))
+	} else {
+		a.col.Set(a.curIdx, a.curAgg)
+	}
+}
+
+func (a *stringAggAgg) Compute(b coldata.Batch, inputIdxs []uint32) {
+	if a.done {
+		return
+	}
+	inputLen := b.Length()
+	if inputLen == 0 {
+		a.allocator.PerformOperation([]coldata.Vec{a.vec}, a.flush)
+		a.curIdx++
+		a.done = true
+		return
+	}
+	vec, sel := b.ColVec(int(inputIdxs[0])), b.Selection()
+	col, nulls := vec.Bytes(), vec.Nulls()
+	a.allocator.PerformOperation(
+		[]coldata.Vec{a.vec},
+		func() {
+			if sel != nil {
+				for _, i := range sel[:inputLen] {
+					a.accumulate(col, nulls, int(i))
+				}
+			} else {
+				for i := 0; i < int(inputLen); i++ {
+					a.accumulate(col, nulls, i)
+				}
+			}
+		},
+	)
+}
+
+// accumulate adds the ith value of col into the aggregate.
+func (a *stringAggAgg) accumulate(col *coldata.Bytes, nulls *coldata.Nulls, i int) {
+	if a.groups[i] {
+		// The check is necessary because for the first group in the result set
+		// there is no "current group."
+		if a.curIdx >= 0 {
+			a.flush()
+		}
+		a.curIdx++
+		a.curAgg = a.curAgg[:0]
+		a.foundNonNullForCurrentGroup = false
+	}
+	if nulls.NullAt(uint16(i)) {
+		return
+	}
+	oldCap := cap(a.curAgg)
+	if a.foundNonNullForCurrentGroup {
+		a.curAgg = append(a.curAgg, a.delimiter...)
+	}
+	a.curAgg = append(a.curAgg, col.Get(i)...)
+	a.foundNonNullForCurrentGroup = true
+	// curAgg is not a part of the output vector, so its growth is not captured
+	// by PerformOperation and has to be accounted for separately.
+	if newCap := cap(a.curAgg); newCap != oldCap {
+		a.allocator.AdjustMemoryUsage(int64(newCap - oldCap))
+	}
+}
+
+func (a *stringAggAgg) HandleEmptyInputScalar() {
+	a.nulls.SetNull(0)
+}
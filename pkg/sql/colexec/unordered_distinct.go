@@ -39,7 +39,7 @@ func NewUnorderedDistinct(
 		OneInputNode: NewOneInputNode(input),
 		allocator:    allocator,
 		ht:           ht,
-		output:       allocator.NewMemBatch(ht.outTypes),
+		colTypes:     colTypes,
 	}
 }
 
@@ -55,6 +55,7 @@ type unorderedDistinct struct {
 
 	allocator     *Allocator
 	ht            *hashTable
+	colTypes      []coltypes.T
 	buildFinished bool
 
 	// sel is a list of indices to select representing the distinct rows.
@@ -63,15 +64,28 @@ type unorderedDistinct struct {
 
 	output           coldata.Batch
 	outputBatchStart uint64
+
+	exportBufferedState struct {
+		windowedBatch coldata.Batch
+		exported      uint64
+	}
 }
 
-var _ Operator = &unorderedDistinct{}
+var _ bufferingInMemoryOperator = &unorderedDistinct{}
 
 func (op *unorderedDistinct) Init() {
 	op.input.Init()
 }
 
 func (op *unorderedDistinct) Next(ctx context.Context) coldata.Batch {
+	if op.output == nil {
+		// We allocate the batches lazily so that the unordered distinct that
+		// runs out of memory right away could still fall back to the disk-backed
+		// one. Note that nothing has been buffered at this point, so the export
+		// batch will not be needed if either of these allocations fails.
+		op.exportBufferedState.windowedBatch = op.ht.newExportBatch(op.colTypes)
+		op.output = op.allocator.NewMemBatch(op.ht.outTypes)
+	}
 	op.output.ResetInternalBatch()
 	// First, build the hash table.
 	if !op.buildFinished {
@@ -133,10 +147,29 @@ func (op *unorderedDistinct) Next(ctx context.Context) coldata.Batch {
 	return op.output
 }
 
+// ExportBuffered is part of the bufferingInMemoryOperator interface. The
+// memory limit can only be reached while the hash table is being built, so we
+// simply return all of the tuples buffered in the hash table so far.
+func (op *unorderedDistinct) ExportBuffered(Operator) coldata.Batch {
+	if op.exportBufferedState.exported == op.ht.vals.length {
+		return coldata.ZeroBatch
+	}
+	newExported := op.exportBufferedState.exported + uint64(coldata.BatchSize())
+	if newExported > op.ht.vals.length {
+		newExported = op.ht.vals.length
+	}
+	b := op.ht.exportVals(
+		op.exportBufferedState.windowedBatch, op.exportBufferedState.exported, newExported,
+	)
+	op.exportBufferedState.exported = newExported
+	return b
+}
+
 // Reset resets the unorderedDistinct for another run. Primarily used for
 // benchmarks.
 func (op *unorderedDistinct) reset() {
 	op.outputBatchStart = 0
 	op.ht.vals.reset()
 	op.buildFinished = false
+	op.exportBufferedState.exported = 0
 }
@@ -0,0 +1,526 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+
+	"github.com/cockroachdb/apd"
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/arith"
+	"github.com/cockroachdb/errors"
+)
+
+// windowAggregate is an aggregate function that is computed over a window
+// frame using the sliding window approach: the tuples that enter the frame are
+// added to the aggregation, and the tuples that leave the frame are removed
+// from it. The tuples are always removed in the same order in which they were
+// added.
+type windowAggregate interface {
+	// reset prepares the aggregate for the computation over a new partition.
+	reset(partition *windowPartition)
+	// add includes the tuple with index idx of the partition into the
+	// aggregation.
+	add(idx uint64)
+	// remove excludes the tuple with index idx of the partition (that has been
+	// previously added) from the aggregation.
+	remove(idx uint64)
+	// setResult writes the result of the aggregation over all tuples that are
+	// currently included into the output vector at position outputIdx.
+	setResult(output coldata.Vec, outputIdx uint16)
+}
+
+// windowAggregateFunc computes an aggregate function over the window frame of
+// every tuple.
+type windowAggregateFunc struct {
+	windowFramer
+
+	agg windowAggregate
+	// filterColIdx is the index of a boolean column that determines whether a
+	// tuple should be included into the aggregation (the tuple is skipped unless
+	// the value is true). It is -1 if there is no FILTER clause.
+	filterColIdx int
+	// prevStart and prevEnd are the boundaries of the window frame of the
+	// previous tuple.
+	prevStart, prevEnd uint64
+}
+
+var _ windowFunction = &windowAggregateFunc{}
+
+func (w *windowAggregateFunc) startPartition(partition *windowPartition) {
+	w.windowFramer.reset(partition)
+	w.agg.reset(partition)
+	w.prevStart, w.prevEnd = 0, 0
+}
+
+func (w *windowAggregateFunc) isSkipped(idx uint64) bool {
+	if w.filterColIdx < 0 {
+		return false
+	}
+	filterVec := w.partition.vecs[w.filterColIdx]
+	return filterVec.Nulls().NullAt64(idx) || !filterVec.Bool()[idx]
+}
+
+func (w *windowAggregateFunc) compute(output coldata.Vec, startIdx, endIdx uint64) {
+	for i := startIdx; i < endIdx; i++ {
+		w.next()
+		start, end := w.frameBounds()
+		// First, we remove all tuples that have left the window frame.
+		for idx := w.prevStart; idx < start && idx < w.prevEnd; idx++ {
+			if !w.isSkipped(idx) {
+				w.agg.remove(idx)
+			}
+		}
+		// Then we add all tuples that have just entered the window frame.
+		idx := w.prevEnd
+		if idx < start {
+			idx = start
+		}
+		for ; idx < end; idx++ {
+			if !w.isSkipped(idx) {
+				w.agg.add(idx)
+			}
+		}
+		w.prevStart, w.prevEnd = start, end
+		w.agg.setResult(output, uint16(i-startIdx))
+	}
+}
+
+// isWindowAggregateSupported returns whether the aggregate function with the
+// given argument types can be computed as a window function by the vectorized
+// engine.
+func isWindowAggregateSupported(
+	aggFn execinfrapb.AggregatorSpec_Func, argTypes []coltypes.T,
+) bool {
+	switch aggFn {
+	case execinfrapb.AggregatorSpec_COUNT_ROWS:
+		return len(argTypes) == 0
+	case execinfrapb.AggregatorSpec_COUNT:
+		return len(argTypes) == 1
+	case execinfrapb.AggregatorSpec_MIN, execinfrapb.AggregatorSpec_MAX:
+		return len(argTypes) == 1 && argTypes[0] != coltypes.Unhandled
+	case execinfrapb.AggregatorSpec_BOOL_AND, execinfrapb.AggregatorSpec_BOOL_OR:
+		return len(argTypes) == 1 && argTypes[0] == coltypes.Bool
+	case execinfrapb.AggregatorSpec_SUM_INT:
+		return len(argTypes) == 1 && argTypes[0] == coltypes.Int64
+	case execinfrapb.AggregatorSpec_SUM, execinfrapb.AggregatorSpec_AVG:
+		if len(argTypes) != 1 {
+			return false
+		}
+		switch argTypes[0] {
+		case coltypes.Int16, coltypes.Int32, coltypes.Int64, coltypes.Float64, coltypes.Decimal:
+			return true
+		}
+	}
+	return false
+}
+
+// newWindowAggregate creates a windowAggregate for the aggregate function
+// with the given argument types. argIdx is the index of the argument column
+// (it is ignored for COUNT_ROWS).
+func newWindowAggregate(
+	aggFn execinfrapb.AggregatorSpec_Func, argTypes []coltypes.T, argIdx int,
+) (windowAggregate, error) {
+	if !isWindowAggregateSupported(aggFn, argTypes) {
+		return nil, errors.Errorf(
+			"unsupported window aggregate function %s on %s", aggFn, argTypes,
+		)
+	}
+	switch aggFn {
+	case execinfrapb.AggregatorSpec_COUNT_ROWS:
+		return &countWindowAgg{argIdx: -1}, nil
+	case execinfrapb.AggregatorSpec_COUNT:
+		return &countWindowAgg{argIdx: argIdx}, nil
+	case execinfrapb.AggregatorSpec_MIN, execinfrapb.AggregatorSpec_MAX:
+		return &minMaxWindowAgg{
+			argIdx: argIdx,
+			typ:    argTypes[0],
+			isMin:  aggFn == execinfrapb.AggregatorSpec_MIN,
+		}, nil
+	case execinfrapb.AggregatorSpec_BOOL_AND, execinfrapb.AggregatorSpec_BOOL_OR:
+		return &boolWindowAgg{
+			argIdx: argIdx,
+			isAnd:  aggFn == execinfrapb.AggregatorSpec_BOOL_AND,
+		}, nil
+	case execinfrapb.AggregatorSpec_SUM_INT:
+		return &sumIntWindowAgg{argIdx: argIdx}, nil
+	default:
+		return &sumWindowAgg{
+			argIdx: argIdx,
+			typ:    argTypes[0],
+			isAvg:  aggFn == execinfrapb.AggregatorSpec_AVG,
+		}, nil
+	}
+}
+
+// countWindowAgg computes COUNT (when argIdx is non-negative) and COUNT_ROWS
+// (when argIdx is -1) aggregates.
+type countWindowAgg struct {
+	argIdx int
+	nulls  *coldata.Nulls
+	count  int64
+}
+
+func (a *countWindowAgg) reset(partition *windowPartition) {
+	a.count = 0
+	if a.argIdx >= 0 {
+		a.nulls = partition.vecs[a.argIdx].Nulls()
+	}
+}
+
+func (a *countWindowAgg) add(idx uint64) {
+	if a.argIdx < 0 || !a.nulls.NullAt64(idx) {
+		a.count++
+	}
+}
+
+func (a *countWindowAgg) remove(idx uint64) {
+	if a.argIdx < 0 || !a.nulls.NullAt64(idx) {
+		a.count--
+	}
+}
+
+func (a *countWindowAgg) setResult(output coldata.Vec, outputIdx uint16) {
+	output.Int64()[outputIdx] = a.count
+}
+
+// sumWindowAgg computes SUM and AVG aggregates. The sums of integers are
+// computed as decimals (in order to not overflow), and the sums of floats are
+// computed as floats.
+type sumWindowAgg struct {
+	argIdx int
+	typ    coltypes.T
+	isAvg  bool
+
+	vec coldata.Vec
+	// nonNullCount is the number of non-NULL values that are currently
+	// included into the sum.
+	nonNullCount int64
+	decSum       apd.Decimal
+	floatSum     float64
+	tmpDec       apd.Decimal
+}
+
+func (a *sumWindowAgg) reset(partition *windowPartition) {
+	a.vec = partition.vecs[a.argIdx]
+	a.nonNullCount = 0
+	a.decSum.SetFinite(0, 0)
+	a.floatSum = 0
+}
+
+func (a *sumWindowAgg) addValue(idx uint64, negative bool) {
+	if a.vec.Nulls().NullAt64(idx) {
+		return
+	}
+	if negative {
+		a.nonNullCount--
+	} else {
+		a.nonNullCount++
+	}
+	switch a.typ {
+	case coltypes.Float64:
+		v := a.vec.Float64()[idx]
+		if negative {
+			v = -v
+		}
+		a.floatSum += v
+		return
+	case coltypes.Decimal:
+		a.tmpDec.Set(&a.vec.Decimal()[idx])
+	case coltypes.Int16:
+		a.tmpDec.SetFinite(int64(a.vec.Int16()[idx]), 0)
+	case coltypes.Int32:
+		a.tmpDec.SetFinite(int64(a.vec.Int32()[idx]), 0)
+	case coltypes.Int64:
+		a.tmpDec.SetFinite(a.vec.Int64()[idx], 0)
+	default:
+		execerror.VectorizedInternalPanic(fmt.Sprintf("unexpected sum type %s", a.typ))
+	}
+	if negative {
+		a.tmpDec.Neg(&a.tmpDec)
+	}
+	if _, err := tree.ExactCtx.Add(&a.decSum, &a.decSum, &a.tmpDec); err != nil {
+		execerror.NonVectorizedPanic(err)
+	}
+}
+
+func (a *sumWindowAgg) add(idx uint64) {
+	a.addValue(idx, false /* negative */)
+}
+
+func (a *sumWindowAgg) remove(idx uint64) {
+	a.addValue(idx, true /* negative */)
+}
+
+func (a *sumWindowAgg) setResult(output coldata.Vec, outputIdx uint16) {
+	if a.nonNullCount == 0 {
+		// Either the window frame is empty or it contains only NULL values.
+		output.Nulls().SetNull(outputIdx)
+		return
+	}
+	if a.typ == coltypes.Float64 {
+		if a.isAvg {
+			output.Float64()[outputIdx] = a.floatSum / float64(a.nonNullCount)
+		} else {
+			output.Float64()[outputIdx] = a.floatSum
+		}
+		return
+	}
+	result := &output.Decimal()[outputIdx]
+	if a.isAvg {
+		a.tmpDec.SetFinite(a.nonNullCount, 0)
+		if _, err := tree.DecimalCtx.Quo(result, &a.decSum, &a.tmpDec); err != nil {
+			execerror.NonVectorizedPanic(err)
+		}
+	} else {
+		result.Set(&a.decSum)
+	}
+}
+
+// sumIntWindowAgg computes SUM_INT aggregate.
+type sumIntWindowAgg struct {
+	argIdx int
+
+	vec          coldata.Vec
+	nonNullCount int64
+	sum          int64
+}
+
+func (a *sumIntWindowAgg) reset(partition *windowPartition) {
+	a.vec = partition.vecs[a.argIdx]
+	a.nonNullCount = 0
+	a.sum = 0
+}
+
+func (a *sumIntWindowAgg) add(idx uint64) {
+	if a.vec.Nulls().NullAt64(idx) {
+		return
+	}
+	var ok bool
+	a.sum, ok = arith.AddWithOverflow(a.sum, a.vec.Int64()[idx])
+	if !ok {
+		execerror.NonVectorizedPanic(tree.ErrIntOutOfRange)
+	}
+	a.nonNullCount++
+}
+
+func (a *sumIntWindowAgg) remove(idx uint64) {
+	if a.vec.Nulls().NullAt64(idx) {
+		return
+	}
+	var ok bool
+	a.sum, ok = arith.SubWithOverflow(a.sum, a.vec.Int64()[idx])
+	if !ok {
+		execerror.NonVectorizedPanic(tree.ErrIntOutOfRange)
+	}
+	a.nonNullCount--
+}
+
+func (a *sumIntWindowAgg) setResult(output coldata.Vec, outputIdx uint16) {
+	if a.nonNullCount == 0 {
+		output.Nulls().SetNull(outputIdx)
+		return
+	}
+	output.Int64()[outputIdx] = a.sum
+}
+
+// boolWindowAgg computes BOOL_AND and BOOL_OR aggregates.
+type boolWindowAgg struct {
+	argIdx int
+	isAnd  bool
+
+	vec                   coldata.Vec
+	trueCount, falseCount int64
+}
+
+func (a *boolWindowAgg) reset(partition *windowPartition) {
+	a.vec = partition.vecs[a.argIdx]
+	a.trueCount, a.falseCount = 0, 0
+}
+
+func (a *boolWindowAgg) addValue(idx uint64, delta int64) {
+	if a.vec.Nulls().NullAt64(idx) {
+		return
+	}
+	if a.vec.Bool()[idx] {
+		a.trueCount += delta
+	} else {
+		a.falseCount += delta
+	}
+}
+
+func (a *boolWindowAgg) add(idx uint64) {
+	a.addValue(idx, 1)
+}
+
+func (a *boolWindowAgg) remove(idx uint64) {
+	a.addValue(idx, -1)
+}
+
+func (a *boolWindowAgg) setResult(output coldata.Vec, outputIdx uint16) {
+	if a.trueCount == 0 && a.falseCount == 0 {
+		output.Nulls().SetNull(outputIdx)
+		return
+	}
+	if a.isAnd {
+		output.Bool()[outputIdx] = a.falseCount == 0
+	} else {
+		output.Bool()[outputIdx] = a.trueCount > 0
+	}
+}
+
+// minMaxWindowAgg computes MIN and MAX aggregates. It maintains a deque of
+// indices of the tuples in the window frame such that the values are
+// monotonic (non-decreasing for MIN and non-increasing for MAX), so the
+// result is always at the front of the deque.
+type minMaxWindowAgg struct {
+	argIdx int
+	typ    coltypes.T
+	isMin  bool
+
+	vec   coldata.Vec
+	cmp   func(i, j uint64) int
+	deque []uint64
+}
+
+func (a *minMaxWindowAgg) reset(partition *windowPartition) {
+	a.vec = partition.vecs[a.argIdx]
+	a.cmp = makeWindowValueComparator(a.typ, a.vec)
+	a.deque = a.deque[:0]
+}
+
+func (a *minMaxWindowAgg) add(idx uint64) {
+	if a.vec.Nulls().NullAt64(idx) {
+		return
+	}
+	// Remove all values that can never become the result because the new
+	// value is "better" and will stay in the window frame longer.
+	for len(a.deque) > 0 {
+		cmp := a.cmp(a.deque[len(a.deque)-1], idx)
+		if !a.isMin {
+			cmp = -cmp
+		}
+		if cmp < 0 {
+			break
+		}
+		a.deque = a.deque[:len(a.deque)-1]
+	}
+	a.deque = append(a.deque, idx)
+}
+
+func (a *minMaxWindowAgg) remove(idx uint64) {
+	if len(a.deque) > 0 && a.deque[0] == idx {
+		a.deque = a.deque[1:]
+	}
+}
+
+func (a *minMaxWindowAgg) setResult(output coldata.Vec, outputIdx uint16) {
+	if len(a.deque) == 0 {
+		output.Nulls().SetNull(outputIdx)
+		return
+	}
+	copyWindowValue(output, a.typ, a.vec, a.deque[0], outputIdx)
+}
+
+// makeWindowValueComparator returns a function that compares two non-NULL
+// values of vec at the given indices. It returns -1, 0, or 1.
+func makeWindowValueComparator(typ coltypes.T, vec coldata.Vec) func(i, j uint64) int {
+	switch typ {
+	case coltypes.Bool:
+		col := vec.Bool()
+		return func(i, j uint64) int {
+			if col[i] == col[j] {
+				return 0
+			} else if !col[i] {
+				return -1
+			}
+			return 1
+		}
+	case coltypes.Bytes:
+		col := vec.Bytes()
+		return func(i, j uint64) int {
+			return bytes.Compare(col.Get(int(i)), col.Get(int(j)))
+		}
+	case coltypes.Decimal:
+		col := vec.Decimal()
+		return func(i, j uint64) int {
+			return tree.CompareDecimals(&col[i], &col[j])
+		}
+	case coltypes.Int16:
+		col := vec.Int16()
+		return func(i, j uint64) int {
+			return compareInts(int64(col[i]), int64(col[j]))
+		}
+	case coltypes.Int32:
+		col := vec.Int32()
+		return func(i, j uint64) int {
+			return compareInts(int64(col[i]), int64(col[j]))
+		}
+	case coltypes.Int64:
+		col := vec.Int64()
+		return func(i, j uint64) int {
+			return compareInts(col[i], col[j])
+		}
+	case coltypes.Float64:
+		col := vec.Float64()
+		return func(i, j uint64) int {
+			a, b := col[i], col[j]
+			if a < b {
+				return -1
+			} else if a > b {
+				return 1
+			} else if a == b {
+				return 0
+			} else if math.IsNaN(a) {
+				// NaN is smaller than any other value.
+				if math.IsNaN(b) {
+					return 0
+				}
+				return -1
+			}
+			return 1
+		}
+	case coltypes.Timestamp:
+		col := vec.Timestamp()
+		return func(i, j uint64) int {
+			if col[i].Before(col[j]) {
+				return -1
+			} else if col[j].Before(col[i]) {
+				return 1
+			}
+			return 0
+		}
+	case coltypes.Interval:
+		col := vec.Interval()
+		return func(i, j uint64) int {
+			return col[i].Compare(col[j])
+		}
+	default:
+		execerror.VectorizedInternalPanic(fmt.Sprintf("unhandled type %s", typ))
+		// This code is unreachable, but the compiler cannot infer that.
+		return nil
+	}
+}
+
+func compareInts(a, b int64) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
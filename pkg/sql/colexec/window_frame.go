@@ -0,0 +1,264 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+)
+
+// windowPartition describes a single fully buffered partition of the input to
+// the buffered window operator.
+type windowPartition struct {
+	// vecs contains all of the buffered input columns of the partition.
+	vecs []coldata.Vec
+	// peers contains true for every tuple that starts a new peer group
+	// (according to the ordering of the window functions). The first tuple of
+	// the partition always starts a new peer group.
+	peers []bool
+	// n is the number of tuples in the partition.
+	n uint64
+}
+
+// peerGroupTracker keeps track of the peer group of the "current" tuple while
+// the tuples of the partition are iterated over in order.
+type peerGroupTracker struct {
+	partition *windowPartition
+	// rowIdx is the index of the current tuple within the partition.
+	rowIdx uint64
+	// groupIdx is the ordinal number of the peer group (counting from zero) that
+	// the current tuple belongs to.
+	groupIdx uint64
+	// groupStart and groupEnd are the index of the first tuple of the current
+	// peer group and the index of the first tuple after that peer group.
+	groupStart, groupEnd uint64
+	// started indicates whether the first tuple of the partition has been
+	// visited.
+	started bool
+}
+
+func (t *peerGroupTracker) reset(partition *windowPartition) {
+	*t = peerGroupTracker{partition: partition}
+}
+
+// next advances the tracker to the next tuple of the partition.
+func (t *peerGroupTracker) next() {
+	if !t.started {
+		t.started = true
+	} else {
+		t.rowIdx++
+		if t.partition.peers[t.rowIdx] {
+			t.groupIdx++
+			t.groupStart = t.rowIdx
+		}
+	}
+	if t.groupEnd <= t.rowIdx {
+		// We have just moved into a new peer group, so we need to find where it
+		// ends.
+		t.groupEnd = t.rowIdx + 1
+		for t.groupEnd < t.partition.n && !t.partition.peers[t.groupEnd] {
+			t.groupEnd++
+		}
+	}
+}
+
+// peerGroupCursor is a helper that finds the first tuple of a peer group with
+// the given ordinal number. The sequence of ordinal numbers it is asked about
+// must be non-decreasing, which allows for amortized constant time lookups.
+type peerGroupCursor struct {
+	partition *windowPartition
+	// idx is the index of the first tuple of the peer group with ordinal number
+	// groupIdx (or the size of the partition if there is no such peer group).
+	idx      uint64
+	groupIdx uint64
+}
+
+func (c *peerGroupCursor) reset(partition *windowPartition) {
+	*c = peerGroupCursor{partition: partition}
+}
+
+// seek returns the index of the first tuple of the peer group with ordinal
+// number groupIdx. If the partition has fewer peer groups, then the size of
+// the partition is returned.
+func (c *peerGroupCursor) seek(groupIdx uint64) uint64 {
+	for c.groupIdx < groupIdx && c.idx < c.partition.n {
+		c.idx++
+		for c.idx < c.partition.n && !c.partition.peers[c.idx] {
+			c.idx++
+		}
+		c.groupIdx++
+	}
+	return c.idx
+}
+
+// windowFramer computes the boundaries of the window frame for every tuple of
+// the partition. The tuples must be visited in order, and the boundaries of
+// the frame are guaranteed to be non-decreasing which allows the window
+// functions to use the sliding window approach.
+//
+// Only integer offsets (i.e. ROWS and GROUPS modes) are supported.
+type windowFramer struct {
+	peerGroupTracker
+
+	frame *execinfrapb.WindowerSpec_Frame
+	// startCursor and endCursor are used in GROUPS mode to find the boundaries
+	// of the peer groups that are at an offset from the current peer group.
+	startCursor, endCursor peerGroupCursor
+}
+
+func (f *windowFramer) reset(partition *windowPartition) {
+	f.peerGroupTracker.reset(partition)
+	f.startCursor.reset(partition)
+	f.endCursor.reset(partition)
+}
+
+// frameBounds returns the index of the first tuple of the window frame of the
+// current tuple as well as the index of the first tuple after the frame. The
+// returned end index is never smaller than the start index, so an empty frame
+// is always represented by equal indices.
+func (f *windowFramer) frameBounds() (start, end uint64) {
+	start = f.frameStartIdx()
+	end = f.frameEndIdx()
+	if end < start {
+		end = start
+	}
+	return start, end
+}
+
+func (f *windowFramer) frameStartIdx() uint64 {
+	if f.frame == nil {
+		return 0
+	}
+	n := f.partition.n
+	bound := f.frame.Bounds.Start
+	switch bound.BoundType {
+	case execinfrapb.WindowerSpec_Frame_UNBOUNDED_PRECEDING:
+		return 0
+	case execinfrapb.WindowerSpec_Frame_CURRENT_ROW:
+		if f.frame.Mode == execinfrapb.WindowerSpec_Frame_ROWS {
+			return f.rowIdx
+		}
+		return f.groupStart
+	}
+	offset := bound.IntOffset
+	switch f.frame.Mode {
+	case execinfrapb.WindowerSpec_Frame_ROWS:
+		switch bound.BoundType {
+		case execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING:
+			if offset > f.rowIdx {
+				return 0
+			}
+			return f.rowIdx - offset
+		case execinfrapb.WindowerSpec_Frame_OFFSET_FOLLOWING:
+			if offset >= n-f.rowIdx {
+				return n
+			}
+			return f.rowIdx + offset
+		}
+	case execinfrapb.WindowerSpec_Frame_GROUPS:
+		switch bound.BoundType {
+		case execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING:
+			if offset > f.groupIdx {
+				return 0
+			}
+			return f.startCursor.seek(f.groupIdx - offset)
+		case execinfrapb.WindowerSpec_Frame_OFFSET_FOLLOWING:
+			if offset >= n {
+				return n
+			}
+			return f.startCursor.seek(f.groupIdx + offset)
+		}
+	}
+	execerror.VectorizedInternalPanic(
+		"unexpected window frame start bound " + f.frame.String(),
+	)
+	// This code is unreachable, but the compiler cannot infer that.
+	return 0
+}
+
+func (f *windowFramer) frameEndIdx() uint64 {
+	if f.frame == nil || f.frame.Bounds.End == nil {
+		// The end bound of CURRENT ROW is assumed when it is omitted.
+		if f.frame != nil && f.frame.Mode == execinfrapb.WindowerSpec_Frame_ROWS {
+			return f.rowIdx + 1
+		}
+		return f.groupEnd
+	}
+	n := f.partition.n
+	bound := f.frame.Bounds.End
+	switch bound.BoundType {
+	case execinfrapb.WindowerSpec_Frame_UNBOUNDED_FOLLOWING:
+		return n
+	case execinfrapb.WindowerSpec_Frame_CURRENT_ROW:
+		if f.frame.Mode == execinfrapb.WindowerSpec_Frame_ROWS {
+			return f.rowIdx + 1
+		}
+		return f.groupEnd
+	}
+	offset := bound.IntOffset
+	switch f.frame.Mode {
+	case execinfrapb.WindowerSpec_Frame_ROWS:
+		switch bound.BoundType {
+		case execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING:
+			if offset > f.rowIdx+1 {
+				return 0
+			}
+			return f.rowIdx + 1 - offset
+		case execinfrapb.WindowerSpec_Frame_OFFSET_FOLLOWING:
+			if offset >= n-f.rowIdx-1 {
+				return n
+			}
+			return f.rowIdx + offset + 1
+		}
+	case execinfrapb.WindowerSpec_Frame_GROUPS:
+		switch bound.BoundType {
+		case execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING:
+			if offset > f.groupIdx {
+				// The end bound's peer group is "outside" of the partition.
+				return 0
+			}
+			return f.endCursor.seek(f.groupIdx - offset + 1)
+		case execinfrapb.WindowerSpec_Frame_OFFSET_FOLLOWING:
+			if offset >= n {
+				return n
+			}
+			return f.endCursor.seek(f.groupIdx + offset + 1)
+		}
+	}
+	execerror.VectorizedInternalPanic(
+		"unexpected window frame end bound " + f.frame.String(),
+	)
+	// This code is unreachable, but the compiler cannot infer that.
+	return 0
+}
+
+// isWindowFrameSupported returns whether the window frame can be handled by
+// windowFramer.
+func isWindowFrameSupported(frame *execinfrapb.WindowerSpec_Frame) bool {
+	if frame == nil {
+		return true
+	}
+	if frame.Exclusion != execinfrapb.WindowerSpec_Frame_NO_EXCLUSION {
+		return false
+	}
+	if frame.Mode != execinfrapb.WindowerSpec_Frame_RANGE {
+		return true
+	}
+	// In RANGE mode, only the bounds that do not require an offset are
+	// supported.
+	isOffset := func(bound execinfrapb.WindowerSpec_Frame_Bound) bool {
+		return bound.BoundType == execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING ||
+			bound.BoundType == execinfrapb.WindowerSpec_Frame_OFFSET_FOLLOWING
+	}
+	return !isOffset(frame.Bounds.Start) &&
+		(frame.Bounds.End == nil || !isOffset(*frame.Bounds.End))
+}
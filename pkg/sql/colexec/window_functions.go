@@ -0,0 +1,810 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/execerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/typeconv"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// isStreamingWindowFn returns whether the window function can be computed by
+// a streaming operator (i.e. without buffering up the whole partition).
+func isStreamingWindowFn(wf *execinfrapb.WindowerSpec_WindowFn) bool {
+	if wf.Func.WindowFunc == nil {
+		return false
+	}
+	switch *wf.Func.WindowFunc {
+	case execinfrapb.WindowerSpec_ROW_NUMBER,
+		execinfrapb.WindowerSpec_RANK,
+		execinfrapb.WindowerSpec_DENSE_RANK:
+		return true
+	}
+	return false
+}
+
+// groupWindowFnsByOrdering groups the window functions that have the same
+// ORDER BY clause (and, thus, can be computed on the same sorted input)
+// together. It returns the indices of the window functions in every group,
+// and the groups are in the order of their first appearance.
+func groupWindowFnsByOrdering(windowFns []execinfrapb.WindowerSpec_WindowFn) [][]int {
+	var groups [][]int
+	for i := range windowFns {
+		found := false
+		for j := range groups {
+			if windowFns[groups[j][0]].Ordering.Equal(windowFns[i].Ordering) {
+				groups[j] = append(groups[j], i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			groups = append(groups, []int{i})
+		}
+	}
+	return groups
+}
+
+// checkWindowFnSupported returns an error if the window function cannot be
+// executed by the vectorized engine on the input with the given types.
+func checkWindowFnSupported(wf *execinfrapb.WindowerSpec_WindowFn, inputTypes []types.T) error {
+	if !isWindowFrameSupported(wf.Frame) {
+		return errors.Newf("window frames with offsets in RANGE mode or with exclusion are not supported")
+	}
+	argTypes := make([]coltypes.T, len(wf.ArgsIdxs))
+	for i, idx := range wf.ArgsIdxs {
+		if int(idx) >= len(inputTypes) {
+			return errors.Newf("window function argument index %d is out of bounds", idx)
+		}
+		argTypes[i] = typeconv.FromColumnType(&inputTypes[idx])
+		if argTypes[i] == coltypes.Unhandled {
+			return errors.Newf("window function argument of type %s is not supported", inputTypes[idx].String())
+		}
+	}
+	if wf.Func.AggregateFunc != nil {
+		if !isWindowAggregateSupported(*wf.Func.AggregateFunc, argTypes) {
+			return errors.Newf("aggregate function %s is not supported as a window function", wf.Func.AggregateFunc.String())
+		}
+		if wf.FilterColIdx >= 0 &&
+			(int(wf.FilterColIdx) >= len(inputTypes) || inputTypes[wf.FilterColIdx].Family() != types.BoolFamily) {
+			return errors.Newf("window aggregate functions with non-boolean filter are not supported")
+		}
+		return nil
+	}
+	if wf.Func.WindowFunc == nil {
+		return errors.Newf("function is neither an aggregate nor a window function")
+	}
+	supported := false
+	switch *wf.Func.WindowFunc {
+	case execinfrapb.WindowerSpec_ROW_NUMBER,
+		execinfrapb.WindowerSpec_RANK,
+		execinfrapb.WindowerSpec_DENSE_RANK,
+		execinfrapb.WindowerSpec_PERCENT_RANK,
+		execinfrapb.WindowerSpec_CUME_DIST:
+		supported = len(argTypes) == 0
+	case execinfrapb.WindowerSpec_NTILE:
+		supported = len(argTypes) == 1 && argTypes[0] == coltypes.Int64
+	case execinfrapb.WindowerSpec_LAG, execinfrapb.WindowerSpec_LEAD:
+		supported = len(argTypes) >= 1 && len(argTypes) <= 3 &&
+			(len(argTypes) < 2 || argTypes[1] == coltypes.Int64) &&
+			(len(argTypes) < 3 || argTypes[2] == argTypes[0])
+	case execinfrapb.WindowerSpec_FIRST_VALUE, execinfrapb.WindowerSpec_LAST_VALUE:
+		supported = len(argTypes) == 1
+	case execinfrapb.WindowerSpec_NTH_VALUE:
+		supported = len(argTypes) == 2 && argTypes[1] == coltypes.Int64
+	}
+	if !supported {
+		return errors.Newf("window function %s is not supported", wf.String())
+	}
+	return nil
+}
+
+// GetWindowFnOutputType returns the type of the column that the window
+// function with the given argument types outputs.
+func GetWindowFnOutputType(fn execinfrapb.WindowerSpec_Func, argTypes []types.T) (*types.T, error) {
+	if fn.AggregateFunc != nil {
+		_, outputType, err := execinfrapb.GetAggregateInfo(*fn.AggregateFunc, argTypes...)
+		return outputType, err
+	}
+	if fn.WindowFunc == nil {
+		return nil, errors.Errorf("function is neither an aggregate nor a window function")
+	}
+	switch *fn.WindowFunc {
+	case execinfrapb.WindowerSpec_ROW_NUMBER,
+		execinfrapb.WindowerSpec_RANK,
+		execinfrapb.WindowerSpec_DENSE_RANK,
+		execinfrapb.WindowerSpec_NTILE:
+		return types.Int, nil
+	case execinfrapb.WindowerSpec_PERCENT_RANK,
+		execinfrapb.WindowerSpec_CUME_DIST:
+		return types.Float, nil
+	case execinfrapb.WindowerSpec_LAG,
+		execinfrapb.WindowerSpec_LEAD,
+		execinfrapb.WindowerSpec_FIRST_VALUE,
+		execinfrapb.WindowerSpec_LAST_VALUE,
+		execinfrapb.WindowerSpec_NTH_VALUE:
+		if len(argTypes) == 0 {
+			return nil, errors.Errorf("window function %s requires an argument", fn.WindowFunc.String())
+		}
+		return &argTypes[0], nil
+	}
+	return nil, errors.Errorf("unknown window function %s", fn.WindowFunc.String())
+}
+
+// windowFunction computes the results of a single window function over a
+// fully buffered partition.
+type windowFunction interface {
+	// startPartition prepares the window function for the computation over a
+	// new partition.
+	startPartition(partition *windowPartition)
+	// compute computes the results for the tuples of the partition in range
+	// [startIdx, endIdx) and writes them into output starting at position 0.
+	// The consecutive calls to compute must cover the whole partition in
+	// order.
+	compute(output coldata.Vec, startIdx, endIdx uint64)
+}
+
+// bufferedWindowState represents the state of the buffered window operator.
+type bufferedWindowState int
+
+const (
+	// windowBuffering is the state in which the operator is reading the tuples
+	// of the current partition from the input.
+	windowBuffering bufferedWindowState = iota
+	// windowEmitting is the state in which the operator has buffered the whole
+	// partition and is emitting it together with the results of the window
+	// functions.
+	windowEmitting
+	// windowFinished is the state in which the operator has emitted all of the
+	// tuples.
+	windowFinished
+)
+
+// bufferedWindowOp is an operator that computes window functions that need
+// to see the whole partition (for example, lead and lag, or aggregates over
+// window frames) before they can output any results. It buffers up all the
+// tuples of a single partition, computes all of the window functions over
+// it, and then emits the partition with the output columns of the window
+// functions appended. The input to the operator must be ordered by the
+// partitioning columns followed by the ordering columns of the window
+// functions (all window functions must have the same ordering).
+type bufferedWindowOp struct {
+	OneInputNode
+
+	allocator *Allocator
+	// inputTypes are the types of all of the columns from the input. The
+	// output of the operator has the same columns followed by a column for
+	// every window function.
+	inputTypes    []coltypes.T
+	fnOutputTypes []coltypes.T
+	windowFns     []windowFunction
+	// partitionColIdx is the index of a boolean column that contains true for
+	// every tuple that is the first in its partition. It is -1 when the whole
+	// input is a single partition.
+	partitionColIdx int
+	// peersCol is the output column of the chain of ordered distinct
+	// operators on the ordering columns in which true indicates that a new peer
+	// group begins with the corresponding tuple. It is nil when the window
+	// functions do not have an ordering.
+	peersCol []bool
+
+	state bufferedWindowState
+	// buffered contains all of the tuples of the current partition. It has an
+	// extra boolean column at the end that indicates the start of every peer
+	// group within the partition.
+	buffered  *bufferedBatch
+	partition windowPartition
+	peersVec  coldata.Vec
+	// batch is the most recently read batch from the input, and batchIdx is
+	// the position in it (in terms of the selection vector, if present) of the
+	// first tuple that hasn't been buffered yet.
+	batch     coldata.Batch
+	batchIdx  uint16
+	inputDone bool
+	// emitted is the number of the tuples of the current partition that have
+	// already been emitted.
+	emitted uint64
+	output  coldata.Batch
+
+	exported    uint64
+	exportBatch coldata.Batch
+}
+
+var _ bufferingInMemoryOperator = &bufferedWindowOp{}
+
+// NewBufferedWindowOperator creates a new Operator that computes the window
+// functions windowFns (which must share the same ordering given by
+// orderingCols). outputTypes are the types of the output columns of the
+// window functions that are appended to the input columns. partitionColIdx
+// is the index of the boolean column that indicates the start of every
+// partition (or -1 if there is no PARTITION BY clause).
+func NewBufferedWindowOperator(
+	allocator *Allocator,
+	input Operator,
+	inputTypes []coltypes.T,
+	outputTypes []coltypes.T,
+	windowFns []execinfrapb.WindowerSpec_WindowFn,
+	orderingCols []uint32,
+	partitionColIdx int,
+) (Operator, error) {
+	fns := make([]windowFunction, len(windowFns))
+	for i := range windowFns {
+		fn, err := newWindowFunction(&windowFns[i], inputTypes, outputTypes[i])
+		if err != nil {
+			return nil, err
+		}
+		fns[i] = fn
+	}
+	var peersCol []bool
+	if len(orderingCols) > 0 {
+		var err error
+		input, peersCol, err = OrderedDistinctColsToOperators(input, orderingCols, inputTypes)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &bufferedWindowOp{
+		OneInputNode:    NewOneInputNode(input),
+		allocator:       allocator,
+		inputTypes:      inputTypes,
+		fnOutputTypes:   outputTypes,
+		windowFns:       fns,
+		partitionColIdx: partitionColIdx,
+		peersCol:        peersCol,
+	}, nil
+}
+
+func newWindowFunction(
+	wf *execinfrapb.WindowerSpec_WindowFn, inputTypes []coltypes.T, outputType coltypes.T,
+) (windowFunction, error) {
+	argIdxs := make([]int, len(wf.ArgsIdxs))
+	argTypes := make([]coltypes.T, len(wf.ArgsIdxs))
+	for i, idx := range wf.ArgsIdxs {
+		argIdxs[i] = int(idx)
+		argTypes[i] = inputTypes[idx]
+	}
+	if wf.Func.AggregateFunc != nil {
+		argIdx := -1
+		if len(argIdxs) > 0 {
+			argIdx = argIdxs[0]
+		}
+		agg, err := newWindowAggregate(*wf.Func.AggregateFunc, argTypes, argIdx)
+		if err != nil {
+			return nil, err
+		}
+		return &windowAggregateFunc{
+			windowFramer: windowFramer{frame: wf.Frame},
+			agg:          agg,
+			filterColIdx: int(wf.FilterColIdx),
+		}, nil
+	}
+	switch fn := *wf.Func.WindowFunc; fn {
+	case execinfrapb.WindowerSpec_ROW_NUMBER,
+		execinfrapb.WindowerSpec_RANK,
+		execinfrapb.WindowerSpec_DENSE_RANK,
+		execinfrapb.WindowerSpec_PERCENT_RANK,
+		execinfrapb.WindowerSpec_CUME_DIST:
+		return &rankWindowFunc{fn: fn}, nil
+	case execinfrapb.WindowerSpec_NTILE:
+		return &ntileWindowFunc{argIdx: argIdxs[0]}, nil
+	case execinfrapb.WindowerSpec_LAG, execinfrapb.WindowerSpec_LEAD:
+		f := &leadLagWindowFunc{
+			typ:        outputType,
+			valueIdx:   argIdxs[0],
+			offsetIdx:  -1,
+			defaultIdx: -1,
+			lag:        fn == execinfrapb.WindowerSpec_LAG,
+		}
+		if len(argIdxs) > 1 {
+			f.offsetIdx = argIdxs[1]
+		}
+		if len(argIdxs) > 2 {
+			f.defaultIdx = argIdxs[2]
+		}
+		return f, nil
+	case execinfrapb.WindowerSpec_FIRST_VALUE,
+		execinfrapb.WindowerSpec_LAST_VALUE,
+		execinfrapb.WindowerSpec_NTH_VALUE:
+		f := &valueWindowFunc{
+			windowFramer: windowFramer{frame: wf.Frame},
+			fn:           fn,
+			typ:          outputType,
+			valueIdx:     argIdxs[0],
+			nthIdx:       -1,
+		}
+		if fn == execinfrapb.WindowerSpec_NTH_VALUE {
+			f.nthIdx = argIdxs[1]
+		}
+		return f, nil
+	default:
+		return nil, errors.Errorf("unsupported window function %s", fn.String())
+	}
+}
+
+func (w *bufferedWindowOp) Init() {
+	w.input.Init()
+	w.buffered = newBufferedBatch(
+		w.allocator, append(w.inputTypes[:len(w.inputTypes):len(w.inputTypes)], coltypes.Bool),
+		0, /* initialSize */
+	)
+	w.peersVec = w.allocator.NewMemColumn(coltypes.Bool, int(coldata.BatchSize()))
+	w.output = w.allocator.NewMemBatchWithSize(w.inputTypes, 0 /* size */)
+	for _, t := range w.fnOutputTypes {
+		w.output.AppendCol(w.allocator.NewMemColumn(t, int(coldata.BatchSize())))
+	}
+	w.exportBatch = w.allocator.NewMemBatchWithSize(w.inputTypes, 0 /* size */)
+}
+
+func (w *bufferedWindowOp) Next(ctx context.Context) coldata.Batch {
+	for {
+		switch w.state {
+		case windowBuffering:
+			if w.batch == nil || w.batchIdx == w.batch.Length() {
+				if !w.inputDone {
+					w.batch, w.batchIdx = w.input.Next(ctx), 0
+					w.inputDone = w.batch.Length() == 0
+				}
+				if w.inputDone {
+					if w.buffered.length == 0 {
+						w.state = windowFinished
+					} else {
+						w.startEmitting()
+					}
+					continue
+				}
+			}
+			if partitionDone := w.bufferTuples(); partitionDone {
+				w.startEmitting()
+			}
+		case windowEmitting:
+			if w.emitted < w.buffered.length {
+				return w.emitBatch()
+			}
+			w.buffered.reset()
+			if w.inputDone {
+				w.state = windowFinished
+			} else {
+				w.state = windowBuffering
+			}
+		case windowFinished:
+			return coldata.ZeroBatch
+		default:
+			execerror.VectorizedInternalPanic("unexpected bufferedWindowState")
+		}
+	}
+}
+
+// bufferTuples appends the tuples of the current batch that belong to the
+// current partition to the buffer. It returns true if the end of the current
+// partition has been reached.
+func (w *bufferedWindowOp) bufferTuples() bool {
+	n := w.batch.Length()
+	sel := w.batch.Selection()
+	start, end := w.batchIdx, w.batchIdx
+	firstInPartition := w.buffered.length == 0
+	if firstInPartition {
+		// The first tuple always belongs to the current partition.
+		end++
+	}
+	if w.partitionColIdx >= 0 {
+		partitionCol := w.batch.ColVec(w.partitionColIdx).Bool()
+		for ; end < n; end++ {
+			rowIdx := end
+			if sel != nil {
+				rowIdx = sel[end]
+			}
+			if partitionCol[rowIdx] {
+				break
+			}
+		}
+	} else {
+		end = n
+	}
+
+	peers := w.peersVec.Bool()
+	for i := start; i < end; i++ {
+		rowIdx := i
+		if sel != nil {
+			rowIdx = sel[i]
+		}
+		peers[i-start] = (firstInPartition && i == start) ||
+			(w.peersCol != nil && w.peersCol[rowIdx])
+	}
+	w.allocator.PerformOperation(w.buffered.colVecs, func() {
+		for colIdx, t := range w.inputTypes {
+			w.buffered.colVecs[colIdx].Append(
+				coldata.SliceArgs{
+					ColType:     t,
+					Src:         w.batch.ColVec(colIdx),
+					Sel:         sel,
+					DestIdx:     w.buffered.length,
+					SrcStartIdx: uint64(start),
+					SrcEndIdx:   uint64(end),
+				},
+			)
+		}
+		w.buffered.colVecs[len(w.inputTypes)].Append(
+			coldata.SliceArgs{
+				ColType:   coltypes.Bool,
+				Src:       w.peersVec,
+				DestIdx:   w.buffered.length,
+				SrcEndIdx: uint64(end - start),
+			},
+		)
+		// We update the state right away so that ExportBuffered doesn't export
+		// the same tuples twice in case accounting for the memory fails.
+		w.buffered.length += uint64(end - start)
+		w.batchIdx = end
+	})
+	return end < n
+}
+
+func (w *bufferedWindowOp) startEmitting() {
+	w.partition = windowPartition{
+		vecs:  w.buffered.colVecs[:len(w.inputTypes)],
+		peers: w.buffered.colVecs[len(w.inputTypes)].Bool(),
+		n:     w.buffered.length,
+	}
+	for _, fn := range w.windowFns {
+		fn.startPartition(&w.partition)
+	}
+	w.emitted = 0
+	w.state = windowEmitting
+}
+
+func (w *bufferedWindowOp) emitBatch() coldata.Batch {
+	start := w.emitted
+	end := start + uint64(coldata.BatchSize())
+	if end > w.buffered.length {
+		end = w.buffered.length
+	}
+	w.output.SetSelection(false)
+	for i, t := range w.inputTypes {
+		w.output.ReplaceCol(w.buffered.colVecs[i].Window(t, start, end), i)
+	}
+	for i, fn := range w.windowFns {
+		vec := w.output.ColVec(len(w.inputTypes) + i)
+		vec.Nulls().UnsetNulls()
+		if vec.Type() == coltypes.Bytes {
+			vec.Bytes().Reset()
+		}
+		fn.compute(vec, start, end)
+	}
+	w.output.SetLength(uint16(end - start))
+	w.emitted = end
+	return w.output
+}
+
+// ExportBuffered implements the bufferingInMemoryOperator interface. It
+// returns all of the tuples of the current partition followed by the tuples
+// of the last read batch that haven't been buffered yet. Note that the
+// partitions that have been fully emitted are not affected.
+func (w *bufferedWindowOp) ExportBuffered(Operator) coldata.Batch {
+	if w.exported < w.buffered.length {
+		newExported := w.exported + uint64(coldata.BatchSize())
+		if newExported > w.buffered.length {
+			newExported = w.buffered.length
+		}
+		for i, t := range w.inputTypes {
+			window := w.buffered.colVecs[i].Window(t, w.exported, newExported)
+			w.exportBatch.ReplaceCol(window, i)
+		}
+		w.exportBatch.SetLength(uint16(newExported - w.exported))
+		w.exported = newExported
+		return w.exportBatch
+	}
+	if w.batch != nil && w.batchIdx < w.batch.Length() {
+		// Export the remaining tuples of the last batch by updating its
+		// selection vector.
+		b, start, n := w.batch, w.batchIdx, w.batch.Length()
+		w.batchIdx = n
+		if sel := b.Selection(); sel != nil {
+			copy(sel, sel[start:n])
+		} else {
+			b.SetSelection(true)
+			sel = b.Selection()
+			for i := start; i < n; i++ {
+				sel[i-start] = i
+			}
+		}
+		b.SetLength(n - start)
+		return b
+	}
+	return coldata.ZeroBatch
+}
+
+// copyWindowValue copies the value (or NULL) at position srcIdx of src into
+// the output vector at position outputIdx.
+func copyWindowValue(
+	output coldata.Vec, typ coltypes.T, src coldata.Vec, srcIdx uint64, outputIdx uint16,
+) {
+	output.Copy(coldata.CopySliceArgs{
+		SliceArgs: coldata.SliceArgs{
+			ColType:     typ,
+			Src:         src,
+			DestIdx:     uint64(outputIdx),
+			SrcStartIdx: srcIdx,
+			SrcEndIdx:   srcIdx + 1,
+		},
+	})
+}
+
+// rankWindowFunc computes ROW_NUMBER, RANK, DENSE_RANK, PERCENT_RANK and
+// CUME_DIST window functions.
+type rankWindowFunc struct {
+	peerGroupTracker
+
+	fn execinfrapb.WindowerSpec_WindowFunc
+}
+
+var _ windowFunction = &rankWindowFunc{}
+
+func (w *rankWindowFunc) startPartition(partition *windowPartition) {
+	w.reset(partition)
+}
+
+func (w *rankWindowFunc) compute(output coldata.Vec, startIdx, endIdx uint64) {
+	n := w.partition.n
+	for i := startIdx; i < endIdx; i++ {
+		w.next()
+		outputIdx := i - startIdx
+		switch w.fn {
+		case execinfrapb.WindowerSpec_ROW_NUMBER:
+			output.Int64()[outputIdx] = int64(w.rowIdx + 1)
+		case execinfrapb.WindowerSpec_RANK:
+			output.Int64()[outputIdx] = int64(w.groupStart + 1)
+		case execinfrapb.WindowerSpec_DENSE_RANK:
+			output.Int64()[outputIdx] = int64(w.groupIdx + 1)
+		case execinfrapb.WindowerSpec_PERCENT_RANK:
+			// The relative rank is (rank - 1) / (number of tuples - 1), and it is
+			// zero for a partition with a single tuple.
+			if n <= 1 {
+				output.Float64()[outputIdx] = 0
+			} else {
+				output.Float64()[outputIdx] = float64(w.groupStart) / float64(n-1)
+			}
+		case execinfrapb.WindowerSpec_CUME_DIST:
+			// The cumulative distribution is (number of tuples preceding or peer
+			// with the current one) / (number of tuples).
+			output.Float64()[outputIdx] = float64(w.groupEnd) / float64(n)
+		}
+	}
+}
+
+var errInvalidArgumentForNtile = pgerror.Newf(
+	pgcode.InvalidParameterValue, "argument of ntile() must be greater than zero")
+
+// ntileWindowFunc computes an integer ranging from 1 to the argument value,
+// dividing the partition as equally as possible. The argument of the first
+// tuple of the partition that has a non-NULL argument determines the number
+// of buckets.
+type ntileWindowFunc struct {
+	argIdx int
+
+	partition   *windowPartition
+	initialized bool
+	// ntile is the current result, curBucketCount is the number of tuples in
+	// the current bucket so far, boundary is the number of tuples that should
+	// be in the current bucket, and remainder is the number of leading buckets
+	// that should get an extra tuple.
+	ntile, curBucketCount, boundary, remainder int64
+}
+
+var _ windowFunction = &ntileWindowFunc{}
+
+func (w *ntileWindowFunc) startPartition(partition *windowPartition) {
+	*w = ntileWindowFunc{argIdx: w.argIdx, partition: partition}
+}
+
+func (w *ntileWindowFunc) compute(output coldata.Vec, startIdx, endIdx uint64) {
+	argVec := w.partition.vecs[w.argIdx]
+	outputCol := output.Int64()
+	for i := startIdx; i < endIdx; i++ {
+		outputIdx := uint16(i - startIdx)
+		if !w.initialized {
+			if argVec.Nulls().NullAt64(i) {
+				// If the argument is NULL, then the result is NULL.
+				output.Nulls().SetNull(outputIdx)
+				continue
+			}
+			nbuckets := argVec.Int64()[i]
+			if nbuckets <= 0 {
+				execerror.NonVectorizedPanic(errInvalidArgumentForNtile)
+			}
+			total := int64(w.partition.n)
+			w.initialized = true
+			w.ntile = 1
+			w.curBucketCount = 0
+			w.boundary = total / nbuckets
+			if w.boundary <= 0 {
+				w.boundary = 1
+			} else {
+				// If the total number is not divisible, add 1 tuple to the leading
+				// buckets.
+				w.remainder = total % nbuckets
+				if w.remainder != 0 {
+					w.boundary++
+				}
+			}
+		}
+		w.curBucketCount++
+		if w.boundary < w.curBucketCount {
+			// Move to the next bucket.
+			if w.remainder != 0 && w.ntile == w.remainder {
+				w.remainder = 0
+				w.boundary--
+			}
+			w.ntile++
+			w.curBucketCount = 1
+		}
+		outputCol[outputIdx] = w.ntile
+	}
+}
+
+// leadLagWindowFunc computes LEAD and LAG window functions that return the
+// value of the tuple that is at the given offset after (or before) the
+// current one within the partition.
+type leadLagWindowFunc struct {
+	typ coltypes.T
+	// valueIdx is the index of the column with the values. offsetIdx and
+	// defaultIdx are the indices of the columns with the offsets and the
+	// default values, or -1 if they are omitted.
+	valueIdx, offsetIdx, defaultIdx int
+	lag                             bool
+
+	partition *windowPartition
+}
+
+var _ windowFunction = &leadLagWindowFunc{}
+
+func (w *leadLagWindowFunc) startPartition(partition *windowPartition) {
+	w.partition = partition
+}
+
+func (w *leadLagWindowFunc) compute(output coldata.Vec, startIdx, endIdx uint64) {
+	n := w.partition.n
+	for i := startIdx; i < endIdx; i++ {
+		outputIdx := uint16(i - startIdx)
+		offset := int64(1)
+		if w.offsetIdx >= 0 {
+			offsetVec := w.partition.vecs[w.offsetIdx]
+			if offsetVec.Nulls().NullAt64(i) {
+				output.Nulls().SetNull(outputIdx)
+				continue
+			}
+			offset = offsetVec.Int64()[i]
+		}
+		targetIdx, ok := uint64(0), false
+		if w.lag {
+			if offset != math.MinInt64 {
+				targetIdx, ok = windowOffsetIdx(i, n, -offset)
+			}
+		} else {
+			targetIdx, ok = windowOffsetIdx(i, n, offset)
+		}
+		if ok {
+			copyWindowValue(output, w.typ, w.partition.vecs[w.valueIdx], targetIdx, outputIdx)
+		} else if w.defaultIdx >= 0 {
+			// The target tuple is outside of the partition, so we supply the
+			// default value.
+			copyWindowValue(output, w.typ, w.partition.vecs[w.defaultIdx], i, outputIdx)
+		} else {
+			output.Nulls().SetNull(outputIdx)
+		}
+	}
+}
+
+// windowOffsetIdx returns idx+offset and whether it is within [0, n).
+func windowOffsetIdx(idx, n uint64, offset int64) (uint64, bool) {
+	if offset >= 0 {
+		return idx + uint64(offset), uint64(offset) < n-idx
+	}
+	if offset == math.MinInt64 {
+		return 0, false
+	}
+	return idx - uint64(-offset), uint64(-offset) <= idx
+}
+
+var errInvalidArgumentForNthValue = pgerror.Newf(
+	pgcode.InvalidParameterValue, "argument of nth_value() must be greater than zero")
+
+// valueWindowFunc computes FIRST_VALUE, LAST_VALUE and NTH_VALUE window
+// functions that return the value of the tuple at some position within the
+// window frame.
+type valueWindowFunc struct {
+	windowFramer
+
+	fn  execinfrapb.WindowerSpec_WindowFunc
+	typ coltypes.T
+	// valueIdx is the index of the column with the values, and nthIdx is the
+	// index of the column with the positions for NTH_VALUE (-1 otherwise).
+	valueIdx, nthIdx int
+}
+
+var _ windowFunction = &valueWindowFunc{}
+
+func (w *valueWindowFunc) startPartition(partition *windowPartition) {
+	w.windowFramer.reset(partition)
+}
+
+func (w *valueWindowFunc) compute(output coldata.Vec, startIdx, endIdx uint64) {
+	valueVec := w.partition.vecs[w.valueIdx]
+	for i := startIdx; i < endIdx; i++ {
+		w.next()
+		outputIdx := uint16(i - startIdx)
+		var nth uint64
+		if w.nthIdx >= 0 {
+			nthVec := w.partition.vecs[w.nthIdx]
+			if nthVec.Nulls().NullAt64(i) {
+				output.Nulls().SetNull(outputIdx)
+				continue
+			}
+			if v := nthVec.Int64()[i]; v <= 0 {
+				execerror.NonVectorizedPanic(errInvalidArgumentForNthValue)
+			} else {
+				nth = uint64(v)
+			}
+		}
+		start, end := w.frameBounds()
+		if start == end {
+			// The window frame is empty.
+			output.Nulls().SetNull(outputIdx)
+			continue
+		}
+		switch w.fn {
+		case execinfrapb.WindowerSpec_FIRST_VALUE:
+			copyWindowValue(output, w.typ, valueVec, start, outputIdx)
+		case execinfrapb.WindowerSpec_LAST_VALUE:
+			copyWindowValue(output, w.typ, valueVec, end-1, outputIdx)
+		case execinfrapb.WindowerSpec_NTH_VALUE:
+			if nth > end-start {
+				// The requested tuple is outside of the window frame.
+				output.Nulls().SetNull(outputIdx)
+			} else {
+				copyWindowValue(output, w.typ, valueVec, start+nth-1, outputIdx)
+			}
+		}
+	}
+}
+
+// diskBackedWindower is a wrapper around the Columnarizer of the row-by-row
+// windower that is used as the disk-backed fallback of bufferedWindowOp. The
+// fallback is initialized only if the spilling to disk occurs, so the metadata
+// must not be drained from it otherwise.
+type diskBackedWindower struct {
+	*Columnarizer
+	initialized bool
+}
+
+var _ Operator = &diskBackedWindower{}
+var _ execinfrapb.MetadataSource = &diskBackedWindower{}
+
+func (w *diskBackedWindower) Init() {
+	w.initialized = true
+	w.Columnarizer.Init()
+}
+
+// DrainMeta is part of the MetadataSource interface.
+func (w *diskBackedWindower) DrainMeta(ctx context.Context) []execinfrapb.ProducerMetadata {
+	if !w.initialized {
+		return nil
+	}
+	return w.Columnarizer.DrainMeta(ctx)
+}
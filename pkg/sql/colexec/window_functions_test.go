@@ -227,3 +227,152 @@ func TestRowNumber(t *testing.T) {
 		})
 	}
 }
+
+func TestBufferedWindowFunctions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	defer evalCtx.Stop(ctx)
+	flowCtx := &execinfra.FlowCtx{
+		EvalCtx: &evalCtx,
+		Cfg: &execinfra.ServerConfig{
+			Settings: st,
+		},
+	}
+
+	countFn := execinfrapb.AggregatorSpec_COUNT
+	minFn := execinfrapb.AggregatorSpec_MIN
+	lagFn := execinfrapb.WindowerSpec_LAG
+	ntileFn := execinfrapb.WindowerSpec_NTILE
+	firstValueFn := execinfrapb.WindowerSpec_FIRST_VALUE
+	nthValueFn := execinfrapb.WindowerSpec_NTH_VALUE
+	ascOrdering := execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 0}}}
+	descOrdering := execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{
+		{ColIdx: 0, Direction: execinfrapb.Ordering_Column_DESC},
+	}}
+	for _, tc := range []windowFnTestCase{
+		// No PARTITION BY, with different ORDER BY clauses and window frames.
+		{
+			tuples:   tuples{{3}, {1}, {2}, {5}, {4}},
+			expected: tuples{{1, 2, nil, 1}, {2, 3, 1, 1}, {3, 3, 2, 2}, {4, 3, 3, 3}, {5, 2, 4, 4}},
+			windowerSpec: execinfrapb.WindowerSpec{
+				WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+					{
+						Func:     execinfrapb.WindowerSpec_Func{AggregateFunc: &countFn},
+						ArgsIdxs: []uint32{0},
+						Ordering: ascOrdering,
+						Frame: &execinfrapb.WindowerSpec_Frame{
+							Mode: execinfrapb.WindowerSpec_Frame_ROWS,
+							Bounds: execinfrapb.WindowerSpec_Frame_Bounds{
+								Start: execinfrapb.WindowerSpec_Frame_Bound{
+									BoundType: execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING,
+									IntOffset: 1,
+								},
+								End: &execinfrapb.WindowerSpec_Frame_Bound{
+									BoundType: execinfrapb.WindowerSpec_Frame_OFFSET_FOLLOWING,
+									IntOffset: 1,
+								},
+							},
+						},
+						FilterColIdx: -1,
+						OutputColIdx: 1,
+					},
+					{
+						Func:         execinfrapb.WindowerSpec_Func{WindowFunc: &lagFn},
+						ArgsIdxs:     []uint32{0},
+						Ordering:     ascOrdering,
+						OutputColIdx: 2,
+					},
+					{
+						Func:     execinfrapb.WindowerSpec_Func{AggregateFunc: &minFn},
+						ArgsIdxs: []uint32{0},
+						Ordering: descOrdering,
+						Frame: &execinfrapb.WindowerSpec_Frame{
+							Mode: execinfrapb.WindowerSpec_Frame_ROWS,
+							Bounds: execinfrapb.WindowerSpec_Frame_Bounds{
+								Start: execinfrapb.WindowerSpec_Frame_Bound{
+									BoundType: execinfrapb.WindowerSpec_Frame_CURRENT_ROW,
+								},
+								End: &execinfrapb.WindowerSpec_Frame_Bound{
+									BoundType: execinfrapb.WindowerSpec_Frame_OFFSET_FOLLOWING,
+									IntOffset: 1,
+								},
+							},
+						},
+						FilterColIdx: -1,
+						OutputColIdx: 3,
+					},
+				},
+			},
+		},
+		// With both PARTITION BY and ORDER BY.
+		{
+			tuples: tuples{{1, 1, 2}, {2, 5, 2}, {1, 3, 2}, {nil, 6, 2}, {2, 4, 2}, {1, 2, 2}},
+			expected: tuples{
+				{1, 1, 2, 1, 3, 2}, {1, 2, 2, 1, 3, 2}, {1, 3, 2, 2, 3, 2},
+				{2, 4, 2, 1, 5, 5}, {2, 5, 2, 2, 5, 5}, {nil, 6, 2, 1, 6, nil},
+			},
+			windowerSpec: execinfrapb.WindowerSpec{
+				PartitionBy: []uint32{0},
+				WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+					{
+						Func:         execinfrapb.WindowerSpec_Func{WindowFunc: &ntileFn},
+						ArgsIdxs:     []uint32{2},
+						Ordering:     execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 1}}},
+						OutputColIdx: 3,
+					},
+					{
+						Func:     execinfrapb.WindowerSpec_Func{WindowFunc: &firstValueFn},
+						ArgsIdxs: []uint32{1},
+						Ordering: execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{
+							{ColIdx: 1, Direction: execinfrapb.Ordering_Column_DESC},
+						}},
+						OutputColIdx: 4,
+					},
+					{
+						Func:     execinfrapb.WindowerSpec_Func{WindowFunc: &nthValueFn},
+						ArgsIdxs: []uint32{1, 2},
+						Ordering: execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 1}}},
+						Frame: &execinfrapb.WindowerSpec_Frame{
+							Mode: execinfrapb.WindowerSpec_Frame_ROWS,
+							Bounds: execinfrapb.WindowerSpec_Frame_Bounds{
+								Start: execinfrapb.WindowerSpec_Frame_Bound{
+									BoundType: execinfrapb.WindowerSpec_Frame_UNBOUNDED_PRECEDING,
+								},
+								End: &execinfrapb.WindowerSpec_Frame_Bound{
+									BoundType: execinfrapb.WindowerSpec_Frame_UNBOUNDED_FOLLOWING,
+								},
+							},
+						},
+						OutputColIdx: 5,
+					},
+				},
+			},
+		},
+	} {
+		runTests(t, []tuples{tc.tuples}, tc.expected, unorderedVerifier, func(inputs []Operator) (Operator, error) {
+			ct := make([]types.T, len(tc.tuples[0]))
+			for i := range ct {
+				ct[i] = *types.Int
+			}
+			spec := &execinfrapb.ProcessorSpec{
+				Input: []execinfrapb.InputSyncSpec{{ColumnTypes: ct}},
+				Core: execinfrapb.ProcessorCoreUnion{
+					Windower: &tc.windowerSpec,
+				},
+			}
+			args := NewColOperatorArgs{
+				Spec:                spec,
+				Inputs:              inputs,
+				StreamingMemAccount: testMemAcc,
+			}
+			args.TestingKnobs.UseStreamingMemAccountForBuffering = true
+			result, err := NewColOperator(ctx, flowCtx, args)
+			if err != nil {
+				return nil, err
+			}
+			return result.Op, nil
+		})
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Note that this file is not in pkg/sql/colexec because it instantiates a
+// server, and if it were moved into sql/colexec, that would create a cycle
+// with pkg/server.
+
+package colflow_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// runColJoinReader runs the columnar operator planned for the JoinReader spec
+// on the input and returns the output rows in the order in which they were
+// emitted.
+func runColJoinReader(
+	t *testing.T,
+	flowCtx *execinfra.FlowCtx,
+	spec *execinfrapb.JoinReaderSpec,
+	inputTypes []types.T,
+	input sqlbase.EncDatumRows,
+	lookupJoinMemoryLimit int64,
+) []string {
+	ctx := context.Background()
+	columnarizer, err := colexec.NewColumnarizer(
+		ctx, testAllocator, flowCtx, 0 /* processorID */, execinfra.NewRepeatableRowSource(inputTypes, input),
+	)
+	require.NoError(t, err)
+	args := colexec.NewColOperatorArgs{
+		Spec: &execinfrapb.ProcessorSpec{
+			Input: []execinfrapb.InputSyncSpec{{ColumnTypes: inputTypes}},
+			Core:  execinfrapb.ProcessorCoreUnion{JoinReader: spec},
+		},
+		Inputs:              []colexec.Operator{columnarizer},
+		StreamingMemAccount: testMemAcc,
+	}
+	args.TestingKnobs.UseStreamingMemAccountForBuffering = true
+	args.TestingKnobs.LookupJoinMemoryLimit = lookupJoinMemoryLimit
+	res, err := colexec.NewColOperator(ctx, flowCtx, args)
+	require.NoError(t, err)
+	m, err := colexec.NewMaterializer(
+		flowCtx, 1 /* processorID */, res.Op, res.ColumnTypes, &execinfrapb.PostProcessSpec{},
+		nil /* output */, res.MetadataSources, nil /* outputStatsToTrace */, nil, /* cancelFlow */
+	)
+	require.NoError(t, err)
+	m.Start(ctx)
+	defer m.ConsumerClosed()
+	var rows []string
+	for {
+		row, meta := m.Next()
+		if meta != nil {
+			t.Fatalf("unexpected metadata %+v", meta)
+		}
+		if row == nil {
+			return rows
+		}
+		rows = append(rows, row.String(res.ColumnTypes))
+	}
+}
+
+// makeIntRows returns the rows with the given INT values, where nil stands for
+// NULL.
+func makeIntRows(values [][]interface{}) sqlbase.EncDatumRows {
+	rows := make(sqlbase.EncDatumRows, len(values))
+	for i, rowValues := range values {
+		rows[i] = make(sqlbase.EncDatumRow, len(rowValues))
+		for j, v := range rowValues {
+			d := tree.DNull
+			if v != nil {
+				d = tree.NewDInt(tree.DInt(v.(int)))
+			}
+			rows[i][j] = sqlbase.DatumToEncDatum(types.Int, d)
+		}
+	}
+	return rows
+}
+
+func TestColIndexAndLookupJoin(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	logScope := log.Scope(t)
+	defer logScope.Close(t)
+	ctx := context.Background()
+
+	s, sqlDB, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	r := sqlutils.MakeSQLRunner(sqlDB)
+	r.Exec(t, "CREATE DATABASE test")
+	r.Exec(t, "CREATE TABLE test.t (a INT, b INT, c INT, PRIMARY KEY (a, b), INDEX c_idx (c))")
+	r.Exec(t, "INSERT INTO test.t VALUES (1, 1, 10), (1, 2, NULL), (2, 1, 20), (3, 1, 10)")
+	tableDesc := sqlbase.GetTableDescriptor(kvDB, "test", "t")
+
+	evalCtx := tree.MakeTestingEvalContext(s.ClusterSettings())
+	defer evalCtx.Stop(ctx)
+	flowCtx := &execinfra.FlowCtx{
+		EvalCtx: &evalCtx,
+		Cfg:     &execinfra.ServerConfig{Settings: s.ClusterSettings()},
+		Txn:     client.NewTxn(ctx, kvDB, s.NodeID()),
+		NodeID:  s.NodeID(),
+	}
+
+	t.Run("index join", func(t *testing.T) {
+		// The output follows the order of the input.
+		rows := runColJoinReader(
+			t, flowCtx, &execinfrapb.JoinReaderSpec{Table: *tableDesc},
+			[]types.T{*types.Int, *types.Int}, makeIntRows([][]interface{}{{3, 1}, {1, 1}, {1, 2}, {3, 1}}),
+			0, /* lookupJoinMemoryLimit */
+		)
+		require.Equal(t, []string{"[3 1 10]", "[1 1 10]", "[1 2 NULL]", "[3 1 10]"}, rows)
+	})
+
+	testCases := []struct {
+		name     string
+		indexIdx uint32
+		input    [][]interface{}
+		joinType sqlbase.JoinType
+		expected []string
+	}{
+		{
+			name:     "primary index prefix",
+			input:    [][]interface{}{{1}, {nil}, {4}, {1}, {3}},
+			joinType: sqlbase.JoinType_INNER,
+			expected: []string{
+				"[1 1 1 10]", "[1 1 2 NULL]", "[1 1 1 10]", "[1 1 2 NULL]", "[3 3 1 10]",
+			},
+		},
+		{
+			name:     "primary index prefix",
+			input:    [][]interface{}{{1}, {nil}, {4}, {1}, {3}},
+			joinType: sqlbase.JoinType_LEFT_OUTER,
+			expected: []string{
+				"[1 1 1 10]", "[1 1 2 NULL]", "[NULL NULL NULL NULL]", "[4 NULL NULL NULL]",
+				"[1 1 1 10]", "[1 1 2 NULL]", "[3 3 1 10]",
+			},
+		},
+		{
+			name:     "primary index prefix",
+			input:    [][]interface{}{{1}, {nil}, {4}, {1}, {3}},
+			joinType: sqlbase.JoinType_LEFT_SEMI,
+			expected: []string{"[1]", "[1]", "[3]"},
+		},
+		{
+			name:     "primary index prefix",
+			input:    [][]interface{}{{1}, {nil}, {4}, {1}, {3}},
+			joinType: sqlbase.JoinType_LEFT_ANTI,
+			expected: []string{"[NULL]", "[4]"},
+		},
+		{
+			// NULLs in the index never match NULLs in the input.
+			name:     "secondary index",
+			indexIdx: 1,
+			input:    [][]interface{}{{10}, {nil}, {30}, {20}},
+			joinType: sqlbase.JoinType_INNER,
+			expected: []string{"[10 1 1 10]", "[10 3 1 10]", "[20 2 1 20]"},
+		},
+		{
+			name:     "secondary index",
+			indexIdx: 1,
+			input:    [][]interface{}{{10}, {nil}, {30}, {20}},
+			joinType: sqlbase.JoinType_LEFT_OUTER,
+			expected: []string{
+				"[10 1 1 10]", "[10 3 1 10]", "[NULL NULL NULL NULL]", "[30 NULL NULL NULL]", "[20 2 1 20]",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		// A memory limit of 1 byte forces the lookup join to look up a single
+		// input tuple at a time.
+		for _, memoryLimit := range []int64{0, 1} {
+			t.Run(fmt.Sprintf("%s/%s/limit=%d", tc.name, tc.joinType, memoryLimit), func(t *testing.T) {
+				rows := runColJoinReader(
+					t, flowCtx, &execinfrapb.JoinReaderSpec{
+						Table:         *tableDesc,
+						IndexIdx:      tc.indexIdx,
+						LookupColumns: []uint32{0},
+						Type:          tc.joinType,
+					},
+					[]types.T{*types.Int}, makeIntRows(tc.input), memoryLimit,
+				)
+				require.Equal(t, tc.expected, rows)
+			})
+		}
+	}
+}
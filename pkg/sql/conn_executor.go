@@ -252,6 +252,10 @@ type Server struct {
 	// dbCache is a cache for database descriptors, maintained through Gossip
 	// updates.
 	dbCache *databaseCacheHolder
+
+	// notifications routes the notifications generated by NOTIFY to the
+	// sessions listening on them.
+	notifications *notificationRegistry
 }
 
 // Metrics collects timeseries data about SQL activity.
@@ -280,10 +284,11 @@ func NewServer(cfg *ExecutorConfig, pool *mon.BytesMonitor) *Server {
 		Metrics:         makeMetrics(false /*internal*/),
 		InternalMetrics: makeMetrics(true /*internal*/),
 		// dbCache will be updated on Start().
-		dbCache:  newDatabaseCacheHolder(newDatabaseCache(systemCfg)),
-		pool:     pool,
-		sqlStats: sqlStats{st: cfg.Settings, apps: make(map[string]*appStats)},
-		reCache:  tree.NewRegexpCache(512),
+		dbCache:       newDatabaseCacheHolder(newDatabaseCache(systemCfg)),
+		pool:          pool,
+		sqlStats:      sqlStats{st: cfg.Settings, apps: make(map[string]*appStats)},
+		reCache:       tree.NewRegexpCache(512),
+		notifications: newNotificationRegistry(cfg.Gossip, cfg.NodeID),
 	}
 }
 
@@ -436,6 +441,14 @@ func (h ConnectionHandler) RegisterOnSessionDataChange(key string, f func(val st
 	h.ex.dataMutator.RegisterOnSessionDataChange(key, f)
 }
 
+// RegisterOnNotification sets the function used to send the notifications
+// received by the session (see LISTEN) to the client. The function is called
+// on the session's goroutine, in between the execution of commands, when the
+// session is not inside a transaction.
+func (h ConnectionHandler) RegisterOnNotification(f func(Notification)) {
+	h.ex.notifications.onNotification = f
+}
+
 // ServeConn serves a client connection by reading commands from the stmtBuf
 // embedded in the ConnHandler.
 //
@@ -567,6 +580,7 @@ func (s *Server) newConnExecutor(
 		ctxHolder:                 ctxHolder{connCtx: ctx},
 		executorType:              executorTypeExec,
 		hasCreatedTemporarySchema: false,
+		notifications:             sessionNotifications{registry: s.notifications},
 	}
 
 	ex.state.txnAbortCount = ex.metrics.EngineMetrics.TxnAbortCount
@@ -627,7 +641,14 @@ func (s *Server) newConnExecutor(
 		dbCacheSubscriber: s.dbCache,
 		settings:          s.cfg.Settings,
 	}
+	ex.extraTxnState.sqlCursors.mon = ex.sessionMon
 	ex.extraTxnState.txnRewindPos = -1
+	ex.notifications.queue.wake = func() {
+		// Get an idle session to deliver the notification. The push fails only
+		// if the session is shutting down, in which case there is no one to
+		// deliver to anyway.
+		_ = stmtBuf.Push(ctx, Flush{})
+	}
 	ex.mu.ActiveQueries = make(map[ClusterWideID]*queryMeta)
 	ex.machine = fsm.MakeMachine(TxnStateTransitions, stateNoTxn{}, &ex.state)
 
@@ -816,6 +837,8 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 		log.Warningf(ctx, "error while cleaning up connExecutor: %s", err)
 	}
 
+	ex.notifications.close()
+
 	if closeType != panicClose {
 		// Close all statements and prepared portals.
 		ex.extraTxnState.prepStmtsNamespace.resetTo(ctx, prepStmtNamespace{})
@@ -936,6 +959,11 @@ type connExecutor struct {
 		// prepStmtsNamespaceAtTxnRewindPos).
 		prepStmtsNamespace prepStmtNamespace
 
+		// sqlCursors contains the cursors declared with DECLARE. Cursors are
+		// bound to a transaction and they're all closed once the transaction
+		// finishes.
+		sqlCursors sqlCursors
+
 		// prepStmtsNamespaceAtTxnRewindPos is a snapshot of the prep stmts/portals
 		// (ex.prepStmtsNamespace) before processing the command at position
 		// txnRewindPos.
@@ -1027,6 +1055,9 @@ type connExecutor struct {
 	// hasCreatedTemporarySchema is set if the executor has created a
 	// temporary schema, which requires special cleanup on close.
 	hasCreatedTemporarySchema bool
+
+	// notifications holds the session's LISTEN/NOTIFY state.
+	notifications sessionNotifications
 }
 
 // ctxHolder contains a connection's context and, while session tracing is
@@ -1117,6 +1148,25 @@ func (ex *connExecutor) resetExtraTxnState(
 
 	ex.extraTxnState.tables.databaseCache = dbCacheHolder.getDatabaseCache()
 
+	switch ev {
+	case txnCommit:
+		ex.notifications.commitTxn(ctx)
+	case txnRestart:
+		// The transaction is retried from its savepoint (or from its start, if
+		// it has none), so the statements executed since are discarded.
+		ex.notifications.rollbackToSavepoint()
+	default:
+		if _, ok := ex.machine.CurState().(stateAborted); ok {
+			// The transaction can still be resumed with ROLLBACK TO SAVEPOINT,
+			// which keeps the statements executed before the savepoint.
+			ex.notifications.rollbackToSavepoint()
+		} else {
+			ex.notifications.resetTxn()
+		}
+	}
+
+	ex.extraTxnState.sqlCursors.closeAll(ctx)
+
 	// Close all portals.
 	for name, p := range ex.extraTxnState.prepStmtsNamespace.portals {
 		p.decRef(ctx)
@@ -1367,14 +1417,30 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 			ex.implicitTxn(),
 		)
 		res = stmtRes
+		if _, isOpen := ex.machine.CurState().(stateOpen); isOpen && portal.spool != nil {
+			// The portal's statement already ran to completion while the portal
+			// was suspended. Its remaining rows are served from the spool.
+			if err := portal.spool.serve(ctx, stmtRes); err != nil {
+				ev = eventNonRetriableErr{IsCommit: fsm.False}
+				payload = eventNonRetriableErrPayload{err: err}
+			}
+			break
+		}
 		curStmt := Statement{
 			Statement:     portal.Stmt.Statement,
 			Prepared:      portal.Stmt,
 			ExpectedTypes: portal.Stmt.Columns,
 			AnonymizedStr: portal.Stmt.AnonymizedStr,
 		}
+		portalRes := &portalResult{
+			RestrictedCommandResult: stmtRes,
+			portal:                  portal,
+			evalCtx:                 ex.planner.EvalContext(),
+			distSQLCfg:              &ex.server.cfg.DistSQLSrv.ServerConfig,
+			sessionMon:              ex.sessionMon,
+		}
 		stmtCtx := withStatement(ctx, ex.curStmt)
-		ev, payload, err = ex.execStmt(stmtCtx, curStmt, stmtRes, pinfo)
+		ev, payload, err = ex.execStmt(stmtCtx, curStmt, portalRes, pinfo)
 		if err != nil {
 			return err
 		}
@@ -1398,6 +1464,9 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 		ev = eventNonRetriableErr{IsCommit: fsm.False}
 		payload = eventNonRetriableErrPayload{err: tcmd.Err}
 	case Sync:
+		if ex.idleConn() {
+			ex.notifications.deliver(ctx)
+		}
 		// Note that the Sync result will flush results to the network connection.
 		res = ex.clientComm.CreateSyncResult(pos)
 		if ex.draining {
@@ -1430,6 +1499,9 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 			return errDrainingComplete
 		}
 	case Flush:
+		if ex.idleConn() {
+			ex.notifications.deliver(ctx)
+		}
 		// Closing the res will flush the connection's buffer.
 		res = ex.clientComm.CreateFlushResult(pos)
 	default:
@@ -1933,6 +2005,8 @@ func (ex *connExecutor) initEvalCtx(ctx context.Context, evalCtx *extendedEvalCo
 		TxnModesSetter:    ex,
 		SchemaChangers:    &ex.extraTxnState.schemaChangers,
 		Jobs:              &ex.extraTxnState.jobs,
+		Notifications:     &ex.notifications,
+		SQLCursors:        &ex.extraTxnState.sqlCursors,
 		schemaAccessors:   scInterface,
 		sqlStatsCollector: ex.statsCollector,
 	}
@@ -2010,6 +2084,7 @@ func (ex *connExecutor) resetPlanner(
 	p.semaCtx.Location = &ex.sessionData.DataConversion.Location
 	p.semaCtx.SearchPath = ex.sessionData.SearchPath
 	p.semaCtx.AsOfTimestamp = nil
+	p.semaCtx.AsOfBoundedStalenessMinTimestamp = nil
 	p.semaCtx.Annotations = tree.MakeAnnotations(numAnnotations)
 
 	ex.resetEvalCtx(&p.extendedEvalCtx, txn, stmtTS)
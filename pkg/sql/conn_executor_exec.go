@@ -318,25 +318,36 @@ func (ex *connExecutor) execStmtInOpenState(
 	ex.resetPlanner(ctx, p, ex.state.mu.txn, stmtTS, stmt.NumAnnotations)
 
 	if os.ImplicitTxn.Get() {
-		asOfTs, err := p.isAsOf(stmt.AST)
+		asOf, err := p.isAsOf(stmt.AST)
 		if err != nil {
 			return makeErrEvent(err)
 		}
-		if asOfTs != nil {
-			p.semaCtx.AsOfTimestamp = asOfTs
-			p.extendedEvalCtx.SetTxnTimestamp(asOfTs.GoTime())
-			ex.state.setHistoricalTimestamp(ctx, *asOfTs)
+		if asOf != nil {
+			if asOf.BoundedStaleness {
+				// The timestamp of a bounded staleness read is negotiated once the
+				// statement has been planned and the spans it reads are known. See
+				// negotiateBoundedStalenessTimestamp.
+				p.semaCtx.AsOfBoundedStalenessMinTimestamp = &asOf.Timestamp
+			} else {
+				p.semaCtx.AsOfTimestamp = &asOf.Timestamp
+				p.extendedEvalCtx.SetTxnTimestamp(asOf.Timestamp.GoTime())
+				ex.state.setHistoricalTimestamp(ctx, asOf.Timestamp)
+			}
 		}
 	} else {
 		// If we're in an explicit txn, we allow AOST but only if it matches with
 		// the transaction's timestamp. This is useful for running AOST statements
 		// using the InternalExecutor inside an external transaction; one might want
 		// to do that to force p.avoidCachedDescriptors to be set below.
-		ts, err := p.isAsOf(stmt.AST)
+		asOf, err := p.isAsOf(stmt.AST)
 		if err != nil {
 			return makeErrEvent(err)
 		}
-		if ts != nil {
+		if asOf != nil {
+			if asOf.BoundedStaleness {
+				return makeErrEvent(tree.ErrBoundedStalenessNotSupported)
+			}
+			ts := &asOf.Timestamp
 			if readTs := ex.state.getReadTimestamp(); *ts != readTs {
 				err = pgerror.Newf(pgcode.Syntax,
 					"inconsistent AS OF SYSTEM TIME timestamp; expected: %s", readTs)
@@ -572,6 +583,7 @@ func (ex *connExecutor) commitSQLTransactionInternal(
 	ctx context.Context, stmt tree.Statement,
 ) (ev fsm.Event, payload fsm.EventPayload, ok bool) {
 	ex.clearSavepoints()
+	ex.extraTxnState.sqlCursors.closeAll(ctx)
 
 	if err := ex.checkTableTwoVersionInvariant(ctx); err != nil {
 		ev, payload = ex.makeErrEvent(err, stmt)
@@ -597,6 +609,7 @@ func (ex *connExecutor) commitSQLTransactionInternal(
 // rolled-back and an event is produced.
 func (ex *connExecutor) rollbackSQLTransaction(ctx context.Context) (fsm.Event, fsm.EventPayload) {
 	ex.clearSavepoints()
+	ex.extraTxnState.sqlCursors.closeAll(ctx)
 
 	if err := ex.state.mu.txn.Rollback(ctx); err != nil {
 		log.Warningf(ctx, "txn rollback failed: %s", err)
@@ -656,6 +669,15 @@ func (ex *connExecutor) dispatchToExecutionEngine(
 		return nil
 	}
 
+	boundedStaleness := false
+	if planner.semaCtx.AsOfBoundedStalenessMinTimestamp != nil {
+		boundedStaleness, err = ex.negotiateBoundedStalenessTimestamp(ctx, planner)
+		if err != nil {
+			res.SetError(err)
+			return nil
+		}
+	}
+
 	var cols sqlbase.ResultColumns
 	if stmt.AST.StatementType() == tree.Rows {
 		cols = planColumns(planner.curPlan.plan)
@@ -669,6 +691,11 @@ func (ex *connExecutor) dispatchToExecutionEngine(
 	distributePlan := false
 	distributePlan = shouldDistributePlan(
 		ctx, ex.sessionData.DistSQLMode, ex.server.cfg.DistSQLPlanner, planner.curPlan.plan)
+	if boundedStaleness {
+		// The resolved timestamp was negotiated with the replicas nearest to
+		// this node, which remote flows would not necessarily read from.
+		distributePlan = false
+	}
 	ex.sessionTracing.TracePlanCheckEnd(ctx, nil, distributePlan)
 
 	if ex.server.cfg.TestingKnobs.BeforeExecute != nil {
@@ -709,6 +736,7 @@ func (ex *connExecutor) dispatchToExecutionEngine(
 	bytesRead, rowsRead, err := ex.execWithDistSQLEngine(ctx, planner, stmt.AST.StatementType(), res, distributePlan, progAtomic)
 	ex.sessionTracing.TraceExecEnd(ctx, res.Err(), res.RowsAffected())
 	ex.statsCollector.phaseTimes[plannerEndExecStmt] = timeutil.Now()
+	ex.recordContentionEvents(planner, stmt)
 
 	// Record the statement summary. This also closes the plan if the
 	// plan has not been closed earlier.
@@ -723,6 +751,20 @@ func (ex *connExecutor) dispatchToExecutionEngine(
 	return err
 }
 
+// recordContentionEvents records the contention events encountered by the
+// transaction while executing the statement in the node's contention registry.
+func (ex *connExecutor) recordContentionEvents(planner *planner, stmt *Statement) {
+	events := planner.txn.TakeContentionEvents()
+	if len(events) == 0 || ex.server.cfg.ContentionRegistry == nil {
+		return
+	}
+	fingerprint := stmt.AnonymizedStr
+	if fingerprint == "" {
+		fingerprint = anonymizeStmt(stmt.AST)
+	}
+	ex.server.cfg.ContentionRegistry.AddContentionEvents(fingerprint, events)
+}
+
 // makeExecPlan creates an execution plan and populates planner.curPlan, using
 // either the optimizer or the heuristic planner.
 func (ex *connExecutor) makeExecPlan(ctx context.Context, planner *planner) error {
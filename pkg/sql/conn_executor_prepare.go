@@ -199,13 +199,19 @@ func (ex *connExecutor) populatePrepared(
 	}
 	p.extendedEvalCtx.PrepareOnly = true
 
-	protoTS, err := p.isAsOf(stmt.AST)
+	asOf, err := p.isAsOf(stmt.AST)
 	if err != nil {
 		return 0, err
 	}
-	if protoTS != nil {
-		p.semaCtx.AsOfTimestamp = protoTS
-		txn.SetFixedTimestamp(ctx, *protoTS)
+	if asOf != nil {
+		if asOf.BoundedStaleness {
+			// The timestamp of a bounded staleness read is only negotiated when
+			// the statement is executed.
+			p.semaCtx.AsOfBoundedStalenessMinTimestamp = &asOf.Timestamp
+		} else {
+			p.semaCtx.AsOfTimestamp = &asOf.Timestamp
+			txn.SetFixedTimestamp(ctx, asOf.Timestamp)
+		}
 	}
 
 	// PREPARE has a limited subset of statements it can be run with. Postgres
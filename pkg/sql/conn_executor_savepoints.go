@@ -50,6 +50,7 @@ func (ex *connExecutor) execSavepointInOpenState(
 		return ev, payload, nil
 	}
 	ex.state.activeRestartSavepointName = s.Name
+	ex.notifications.setSavepoint()
 	// Note that Savepoint doesn't have a corresponding plan node.
 	// This here is all the execution there is.
 	return eventRetryIntentSet{}, nil /* payload */, nil
@@ -0,0 +1,204 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package contention
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	"github.com/biogo/store/llrb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/cache"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+)
+
+// Registry is an in-memory registry of the contention events encountered by
+// the statements executed on a node. Contention events are reported by the KV
+// layer whenever a request has to wait for the intents of another transaction
+// to be resolved, and are aggregated by the registry by statement fingerprint
+// and by the index containing the contended key.
+//
+// The registry is bounded: it tracks at most maxNumIndexes (fingerprint, index)
+// pairs and at most maxNumKeysPerIndex keys for each of them, evicting the
+// least recently contended entries first.
+//
+// Registry is safe for concurrent use.
+type Registry struct {
+	mu struct {
+		syncutil.Mutex
+		// indexes maps indexKey to *indexContention.
+		indexes *cache.OrderedCache
+	}
+}
+
+const (
+	// maxNumIndexes is the maximum number of (fingerprint, index) pairs tracked
+	// by a Registry.
+	maxNumIndexes = 1024
+	// maxNumKeysPerIndex is the maximum number of keys tracked for each
+	// (fingerprint, index) pair.
+	maxNumKeysPerIndex = 32
+)
+
+// indexKey identifies the contention events of a statement fingerprint on a
+// single index.
+type indexKey struct {
+	tableID     sqlbase.ID
+	indexID     sqlbase.IndexID
+	fingerprint string
+}
+
+// Compare implements the llrb.Comparable interface.
+func (k indexKey) Compare(o llrb.Comparable) int {
+	other := o.(indexKey)
+	if k.tableID != other.tableID {
+		if k.tableID < other.tableID {
+			return -1
+		}
+		return 1
+	}
+	if k.indexID != other.indexID {
+		if k.indexID < other.indexID {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(k.fingerprint, other.fingerprint)
+}
+
+// comparableKey is a roachpb.Key converted to a string so that it can be used
+// as a key of a cache.OrderedCache.
+type comparableKey string
+
+// Compare implements the llrb.Comparable interface.
+func (k comparableKey) Compare(o llrb.Comparable) int {
+	return strings.Compare(string(k), string(o.(comparableKey)))
+}
+
+// indexContention aggregates the contention events of a statement fingerprint
+// on a single index.
+type indexContention struct {
+	numContentionEvents      int64
+	cumulativeContentionTime time.Duration
+	// keys maps comparableKey to *keyContention.
+	keys *cache.OrderedCache
+}
+
+// keyContention aggregates the contention events of a statement fingerprint
+// on a single key.
+type keyContention struct {
+	numContentionEvents      int64
+	cumulativeContentionTime time.Duration
+	lastBlockingTxnID        uuid.UUID
+	lastWaitingTxnID         uuid.UUID
+}
+
+// NewRegistry creates a new Registry.
+func NewRegistry() *Registry {
+	r := &Registry{}
+	r.mu.indexes = newOrderedCache(maxNumIndexes)
+	return r
+}
+
+func newOrderedCache(maxSize int) *cache.OrderedCache {
+	return cache.NewOrderedCache(cache.Config{
+		Policy: cache.CacheLRU,
+		ShouldEvict: func(size int, _, _ interface{}) bool {
+			return size > maxSize
+		},
+	})
+}
+
+// AddContentionEvents records the contention events encountered by an
+// execution of the statement with the given fingerprint. Events on keys that
+// don't belong to a table are ignored.
+func (r *Registry) AddContentionEvents(fingerprint string, events []roachpb.ContentionEvent) {
+	if len(events) == 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range events {
+		r.addContentionEventLocked(fingerprint, &events[i])
+	}
+}
+
+func (r *Registry) addContentionEventLocked(fingerprint string, ev *roachpb.ContentionEvent) {
+	if bytes.Compare(ev.Key, keys.TableDataMin) < 0 {
+		return
+	}
+	_, tableID, indexID, err := sqlbase.DecodeTableIDIndexID(ev.Key)
+	if err != nil {
+		return
+	}
+
+	k := indexKey{tableID: tableID, indexID: indexID, fingerprint: fingerprint}
+	var index *indexContention
+	if v, ok := r.mu.indexes.Get(k); ok {
+		index = v.(*indexContention)
+	} else {
+		index = &indexContention{keys: newOrderedCache(maxNumKeysPerIndex)}
+		r.mu.indexes.Add(k, index)
+	}
+	index.numContentionEvents++
+	index.cumulativeContentionTime += ev.Duration
+
+	var key *keyContention
+	if v, ok := index.keys.Get(comparableKey(ev.Key)); ok {
+		key = v.(*keyContention)
+	} else {
+		key = &keyContention{}
+		index.keys.Add(comparableKey(ev.Key), key)
+	}
+	key.numContentionEvents++
+	key.cumulativeContentionTime += ev.Duration
+	key.lastBlockingTxnID = ev.TxnMeta.ID
+	key.lastWaitingTxnID = ev.WaitingTxnID
+}
+
+// Serialize returns the contention events tracked by the registry, ordered by
+// table ID, index ID and fingerprint. The NodeID of the returned events is left
+// for the caller to fill in.
+func (r *Registry) Serialize() []serverpb.IndexContention {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res := make([]serverpb.IndexContention, 0, r.mu.indexes.Len())
+	r.mu.indexes.Do(func(k, v interface{}) bool {
+		ik, index := k.(indexKey), v.(*indexContention)
+		ic := serverpb.IndexContention{
+			Fingerprint:              ik.fingerprint,
+			TableID:                  ik.tableID,
+			IndexID:                  ik.indexID,
+			NumContentionEvents:      index.numContentionEvents,
+			CumulativeContentionTime: index.cumulativeContentionTime,
+			Keys:                     make([]serverpb.KeyContention, 0, index.keys.Len()),
+		}
+		index.keys.Do(func(k, v interface{}) bool {
+			key := v.(*keyContention)
+			ic.Keys = append(ic.Keys, serverpb.KeyContention{
+				Key:                      roachpb.Key(k.(comparableKey)),
+				NumContentionEvents:      key.numContentionEvents,
+				CumulativeContentionTime: key.cumulativeContentionTime,
+				LastBlockingTxnID:        key.lastBlockingTxnID,
+				LastWaitingTxnID:         key.lastWaitingTxnID,
+			})
+			return false
+		})
+		res = append(res, ic)
+		return false
+	})
+	return res
+}
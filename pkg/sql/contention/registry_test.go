@@ -0,0 +1,129 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package contention
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func makeEvent(
+	tableID sqlbase.ID, indexID sqlbase.IndexID, val int64, txnID uuid.UUID, dur time.Duration,
+) roachpb.ContentionEvent {
+	key := sqlbase.EncodeTableIDIndexID(nil, tableID, indexID)
+	key = encoding.EncodeVarintAscending(key, val)
+	return roachpb.ContentionEvent{
+		Key:      key,
+		TxnMeta:  enginepb.TxnMeta{ID: txnID},
+		Duration: dur,
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	txn1, txn2, waiter := uuid.MakeV4(), uuid.MakeV4(), uuid.MakeV4()
+	waitingEvent := makeEvent(53, 1, 1, txn2, 2*time.Second)
+	waitingEvent.WaitingTxnID = waiter
+	r := NewRegistry()
+	r.AddContentionEvents("SELECT _ FROM t", []roachpb.ContentionEvent{
+		makeEvent(53, 1, 1, txn1, time.Second),
+		waitingEvent,
+		makeEvent(53, 1, 2, txn1, time.Second),
+		makeEvent(53, 2, 1, txn1, time.Second),
+		// Events on non-table keys are ignored.
+		{Key: keys.MakeRangeKeyPrefix(roachpb.RKey("a")), Duration: time.Second},
+	})
+	r.AddContentionEvents("UPDATE t SET v = _", []roachpb.ContentionEvent{
+		makeEvent(53, 1, 1, txn1, time.Second),
+	})
+
+	res := r.Serialize()
+	require.Len(t, res, 3)
+
+	require.Equal(t, "SELECT _ FROM t", res[0].Fingerprint)
+	require.Equal(t, sqlbase.ID(53), res[0].TableID)
+	require.Equal(t, sqlbase.IndexID(1), res[0].IndexID)
+	require.Equal(t, int64(3), res[0].NumContentionEvents)
+	require.Equal(t, 4*time.Second, res[0].CumulativeContentionTime)
+	require.Len(t, res[0].Keys, 2)
+	require.Equal(t, int64(2), res[0].Keys[0].NumContentionEvents)
+	require.Equal(t, 3*time.Second, res[0].Keys[0].CumulativeContentionTime)
+	require.Equal(t, txn2, res[0].Keys[0].LastBlockingTxnID)
+	require.Equal(t, waiter, res[0].Keys[0].LastWaitingTxnID)
+
+	require.Equal(t, "UPDATE t SET v = _", res[1].Fingerprint)
+	require.Equal(t, sqlbase.IndexID(1), res[1].IndexID)
+	require.Equal(t, int64(1), res[1].NumContentionEvents)
+
+	require.Equal(t, "SELECT _ FROM t", res[2].Fingerprint)
+	require.Equal(t, sqlbase.IndexID(2), res[2].IndexID)
+	require.Len(t, res[2].Keys, 1)
+}
+
+func TestRegistryEviction(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	txnID := uuid.MakeV4()
+	r := NewRegistry()
+	r.AddContentionEvents("SELECT _ FROM t", []roachpb.ContentionEvent{
+		makeEvent(53, 1, 0, txnID, time.Second),
+	})
+	for i := 0; i < maxNumIndexes; i++ {
+		r.AddContentionEvents(fmt.Sprintf("SELECT _ FROM t%d", i), []roachpb.ContentionEvent{
+			makeEvent(53, 1, 0, txnID, time.Second),
+		})
+	}
+
+	res := r.Serialize()
+	require.Len(t, res, maxNumIndexes)
+	for _, ic := range res {
+		// The fingerprint that was contended first was evicted.
+		require.NotEqual(t, "SELECT _ FROM t", ic.Fingerprint)
+		require.Len(t, ic.Keys, 1)
+	}
+}
+
+func TestRegistryKeyEviction(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	txnID := uuid.MakeV4()
+	r := NewRegistry()
+	for i := 0; i < maxNumKeysPerIndex+10; i++ {
+		r.AddContentionEvents("SELECT _ FROM t", []roachpb.ContentionEvent{
+			makeEvent(53, 1, int64(i), txnID, time.Second),
+		})
+	}
+
+	res := r.Serialize()
+	require.Len(t, res, 1)
+	// The index aggregates all events, but only the most recently contended
+	// keys are tracked.
+	require.Equal(t, int64(maxNumKeysPerIndex+10), res[0].NumContentionEvents)
+	require.Len(t, res[0].Keys, maxNumKeysPerIndex)
+	for _, kc := range res[0].Keys {
+		rest, _, _, err := sqlbase.DecodeTableIDIndexID(kc.Key)
+		require.NoError(t, err)
+		_, val, err := encoding.DecodeVarintAscending(rest)
+		require.NoError(t, err)
+		require.True(t, val >= 10, "expected key %d to be evicted", val)
+	}
+}
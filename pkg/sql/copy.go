@@ -381,6 +381,8 @@ func (c *copyMachine) addRow(ctx context.Context, line []byte) error {
 			types.StringFamily,
 			types.TimestampFamily,
 			types.TimestampTZFamily,
+			types.TSQueryFamily,
+			types.TSVectorFamily,
 			types.UuidFamily:
 			s, err = decodeCopy(s)
 			if err != nil {
@@ -39,11 +39,13 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/storage/storagepb"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	"gopkg.in/yaml.v2"
 )
@@ -64,41 +66,42 @@ const crdbInternalName = "crdb_internal"
 var crdbInternal = virtualSchema{
 	name: crdbInternalName,
 	tableDefs: map[sqlbase.ID]virtualSchemaDef{
-		sqlbase.CrdbInternalBackwardDependenciesTableID: crdbInternalBackwardDependenciesTable,
-		sqlbase.CrdbInternalBuildInfoTableID:            crdbInternalBuildInfoTable,
-		sqlbase.CrdbInternalBuiltinFunctionsTableID:     crdbInternalBuiltinFunctionsTable,
-		sqlbase.CrdbInternalClusterQueriesTableID:       crdbInternalClusterQueriesTable,
-		sqlbase.CrdbInternalClusterSessionsTableID:      crdbInternalClusterSessionsTable,
-		sqlbase.CrdbInternalClusterSettingsTableID:      crdbInternalClusterSettingsTable,
-		sqlbase.CrdbInternalCreateStmtsTableID:          crdbInternalCreateStmtsTable,
-		sqlbase.CrdbInternalFeatureUsageID:              crdbInternalFeatureUsage,
-		sqlbase.CrdbInternalForwardDependenciesTableID:  crdbInternalForwardDependenciesTable,
-		sqlbase.CrdbInternalGossipNodesTableID:          crdbInternalGossipNodesTable,
-		sqlbase.CrdbInternalGossipAlertsTableID:         crdbInternalGossipAlertsTable,
-		sqlbase.CrdbInternalGossipLivenessTableID:       crdbInternalGossipLivenessTable,
-		sqlbase.CrdbInternalGossipNetworkTableID:        crdbInternalGossipNetworkTable,
-		sqlbase.CrdbInternalIndexColumnsTableID:         crdbInternalIndexColumnsTable,
-		sqlbase.CrdbInternalJobsTableID:                 crdbInternalJobsTable,
-		sqlbase.CrdbInternalKVNodeStatusTableID:         crdbInternalKVNodeStatusTable,
-		sqlbase.CrdbInternalKVStoreStatusTableID:        crdbInternalKVStoreStatusTable,
-		sqlbase.CrdbInternalLeasesTableID:               crdbInternalLeasesTable,
-		sqlbase.CrdbInternalLocalQueriesTableID:         crdbInternalLocalQueriesTable,
-		sqlbase.CrdbInternalLocalSessionsTableID:        crdbInternalLocalSessionsTable,
-		sqlbase.CrdbInternalLocalMetricsTableID:         crdbInternalLocalMetricsTable,
-		sqlbase.CrdbInternalPartitionsTableID:           crdbInternalPartitionsTable,
-		sqlbase.CrdbInternalPredefinedCommentsTableID:   crdbInternalPredefinedCommentsTable,
-		sqlbase.CrdbInternalRangesNoLeasesTableID:       crdbInternalRangesNoLeasesTable,
-		sqlbase.CrdbInternalRangesViewID:                crdbInternalRangesView,
-		sqlbase.CrdbInternalRuntimeInfoTableID:          crdbInternalRuntimeInfoTable,
-		sqlbase.CrdbInternalSchemaChangesTableID:        crdbInternalSchemaChangesTable,
-		sqlbase.CrdbInternalSessionTraceTableID:         crdbInternalSessionTraceTable,
-		sqlbase.CrdbInternalSessionVariablesTableID:     crdbInternalSessionVariablesTable,
-		sqlbase.CrdbInternalStmtStatsTableID:            crdbInternalStmtStatsTable,
-		sqlbase.CrdbInternalTableColumnsTableID:         crdbInternalTableColumnsTable,
-		sqlbase.CrdbInternalTableIndexesTableID:         crdbInternalTableIndexesTable,
-		sqlbase.CrdbInternalTablesTableID:               crdbInternalTablesTable,
-		sqlbase.CrdbInternalTxnStatsTableID:             crdbInternalTxnStatsTable,
-		sqlbase.CrdbInternalZonesTableID:                crdbInternalZonesTable,
+		sqlbase.CrdbInternalBackwardDependenciesTableID:    crdbInternalBackwardDependenciesTable,
+		sqlbase.CrdbInternalBuildInfoTableID:               crdbInternalBuildInfoTable,
+		sqlbase.CrdbInternalBuiltinFunctionsTableID:        crdbInternalBuiltinFunctionsTable,
+		sqlbase.CrdbInternalClusterContentionEventsTableID: crdbInternalClusterContentionEventsTable,
+		sqlbase.CrdbInternalClusterQueriesTableID:          crdbInternalClusterQueriesTable,
+		sqlbase.CrdbInternalClusterSessionsTableID:         crdbInternalClusterSessionsTable,
+		sqlbase.CrdbInternalClusterSettingsTableID:         crdbInternalClusterSettingsTable,
+		sqlbase.CrdbInternalCreateStmtsTableID:             crdbInternalCreateStmtsTable,
+		sqlbase.CrdbInternalFeatureUsageID:                 crdbInternalFeatureUsage,
+		sqlbase.CrdbInternalForwardDependenciesTableID:     crdbInternalForwardDependenciesTable,
+		sqlbase.CrdbInternalGossipNodesTableID:             crdbInternalGossipNodesTable,
+		sqlbase.CrdbInternalGossipAlertsTableID:            crdbInternalGossipAlertsTable,
+		sqlbase.CrdbInternalGossipLivenessTableID:          crdbInternalGossipLivenessTable,
+		sqlbase.CrdbInternalGossipNetworkTableID:           crdbInternalGossipNetworkTable,
+		sqlbase.CrdbInternalIndexColumnsTableID:            crdbInternalIndexColumnsTable,
+		sqlbase.CrdbInternalJobsTableID:                    crdbInternalJobsTable,
+		sqlbase.CrdbInternalKVNodeStatusTableID:            crdbInternalKVNodeStatusTable,
+		sqlbase.CrdbInternalKVStoreStatusTableID:           crdbInternalKVStoreStatusTable,
+		sqlbase.CrdbInternalLeasesTableID:                  crdbInternalLeasesTable,
+		sqlbase.CrdbInternalLocalQueriesTableID:            crdbInternalLocalQueriesTable,
+		sqlbase.CrdbInternalLocalSessionsTableID:           crdbInternalLocalSessionsTable,
+		sqlbase.CrdbInternalLocalMetricsTableID:            crdbInternalLocalMetricsTable,
+		sqlbase.CrdbInternalPartitionsTableID:              crdbInternalPartitionsTable,
+		sqlbase.CrdbInternalPredefinedCommentsTableID:      crdbInternalPredefinedCommentsTable,
+		sqlbase.CrdbInternalRangesNoLeasesTableID:          crdbInternalRangesNoLeasesTable,
+		sqlbase.CrdbInternalRangesViewID:                   crdbInternalRangesView,
+		sqlbase.CrdbInternalRuntimeInfoTableID:             crdbInternalRuntimeInfoTable,
+		sqlbase.CrdbInternalSchemaChangesTableID:           crdbInternalSchemaChangesTable,
+		sqlbase.CrdbInternalSessionTraceTableID:            crdbInternalSessionTraceTable,
+		sqlbase.CrdbInternalSessionVariablesTableID:        crdbInternalSessionVariablesTable,
+		sqlbase.CrdbInternalStmtStatsTableID:               crdbInternalStmtStatsTable,
+		sqlbase.CrdbInternalTableColumnsTableID:            crdbInternalTableColumnsTable,
+		sqlbase.CrdbInternalTableIndexesTableID:            crdbInternalTableIndexesTable,
+		sqlbase.CrdbInternalTablesTableID:                  crdbInternalTablesTable,
+		sqlbase.CrdbInternalTxnStatsTableID:                crdbInternalTxnStatsTable,
+		sqlbase.CrdbInternalZonesTableID:                   crdbInternalZonesTable,
 	},
 	validWithNoDatabaseContext: true,
 }
@@ -1118,6 +1121,96 @@ func populateSessionsTable(
 	return nil
 }
 
+// crdbInternalClusterContentionEventsTable exposes the contention events
+// encountered by the statements executed on all nodes of the cluster,
+// aggregated by statement fingerprint and index. There is one row for every
+// key of an index on which contention was encountered.
+var crdbInternalClusterContentionEventsTable = virtualSchemaTable{
+	comment: "contention events encountered by statements (cluster RPC; expensive!)",
+	schema: `
+CREATE TABLE crdb_internal.cluster_contention_events (
+  node_id                        INT NOT NULL, -- the node on which the statements were executed
+  fingerprint                    STRING,       -- the fingerprint of the blocked statements
+  table_id                       INT,          -- the table containing the contended keys
+  index_id                       INT,          -- the index containing the contended keys
+  num_contention_events          INT,          -- the number of contention events on the index
+  cumulative_contention_time     INTERVAL,     -- the time spent waiting on the index
+  key                            BYTES,        -- the contended key
+  key_num_contention_events      INT,          -- the number of contention events on the key
+  key_cumulative_contention_time INTERVAL,     -- the time spent waiting on the key
+  last_blocking_txn_id           UUID,         -- the transaction that most recently blocked a statement on the key
+  last_waiting_txn_id            UUID          -- the transaction that was most recently blocked on the key
+)`,
+	populate: func(ctx context.Context, p *planner, _ *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if err := p.RequireAdminRole(ctx, "read crdb_internal.cluster_contention_events"); err != nil {
+			return err
+		}
+		response, err := p.extendedEvalCtx.StatusServer.ListContentionEvents(
+			ctx, &serverpb.ListContentionEventsRequest{},
+		)
+		if err != nil {
+			return err
+		}
+		for _, ic := range response.Events {
+			nodeID := tree.NewDInt(tree.DInt(ic.NodeID))
+			fingerprint := tree.NewDString(ic.Fingerprint)
+			tableID := tree.NewDInt(tree.DInt(ic.TableID))
+			indexID := tree.NewDInt(tree.DInt(ic.IndexID))
+			numEvents := tree.NewDInt(tree.DInt(ic.NumContentionEvents))
+			cumulativeTime := &tree.DInterval{
+				Duration: duration.MakeDuration(ic.CumulativeContentionTime.Nanoseconds(), 0, 0),
+			}
+			for _, kc := range ic.Keys {
+				// Non-transactional requests don't have a transaction ID.
+				lastWaitingTxnID := tree.DNull
+				if kc.LastWaitingTxnID != uuid.Nil {
+					lastWaitingTxnID = tree.NewDUuid(tree.DUuid{UUID: kc.LastWaitingTxnID})
+				}
+				if err := addRow(
+					nodeID,
+					fingerprint,
+					tableID,
+					indexID,
+					numEvents,
+					cumulativeTime,
+					tree.NewDBytes(tree.DBytes(kc.Key)),
+					tree.NewDInt(tree.DInt(kc.NumContentionEvents)),
+					&tree.DInterval{
+						Duration: duration.MakeDuration(kc.CumulativeContentionTime.Nanoseconds(), 0, 0),
+					},
+					tree.NewDUuid(tree.DUuid{UUID: kc.LastBlockingTxnID}),
+					lastWaitingTxnID,
+				); err != nil {
+					return err
+				}
+			}
+		}
+		for _, rpcErr := range response.Errors {
+			log.Warning(ctx, rpcErr.Message)
+			if rpcErr.NodeID != 0 {
+				// Add a row with this node ID, the error for fingerprint, and
+				// nulls for all other columns.
+				if err := addRow(
+					tree.NewDInt(tree.DInt(rpcErr.NodeID)), // node ID
+					tree.NewDString("-- "+rpcErr.Message),  // fingerprint
+					tree.DNull,                             // table_id
+					tree.DNull,                             // index_id
+					tree.DNull,                             // num_contention_events
+					tree.DNull,                             // cumulative_contention_time
+					tree.DNull,                             // key
+					tree.DNull,                             // key_num_contention_events
+					tree.DNull,                             // key_cumulative_contention_time
+					tree.DNull,                             // last_blocking_txn_id
+					tree.DNull,                             // last_waiting_txn_id
+				); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	},
+}
+
 // crdbInternalLocalMetricsTable exposes a snapshot of the metrics on the
 // current node.
 var crdbInternalLocalMetricsTable = virtualSchemaTable{
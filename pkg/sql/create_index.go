@@ -173,6 +173,13 @@ func MakeIndexDescriptor(
 	if err := indexDesc.FillColumns(n.Columns); err != nil {
 		return nil, err
 	}
+	if n.Inverted {
+		if err := checkInvertedIndexIsSupported(
+			params.ctx, params.EvalContext().Settings, tableDesc, &indexDesc,
+		); err != nil {
+			return nil, err
+		}
+	}
 	return &indexDesc, nil
 }
 
@@ -46,6 +46,14 @@ var createStatsPostEvents = settings.RegisterPublicBoolSetting(
 	false,
 )
 
+// multiColumnStatisticsClusterMode controls the cluster setting for enabling
+// automatic collection of multi-column statistics.
+var multiColumnStatisticsClusterMode = settings.RegisterPublicBoolSetting(
+	"sql.stats.multi_column_collection.enabled",
+	"multi-column statistics collection mode",
+	true,
+)
+
 func (p *planner) CreateStatistics(ctx context.Context, n *tree.CreateStats) (planNode, error) {
 	return &createStatsNode{
 		CreateStats: *n,
@@ -185,8 +193,13 @@ func (n *createStatsNode) makeJobRecord(ctx context.Context) (*jobs.Record, erro
 
 	// Identify which columns we should create statistics for.
 	var colStats []jobspb.CreateStatsDetails_ColStat
+	// The samplers of the nodes running older versions cannot build sketches
+	// of multiple columns.
+	multiColSupported := cluster.Version.IsActive(ctx, n.p.ExecCfg().Settings, cluster.VersionMultiColumnStats)
 	if len(n.ColumnNames) == 0 {
-		if colStats, err = createStatsDefaultColumns(tableDesc); err != nil {
+		multiColEnabled := multiColSupported &&
+			multiColumnStatisticsClusterMode.Get(&n.p.ExecCfg().Settings.SV)
+		if colStats, err = createStatsDefaultColumns(tableDesc, multiColEnabled); err != nil {
 			return nil, err
 		}
 	} else {
@@ -195,12 +208,21 @@ func (n *createStatsNode) makeJobRecord(ctx context.Context) (*jobs.Record, erro
 			return nil, err
 		}
 
+		if len(columns) > 1 && !multiColSupported {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"multi-column statistics are not supported until the cluster upgrade is finalized")
+		}
+
 		columnIDs := make([]sqlbase.ColumnID, len(columns))
 		for i := range columns {
 			if columns[i].Type.Family() == types.JsonFamily {
 				return nil, unimplemented.NewWithIssuef(35844,
 					"CREATE STATISTICS is not supported for JSON columns")
 			}
+			if !columnTypeSupportsStats(columns[i].Type) {
+				return nil, unimplemented.Newf("stats",
+					"CREATE STATISTICS is not supported for %s columns", columns[i].Type.Name())
+			}
 			columnIDs[i] = columns[i].ID
 		}
 		colStats = []jobspb.CreateStatsDetails_ColStat{{ColumnIDs: columnIDs, HasHistogram: false}}
@@ -250,6 +272,17 @@ func (n *createStatsNode) makeJobRecord(ctx context.Context) (*jobs.Record, erro
 	}, nil
 }
 
+// columnTypeSupportsStats returns whether statistics can be collected on
+// columns of the given type. Sampling relies on the key encoding of the
+// values, so types that cannot be key encoded are not supported.
+func columnTypeSupportsStats(t *types.T) bool {
+	switch t.Family() {
+	case types.JsonFamily, types.TSVectorFamily, types.TSQueryFamily:
+		return false
+	}
+	return true
+}
+
 // maxNonIndexCols is the maximum number of non-index columns that we will use
 // when choosing a default set of column statistics.
 const maxNonIndexCols = 100
@@ -263,28 +296,61 @@ const maxNonIndexCols = 100
 // queries that involve those columns (e.g., for filters), and it would be
 // useful to have statistics on prefixes of those columns. For example, if a
 // table abc contains indexes on (a ASC, b ASC) and (b ASC, c ASC), we will
-// collect statistics on a, {a, b}, b, and {b, c}.
+// collect statistics on a, {a, b}, b, and {b, c}. Statistics on multiple
+// columns are only collected if multiColEnabled is true.
 //
 // In addition to the index columns, we collect stats on up to maxNonIndexCols
-// other columns from the table. We only collect histograms for index columns,
-// plus any other boolean columns (where the "histogram" is tiny).
-//
-// TODO(rytaft): This currently only generates one single-column stat per
-// index. Add code to collect multi-column stats once they are supported.
+// other columns from the table. We collect histograms for every index column
+// (not only the leading ones), plus any other boolean columns (where the
+// "histogram" is tiny).
 func createStatsDefaultColumns(
-	desc *ImmutableTableDescriptor,
+	desc *ImmutableTableDescriptor, multiColEnabled bool,
 ) ([]jobspb.CreateStatsDetails_ColStat, error) {
 	colStats := make([]jobspb.CreateStatsDetails_ColStat, 0, len(desc.Indexes)+1)
 
+	// requestedStats contains the sets of columns for which stats have already
+	// been requested (keyed by the string representation of the set).
+	requestedStats := make(map[string]struct{})
 	var requestedCols util.FastIntSet
 
-	// Add a column for the primary key.
-	pkCol := desc.PrimaryIndex.ColumnIDs[0]
-	colStats = append(colStats, jobspb.CreateStatsDetails_ColStat{
-		ColumnIDs:    []sqlbase.ColumnID{pkCol},
-		HasHistogram: true,
-	})
-	requestedCols.Add(int(pkCol))
+	// addIndexColumnStats adds a single-column statistic with a histogram on
+	// each column of the given index, as well as multi-column statistics on all
+	// prefixes of the index if multiColEnabled is true. Statistics that have
+	// already been requested are skipped.
+	addIndexColumnStats := func(index *sqlbase.IndexDescriptor) {
+		var colSet util.FastIntSet
+		multiColOk := multiColEnabled
+		for i, colID := range index.ColumnIDs {
+			col, err := desc.FindActiveColumnByID(colID)
+			if err != nil || !columnTypeSupportsStats(col.Type) {
+				// A multi-column statistic is only requested if all of its columns
+				// support statistics.
+				multiColOk = false
+				continue
+			}
+			colSet.Add(int(colID))
+			if i > 0 && multiColOk {
+				key := colSet.String()
+				if _, ok := requestedStats[key]; !ok {
+					colStats = append(colStats, jobspb.CreateStatsDetails_ColStat{
+						ColumnIDs:    append([]sqlbase.ColumnID(nil), index.ColumnIDs[:i+1]...),
+						HasHistogram: false,
+					})
+					requestedStats[key] = struct{}{}
+				}
+			}
+			if !requestedCols.Contains(int(colID)) {
+				colStats = append(colStats, jobspb.CreateStatsDetails_ColStat{
+					ColumnIDs:    []sqlbase.ColumnID{colID},
+					HasHistogram: true,
+				})
+				requestedCols.Add(int(colID))
+			}
+		}
+	}
+
+	// Add columns for the primary key.
+	addIndexColumnStats(&desc.PrimaryIndex)
 
 	// Add columns for each secondary index.
 	for i := range desc.Indexes {
@@ -292,21 +358,15 @@ func createStatsDefaultColumns(
 			// We don't yet support stats on inverted indexes.
 			continue
 		}
-		idxCol := desc.Indexes[i].ColumnIDs[0]
-		if !requestedCols.Contains(int(idxCol)) {
-			colStats = append(colStats, jobspb.CreateStatsDetails_ColStat{
-				ColumnIDs:    []sqlbase.ColumnID{idxCol},
-				HasHistogram: true,
-			})
-			requestedCols.Add(int(idxCol))
-		}
+		addIndexColumnStats(&desc.Indexes[i])
 	}
 
-	// Add all remaining non-json columns in the table, up to maxNonIndexCols.
+	// Add all remaining columns in the table that support statistics, up to
+	// maxNonIndexCols.
 	nonIdxCols := 0
 	for i := 0; i < len(desc.Columns) && nonIdxCols < maxNonIndexCols; i++ {
 		col := &desc.Columns[i]
-		if col.Type.Family() != types.JsonFamily && !requestedCols.Contains(int(col.ID)) {
+		if columnTypeSupportsStats(col.Type) && !requestedCols.Contains(int(col.ID)) {
 			colStats = append(colStats, jobspb.CreateStatsDetails_ColStat{
 				ColumnIDs:    []sqlbase.ColumnID{col.ID},
 				HasHistogram: col.Type.Family() == types.BoolFamily,
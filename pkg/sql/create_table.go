@@ -1201,6 +1201,9 @@ func MakeTableDesc(
 					)
 				}
 			}
+			if err := checkColumnTypeIsSupported(ctx, st, d.Type); err != nil {
+				return desc, err
+			}
 			if d.PrimaryKey.Sharded {
 				if !cluster.Version.IsActive(ctx, st, cluster.VersionHashShardedIndexes) {
 					return desc, invalidClusterForShardedIndexError
@@ -1345,6 +1348,11 @@ func MakeTableDesc(
 			if err := idx.FillColumns(d.Columns); err != nil {
 				return desc, err
 			}
+			if d.Inverted {
+				if err := checkInvertedIndexIsSupported(ctx, st, &desc, &idx); err != nil {
+					return desc, err
+				}
+			}
 			if d.PartitionBy != nil {
 				partitioning, err := CreatePartitioning(ctx, st, evalCtx, &desc, &idx, d.PartitionBy)
 				if err != nil {
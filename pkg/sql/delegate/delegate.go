@@ -90,6 +90,9 @@ func TryDelegate(
 	case *tree.ShowSessions:
 		return d.delegateShowSessions(t)
 
+	case *tree.ShowStatementHints:
+		return d.delegateShowStatementHints(t)
+
 	case *tree.ShowSyntax:
 		return d.delegateShowSyntax(t)
 
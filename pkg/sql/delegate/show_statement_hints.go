@@ -0,0 +1,20 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package delegate
+
+import "github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+
+// delegateShowStatementHints implements SHOW STATEMENT HINTS, which returns the
+// hints bound to statement fingerprints with CREATE STATEMENT HINTS.
+// Privileges: SELECT on system.statement_hints.
+func (d *delegator) delegateShowStatementHints(n *tree.ShowStatementHints) (tree.Statement, error) {
+	return parse(`SELECT fingerprint, hints, created FROM system.statement_hints ORDER BY fingerprint`)
+}
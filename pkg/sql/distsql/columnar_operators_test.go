@@ -18,8 +18,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/col/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/typeconv"
@@ -27,6 +29,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
 )
@@ -56,13 +60,18 @@ func TestAggregatorAgainstProcessor(t *testing.T) {
 	}
 	var da sqlbase.DatumAlloc
 
-	deterministicAggFns := make([]execinfrapb.AggregatorSpec_Func, 0, len(colexec.SupportedAggFns)-1)
+	deterministicAggFns := make([]execinfrapb.AggregatorSpec_Func, 0, len(colexec.SupportedAggFns)-2)
 	for _, aggFn := range colexec.SupportedAggFns {
 		if aggFn == execinfrapb.AggregatorSpec_ANY_NOT_NULL {
 			// We skip ANY_NOT_NULL aggregate function because it returns
 			// non-deterministic results.
 			continue
 		}
+		if aggFn == execinfrapb.AggregatorSpec_STRING_AGG {
+			// We skip STRING_AGG aggregate function because it requires a
+			// constant delimiter argument.
+			continue
+		}
 		deterministicAggFns = append(deterministicAggFns, aggFn)
 	}
 	aggregations := make([]execinfrapb.AggregatorSpec_Aggregation, len(deterministicAggFns))
@@ -829,6 +838,120 @@ func generateFilterExpr(
 	return execinfrapb.Expression{Expr: fmt.Sprintf("@%d %s @%d", leftColIdx, comparison, rightColIdx)}
 }
 
+// TestJoinReaderAgainstProcessor verifies the columnar index and lookup joins
+// against the joinReader processor on random inputs.
+func TestJoinReaderAgainstProcessor(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	s, sqlDB, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	rng, _ := randutil.NewPseudoRand()
+
+	nTableRows := 50
+	nInputRows := 100
+	maxNum := 5
+	// The values of a and c are chosen from a small range, so that there are
+	// multiple matches for the same prefix of both the primary and the
+	// secondary index. c might also be NULL.
+	var values []string
+	for i := 0; i < nTableRows; i++ {
+		c := "NULL"
+		if rng.Float64() >= nullProbability {
+			c = fmt.Sprint(rng.Intn(maxNum))
+		}
+		values = append(values, fmt.Sprintf("(%d, %d, %s)", rng.Intn(maxNum), i, c))
+	}
+	r := sqlutils.MakeSQLRunner(sqlDB)
+	r.Exec(t, "CREATE DATABASE test")
+	r.Exec(t, "CREATE TABLE test.t (a INT, b INT, c INT, PRIMARY KEY (a, b), INDEX c_idx (c))")
+	r.Exec(t, "INSERT INTO test.t VALUES "+strings.Join(values, ", "))
+	tableDesc := sqlbase.GetTableDescriptor(kvDB, "test", "t")
+	tableTypes := tableDesc.ColumnTypes()
+	inputTypes := []types.T{*types.Int, *types.Int}
+
+	// The input of the index join consists of the primary keys of random rows
+	// of the table.
+	pkRows := r.QueryStr(t, "SELECT a, b FROM test.t")
+	indexJoinInput := make(sqlbase.EncDatumRows, nInputRows)
+	for i := range indexJoinInput {
+		pk := pkRows[rng.Intn(len(pkRows))]
+		indexJoinInput[i] = make(sqlbase.EncDatumRow, len(pk))
+		for j := range pk {
+			d, err := tree.ParseDInt(pk[j])
+			if err != nil {
+				t.Fatal(err)
+			}
+			indexJoinInput[i][j] = sqlbase.DatumToEncDatum(types.Int, d)
+		}
+	}
+	txn := client.NewTxn(ctx, kvDB, s.NodeID())
+	if err := verifyColOperator(verifyColOperatorArgs{
+		anyOrder:    true,
+		inputTypes:  [][]types.T{inputTypes},
+		inputs:      []sqlbase.EncDatumRows{indexJoinInput},
+		outputTypes: tableTypes,
+		pspec: &execinfrapb.ProcessorSpec{
+			Input: []execinfrapb.InputSyncSpec{{ColumnTypes: inputTypes}},
+			Core:  execinfrapb.ProcessorCoreUnion{JoinReader: &execinfrapb.JoinReaderSpec{Table: *tableDesc}},
+		},
+		txn: txn,
+	}); err != nil {
+		prettyPrintInput(indexJoinInput, inputTypes, "input" /* tableName */)
+		t.Fatal(err)
+	}
+
+	for _, lookup := range []struct {
+		indexIdx            uint32
+		lookupColumns       []uint32
+		lookupColumnsAreKey bool
+	}{
+		{indexIdx: 0, lookupColumns: []uint32{0}},
+		{indexIdx: 0, lookupColumns: []uint32{0, 1}, lookupColumnsAreKey: true},
+		{indexIdx: 1, lookupColumns: []uint32{0}},
+		{indexIdx: 1, lookupColumns: []uint32{0, 1}},
+	} {
+		for _, joinType := range []sqlbase.JoinType{
+			sqlbase.JoinType_INNER,
+			sqlbase.JoinType_LEFT_OUTER,
+			sqlbase.JoinType_LEFT_SEMI,
+			sqlbase.JoinType_LEFT_ANTI,
+		} {
+			// A memory limit of 1 byte forces the lookup join to look up a
+			// single input tuple at a time.
+			for _, memoryLimit := range []int64{0, 1, 256} {
+				input := sqlbase.MakeRandIntRowsInRange(rng, nInputRows, len(inputTypes), maxNum, nullProbability)
+				outputTypes := inputTypes
+				if joinType == sqlbase.JoinType_INNER || joinType == sqlbase.JoinType_LEFT_OUTER {
+					outputTypes = append(inputTypes[:len(inputTypes):len(inputTypes)], tableTypes...)
+				}
+				if err := verifyColOperator(verifyColOperatorArgs{
+					anyOrder:    true,
+					inputTypes:  [][]types.T{inputTypes},
+					inputs:      []sqlbase.EncDatumRows{input},
+					outputTypes: outputTypes,
+					pspec: &execinfrapb.ProcessorSpec{
+						Input: []execinfrapb.InputSyncSpec{{ColumnTypes: inputTypes}},
+						Core: execinfrapb.ProcessorCoreUnion{JoinReader: &execinfrapb.JoinReaderSpec{
+							Table:               *tableDesc,
+							IndexIdx:            lookup.indexIdx,
+							LookupColumns:       lookup.lookupColumns,
+							LookupColumnsAreKey: lookup.lookupColumnsAreKey,
+							Type:                joinType,
+						}},
+					},
+					txn:                   txn,
+					lookupJoinMemoryLimit: memoryLimit,
+				}); err != nil {
+					fmt.Printf("index: %d, lookup columns: %v, join type: %s, memory limit: %d\n",
+						lookup.indexIdx, lookup.lookupColumns, joinType, memoryLimit)
+					prettyPrintInput(input, inputTypes, "input" /* tableName */)
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+}
+
 func TestWindowFunctionsAgainstProcessor(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	rng, _ := randutil.NewPseudoRand()
@@ -836,17 +959,44 @@ func TestWindowFunctionsAgainstProcessor(t *testing.T) {
 	nRows := 10
 	maxCols := 4
 	maxNum := 5
-	typs := make([]types.T, maxCols)
+	// The input always has an additional column after the random ones that
+	// contains the same positive value in all rows. It is used as the argument
+	// of NTILE and NTH_VALUE that must be positive and are not deterministic if
+	// the argument differs between the rows of a partition.
+	typs := make([]types.T, maxCols+1)
 	for i := range typs {
-		// TODO(yuzefovich): randomize the types of the columns once we support
-		// window functions that take in arguments.
 		typs[i] = *types.Int
 	}
+	var windowFns []execinfrapb.WindowerSpec_Func
 	for _, windowFn := range []execinfrapb.WindowerSpec_WindowFunc{
 		execinfrapb.WindowerSpec_ROW_NUMBER,
 		execinfrapb.WindowerSpec_RANK,
 		execinfrapb.WindowerSpec_DENSE_RANK,
+		execinfrapb.WindowerSpec_PERCENT_RANK,
+		execinfrapb.WindowerSpec_CUME_DIST,
+		execinfrapb.WindowerSpec_NTILE,
+		execinfrapb.WindowerSpec_LAG,
+		execinfrapb.WindowerSpec_LEAD,
+		execinfrapb.WindowerSpec_FIRST_VALUE,
+		execinfrapb.WindowerSpec_LAST_VALUE,
+		execinfrapb.WindowerSpec_NTH_VALUE,
 	} {
+		windowFn := windowFn
+		windowFns = append(windowFns, execinfrapb.WindowerSpec_Func{WindowFunc: &windowFn})
+	}
+	for _, aggFn := range []execinfrapb.AggregatorSpec_Func{
+		execinfrapb.AggregatorSpec_COUNT_ROWS,
+		execinfrapb.AggregatorSpec_COUNT,
+		execinfrapb.AggregatorSpec_SUM_INT,
+		execinfrapb.AggregatorSpec_SUM,
+		execinfrapb.AggregatorSpec_AVG,
+		execinfrapb.AggregatorSpec_MIN,
+		execinfrapb.AggregatorSpec_MAX,
+	} {
+		aggFn := aggFn
+		windowFns = append(windowFns, execinfrapb.WindowerSpec_Func{AggregateFunc: &aggFn})
+	}
+	for _, fn := range windowFns {
 		for _, partitionBy := range [][]uint32{
 			{},     // No PARTITION BY clause.
 			{0},    // Partitioning on the first input column.
@@ -861,26 +1011,73 @@ func TestWindowFunctionsAgainstProcessor(t *testing.T) {
 					if len(partitionBy) > nCols || nOrderingCols > nCols {
 						continue
 					}
-					inputTypes := typs[:nCols]
+					inputTypes := typs[:nCols+1]
 					rows := sqlbase.MakeRandIntRowsInRange(rng, nRows, nCols, maxNum, nullProbability)
+					constArg := sqlbase.DatumToEncDatum(types.Int, tree.NewDInt(tree.DInt(1+rng.Intn(maxNum))))
+					for i := range rows {
+						rows[i] = append(rows[i], constArg)
+					}
+					constArgIdx := uint32(nCols)
 
 					windowerSpec := &execinfrapb.WindowerSpec{
 						PartitionBy: partitionBy,
 						WindowFns: []execinfrapb.WindowerSpec_WindowFn{
 							{
-								Func:         execinfrapb.WindowerSpec_Func{WindowFunc: &windowFn},
+								Func:         fn,
 								Ordering:     generateOrderingGivenPartitionBy(rng, nCols, nOrderingCols, partitionBy),
-								OutputColIdx: uint32(nCols),
+								FilterColIdx: -1,
+								OutputColIdx: uint32(nCols + 1),
 							},
 						},
 					}
-					if windowFn == execinfrapb.WindowerSpec_ROW_NUMBER &&
-						len(partitionBy)+len(windowerSpec.WindowFns[0].Ordering.Columns) < nCols {
-						// The output of row_number is not deterministic if there are
-						// columns that are not present in either PARTITION BY or ORDER BY
-						// clauses, so we skip such a configuration.
+					wf := &windowerSpec.WindowFns[0]
+					// orderSensitive indicates whether the output of the window
+					// function depends on the order of the rows within a peer group.
+					orderSensitive := false
+					if fn.AggregateFunc != nil {
+						if *fn.AggregateFunc != execinfrapb.AggregatorSpec_COUNT_ROWS {
+							wf.ArgsIdxs = []uint32{uint32(rng.Intn(nCols))}
+						}
+						wf.Frame = generateWindowFrame(rng, len(wf.Ordering.Columns) > 0)
+						orderSensitive = wf.Frame != nil && wf.Frame.Mode == execinfrapb.WindowerSpec_Frame_ROWS
+					} else {
+						switch *fn.WindowFunc {
+						case execinfrapb.WindowerSpec_RANK,
+							execinfrapb.WindowerSpec_DENSE_RANK,
+							execinfrapb.WindowerSpec_PERCENT_RANK,
+							execinfrapb.WindowerSpec_CUME_DIST:
+						case execinfrapb.WindowerSpec_ROW_NUMBER:
+							orderSensitive = true
+						case execinfrapb.WindowerSpec_NTILE:
+							wf.ArgsIdxs = []uint32{constArgIdx}
+							orderSensitive = true
+						case execinfrapb.WindowerSpec_LAG, execinfrapb.WindowerSpec_LEAD:
+							wf.ArgsIdxs = []uint32{uint32(rng.Intn(nCols))}
+							orderSensitive = true
+						case execinfrapb.WindowerSpec_FIRST_VALUE, execinfrapb.WindowerSpec_LAST_VALUE:
+							wf.ArgsIdxs = []uint32{uint32(rng.Intn(nCols))}
+							wf.Frame = generateWindowFrame(rng, len(wf.Ordering.Columns) > 0)
+							orderSensitive = true
+						case execinfrapb.WindowerSpec_NTH_VALUE:
+							wf.ArgsIdxs = []uint32{uint32(rng.Intn(nCols)), constArgIdx}
+							wf.Frame = generateWindowFrame(rng, len(wf.Ordering.Columns) > 0)
+							orderSensitive = true
+						}
+					}
+					if orderSensitive && len(partitionBy)+len(wf.Ordering.Columns) < nCols {
+						// The output of the window function is not deterministic if
+						// there are columns that are not present in either PARTITION BY
+						// or ORDER BY clauses, so we skip such a configuration.
 						continue
 					}
+					argTypes := make([]types.T, len(wf.ArgsIdxs))
+					for i, idx := range wf.ArgsIdxs {
+						argTypes[i] = inputTypes[idx]
+					}
+					outputType, err := colexec.GetWindowFnOutputType(fn, argTypes)
+					if err != nil {
+						t.Fatal(err)
+					}
 
 					pspec := &execinfrapb.ProcessorSpec{
 						Input: []execinfrapb.InputSyncSpec{{ColumnTypes: inputTypes}},
@@ -890,10 +1087,11 @@ func TestWindowFunctionsAgainstProcessor(t *testing.T) {
 						anyOrder:    true,
 						inputTypes:  [][]types.T{inputTypes},
 						inputs:      []sqlbase.EncDatumRows{rows},
-						outputTypes: append(inputTypes, *types.Int),
+						outputTypes: append(inputTypes[:nCols+1:nCols+1], *outputType),
 						pspec:       pspec,
 					}
 					if err := verifyColOperator(args); err != nil {
+						fmt.Printf("window function: %v, frame: %v\n", wf.Func, wf.Frame)
 						prettyPrintTypes(inputTypes, "t" /* tableName */)
 						prettyPrintInput(rows, inputTypes, "t" /* tableName */)
 						t.Fatal(err)
@@ -904,6 +1102,56 @@ func TestWindowFunctionsAgainstProcessor(t *testing.T) {
 	}
 }
 
+// generateWindowFrame returns a random window frame (or nil, meaning the
+// default frame) that is supported by the vectorized engine: frames in RANGE
+// mode with offsets and frames with exclusion are not supported, so they are
+// never generated. Frames in GROUPS mode are only generated if the window
+// function has an ORDER BY clause.
+func generateWindowFrame(rng *rand.Rand, hasOrdering bool) *execinfrapb.WindowerSpec_Frame {
+	if rng.Intn(4) == 0 {
+		return nil
+	}
+	// boundTypes are in the order in which the bounds can follow each other.
+	boundTypes := []execinfrapb.WindowerSpec_Frame_BoundType{
+		execinfrapb.WindowerSpec_Frame_UNBOUNDED_PRECEDING,
+		execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING,
+		execinfrapb.WindowerSpec_Frame_CURRENT_ROW,
+		execinfrapb.WindowerSpec_Frame_OFFSET_FOLLOWING,
+		execinfrapb.WindowerSpec_Frame_UNBOUNDED_FOLLOWING,
+	}
+	modes := []execinfrapb.WindowerSpec_Frame_Mode{
+		execinfrapb.WindowerSpec_Frame_ROWS,
+		execinfrapb.WindowerSpec_Frame_RANGE,
+	}
+	if hasOrdering {
+		modes = append(modes, execinfrapb.WindowerSpec_Frame_GROUPS)
+	}
+	frame := &execinfrapb.WindowerSpec_Frame{Mode: modes[rng.Intn(len(modes))]}
+	if frame.Mode == execinfrapb.WindowerSpec_Frame_RANGE {
+		boundTypes = []execinfrapb.WindowerSpec_Frame_BoundType{
+			execinfrapb.WindowerSpec_Frame_UNBOUNDED_PRECEDING,
+			execinfrapb.WindowerSpec_Frame_CURRENT_ROW,
+			execinfrapb.WindowerSpec_Frame_UNBOUNDED_FOLLOWING,
+		}
+	}
+	// The start bound cannot be UNBOUNDED FOLLOWING, the end bound cannot be
+	// UNBOUNDED PRECEDING, and the end bound cannot precede the start bound.
+	startIdx := rng.Intn(len(boundTypes) - 1)
+	endIdx := startIdx + rng.Intn(len(boundTypes)-startIdx)
+	if endIdx == 0 {
+		endIdx = 1 + rng.Intn(len(boundTypes)-1)
+	}
+	frame.Bounds.Start = execinfrapb.WindowerSpec_Frame_Bound{BoundType: boundTypes[startIdx]}
+	frame.Bounds.End = &execinfrapb.WindowerSpec_Frame_Bound{BoundType: boundTypes[endIdx]}
+	for _, bound := range []*execinfrapb.WindowerSpec_Frame_Bound{&frame.Bounds.Start, frame.Bounds.End} {
+		if bound.BoundType == execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING ||
+			bound.BoundType == execinfrapb.WindowerSpec_Frame_OFFSET_FOLLOWING {
+			bound.IntOffset = uint64(rng.Intn(3))
+		}
+	}
+	return frame
+}
+
 func isSupportedType(typ *types.T) bool {
 	converted := typeconv.FromColumnType(typ)
 	return converted != coltypes.Unhandled
@@ -17,6 +17,7 @@ import (
 	"strconv"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
@@ -43,6 +44,12 @@ type verifyColOperatorArgs struct {
 	// forceDiskSpill, if set, will force the operator to spill to disk.
 	forceDiskSpill      bool
 	maxNumberPartitions int
+	// txn, if set, is the transaction used by the processor and the operator
+	// that read from KV.
+	txn *client.Txn
+	// lookupJoinMemoryLimit, if positive, overrides the memory limit of a
+	// single lookup of the columnar lookup join.
+	lookupJoinMemoryLimit int64
 }
 
 // verifyColOperator passes inputs through both the processor defined by pspec
@@ -77,6 +84,7 @@ func verifyColOperator(args verifyColOperatorArgs) error {
 		},
 	}
 	flowCtx.Cfg.TestingKnobs.ForceDiskSpill = args.forceDiskSpill
+	flowCtx.Txn = args.txn
 
 	inputsProc := make([]execinfra.RowSource, len(args.inputs))
 	inputsColOp := make([]execinfra.RowSource, len(args.inputs))
@@ -122,6 +130,7 @@ func verifyColOperator(args verifyColOperatorArgs) error {
 	if args.maxNumberPartitions > 0 {
 		constructorArgs.TestingKnobs.MaxNumberPartitions = args.maxNumberPartitions
 	}
+	constructorArgs.TestingKnobs.LookupJoinMemoryLimit = args.lookupJoinMemoryLimit
 	result, err := colexec.NewColOperator(ctx, flowCtx, constructorArgs)
 	if err != nil {
 		return err
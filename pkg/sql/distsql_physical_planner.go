@@ -200,7 +200,10 @@ func (dsp *DistSQLPlanner) SetSpanResolver(spanResolver physicalplan.SpanResolve
 // distSQLExprCheckVisitor is a tree.Visitor that checks if expressions
 // contain things not supported by distSQL (like subqueries).
 type distSQLExprCheckVisitor struct {
-	err error
+	// nestedArrays is set if nested arrays and array slices can be sent to
+	// other nodes, which requires all of them to support them.
+	nestedArrays bool
+	err          error
 }
 
 var _ tree.Visitor = &distSQLExprCheckVisitor{}
@@ -209,7 +212,24 @@ func (v *distSQLExprCheckVisitor) VisitPre(expr tree.Expr) (recurse bool, newExp
 	if v.err != nil {
 		return false, expr
 	}
+	if !v.nestedArrays {
+		if t, ok := expr.(tree.TypedExpr); ok && isNestedArray(t.ResolvedType()) {
+			v.err = newQueryNotSupportedError(
+				"nested arrays are not supported by distsql until the cluster upgrade is finalized")
+			return false, expr
+		}
+	}
 	switch t := expr.(type) {
+	case *tree.IndirectionExpr:
+		if !v.nestedArrays {
+			for _, subscript := range t.Indirection {
+				if subscript.Slice {
+					v.err = newQueryNotSupportedError(
+						"array slices are not supported by distsql until the cluster upgrade is finalized")
+					return false, expr
+				}
+			}
+		}
 	case *tree.FuncExpr:
 		if t.IsDistSQLBlacklist() {
 			v.err = newQueryNotSupportedErrorf("function %s cannot be executed with distsql", t)
@@ -235,11 +255,33 @@ func (dsp *DistSQLPlanner) checkExpr(expr tree.Expr) error {
 	if expr == nil {
 		return nil
 	}
-	v := distSQLExprCheckVisitor{}
+	v := distSQLExprCheckVisitor{
+		nestedArrays: cluster.Version.IsActive(context.TODO(), dsp.st, cluster.VersionNestedArrays),
+	}
 	tree.WalkExprConst(&v, expr)
 	return v.err
 }
 
+// checkColumnTypes verifies that the results of a node can be sent to other
+// nodes.
+func (dsp *DistSQLPlanner) checkColumnTypes(cols sqlbase.ResultColumns) error {
+	if cluster.Version.IsActive(context.TODO(), dsp.st, cluster.VersionNestedArrays) {
+		return nil
+	}
+	for i := range cols {
+		if isNestedArray(cols[i].Typ) {
+			return newQueryNotSupportedError(
+				"nested arrays are not supported by distsql until the cluster upgrade is finalized")
+		}
+	}
+	return nil
+}
+
+// isNestedArray returns true if typ is an array whose elements are arrays.
+func isNestedArray(typ *types.T) bool {
+	return typ.Family() == types.ArrayFamily && typ.ArrayContents().Family() == types.ArrayFamily
+}
+
 type distRecommendation int
 
 const (
@@ -341,6 +383,9 @@ func (dsp *DistSQLPlanner) mustWrapNode(planCtx *PlanningCtx, node planNode) boo
 // this plan couldn't be distributed.
 // TODO(radu): add tests for this.
 func (dsp *DistSQLPlanner) checkSupportForNode(node planNode) (distRecommendation, error) {
+	if err := dsp.checkColumnTypes(planColumns(node)); err != nil {
+		return cannotDistribute, err
+	}
 	switch n := node.(type) {
 	// Keep these cases alphabetized, please!
 	case *distinctNode:
@@ -36,6 +36,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
@@ -1232,3 +1233,35 @@ func TestCheckNodeHealth(t *testing.T) {
 		})
 	}
 }
+
+// TestCheckExprNestedArrays verifies that expressions involving nested arrays
+// or array slices are only distributed once all the nodes support them.
+func TestCheckExprNestedArrays(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	intArray := types.MakeArray(types.Int)
+	exprs := []tree.TypedExpr{
+		tree.NewDArray(intArray),
+		tree.NewTypedArraySliceExpr(
+			tree.NewDArray(types.Int), []tree.TypedExpr{tree.NewDInt(1), tree.NewDInt(2)}, intArray,
+		),
+	}
+	for _, tc := range []struct {
+		version   cluster.VersionKey
+		expectErr bool
+	}{
+		{version: cluster.VersionNestedArrays - 1, expectErr: true},
+		{version: cluster.VersionNestedArrays, expectErr: false},
+	} {
+		v := cluster.VersionByKey(tc.version)
+		dsp := DistSQLPlanner{st: cluster.MakeTestingClusterSettingsWithVersion(v, v)}
+		for _, expr := range exprs {
+			err := dsp.checkExpr(expr)
+			if tc.expectErr && !testutils.IsError(err, "not supported by distsql until the cluster upgrade is finalized") {
+				t.Errorf("%s at %s: expected error, got %v", expr, tc.version, err)
+			} else if !tc.expectErr && err != nil {
+				t.Errorf("%s at %s: unexpected error: %v", expr, tc.version, err)
+			}
+		}
+	}
+}
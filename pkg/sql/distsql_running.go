@@ -36,7 +36,6 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
-	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
@@ -721,17 +720,7 @@ func (r *DistSQLReceiver) Push(
 			// distinction and just uses resultWriter.Err() to see if we're still
 			// accepting results.
 			r.resultWriter.SetError(commErr)
-
-			// We don't need to shut down the connection
-			// if there's a portal-related error. This is
-			// definitely a layering violation, but is part
-			// of some accepted technical debt (see comments on
-			// sql/pgwire.limitedCommandResult.moreResultsNeeded).
-			// Instead of changing the signature of AddRow, we have
-			// a sentinel error that is handled specially here.
-			if !errors.Is(commErr, ErrLimitedResultNotSupported) {
-				r.commErr = commErr
-			}
+			r.commErr = commErr
 		}
 		// TODO(andrei): We should drain here. Metadata from this query would be
 		// useful, particularly as it was likely a large query (since AddRow()
@@ -742,12 +731,14 @@ func (r *DistSQLReceiver) Push(
 }
 
 var (
-	// ErrLimitedResultNotSupported is an error produced by pgwire
-	// indicating an unsupported feature of row count limits was attempted.
-	ErrLimitedResultNotSupported = unimplemented.NewWithIssue(40195, "multiple active portals not supported")
 	// ErrLimitedResultClosed is a sentinel error produced by pgwire
 	// indicating the portal should be closed without error.
 	ErrLimitedResultClosed = errors.New("row count limit closed")
+	// ErrLimitedResultSuspended is a sentinel error produced by pgwire
+	// indicating that the client executes other commands while the portal is
+	// suspended. The rest of the portal's rows need to be buffered so that its
+	// execution can finish (see portalSpool).
+	ErrLimitedResultSuspended = errors.New("row count limit suspended")
 )
 
 // ProducerDone is part of the RowReceiver interface.
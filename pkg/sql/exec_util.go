@@ -39,6 +39,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec"
+	"github.com/cockroachdb/cockroach/pkg/sql/contention"
 	"github.com/cockroachdb/cockroach/pkg/sql/distsql"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
@@ -55,7 +56,6 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/protectedts"
 	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
-	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
@@ -171,6 +171,12 @@ var hashShardedIndexesEnabledClusterMode = settings.RegisterBoolSetting(
 	false,
 )
 
+var greedyJoinReorderClusterMode = settings.RegisterBoolSetting(
+	"sql.defaults.experimental_greedy_join_reorder.enabled",
+	"default value for experimental_enable_greedy_join_reorder; allows greedy join reordering beyond the reorder_joins_limit by default",
+	false,
+)
+
 var zigzagJoinClusterMode = settings.RegisterBoolSetting(
 	"sql.defaults.zigzag_join.enabled",
 	"default value for enable_zigzag_join session setting; allows use of zig-zag join by default",
@@ -561,6 +567,13 @@ type ExecutorConfig struct {
 	// Role membership cache.
 	RoleMemberCache *MembershipCache
 
+	// StatementHintsCache caches the hints bound to statement fingerprints.
+	StatementHintsCache *StatementHintsCache
+
+	// ContentionRegistry records the contention events encountered by the
+	// statements executed on this node.
+	ContentionRegistry *contention.Registry
+
 	// ProtectedTimestampProvider encapsulates the protected timestamp subsystem.
 	ProtectedTimestampProvider protectedts.Provider
 }
@@ -818,18 +831,11 @@ func checkResultType(typ *types.T) error {
 	case types.JsonFamily:
 	case types.UuidFamily:
 	case types.INetFamily:
+	case types.TSVectorFamily:
+	case types.TSQueryFamily:
 	case types.OidFamily:
 	case types.TupleFamily:
 	case types.ArrayFamily:
-		if typ.ArrayContents().Family() == types.ArrayFamily {
-			// Technically we could probably return arrays of arrays to a
-			// client (the encoding exists) but we don't want to give
-			// mixed signals -- that nested arrays appear to be supported
-			// in this case, and not in other cases (eg. CREATE). So we
-			// reject them in every case instead.
-			return unimplemented.NewWithIssueDetail(32552,
-				"result", "arrays cannot have arrays as element type")
-		}
 	case types.AnyFamily:
 		// Placeholder case.
 		return errors.Errorf("could not determine data type of %s", typ)
@@ -840,17 +846,30 @@ func checkResultType(typ *types.T) error {
 }
 
 // EvalAsOfTimestamp evaluates and returns the timestamp from an AS OF SYSTEM
-// TIME clause.
+// TIME clause. Bounded staleness clauses are rejected.
 func (p *planner) EvalAsOfTimestamp(asOf tree.AsOfClause) (_ hlc.Timestamp, err error) {
-	ts, err := tree.EvalAsOfTimestamp(asOf, &p.semaCtx, p.EvalContext())
+	asOfTS, err := p.EvalAsOf(asOf)
 	if err != nil {
 		return hlc.Timestamp{}, err
 	}
-	if now := p.execCfg.Clock.Now(); now.Less(ts) {
-		return hlc.Timestamp{}, errors.Errorf(
-			"AS OF SYSTEM TIME: cannot specify timestamp in the future (%s > %s)", ts, now)
+	if asOfTS.BoundedStaleness {
+		return hlc.Timestamp{}, tree.ErrBoundedStalenessNotSupported
+	}
+	return asOfTS.Timestamp, nil
+}
+
+// EvalAsOf evaluates an AS OF SYSTEM TIME clause, which may be a bounded
+// staleness clause.
+func (p *planner) EvalAsOf(asOf tree.AsOfClause) (tree.AsOfSystemTime, error) {
+	asOfTS, err := tree.EvalAsOf(asOf, &p.semaCtx, p.EvalContext())
+	if err != nil {
+		return tree.AsOfSystemTime{}, err
+	}
+	if now := p.execCfg.Clock.Now(); now.Less(asOfTS.Timestamp) {
+		return tree.AsOfSystemTime{}, errors.Errorf(
+			"AS OF SYSTEM TIME: cannot specify timestamp in the future (%s > %s)", asOfTS.Timestamp, now)
 	}
-	return ts, nil
+	return asOfTS, nil
 }
 
 // ParseHLC parses a string representation of an `hlc.Timestamp`.
@@ -876,10 +895,12 @@ func ParseHLC(s string) (hlc.Timestamp, error) {
 
 // isAsOf analyzes a statement to bypass the logic in newPlan(), since
 // that requires the transaction to be started already. If the returned
-// timestamp is not nil, it is the timestamp to which a transaction
-// should be set. The statements that will be checked are Select,
-// ShowTrace (of a Select statement), Scrub, Export, and CreateStats.
-func (p *planner) isAsOf(stmt tree.Statement) (*hlc.Timestamp, error) {
+// AS OF SYSTEM TIME is not nil, its timestamp is the timestamp to which a
+// transaction should be set or, for bounded staleness reads, the minimum
+// such timestamp. The statements that will be checked are Select,
+// ShowTrace (of a Select statement), Scrub, Export, and CreateStats. Only
+// Select statements may use bounded staleness.
+func (p *planner) isAsOf(stmt tree.Statement) (*tree.AsOfSystemTime, error) {
 	var asOf tree.AsOfClause
 	switch s := stmt.(type) {
 	case *tree.Select:
@@ -898,26 +919,43 @@ func (p *planner) isAsOf(stmt tree.Statement) (*hlc.Timestamp, error) {
 			return nil, nil
 		}
 
-		asOf = sc.From.AsOf
+		asOfTS, err := p.EvalAsOf(sc.From.AsOf)
+		if err != nil {
+			return nil, err
+		}
+		return &asOfTS, nil
 	case *tree.Scrub:
 		if s.AsOf.Expr == nil {
 			return nil, nil
 		}
 		asOf = s.AsOf
 	case *tree.Export:
-		return p.isAsOf(s.Query)
+		return p.isAsOfWithoutBoundedStaleness(s.Query)
 	case *tree.CreateStats:
 		if s.Options.AsOf.Expr == nil {
 			return nil, nil
 		}
 		asOf = s.Options.AsOf
 	case *tree.Explain:
-		return p.isAsOf(s.Statement)
+		return p.isAsOfWithoutBoundedStaleness(s.Statement)
 	default:
 		return nil, nil
 	}
 	ts, err := p.EvalAsOfTimestamp(asOf)
-	return &ts, err
+	return &tree.AsOfSystemTime{Timestamp: ts}, err
+}
+
+// isAsOfWithoutBoundedStaleness is like isAsOf, but rejects bounded staleness
+// AS OF SYSTEM TIME clauses.
+func (p *planner) isAsOfWithoutBoundedStaleness(stmt tree.Statement) (*tree.AsOfSystemTime, error) {
+	asOfTS, err := p.isAsOf(stmt)
+	if err != nil {
+		return nil, err
+	}
+	if asOfTS != nil && asOfTS.BoundedStaleness {
+		return nil, tree.ErrBoundedStalenessNotSupported
+	}
+	return asOfTS, nil
 }
 
 // isSavepoint returns true if stmt is a SAVEPOINT statement.
@@ -1870,6 +1908,10 @@ func (m *sessionDataMutator) SetReorderJoinsLimit(val int) {
 	m.data.ReorderJoinsLimit = val
 }
 
+func (m *sessionDataMutator) SetGreedyJoinReorderEnabled(val bool) {
+	m.data.GreedyJoinReorderEnabled = val
+}
+
 func (m *sessionDataMutator) SetVectorize(val sessiondata.VectorizeExecMode) {
 	m.data.VectorizeMode = val
 }
@@ -0,0 +1,557 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/gossip"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// Notification is an asynchronous message generated by NOTIFY or pg_notify()
+// and delivered to the sessions listening on its channel.
+type Notification struct {
+	Channel string
+	Payload string
+	// NodeID is the node on which the notification was published. It is
+	// reported to clients in place of the notifying backend's process ID.
+	NodeID roachpb.NodeID
+}
+
+const (
+	// maxNotificationChannelLength is the maximum length of a channel name,
+	// matching the Postgres identifier length limit.
+	maxNotificationChannelLength = 63
+
+	// maxNotificationPayloadLength is the maximum length of a notification
+	// payload, matching the Postgres limit.
+	maxNotificationPayloadLength = 8000
+
+	// maxPendingNotifications bounds the number of undelivered notifications a
+	// session buffers. When it is exceeded, the oldest notifications are dropped.
+	maxPendingNotifications = 1 << 16
+
+	// notificationGossipWindow is the number of recent notifications each node
+	// keeps in its gossiped notification info. Gossip only propagates the latest
+	// value of every key, so a window is needed for remote nodes to not miss
+	// notifications published in quick succession.
+	notificationGossipWindow = 256
+
+	// notificationGossipTTL is the TTL of the gossiped notification info. A node
+	// that stops publishing notifications eventually stops occupying space in
+	// gossip.
+	notificationGossipTTL = time.Minute
+)
+
+// notificationRegistry keeps track of the sessions on this node that listen on
+// notification channels and routes the notifications published anywhere in the
+// cluster to them.
+//
+// Notifications published on this node are delivered to local listeners
+// directly. They are also gossiped under a per-node key so that the registries
+// on the other nodes can deliver them to their own listeners. Delivery to
+// remote nodes is best effort: a node that falls behind by more than
+// notificationGossipWindow notifications misses the oldest ones.
+type notificationRegistry struct {
+	gossip *gossip.Gossip
+	nodeID *base.NodeIDContainer
+	// epoch identifies this incarnation of the registry. It allows receivers
+	// to detect that a node restarted and that its sequence numbers were reset.
+	epoch uint64
+
+	mu struct {
+		syncutil.Mutex
+		// listeners maps a channel name to the queues of the sessions that
+		// listen on it.
+		listeners map[string]map[*notificationQueue]struct{}
+		// seq is the sequence number of the last notification published by this
+		// node.
+		seq uint64
+		// recent contains this node's most recently published notifications, in
+		// increasing sequence order.
+		recent []sequencedNotification
+		// lastSeen contains, for every remote node, the epoch and the sequence
+		// number of the last notification received from it.
+		lastSeen map[roachpb.NodeID]remoteNotificationCursor
+	}
+}
+
+type sequencedNotification struct {
+	seq uint64
+	Notification
+}
+
+type remoteNotificationCursor struct {
+	epoch, seq uint64
+}
+
+// newNotificationRegistry creates a notificationRegistry. If g is nil,
+// notifications are only delivered to listeners on this node.
+func newNotificationRegistry(g *gossip.Gossip, nodeID *base.NodeIDContainer) *notificationRegistry {
+	r := &notificationRegistry{
+		gossip: g,
+		nodeID: nodeID,
+		epoch:  uint64(timeutil.Now().UnixNano()),
+	}
+	r.mu.listeners = make(map[string]map[*notificationQueue]struct{})
+	r.mu.lastSeen = make(map[roachpb.NodeID]remoteNotificationCursor)
+	if g != nil {
+		g.RegisterCallback(
+			gossip.MakePrefixPattern(gossip.KeySQLNotificationPrefix),
+			r.notificationGossipUpdate,
+		)
+	}
+	return r
+}
+
+// listen subscribes q to the given channel.
+func (r *notificationRegistry) listen(q *notificationQueue, channel string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	queues, ok := r.mu.listeners[channel]
+	if !ok {
+		queues = make(map[*notificationQueue]struct{})
+		r.mu.listeners[channel] = queues
+	}
+	queues[q] = struct{}{}
+}
+
+// unlisten unsubscribes q from the given channel. It is a no-op if q was not
+// subscribed to it.
+func (r *notificationRegistry) unlisten(q *notificationQueue, channel string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.unlistenLocked(q, channel)
+}
+
+// unlistenAll unsubscribes q from all the channels.
+func (r *notificationRegistry) unlistenAll(q *notificationQueue) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for channel := range r.mu.listeners {
+		r.unlistenLocked(q, channel)
+	}
+}
+
+func (r *notificationRegistry) unlistenLocked(q *notificationQueue, channel string) {
+	queues, ok := r.mu.listeners[channel]
+	if !ok {
+		return
+	}
+	delete(queues, q)
+	if len(queues) == 0 {
+		delete(r.mu.listeners, channel)
+	}
+}
+
+// publish delivers the given notifications to the listeners on this node and
+// gossips them to the other nodes.
+func (r *notificationRegistry) publish(ctx context.Context, notifications []Notification) {
+	if len(notifications) == 0 {
+		return
+	}
+	nodeID := r.nodeID.Get()
+	r.mu.Lock()
+	for i := range notifications {
+		n := notifications[i]
+		n.NodeID = nodeID
+		r.deliverLocked(n)
+		r.mu.seq++
+		r.mu.recent = append(r.mu.recent, sequencedNotification{seq: r.mu.seq, Notification: n})
+	}
+	if len(r.mu.recent) > notificationGossipWindow {
+		r.mu.recent = append(r.mu.recent[:0], r.mu.recent[len(r.mu.recent)-notificationGossipWindow:]...)
+	}
+	var encoded []byte
+	if r.gossip != nil {
+		encoded = encodeNotifications(r.epoch, r.mu.recent)
+	}
+	r.mu.Unlock()
+
+	if encoded != nil {
+		if err := r.gossip.AddInfo(
+			gossip.MakeSQLNotificationKey(nodeID), encoded, notificationGossipTTL,
+		); err != nil {
+			log.Warningf(ctx, "failed to gossip notifications: %v", err)
+		}
+	}
+}
+
+// deliverLocked pushes n onto the queues listening on its channel.
+func (r *notificationRegistry) deliverLocked(n Notification) {
+	for q := range r.mu.listeners[n.Channel] {
+		q.push(n)
+	}
+}
+
+// notificationGossipUpdate is the gossip callback that fires when a node
+// gossips its recent notifications.
+func (r *notificationRegistry) notificationGossipUpdate(key string, value roachpb.Value) {
+	ctx := context.TODO()
+	nodeID, err := gossip.NodeIDFromKey(key, gossip.KeySQLNotificationPrefix)
+	if err != nil {
+		log.Errorf(ctx, "notificationGossipUpdate(%s) error: %v", key, err)
+		return
+	}
+	if nodeID == r.nodeID.Get() {
+		// Local notifications have already been delivered by publish.
+		return
+	}
+	buf, err := value.GetBytes()
+	if err != nil {
+		log.Errorf(ctx, "notificationGossipUpdate(%s) error: %v", key, err)
+		return
+	}
+	epoch, notifications, err := decodeNotifications(buf)
+	if err != nil {
+		log.Errorf(ctx, "notificationGossipUpdate(%s) error: %v", key, err)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cursor := r.mu.lastSeen[nodeID]
+	if cursor.epoch != epoch {
+		// The node restarted (or this is the first time we hear from it); its
+		// sequence numbers start over.
+		cursor = remoteNotificationCursor{epoch: epoch}
+	}
+	for _, n := range notifications {
+		if n.seq <= cursor.seq {
+			continue
+		}
+		n.NodeID = nodeID
+		r.deliverLocked(n.Notification)
+		cursor.seq = n.seq
+	}
+	r.mu.lastSeen[nodeID] = cursor
+}
+
+// encodeNotifications encodes the window of a node's recent notifications for
+// gossip.
+func encodeNotifications(epoch uint64, notifications []sequencedNotification) []byte {
+	buf := encoding.EncodeUvarintAscending(nil, epoch)
+	buf = encoding.EncodeUvarintAscending(buf, uint64(len(notifications)))
+	for _, n := range notifications {
+		buf = encoding.EncodeUvarintAscending(buf, n.seq)
+		buf = encoding.EncodeBytesAscending(buf, []byte(n.Channel))
+		buf = encoding.EncodeBytesAscending(buf, []byte(n.Payload))
+	}
+	return buf
+}
+
+// decodeNotifications is the inverse of encodeNotifications.
+func decodeNotifications(buf []byte) (uint64, []sequencedNotification, error) {
+	buf, epoch, err := encoding.DecodeUvarintAscending(buf)
+	if err != nil {
+		return 0, nil, err
+	}
+	buf, count, err := encoding.DecodeUvarintAscending(buf)
+	if err != nil {
+		return 0, nil, err
+	}
+	if count > notificationGossipWindow {
+		return 0, nil, errors.AssertionFailedf("too many notifications: %d", count)
+	}
+	notifications := make([]sequencedNotification, count)
+	for i := range notifications {
+		var channel, payload []byte
+		if buf, notifications[i].seq, err = encoding.DecodeUvarintAscending(buf); err != nil {
+			return 0, nil, err
+		}
+		if buf, channel, err = encoding.DecodeBytesAscending(buf, nil); err != nil {
+			return 0, nil, err
+		}
+		if buf, payload, err = encoding.DecodeBytesAscending(buf, nil); err != nil {
+			return 0, nil, err
+		}
+		notifications[i].Channel = string(channel)
+		notifications[i].Payload = string(payload)
+	}
+	if len(buf) != 0 {
+		return 0, nil, errors.AssertionFailedf("%d trailing bytes", len(buf))
+	}
+	return epoch, notifications, nil
+}
+
+// notificationQueue buffers the notifications received by a session until the
+// session is able to send them to its client.
+type notificationQueue struct {
+	// wake, if set, is called when a notification is added to an empty queue.
+	// It is used to get an idle session to deliver the notification without
+	// waiting for the client's next command. It is called with mu held, so it
+	// must not block.
+	wake func()
+
+	mu struct {
+		syncutil.Mutex
+		pending []Notification
+		// dropped counts the notifications discarded because the queue was
+		// full since the last time the queue was drained.
+		dropped int
+	}
+}
+
+func (q *notificationQueue) push(n Notification) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.mu.pending) >= maxPendingNotifications {
+		q.mu.pending = q.mu.pending[1:]
+		q.mu.dropped++
+	}
+	q.mu.pending = append(q.mu.pending, n)
+	if len(q.mu.pending) == 1 && q.wake != nil {
+		q.wake()
+	}
+}
+
+// drain removes and returns the pending notifications, as well as the number
+// of notifications dropped since the last call.
+func (q *notificationQueue) drain() ([]Notification, int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	pending, dropped := q.mu.pending, q.mu.dropped
+	q.mu.pending, q.mu.dropped = nil, 0
+	return pending, dropped
+}
+
+// sessionNotifications holds the LISTEN/NOTIFY state of a session.
+type sessionNotifications struct {
+	// registry is nil for sessions that cannot listen to notifications (e.g.
+	// internal executors).
+	registry *notificationRegistry
+
+	// queue receives the notifications for the channels the session listens on.
+	queue notificationQueue
+
+	// onNotification, if set, is called to send a notification to the client.
+	onNotification func(Notification)
+
+	// txnPending accumulates the notifications generated by the current
+	// transaction. They are published once the transaction commits, and
+	// discarded if it is rolled back.
+	txnPending []Notification
+
+	// txnListens accumulates the LISTEN and UNLISTEN statements executed by
+	// the current transaction. As in Postgres, they take effect when the
+	// transaction commits, and are discarded if it is rolled back.
+	txnListens []pendingListen
+
+	// savepoint records how much of txnPending and txnListens precedes the
+	// transaction's savepoint. Rolling back to the savepoint discards the
+	// statements executed after it.
+	savepoint notificationsMark
+}
+
+// notificationsMark is a position in the LISTEN, UNLISTEN and NOTIFY
+// statements of a transaction.
+type notificationsMark struct {
+	pending, listens int
+}
+
+// pendingListen is a LISTEN or UNLISTEN waiting for its transaction to commit.
+type pendingListen struct {
+	// channel is empty for UNLISTEN *.
+	channel  string
+	unlisten bool
+}
+
+// listen subscribes the session to the given channel when the current
+// transaction commits.
+func (sn *sessionNotifications) listen(channel string) error {
+	if sn.registry == nil || sn.onNotification == nil {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"LISTEN is not supported in this context")
+	}
+	if err := checkNotificationChannel(channel); err != nil {
+		return err
+	}
+	sn.txnListens = append(sn.txnListens, pendingListen{channel: channel})
+	return nil
+}
+
+// unlisten unsubscribes the session from the given channel, or from all the
+// channels if channel is empty, when the current transaction commits.
+func (sn *sessionNotifications) unlisten(channel string) {
+	if sn.registry == nil {
+		return
+	}
+	sn.txnListens = append(sn.txnListens, pendingListen{channel: channel, unlisten: true})
+}
+
+// notify queues a notification to be published when the current transaction
+// commits. As in Postgres, duplicate notifications within a transaction are
+// folded into one.
+func (sn *sessionNotifications) notify(channel, payload string) error {
+	if err := checkNotificationChannel(channel); err != nil {
+		return err
+	}
+	if len(payload) >= maxNotificationPayloadLength {
+		return pgerror.New(pgcode.InvalidParameterValue, "payload string too long")
+	}
+	n := Notification{Channel: channel, Payload: payload}
+	for i := range sn.txnPending {
+		if sn.txnPending[i] == n {
+			return nil
+		}
+	}
+	sn.txnPending = append(sn.txnPending, n)
+	return nil
+}
+
+// commitTxn applies the LISTEN and UNLISTEN statements of the transaction that
+// just committed and publishes its notifications. The subscriptions are
+// updated first so that, as in Postgres, a transaction that listens on a
+// channel and notifies it receives its own notifications.
+func (sn *sessionNotifications) commitTxn(ctx context.Context) {
+	if sn.registry != nil {
+		for _, l := range sn.txnListens {
+			switch {
+			case !l.unlisten:
+				sn.registry.listen(&sn.queue, l.channel)
+			case l.channel == "":
+				sn.registry.unlistenAll(&sn.queue)
+			default:
+				sn.registry.unlisten(&sn.queue, l.channel)
+			}
+		}
+		sn.registry.publish(ctx, sn.txnPending)
+	}
+	sn.resetTxn()
+}
+
+// resetTxn discards the LISTEN, UNLISTEN and NOTIFY statements of the current
+// transaction.
+func (sn *sessionNotifications) resetTxn() {
+	sn.txnPending = nil
+	sn.txnListens = nil
+	sn.savepoint = notificationsMark{}
+}
+
+// setSavepoint marks the LISTEN, UNLISTEN and NOTIFY statements executed so
+// far by the current transaction as preceding its savepoint.
+func (sn *sessionNotifications) setSavepoint() {
+	sn.savepoint = notificationsMark{pending: len(sn.txnPending), listens: len(sn.txnListens)}
+}
+
+// rollbackToSavepoint discards the LISTEN, UNLISTEN and NOTIFY statements
+// executed by the current transaction since its savepoint, or all of them if
+// there is no savepoint.
+func (sn *sessionNotifications) rollbackToSavepoint() {
+	sn.txnPending = sn.txnPending[:sn.savepoint.pending]
+	sn.txnListens = sn.txnListens[:sn.savepoint.listens]
+}
+
+// deliver sends the notifications received by the session to the client.
+func (sn *sessionNotifications) deliver(ctx context.Context) {
+	if sn.onNotification == nil {
+		return
+	}
+	pending, dropped := sn.queue.drain()
+	if dropped > 0 {
+		log.Warningf(ctx, "dropped %d notifications because the session's queue was full", dropped)
+	}
+	for _, n := range pending {
+		sn.onNotification(n)
+	}
+}
+
+// close unsubscribes the session from all the channels.
+func (sn *sessionNotifications) close() {
+	sn.resetTxn()
+	if sn.registry != nil {
+		sn.registry.unlistenAll(&sn.queue)
+	}
+}
+
+func checkNotificationChannel(channel string) error {
+	if channel == "" {
+		return pgerror.New(pgcode.InvalidParameterValue, "channel name cannot be empty")
+	}
+	if len(channel) > maxNotificationChannelLength {
+		return pgerror.New(pgcode.InvalidParameterValue, "channel name too long")
+	}
+	return nil
+}
+
+// Listen implements the LISTEN statement.
+// See https://www.postgresql.org/docs/current/sql-listen.html for details.
+func (p *planner) Listen(ctx context.Context, n *tree.Listen) (planNode, error) {
+	return &listenNode{n: n}, nil
+}
+
+// Unlisten implements the UNLISTEN statement.
+// See https://www.postgresql.org/docs/current/sql-unlisten.html for details.
+func (p *planner) Unlisten(ctx context.Context, n *tree.Unlisten) (planNode, error) {
+	return &unlistenNode{n: n}, nil
+}
+
+// Notify implements the NOTIFY statement.
+// See https://www.postgresql.org/docs/current/sql-notify.html for details.
+func (p *planner) Notify(ctx context.Context, n *tree.Notify) (planNode, error) {
+	return &notifyNode{n: n}, nil
+}
+
+// The nodes below only record their statement with the session when they are
+// executed; plans are also built for statements that are merely prepared.
+
+type listenNode struct {
+	n *tree.Listen
+}
+
+func (n *listenNode) startExec(params runParams) error {
+	return params.p.extendedEvalCtx.Notifications.listen(string(n.n.Channel))
+}
+
+func (*listenNode) Next(runParams) (bool, error) { return false, nil }
+func (*listenNode) Values() tree.Datums          { return nil }
+func (*listenNode) Close(context.Context)        {}
+
+type unlistenNode struct {
+	n *tree.Unlisten
+}
+
+func (n *unlistenNode) startExec(params runParams) error {
+	params.p.extendedEvalCtx.Notifications.unlisten(string(n.n.Channel))
+	return nil
+}
+
+func (*unlistenNode) Next(runParams) (bool, error) { return false, nil }
+func (*unlistenNode) Values() tree.Datums          { return nil }
+func (*unlistenNode) Close(context.Context)        {}
+
+type notifyNode struct {
+	n *tree.Notify
+}
+
+func (n *notifyNode) startExec(params runParams) error {
+	return params.p.extendedEvalCtx.Notifications.notify(string(n.n.Channel), n.n.Payload)
+}
+
+func (*notifyNode) Next(runParams) (bool, error) { return false, nil }
+func (*notifyNode) Values() tree.Datums          { return nil }
+func (*notifyNode) Close(context.Context)        {}
+
+// NotifyChannel is part of the tree.EvalSessionAccessor interface.
+func (p *planner) NotifyChannel(ctx context.Context, channel, payload string) error {
+	return p.extendedEvalCtx.Notifications.notify(channel, payload)
+}
@@ -0,0 +1,192 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/gossip"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestNotificationEncoding(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	notifications := []sequencedNotification{
+		{seq: 1, Notification: Notification{Channel: "a"}},
+		{seq: 2, Notification: Notification{Channel: "b", Payload: "hello"}},
+		{seq: 7, Notification: Notification{Channel: "a", Payload: "\x00\xff"}},
+	}
+	epoch, decoded, err := decodeNotifications(encodeNotifications(42, notifications))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if epoch != 42 {
+		t.Errorf("expected epoch 42, got %d", epoch)
+	}
+	if !reflect.DeepEqual(notifications, decoded) {
+		t.Errorf("expected %+v, got %+v", notifications, decoded)
+	}
+
+	if _, _, err := decodeNotifications([]byte{0x01}); err == nil {
+		t.Error("expected error decoding truncated notifications")
+	}
+}
+
+func TestNotificationDelivery(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	nodeID := &base.NodeIDContainer{}
+	nodeID.Set(ctx, 1)
+	registry := newNotificationRegistry(nil /* gossip */, nodeID)
+
+	newSession := func() (*sessionNotifications, *[]Notification) {
+		var received []Notification
+		sn := &sessionNotifications{
+			registry:       registry,
+			onNotification: func(n Notification) { received = append(received, n) },
+		}
+		return sn, &received
+	}
+	listener, received := newSession()
+	notifier, _ := newSession()
+
+	// LISTEN only takes effect when the transaction commits.
+	if err := listener.listen("a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := notifier.notify("a", "early"); err != nil {
+		t.Fatal(err)
+	}
+	notifier.commitTxn(ctx)
+	listener.deliver(ctx)
+	if len(*received) != 0 {
+		t.Fatalf("expected no notification before LISTEN committed, got %+v", *received)
+	}
+	listener.commitTxn(ctx)
+
+	// Notifications are only published when the transaction commits and
+	// duplicates are folded.
+	for _, payload := range []string{"x", "x", "y"} {
+		if err := notifier.notify("a", payload); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := notifier.notify("b", "z"); err != nil {
+		t.Fatal(err)
+	}
+	listener.deliver(ctx)
+	if len(*received) != 0 {
+		t.Fatalf("expected no notification before commit, got %+v", *received)
+	}
+	notifier.commitTxn(ctx)
+	listener.deliver(ctx)
+	expected := []Notification{
+		{Channel: "a", Payload: "x", NodeID: 1},
+		{Channel: "a", Payload: "y", NodeID: 1},
+	}
+	if !reflect.DeepEqual(expected, *received) {
+		t.Fatalf("expected %+v, got %+v", expected, *received)
+	}
+
+	// Notifications gossiped by other nodes are delivered once.
+	*received = nil
+	key := gossip.MakeSQLNotificationKey(2)
+	window := []sequencedNotification{
+		{seq: 1, Notification: Notification{Channel: "a", Payload: "remote"}},
+		{seq: 2, Notification: Notification{Channel: "b", Payload: "ignored"}},
+	}
+	registry.notificationGossipUpdate(key, roachpb.MakeValueFromBytes(encodeNotifications(1, window)))
+	registry.notificationGossipUpdate(key, roachpb.MakeValueFromBytes(encodeNotifications(1, window)))
+	listener.deliver(ctx)
+	expected = []Notification{{Channel: "a", Payload: "remote", NodeID: 2}}
+	if !reflect.DeepEqual(expected, *received) {
+		t.Fatalf("expected %+v, got %+v", expected, *received)
+	}
+
+	// Rolled back notifications and UNLISTENs are discarded.
+	*received = nil
+	listener.unlisten("" /* channel */)
+	listener.resetTxn()
+	if err := notifier.notify("a", "rolled back"); err != nil {
+		t.Fatal(err)
+	}
+	notifier.resetTxn()
+	if err := notifier.notify("a", "x"); err != nil {
+		t.Fatal(err)
+	}
+	notifier.commitTxn(ctx)
+	listener.deliver(ctx)
+	expected = []Notification{{Channel: "a", Payload: "x", NodeID: 1}}
+	if !reflect.DeepEqual(expected, *received) {
+		t.Fatalf("expected %+v, got %+v", expected, *received)
+	}
+
+	// Rolling back to the savepoint discards the notifications and UNLISTENs
+	// since the savepoint, but keeps the ones before it.
+	*received = nil
+	if err := notifier.notify("a", "before savepoint"); err != nil {
+		t.Fatal(err)
+	}
+	notifier.setSavepoint()
+	if err := notifier.notify("a", "after savepoint"); err != nil {
+		t.Fatal(err)
+	}
+	notifier.rollbackToSavepoint()
+	listener.setSavepoint()
+	listener.unlisten("a")
+	listener.rollbackToSavepoint()
+	listener.commitTxn(ctx)
+	notifier.commitTxn(ctx)
+	listener.deliver(ctx)
+	expected = []Notification{{Channel: "a", Payload: "before savepoint", NodeID: 1}}
+	if !reflect.DeepEqual(expected, *received) {
+		t.Fatalf("expected %+v, got %+v", expected, *received)
+	}
+
+	// Nothing is delivered after UNLISTEN * commits.
+	*received = nil
+	listener.unlisten("" /* channel */)
+	listener.commitTxn(ctx)
+	if err := notifier.notify("a", "x"); err != nil {
+		t.Fatal(err)
+	}
+	notifier.commitTxn(ctx)
+	listener.deliver(ctx)
+	if len(*received) != 0 {
+		t.Fatalf("expected no notification after UNLISTEN, got %+v", *received)
+	}
+}
+
+func TestNotificationErrors(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	var sn sessionNotifications
+	if err := sn.listen("a"); !testutils.IsError(err, "LISTEN is not supported in this context") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := sn.notify("", ""); !testutils.IsError(err, "channel name cannot be empty") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := sn.notify(strings.Repeat("a", 64), ""); !testutils.IsError(err, "channel name too long") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := sn.notify("a", strings.Repeat("a", 8000)); !testutils.IsError(err, "payload string too long") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -73,6 +73,12 @@ func buildOpaque(
 		plan, err = p.CreateSequence(ctx, n)
 	case *tree.CreateStats:
 		plan, err = p.CreateStatistics(ctx, n)
+	case *tree.CreateStatementHints:
+		plan, err = p.CreateStatementHints(ctx, n)
+	case *tree.CloseCursor:
+		plan, err = p.CloseCursor(ctx, n)
+	case *tree.DeclareCursor:
+		plan, err = p.DeclareCursor(ctx, n)
 	case *tree.Deallocate:
 		plan, err = p.Deallocate(ctx, n)
 	case *tree.Discard:
@@ -87,10 +93,20 @@ func buildOpaque(
 		plan, err = p.DropView(ctx, n)
 	case *tree.DropSequence:
 		plan, err = p.DropSequence(ctx, n)
+	case *tree.DropStatementHints:
+		plan, err = p.DropStatementHints(ctx, n)
 	case *tree.DropUser:
 		plan, err = p.DropUser(ctx, n)
 	case *tree.Grant:
 		plan, err = p.Grant(ctx, n)
+	case *tree.FetchCursor:
+		plan, err = p.FetchCursor(ctx, n)
+	case *tree.Listen:
+		plan, err = p.Listen(ctx, n)
+	case *tree.MoveCursor:
+		plan, err = p.MoveCursor(ctx, n)
+	case *tree.Notify:
+		plan, err = p.Notify(ctx, n)
 	case *tree.RenameColumn:
 		plan, err = p.RenameColumn(ctx, n)
 	case *tree.RenameDatabase:
@@ -131,6 +147,8 @@ func buildOpaque(
 		plan, err = p.ShowFingerprints(ctx, n)
 	case *tree.Truncate:
 		plan, err = p.Truncate(ctx, n)
+	case *tree.Unlisten:
+		plan, err = p.Unlisten(ctx, n)
 	case tree.CCLOnlyStatement:
 		plan, err = p.maybePlanHook(ctx, stmt)
 		if plan == nil && err == nil {
@@ -165,6 +183,9 @@ func init() {
 		&tree.CreateUser{},
 		&tree.CreateSequence{},
 		&tree.CreateStats{},
+		&tree.CreateStatementHints{},
+		&tree.CloseCursor{},
+		&tree.DeclareCursor{},
 		&tree.Deallocate{},
 		&tree.Discard{},
 		&tree.DropDatabase{},
@@ -172,8 +193,13 @@ func init() {
 		&tree.DropTable{},
 		&tree.DropView{},
 		&tree.DropSequence{},
+		&tree.DropStatementHints{},
 		&tree.DropUser{},
 		&tree.Grant{},
+		&tree.FetchCursor{},
+		&tree.Listen{},
+		&tree.MoveCursor{},
+		&tree.Notify{},
 		&tree.RenameColumn{},
 		&tree.RenameDatabase{},
 		&tree.RenameIndex{},
@@ -194,6 +220,7 @@ func init() {
 		&tree.ShowZoneConfig{},
 		&tree.ShowFingerprints{},
 		&tree.Truncate{},
+		&tree.Unlisten{},
 
 		// CCL statements (without Export which has an optimizer operator).
 		&tree.Backup{},
@@ -334,6 +334,14 @@ type harness struct {
 	cat       *testcat.Catalog
 	optimizer xform.Optimizer
 
+	// schemas are the CREATE TABLE statements used to set up the database.
+	schemas []string
+
+	// reorderJoinsLimit and greedyJoinReorder set the corresponding session
+	// settings when the query is planned using the API.
+	reorderJoinsLimit int
+	greedyJoinReorder bool
+
 	s  serverutils.TestServerInterface
 	db *gosql.DB
 	sr *sqlutils.SQLRunner
@@ -345,7 +353,7 @@ type harness struct {
 }
 
 func newHarness() *harness {
-	return &harness{}
+	return &harness{schemas: schemas[:]}
 }
 
 func (h *harness) close() {
@@ -430,7 +438,7 @@ func (h *harness) prepareUsingServer(tb testing.TB) {
 		h.s, h.db, _ = serverutils.StartServer(tb, base.TestServerArgs{UseDatabase: "bench"})
 		h.sr = sqlutils.MakeSQLRunner(h.db)
 		h.sr.Exec(tb, `CREATE DATABASE bench`)
-		for _, schema := range schemas {
+		for _, schema := range h.schemas {
 			h.sr.Exec(tb, schema)
 		}
 		h.ready = true
@@ -464,13 +472,15 @@ func (h *harness) prepareUsingAPI(tb testing.TB) {
 	h.ctx = context.Background()
 	h.semaCtx = tree.MakeSemaContext()
 	h.evalCtx = tree.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+	h.evalCtx.SessionData.ReorderJoinsLimit = h.reorderJoinsLimit
+	h.evalCtx.SessionData.GreedyJoinReorderEnabled = h.greedyJoinReorder
 	h.prepMemo = nil
 	h.cat = nil
 	h.optimizer = xform.Optimizer{}
 
 	// Set up the catalog.
 	h.cat = testcat.New()
-	for _, schema := range schemas {
+	for _, schema := range h.schemas {
 		_, err := h.cat.ExecuteDDL(schema)
 		if err != nil {
 			tb.Fatalf("%v", err)
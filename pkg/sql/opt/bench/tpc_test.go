@@ -0,0 +1,93 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package bench
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/workload"
+	"github.com/cockroachdb/cockroach/pkg/workload/tpcds"
+	"github.com/cockroachdb/cockroach/pkg/workload/tpch"
+)
+
+// BenchmarkTPCH measures the time to plan the TPC-H queries, with and without
+// greedy join reordering.
+func BenchmarkTPCH(b *testing.B) {
+	var queries []benchQuery
+	for name, query := range tpch.QueriesByName {
+		queries = append(queries, benchQuery{name: "tpch-" + name, query: query})
+	}
+	runTPCBenchmark(b, "tpch", queries)
+}
+
+// BenchmarkTPCDS measures the time to plan the TPC-DS queries, with and
+// without greedy join reordering. Many of these queries join more tables than
+// the default reorder_joins_limit.
+func BenchmarkTPCDS(b *testing.B) {
+	var queries []benchQuery
+	for num, query := range tpcds.QueriesByNumber {
+		queries = append(queries, benchQuery{name: fmt.Sprintf("tpcds-%d", num), query: query})
+	}
+	runTPCBenchmark(b, "tpcds", queries)
+}
+
+// runTPCBenchmark runs the Explore and ExecBuild benchmarks for the given
+// queries against the schema of the given workload. Queries that consist of
+// multiple statements (like TPC-H query 15, which creates a view) are skipped.
+func runTPCBenchmark(b *testing.B, workloadName string, queries []benchQuery) {
+	meta, err := workload.Get(workloadName)
+	if err != nil {
+		b.Fatal(err)
+	}
+	var tpcSchemas []string
+	for _, table := range meta.New().Tables() {
+		tpcSchemas = append(tpcSchemas, fmt.Sprintf("CREATE TABLE %s %s", table.Name, table.Schema))
+	}
+
+	sort.Slice(queries, func(i, j int) bool {
+		return queryNumber(queries[i].name) < queryNumber(queries[j].name)
+	})
+
+	for _, greedy := range []bool{false, true} {
+		h := newHarness()
+		h.schemas = tpcSchemas
+		h.reorderJoinsLimit = opt.DefaultJoinOrderLimit
+		h.greedyJoinReorder = greedy
+
+		b.Run(fmt.Sprintf("greedy=%t", greedy), func(b *testing.B) {
+			for _, query := range queries {
+				if stmts, err := parser.Parse(query.query); err != nil {
+					b.Fatal(err)
+				} else if len(stmts) != 1 {
+					continue
+				}
+				h.runForBenchmark(b, Explore, query)
+				h.runForBenchmark(b, ExecBuild, query)
+			}
+		})
+		h.close()
+	}
+}
+
+// queryNumber returns the number at the end of a query name like "tpch-12".
+func queryNumber(name string) int {
+	i := len(name)
+	for i > 0 && name[i-1] >= '0' && name[i-1] <= '9' {
+		i--
+	}
+	n, _ := strconv.Atoi(name[i:])
+	return n
+}
@@ -127,6 +127,8 @@ func parseDatumPath(evalCtx *tree.EvalContext, str string, typ types.Family) []t
 			val, err = tree.ParseDTimestampTZ(evalCtx, valStr, time.Microsecond)
 		case types.StringFamily:
 			val = tree.NewDString(valStr)
+		case types.UuidFamily:
+			val, err = tree.ParseDUuidFromString(valStr)
 		default:
 			panic(errors.AssertionFailedf("type %s not supported", typ.String()))
 		}
@@ -51,6 +51,7 @@ func init() {
 		opt.AnyOp:             (*Builder).buildAny,
 		opt.AnyScalarOp:       (*Builder).buildAnyScalar,
 		opt.IndirectionOp:     (*Builder).buildIndirection,
+		opt.ArraySliceOp:      (*Builder).buildArraySlice,
 		opt.CollateOp:         (*Builder).buildCollate,
 		opt.ArrayFlattenOp:    (*Builder).buildArrayFlatten,
 		opt.IfErrOp:           (*Builder).buildIfErr,
@@ -405,6 +406,26 @@ func (b *Builder) buildIndirection(
 	return tree.NewTypedIndirectionExpr(expr, index, scalar.DataType()), nil
 }
 
+func (b *Builder) buildArraySlice(
+	ctx *buildScalarCtx, scalar opt.ScalarExpr,
+) (tree.TypedExpr, error) {
+	slice := scalar.(*memo.ArraySliceExpr)
+	expr, err := b.buildScalar(ctx, slice.Input)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := make([]tree.TypedExpr, len(slice.Bounds))
+	for i := range slice.Bounds {
+		bounds[i], err = b.buildScalar(ctx, slice.Bounds[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tree.NewTypedArraySliceExpr(expr, bounds, scalar.DataType()), nil
+}
+
 func (b *Builder) buildCollate(ctx *buildScalarCtx, scalar opt.ScalarExpr) (tree.TypedExpr, error) {
 	expr, err := b.buildScalar(ctx, scalar.Child(0).(opt.ScalarExpr))
 	if err != nil {
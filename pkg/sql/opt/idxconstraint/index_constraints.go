@@ -13,6 +13,7 @@ package idxconstraint
 import (
 	"context"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
@@ -20,9 +21,11 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/norm"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/errors"
 )
 
@@ -756,6 +759,85 @@ func (c *indexConstraintCtx) makeSpansForOr(
 	return tight
 }
 
+// makeInvertedIndexSpansForArrayContains is a helper for
+// makeInvertedIndexSpansForExpr that constrains an inverted index on an array
+// column with the elements of the constant array on the right side of a @>
+// operator. An array contains all of them only if the index has a key for each
+// one, so every element yields a constraint.
+func (c *indexConstraintCtx) makeInvertedIndexSpansForArrayContains(
+	rightArray *tree.DArray, constraints []*constraint.Constraint, allPaths bool,
+) (bool, []*constraint.Constraint) {
+	out := &constraint.Constraint{}
+	if rightArray.ParamTyp.Family() != types.ArrayFamily && rightArray.HasNulls {
+		// No array contains a NULL element.
+		c.contradiction(0 /* offset */, out)
+		return false, append(constraints, out)
+	}
+	elems := c.invertedIndexArrayElements(rightArray)
+	if len(elems) == 0 {
+		// Every array contains the empty array.
+		c.unconstrained(0 /* offset */, out)
+		return false, append(constraints, out)
+	}
+	tight := invertedIndexArraySpansAreTight(rightArray, elems)
+	for i := range elems {
+		if i > 0 {
+			out = &constraint.Constraint{}
+		}
+		c.eqSpan(0 /* offset */, invertedIndexArrayKey(elems[i]), out)
+		constraints = append(constraints, out)
+		if !allPaths {
+			break
+		}
+	}
+	return tight, constraints
+}
+
+// invertedIndexArrayElements returns the distinct, non-NULL elements of the
+// array in sorted order. The elements of multidimensional arrays are returned
+// individually, since that is how they are stored in an inverted index (see
+// sqlbase.EncodeInvertedIndexTableKeys).
+func (c *indexConstraintCtx) invertedIndexArrayElements(a *tree.DArray) tree.Datums {
+	flattened, _ := a.Flatten()
+	elems := make(tree.Datums, 0, len(flattened))
+	for _, elem := range flattened {
+		if elem != tree.DNull {
+			elems = append(elems, elem)
+		}
+	}
+	sort.Slice(elems, func(i, j int) bool {
+		return elems[i].Compare(c.evalCtx, elems[j]) < 0
+	})
+	n := 0
+	for i := range elems {
+		if i == 0 || elems[i].Compare(c.evalCtx, elems[n-1]) != 0 {
+			elems[n] = elems[i]
+			n++
+		}
+	}
+	return elems[:n]
+}
+
+// invertedIndexArraySpansAreTight returns true if a span over the single
+// element of an array exactly matches the arrays that contain (or overlap
+// with) it. This is not the case for multidimensional arrays, which are
+// compared one sub-array at a time, nor for types with composite key
+// encodings, whose distinct values can map to the same key.
+func invertedIndexArraySpansAreTight(a *tree.DArray, elems tree.Datums) bool {
+	return len(elems) == 1 &&
+		a.ParamTyp.Family() != types.ArrayFamily &&
+		!sqlbase.DatumTypeHasCompositeKeyEncoding(a.ParamTyp)
+}
+
+// invertedIndexArrayKey returns the datum used in the spans of an inverted
+// index on an array column for the given element. It is a single-element
+// array, which sqlbase.EncodeInvertedIndexTableKeys encodes as a single key.
+func invertedIndexArrayKey(elem tree.Datum) tree.Datum {
+	a := tree.NewDArray(elem.ResolvedType())
+	a.Array = tree.Datums{elem}
+	return a
+}
+
 // makeInvertedIndexSpansForExpr is analogous to makeSpansForExpr, but it is
 // used for inverted indexes. If allPaths is true, the slice is populated with
 // all constraints found. Otherwise, this function stops at the first
@@ -787,6 +869,10 @@ func (c *indexConstraintCtx) makeInvertedIndexSpansForExpr(
 			return false, append(constraints, out)
 		}
 
+		if rightArray, ok := rightDatum.(*tree.DArray); ok {
+			return c.makeInvertedIndexSpansForArrayContains(rightArray, constraints, allPaths)
+		}
+
 		rd := rightDatum.(*tree.DJSON).JSON
 
 		switch rd.Type() {
@@ -857,6 +943,79 @@ func (c *indexConstraintCtx) makeInvertedIndexSpansForExpr(
 			return true, append(constraints, out)
 		}
 
+	case opt.OverlapsOp:
+		lhs, rhs := nd.Child(0), nd.Child(1)
+
+		// The && operator is commutative, so the index column can be on either
+		// side.
+		if !c.isIndexColumn(lhs, 0 /* index */) {
+			lhs, rhs = rhs, lhs
+		}
+		if !c.isIndexColumn(lhs, 0 /* index */) || !opt.IsConstValueOp(rhs) {
+			c.unconstrained(0 /* offset */, out)
+			return false, append(constraints, out)
+		}
+
+		rightDatum := memo.ExtractConstDatum(rhs)
+
+		if rightDatum == tree.DNull {
+			c.contradiction(0 /* offset */, out)
+			return false, append(constraints, out)
+		}
+
+		rightArray, ok := rightDatum.(*tree.DArray)
+		if !ok {
+			c.unconstrained(0 /* offset */, out)
+			return false, append(constraints, out)
+		}
+
+		// An array overlaps with the constant if it contains any of its
+		// elements. We only constrain the scan when there is exactly one of them,
+		// since the spans for several elements would overlap when mapped onto the
+		// primary key space and return duplicate rows.
+		elems := c.invertedIndexArrayElements(rightArray)
+		switch len(elems) {
+		case 0:
+			// NULL elements never overlap.
+			c.contradiction(0 /* offset */, out)
+			return false, append(constraints, out)
+		case 1:
+			c.eqSpan(0 /* offset */, invertedIndexArrayKey(elems[0]), out)
+			return invertedIndexArraySpansAreTight(rightArray, elems), append(constraints, out)
+		default:
+			c.unconstrained(0 /* offset */, out)
+			return false, append(constraints, out)
+		}
+
+	case opt.TSMatchesOp:
+		lhs, rhs := nd.Child(0), nd.Child(1)
+
+		if !c.isIndexColumn(lhs, 0 /* index */) || !opt.IsConstValueOp(rhs) {
+			c.unconstrained(0 /* offset */, out)
+			return false, append(constraints, out)
+		}
+
+		rightDatum := memo.ExtractConstDatum(rhs)
+
+		if rightDatum == tree.DNull {
+			c.contradiction(0 /* offset */, out)
+			return false, append(constraints, out)
+		}
+
+		// Every matching document must contain at least one of the required
+		// lexemes. We only constrain the scan when there is exactly one of them,
+		// since the spans for several lexemes would overlap when mapped onto the
+		// primary key space and return duplicate rows. The spans are never tight,
+		// because the index does not store positions or weights.
+		lexemes, ok := rightDatum.(*tree.DTSQuery).RequiredLexemes()
+		if !ok || len(lexemes) != 1 {
+			c.unconstrained(0 /* offset */, out)
+			return false, append(constraints, out)
+		}
+		lexeme := tree.NewDTSVector(tsearch.TSVector{{Text: lexemes[0]}})
+		c.eqSpan(0 /* offset */, lexeme, out)
+		return false, append(constraints, out)
+
 	case opt.AndOp, opt.FiltersOp:
 		for i, n := 0, nd.ChildCount(); i < n; i++ {
 			tight, constraints = c.makeInvertedIndexSpansForExpr(
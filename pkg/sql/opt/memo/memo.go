@@ -132,13 +132,19 @@ type Memo struct {
 
 	// The following are selected fields from SessionData which can affect
 	// planning. We need to cross-check these before reusing a cached memo.
-	dataConversion    sessiondata.DataConversionConfig
-	reorderJoinsLimit int
-	zigzagJoinEnabled bool
-	optimizerFKs      bool
-	safeUpdates       bool
-	saveTablesPrefix  string
-	insertFastPath    bool
+	dataConversion           sessiondata.DataConversionConfig
+	reorderJoinsLimit        int
+	zigzagJoinEnabled        bool
+	greedyJoinReorderEnabled bool
+	optimizerFKs             bool
+	safeUpdates              bool
+	saveTablesPrefix         string
+	insertFastPath           bool
+
+	// statementHints are the hints bound to the statement's fingerprint with
+	// CREATE STATEMENT HINTS, if any. They are applied while the memo is built,
+	// so the caller must cross-check them before reusing a cached memo.
+	statementHints *tree.StatementHints
 
 	// curID is the highest currently in-use scalar expression ID.
 	curID opt.ScalarID
@@ -168,10 +174,12 @@ func (m *Memo) Init(evalCtx *tree.EvalContext) {
 	m.dataConversion = evalCtx.SessionData.DataConversion
 	m.reorderJoinsLimit = evalCtx.SessionData.ReorderJoinsLimit
 	m.zigzagJoinEnabled = evalCtx.SessionData.ZigzagJoinEnabled
+	m.greedyJoinReorderEnabled = evalCtx.SessionData.GreedyJoinReorderEnabled
 	m.optimizerFKs = evalCtx.SessionData.OptimizerFKs
 	m.safeUpdates = evalCtx.SessionData.SafeUpdates
 	m.saveTablesPrefix = evalCtx.SessionData.SaveTablesPrefix
 	m.insertFastPath = evalCtx.SessionData.InsertFastPath
+	m.statementHints = nil
 
 	m.curID = 0
 	m.curWithID = 0
@@ -275,6 +283,7 @@ func (m *Memo) IsStale(
 	if !m.dataConversion.Equals(&evalCtx.SessionData.DataConversion) ||
 		m.reorderJoinsLimit != evalCtx.SessionData.ReorderJoinsLimit ||
 		m.zigzagJoinEnabled != evalCtx.SessionData.ZigzagJoinEnabled ||
+		m.greedyJoinReorderEnabled != evalCtx.SessionData.GreedyJoinReorderEnabled ||
 		m.optimizerFKs != evalCtx.SessionData.OptimizerFKs ||
 		m.safeUpdates != evalCtx.SessionData.SafeUpdates ||
 		m.saveTablesPrefix != evalCtx.SessionData.SaveTablesPrefix ||
@@ -293,6 +302,18 @@ func (m *Memo) IsStale(
 	return false, nil
 }
 
+// SetStatementHints records the statement hints that are applied while
+// building and optimizing the memo.
+func (m *Memo) SetStatementHints(hints *tree.StatementHints) {
+	m.statementHints = hints
+}
+
+// StatementHints returns the statement hints that were applied while building
+// and optimizing the memo, or nil if there are none.
+func (m *Memo) StatementHints() *tree.StatementHints {
+	return m.statementHints
+}
+
 // InternPhysicalProps adds the given physical props to the memo if they haven't
 // yet been added. If the same props was added previously, then return a pointer
 // to the previously added props. This allows interned physical props to be
@@ -167,6 +167,12 @@ func TestMemoIsStale(t *testing.T) {
 	evalCtx.SessionData.ZigzagJoinEnabled = false
 	notStale()
 
+	// Stale greedy join reorder enable.
+	evalCtx.SessionData.GreedyJoinReorderEnabled = true
+	stale()
+	evalCtx.SessionData.GreedyJoinReorderEnabled = false
+	notStale()
+
 	// Stale optimizer FK planning enable.
 	evalCtx.SessionData.OptimizerFKs = true
 	stale()
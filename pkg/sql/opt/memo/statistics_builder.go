@@ -13,12 +13,14 @@ package memo
 import (
 	"math"
 	"reflect"
+	"sort"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/constraint"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
@@ -26,6 +28,11 @@ import (
 
 var statsAnnID = opt.NewTableAnnID()
 
+// multiColStatsAnnID identifies the table annotation that stores the column
+// sets of the multi-column statistics available for a table (as a slice of
+// opt.ColSet).
+var multiColStatsAnnID = opt.NewTableAnnID()
+
 // statisticsBuilder is responsible for building the statistics that are
 // used by the coster to estimate the cost of expressions.
 //
@@ -462,13 +469,29 @@ func (sb *statisticsBuilder) colStatLeaf(
 	} else {
 		distinctCount := 1.0
 		nullCount := 0.0
-		colSet.ForEach(func(i opt.ColumnID) {
-			colStatLeaf := sb.colStatLeaf(opt.MakeColSet(i), s, fd, notNullCols)
+		addColStat := func(colStatLeaf *props.ColumnStatistic) {
 			distinctCount *= colStatLeaf.DistinctCount
 			if nullCount < s.RowCount {
 				// Subtract the expected chance of collisions with nulls already collected.
 				nullCount += colStatLeaf.NullCount * (1 - nullCount/s.RowCount)
 			}
+		}
+		// If there are multi-column statistics on subsets of the columns, use
+		// them (largest first) in order to account for the correlation between
+		// the columns in each subset. The remaining columns are assumed to be
+		// independent.
+		remaining := colSet
+		for _, multiCols := range sb.multiColStatsInSet(colSet) {
+			if multiCols.Equals(colSet) || !multiCols.SubsetOf(remaining) {
+				continue
+			}
+			if multiColStat, ok := s.ColStats.Lookup(multiCols); ok {
+				addColStat(multiColStat)
+				remaining = remaining.Difference(multiCols)
+			}
+		}
+		remaining.ForEach(func(i opt.ColumnID) {
+			addColStat(sb.colStatLeaf(opt.MakeColSet(i), s, fd, notNullCols))
 		})
 		// Fetch the colStat again since it may now have a different address.
 		colStat, _ = s.ColStats.Lookup(colSet)
@@ -497,6 +520,7 @@ func (sb *statisticsBuilder) makeTableStatistics(tabID opt.TableID) *props.Stati
 	// Make now and annotate the metadata table with it for next time.
 	tab := sb.md.Table(tabID)
 	stats = &props.Statistics{}
+	var multiColStats []opt.ColSet
 	if tab.StatisticCount() == 0 {
 		// No statistics.
 		stats.Available = false
@@ -520,6 +544,9 @@ func (sb *statisticsBuilder) makeTableStatistics(tabID opt.TableID) *props.Stati
 				cols.Add(tabID.ColumnID(stat.ColumnOrdinal(i)))
 			}
 			if colStat, ok := stats.ColStats.Add(cols); ok {
+				if cols.Len() > 1 {
+					multiColStats = append(multiColStats, cols)
+				}
 				colStat.DistinctCount = float64(stat.DistinctCount())
 				colStat.NullCount = float64(stat.NullCount())
 				if cols.Len() == 1 && stat.Histogram() != nil {
@@ -540,9 +567,41 @@ func (sb *statisticsBuilder) makeTableStatistics(tabID opt.TableID) *props.Stati
 		}
 	}
 	sb.md.SetTableAnnotation(tabID, statsAnnID, stats)
+	sb.md.SetTableAnnotation(tabID, multiColStatsAnnID, multiColStats)
 	return stats
 }
 
+// multiColStatsInSet returns the column sets of all multi-column table
+// statistics that are a subset of the given column set, ordered by decreasing
+// number of columns. Only the statistics of the tables that the columns
+// belong to are considered.
+func (sb *statisticsBuilder) multiColStatsInSet(cols opt.ColSet) []opt.ColSet {
+	if cols.Len() < 2 {
+		return nil
+	}
+	var tables util.FastIntSet
+	cols.ForEach(func(col opt.ColumnID) {
+		if tabID := sb.md.ColumnMeta(col).Table; tabID != 0 {
+			tables.Add(int(tabID))
+		}
+	})
+	var res []opt.ColSet
+	tables.ForEach(func(t int) {
+		tabID := opt.TableID(t)
+		sb.makeTableStatistics(tabID)
+		multiColStats, _ := sb.md.TableAnnotation(tabID, multiColStatsAnnID).([]opt.ColSet)
+		for _, multiCols := range multiColStats {
+			if multiCols.SubsetOf(cols) {
+				res = append(res, multiCols)
+			}
+		}
+	})
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Len() > res[j].Len()
+	})
+	return res
+}
+
 func (sb *statisticsBuilder) colStatTable(
 	tabID opt.TableID, colSet opt.ColSet,
 ) *props.ColumnStatistic {
@@ -2966,12 +3025,28 @@ func (sb *statisticsBuilder) updateDistinctNullCountsFromEquivalency(
 // This selectivity will be used later to update the row count and the
 // distinct count for the unconstrained columns.
 //
-// This algorithm assumes the columns are completely independent.
+// This algorithm assumes the columns are completely independent, unless there
+// are multi-column statistics available on some of the constrained columns. In
+// that case, the selectivity of each such set of columns is calculated as:
+//
+//                  new distinct(cols)
+//   selectivity = --------------------
+//                  old distinct(cols)
+//
+// where the new distinct count is the product of the new distinct counts of
+// the individual columns, and the old distinct count comes from the
+// multi-column statistic. The result is bounded below by the selectivity that
+// assumes independence and above by the smallest selectivity of the individual
+// columns in the set.
 //
 func (sb *statisticsBuilder) selectivityFromDistinctCounts(
 	cols opt.ColSet, e RelExpr, s *props.Statistics,
 ) (selectivity float64) {
-	selectivity = 1.0
+	var (
+		selectivityCols opt.ColSet
+		colSelectivity  map[opt.ColumnID]float64
+		colNewDistinct  map[opt.ColumnID]float64
+	)
 	for col, ok := cols.Next(0); ok; col, ok = cols.Next(col + 1) {
 		colStat, ok := s.ColStats.Lookup(opt.MakeColSet(col))
 		if !ok {
@@ -2994,10 +3069,41 @@ func (sb *statisticsBuilder) selectivityFromDistinctCounts(
 		// Calculate the selectivity of the predicate.
 		nonNullSelectivity := fraction(newDistinct, oldDistinct)
 		nullSelectivity := fraction(colStat.NullCount, inputColStat.NullCount)
-		selectivity *= sb.predicateSelectivity(
+		if colSelectivity == nil {
+			colSelectivity = make(map[opt.ColumnID]float64)
+			colNewDistinct = make(map[opt.ColumnID]float64)
+		}
+		selectivityCols.Add(col)
+		colSelectivity[col] = sb.predicateSelectivity(
 			nonNullSelectivity, nullSelectivity, inputColStat.NullCount, inputStats.RowCount,
 		)
+		colNewDistinct[col] = newDistinct
+	}
+
+	selectivity = 1.0
+	remaining := selectivityCols
+	for _, multiCols := range sb.multiColStatsInSet(selectivityCols) {
+		if !multiCols.SubsetOf(remaining) {
+			continue
+		}
+		inputColStat, _ := sb.colStatFromInput(multiCols, e)
+		oldDistinct := inputColStat.DistinctCount
+		if inputColStat.NullCount > 0 {
+			oldDistinct = max(oldDistinct-1, 0)
+		}
+		newDistinct, independentSelectivity, minSelectivity := 1.0, 1.0, 1.0
+		multiCols.ForEach(func(col opt.ColumnID) {
+			newDistinct *= colNewDistinct[col]
+			independentSelectivity *= colSelectivity[col]
+			minSelectivity = min(minSelectivity, colSelectivity[col])
+		})
+		multiColSelectivity := min(fraction(newDistinct, oldDistinct), minSelectivity)
+		selectivity *= max(multiColSelectivity, independentSelectivity)
+		remaining = remaining.Difference(multiCols)
 	}
+	remaining.ForEach(func(col opt.ColumnID) {
+		selectivity *= colSelectivity[col]
+	})
 
 	return selectivity
 }
@@ -244,6 +244,7 @@ func (f *Factory) AssignPlaceholders(from *memo.Memo) (err error) {
 		return f.CopyAndReplaceDefault(e, replaceFn)
 	}
 	f.CopyAndReplace(from.RootExpr().(memo.RelExpr), from.RootProps(), replaceFn)
+	f.mem.SetStatementHints(from.StatementHints())
 
 	return nil
 }
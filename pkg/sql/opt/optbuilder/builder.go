@@ -13,6 +13,7 @@ package optbuilder
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/delegate"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
@@ -21,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
@@ -70,6 +72,17 @@ type Builder struct {
 	// This is used when re-preparing invalidated queries.
 	KeepPlaceholders bool
 
+	// StatementHints is a control knob: if set, the hints are applied to the
+	// data sources and joins of the statement that don't have inline hints.
+	// These are the hints bound to the statement fingerprint with CREATE
+	// STATEMENT HINTS.
+	StatementHints *tree.StatementHints
+
+	// ValidateStatementHints is a control knob: if set, Build returns an error
+	// if any of the StatementHints don't apply to the statement, instead of
+	// ignoring them. It is used when the hints are created.
+	ValidateStatementHints bool
+
 	// -- Results --
 	//
 	// These fields are set during the building process and can be used after
@@ -131,6 +144,10 @@ type Builder struct {
 	// isCorrelated is set to true if we already reported to telemetry that the
 	// query contains a correlated subquery.
 	isCorrelated bool
+
+	// statementHintTables tracks the data sources that StatementHints apply to
+	// (see statement_hints.go).
+	statementHintTables statementHintTables
 }
 
 // New creates a new Builder structure initialized with the given
@@ -181,6 +198,11 @@ func (b *Builder) Build() (err error) {
 		return err
 	}
 
+	if b.StatementHints != nil {
+		telemetry.Inc(sqltelemetry.StatementHintsUseCounter)
+		b.factory.Memo().SetStatementHints(b.StatementHints)
+	}
+
 	b.pushWithFrame()
 
 	// Build the memo, and call SetRoot on the memo to indicate the root group
@@ -194,7 +216,7 @@ func (b *Builder) Build() (err error) {
 
 	physical := outScope.makePhysicalProps()
 	b.factory.Memo().SetRoot(outScope.expr, physical)
-	return nil
+	return b.checkStatementHints()
 }
 
 // unimplementedWithIssueDetailf formats according to a format
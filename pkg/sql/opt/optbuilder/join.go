@@ -76,6 +76,9 @@ func (b *Builder) buildJoin(
 			pgcode.FeatureNotSupported, "join hint %s not supported", join.Hint,
 		))
 	}
+	if join.Hint == "" {
+		flags = b.statementHintJoinFlags(join.Right, joinType)
+	}
 
 	switch cond := join.Cond.(type) {
 	case tree.NaturalJoinCond, *tree.UsingJoinCond:
@@ -13,6 +13,7 @@ package optbuilder
 import (
 	"context"
 	"fmt"
+	"math"
 
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
@@ -167,20 +168,21 @@ func (b *Builder) buildScalar(
 	case *tree.IndirectionExpr:
 		expr := b.buildScalar(t.Expr.(tree.TypedExpr), inScope, nil, nil, colRefs)
 
-		if len(t.Indirection) != 1 {
-			panic(unimplementedWithIssueDetailf(32552, "ind", "multidimensional indexing is not supported"))
+		if isArraySlice(t) {
+			out = b.factory.ConstructArraySlice(expr, b.buildArraySliceBounds(t, inScope, colRefs))
+			break
 		}
 
-		subscript := t.Indirection[0]
-		if subscript.Slice {
-			panic(unimplementedWithIssueDetailf(32551, "", "array slicing is not supported"))
+		// Each subscript removes one dimension of the array, so a[i][j] is built
+		// as (a[i])[j].
+		out = expr
+		for _, subscript := range t.Indirection {
+			out = b.factory.ConstructIndirection(
+				out,
+				b.buildScalar(subscript.Begin.(tree.TypedExpr), inScope, nil, nil, colRefs),
+			)
 		}
 
-		out = b.factory.ConstructIndirection(
-			expr,
-			b.buildScalar(subscript.Begin.(tree.TypedExpr), inScope, nil, nil, colRefs),
-		)
-
 	case *tree.IfErrExpr:
 		cond := b.buildScalar(t.Cond.(tree.TypedExpr), inScope, nil, nil, colRefs)
 
@@ -411,6 +413,45 @@ func (b *Builder) buildScalar(
 	return b.finishBuildScalar(scalar, out, inScope, outScope, outCol)
 }
 
+// isArraySlice returns true if any of the subscripts of the given indirection
+// expression is a slice, in which case all of them are treated as slices.
+func isArraySlice(t *tree.IndirectionExpr) bool {
+	for _, subscript := range t.Indirection {
+		if subscript.Slice {
+			return true
+		}
+	}
+	return false
+}
+
+// buildArraySliceBounds builds the lower and upper bound of each of the
+// subscripts of an array slicing expression. A subscript without a colon, such
+// as [2], is equivalent to [1:2]. Omitted bounds are replaced by bounds that
+// are clamped to the bounds of the array when the slice is evaluated.
+func (b *Builder) buildArraySliceBounds(
+	t *tree.IndirectionExpr, inScope *scope, colRefs *opt.ColSet,
+) memo.ScalarListExpr {
+	buildBound := func(e tree.Expr, def int) opt.ScalarExpr {
+		if e == nil {
+			return b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(def)), types.Int)
+		}
+		return b.buildScalar(e.(tree.TypedExpr), inScope, nil, nil, colRefs)
+	}
+
+	bounds := make(memo.ScalarListExpr, 0, 2*len(t.Indirection))
+	for _, subscript := range t.Indirection {
+		if !subscript.Slice {
+			bounds = append(bounds, buildBound(nil, 1), buildBound(subscript.Begin, 0))
+			continue
+		}
+		bounds = append(bounds,
+			buildBound(subscript.Begin, math.MinInt32),
+			buildBound(subscript.End, math.MaxInt32),
+		)
+	}
+	return bounds
+}
+
 func (b *Builder) hasSubOperator(t *tree.ComparisonExpr) bool {
 	return t.Operator == tree.Any || t.Operator == tree.All || t.Operator == tree.Some
 }
@@ -645,6 +686,11 @@ func (b *Builder) constructComparison(
 		return b.factory.ConstructJsonSomeExists(left, right)
 	case tree.Overlaps:
 		return b.factory.ConstructOverlaps(left, right)
+	case tree.TSMatches:
+		if left.DataType().Family() == types.TSQueryFamily {
+			left, right = right, left
+		}
+		return b.factory.ConstructTSMatches(left, right)
 	}
 	panic(errors.AssertionFailedf("unhandled comparison operator: %s", log.Safe(cmp)))
 }
@@ -54,6 +54,8 @@ func (b *Builder) buildDataSource(
 		if source.IndexFlags != nil {
 			telemetry.Inc(sqltelemetry.IndexHintUseCounter)
 			indexFlags = source.IndexFlags
+		} else if hintFlags := b.statementHintIndexFlags(source); hintFlags != nil {
+			indexFlags = hintFlags
 		}
 		if source.As.Alias != "" {
 			locking = locking.filter(source.As.Alias)
@@ -489,17 +491,20 @@ func (b *Builder) buildScan(
 					}
 				}
 				if idx == -1 {
-					var err error
-					if indexFlags.Index != "" {
-						err = errors.Errorf("index %q not found", tree.ErrString(&indexFlags.Index))
-					} else {
-						err = errors.Errorf("index [%d] not found", indexFlags.IndexID)
+					if !b.ignoreStaleStatementHintIndex(indexFlags) {
+						var err error
+						if indexFlags.Index != "" {
+							err = errors.Errorf("index %q not found", tree.ErrString(&indexFlags.Index))
+						} else {
+							err = errors.Errorf("index [%d] not found", indexFlags.IndexID)
+						}
+						panic(err)
 					}
-					panic(err)
+				} else {
+					private.Flags.ForceIndex = true
+					private.Flags.Index = idx
+					private.Flags.Direction = indexFlags.Direction
 				}
-				private.Flags.ForceIndex = true
-				private.Flags.Index = idx
-				private.Flags.Direction = indexFlags.Direction
 			}
 		}
 		if locking.isSet() {
@@ -1137,6 +1142,18 @@ func (b *Builder) buildFromWithLateral(
 // validateAsOf ensures that any AS OF SYSTEM TIME timestamp is consistent with
 // that of the root statement.
 func (b *Builder) validateAsOf(asOf tree.AsOfClause) {
+	if minTS := b.semaCtx.AsOfBoundedStalenessMinTimestamp; minTS != nil {
+		asOfTS, err := tree.EvalAsOf(asOf, b.semaCtx, b.evalCtx)
+		if err != nil {
+			panic(err)
+		}
+		if !asOfTS.BoundedStaleness || *minTS != asOfTS.Timestamp {
+			panic(unimplementedWithIssueDetailf(35712, "",
+				"cannot specify AS OF SYSTEM TIME with different timestamps"))
+		}
+		return
+	}
+
 	ts, err := tree.EvalAsOfTimestamp(asOf, b.semaCtx, b.evalCtx)
 	if err != nil {
 		panic(err)
@@ -1191,11 +1208,9 @@ func (b *Builder) validateLockingInFrom(
 			// AST nodes should not be created with this locking strength.
 			panic(errors.AssertionFailedf("locking item without strength"))
 		case tree.ForUpdate, tree.ForNoKeyUpdate, tree.ForShare, tree.ForKeyShare:
-			// CockroachDB treats all of the FOR LOCKED modes as no-ops. Since all
-			// transactions are serializable in CockroachDB, clients can't observe
-			// whether or not FOR UPDATE (or any of the other weaker modes) actually
-			// created a lock. This behavior may improve as the transaction model gains
-			// more capabilities.
+			// The key-value scans acquire exclusive locks on the rows that they
+			// return for all of the FOR LOCKED modes, since exclusive locks are at
+			// least as strong as any of them.
 		default:
 			panic(errors.AssertionFailedf("unknown locking strength: %s", li.Strength))
 		}
@@ -1204,12 +1219,10 @@ func (b *Builder) validateLockingInFrom(
 		switch li.WaitPolicy {
 		case tree.LockWaitBlock:
 			// Default.
-		case tree.LockWaitSkip:
-			panic(unimplementedWithIssueDetailf(40476, "",
-				"SKIP LOCKED lock wait policy is not supported"))
-		case tree.LockWaitError:
-			panic(unimplementedWithIssueDetailf(40476, "",
-				"NOWAIT lock wait policy is not supported"))
+		case tree.LockWaitSkip, tree.LockWaitError:
+			// SKIP LOCKED and NOWAIT are passed down to the key-value scans, which
+			// skip over keys locked by other transactions or raise an error when
+			// they encounter such keys, respectively.
 		default:
 			panic(errors.AssertionFailedf("unknown locking wait policy: %s", li.WaitPolicy))
 		}
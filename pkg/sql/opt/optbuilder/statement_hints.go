@@ -0,0 +1,183 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// staleStatementHintsLogLimiter limits the rate at which ignored statement
+// hints are logged.
+var staleStatementHintsLogLimiter = log.Every(time.Minute)
+
+// statementHintTables tracks which data sources of the statement the
+// statement hints apply to.
+type statementHintTables struct {
+	// indexFlags maps the index flags created by statementHintIndexFlags to
+	// the name of the hinted table, which distinguishes them from inline index
+	// hints.
+	indexFlags map[*tree.IndexFlags]tree.Name
+	// indexes and joins contain the names of the tables whose index and join
+	// hints matched a data source.
+	indexes map[tree.Name]bool
+	joins   map[tree.Name]bool
+	// staleIndexes contains the names of the tables whose hinted index doesn't
+	// exist (anymore).
+	staleIndexes map[tree.Name]bool
+}
+
+// statementHintIndexFlags returns the index flags that the statement hints
+// specify for the given data source, or nil if there are none. Data sources
+// are matched by their alias, or by their table name if they have no alias.
+func (b *Builder) statementHintIndexFlags(source *tree.AliasedTableExpr) *tree.IndexFlags {
+	if b.StatementHints == nil || len(b.StatementHints.Indexes) == 0 {
+		return nil
+	}
+	name, ok := statementHintTableName(source)
+	if !ok {
+		return nil
+	}
+	idx, ok := b.StatementHints.Indexes[name]
+	if !ok {
+		return nil
+	}
+	t := &b.statementHintTables
+	if t.indexFlags == nil {
+		t.indexFlags = make(map[*tree.IndexFlags]tree.Name)
+		t.indexes = make(map[tree.Name]bool)
+	}
+	flags := &tree.IndexFlags{Index: idx}
+	t.indexFlags[flags] = name
+	t.indexes[name] = true
+	return flags
+}
+
+// ignoreStaleStatementHintIndex returns true if the given index flags were
+// created from a statement hint, in which case the hint is ignored because the
+// hinted index doesn't exist. The index may have been dropped or renamed after
+// the hint was created.
+func (b *Builder) ignoreStaleStatementHintIndex(flags *tree.IndexFlags) bool {
+	t := &b.statementHintTables
+	name, ok := t.indexFlags[flags]
+	if !ok {
+		return false
+	}
+	if t.staleIndexes == nil {
+		t.staleIndexes = make(map[tree.Name]bool)
+	}
+	t.staleIndexes[name] = true
+	return true
+}
+
+// statementHintJoinFlags returns the join flags that the statement hints
+// specify for a join with the given right side, or zero flags if there are
+// none. Like the inline LOOKUP join hint, a lookup join hint only applies to
+// inner and left joins; it is ignored for other join types.
+func (b *Builder) statementHintJoinFlags(
+	right tree.TableExpr, joinType sqlbase.JoinType,
+) memo.JoinFlags {
+	if b.StatementHints == nil || len(b.StatementHints.Joins) == 0 {
+		return 0
+	}
+	source, ok := right.(*tree.AliasedTableExpr)
+	if !ok {
+		return 0
+	}
+	name, ok := statementHintTableName(source)
+	if !ok {
+		return 0
+	}
+	hint, ok := b.StatementHints.Joins[name]
+	if !ok {
+		return 0
+	}
+	t := &b.statementHintTables
+	if t.joins == nil {
+		t.joins = make(map[tree.Name]bool)
+	}
+	t.joins[name] = true
+	switch hint {
+	case tree.AstHash:
+		return memo.AllowHashJoinStoreRight
+
+	case tree.AstLookup:
+		if joinType == sqlbase.InnerJoin || joinType == sqlbase.LeftOuterJoin {
+			return memo.AllowLookupJoinIntoRight
+		}
+
+	case tree.AstMerge:
+		return memo.AllowMergeJoin
+	}
+	return 0
+}
+
+// checkStatementHints checks that all the statement hints applied to the
+// statement. Hints that name a table which isn't a data source of the
+// statement, or an index that doesn't exist, can't be applied; this happens
+// when the schema changes after the hints are created. Such hints are ignored
+// and reported to telemetry and the log, unless ValidateStatementHints is set,
+// in which case an error is returned.
+func (b *Builder) checkStatementHints() error {
+	h := b.StatementHints
+	if h == nil {
+		return nil
+	}
+	t := &b.statementHintTables
+	var stale []string
+	for name, idx := range h.Indexes {
+		if !t.indexes[name] || t.staleIndexes[name] {
+			idxName := tree.Name(idx)
+			stale = append(stale, "INDEX("+name.String()+", "+idxName.String()+")")
+		}
+	}
+	for name, hint := range h.Joins {
+		if !t.joins[name] {
+			stale = append(stale, hint+"_JOIN("+name.String()+")")
+		}
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+	sort.Strings(stale)
+	if b.ValidateStatementHints {
+		return pgerror.Newf(pgcode.InvalidParameterValue,
+			"statement hints do not apply to the statement: %s", strings.Join(stale, " "))
+	}
+	telemetry.Inc(sqltelemetry.StaleStatementHintsCounter)
+	if staleStatementHintsLogLimiter.ShouldLog() {
+		log.Warningf(b.ctx, "ignoring statement hints that do not apply to the statement: %s",
+			strings.Join(stale, " "))
+	}
+	return nil
+}
+
+// statementHintTableName returns the name by which statement hints refer to
+// the given data source.
+func statementHintTableName(source *tree.AliasedTableExpr) (tree.Name, bool) {
+	if source.As.Alias != "" {
+		return source.As.Alias, true
+	}
+	if tn, ok := source.Expr.(*tree.TableName); ok {
+		return tn.TableName, true
+	}
+	return "", false
+}
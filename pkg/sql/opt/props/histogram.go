@@ -12,10 +12,12 @@ package props
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"io"
 	"math"
 	"sort"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
@@ -143,7 +145,7 @@ func (h *Histogram) maxDistinctValuesCount() float64 {
 // maxDistinctValuesInRange returns the maximum number of distinct values in
 // the range [lowerBound, upperBound). It returns ok=false when it is not
 // possible to determine a finite value (which is the case for all types other
-// than integers and dates).
+// than integers, dates and timestamps).
 func maxDistinctValuesInRange(lowerBound, upperBound tree.Datum) (_ float64, ok bool) {
 	switch lowerBound.ResolvedType().Family() {
 	case types.IntFamily:
@@ -157,11 +159,30 @@ func maxDistinctValuesInRange(lowerBound, upperBound tree.Datum) (_ float64, ok
 		}
 		return 0, false
 
+	case types.TimestampFamily:
+		lower := lowerBound.(*tree.DTimestamp).Time
+		upper := upperBound.(*tree.DTimestamp).Time
+		return microsecondsBetween(lower, upper), true
+
+	case types.TimestampTZFamily:
+		lower := lowerBound.(*tree.DTimestampTZ).Time
+		upper := upperBound.(*tree.DTimestampTZ).Time
+		return microsecondsBetween(lower, upper), true
+
 	default:
 		return 0, false
 	}
 }
 
+// microsecondsBetween returns the number of microseconds between lower and
+// upper, which is the maximum number of distinct timestamps in the range
+// [lower, upper). It avoids time.Duration so that it doesn't overflow for
+// ranges longer than ~292 years.
+func microsecondsBetween(lower, upper time.Time) float64 {
+	return float64(upper.Unix()-lower.Unix())*1e6 +
+		float64(upper.Nanosecond()/1000-lower.Nanosecond()/1000)
+}
+
 // CanFilter returns true if the given constraint can filter the histogram.
 // This is the case if there is only one constrained column in c, it is
 // ascending, and it matches the column of the histogram.
@@ -391,9 +412,11 @@ func makeSpanFromBucket(b *cat.HistogramBucket, lowerBound tree.Datum) (span con
 //   [/2 - /10]  => {NumEq: 5, NumRange: 8, UpperBound: 10.0}
 //   [/20 - /30] => error
 //
-// For non-numeric types such as strings, it is not possible to estimate
-// the size of NumRange if the bucket is cut off in the middle. In this case,
-// we use the heuristic that NumRange is reduced by half.
+// For strings, bytes and UUIDs, the size of NumRange is estimated by
+// interpolating between the byte representations of the bounds (see
+// getRangesFromBytes). For other non-numeric types, it is not possible to
+// estimate the size of NumRange if the bucket is cut off in the middle. In
+// this case, we use the heuristic that NumRange is reduced by half.
 //
 func getFilteredBucket(
 	b *cat.HistogramBucket,
@@ -414,7 +437,6 @@ func getFilteredBucket(
 	var rangeBefore, rangeAfter float64
 	isDiscrete := false
 	ok := true
-	// TODO(rytaft): handle more types here.
 	// Note: the calculations below assume that bucketLowerBound is inclusive and
 	// Span.PreferInclusive() has been called on the span.
 	switch spanLowerBound.ResolvedType().Family() {
@@ -481,6 +503,11 @@ func getFilteredBucket(
 		rangeBefore = float64(upperBefore.Sub(lowerBefore))
 		rangeAfter = float64(upperAfter.Sub(lowerAfter))
 
+	case types.StringFamily, types.BytesFamily, types.UuidFamily:
+		rangeBefore, rangeAfter, ok = getRangesFromBytes(
+			bucketLowerBound, b.UpperBound, spanLowerBound, spanUpperBound,
+		)
+
 	default:
 		ok = false
 	}
@@ -523,6 +550,58 @@ func getFilteredBucket(
 	}
 }
 
+// getRangesFromBytes approximates the size of the ranges [lowerBefore,
+// upperBefore] and [lowerAfter, upperAfter] for strings, bytes and UUIDs,
+// which are ordered according to their byte representation. The prefix that
+// is common to all four bounds is removed, and the following 8 bytes of each
+// bound (padded with zeros if necessary) are interpreted as a big-endian
+// integer. This allows interpolation within a bucket, e.g. the range
+// ['foobar', 'foobat'] is estimated to be a quarter of the range
+// ['foobar', 'foobaz'].
+//
+// It returns ok=false if any of the bounds is not a string, bytes or UUID.
+func getRangesFromBytes(
+	lowerBefore, upperBefore, lowerAfter, upperAfter tree.Datum,
+) (rangeBefore, rangeAfter float64, ok bool) {
+	var bounds [4][]byte
+	for i, d := range [4]tree.Datum{lowerBefore, upperBefore, lowerAfter, upperAfter} {
+		switch t := d.(type) {
+		case *tree.DString:
+			bounds[i] = []byte(*t)
+		case *tree.DBytes:
+			bounds[i] = []byte(*t)
+		case *tree.DUuid:
+			bounds[i] = t.GetBytes()
+		default:
+			return 0, 0, false
+		}
+	}
+
+	// Find the length of the common prefix.
+	prefixLen := 0
+	for ; prefixLen < len(bounds[0]); prefixLen++ {
+		c := bounds[0][prefixLen]
+		common := true
+		for i := 1; i < len(bounds); i++ {
+			if prefixLen >= len(bounds[i]) || bounds[i][prefixLen] != c {
+				common = false
+				break
+			}
+		}
+		if !common {
+			break
+		}
+	}
+
+	var vals [4]float64
+	for i := range bounds {
+		var fixed [8]byte
+		copy(fixed[:], bounds[i][prefixLen:])
+		vals[i] = float64(binary.BigEndian.Uint64(fixed[:]))
+	}
+	return vals[1] - vals[0], vals[3] - vals[2], true
+}
+
 // histogramWriter prints histograms with the following formatting:
 //   NumRange1    NumEq1     NumRange2    NumEq2    ....
 // <----------- UpperBound1 ----------- UpperBound2 ....
@@ -396,20 +396,50 @@ func TestFilterBucket(t *testing.T) {
 	})
 
 	t.Run("string", func(t *testing.T) {
-		bucket := &cat.HistogramBucket{NumEq: 5, NumRange: 10, DistinctRange: 10, UpperBound: tree.NewDString("foo")}
-		lowerBound := tree.NewDString("bar")
+		bucket := &cat.HistogramBucket{NumEq: 5, NumRange: 10, DistinctRange: 10, UpperBound: tree.NewDString("foobaz")}
+		lowerBound := tree.NewDString("foobar")
 		testData := []testCase{
 			{
-				span:     "[/bar - /baz]",
-				expected: &cat.HistogramBucket{NumEq: 0, NumRange: 5, DistinctRange: 5, UpperBound: tree.NewDString("baz")},
+				span:     "[/foobar - /foobat]",
+				expected: &cat.HistogramBucket{NumEq: 0, NumRange: 2.5, DistinctRange: 2.5, UpperBound: tree.NewDString("foobat")},
 			},
 			{
-				span:     "[/baz - /foo]",
-				expected: &cat.HistogramBucket{NumEq: 5, NumRange: 5, DistinctRange: 5, UpperBound: tree.NewDString("foo")},
+				span:     "[/foobat - /foobaz]",
+				expected: &cat.HistogramBucket{NumEq: 5, NumRange: 7.5, DistinctRange: 7.5, UpperBound: tree.NewDString("foobaz")},
+			},
+			{
+				span:     "[/foobaz - /foobaz]",
+				expected: &cat.HistogramBucket{NumEq: 5, NumRange: 0, DistinctRange: 0, UpperBound: tree.NewDString("foobaz")},
 			},
 		}
 
 		runTest(bucket, lowerBound, testData, types.StringFamily)
 	})
 
+	t.Run("uuid", func(t *testing.T) {
+		parseUUID := func(s string) tree.Datum {
+			d, err := tree.ParseDUuidFromString(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return d
+		}
+		upperBound := parseUUID("00000000-0000-0000-0000-000000000100")
+		bucket := &cat.HistogramBucket{NumEq: 5, NumRange: 10, DistinctRange: 10, UpperBound: upperBound}
+		lowerBound := parseUUID("00000000-0000-0000-0000-000000000000")
+		ub1 := parseUUID("00000000-0000-0000-0000-000000000040")
+		testData := []testCase{
+			{
+				span:     "[/00000000-0000-0000-0000-000000000000 - /00000000-0000-0000-0000-000000000040]",
+				expected: &cat.HistogramBucket{NumEq: 0, NumRange: 2.5, DistinctRange: 2.5, UpperBound: ub1},
+			},
+			{
+				span:     "[/00000000-0000-0000-0000-000000000040 - /00000000-0000-0000-0000-000000000100]",
+				expected: &cat.HistogramBucket{NumEq: 5, NumRange: 7.5, DistinctRange: 7.5, UpperBound: upperBound},
+			},
+		}
+
+		runTest(bucket, lowerBound, testData, types.UuidFamily)
+	})
+
 }
@@ -148,6 +148,10 @@ type Flags struct {
 	// should attempt to reorder.
 	JoinLimit int
 
+	// GreedyJoinReorder enables greedy join reordering for joins that are too
+	// large to be reordered exhaustively (see JoinLimit).
+	GreedyJoinReorder bool
+
 	// Locality specifies the location of the planning node as a set of user-
 	// defined key/value pairs, ordered from most inclusive to least inclusive.
 	// If there are no tiers, then the node's location is not known. Examples:
@@ -302,6 +306,9 @@ func New(catalog cat.Catalog, sql string) *OptTester {
 //    expression in the query tree for the purpose of creating alternate query
 //    plans in the optimizer.
 //
+//  - greedy-join-reorder: enables greedy join reordering for joins that are
+//    too large to be reordered exhaustively.
+//
 //  - locality: used to set the locality of the node that plans the query. This
 //    can affect costing when there are multiple possible indexes to choose
 //    from, each in different localities.
@@ -336,6 +343,13 @@ func (ot *OptTester) RunCommand(tb testing.TB, d *datadriven.TestData) string {
 		ot.evalCtx.SessionData.ReorderJoinsLimit = ot.Flags.JoinLimit
 	}
 
+	if ot.Flags.GreedyJoinReorder {
+		defer func(oldValue bool) {
+			ot.evalCtx.SessionData.GreedyJoinReorderEnabled = oldValue
+		}(ot.evalCtx.SessionData.GreedyJoinReorderEnabled)
+		ot.evalCtx.SessionData.GreedyJoinReorderEnabled = true
+	}
+
 	ot.Flags.Verbose = testing.Verbose()
 	ot.evalCtx.TestingKnobs.OptimizerCostPerturbation = ot.Flags.PerturbCost
 	ot.evalCtx.Locality = ot.Flags.Locality
@@ -599,6 +613,9 @@ func (f *Flags) Set(arg datadriven.CmdArg) error {
 		}
 		f.JoinLimit = int(limit)
 
+	case "greedy-join-reorder":
+		f.GreedyJoinReorder = true
+
 	case "rule":
 		if len(arg.Vals) != 1 {
 			return fmt.Errorf("rule requires one argument")
@@ -2067,10 +2067,52 @@ func (c *CustomFuncs) ShouldReorderJoins(left, right memo.RelExpr) bool {
 	// TODO(justin): referencing left and right here is a hack: ideally
 	// we'd want to be able to reference the logical properties of the
 	// expression being explored in this CustomFunc.
+	if hints := c.e.mem.StatementHints(); hints != nil && hints.NoJoinReorder {
+		return false
+	}
 	size := c.deriveJoinSize(left) + c.deriveJoinSize(right)
 	return size <= c.e.evalCtx.SessionData.ReorderJoinsLimit
 }
 
+// ShouldGreedyReorderJoins returns whether the optimizer should build a join
+// order greedily for the tree of inner joins with the given inputs. This is the
+// case if greedy join reordering is enabled and the tree has too many joins to
+// be reordered exhaustively (see ShouldReorderJoins).
+func (c *CustomFuncs) ShouldGreedyReorderJoins(left, right memo.RelExpr) bool {
+	if !c.e.evalCtx.SessionData.GreedyJoinReorderEnabled {
+		return false
+	}
+	// A limit of zero disables join reordering altogether.
+	limit := c.e.evalCtx.SessionData.ReorderJoinsLimit
+	if limit == 0 {
+		return false
+	}
+	if hints := c.e.mem.StatementHints(); hints != nil && hints.NoJoinReorder {
+		return false
+	}
+	size := c.deriveJoinSize(left) + c.deriveJoinSize(right)
+	return size > limit
+}
+
+// GenerateGreedyJoinOrder flattens the tree of inner joins rooted at the given
+// join into its base relations and filters, and adds a join with a greedily
+// built left-deep join order to the group. See greedyJoinOrderer for details.
+func (c *CustomFuncs) GenerateGreedyJoinOrder(
+	grp memo.RelExpr, left, right memo.RelExpr, on memo.FiltersExpr,
+) {
+	var o greedyJoinOrderer
+	o.init(c)
+	o.addJoin(left, right, on)
+	newLeft, newRight, newOn := o.build()
+	join := memo.InnerJoinExpr{
+		Left:        newLeft,
+		Right:       newRight,
+		On:          newOn,
+		JoinPrivate: *memo.EmptyJoinPrivate,
+	}
+	c.e.mem.AddInnerJoinToGroup(&join, grp)
+}
+
 // IsSimpleEquality returns true if all of the filter conditions are equalities
 // between simple data types (constants, variables, tuples and NULL).
 func (c *CustomFuncs) IsSimpleEquality(filters memo.FiltersExpr) bool {
@@ -0,0 +1,269 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package xform
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+)
+
+// greedyUnknownFilterSelectivity is the selectivity used for filters other
+// than equalities between columns. It matches the selectivity used by the
+// statistics builder for filters it cannot estimate.
+const greedyUnknownFilterSelectivity = 1.0 / 3.0
+
+// greedyJoinOrderer builds a left-deep join order for a tree of inner joins
+// that has too many joins to be reordered exhaustively by AssociateJoin and
+// CommuteJoin. The tree is flattened into its base relations (any input that
+// is not an inner join without hints) and the conjuncts of its ON conditions.
+// The join order is then built greedily, based on the estimated cardinality of
+// the intermediate results:
+//
+//   1. Start with the pair of connected relations (i.e. relations that share a
+//      filter) whose join has the smallest estimated row count.
+//   2. Repeatedly join the relation that is connected to the relations joined
+//      so far and that results in the smallest estimated row count. If there is
+//      no such relation, add the relation with the smallest row count with a
+//      cross join.
+//
+// Every filter is placed on the lowest join that has all the columns it
+// references. The resulting tree is added to the memo, where the exhaustive
+// rules can still reorder its smaller subtrees and pick join algorithms. This
+// is similar to the Greedy Operator Ordering heuristic described in:
+//
+//   Leonidas Fegaras. 1998. A New Heuristic for Optimizing Large Queries.
+//
+// For example, to build the greedy join order for an InnerJoin expression:
+//
+//   var o greedyJoinOrderer
+//   o.init(c)
+//   o.addJoin(left, right, on)
+//   left, right, on := o.build()
+//
+type greedyJoinOrderer struct {
+	c *CustomFuncs
+
+	// rels are the base relations of the join tree.
+	rels []memo.RelExpr
+
+	// relCols[i] is the set of output columns of rels[i].
+	relCols []opt.ColSet
+
+	// allCols is the union of relCols.
+	allCols opt.ColSet
+
+	// filters are the conjuncts of the ON conditions of the join tree.
+	filters memo.FiltersExpr
+
+	// filterCols[i] is the set of columns of rels that filters[i] references.
+	filterCols []opt.ColSet
+
+	// used[i] is true if filters[i] has been placed on a join.
+	used []bool
+}
+
+func (o *greedyJoinOrderer) init(c *CustomFuncs) {
+	o.c = c
+}
+
+// addJoin flattens the inner join with the given inputs and ON condition into
+// the base relations and filters of the join tree.
+func (o *greedyJoinOrderer) addJoin(left, right memo.RelExpr, on memo.FiltersExpr) {
+	o.addInput(left)
+	o.addInput(right)
+	o.filters = append(o.filters, on...)
+}
+
+func (o *greedyJoinOrderer) addInput(e memo.RelExpr) {
+	if join, ok := e.(*memo.InnerJoinExpr); ok && join.Flags.Empty() {
+		o.addJoin(join.Left, join.Right, join.On)
+		return
+	}
+	o.rels = append(o.rels, e)
+}
+
+// build returns the inputs and ON condition of the topmost join of the greedy
+// join order. The lower joins are constructed with the factory.
+func (o *greedyJoinOrderer) build() (left, right memo.RelExpr, on memo.FiltersExpr) {
+	o.relCols = make([]opt.ColSet, len(o.rels))
+	for i := range o.rels {
+		o.relCols[i] = o.rels[i].Relational().OutputCols
+		o.allCols.UnionWith(o.relCols[i])
+	}
+	o.filterCols = make([]opt.ColSet, len(o.filters))
+	for i := range o.filters {
+		o.filterCols[i] = o.filters[i].ScalarProps().OuterCols.Intersection(o.allCols)
+	}
+	o.used = make([]bool, len(o.filters))
+	joined := make([]bool, len(o.rels))
+
+	// Start with the pair of connected relations with the smallest estimated
+	// join row count. If no relations are connected, start with the smallest
+	// relation.
+	first, second := -1, -1
+	var bestRows float64
+	for i := range o.rels {
+		for j := i + 1; j < len(o.rels); j++ {
+			rows, connected := o.joinRowCount(o.rowCount(i), o.relCols[i], j)
+			if connected && (first == -1 || rows < bestRows) {
+				first, second, bestRows = i, j, rows
+			}
+		}
+	}
+	if first == -1 {
+		first = o.smallestRelation(joined)
+	}
+	joined[first] = true
+	curCols := o.relCols[first]
+	curRows := o.rowCount(first)
+	cur := o.rels[first]
+
+	for n := 1; n < len(o.rels); n++ {
+		next := second
+		if n == 1 && next != -1 {
+			curRows = bestRows
+		} else {
+			next, curRows = o.nextRelation(curRows, curCols, joined)
+		}
+		joined[next] = true
+		curCols = curCols.Union(o.relCols[next])
+		on = o.placeFilters(curCols)
+		if n == len(o.rels)-1 {
+			return cur, o.rels[next], on
+		}
+		cur = o.c.e.f.ConstructInnerJoin(cur, o.rels[next], on, memo.EmptyJoinPrivate)
+	}
+	panic("greedy join order requires at least two relations")
+}
+
+// nextRelation returns the relation that should be joined with the relations
+// joined so far, which have the given estimated row count and columns, as well
+// as the estimated row count of the join.
+func (o *greedyJoinOrderer) nextRelation(
+	curRows float64, curCols opt.ColSet, joined []bool,
+) (next int, rows float64) {
+	next = -1
+	for i := range o.rels {
+		if joined[i] {
+			continue
+		}
+		r, connected := o.joinRowCount(curRows, curCols, i)
+		if connected && (next == -1 || r < rows) {
+			next, rows = i, r
+		}
+	}
+	if next == -1 {
+		// None of the remaining relations is connected, so a cross join is
+		// unavoidable. Keep it as small as possible.
+		next = o.smallestRelation(joined)
+		rows, _ = o.joinRowCount(curRows, curCols, next)
+	}
+	return next, rows
+}
+
+// smallestRelation returns the relation with the smallest row count that has
+// not been joined yet.
+func (o *greedyJoinOrderer) smallestRelation(joined []bool) int {
+	res := -1
+	for i := range o.rels {
+		if !joined[i] && (res == -1 || o.rowCount(i) < o.rowCount(res)) {
+			res = i
+		}
+	}
+	return res
+}
+
+// joinRowCount estimates the row count of joining rels[rel] with the relations
+// joined so far, which have the given estimated row count and columns. It also
+// returns whether any of the remaining filters connects rels[rel] to those
+// relations.
+func (o *greedyJoinOrderer) joinRowCount(
+	curRows float64, curCols opt.ColSet, rel int,
+) (rows float64, connected bool) {
+	rows = curRows * o.rowCount(rel)
+	cols := curCols.Union(o.relCols[rel])
+	for i := range o.filters {
+		if o.used[i] || !o.filterCols[i].SubsetOf(cols) || !o.filterCols[i].Intersects(o.relCols[rel]) {
+			continue
+		}
+		if o.filterCols[i].Intersects(curCols) {
+			connected = true
+		}
+		rows *= o.selectivity(&o.filters[i])
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	return rows, connected
+}
+
+// selectivity estimates the selectivity of the given filter. The selectivity
+// of an equality between two columns is estimated from the distinct counts of
+// the columns in their base relations.
+func (o *greedyJoinOrderer) selectivity(filter *memo.FiltersItem) float64 {
+	eq, ok := filter.Condition.(*memo.EqExpr)
+	if !ok {
+		return greedyUnknownFilterSelectivity
+	}
+	leftVar, ok := eq.Left.(*memo.VariableExpr)
+	if !ok {
+		return greedyUnknownFilterSelectivity
+	}
+	rightVar, ok := eq.Right.(*memo.VariableExpr)
+	if !ok {
+		return greedyUnknownFilterSelectivity
+	}
+	distinct := o.distinctCount(leftVar.Col)
+	if d := o.distinctCount(rightVar.Col); d > distinct {
+		distinct = d
+	}
+	if distinct < 1 {
+		return greedyUnknownFilterSelectivity
+	}
+	return 1 / distinct
+}
+
+// distinctCount returns the distinct count of the given column in the base
+// relation that produces it, or zero if it is not known.
+func (o *greedyJoinOrderer) distinctCount(col opt.ColumnID) float64 {
+	for i := range o.rels {
+		if !o.relCols[i].Contains(col) {
+			continue
+		}
+		colStat, ok := o.c.e.mem.RequestColStat(o.rels[i], opt.MakeColSet(col))
+		if !ok {
+			return 0
+		}
+		return colStat.DistinctCount
+	}
+	return 0
+}
+
+func (o *greedyJoinOrderer) rowCount(rel int) float64 {
+	return o.rels[rel].Relational().Stats.RowCount
+}
+
+// placeFilters returns the filters that have not been placed on a join yet and
+// that only reference the given columns of the base relations, and marks them
+// as placed.
+func (o *greedyJoinOrderer) placeFilters(cols opt.ColSet) memo.FiltersExpr {
+	var res memo.FiltersExpr
+	for i := range o.filters {
+		if !o.used[i] && o.filterCols[i].SubsetOf(cols) {
+			o.used[i] = true
+			res = append(res, o.filters[i])
+		}
+	}
+	if res == nil {
+		return memo.TrueFilter
+	}
+	return o.c.SortFilters(res)
+}
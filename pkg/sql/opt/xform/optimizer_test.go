@@ -12,6 +12,7 @@ package xform_test
 
 import (
 	"flag"
+	"fmt"
 	"strings"
 	"sync"
 	"testing"
@@ -126,6 +127,68 @@ func TestDetachMemoRace(t *testing.T) {
 	wg.Wait()
 }
 
+// TestGreedyJoinReorder verifies that greedy join reordering finds a cheaper
+// plan for a join that is too large to be reordered exhaustively and whose
+// syntactic order requires cross joins.
+func TestGreedyJoinReorder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	catalog := testcat.New()
+	const numTables = 8
+	for i := 0; i < numTables; i++ {
+		ddl := fmt.Sprintf("CREATE TABLE t%d (a INT PRIMARY KEY, b INT)", i)
+		if _, err := catalog.ExecuteDDL(ddl); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The tables form a chain (t0.b = t1.a, t1.b = t2.a, ...), but they are
+	// listed so that no two adjacent tables are connected.
+	var from, where []string
+	for i := 0; i < numTables; i += 2 {
+		from = append(from, fmt.Sprintf("t%d", i))
+	}
+	for i := 1; i < numTables; i += 2 {
+		from = append(from, fmt.Sprintf("t%d", i))
+	}
+	for i := 0; i < numTables-1; i++ {
+		where = append(where, fmt.Sprintf("t%d.b = t%d.a", i, i+1))
+	}
+	query := fmt.Sprintf(
+		"SELECT * FROM %s WHERE %s", strings.Join(from, ", "), strings.Join(where, " AND "),
+	)
+
+	optimize := func(greedy bool) (cost memo.Cost, greedyApplied bool) {
+		var o xform.Optimizer
+		evalCtx := tree.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+		evalCtx.SessionData.ReorderJoinsLimit = 2
+		evalCtx.SessionData.GreedyJoinReorderEnabled = greedy
+		testutils.BuildQuery(t, &o, catalog, &evalCtx, query)
+		o.NotifyOnAppliedRule(func(ruleName opt.RuleName, source, target opt.Expr) {
+			if ruleName == opt.GenerateGreedyJoinOrder {
+				greedyApplied = true
+			}
+		})
+		root, err := o.Optimize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return root.(memo.RelExpr).Cost(), greedyApplied
+	}
+
+	syntacticCost, applied := optimize(false /* greedy */)
+	if applied {
+		t.Fatal("greedy join reordering applied while disabled")
+	}
+	greedyCost, applied := optimize(true /* greedy */)
+	if !applied {
+		t.Fatal("greedy join reordering not applied")
+	}
+	if greedyCost >= syntacticCost {
+		t.Errorf("expected greedy join order to be cheaper than %f, got %f", syntacticCost, greedyCost)
+	}
+}
+
 // TestCoster files can be run separately like this:
 //   make test PKG=./pkg/sql/opt/xform TESTS="TestCoster/sort"
 //   make test PKG=./pkg/sql/opt/xform TESTS="TestCoster/scan"
@@ -20,12 +20,15 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/constraint"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowexec"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
@@ -123,6 +126,13 @@ func (ef *execFactory) ConstructScan(
 	scan.reqOrdering = ReqOrdering(reqOrdering)
 	scan.estimatedRowCount = uint64(rowCount)
 	if locking != nil {
+		if locking.WaitPolicy != tree.LockWaitBlock && !cluster.Version.IsActive(
+			context.TODO(), ef.planner.ExecCfg().Settings, cluster.VersionLockWaitPolicies,
+		) {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"%s lock wait policy can only be used on a cluster that has fully migrated to version 20.1",
+				locking.WaitPolicy)
+		}
 		scan.lockingStrength = sqlbase.ToScanLockingStrength(locking.Strength)
 		scan.lockingWaitPolicy = sqlbase.ToScanLockingWaitPolicy(locking.WaitPolicy)
 	}
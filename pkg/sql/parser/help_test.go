@@ -114,6 +114,9 @@ func TestContextualHelp(t *testing.T) {
 
 		{`CREATE STATISTICS ??`, `CREATE STATISTICS`},
 
+		{`CREATE STATEMENT ??`, `CREATE STATEMENT HINTS`},
+		{`CREATE STATEMENT HINTS FOR 'SELECT 1' ??`, `CREATE STATEMENT HINTS`},
+
 		{`CREATE TABLE blah (??`, `CREATE TABLE`},
 		{`CREATE TABLE IF NOT ??`, `CREATE TABLE`},
 		{`CREATE TABLE blah (x, y) AS ??`, `CREATE TABLE`},
@@ -158,6 +161,8 @@ func TestContextualHelp(t *testing.T) {
 		{`DROP USER IF ??`, `DROP USER`},
 		{`DROP USER IF EXISTS bloh ??`, `DROP USER`},
 
+		{`DROP STATEMENT ??`, `DROP STATEMENT HINTS`},
+
 		{`EXPLAIN (??`, `EXPLAIN`},
 		{`EXPLAIN SELECT 1 ??`, `SELECT`},
 		{`EXPLAIN INSERT INTO xx (SELECT 1) ??`, `INSERT`},
@@ -182,6 +187,13 @@ func TestContextualHelp(t *testing.T) {
 		{`DEALLOCATE ALL ??`, `DEALLOCATE`},
 		{`DEALLOCATE PREPARE ??`, `DEALLOCATE`},
 
+		{`DECLARE ??`, `DECLARE`},
+		{`DECLARE foo CURSOR FOR SELECT 1 ??`, `SELECT`},
+		{`FETCH ??`, `FETCH`},
+		{`FETCH NEXT ??`, `FETCH`},
+		{`MOVE ??`, `MOVE`},
+		{`CLOSE ??`, `CLOSE`},
+
 		{`INSERT INTO ??`, `INSERT`},
 		{`INSERT INTO blah (??`, `<SELECTCLAUSE>`},
 		{`INSERT INTO blah VALUES (1) RETURNING ??`, `INSERT`},
@@ -206,6 +218,11 @@ func TestContextualHelp(t *testing.T) {
 		{`GRANT ALL ON foo TO ??`, `GRANT`},
 		{`GRANT ALL ON foo TO bar ??`, `GRANT`},
 
+		{`LISTEN ??`, `LISTEN`},
+		{`NOTIFY ??`, `NOTIFY`},
+		{`NOTIFY foo, ??`, `NOTIFY`},
+		{`UNLISTEN ??`, `UNLISTEN`},
+
 		{`PAUSE ??`, `PAUSE JOBS`},
 
 		{`RESUME ??`, `RESUME JOBS`},
@@ -237,6 +254,8 @@ func TestContextualHelp(t *testing.T) {
 
 		{`SHOW HISTOGRAM ??`, `SHOW HISTOGRAM`},
 
+		{`SHOW STATEMENT ??`, `SHOW STATEMENT HINTS`},
+
 		{`SHOW QUERIES ??`, `SHOW QUERIES`},
 		{`SHOW LOCAL QUERIES ??`, `SHOW QUERIES`},
 
@@ -355,14 +355,19 @@ func newDecimal(prec, scale int32) (*types.T, error) {
 }
 
 // ArrayOf creates a type alias for an array of the given element type and fixed
-// bounds.
+// bounds. Each bound adds one dimension to the array; no bounds at all denotes
+// a one-dimensional array.
 func arrayOf(colType *types.T, bounds []int32) (*types.T, error) {
 	if err := types.CheckArrayElementType(colType); err != nil {
 		return nil, err
 	}
 
-	// Currently bounds are ignored.
-	return types.MakeArray(colType), nil
+	// Currently the bound values are ignored.
+	typ := types.MakeArray(colType)
+	for i := 1; i < len(bounds); i++ {
+		typ = types.MakeArray(typ)
+	}
+	return typ, nil
 }
 
 // The SERIAL types are pseudo-types that are only used during parsing. After
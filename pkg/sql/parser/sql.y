@@ -255,6 +255,9 @@ func (u *sqlSymUnion) int32() int32 {
 func (u *sqlSymUnion) int64() int64 {
     return u.val.(int64)
 }
+func (u *sqlSymUnion) cursorStmt() tree.CursorStmt {
+    return u.val.(tree.CursorStmt)
+}
 func (u *sqlSymUnion) seqOpt() tree.SequenceOption {
     return u.val.(tree.SequenceOption)
 }
@@ -514,25 +517,25 @@ func newNameFromStr(s string) *tree.Name {
 // below; search this file for "Keyword category lists".
 
 // Ordinary key words in alphabetical order.
-%token <str> ABORT ACTION ADD ADMIN AGGREGATE
+%token <str> ABORT ABSOLUTE ACTION ADD ADMIN AGGREGATE
 %token <str> ALL ALTER ANALYSE ANALYZE AND AND_AND ANY ANNOTATE_TYPE ARRAY AS ASC
 %token <str> ASYMMETRIC AT AUTHORIZATION AUTOMATIC
 
-%token <str> BACKUP BEGIN BETWEEN BIGINT BIGSERIAL BIT
+%token <str> BACKUP BACKWARD BEGIN BETWEEN BIGINT BIGSERIAL BIT
 %token <str> BUCKET_COUNT
 %token <str> BLOB BOOL BOOLEAN BOTH BY BYTEA BYTES
 
 %token <str> CACHE CANCEL CASCADE CASE CAST CHANGEFEED CHAR
 %token <str> CHARACTER CHARACTERISTICS CHECK
-%token <str> CLUSTER COALESCE COLLATE COLLATION COLUMN COLUMNS COMMENT COMMIT
+%token <str> CLOSE CLUSTER COALESCE COLLATE COLLATION COLUMN COLUMNS COMMENT COMMIT
 %token <str> COMMITTED COMPACT COMPLETE CONCAT CONFIGURATION CONFIGURATIONS CONFIGURE
 %token <str> CONFLICT CONSTRAINT CONSTRAINTS CONTAINS CONVERSION COPY COVERING CREATE
 %token <str> CROSS CUBE CURRENT CURRENT_CATALOG CURRENT_DATE CURRENT_SCHEMA
 %token <str> CURRENT_ROLE CURRENT_TIME CURRENT_TIMESTAMP
-%token <str> CURRENT_USER CYCLE
+%token <str> CURRENT_USER CURSOR CYCLE
 
 %token <str> DATA DATABASE DATABASES DATE DAY DEC DECIMAL DEFAULT
-%token <str> DEALLOCATE DEFERRABLE DEFERRED DELETE DESC
+%token <str> DEALLOCATE DECLARE DEFERRABLE DEFERRED DELETE DESC
 %token <str> DISCARD DISTINCT DO DOMAIN DOUBLE DROP
 
 %token <str> ELSE ENCODING END ENUM ESCAPE EXCEPT EXCLUDE
@@ -543,11 +546,11 @@ func newNameFromStr(s string) *tree.Name {
 
 %token <str> FALSE FAMILY FETCH FETCHVAL FETCHTEXT FETCHVAL_PATH FETCHTEXT_PATH
 %token <str> FILES FILTER
-%token <str> FIRST FLOAT FLOAT4 FLOAT8 FLOORDIV FOLLOWING FOR FORCE_INDEX FOREIGN FROM FULL FUNCTION
+%token <str> FIRST FLOAT FLOAT4 FLOAT8 FLOORDIV FOLLOWING FOR FORCE_INDEX FOREIGN FORWARD FROM FULL FUNCTION
 
 %token <str> GLOBAL GRANT GRANTS GREATEST GROUP GROUPING GROUPS
 
-%token <str> HAVING HASH HIGH HISTOGRAM HOUR
+%token <str> HAVING HASH HIGH HINTS HISTOGRAM HOLD HOUR
 
 %token <str> IF IFERROR IFNULL IGNORE_FOREIGN_KEYS ILIKE IMMEDIATE IMPORT IN INCREMENT INCREMENTAL
 %token <str> INET INET_CONTAINED_BY_OR_EQUALS
@@ -560,43 +563,43 @@ func newNameFromStr(s string) *tree.Name {
 %token <str> KEY KEYS KV
 
 %token <str> LANGUAGE LAST LATERAL LC_CTYPE LC_COLLATE
-%token <str> LEADING LEASE LEAST LEFT LESS LEVEL LIKE LIMIT LIST LOCAL
+%token <str> LEADING LEASE LEAST LEFT LESS LEVEL LIKE LIMIT LIST LISTEN LOCAL
 %token <str> LOCALTIME LOCALTIMESTAMP LOCKED LOOKUP LOW LSHIFT
 
-%token <str> MATCH MATERIALIZED MERGE MINVALUE MAXVALUE MINUTE MONTH
+%token <str> MATCH MATERIALIZED MERGE MINVALUE MAXVALUE MINUTE MONTH MOVE
 
 %token <str> NAN NAME NAMES NATURAL NEXT NO NO_INDEX_JOIN NONE NORMAL
-%token <str> NOT NOTHING NOTNULL NOWAIT NULL NULLIF NULLS NUMERIC
+%token <str> NOT NOTHING NOTIFY NOTNULL NOWAIT NULL NULLIF NULLS NUMERIC
 
 %token <str> OF OFF OFFSET OID OIDS OIDVECTOR ON ONLY OPT OPTION OPTIONS OR
 %token <str> ORDER ORDINALITY OTHERS OUT OUTER OVER OVERLAPS OVERLAY OWNED OPERATOR
 
 %token <str> PARENT PARTIAL PARTITION PARTITIONS PASSWORD PAUSE PHYSICAL PLACING
-%token <str> PLAN PLANS POSITION PRECEDING PRECISION PREPARE PRIMARY PRIORITY
+%token <str> PLAN PLANS POSITION PRECEDING PRECISION PREPARE PRIMARY PRIOR PRIORITY
 %token <str> PROCEDURAL PUBLIC PUBLICATION
 
 %token <str> QUERIES QUERY
 
 %token <str> RANGE RANGES READ REAL RECURSIVE REF REFERENCES
 %token <str> REGCLASS REGPROC REGPROCEDURE REGNAMESPACE REGTYPE
-%token <str> REMOVE_PATH RENAME REPEATABLE REPLACE
+%token <str> RELATIVE REMOVE_PATH RENAME REPEATABLE REPLACE
 %token <str> RELEASE RESET RESTORE RESTRICT RESUME RETURNING REVOKE RIGHT
 %token <str> ROLE ROLES ROLLBACK ROLLUP ROW ROWS RSHIFT RULE
 
-%token <str> SAVEPOINT SCATTER SCHEMA SCHEMAS SCRUB SEARCH SECOND SELECT SEQUENCE SEQUENCES
+%token <str> SAVEPOINT SCATTER SCHEMA SCHEMAS SCROLL SCRUB SEARCH SECOND SELECT SEQUENCE SEQUENCES
 %token <str> SERIAL SERIAL2 SERIAL4 SERIAL8
 %token <str> SERIALIZABLE SERVER SESSION SESSIONS SESSION_USER SET SETTING SETTINGS
 %token <str> SHARE SHOW SIMILAR SIMPLE SKIP SMALLINT SMALLSERIAL SNAPSHOT SOME SPLIT SQL
 
-%token <str> START STATISTICS STATUS STDIN STRICT STRING STORE STORED STORING SUBSTRING
+%token <str> START STATEMENT STATISTICS STATUS STDIN STRICT STRING STORE STORED STORING SUBSTRING
 %token <str> SYMMETRIC SYNTAX SYSTEM SUBSCRIPTION
 
 %token <str> TABLE TABLES TEMP TEMPLATE TEMPORARY TESTING_RELOCATE EXPERIMENTAL_RELOCATE TEXT THEN
 %token <str> TIES TIME TIMETZ TIMESTAMP TIMESTAMPTZ TO THROTTLING TRAILING TRACE TRANSACTION TREAT TRIGGER TRIM TRUE
-%token <str> TRUNCATE TRUSTED TYPE
+%token <str> TRUNCATE TRUSTED TSMATCH TYPE
 %token <str> TRACING
 
-%token <str> UNBOUNDED UNCOMMITTED UNION UNIQUE UNKNOWN UNLOGGED UNSPLIT
+%token <str> UNBOUNDED UNCOMMITTED UNION UNIQUE UNKNOWN UNLISTEN UNLOGGED UNSPLIT
 %token <str> UPDATE UPSERT USE USER USERS USING UUID
 
 %token <str> VALID VALIDATE VALUE VALUES VARBIT VARCHAR VARIADIC VIEW VARYING VIRTUAL
@@ -711,11 +714,13 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> create_sequence_stmt
 
 %type <tree.Statement> create_stats_stmt
+%type <tree.Statement> create_statement_hints_stmt
 %type <*tree.CreateStatsOptions> opt_create_stats_options
 %type <*tree.CreateStatsOptions> create_stats_option_list
 %type <*tree.CreateStatsOptions> create_stats_option
 
 %type <tree.Statement> create_type_stmt
+%type <tree.Statement> declare_cursor_stmt
 %type <tree.Statement> delete_stmt
 %type <tree.Statement> discard_stmt
 
@@ -728,6 +733,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> drop_user_stmt
 %type <tree.Statement> drop_view_stmt
 %type <tree.Statement> drop_sequence_stmt
+%type <tree.Statement> drop_statement_hints_stmt
 
 %type <tree.Statement> explain_stmt
 %type <tree.Statement> prepare_stmt
@@ -736,9 +742,13 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> export_stmt
 %type <tree.Statement> execute_stmt
 %type <tree.Statement> deallocate_stmt
+%type <tree.Statement> fetch_cursor_stmt
 %type <tree.Statement> grant_stmt
 %type <tree.Statement> insert_stmt
 %type <tree.Statement> import_stmt
+%type <tree.Statement> listen_stmt
+%type <tree.Statement> move_cursor_stmt
+%type <tree.Statement> notify_stmt
 %type <tree.Statement> pause_stmt
 %type <tree.Statement> release_stmt
 %type <tree.Statement> reset_stmt reset_session_stmt reset_csetting_stmt
@@ -749,6 +759,8 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> revoke_stmt
 %type <*tree.Select> select_stmt
 %type <tree.Statement> abort_stmt
+%type <tree.Statement> close_cursor_stmt
+%type <tree.CursorStmt> cursor_movement_specifier
 %type <tree.Statement> rollback_stmt
 %type <tree.Statement> savepoint_stmt
 
@@ -771,6 +783,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> show_fingerprints_stmt
 %type <tree.Statement> show_grants_stmt
 %type <tree.Statement> show_histogram_stmt
+%type <tree.Statement> show_statement_hints_stmt
 %type <tree.Statement> show_indexes_stmt
 %type <tree.Statement> show_partitions_stmt
 %type <tree.Statement> show_jobs_stmt
@@ -795,6 +808,7 @@ func newNameFromStr(s string) *tree.Name {
 
 %type <tree.Statement> transaction_stmt
 %type <tree.Statement> truncate_stmt
+%type <tree.Statement> unlisten_stmt
 %type <tree.Statement> update_stmt
 %type <tree.Statement> upsert_stmt
 %type <tree.Statement> use_stmt
@@ -1056,7 +1070,7 @@ func newNameFromStr(s string) *tree.Name {
 %nonassoc  '<' '>' '=' LESS_EQUALS GREATER_EQUALS NOT_EQUALS
 %nonassoc  '~' BETWEEN IN LIKE ILIKE SIMILAR NOT_REGMATCH REGIMATCH NOT_REGIMATCH NOT_LA
 %nonassoc  ESCAPE              // ESCAPE must be just above LIKE/ILIKE/SIMILAR
-%nonassoc  CONTAINS CONTAINED_BY '?' JSON_SOME_EXISTS JSON_ALL_EXISTS
+%nonassoc  CONTAINS CONTAINED_BY '?' JSON_SOME_EXISTS JSON_ALL_EXISTS TSMATCH
 %nonassoc  OVERLAPS
 %left      POSTFIXOP           // dummy for postfix OP rules
 // To support target_elem without AS, we must give IDENT an explicit priority
@@ -1126,10 +1140,17 @@ stmt:
 | deallocate_stmt   // EXTEND WITH HELP: DEALLOCATE
 | discard_stmt      // EXTEND WITH HELP: DISCARD
 | grant_stmt        // EXTEND WITH HELP: GRANT
+| listen_stmt       // EXTEND WITH HELP: LISTEN
+| notify_stmt       // EXTEND WITH HELP: NOTIFY
+| declare_cursor_stmt // EXTEND WITH HELP: DECLARE
+| fetch_cursor_stmt   // EXTEND WITH HELP: FETCH
+| move_cursor_stmt    // EXTEND WITH HELP: MOVE
+| close_cursor_stmt   // EXTEND WITH HELP: CLOSE
 | prepare_stmt      // EXTEND WITH HELP: PREPARE
 | revoke_stmt       // EXTEND WITH HELP: REVOKE
 | savepoint_stmt    // EXTEND WITH HELP: SAVEPOINT
 | release_stmt      // EXTEND WITH HELP: RELEASE
+| unlisten_stmt     // EXTEND WITH HELP: UNLISTEN
 | nonpreparable_set_stmt // help texts in sub-rule
 | transaction_stmt  // help texts in sub-rule
 | /* EMPTY */
@@ -2244,12 +2265,13 @@ comment_text:
 // %Text:
 // CREATE DATABASE, CREATE TABLE, CREATE INDEX, CREATE TABLE AS,
 // CREATE USER, CREATE VIEW, CREATE SEQUENCE, CREATE STATISTICS,
-// CREATE ROLE
+// CREATE STATEMENT HINTS, CREATE ROLE
 create_stmt:
   create_user_stmt     // EXTEND WITH HELP: CREATE USER
 | create_role_stmt     // EXTEND WITH HELP: CREATE ROLE
 | create_ddl_stmt      // help texts in sub-rule
 | create_stats_stmt    // EXTEND WITH HELP: CREATE STATISTICS
+| create_statement_hints_stmt // EXTEND WITH HELP: CREATE STATEMENT HINTS
 | create_unsupported   {}
 | CREATE error         // SHOW HELP: CREATE
 
@@ -2322,6 +2344,24 @@ create_ddl_stmt:
 | create_view_stmt     // EXTEND WITH HELP: CREATE VIEW
 | create_sequence_stmt // EXTEND WITH HELP: CREATE SEQUENCE
 
+// %Help: CREATE STATEMENT HINTS - bind optimizer hints to a statement
+// %Category: Misc
+// %Text:
+// CREATE STATEMENT HINTS FOR '<statement>' USING '<hints>'
+//
+// The hints are applied whenever a statement with the same
+// fingerprint as <statement> is planned. <hints> is a list of:
+//   INDEX(<table>, <index>)
+//   HASH_JOIN(<table>) | MERGE_JOIN(<table>) | LOOKUP_JOIN(<table>)
+//   NO_JOIN_REORDER
+// %SeeAlso: SHOW STATEMENT HINTS, DROP STATEMENT HINTS
+create_statement_hints_stmt:
+  CREATE STATEMENT HINTS FOR SCONST USING SCONST
+  {
+    $$.val = &tree.CreateStatementHints{Statement: $5, Hints: $7}
+  }
+| CREATE STATEMENT error // SHOW HELP: CREATE STATEMENT HINTS
+
 // %Help: CREATE STATISTICS - create a new table statistic
 // %Category: Misc
 // %Text:
@@ -2507,15 +2547,206 @@ discard_stmt:
 | DISCARD TEMPORARY { return unimplemented(sqllex, "discard temp") }
 | DISCARD error // SHOW HELP: DISCARD
 
+// %Help: LISTEN - start listening for notifications on a channel
+// %Category: Misc
+// %Text: LISTEN <channel>
+// %SeeAlso: UNLISTEN, NOTIFY
+listen_stmt:
+  LISTEN name
+  {
+    $$.val = &tree.Listen{Channel: tree.Name($2)}
+  }
+| LISTEN error // SHOW HELP: LISTEN
+
+// %Help: UNLISTEN - stop listening for notifications on a channel
+// %Category: Misc
+// %Text: UNLISTEN { <channel> | * }
+// %SeeAlso: LISTEN, NOTIFY
+unlisten_stmt:
+  UNLISTEN name
+  {
+    $$.val = &tree.Unlisten{Channel: tree.Name($2)}
+  }
+| UNLISTEN '*'
+  {
+    $$.val = &tree.Unlisten{}
+  }
+| UNLISTEN error // SHOW HELP: UNLISTEN
+
+// %Help: NOTIFY - generate a notification on a channel
+// %Category: Misc
+// %Text: NOTIFY <channel> [, <payload>]
+// %SeeAlso: LISTEN, UNLISTEN
+notify_stmt:
+  NOTIFY name
+  {
+    $$.val = &tree.Notify{Channel: tree.Name($2)}
+  }
+| NOTIFY name ',' SCONST
+  {
+    $$.val = &tree.Notify{Channel: tree.Name($2), Payload: $4}
+  }
+| NOTIFY error // SHOW HELP: NOTIFY
+
+// %Help: DECLARE - define a cursor
+// %Category: Misc
+// %Text: DECLARE <name> [NO SCROLL] CURSOR [WITHOUT HOLD] FOR <selectclause>
+// %SeeAlso: FETCH, MOVE, CLOSE, SELECT
+declare_cursor_stmt:
+  DECLARE name opt_no_scroll CURSOR opt_without_hold FOR select_stmt
+  {
+    $$.val = &tree.DeclareCursor{Name: tree.Name($2), Select: $7.slct()}
+  }
+| DECLARE error // SHOW HELP: DECLARE
+
+opt_no_scroll:
+  NO SCROLL {}
+| SCROLL
+  {
+    return unimplemented(sqllex, "scroll cursor")
+  }
+| /* EMPTY */ {}
+
+opt_without_hold:
+  WITHOUT HOLD {}
+| WITH HOLD
+  {
+    return unimplemented(sqllex, "cursor with hold")
+  }
+| /* EMPTY */ {}
+
+// %Help: FETCH - retrieve rows from a cursor
+// %Category: Misc
+// %Text:
+// FETCH [ <direction> [ FROM | IN ] ] <name>
+//
+// Direction:
+//   NEXT | PRIOR | FIRST | LAST | ABSOLUTE <count> | RELATIVE <count>
+//   | <count> | ALL | FORWARD | FORWARD <count> | FORWARD ALL
+//   | BACKWARD | BACKWARD <count> | BACKWARD ALL
+//
+// %SeeAlso: DECLARE, MOVE, CLOSE
+fetch_cursor_stmt:
+  FETCH cursor_movement_specifier
+  {
+    $$.val = &tree.FetchCursor{CursorStmt: $2.cursorStmt()}
+  }
+| FETCH error // SHOW HELP: FETCH
+
+// %Help: MOVE - reposition a cursor
+// %Category: Misc
+// %Text:
+// MOVE [ <direction> [ FROM | IN ] ] <name>
+//
+// Direction: see FETCH.
+//
+// %SeeAlso: DECLARE, FETCH, CLOSE
+move_cursor_stmt:
+  MOVE cursor_movement_specifier
+  {
+    $$.val = &tree.MoveCursor{CursorStmt: $2.cursorStmt()}
+  }
+| MOVE error // SHOW HELP: MOVE
+
+cursor_movement_specifier:
+  name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($1), Count: 1}
+  }
+| from_or_in name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($2), Count: 1}
+  }
+| NEXT opt_from_or_in name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($3), Count: 1}
+  }
+| PRIOR opt_from_or_in name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($3), Count: -1}
+  }
+| FIRST opt_from_or_in name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($3), FetchType: tree.FetchFirst}
+  }
+| LAST opt_from_or_in name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($3), FetchType: tree.FetchLast}
+  }
+| ABSOLUTE signed_iconst64 opt_from_or_in name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($4), FetchType: tree.FetchAbsolute, Count: $2.int64()}
+  }
+| RELATIVE signed_iconst64 opt_from_or_in name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($4), FetchType: tree.FetchRelative, Count: $2.int64()}
+  }
+| signed_iconst64 opt_from_or_in name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($3), Count: $1.int64()}
+  }
+| ALL opt_from_or_in name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($3), FetchType: tree.FetchAll}
+  }
+| FORWARD opt_from_or_in name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($3), Count: 1}
+  }
+| FORWARD signed_iconst64 opt_from_or_in name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($4), Count: $2.int64()}
+  }
+| FORWARD ALL opt_from_or_in name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($4), FetchType: tree.FetchAll}
+  }
+| BACKWARD opt_from_or_in name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($3), Count: -1}
+  }
+| BACKWARD signed_iconst64 opt_from_or_in name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($4), Count: -$2.int64()}
+  }
+| BACKWARD ALL opt_from_or_in name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($4), FetchType: tree.FetchBackwardAll}
+  }
+
+from_or_in:
+  FROM {}
+| IN {}
+
+opt_from_or_in:
+  from_or_in {}
+| /* EMPTY */ {}
+
+// %Help: CLOSE - close a cursor
+// %Category: Misc
+// %Text: CLOSE { <name> | ALL }
+// %SeeAlso: DECLARE, FETCH, MOVE
+close_cursor_stmt:
+  CLOSE name
+  {
+    $$.val = &tree.CloseCursor{Name: tree.Name($2)}
+  }
+| CLOSE ALL
+  {
+    $$.val = &tree.CloseCursor{All: true}
+  }
+| CLOSE error // SHOW HELP: CLOSE
+
 // %Help: DROP
 // %Category: Group
 // %Text:
 // DROP DATABASE, DROP INDEX, DROP TABLE, DROP VIEW, DROP SEQUENCE,
-// DROP USER, DROP ROLE
+// DROP USER, DROP ROLE, DROP STATEMENT HINTS
 drop_stmt:
   drop_ddl_stmt      // help texts in sub-rule
 | drop_role_stmt     // EXTEND WITH HELP: DROP ROLE
 | drop_user_stmt     // EXTEND WITH HELP: DROP USER
+| drop_statement_hints_stmt // EXTEND WITH HELP: DROP STATEMENT HINTS
 | drop_unsupported   {}
 | DROP error         // SHOW HELP: DROP
 
@@ -2556,6 +2787,17 @@ drop_sequence_stmt:
   }
 | DROP SEQUENCE error // SHOW HELP: DROP VIEW
 
+// %Help: DROP STATEMENT HINTS - remove the optimizer hints of a statement
+// %Category: Misc
+// %Text: DROP STATEMENT HINTS FOR '<statement>'
+// %SeeAlso: CREATE STATEMENT HINTS, SHOW STATEMENT HINTS
+drop_statement_hints_stmt:
+  DROP STATEMENT HINTS FOR SCONST
+  {
+    $$.val = &tree.DropStatementHints{Statement: $5}
+  }
+| DROP STATEMENT error // SHOW HELP: DROP STATEMENT HINTS
+
 // %Help: DROP TABLE - remove a table
 // %Category: DDL
 // %Text: DROP TABLE [IF EXISTS] <tablename> [, ...] [CASCADE | RESTRICT]
@@ -3359,7 +3601,8 @@ zone_value:
 // SHOW CREATE, SHOW DATABASES, SHOW HISTOGRAM, SHOW INDEXES, SHOW
 // PARTITIONS, SHOW JOBS, SHOW QUERIES, SHOW RANGE, SHOW RANGES,
 // SHOW ROLES, SHOW SCHEMAS, SHOW SEQUENCES, SHOW SESSION, SHOW SESSIONS,
-// SHOW STATISTICS, SHOW SYNTAX, SHOW TABLES, SHOW TRACE SHOW TRANSACTION, SHOW USERS
+// SHOW STATEMENT HINTS, SHOW STATISTICS, SHOW SYNTAX, SHOW TABLES,
+// SHOW TRACE SHOW TRANSACTION, SHOW USERS
 show_stmt:
   show_backup_stmt          // EXTEND WITH HELP: SHOW BACKUP
 | show_columns_stmt         // EXTEND WITH HELP: SHOW COLUMNS
@@ -3381,6 +3624,7 @@ show_stmt:
 | show_sequences_stmt       // EXTEND WITH HELP: SHOW SEQUENCES
 | show_session_stmt         // EXTEND WITH HELP: SHOW SESSION
 | show_sessions_stmt        // EXTEND WITH HELP: SHOW SESSIONS
+| show_statement_hints_stmt // EXTEND WITH HELP: SHOW STATEMENT HINTS
 | show_stats_stmt           // EXTEND WITH HELP: SHOW STATISTICS
 | show_syntax_stmt          // EXTEND WITH HELP: SHOW SYNTAX
 | show_tables_stmt          // EXTEND WITH HELP: SHOW TABLES
@@ -3436,6 +3680,17 @@ show_stats_stmt:
   }
 | SHOW STATISTICS error // SHOW HELP: SHOW STATISTICS
 
+// %Help: SHOW STATEMENT HINTS - display the optimizer hints bound to statements
+// %Category: Misc
+// %Text: SHOW STATEMENT HINTS
+// %SeeAlso: CREATE STATEMENT HINTS, DROP STATEMENT HINTS
+show_statement_hints_stmt:
+  SHOW STATEMENT HINTS
+  {
+    $$.val = &tree.ShowStatementHints{}
+  }
+| SHOW STATEMENT error // SHOW HELP: SHOW STATEMENT HINTS
+
 // %Help: SHOW HISTOGRAM - display histogram (experimental)
 // %Category: Experimental
 // %Text: SHOW HISTOGRAM <histogram_id>
@@ -7066,7 +7321,6 @@ typename:
       return setErr(sqllex, err)
     }
   }
-| simple_typename ARRAY '[' ICONST ']' '[' error { return unimplementedWithIssue(sqllex, 32552) }
 | simple_typename ARRAY {
     var err error
     $$.val, err = arrayOf($1.colType(), nil)
@@ -7082,20 +7336,16 @@ cast_target:
   }
 
 opt_array_bounds:
-  // TODO(justin): reintroduce multiple array bounds
-  // opt_array_bounds '[' ']' { $$.val = append($1.int32s(), -1) }
-  '[' ']' { $$.val = []int32{-1} }
-| '[' ']' '[' error { return unimplementedWithIssue(sqllex, 32552) }
-| '[' ICONST ']'
+  opt_array_bounds '[' ']' { $$.val = append($1.int32s(), -1) }
+| opt_array_bounds '[' ICONST ']'
   {
     /* SKIP DOC */
-    bound, err := $2.numVal().AsInt32()
+    bound, err := $3.numVal().AsInt32()
     if err != nil {
       return setErr(sqllex, err)
     }
-    $$.val = []int32{bound}
+    $$.val = append($1.int32s(), bound)
   }
-| '[' ICONST ']' '[' error { return unimplementedWithIssue(sqllex, 32552) }
 | /* EMPTY */ { $$.val = []int32(nil) }
 
 const_json:
@@ -7807,6 +8057,10 @@ a_expr:
   {
     $$.val = &tree.ComparisonExpr{Operator: tree.ContainedBy, Left: $1.expr(), Right: $3.expr()}
   }
+| a_expr TSMATCH a_expr
+  {
+    $$.val = &tree.ComparisonExpr{Operator: tree.TSMatches, Left: $1.expr(), Right: $3.expr()}
+  }
 | a_expr '=' a_expr
   {
     $$.val = &tree.ComparisonExpr{Operator: tree.EQ, Left: $1.expr(), Right: $3.expr()}
@@ -9671,6 +9925,7 @@ unrestricted_name:
 // "Unreserved" keywords --- available for use as any kind of name.
 unreserved_keyword:
   ABORT
+| ABSOLUTE
 | ACTION
 | ADD
 | ADMIN
@@ -9680,6 +9935,7 @@ unreserved_keyword:
 | AUTOMATIC
 | AUTHORIZATION
 | BACKUP
+| BACKWARD
 | BEGIN
 | BIGSERIAL
 | BLOB
@@ -9692,6 +9948,7 @@ unreserved_keyword:
 | CANCEL
 | CASCADE
 | CHANGEFEED
+| CLOSE
 | CLUSTER
 | COLUMNS
 | COMMENT
@@ -9709,6 +9966,7 @@ unreserved_keyword:
 | COVERING
 | CUBE
 | CURRENT
+| CURSOR
 | CYCLE
 | DATA
 | DATABASE
@@ -9716,6 +9974,7 @@ unreserved_keyword:
 | DATE
 | DAY
 | DEALLOCATE
+| DECLARE
 | DELETE
 | DEFERRED
 | DISCARD
@@ -9743,13 +10002,16 @@ unreserved_keyword:
 | FLOAT8
 | FOLLOWING
 | FORCE_INDEX
+| FORWARD
 | FUNCTION
 | GLOBAL
 | GRANTS
 | GROUPS
 | HASH
 | HIGH
+| HINTS
 | HISTOGRAM
+| HOLD
 | HOUR
 | IMMEDIATE
 | IMPORT
@@ -9782,6 +10044,7 @@ unreserved_keyword:
 | LESS
 | LEVEL
 | LIST
+| LISTEN
 | LOCAL
 | LOCKED
 | LOOKUP
@@ -9793,6 +10056,7 @@ unreserved_keyword:
 | MINUTE
 | MINVALUE
 | MONTH
+| MOVE
 | NAMES
 | NAN
 | NAME
@@ -9800,6 +10064,7 @@ unreserved_keyword:
 | NO
 | NORMAL
 | NO_INDEX_JOIN
+| NOTIFY
 | NOWAIT
 | NULLS
 | IGNORE_FOREIGN_KEYS
@@ -9827,6 +10092,7 @@ unreserved_keyword:
 | PLANS
 | PRECEDING
 | PREPARE
+| PRIOR
 | PRIORITY
 | PUBLIC
 | PUBLICATION
@@ -9842,6 +10108,7 @@ unreserved_keyword:
 | REGPROCEDURE
 | REGNAMESPACE
 | REGTYPE
+| RELATIVE
 | RELEASE
 | RENAME
 | REPEATABLE
@@ -9864,6 +10131,7 @@ unreserved_keyword:
 | SCATTER
 | SCHEMA
 | SCHEMAS
+| SCROLL
 | SCRUB
 | SEARCH
 | SECOND
@@ -9887,6 +10155,7 @@ unreserved_keyword:
 | SPLIT
 | SQL
 | START
+| STATEMENT
 | STATISTICS
 | STDIN
 | STORE
@@ -9914,6 +10183,7 @@ unreserved_keyword:
 | UNBOUNDED
 | UNCOMMITTED
 | UNKNOWN
+| UNLISTEN
 | UNLOGGED
 | UNSPLIT
 | UPDATE
@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"hash"
 	"hash/fnv"
+	"sort"
 	"strings"
 	"time"
 	"unicode"
@@ -205,6 +206,7 @@ var pgCatalog = virtualSchema{
 		sqlbase.PgCatalogCollationTableID:           pgCatalogCollationTable,
 		sqlbase.PgCatalogConstraintTableID:          pgCatalogConstraintTable,
 		sqlbase.PgCatalogConversionTableID:          pgCatalogConversionTable,
+		sqlbase.PgCatalogCursorsTableID:             pgCatalogCursorsTable,
 		sqlbase.PgCatalogDatabaseTableID:            pgCatalogDatabaseTable,
 		sqlbase.PgCatalogDefaultACLTableID:          pgCatalogDefaultACLTable,
 		sqlbase.PgCatalogDependTableID:              pgCatalogDependTable,
@@ -1055,6 +1057,42 @@ CREATE TABLE pg_catalog.pg_conversion (
 	},
 }
 
+var pgCatalogCursorsTable = virtualSchemaTable{
+	comment: `open cursors
+https://www.postgresql.org/docs/current/view-pg-cursors.html`,
+	schema: `
+CREATE TABLE pg_catalog.pg_cursors (
+	name TEXT,
+	statement TEXT,
+	is_holdable BOOL,
+	is_binary BOOL,
+	is_scrollable BOOL,
+	creation_time TIMESTAMPTZ
+)`,
+	populate: func(ctx context.Context, p *planner, dbContext *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		cursors := p.extendedEvalCtx.SQLCursors.cursors
+		names := make([]string, 0, len(cursors))
+		for name := range cursors {
+			names = append(names, string(name))
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			c := cursors[tree.Name(name)]
+			if err := addRow(
+				tree.NewDString(name),
+				tree.NewDString(c.stmt),
+				tree.DBoolFalse,
+				tree.DBoolFalse,
+				tree.DBoolFalse,
+				tree.MakeDTimestampTZ(c.created, time.Microsecond),
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
 var pgCatalogDatabaseTable = virtualSchemaTable{
 	comment: `available databases (incomplete)
 https://www.postgresql.org/docs/9.5/catalog-pg-database.html`,
@@ -2764,6 +2802,8 @@ var datumToTypeCategory = map[types.Family]*tree.DString{
 	types.OidFamily:         typCategoryNumeric,
 	types.UuidFamily:        typCategoryUserDefined,
 	types.INetFamily:        typCategoryNetworkAddr,
+	types.TSVectorFamily:    typCategoryUserDefined,
+	types.TSQueryFamily:     typCategoryUserDefined,
 	types.UnknownFamily:     typCategoryUnknown,
 }
 
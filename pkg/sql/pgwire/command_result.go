@@ -21,7 +21,6 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
-	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
 )
 
@@ -321,20 +320,15 @@ func (c *conn) newMiscResult(pos sql.CmdPos, typ completionMsgType) *commandResu
 // rows. It essentially implements the "execute portal with limit" part of the
 // Postgres protocol.
 //
-// This design is known to be flawed. It only supports a specific subset of the
-// protocol. We only allow a portal suspension in an explicit transaction where
-// the suspended portal is completely exhausted before any other pgwire command
-// is executed, otherwise an error is produced. You cannot, for example,
-// interleave portal executions (a portal must be executed to completion before
-// another can be executed). It also breaks the software layering by adding an
-// additional state machine here, instead of teaching the state machine in the
-// sql package about portals.
-//
-// This has been done because refactoring the executor to be able to correctly
-// suspend a portal will require a lot of work, and we wanted to move
-// forward. The work included is things like auditing all of the defers and
-// post-execution stuff (like stats collection) to have it only execute once
-// per statement instead of once per portal.
+// While the portal is suspended, the connection's commands are peeked at
+// directly from here, without the involvement of the connExecutor: requests
+// for more rows from the same portal resume the execution in place. If the
+// client executes any other command instead, the command is left in the
+// stmtBuf and sql.ErrLimitedResultSuspended is returned, which tells the sql
+// layer to run the portal's statement to completion and to buffer its
+// remaining rows with the portal. This allows the execution of multiple
+// portals to be interleaved, at the cost of buffering the results of all but
+// the most recently executed one.
 type limitedCommandResult struct {
 	*commandResult
 	portalName  string
@@ -399,9 +393,7 @@ func (r *limitedCommandResult) moreResultsNeeded(ctx context.Context) error {
 			// the cleanup. We are in effect peeking to see if the
 			// next message is a delete portal.
 			if c.Type != pgwirebase.PreparePortal || c.Name != r.portalName {
-				telemetry.Inc(sqltelemetry.InterleavedPortalRequestCounter)
-				return errors.WithDetail(sql.ErrLimitedResultNotSupported,
-					"cannot close a portal while a different one is open")
+				return r.suspend(ctx, prevPos)
 			}
 			r.typ = noCompletionMsg
 			// Rewind to before the delete so the AdvanceOne in
@@ -411,9 +403,7 @@ func (r *limitedCommandResult) moreResultsNeeded(ctx context.Context) error {
 		case sql.ExecPortal:
 			// The happy case: the client wants more rows from the portal.
 			if c.Name != r.portalName {
-				telemetry.Inc(sqltelemetry.InterleavedPortalRequestCounter)
-				return errors.WithDetail(sql.ErrLimitedResultNotSupported,
-					"cannot execute a portal while a different one is open")
+				return r.suspend(ctx, prevPos)
 			}
 			r.limit = c.Limit
 			// In order to get the correct command tag, we need to reset the seen rows.
@@ -430,13 +420,30 @@ func (r *limitedCommandResult) moreResultsNeeded(ctx context.Context) error {
 			if err := r.conn.Flush(r.pos); err != nil {
 				return err
 			}
+		case sql.Flush:
+			// The client wants the buffered results to be sent. Flush them
+			// then run the for loop again.
+			r.conn.stmtBuf.AdvanceOne()
+			if err := r.conn.Flush(r.pos); err != nil {
+				return err
+			}
 		default:
-			// We got some other message, but we only support executing to completion.
-			telemetry.Inc(sqltelemetry.InterleavedPortalRequestCounter)
-			return errors.WithSafeDetails(sql.ErrLimitedResultNotSupported,
-				"cannot perform operation %T while a different portal is open",
-				errors.Safe(c))
+			// The client wants to do something else while the portal is
+			// suspended.
+			return r.suspend(ctx, prevPos)
 		}
 		prevPos = curPos
 	}
 }
+
+// suspend is called when the client executes a command other than the ones
+// that resume or close this portal while it is suspended. The stmtBuf is
+// rewound to before that command, so that it is executed by the connExecutor
+// once the portal's statement is done, and the sql layer is told to buffer the
+// rest of the portal's rows.
+func (r *limitedCommandResult) suspend(ctx context.Context, prevPos sql.CmdPos) error {
+	telemetry.Inc(sqltelemetry.InterleavedPortalRequestCounter)
+	r.typ = noCompletionMsg
+	r.conn.stmtBuf.Rewind(ctx, prevPos)
+	return sql.ErrLimitedResultSuspended
+}
@@ -582,6 +582,16 @@ func (c *conn) bufferStatusParam(param, value string) error {
 	return c.msgBuilder.finishMsg(&c.writerState.buf)
 }
 
+// bufferNotification appends a NotificationResponse message to the write
+// buffer. The message is sent to the client with the next flush.
+func (c *conn) bufferNotification(n sql.Notification) error {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgNotificationResponse)
+	c.msgBuilder.putInt32(int32(n.NodeID))
+	c.msgBuilder.writeTerminatedString(n.Channel)
+	c.msgBuilder.writeTerminatedString(n.Payload)
+	return c.msgBuilder.finishMsg(&c.writerState.buf)
+}
+
 func (c *conn) sendInitialConnData(
 	ctx context.Context, sqlServer *sql.Server,
 ) (sql.ConnectionHandler, error) {
@@ -611,6 +621,13 @@ func (c *conn) sendInitialConnData(
 			}
 		})
 	}
+	// Notifications received by the session through LISTEN are sent
+	// asynchronously, in between the execution of commands.
+	connHandler.RegisterOnNotification(func(n sql.Notification) {
+		if err := c.bufferNotification(n); err != nil {
+			panic(fmt.Sprintf("unexpected error when trying to send notification: %s", err.Error()))
+		}
+	})
 	// The two following status parameters have no equivalent session
 	// variable.
 	if err := c.sendStatusParam("session_authorization", c.sessionArgs.User); err != nil {
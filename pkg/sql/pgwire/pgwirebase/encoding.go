@@ -32,6 +32,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uint128"
 	"github.com/cockroachdb/errors"
 	"github.com/jackc/pgx/pgtype"
@@ -372,6 +373,16 @@ func DecodeOidDatum(
 				return nil, err
 			}
 			return tree.ParseDJSON(string(b))
+		case oid.T_tsvector:
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			return tree.ParseDTSVector(string(b))
+		case oid.T_tsquery:
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			return tree.ParseDTSQuery(string(b))
 		}
 		if _, ok := types.ArrayOids[id]; ok {
 			// Arrays come in in their string form, so we parse them as such and later
@@ -576,6 +587,18 @@ func DecodeOidDatum(
 				return nil, err
 			}
 			return tree.ParseDJSON(string(b))
+		case oid.T_tsvector:
+			v, err := tsearch.DecodeTSVectorBinary(b)
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDTSVector(v), nil
+		case oid.T_tsquery:
+			q, err := tsearch.DecodeTSQueryBinary(b)
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDTSQuery(q), nil
 		case oid.T_varbit, oid.T_bit:
 			if len(b) < 4 {
 				return nil, NewProtocolViolationErrorf("insufficient data: %d", len(b))
@@ -745,9 +768,6 @@ func decodeBinaryArray(
 		ElemOid int32
 	}
 	var dim struct {
-		// The next two fields should be arrays of size Ndims. However, since
-		// we only support 1-dimensional arrays for now, for convenience we can
-		// leave them in this struct as such for `binary.Read` to parse for us.
 		DimSize int32
 		// Dim lower bound
 		_ int32
@@ -759,18 +779,45 @@ func decodeBinaryArray(
 	if elemOid != oid.Oid(hdr.ElemOid) {
 		return nil, pgerror.Newf(pgcode.DatatypeMismatch, "wrong element type")
 	}
-	arr := tree.NewDArray(types.OidToType[elemOid])
 	if hdr.Ndims == 0 {
-		return arr, nil
+		return tree.NewDArray(types.OidToType[elemOid]), nil
 	}
-	if err := binary.Read(r, binary.BigEndian, &dim); err != nil {
-		return nil, err
+	if hdr.Ndims < 0 {
+		return nil, NewInvalidBinaryRepresentationErrorf("invalid number of array dimensions: %d", hdr.Ndims)
 	}
-	if err := validateArrayDimensions(int(hdr.Ndims), int(dim.DimSize)); err != nil {
-		return nil, err
+	dims := make([]int32, hdr.Ndims)
+	for i := range dims {
+		if err := binary.Read(r, binary.BigEndian, &dim); err != nil {
+			return nil, err
+		}
+		dims[i] = dim.DimSize
 	}
+	return decodeBinaryArrayElements(ctx, r, elemOid, code, dims)
+}
+
+// decodeBinaryArrayElements decodes an array with the given dimensions out of
+// the elements in r, which are in row-major order. Multidimensional arrays are
+// decoded as nested arrays.
+func decodeBinaryArrayElements(
+	ctx tree.ParseTimeContext, r *bytes.Buffer, elemOid oid.Oid, code FormatCode, dims []int32,
+) (*tree.DArray, error) {
+	typ := types.OidToType[elemOid]
+	for range dims[1:] {
+		typ = types.MakeArray(typ)
+	}
+	arr := tree.NewDArray(typ)
 	var vlen int32
-	for i := int32(0); i < dim.DimSize; i++ {
+	for i := int32(0); i < dims[0]; i++ {
+		if len(dims) > 1 {
+			sub, err := decodeBinaryArrayElements(ctx, r, elemOid, code, dims[1:])
+			if err != nil {
+				return nil, err
+			}
+			if err := arr.Append(sub); err != nil {
+				return nil, err
+			}
+			continue
+		}
 		if err := binary.Read(r, binary.BigEndian, &vlen); err != nil {
 			return nil, err
 		}
@@ -29,7 +29,6 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
-	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
@@ -180,6 +179,12 @@ func (b *writeBuffer) writeTextDatum(
 	case *tree.DJSON:
 		b.writeLengthPrefixedString(v.JSON.String())
 
+	case *tree.DTSVector:
+		b.writeLengthPrefixedString(v.TSVector.String())
+
+	case *tree.DTSQuery:
+		b.writeLengthPrefixedString(v.TSQuery.String())
+
 	case *tree.DTuple:
 		b.textFormatter.FormatNode(v)
 		b.writeFromFmtCtx(b.textFormatter)
@@ -450,31 +455,36 @@ func (b *writeBuffer) writeBinaryDatum(
 		b.writeLengthPrefixedBuffer(&subWriter.wrapped)
 
 	case *tree.DArray:
-		if v.ParamTyp.Family() == types.ArrayFamily {
-			b.setError(unimplemented.NewWithIssueDetail(32552,
-				"binenc", "unsupported binary serialization of multidimensional arrays"))
-			return
-		}
 		// TODO(andrei): We shouldn't be allocating a new buffer for every array.
 		subWriter := newWriteBuffer(nil /* bytecount */)
-		// Put the number of dimensions. We currently support 1d arrays only.
-		var ndims int32 = 1
-		if v.Len() == 0 {
-			ndims = 0
+		// Put the number of dimensions. Empty arrays have zero dimensions.
+		dims := v.Dimensions()
+		for _, l := range dims {
+			if l == 0 {
+				dims = nil
+				break
+			}
+		}
+		subWriter.putInt32(int32(len(dims)))
+		elems, hasNulls := v.Flatten()
+		hasNullsFlag := 0
+		if hasNulls {
+			hasNullsFlag = 1
 		}
-		subWriter.putInt32(ndims)
-		hasNulls := 0
-		if v.HasNulls {
-			hasNulls = 1
+		elemTyp := v.ParamTyp
+		for elemTyp.Family() == types.ArrayFamily {
+			elemTyp = elemTyp.ArrayContents()
 		}
-		oid := v.ParamTyp.Oid()
-		subWriter.putInt32(int32(hasNulls))
+		oid := elemTyp.Oid()
+		subWriter.putInt32(int32(hasNullsFlag))
 		subWriter.putInt32(int32(oid))
-		if v.Len() > 0 {
-			subWriter.putInt32(int32(v.Len()))
+		for _, l := range dims {
+			subWriter.putInt32(int32(l))
 			// Lower bound, we only support a lower bound of 1.
 			subWriter.putInt32(1)
-			for _, elem := range v.Array {
+		}
+		if len(dims) > 0 {
+			for _, elem := range elems {
 				subWriter.writeBinaryDatum(ctx, elem, sessionLoc, oid)
 			}
 		}
@@ -485,6 +495,14 @@ func (b *writeBuffer) writeBinaryDatum(
 		// Postgres version number, as of writing, `1` is the only valid value.
 		b.writeByte(1)
 		b.writeString(s)
+	case *tree.DTSVector:
+		s := v.TSVector.EncodeBinary(nil)
+		b.putInt32(int32(len(s)))
+		b.write(s)
+	case *tree.DTSQuery:
+		s := v.TSQuery.EncodeBinary(nil)
+		b.putInt32(int32(len(s)))
+		b.write(s)
 	case *tree.DOid:
 		b.putInt32(4)
 		b.putInt32(int32(v.DInt))
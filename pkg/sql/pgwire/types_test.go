@@ -142,6 +142,31 @@ func TestIntArrayRoundTrip(t *testing.T) {
 	}
 }
 
+func TestNestedArrayBinaryRoundTrip(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	evalCtx := tree.NewTestingEvalContext(cluster.MakeTestingClusterSettings())
+	defer evalCtx.Stop(context.Background())
+	d, err := tree.ParseDArrayFromString(evalCtx, "{{1,2,3},{4,NULL,6}}", types.IntArray)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := newWriteBuffer(nil /* bytecount */)
+	buf.bytecount = metric.NewCounter(metric.Metadata{})
+	buf.writeBinaryDatum(context.Background(), d, time.UTC, 0 /* oid */)
+
+	b := buf.wrapped.Bytes()
+
+	got, err := pgwirebase.DecodeOidDatum(nil, oid.T__int8, pgwirebase.FormatBinary, b[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Compare(evalCtx, d) != 0 {
+		t.Fatalf("expected %s, got %s", d, got)
+	}
+}
+
 func TestFloatConversion(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -133,7 +133,7 @@ func (o *randomOracle) Oracle(_ *client.Txn) Oracle {
 func (o *randomOracle) ChoosePreferredReplica(
 	ctx context.Context, desc roachpb.RangeDescriptor, _ QueryState,
 ) (kv.ReplicaInfo, error) {
-	replicas, err := replicaSliceOrErr(desc, o.gossip)
+	replicas, err := replicaSliceOrErr(desc, o.gossip, false /* includeNonVoters */)
 	if err != nil {
 		return kv.ReplicaInfo{}, err
 	}
@@ -163,7 +163,9 @@ func (o *closestOracle) Oracle(_ *client.Txn) Oracle {
 func (o *closestOracle) ChoosePreferredReplica(
 	ctx context.Context, desc roachpb.RangeDescriptor, queryState QueryState,
 ) (kv.ReplicaInfo, error) {
-	replicas, err := replicaSliceOrErr(desc, o.gossip)
+	// The closest oracle is used for follower reads, which can also be served
+	// by non-voting replicas.
+	replicas, err := replicaSliceOrErr(desc, o.gossip, true /* includeNonVoters */)
 	if err != nil {
 		return kv.ReplicaInfo{}, err
 	}
@@ -238,7 +240,7 @@ func (o *binPackingOracle) ChoosePreferredReplica(
 		return repl, nil
 	}
 
-	replicas, err := replicaSliceOrErr(desc, o.gossip)
+	replicas, err := replicaSliceOrErr(desc, o.gossip, false /* includeNonVoters */)
 	if err != nil {
 		return kv.ReplicaInfo{}, err
 	}
@@ -266,17 +268,22 @@ func (o *binPackingOracle) ChoosePreferredReplica(
 // replicaSliceOrErr returns a ReplicaSlice for the given range descriptor.
 // ReplicaSlices are restricted to replicas on nodes for which a NodeDescriptor
 // is available in gossip. If no nodes are available, a RangeUnavailableError is
-// returned.
-func replicaSliceOrErr(desc roachpb.RangeDescriptor, gsp *gossip.Gossip) (kv.ReplicaSlice, error) {
+// returned. Non-voting replicas are only included if includeNonVoters is set.
+func replicaSliceOrErr(
+	desc roachpb.RangeDescriptor, gsp *gossip.Gossip, includeNonVoters bool,
+) (kv.ReplicaSlice, error) {
 	// Learner replicas won't serve reads/writes, so send only to the `Voters`
 	// replicas. This is just an optimization to save a network hop, everything
 	// would still work if we had `All` here.
-	voterReplicas := desc.Replicas().Voters()
-	replicas := kv.NewReplicaSlice(gsp, voterReplicas)
+	replicaDescs := desc.Replicas().Voters()
+	if includeNonVoters {
+		replicaDescs = desc.Replicas().VotersAndNonVoters()
+	}
+	replicas := kv.NewReplicaSlice(gsp, replicaDescs)
 	if len(replicas) == 0 {
 		// We couldn't get node descriptors for any replicas.
 		var nodeIDs []roachpb.NodeID
-		for _, r := range voterReplicas {
+		for _, r := range replicaDescs {
 			nodeIDs = append(nodeIDs, r.NodeID)
 		}
 		return kv.ReplicaSlice{}, sqlbase.NewRangeUnavailableError(
@@ -12,9 +12,12 @@ package sql
 
 import (
 	"context"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec/execbuilder"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/optbuilder"
@@ -24,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/querycache"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 )
 
@@ -71,6 +75,10 @@ func (p *planner) prepareUsingOptimizer(ctx context.Context) (planFlags, error)
 		return opc.flags, nil
 	}
 
+	if err := opc.resolveStatementHints(ctx); err != nil {
+		return 0, err
+	}
+
 	if opc.useCache {
 		cachedData, ok := p.execCfg.QueryCache.Find(&p.queryCacheSession, stmt.SQL)
 		if ok && cachedData.PrepareMetadata != nil {
@@ -79,7 +87,7 @@ func (p *planner) prepareUsingOptimizer(ctx context.Context) (planFlags, error)
 			if !pm.TypeHints.Equals(p.semaCtx.Placeholders.TypeHints) {
 				opc.log(ctx, "query cache hit but type hints don't match")
 			} else {
-				isStale, err := cachedData.Memo.IsStale(ctx, p.EvalContext(), &opc.catalog)
+				isStale, err := opc.memoIsStale(ctx, cachedData.Memo)
 				if err != nil {
 					return 0, err
 				}
@@ -156,15 +164,22 @@ func (p *planner) makeOptimizerPlan(ctx context.Context) error {
 	opc := &p.optPlanningCtx
 	opc.reset()
 
-	execMemo, err := opc.buildExecMemo(ctx)
-	if err != nil {
+	if err := opc.resolveStatementHints(ctx); err != nil {
 		return err
 	}
 
-	// Build the plan tree.
-	root := execMemo.RootExpr()
-	execFactory := makeExecFactory(p)
-	plan, err := execbuilder.New(&execFactory, execMemo, &opc.catalog, root, p.EvalContext()).Build()
+	plan, err := opc.buildExecPlan(ctx)
+	if err != nil && opc.hints != nil {
+		// The statement hints may no longer be satisfiable after a schema
+		// change, e.g. a LOOKUP_JOIN hint after the index used for the lookup
+		// join was dropped. Plan the statement without them.
+		telemetry.Inc(sqltelemetry.StaleStatementHintsCounter)
+		if staleStatementHintsLogLimiter.ShouldLog() {
+			log.Warningf(ctx, "ignoring statement hints %s: %v", opc.hints, err)
+		}
+		opc.reset()
+		plan, err = opc.buildExecPlan(ctx)
+	}
 	if err != nil {
 		return err
 	}
@@ -185,6 +200,23 @@ func (p *planner) makeOptimizerPlan(ctx context.Context) error {
 	return nil
 }
 
+// staleStatementHintsLogLimiter limits the rate at which statement hints that
+// can't be satisfied are logged.
+var staleStatementHintsLogLimiter = log.Every(time.Minute)
+
+// buildExecPlan builds the memo for the statement and the plan tree from it.
+func (opc *optPlanningCtx) buildExecPlan(ctx context.Context) (exec.Plan, error) {
+	execMemo, err := opc.buildExecMemo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build the plan tree.
+	root := execMemo.RootExpr()
+	execFactory := makeExecFactory(opc.p)
+	return execbuilder.New(&execFactory, execMemo, &opc.catalog, root, opc.p.EvalContext()).Build()
+}
+
 type optPlanningCtx struct {
 	p *planner
 
@@ -203,6 +235,10 @@ type optPlanningCtx struct {
 	// allowMemoReuse is false.
 	useCache bool
 
+	// hints are the statement hints bound to the fingerprint of the statement,
+	// if any (see resolveStatementHints).
+	hints *tree.StatementHints
+
 	flags planFlags
 }
 
@@ -219,6 +255,7 @@ func (opc *optPlanningCtx) reset() {
 	opc.catalog.reset()
 	opc.optimizer.Init(p.EvalContext(), &opc.catalog)
 	opc.flags = 0
+	opc.hints = nil
 
 	// We only allow memo caching for SELECT/INSERT/UPDATE/DELETE. We could
 	// support it for all statements in principle, but it would increase the
@@ -248,6 +285,27 @@ func (opc *optPlanningCtx) reset() {
 	}
 }
 
+// resolveStatementHints looks up the statement hints bound to the fingerprint
+// of the statement, which are applied when the memo is built.
+func (opc *optPlanningCtx) resolveStatementHints(ctx context.Context) error {
+	hints, err := opc.p.lookupStatementHints(ctx)
+	if err != nil {
+		return err
+	}
+	opc.hints = hints
+	return nil
+}
+
+// memoIsStale returns true if a cached memo can't be reused, either because it
+// is stale (see memo.IsStale) or because it was built with different statement
+// hints.
+func (opc *optPlanningCtx) memoIsStale(ctx context.Context, m *memo.Memo) (bool, error) {
+	if m.StatementHints().String() != opc.hints.String() {
+		return true, nil
+	}
+	return m.IsStale(ctx, opc.p.EvalContext(), &opc.catalog)
+}
+
 func (opc *optPlanningCtx) log(ctx context.Context, msg string) {
 	if log.VDepth(1, 1) {
 		log.InfofDepth(ctx, 1, "%s: %s", msg, opc.p.stmt)
@@ -293,6 +351,7 @@ func (opc *optPlanningCtx) buildReusableMemo(ctx context.Context) (_ *memo.Memo,
 	f := opc.optimizer.Factory()
 	bld := optbuilder.New(ctx, &p.semaCtx, p.EvalContext(), &opc.catalog, f, opc.p.stmt.AST)
 	bld.KeepPlaceholders = true
+	bld.StatementHints = opc.hints
 	if err := bld.Build(); err != nil {
 		return nil, err
 	}
@@ -368,7 +427,7 @@ func (opc *optPlanningCtx) buildExecMemo(ctx context.Context) (_ *memo.Memo, _ e
 
 		// If the prepared memo has been invalidated by schema or other changes,
 		// re-prepare it.
-		if isStale, err := prepared.Memo.IsStale(ctx, p.EvalContext(), &opc.catalog); err != nil {
+		if isStale, err := opc.memoIsStale(ctx, prepared.Memo); err != nil {
 			return nil, err
 		} else if isStale {
 			prepared.Memo, err = opc.buildReusableMemo(ctx)
@@ -386,7 +445,7 @@ func (opc *optPlanningCtx) buildExecMemo(ctx context.Context) (_ *memo.Memo, _ e
 		// Consult the query cache.
 		cachedData, ok := p.execCfg.QueryCache.Find(&p.queryCacheSession, opc.p.stmt.SQL)
 		if ok {
-			if isStale, err := cachedData.Memo.IsStale(ctx, p.EvalContext(), &opc.catalog); err != nil {
+			if isStale, err := opc.memoIsStale(ctx, cachedData.Memo); err != nil {
 				return nil, err
 			} else if isStale {
 				cachedData.Memo, err = opc.buildReusableMemo(ctx)
@@ -414,6 +473,7 @@ func (opc *optPlanningCtx) buildExecMemo(ctx context.Context) (_ *memo.Memo, _ e
 	// available.
 	f := opc.optimizer.Factory()
 	bld := optbuilder.New(ctx, &p.semaCtx, p.EvalContext(), &opc.catalog, f, opc.p.stmt.AST)
+	bld.StatementHints = opc.hints
 	if err := bld.Build(); err != nil {
 		return nil, err
 	}
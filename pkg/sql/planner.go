@@ -71,6 +71,12 @@ type extendedEvalContext struct {
 
 	Jobs *jobsCollection
 
+	// Notifications holds the session's LISTEN/NOTIFY state.
+	Notifications *sessionNotifications
+
+	// SQLCursors contains the cursors declared in the session's transaction.
+	SQLCursors *sqlCursors
+
 	schemaAccessors *schemaInterface
 
 	sqlStatsCollector *sqlStatsCollector
@@ -349,6 +355,8 @@ func internalExtendedEvalCtx(
 		ExecCfg:         execCfg,
 		schemaAccessors: newSchemaInterface(tables, execCfg.VirtualSchemas),
 		SchemaChangers:  &schemaChangerCollection{},
+		Notifications:   &sessionNotifications{},
+		SQLCursors:      &sqlCursors{},
 		DistSQLPlanner:  execCfg.DistSQLPlanner,
 	}
 }
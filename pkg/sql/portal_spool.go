@@ -0,0 +1,165 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+)
+
+// portalSpool buffers the rows of a suspended portal whose statement had to be
+// run to completion before the client consumed all of its results.
+//
+// A portal executed with a row limit is suspended once the limit is reached,
+// at which point the execution of its statement is paused (see
+// pgwire.limitedCommandResult) until the client either asks for more rows or
+// closes the portal. If the client executes any other command instead, the
+// statement is run to completion and the rows it still produces are buffered
+// in the portal's spool, from which the subsequent executions of the portal are
+// served. This allows clients to interleave the execution of multiple portals
+// within a transaction.
+//
+// The rows are kept in memory up to the distsql working memory limit, past
+// which they are spilled to temporary storage.
+type portalSpool struct {
+	rows rowcontainer.DiskBackedRowContainer
+	// memMonitor and diskMonitor are rows' monitors.
+	memMonitor  *mon.BytesMonitor
+	diskMonitor *mon.BytesMonitor
+
+	types      []types.T
+	scratch    sqlbase.EncDatumRow
+	datums     tree.Datums
+	datumAlloc sqlbase.DatumAlloc
+}
+
+func newPortalSpool(
+	ctx context.Context,
+	evalCtx *tree.EvalContext,
+	cfg *execinfra.ServerConfig,
+	sessionMon *mon.BytesMonitor,
+	cols sqlbase.ResultColumns,
+) *portalSpool {
+	s := &portalSpool{
+		memMonitor:  execinfra.NewLimitedMonitor(ctx, sessionMon, cfg, "portal-spool-mem"),
+		diskMonitor: execinfra.NewMonitor(ctx, cfg.DiskMonitor, "portal-spool-disk"),
+		types:       make([]types.T, len(cols)),
+		scratch:     make(sqlbase.EncDatumRow, len(cols)),
+		datums:      make(tree.Datums, len(cols)),
+	}
+	for i := range cols {
+		s.types[i] = *cols[i].Typ
+	}
+	s.rows.Init(
+		nil, /* ordering */
+		s.types,
+		evalCtx,
+		cfg.TempStorage,
+		s.memMonitor,
+		s.diskMonitor,
+		0, /* rowCapacity */
+	)
+	return s
+}
+
+func (s *portalSpool) addRow(ctx context.Context, row tree.Datums) error {
+	for i := range row {
+		s.scratch[i] = sqlbase.DatumToEncDatum(&s.types[i], row[i])
+	}
+	return s.rows.AddRow(ctx, s.scratch)
+}
+
+// serve returns the spooled rows to res, until either the spool is exhausted
+// or res stops accepting rows because the portal got suspended or closed
+// again.
+func (s *portalSpool) serve(ctx context.Context, res RestrictedCommandResult) error {
+	// The final iterator discards the rows as they are consumed, and a new one
+	// resumes after the last row returned by the previous one.
+	i := s.rows.NewFinalIterator(ctx)
+	defer i.Close()
+	for i.Rewind(); ; i.Next() {
+		if ok, err := i.Valid(); err != nil {
+			return err
+		} else if !ok {
+			return nil
+		}
+		row, err := i.Row()
+		if err != nil {
+			return err
+		}
+		for j := range row {
+			if err := row[j].EnsureDecoded(&s.types[j], &s.datumAlloc); err != nil {
+				return err
+			}
+			s.datums[j] = row[j].Datum
+		}
+		if err := res.AddRow(ctx, s.datums); err != nil {
+			if errors.Is(err, ErrLimitedResultSuspended) || errors.Is(err, ErrLimitedResultClosed) {
+				// The row has been delivered to the client, so it must not be
+				// returned again.
+				i.Next()
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func (s *portalSpool) close(ctx context.Context) {
+	s.rows.Close(ctx)
+	s.memMonitor.Stop(ctx)
+	s.diskMonitor.Stop(ctx)
+}
+
+// portalResult wraps the result of the execution of a portal. When the client
+// interleaves other commands with the portal's execution (which is signaled by
+// ErrLimitedResultSuspended), it diverts the remaining rows to the portal's
+// spool so that the portal's statement can finish executing.
+type portalResult struct {
+	RestrictedCommandResult
+	portal *PreparedPortal
+	cols   sqlbase.ResultColumns
+
+	// The following fields are used to create the spool.
+	evalCtx    *tree.EvalContext
+	distSQLCfg *execinfra.ServerConfig
+	sessionMon *mon.BytesMonitor
+}
+
+var _ RestrictedCommandResult = &portalResult{}
+
+// SetColumns is part of the RestrictedCommandResult interface.
+func (r *portalResult) SetColumns(ctx context.Context, cols sqlbase.ResultColumns) {
+	r.cols = cols
+	r.RestrictedCommandResult.SetColumns(ctx, cols)
+}
+
+// AddRow is part of the RestrictedCommandResult interface.
+func (r *portalResult) AddRow(ctx context.Context, row tree.Datums) error {
+	if r.portal.spool != nil {
+		return r.portal.spool.addRow(ctx, row)
+	}
+	err := r.RestrictedCommandResult.AddRow(ctx, row)
+	if errors.Is(err, ErrLimitedResultSuspended) {
+		// The row has been delivered to the client; the following ones go to
+		// the spool.
+		r.portal.spool = newPortalSpool(ctx, r.evalCtx, r.distSQLCfg, r.sessionMon, r.cols)
+		return nil
+	}
+	return err
+}
@@ -136,6 +136,13 @@ type PreparedPortal struct {
 	refCount int
 
 	memAcc mon.BoundAccount
+
+	// spool, if set, contains the rows of the portal that haven't been returned
+	// to the client yet. It is populated when the client executes other
+	// commands while the portal is suspended: the portal's statement is then
+	// run to completion and its remaining rows are buffered here, to be
+	// returned by subsequent executions of the portal.
+	spool *portalSpool
 }
 
 // newPreparedPortal creates a new PreparedPortal.
@@ -179,6 +186,9 @@ func (p *PreparedPortal) decRef(ctx context.Context) {
 	p.refCount--
 
 	if p.refCount == 0 {
+		if p.spool != nil {
+			p.spool.close(ctx)
+		}
 		p.memAcc.Close(ctx)
 		p.Stmt.decRef(ctx)
 	}
@@ -416,6 +416,7 @@ func (c *cascader) addIndexPKRowFetcher(
 	if err := rowFetcher.Init(
 		false, /* reverse */
 		sqlbase.ScanLockingStrength_FOR_NONE,
+		sqlbase.ScanLockingWaitPolicy_BLOCK,
 		false, /* returnRangeInfo */
 		false, /* isCheck */
 		c.alloc,
@@ -484,6 +485,7 @@ func (c *cascader) addRowDeleter(
 		// strength here. Consider hooking this in to the same knob that will
 		// control whether we perform locking implicitly during DELETEs.
 		sqlbase.ScanLockingStrength_FOR_NONE,
+		sqlbase.ScanLockingWaitPolicy_BLOCK,
 		false, /* returnRangeInfo */
 		false, /* isCheck */
 		c.alloc,
@@ -550,6 +552,7 @@ func (c *cascader) addRowUpdater(
 		// strength here. Consider hooking this in to the same knob that will
 		// control whether we perform locking implicitly during UPDATEs.
 		sqlbase.ScanLockingStrength_FOR_NONE,
+		sqlbase.ScanLockingWaitPolicy_BLOCK,
 		false, /* returnRangeInfo */
 		false, /* isCheck */
 		c.alloc,
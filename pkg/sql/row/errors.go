@@ -111,6 +111,7 @@ func NewUniquenessConstraintViolationError(
 	if err := rf.Init(
 		false, /* reverse */
 		sqlbase.ScanLockingStrength_FOR_NONE,
+		sqlbase.ScanLockingWaitPolicy_BLOCK,
 		false, /* returnRangeInfo */
 		false, /* isCheck */
 		&sqlbase.DatumAlloc{},
@@ -193,6 +193,10 @@ type Fetcher struct {
 	// lockStr represents the row-level locking mode to use when fetching rows.
 	lockStr sqlbase.ScanLockingStrength
 
+	// lockWaitPolicy represents the policy to be used for handling conflicting
+	// locks held by other active transactions.
+	lockWaitPolicy sqlbase.ScanLockingWaitPolicy
+
 	// returnRangeInfo, if set, causes the underlying kvBatchFetcher to return
 	// information about the ranges descriptors/leases uses in servicing the
 	// requests. This has some cost, so it's only enabled by DistSQL when this
@@ -247,6 +251,7 @@ func (rf *Fetcher) Reset() {
 func (rf *Fetcher) Init(
 	reverse bool,
 	lockStr sqlbase.ScanLockingStrength,
+	lockWaitPolicy sqlbase.ScanLockingWaitPolicy,
 	returnRangeInfo bool,
 	isCheck bool,
 	alloc *sqlbase.DatumAlloc,
@@ -258,6 +263,7 @@ func (rf *Fetcher) Init(
 
 	rf.reverse = reverse
 	rf.lockStr = lockStr
+	rf.lockWaitPolicy = lockWaitPolicy
 	rf.returnRangeInfo = returnRangeInfo
 	rf.alloc = alloc
 	rf.isCheck = isCheck
@@ -476,6 +482,7 @@ func (rf *Fetcher) StartScan(
 		limitBatches,
 		rf.firstBatchLimit(limitHint),
 		rf.lockStr,
+		rf.lockWaitPolicy,
 		rf.returnRangeInfo,
 	)
 	if err != nil {
@@ -556,6 +563,7 @@ func (rf *Fetcher) StartInconsistentScan(
 		limitBatches,
 		rf.firstBatchLimit(limitHint),
 		rf.lockStr,
+		rf.lockWaitPolicy,
 		rf.returnRangeInfo,
 	)
 	if err != nil {
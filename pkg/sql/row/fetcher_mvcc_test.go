@@ -108,6 +108,7 @@ func TestRowFetcherMVCCMetadata(t *testing.T) {
 	if err := rf.Init(
 		false, /* reverse */
 		sqlbase.ScanLockingStrength_FOR_NONE,
+		sqlbase.ScanLockingWaitPolicy_BLOCK,
 		false, /* returnRangeInfo */
 		true,  /* isCheck */
 		&sqlbase.DatumAlloc{},
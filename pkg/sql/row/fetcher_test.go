@@ -74,6 +74,7 @@ func initFetcher(
 	if err := fetcher.Init(
 		reverseScan,
 		sqlbase.ScanLockingStrength_FOR_NONE,
+		sqlbase.ScanLockingWaitPolicy_BLOCK,
 		false, /* returnRangeInfo */
 		false, /* isCheck */
 		alloc,
@@ -1058,7 +1059,7 @@ func TestRowFetcherReset(t *testing.T) {
 
 	fetcherArgs := makeFetcherArgs(args)
 	if err := resetFetcher.Init(
-		false /*reverse*/, 0 /* todo */, false /* returnRangeInfo */, false /* isCheck */, &da, fetcherArgs...,
+		false /*reverse*/, 0 /* todo */, 0 /* todo */, false /* returnRangeInfo */, false /* isCheck */, &da, fetcherArgs...,
 	); err != nil {
 		t.Fatal(err)
 	}
@@ -149,6 +149,7 @@ func makeFkExistenceCheckBaseHelper(
 	if err := rf.Init(
 		false, /* reverse */
 		sqlbase.ScanLockingStrength_FOR_NONE,
+		sqlbase.ScanLockingWaitPolicy_BLOCK,
 		false, /* returnRangeInfo */
 		false, /* isCheck */
 		alloc,
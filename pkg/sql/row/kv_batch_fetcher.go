@@ -55,6 +55,9 @@ type txnKVFetcher struct {
 	reverse         bool
 	// lockStr represents the locking mode to use when fetching KVs.
 	lockStr sqlbase.ScanLockingStrength
+	// lockWaitPolicy represents the policy to be used for handling conflicting
+	// locks held by other active transactions.
+	lockWaitPolicy sqlbase.ScanLockingWaitPolicy
 	// returnRangeInfo, if set, causes the kvBatchFetcher to populate rangeInfos.
 	// See also rowFetcher.returnRangeInfo.
 	returnRangeInfo bool
@@ -148,6 +151,7 @@ func makeKVBatchFetcher(
 	useBatchLimit bool,
 	firstBatchLimit int64,
 	lockStr sqlbase.ScanLockingStrength,
+	lockWaitPolicy sqlbase.ScanLockingWaitPolicy,
 	returnRangeInfo bool,
 ) (txnKVFetcher, error) {
 	sendFn := func(ctx context.Context, ba roachpb.BatchRequest) (*roachpb.BatchResponse, error) {
@@ -158,7 +162,7 @@ func makeKVBatchFetcher(
 		return res, nil
 	}
 	return makeKVBatchFetcherWithSendFunc(
-		sendFn, spans, reverse, useBatchLimit, firstBatchLimit, lockStr, returnRangeInfo,
+		sendFn, spans, reverse, useBatchLimit, firstBatchLimit, lockStr, lockWaitPolicy, returnRangeInfo,
 	)
 }
 
@@ -171,6 +175,7 @@ func makeKVBatchFetcherWithSendFunc(
 	useBatchLimit bool,
 	firstBatchLimit int64,
 	lockStr sqlbase.ScanLockingStrength,
+	lockWaitPolicy sqlbase.ScanLockingWaitPolicy,
 	returnRangeInfo bool,
 ) (txnKVFetcher, error) {
 	if firstBatchLimit < 0 || (!useBatchLimit && firstBatchLimit != 0) {
@@ -223,6 +228,7 @@ func makeKVBatchFetcherWithSendFunc(
 		useBatchLimit:   useBatchLimit,
 		firstBatchLimit: firstBatchLimit,
 		lockStr:         lockStr,
+		lockWaitPolicy:  lockWaitPolicy,
 		returnRangeInfo: returnRangeInfo,
 	}, nil
 }
@@ -232,13 +238,14 @@ func (f *txnKVFetcher) fetch(ctx context.Context) error {
 	var ba roachpb.BatchRequest
 	ba.Header.MaxSpanRequestKeys = f.getBatchSize()
 	ba.Header.ReturnRangeInfo = f.returnRangeInfo
+	ba.Header.WaitPolicy = getWaitPolicy(f.lockWaitPolicy)
 	ba.Requests = make([]roachpb.RequestUnion, len(f.spans))
 	if f.reverse {
 		scans := make([]roachpb.ReverseScanRequest, len(f.spans))
 		for i := range f.spans {
 			scans[i].ScanFormat = roachpb.BATCH_RESPONSE
 			scans[i].SetSpan(f.spans[i])
-			// TODO(nvanbenschoten): use f.lockStr here.
+			scans[i].KeyLocking = getKeyLockingStrength(f.lockStr)
 			ba.Requests[i].MustSetInner(&scans[i])
 		}
 	} else {
@@ -246,7 +253,7 @@ func (f *txnKVFetcher) fetch(ctx context.Context) error {
 		for i := range f.spans {
 			scans[i].ScanFormat = roachpb.BATCH_RESPONSE
 			scans[i].SetSpan(f.spans[i])
-			// TODO(nvanbenschoten): use f.lockStr here.
+			scans[i].KeyLocking = getKeyLockingStrength(f.lockStr)
 			ba.Requests[i].MustSetInner(&scans[i])
 		}
 	}
@@ -273,7 +280,7 @@ func (f *txnKVFetcher) fetch(ctx context.Context) error {
 
 	br, err := f.sendFn(ctx, ba)
 	if err != nil {
-		return err
+		return convertLockNotAvailableError(err, f.lockWaitPolicy)
 	}
 	if br != nil {
 		f.responses = br.Responses
@@ -40,10 +40,11 @@ func NewKVFetcher(
 	useBatchLimit bool,
 	firstBatchLimit int64,
 	lockStr sqlbase.ScanLockingStrength,
+	lockWaitPolicy sqlbase.ScanLockingWaitPolicy,
 	returnRangeInfo bool,
 ) (*KVFetcher, error) {
 	kvBatchFetcher, err := makeKVBatchFetcher(
-		txn, spans, reverse, useBatchLimit, firstBatchLimit, lockStr, returnRangeInfo,
+		txn, spans, reverse, useBatchLimit, firstBatchLimit, lockStr, lockWaitPolicy, returnRangeInfo,
 	)
 	return newKVFetcher(&kvBatchFetcher), err
 }
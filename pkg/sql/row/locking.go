@@ -0,0 +1,74 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package row
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage/concurrency/lock"
+	"github.com/cockroachdb/errors"
+)
+
+// getKeyLockingStrength returns the configured per-key locking strength to use
+// for key-value scans.
+func getKeyLockingStrength(lockStr sqlbase.ScanLockingStrength) lock.Strength {
+	switch lockStr {
+	case sqlbase.ScanLockingStrength_FOR_NONE:
+		return lock.None
+	case sqlbase.ScanLockingStrength_FOR_KEY_SHARE,
+		sqlbase.ScanLockingStrength_FOR_SHARE,
+		sqlbase.ScanLockingStrength_FOR_NO_KEY_UPDATE,
+		sqlbase.ScanLockingStrength_FOR_UPDATE:
+		// The key-value layer only supports exclusive locks, so all of the
+		// locking strengths map to them. Exclusive locks are at least as strong
+		// as all of the requested strengths.
+		return lock.Exclusive
+	default:
+		panic(fmt.Sprintf("unknown locking strength %s", lockStr))
+	}
+}
+
+// getWaitPolicy returns the configured lock wait policy to use for key-value
+// scans.
+func getWaitPolicy(lockWaitPolicy sqlbase.ScanLockingWaitPolicy) lock.WaitPolicy {
+	switch lockWaitPolicy {
+	case sqlbase.ScanLockingWaitPolicy_BLOCK:
+		return lock.WaitPolicy_Block
+	case sqlbase.ScanLockingWaitPolicy_SKIP:
+		return lock.WaitPolicy_SkipLocked
+	case sqlbase.ScanLockingWaitPolicy_ERROR:
+		return lock.WaitPolicy_Error
+	default:
+		panic(fmt.Sprintf("unknown wait policy %s", lockWaitPolicy))
+	}
+}
+
+// convertLockNotAvailableError converts a WriteIntentError returned by a
+// key-value scan with an ERROR lock wait policy into a pgerror with the
+// LockNotAvailable code. Other errors are returned unchanged.
+func convertLockNotAvailableError(err error, lockWaitPolicy sqlbase.ScanLockingWaitPolicy) error {
+	if lockWaitPolicy != sqlbase.ScanLockingWaitPolicy_ERROR {
+		return err
+	}
+	wiErr, ok := err.(*roachpb.WriteIntentError)
+	if !ok {
+		return err
+	}
+	err = pgerror.New(pgcode.LockNotAvailable, "could not obtain lock on row")
+	if len(wiErr.Intents) > 0 {
+		err = errors.WithDetailf(err, "conflicting lock on key %s", wiErr.Intents[0].Key)
+	}
+	return err
+}
@@ -127,6 +127,7 @@ func newIndexSkipTableReader(
 	if err := t.fetcher.Init(
 		t.reverse,
 		spec.LockingStrength,
+		spec.LockingWaitPolicy,
 		true,  /* returnRangeInfo */
 		false, /* isCheck */
 		&t.alloc,
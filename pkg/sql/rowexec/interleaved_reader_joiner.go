@@ -367,7 +367,7 @@ func newInterleavedReaderJoiner(
 	}
 
 	if err := irj.initRowFetcher(
-		spec.Tables, tables, spec.Reverse, spec.LockingStrength, &irj.alloc,
+		spec.Tables, tables, spec.Reverse, spec.LockingStrength, spec.LockingWaitPolicy, &irj.alloc,
 	); err != nil {
 		return nil, err
 	}
@@ -404,6 +404,7 @@ func (irj *interleavedReaderJoiner) initRowFetcher(
 	tableInfos []tableInfo,
 	reverseScan bool,
 	lockStr sqlbase.ScanLockingStrength,
+	lockWaitPolicy sqlbase.ScanLockingWaitPolicy,
 	alloc *sqlbase.DatumAlloc,
 ) error {
 	args := make([]row.FetcherTableArgs, len(tables))
@@ -429,6 +430,7 @@ func (irj *interleavedReaderJoiner) initRowFetcher(
 	return irj.fetcher.Init(
 		reverseScan,
 		lockStr,
+		lockWaitPolicy,
 		true, /* returnRangeInfo */
 		true, /* isCheck */
 		alloc,
@@ -25,7 +25,6 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/stats"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
-	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
@@ -100,8 +99,11 @@ func newSamplerProcessor(
 		if _, ok := supportedSketchTypes[s.SketchType]; !ok {
 			return nil, errors.Errorf("unsupported sketch type %s", s.SketchType)
 		}
-		if len(s.Columns) != 1 {
-			return nil, unimplemented.NewWithIssue(34422, "multi-column statistics are not supported yet.")
+		if len(s.Columns) == 0 {
+			return nil, errors.Errorf("no columns")
+		}
+		if s.GenerateHistogram && len(s.Columns) != 1 {
+			return nil, errors.Errorf("histograms require one column")
 		}
 	}
 
@@ -277,44 +279,9 @@ func (s *samplerProcessor) mainLoop(ctx context.Context) (earlyExit bool, err er
 			}
 		}
 
-		var intbuf [8]byte
 		for i := range s.sketches {
-			// TODO(radu): for multi-column sketches, we will need to do this for all
-			// columns.
-			col := s.sketches[i].spec.Columns[0]
-			s.sketches[i].numRows++
-			isNull := row[col].IsNull()
-			if isNull {
-				s.sketches[i].numNulls++
-			}
-			if s.outTypes[col].Family() == types.IntFamily && !isNull {
-				// Fast path for integers.
-				// TODO(radu): make this more general.
-				val, err := row[col].GetInt()
-				if err != nil {
-					return false, err
-				}
-
-				// Note: this encoding is not identical with the one in the general path
-				// below, but it achieves the same thing (we want equal integers to
-				// encode to equal []bytes). The only caveat is that all samplers must
-				// use the same encodings, so changes will require a new SketchType to
-				// avoid problems during upgrade.
-				//
-				// We could use a more efficient hash function and use InsertHash, but
-				// it must be a very good hash function (HLL expects the hash values to
-				// be uniformly distributed in the 2^64 range). Experiments (on tpcc
-				// order_line) with simplistic functions yielded bad results.
-				binary.LittleEndian.PutUint64(intbuf[:], uint64(val))
-				s.sketches[i].sketch.Insert(intbuf[:])
-			} else {
-				// We need to use a KEY encoding because equal values should have the same
-				// encoding.
-				buf, err = row[col].Encode(&s.outTypes[col], &da, sqlbase.DatumEncoding_ASCENDING_KEY, buf[:0])
-				if err != nil {
-					return false, err
-				}
-				s.sketches[i].sketch.Insert(buf)
+			if err := s.sketches[i].addRow(row, s.outTypes, &buf, &da); err != nil {
+				return false, err
 			}
 		}
 
@@ -385,6 +352,67 @@ func (s *samplerProcessor) mainLoop(ctx context.Context) (earlyExit bool, err er
 	return false, nil
 }
 
+// addRow adds a row to the sketch. For multi-column sketches, the key encodings
+// of all the sketch columns are concatenated, and the row is counted as NULL if
+// any of these columns is NULL.
+func (s *sketchInfo) addRow(
+	row sqlbase.EncDatumRow, typs []types.T, buf *[]byte, da *sqlbase.DatumAlloc,
+) error {
+	s.numRows++
+
+	if len(s.spec.Columns) == 1 {
+		col := s.spec.Columns[0]
+		isNull := row[col].IsNull()
+		if isNull {
+			s.numNulls++
+		}
+		if typs[col].Family() == types.IntFamily && !isNull {
+			// Fast path for integers.
+			// TODO(radu): make this more general.
+			val, err := row[col].GetInt()
+			if err != nil {
+				return err
+			}
+
+			// Note: this encoding is not identical with the one in the general path
+			// below, but it achieves the same thing (we want equal integers to
+			// encode to equal []bytes). The only caveat is that all samplers must
+			// use the same encodings, so changes will require a new SketchType to
+			// avoid problems during upgrade.
+			//
+			// We could use a more efficient hash function and use InsertHash, but
+			// it must be a very good hash function (HLL expects the hash values to
+			// be uniformly distributed in the 2^64 range). Experiments (on tpcc
+			// order_line) with simplistic functions yielded bad results.
+			var intbuf [8]byte
+			binary.LittleEndian.PutUint64(intbuf[:], uint64(val))
+			s.sketch.Insert(intbuf[:])
+			return nil
+		}
+	} else {
+		for _, col := range s.spec.Columns {
+			if row[col].IsNull() {
+				s.numNulls++
+				break
+			}
+		}
+	}
+
+	// We need to use a KEY encoding because equal values should have the same
+	// encoding. The key encoding of each value is self-delimiting, so the
+	// concatenation of the encodings of several columns is unambiguous.
+	var err error
+	*buf = (*buf)[:0]
+	for _, col := range s.spec.Columns {
+		*buf, err = row[col].Encode(&typs[col], da, sqlbase.DatumEncoding_ASCENDING_KEY, *buf)
+		if err != nil {
+			return err
+		}
+	}
+	s.sketch.Insert(*buf)
+	return nil
+}
+
 func (s *samplerProcessor) close() {
 	if s.InternalClose() {
 		s.memAcc.Close(s.Ctx)
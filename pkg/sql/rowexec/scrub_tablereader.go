@@ -123,7 +123,7 @@ func newScrubTableReader(
 	if _, _, err := initRowFetcher(
 		&fetcher, &tr.tableDesc, int(spec.IndexIdx), tr.tableDesc.ColumnIdxMap(), spec.Reverse,
 		neededColumns, true /* isCheck */, &tr.alloc,
-		execinfrapb.ScanVisibility_PUBLIC, spec.LockingStrength,
+		execinfrapb.ScanVisibility_PUBLIC, spec.LockingStrength, spec.LockingWaitPolicy,
 	); err != nil {
 		return nil, err
 	}
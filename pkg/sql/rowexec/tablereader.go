@@ -117,6 +117,7 @@ func newTableReader(
 	if _, _, err := initRowFetcher(
 		&fetcher, &spec.Table, int(spec.IndexIdx), columnIdxMap, spec.Reverse,
 		neededColumns, spec.IsCheck, &tr.alloc, spec.Visibility, spec.LockingStrength,
+		spec.LockingWaitPolicy,
 	); err != nil {
 		return nil, err
 	}
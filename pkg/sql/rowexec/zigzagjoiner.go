@@ -454,6 +454,7 @@ func (z *zigzagJoiner) setupInfo(
 		// NB: zigzag joins are disabled when a row-level locking clause is
 		// supplied, so there is no locking strength on *ZigzagJoinerSpec.
 		sqlbase.ScanLockingStrength_FOR_NONE,
+		sqlbase.ScanLockingWaitPolicy_BLOCK,
 	)
 	if err != nil {
 		return err
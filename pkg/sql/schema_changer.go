@@ -64,6 +64,14 @@ var schemaChangeLeaseRenewFraction = settings.RegisterFloatSetting(
 	0.5,
 )
 
+var dropTableRangeTombstonesEnabled = settings.RegisterBoolSetting(
+	"schemachanger.drop_table.range_tombstones.enabled",
+	"if enabled, the data of dropped and truncated tables is deleted with MVCC range tombstones "+
+		"instead of being cleared, which keeps the deletion visible to rangefeeds and incremental "+
+		"backups but only reclaims the space one GC TTL later",
+	false,
+)
+
 // This is a delay [0.9 * asyncSchemaChangeDelay, 1.1 * asyncSchemaChangeDelay)
 // added to an attempt to run a schema change via the asynchronous path.
 // This delay allows the synchronous path to execute the schema change
@@ -452,6 +460,25 @@ func (sc *SchemaChanger) truncateTable(
 	tableKey := roachpb.RKey(keys.MakeTablePrefix(uint32(table.ID)))
 	tableSpan := roachpb.RSpan{Key: tableKey, EndKey: tableKey.PrefixEnd()}
 
+	// Unlike ClearRange, MVCC range tombstones delete the data through MVCC,
+	// so rangefeeds and incremental backups observe the deletion and the data
+	// stays readable below the deletion until the MVCC GC queue removes it.
+	// Since they only write a single key per range, there is no need to
+	// throttle them.
+	if dropTableRangeTombstonesEnabled.Get(&sc.settings.SV) &&
+		cluster.Version.IsActive(ctx, sc.settings, cluster.VersionMVCCRangeTombstones) {
+		var b client.Batch
+		b.AddRawRequest(&roachpb.DeleteRangeRequest{
+			RequestHeader: roachpb.RequestHeader{
+				Key:    tableSpan.Key.AsRawKey(),
+				EndKey: tableSpan.EndKey.AsRawKey(),
+			},
+			UseRangeTombstone: true,
+		})
+		log.VEventf(ctx, 2, "DeleteRange using range tombstones %s - %s", tableSpan.Key, tableSpan.EndKey)
+		return sc.db.Run(ctx, &b)
+	}
+
 	// ClearRange requests lays down RocksDB range deletion tombstones that have
 	// serious performance implications (#24029). The logic below attempts to
 	// bound the number of tombstones in one store by sending the ClearRange
@@ -79,17 +79,18 @@ const maxAllocatedStringSize = 128 * 1024 * 1024
 const errInsufficientArgsFmtString = "unknown signature: %s()"
 
 const (
-	categoryComparison    = "Comparison"
-	categoryCompatibility = "Compatibility"
-	categoryDateAndTime   = "Date and time"
-	categoryIDGeneration  = "ID generation"
-	categorySequences     = "Sequence"
-	categoryMath          = "Math and numeric"
-	categoryString        = "String and byte"
-	categoryArray         = "Array"
-	categorySystemInfo    = "System info"
-	categoryGenerator     = "Set-returning"
-	categoryJSON          = "JSONB"
+	categoryComparison     = "Comparison"
+	categoryCompatibility  = "Compatibility"
+	categoryDateAndTime    = "Date and time"
+	categoryIDGeneration   = "ID generation"
+	categorySequences      = "Sequence"
+	categoryMath           = "Math and numeric"
+	categoryString         = "String and byte"
+	categoryArray          = "Array"
+	categorySystemInfo     = "System info"
+	categoryGenerator      = "Set-returning"
+	categoryJSON           = "JSONB"
+	categoryFullTextSearch = "Full Text Search"
 )
 
 func categorizeType(t *types.T) string {
@@ -1743,6 +1744,65 @@ historical reads against a time which is recent but sufficiently old for reads
 to be performed against the closest replica as opposed to the currently
 leaseholder for a given range.
 
+Note that this function requires an enterprise license on a CCL distribution to
+return without an error.`,
+		},
+	),
+
+	tree.WithMinTimestampFunctionName: makeBuiltin(
+		tree.FunctionProperties{Impure: true},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"min_timestamp", types.TimestampTZ}},
+			ReturnType: tree.FixedReturnType(types.TimestampTZ),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				if err := checkBoundedStalenessEnabled(ctx, tree.WithMinTimestampFunctionName); err != nil {
+					return nil, err
+				}
+				minTS := args[0].(*tree.DTimestampTZ)
+				if minTS.After(ctx.GetStmtTimestamp()) {
+					return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+						"minimum timestamp %s is in the future", minTS)
+				}
+				return minTS, nil
+			},
+			Info: `Returns the provided minimum timestamp.
+
+This function is intended to be used with an AS OF SYSTEM TIME clause to perform
+a bounded staleness read, which runs at the newest timestamp no older than
+min_timestamp that the closest replicas of the data being read are able to
+serve. If no such timestamp exists, the read is instead performed at the
+current time against the leaseholders.
+
+Note that this function requires an enterprise license on a CCL distribution to
+return without an error.`,
+		},
+	),
+
+	tree.WithMaxStalenessFunctionName: makeBuiltin(
+		tree.FunctionProperties{Impure: true},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"max_staleness", types.Interval}},
+			ReturnType: tree.FixedReturnType(types.TimestampTZ),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				if err := checkBoundedStalenessEnabled(ctx, tree.WithMaxStalenessFunctionName); err != nil {
+					return nil, err
+				}
+				maxStaleness := args[0].(*tree.DInterval)
+				if maxStaleness.Duration.Compare(duration.Duration{}) < 0 {
+					return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+						"interval %s must not be negative", maxStaleness)
+				}
+				ts := duration.Add(ctx.GetStmtTimestamp(), maxStaleness.Duration.Mul(-1))
+				return tree.MakeDTimestampTZ(ts, time.Microsecond), nil
+			},
+			Info: `Returns the current statement time less the provided staleness bound.
+
+This function is intended to be used with an AS OF SYSTEM TIME clause to perform
+a bounded staleness read, which runs at the newest timestamp no more than
+max_staleness in the past that the closest replicas of the data being read are
+able to serve. If no such timestamp exists, the read is instead performed at
+the current time against the leaseholders.
+
 Note that this function requires an enterprise license on a CCL distribution to
 return without an error.`,
 		},
@@ -2793,9 +2853,7 @@ may increase either contention or retry errors, or both.`,
 				dimen := int64(tree.MustBeDInt(args[1]))
 				return arrayLength(arr, dimen), nil
 			},
-			Info: "Calculates the length of `input` on the provided `array_dimension`. However, " +
-				"because CockroachDB doesn't yet support multi-dimensional arrays, the only supported" +
-				" `array_dimension` is **1**.",
+			Info: "Calculates the length of `input` on the provided `array_dimension`.",
 		},
 	),
 
@@ -2808,9 +2866,7 @@ may increase either contention or retry errors, or both.`,
 				dimen := int64(tree.MustBeDInt(args[1]))
 				return arrayLower(arr, dimen), nil
 			},
-			Info: "Calculates the minimum value of `input` on the provided `array_dimension`. " +
-				"However, because CockroachDB doesn't yet support multi-dimensional arrays, the only " +
-				"supported `array_dimension` is **1**.",
+			Info: "Calculates the minimum value of `input` on the provided `array_dimension`.",
 		},
 	),
 
@@ -2823,9 +2879,42 @@ may increase either contention or retry errors, or both.`,
 				dimen := int64(tree.MustBeDInt(args[1]))
 				return arrayLength(arr, dimen), nil
 			},
-			Info: "Calculates the maximum value of `input` on the provided `array_dimension`. " +
-				"However, because CockroachDB doesn't yet support multi-dimensional arrays, the only " +
-				"supported `array_dimension` is **1**.",
+			Info: "Calculates the maximum value of `input` on the provided `array_dimension`.",
+		},
+	),
+
+	"array_ndims": makeBuiltin(arrayProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"input", types.AnyArray}},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				arr := tree.MustBeDArray(args[0])
+				if isEmptyArray(arr) {
+					return tree.DNull, nil
+				}
+				return tree.NewDInt(tree.DInt(arr.NumDimensions())), nil
+			},
+			Info: "Returns the number of dimensions of `input`.",
+		},
+	),
+
+	"array_dims": makeBuiltin(arrayProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"input", types.AnyArray}},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				arr := tree.MustBeDArray(args[0])
+				if isEmptyArray(arr) {
+					return tree.DNull, nil
+				}
+				var buf bytes.Buffer
+				for _, l := range arr.Dimensions() {
+					fmt.Fprintf(&buf, "[%d:%d]", arr.FirstIndex(), l+arr.FirstIndex()-1)
+				}
+				return tree.NewDString(buf.String()), nil
+			},
+			Info: "Returns a text representation of the dimensions of `input`, " +
+				"for example `[1:2][1:3]`.",
 		},
 	),
 
@@ -3631,6 +3720,60 @@ may increase either contention or retry errors, or both.`,
 		},
 	),
 
+	// Returns the number of entries a value adds to an inverted index. This is
+	// used to validate inverted indexes after they are backfilled.
+	"crdb_internal.num_inverted_index_entries": makeBuiltin(
+		tree.FunctionProperties{
+			Category:     categorySystemInfo,
+			NullableArgs: true,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"val", types.Jsonb}},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				arg := args[0]
+				if arg == tree.DNull {
+					return tree.NewDInt(tree.DInt(1)), nil
+				}
+				n, err := json.NumInvertedIndexEntries(tree.MustBeDJSON(arg).JSON)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDInt(tree.DInt(n)), nil
+			},
+			Info: "This function is used only by CockroachDB's developers for testing purposes.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"val", types.TSVector}},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				arg := args[0]
+				if arg == tree.DNull {
+					return tree.NewDInt(tree.DInt(1)), nil
+				}
+				// There is one entry per lexeme.
+				return tree.NewDInt(tree.DInt(tree.MustBeDTSVector(arg).TSVector.Length())), nil
+			},
+			Info: "This function is used only by CockroachDB's developers for testing purposes.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"val", types.AnyArray}},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				arg := args[0]
+				if arg == tree.DNull {
+					return tree.NewDInt(tree.DInt(1)), nil
+				}
+				keys, err := sqlbase.EncodeInvertedIndexTableKeys(arg, nil /* inKey */)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDInt(tree.DInt(len(keys))), nil
+			},
+			Info: "This function is used only by CockroachDB's developers for testing purposes.",
+		},
+	),
+
 	// Returns true iff the current user has admin role.
 	// Note: it would be a privacy leak to extend this to check arbitrary usernames.
 	"crdb_internal.is_admin": makeBuiltin(
@@ -4967,6 +5110,17 @@ func arrayLength(arr *tree.DArray, dim int64) tree.Datum {
 
 var intOne = tree.NewDInt(tree.DInt(1))
 
+// isEmptyArray returns true if the given (possibly nested) array has no
+// elements, in which case it has no dimensions either.
+func isEmptyArray(arr *tree.DArray) bool {
+	for _, l := range arr.Dimensions() {
+		if l == 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func arrayLower(arr *tree.DArray, dim int64) tree.Datum {
 	if arr.Len() == 0 || dim < 1 {
 		return tree.DNull
@@ -5617,6 +5771,20 @@ func checkPrivilegedUser(ctx *tree.EvalContext) error {
 // if an enterprise license is not installed.
 var EvalFollowerReadOffset func(clusterID uuid.UUID, _ *cluster.Settings) (time.Duration, error)
 
+// CheckBoundedStalenessReadsEnabled is used by the bounded staleness functions
+// used with AS OF SYSTEM TIME queries to determine whether bounded staleness
+// follower reads may be performed. It is injected by followerreadsccl. An
+// error is returned if an enterprise license is not installed.
+var CheckBoundedStalenessReadsEnabled func(clusterID uuid.UUID, _ *cluster.Settings) error
+
+func checkBoundedStalenessEnabled(ctx *tree.EvalContext, funcName string) error {
+	if CheckBoundedStalenessReadsEnabled == nil {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s is only available in ccl distribution", funcName)
+	}
+	return CheckBoundedStalenessReadsEnabled(ctx.ClusterID, ctx.Settings)
+}
+
 func recentTimestamp(ctx *tree.EvalContext) (time.Time, error) {
 	if EvalFollowerReadOffset == nil {
 		return time.Time{}, pgerror.New(pgcode.FeatureNotSupported,
@@ -1711,6 +1711,33 @@ SELECT description
 		},
 	),
 
+	// See https://www.postgresql.org/docs/10/functions-info.html#FUNCTIONS-INFO-SESSION-TABLE
+	"pg_notify": makeBuiltin(
+		tree.FunctionProperties{
+			Category:         categorySystemInfo,
+			DistsqlBlacklist: true,
+			Impure:           true,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"channel", types.String}, {"payload", types.String}},
+			ReturnType: tree.FixedReturnType(types.Unknown),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				if ctx.SessionAccessor == nil {
+					return nil, errors.AssertionFailedf("session accessor not set")
+				}
+				channel := string(tree.MustBeDString(args[0]))
+				payload := string(tree.MustBeDString(args[1]))
+				if err := ctx.SessionAccessor.NotifyChannel(ctx.Context, channel, payload); err != nil {
+					return nil, err
+				}
+				return tree.DNull, nil
+			},
+			Info: "Sends a notification event with the given payload to all sessions " +
+				"listening on the given channel. The notification is delivered when " +
+				"the current transaction commits.",
+		},
+	),
+
 	// inet_{client,server}_{addr,port} return either an INet address or integer
 	// port that corresponds to either the client or server side of the current
 	// session's connection.
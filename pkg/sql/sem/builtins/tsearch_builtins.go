@@ -0,0 +1,305 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package builtins
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
+)
+
+func initTSearchBuiltins() {
+	for k, v := range tsearchBuiltins {
+		if _, exists := builtins[k]; exists {
+			panic("duplicate builtin: " + k)
+		}
+		v.props.Category = categoryFullTextSearch
+		builtins[k] = v
+	}
+
+	// length(tsvector) is an overload of the string length function. Make sure
+	// that the overloads of char_length, which share the same slice, are not
+	// modified.
+	def := builtins["length"]
+	def.overloads = append(def.overloads[:len(def.overloads):len(def.overloads)], tree.Overload{
+		Types:      tree.ArgTypes{{"vector", types.TSVector}},
+		ReturnType: tree.FixedReturnType(types.Int),
+		Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+			v := tree.MustBeDTSVector(args[0])
+			return tree.NewDInt(tree.DInt(v.Length())), nil
+		},
+		Info: "Returns the number of lexemes in `vector`.",
+	})
+	builtins["length"] = def
+}
+
+// See https://www.postgresql.org/docs/current/functions-textsearch.html.
+var tsearchBuiltins = map[string]builtinDefinition{
+	"to_tsvector": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"config", types.String}, {"document", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				c, err := tsearch.GetConfig(string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDTSVector(c.ToTSVector(string(tree.MustBeDString(args[1])))), nil
+			},
+			Info: "Reduces `document` to a tsvector, normalizing its words into lexemes " +
+				"using the text search configuration `config`.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"document", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				c := defaultTSearchConfig()
+				return tree.NewDTSVector(c.ToTSVector(string(tree.MustBeDString(args[0])))), nil
+			},
+			Info: "Reduces `document` to a tsvector, normalizing its words into lexemes " +
+				"using the " + tsearch.DefaultConfig + " text search configuration.",
+		},
+	),
+
+	"to_tsquery": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"config", types.String}, {"query", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSQuery),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				c, err := tsearch.GetConfig(string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				return toTSQuery(c, args[1])
+			},
+			Info: "Converts `query`, which must consist of single tokens separated by the " +
+				"tsquery operators, to a tsquery, normalizing the tokens into lexemes " +
+				"using the text search configuration `config`.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"query", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSQuery),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return toTSQuery(defaultTSearchConfig(), args[0])
+			},
+			Info: "Converts `query`, which must consist of single tokens separated by the " +
+				"tsquery operators, to a tsquery, normalizing the tokens into lexemes " +
+				"using the " + tsearch.DefaultConfig + " text search configuration.",
+		},
+	),
+
+	"plainto_tsquery": makeTSQueryBuiltin(
+		(*tsearch.Config).PlainToTSQuery,
+		"Converts the plain text `query` to a tsquery that matches documents containing "+
+			"all of its words.",
+	),
+
+	"phraseto_tsquery": makeTSQueryBuiltin(
+		(*tsearch.Config).PhraseToTSQuery,
+		"Converts the plain text `query` to a tsquery that matches documents containing "+
+			"its words in the same order.",
+	),
+
+	"ts_rank": makeBuiltin(defProps(),
+		makeTSRankOverload(false /* withWeights */, false /* withNormalization */),
+		makeTSRankOverload(false /* withWeights */, true /* withNormalization */),
+		makeTSRankOverload(true /* withWeights */, false /* withNormalization */),
+		makeTSRankOverload(true /* withWeights */, true /* withNormalization */),
+	),
+
+	"ts_headline": makeBuiltin(defProps(),
+		makeTSHeadlineOverload(false /* withConfig */, false /* withOptions */),
+		makeTSHeadlineOverload(false /* withConfig */, true /* withOptions */),
+		makeTSHeadlineOverload(true /* withConfig */, false /* withOptions */),
+		makeTSHeadlineOverload(true /* withConfig */, true /* withOptions */),
+	),
+
+	"setweight": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector", types.TSVector}, {"weight", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				w, err := tsearch.ParseWeight(string(tree.MustBeDString(args[1])))
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDTSVector(tree.MustBeDTSVector(args[0]).SetWeight(w)), nil
+			},
+			Info: "Assigns `weight`, one of A, B, C or D, to all the positions of `vector`.",
+		},
+	),
+
+	"strip": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector", types.TSVector}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tree.NewDTSVector(tree.MustBeDTSVector(args[0]).Strip()), nil
+			},
+			Info: "Removes the positions and weights from `vector`.",
+		},
+	),
+}
+
+// defaultTSearchConfig returns the configuration used by the text search
+// functions that don't take a configuration argument.
+func defaultTSearchConfig() *tsearch.Config {
+	c, err := tsearch.GetConfig(tsearch.DefaultConfig)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func toTSQuery(c *tsearch.Config, query tree.Datum) (tree.Datum, error) {
+	q, err := c.ToTSQuery(string(tree.MustBeDString(query)))
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewDTSQuery(q), nil
+}
+
+// makeTSQueryBuiltin creates a builtin that converts plain text to a tsquery,
+// with and without a configuration argument.
+func makeTSQueryBuiltin(
+	fn func(c *tsearch.Config, text string) tsearch.TSQuery, info string,
+) builtinDefinition {
+	return makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{{"config", types.String}, {"query", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSQuery),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				c, err := tsearch.GetConfig(string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDTSQuery(fn(c, string(tree.MustBeDString(args[1])))), nil
+			},
+			Info: info + " The words are normalized into lexemes using the text search " +
+				"configuration `config`.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"query", types.String}},
+			ReturnType: tree.FixedReturnType(types.TSQuery),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tree.NewDTSQuery(fn(defaultTSearchConfig(), string(tree.MustBeDString(args[0])))), nil
+			},
+			Info: info + " The words are normalized into lexemes using the " +
+				tsearch.DefaultConfig + " text search configuration.",
+		},
+	)
+}
+
+func makeTSRankOverload(withWeights, withNormalization bool) tree.Overload {
+	var argTypes tree.ArgTypes
+	if withWeights {
+		argTypes = append(argTypes, tree.ArgTypes{{"weights", types.MakeArray(types.Float4)}}...)
+	}
+	argTypes = append(argTypes, tree.ArgTypes{{"vector", types.TSVector}, {"query", types.TSQuery}}...)
+	if withNormalization {
+		argTypes = append(argTypes, tree.ArgTypes{{"normalization", types.Int}}...)
+	}
+	info := "Ranks `vector` for `query` based on the frequency of its matching lexemes."
+	if withWeights {
+		info += " The occurrences of the lexemes are weighted by `weights`, an array " +
+			"containing the weights of the D, C, B and A labels."
+	}
+	if withNormalization {
+		info += " The rank is normalized by the length of the document as specified " +
+			"by the `normalization` bit mask."
+	}
+	return tree.Overload{
+		Types:      argTypes,
+		ReturnType: tree.FixedReturnType(types.Float4),
+		Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+			weights := tsearch.DefaultRankWeights
+			if withWeights {
+				var err error
+				if weights, err = rankWeights(tree.MustBeDArray(args[0])); err != nil {
+					return nil, err
+				}
+				args = args[1:]
+			}
+			var norm tsearch.RankNormalization
+			if withNormalization {
+				norm = tsearch.RankNormalization(tree.MustBeDInt(args[2]))
+				if norm < 0 {
+					return nil, pgerror.New(pgcode.InvalidParameterValue,
+						"normalization must be non-negative")
+				}
+			}
+			v, q := tree.MustBeDTSVector(args[0]), tree.MustBeDTSQuery(args[1])
+			rank := tsearch.Rank(weights, v.TSVector, q.TSQuery, norm)
+			return tree.NewDFloat(tree.DFloat(rank)), nil
+		},
+		Info: info,
+	}
+}
+
+// rankWeights validates the weights argument of ts_rank.
+func rankWeights(arr *tree.DArray) ([4]float64, error) {
+	if arr.NumDimensions() != 1 {
+		return [4]float64{}, pgerror.New(pgcode.ArraySubscript, "array of weight must be one-dimensional")
+	}
+	if arr.HasNulls {
+		return [4]float64{}, pgerror.New(pgcode.NullValueNotAllowed, "array of weight must not contain nulls")
+	}
+	weights := make([]float64, len(arr.Array))
+	for i, d := range arr.Array {
+		weights[i] = float64(tree.MustBeDFloat(d))
+	}
+	return tsearch.ValidateRankWeights(weights)
+}
+
+func makeTSHeadlineOverload(withConfig, withOptions bool) tree.Overload {
+	var argTypes tree.ArgTypes
+	if withConfig {
+		argTypes = append(argTypes, tree.ArgTypes{{"config", types.String}}...)
+	}
+	argTypes = append(argTypes, tree.ArgTypes{{"document", types.String}, {"query", types.TSQuery}}...)
+	if withOptions {
+		argTypes = append(argTypes, tree.ArgTypes{{"options", types.String}}...)
+	}
+	info := "Returns an excerpt of `document` in which the words matching `query` are highlighted."
+	if withConfig {
+		info += " The words are normalized using the text search configuration `config`."
+	}
+	if withOptions {
+		info += " The format of the excerpt is controlled by `options`, a comma-separated " +
+			"list of option=value pairs."
+	}
+	return tree.Overload{
+		Types:      argTypes,
+		ReturnType: tree.FixedReturnType(types.String),
+		Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+			c := defaultTSearchConfig()
+			if withConfig {
+				var err error
+				if c, err = tsearch.GetConfig(string(tree.MustBeDString(args[0]))); err != nil {
+					return nil, err
+				}
+				args = args[1:]
+			}
+			opts := tsearch.DefaultHeadlineOptions()
+			if withOptions {
+				var err error
+				if opts, err = tsearch.ParseHeadlineOptions(string(tree.MustBeDString(args[2]))); err != nil {
+					return nil, err
+				}
+			}
+			doc, q := string(tree.MustBeDString(args[0])), tree.MustBeDTSQuery(args[1])
+			return tree.NewDString(c.Headline(doc, q.TSQuery, opts)), nil
+		},
+		Info: info,
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import "strconv"
+
+// DeclareCursor represents a DECLARE statement.
+type DeclareCursor struct {
+	Name   Name
+	Select *Select
+}
+
+var _ Statement = &DeclareCursor{}
+
+// Format implements the NodeFormatter interface.
+func (node *DeclareCursor) Format(ctx *FmtCtx) {
+	ctx.WriteString("DECLARE ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" CURSOR FOR ")
+	ctx.FormatNode(node.Select)
+}
+
+// FetchType describes the direction and the extent of a FETCH or MOVE
+// statement.
+type FetchType int
+
+const (
+	// FetchNormal moves the cursor forward by Count rows, or backward if Count
+	// is negative. It covers NEXT, PRIOR, FORWARD and BACKWARD.
+	FetchNormal FetchType = iota
+	// FetchRelative moves the cursor by Count rows and returns the row it ends
+	// up on.
+	FetchRelative
+	// FetchAbsolute moves the cursor to the Count-th row and returns it.
+	FetchAbsolute
+	// FetchFirst moves the cursor to its first row and returns it.
+	FetchFirst
+	// FetchLast moves the cursor to its last row and returns it.
+	FetchLast
+	// FetchAll moves the cursor forward past its last row.
+	FetchAll
+	// FetchBackwardAll moves the cursor backward before its first row.
+	FetchBackwardAll
+)
+
+// CursorStmt contains the fields shared by the FETCH and MOVE statements.
+type CursorStmt struct {
+	Name      Name
+	FetchType FetchType
+	Count     int64
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CursorStmt) Format(ctx *FmtCtx) {
+	switch node.FetchType {
+	case FetchNormal:
+		ctx.WriteString(strconv.FormatInt(node.Count, 10))
+	case FetchRelative:
+		ctx.WriteString("RELATIVE ")
+		ctx.WriteString(strconv.FormatInt(node.Count, 10))
+	case FetchAbsolute:
+		ctx.WriteString("ABSOLUTE ")
+		ctx.WriteString(strconv.FormatInt(node.Count, 10))
+	case FetchFirst:
+		ctx.WriteString("FIRST")
+	case FetchLast:
+		ctx.WriteString("LAST")
+	case FetchAll:
+		ctx.WriteString("ALL")
+	case FetchBackwardAll:
+		ctx.WriteString("BACKWARD ALL")
+	}
+	ctx.WriteByte(' ')
+	ctx.FormatNode(&node.Name)
+}
+
+// FetchCursor represents a FETCH statement.
+type FetchCursor struct {
+	CursorStmt
+}
+
+var _ Statement = &FetchCursor{}
+
+// Format implements the NodeFormatter interface.
+func (node *FetchCursor) Format(ctx *FmtCtx) {
+	ctx.WriteString("FETCH ")
+	ctx.FormatNode(&node.CursorStmt)
+}
+
+// MoveCursor represents a MOVE statement.
+type MoveCursor struct {
+	CursorStmt
+}
+
+var _ Statement = &MoveCursor{}
+
+// Format implements the NodeFormatter interface.
+func (node *MoveCursor) Format(ctx *FmtCtx) {
+	ctx.WriteString("MOVE ")
+	ctx.FormatNode(&node.CursorStmt)
+}
+
+// CloseCursor represents a CLOSE statement.
+type CloseCursor struct {
+	Name Name
+	All  bool
+}
+
+var _ Statement = &CloseCursor{}
+
+// Format implements the NodeFormatter interface.
+func (node *CloseCursor) Format(ctx *FmtCtx) {
+	ctx.WriteString("CLOSE ")
+	if node.All {
+		ctx.WriteString("ALL")
+	} else {
+		ctx.FormatNode(&node.Name)
+	}
+}
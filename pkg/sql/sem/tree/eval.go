@@ -42,6 +42,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
@@ -1448,6 +1449,15 @@ var BinOps = map[BinaryOperator]binOpOverload{
 				return &DJSON{j}, nil
 			},
 		},
+		&BinOp{
+			LeftType:   types.TSVector,
+			RightType:  types.TSVector,
+			ReturnType: types.TSVector,
+			Fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				v := MustBeDTSVector(left).TSVector
+				return NewDTSVector(v.Concat(MustBeDTSVector(right).TSVector)), nil
+			},
+		},
 	},
 
 	// TODO(pmattis): Check that the shift is valid.
@@ -1840,6 +1850,8 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeEqFn(types.TimeTZ, types.TimeTZ),
 		makeEqFn(types.Timestamp, types.Timestamp),
 		makeEqFn(types.TimestampTZ, types.TimestampTZ),
+		makeEqFn(types.TSQuery, types.TSQuery),
+		makeEqFn(types.TSVector, types.TSVector),
 		makeEqFn(types.Uuid, types.Uuid),
 		makeEqFn(types.VarBit, types.VarBit),
 
@@ -1987,6 +1999,8 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeIsFn(types.TimeTZ, types.TimeTZ),
 		makeIsFn(types.Timestamp, types.Timestamp),
 		makeIsFn(types.TimestampTZ, types.TimestampTZ),
+		makeIsFn(types.TSQuery, types.TSQuery),
+		makeIsFn(types.TSVector, types.TSVector),
 		makeIsFn(types.Uuid, types.Uuid),
 		makeIsFn(types.VarBit, types.VarBit),
 
@@ -2237,6 +2251,27 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 			},
 		},
 	},
+
+	TSMatches: {
+		&CmpOp{
+			LeftType:  types.TSVector,
+			RightType: types.TSQuery,
+			Fn: func(_ *EvalContext, left, right Datum) (Datum, error) {
+				v := MustBeDTSVector(left).TSVector
+				q := MustBeDTSQuery(right).TSQuery
+				return MakeDBool(DBool(tsearch.Match(v, q))), nil
+			},
+		},
+		&CmpOp{
+			LeftType:  types.TSQuery,
+			RightType: types.TSVector,
+			Fn: func(_ *EvalContext, left, right Datum) (Datum, error) {
+				q := MustBeDTSQuery(left).TSQuery
+				v := MustBeDTSVector(right).TSVector
+				return MakeDBool(DBool(tsearch.Match(v, q))), nil
+			},
+		},
+	},
 })
 
 // This map contains the inverses for operators in the CmpOps map that have
@@ -2628,6 +2663,13 @@ type EvalSessionAccessor interface {
 
 	// HasAdminRole returns true iff the current session user has the admin role.
 	HasAdminRole(ctx context.Context) (bool, error)
+
+	// NotifyChannel generates a notification on the given channel, to be
+	// delivered to the listening sessions once the current transaction commits.
+	//
+	// This interface only supports strings as this is sufficient for
+	// pg_catalog.pg_notify().
+	NotifyChannel(ctx context.Context, channel, payload string) error
 }
 
 // InternalExecutor is a subset of sqlutil.InternalExecutor (which, in turn, is
@@ -3486,6 +3528,10 @@ func PerformCast(ctx *EvalContext, d Datum, t *types.T) (Datum, error) {
 			s = t.String()
 		case *DJSON:
 			s = t.JSON.String()
+		case *DTSVector:
+			s = t.TSVector.String()
+		case *DTSQuery:
+			s = t.TSQuery.String()
 		}
 		switch t.Family() {
 		case types.StringFamily:
@@ -3682,6 +3728,24 @@ func PerformCast(ctx *EvalContext, d Datum, t *types.T) (Datum, error) {
 		case *DJSON:
 			return v, nil
 		}
+	case types.TSVectorFamily:
+		switch v := d.(type) {
+		case *DString:
+			return ParseDTSVector(string(*v))
+		case *DCollatedString:
+			return ParseDTSVector(v.Contents)
+		case *DTSVector:
+			return v, nil
+		}
+	case types.TSQueryFamily:
+		switch v := d.(type) {
+		case *DString:
+			return ParseDTSQuery(string(*v))
+		case *DCollatedString:
+			return ParseDTSQuery(v.Contents)
+		case *DTSQuery:
+			return v, nil
+		}
 	case types.ArrayFamily:
 		switch v := d.(type) {
 		case *DString:
@@ -3846,42 +3910,124 @@ func PerformCast(ctx *EvalContext, d Datum, t *types.T) (Datum, error) {
 
 // Eval implements the TypedExpr interface.
 func (expr *IndirectionExpr) Eval(ctx *EvalContext) (Datum, error) {
-	var subscriptIdx int
-	for i, t := range expr.Indirection {
-		if t.Slice || i > 0 {
-			return nil, errors.AssertionFailedf("unsupported feature should have been rejected during planning")
+	d, err := expr.Expr.(TypedExpr).Eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if d == DNull {
+		return d, nil
+	}
+
+	for _, t := range expr.Indirection {
+		if t.Slice {
+			return expr.evalSlice(ctx, MustBeDArray(d))
 		}
+	}
 
-		d, err := t.Begin.(TypedExpr).Eval(ctx)
+	for _, t := range expr.Indirection {
+		idx, err := t.Begin.(TypedExpr).Eval(ctx)
 		if err != nil {
 			return nil, err
 		}
-		if d == DNull {
-			return d, nil
+		if idx == DNull {
+			return idx, nil
 		}
-		subscriptIdx = int(MustBeDInt(d))
+
+		// Index into the DArray, using 1-indexing.
+		arr := MustBeDArray(d)
+		subscriptIdx := int(MustBeDInt(idx))
+
+		// VECTOR types use 0-indexing.
+		switch arr.customOid {
+		case oid.T_oidvector, oid.T_int2vector:
+			subscriptIdx++
+		}
+		if subscriptIdx < 1 || subscriptIdx > arr.Len() {
+			return DNull, nil
+		}
+		d = arr.Array[subscriptIdx-1]
 	}
+	return d, nil
+}
 
-	d, err := expr.Expr.(TypedExpr).Eval(ctx)
+// evalSlice evaluates a slicing expression such as a[1:2][2:3] over the given
+// array. As in Postgres, once any of the subscripts is a slice all of them are
+// treated as slices, with a subscript without a colon such as [2] being
+// equivalent to [1:2]. Omitted bounds default to the bounds of the array and
+// bounds outside of the array are clamped to it. If the slice of any dimension
+// is empty, the result is an empty array.
+func (expr *IndirectionExpr) evalSlice(ctx *EvalContext, arr *DArray) (Datum, error) {
+	lower := make([]int, len(expr.Indirection))
+	upper := make([]int, len(expr.Indirection))
+	for i, t := range expr.Indirection {
+		lower[i], upper[i] = math.MinInt32, math.MaxInt32
+		for _, bound := range []struct {
+			e   Expr
+			val *int
+		}{
+			{e: t.Begin, val: &lower[i]},
+			{e: t.End, val: &upper[i]},
+		} {
+			if bound.e == nil {
+				continue
+			}
+			d, err := bound.e.(TypedExpr).Eval(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if d == DNull {
+				return DNull, nil
+			}
+			*bound.val = int(MustBeDInt(d))
+		}
+		if !t.Slice {
+			// A subscript without a colon is the upper bound of the slice.
+			upper[i], lower[i] = lower[i], 1
+		}
+	}
+
+	res, err := sliceArray(arr, lower, upper)
 	if err != nil {
 		return nil, err
 	}
-	if d == DNull {
-		return d, nil
+	if res == nil {
+		res = NewDArray(arr.ParamTyp)
+		res.customOid = arr.customOid
 	}
+	return res, nil
+}
 
-	// Index into the DArray, using 1-indexing.
-	arr := MustBeDArray(d)
-
-	// VECTOR types use 0-indexing.
-	switch arr.customOid {
-	case oid.T_oidvector, oid.T_int2vector:
-		subscriptIdx++
+// sliceArray returns the elements of arr between the given lower and upper
+// bounds (inclusive) in each dimension, or nil if the slice is empty.
+func sliceArray(arr *DArray, lower, upper []int) (*DArray, error) {
+	first := arr.FirstIndex()
+	lo, hi := lower[0], upper[0]
+	if lo < first {
+		lo = first
 	}
-	if subscriptIdx < 1 || subscriptIdx > arr.Len() {
-		return DNull, nil
+	if last := arr.Len() - 1 + first; hi > last {
+		hi = last
+	}
+	if lo > hi {
+		return nil, nil
+	}
+
+	res := NewDArray(arr.ParamTyp)
+	res.customOid = arr.customOid
+	for i := lo; i <= hi; i++ {
+		elem := arr.Array[i-first]
+		if len(lower) > 1 {
+			inner, err := sliceArray(MustBeDArray(elem), lower[1:], upper[1:])
+			if err != nil || inner == nil {
+				return nil, err
+			}
+			elem = inner
+		}
+		if err := res.Append(elem); err != nil {
+			return nil, err
+		}
 	}
-	return arr.Array[subscriptIdx-1], nil
+	return res, nil
 }
 
 // Eval implements the TypedExpr interface.
@@ -4363,6 +4509,16 @@ func (t *DJSON) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
 }
 
+// Eval implements the TypedExpr interface.
+func (t *DTSVector) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
+// Eval implements the TypedExpr interface.
+func (t *DTSQuery) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
 // Eval implements the TypedExpr interface.
 func (t dNull) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
@@ -325,6 +325,7 @@ const (
 	JSONSomeExists
 	JSONAllExists
 	Overlaps
+	TSMatches
 
 	// The following operators will always be used with an associated SubOperator.
 	// If Go had algebraic data types they would be defined in a self-contained
@@ -375,6 +376,7 @@ var comparisonOpName = [...]string{
 	JSONSomeExists:    "?|",
 	JSONAllExists:     "?&",
 	Overlaps:          "&&",
+	TSMatches:         "@@",
 	Any:               "ANY",
 	Some:              "SOME",
 	All:               "ALL",
@@ -461,6 +463,25 @@ func NewTypedIndirectionExpr(expr, index TypedExpr, typ *types.T) *IndirectionEx
 	return node
 }
 
+// NewTypedArraySliceExpr returns a new IndirectionExpr that slices an array
+// and is verified to be well-typed. The bounds hold the lower and upper bound
+// of each of the sliced dimensions in turn.
+func NewTypedArraySliceExpr(expr TypedExpr, bounds []TypedExpr, typ *types.T) *IndirectionExpr {
+	node := &IndirectionExpr{
+		Expr:        expr,
+		Indirection: make(ArraySubscripts, 0, len(bounds)/2),
+	}
+	for i := 0; i+1 < len(bounds); i += 2 {
+		node.Indirection = append(node.Indirection, &ArraySubscript{
+			Begin: bounds[i],
+			End:   bounds[i+1],
+			Slice: true,
+		})
+	}
+	node.typ = typ
+	return node
+}
+
 // NewTypedCollateExpr returns a new CollateExpr that is verified to be well-typed.
 func NewTypedCollateExpr(expr TypedExpr, locale string) *CollateExpr {
 	node := &CollateExpr{
@@ -1510,7 +1531,8 @@ var (
 	stringCastTypes = annotateCast(types.String, []*types.T{types.Unknown, types.Bool, types.Int, types.Float, types.Decimal, types.String, types.AnyCollatedString,
 		types.VarBit,
 		types.AnyArray, types.AnyTuple,
-		types.Bytes, types.Timestamp, types.TimestampTZ, types.Interval, types.Uuid, types.Date, types.Time, types.TimeTZ, types.Oid, types.INet, types.Jsonb})
+		types.Bytes, types.Timestamp, types.TimestampTZ, types.Interval, types.Uuid, types.Date, types.Time, types.TimeTZ, types.Oid, types.INet, types.Jsonb,
+		types.TSVector, types.TSQuery})
 	bytesCastTypes = annotateCast(types.Bytes, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.Bytes, types.Uuid})
 	dateCastTypes  = annotateCast(types.Date, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.Date, types.Timestamp, types.TimestampTZ, types.Int})
 	timeCastTypes  = annotateCast(types.Time, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.Time, types.TimeTZ,
@@ -1523,6 +1545,8 @@ var (
 	inetCastTypes      = annotateCast(types.INet, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.INet})
 	arrayCastTypes     = annotateCast(types.AnyArray, []*types.T{types.Unknown, types.String})
 	jsonCastTypes      = annotateCast(types.Jsonb, []*types.T{types.Unknown, types.String, types.Jsonb})
+	tsvectorCastTypes  = annotateCast(types.TSVector, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.TSVector})
+	tsqueryCastTypes   = annotateCast(types.TSQuery, []*types.T{types.Unknown, types.String, types.AnyCollatedString, types.TSQuery})
 )
 
 // validCastTypes returns a set of types that can be cast into the provided type.
@@ -1554,6 +1578,10 @@ func validCastTypes(t *types.T) []castInfo {
 		return intervalCastTypes
 	case types.JsonFamily:
 		return jsonCastTypes
+	case types.TSVectorFamily:
+		return tsvectorCastTypes
+	case types.TSQueryFamily:
+		return tsqueryCastTypes
 	case types.UuidFamily:
 		return uuidCastTypes
 	case types.INetFamily:
@@ -1727,6 +1755,8 @@ func (node *DFloat) String() string           { return AsString(node) }
 func (node *DInt) String() string             { return AsString(node) }
 func (node *DInterval) String() string        { return AsString(node) }
 func (node *DJSON) String() string            { return AsString(node) }
+func (node *DTSVector) String() string        { return AsString(node) }
+func (node *DTSQuery) String() string         { return AsString(node) }
 func (node *DUuid) String() string            { return AsString(node) }
 func (node *DIPAddr) String() string          { return AsString(node) }
 func (node *DString) String() string          { return AsString(node) }
@@ -19,12 +19,12 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
-	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 )
 
 var enclosingError = pgerror.Newf(pgcode.InvalidTextRepresentation, "array must be enclosed in { and }")
 var extraTextError = pgerror.Newf(pgcode.InvalidTextRepresentation, "extra text after closing right brace")
-var nestedArraysNotSupportedError = unimplemented.NewWithIssueDetail(32552, "strcast", "nested arrays not supported")
+var tooManyDimensionsError = pgerror.Newf(pgcode.InvalidTextRepresentation, "array has more dimensions than its type")
+var tooFewDimensionsError = pgerror.Newf(pgcode.InvalidTextRepresentation, "array has fewer dimensions than its type")
 var malformedError = pgerror.Newf(pgcode.InvalidTextRepresentation, "malformed array")
 
 var isQuoteChar = func(ch byte) bool {
@@ -71,10 +71,8 @@ func (p *parseState) gobbleString(isTerminatingChar func(ch byte) bool) (out str
 }
 
 type parseState struct {
-	s      string
-	ctx    ParseTimeContext
-	result *DArray
-	t      *types.T
+	s   string
+	ctx ParseTimeContext
 }
 
 func (p *parseState) advance() {
@@ -109,13 +107,26 @@ func (p *parseState) parseUnquotedString() (string, error) {
 	return strings.TrimSpace(out), nil
 }
 
-func (p *parseState) parseElement() error {
+// parseElement parses a single element of an array whose elements are of type
+// t and appends it to result. Elements of nested arrays are themselves arrays
+// enclosed in { and }.
+func (p *parseState) parseElement(result *DArray, t *types.T) error {
 	var next string
 	var err error
 	r := p.peek()
+	if t.Family() == types.ArrayFamily {
+		if r != '{' {
+			return tooFewDimensionsError
+		}
+		d, err := p.parseArray(t.ArrayContents())
+		if err != nil {
+			return err
+		}
+		return result.Append(d)
+	}
 	switch r {
 	case '{':
-		return nestedArraysNotSupportedError
+		return tooManyDimensionsError
 	case '"':
 		p.advance()
 		next, err = p.parseQuotedString()
@@ -132,20 +143,53 @@ func (p *parseState) parseElement() error {
 			return err
 		}
 		if strings.EqualFold(next, "null") {
-			return p.result.Append(DNull)
+			return result.Append(DNull)
 		}
 	}
 
-	d, err := ParseAndRequireString(p.t, next, p.ctx)
+	d, err := ParseAndRequireString(t, next, p.ctx)
 	if err != nil {
 		return err
 	}
-	return p.result.Append(d)
+	return result.Append(d)
+}
+
+// parseArray parses an array enclosed in { and } whose elements are of type t.
+func (p *parseState) parseArray(t *types.T) (*DArray, error) {
+	result := NewDArray(t)
+	if p.peek() != '{' {
+		return nil, enclosingError
+	}
+	p.advance()
+	p.eatWhitespace()
+	if p.peek() != '}' {
+		if err := p.parseElement(result, t); err != nil {
+			return nil, err
+		}
+		p.eatWhitespace()
+		for p.peek() == ',' {
+			p.advance()
+			p.eatWhitespace()
+			if err := p.parseElement(result, t); err != nil {
+				return nil, err
+			}
+			p.eatWhitespace()
+		}
+	}
+	p.eatWhitespace()
+	if p.eof() {
+		return nil, enclosingError
+	}
+	if p.peek() != '}' {
+		return nil, malformedError
+	}
+	p.advance()
+	return result, nil
 }
 
 // ParseDArrayFromString parses the string-form of constructing arrays, handling
-// cases such as `'{1,2,3}'::INT[]`. The input type t is the type of the
-// parameter of the array to parse.
+// cases such as `'{1,2,3}'::INT[]` and `'{{1,2},{3,4}}'::INT[][]`. The input
+// type t is the type of the parameter of the array to parse.
 func ParseDArrayFromString(ctx ParseTimeContext, s string, t *types.T) (*DArray, error) {
 	ret, err := doParseDArrayFromString(ctx, s, t)
 	if err != nil {
@@ -158,43 +202,19 @@ func ParseDArrayFromString(ctx ParseTimeContext, s string, t *types.T) (*DArray,
 // except the error it returns isn't prettified as a parsing error.
 func doParseDArrayFromString(ctx ParseTimeContext, s string, t *types.T) (*DArray, error) {
 	parser := parseState{
-		s:      s,
-		ctx:    ctx,
-		result: NewDArray(t),
-		t:      t,
+		s:   s,
+		ctx: ctx,
 	}
 
 	parser.eatWhitespace()
-	if parser.peek() != '{' {
-		return nil, enclosingError
-	}
-	parser.advance()
-	parser.eatWhitespace()
-	if parser.peek() != '}' {
-		if err := parser.parseElement(); err != nil {
-			return nil, err
-		}
-		parser.eatWhitespace()
-		for parser.peek() == ',' {
-			parser.advance()
-			parser.eatWhitespace()
-			if err := parser.parseElement(); err != nil {
-				return nil, err
-			}
-		}
-	}
-	parser.eatWhitespace()
-	if parser.eof() {
-		return nil, enclosingError
-	}
-	if parser.peek() != '}' {
-		return nil, malformedError
+	result, err := parser.parseArray(t)
+	if err != nil {
+		return nil, err
 	}
-	parser.advance()
 	parser.eatWhitespace()
 	if !parser.eof() {
 		return nil, extraTextError
 	}
 
-	return parser.result, nil
+	return result, nil
 }
@@ -78,6 +78,15 @@ lo}`, types.String, Datums{NewDString(`hel`), NewDString(`lo`)}},
 		// occur.
 		{string([]byte{'{', 'a', 200, '}'}), types.String, Datums{NewDString("a\xc8")}},
 		{string([]byte{'{', 'a', 200, 'a', '}'}), types.String, Datums{NewDString("a\xc8a")}},
+
+		{`{}`, types.IntArray, Datums{}},
+		{`{{}}`, types.IntArray, Datums{intArray()}},
+		{`{{1,2},{3,4}}`, types.IntArray, Datums{intArray(1, 2), intArray(3, 4)}},
+		{` { { 1 , 2 } , {3,4} } `, types.IntArray, Datums{intArray(1, 2), intArray(3, 4)}},
+		{`{{{1}},{{2}}}`, types.MakeArray(types.IntArray), Datums{
+			makeArray(types.IntArray, intArray(1)),
+			makeArray(types.IntArray, intArray(2)),
+		}},
 	}
 	for _, td := range testData {
 		t.Run(td.str, func(t *testing.T) {
@@ -99,6 +108,28 @@ lo}`, types.String, Datums{NewDString(`hel`), NewDString(`lo`)}},
 	}
 }
 
+// makeArray returns an array of the given element type and elements.
+func makeArray(typ *types.T, elems ...Datum) *DArray {
+	arr := NewDArray(typ)
+	for _, d := range elems {
+		if err := arr.Append(d); err != nil {
+			panic(err)
+		}
+	}
+	return arr
+}
+
+// intArray returns a one-dimensional INT array of the given values.
+func intArray(vals ...int) *DArray {
+	arr := makeArray(types.Int)
+	for _, v := range vals {
+		if err := arr.Append(NewDInt(DInt(v))); err != nil {
+			panic(err)
+		}
+	}
+	return arr
+}
+
 const randomArrayIterations = 1000
 const randomArrayMaxLength = 10
 const randomStringMaxLength = 1000
@@ -168,8 +199,12 @@ func TestParseArrayError(t *testing.T) {
 		{`{,}`, types.Int, `could not parse "{,}" as type int[]: malformed array`},
 		{`{}{}`, types.Int, `could not parse "{}{}" as type int[]: extra text after closing right brace`},
 		{`{} {}`, types.Int, `could not parse "{} {}" as type int[]: extra text after closing right brace`},
-		{`{{}}`, types.Int, `could not parse "{{}}" as type int[]: unimplemented: nested arrays not supported`},
-		{`{1, {1}}`, types.Int, `could not parse "{1, {1}}" as type int[]: unimplemented: nested arrays not supported`},
+		{`{{}}`, types.Int, `could not parse "{{}}" as type int[]: array has more dimensions than its type`},
+		{`{1, {1}}`, types.Int, `could not parse "{1, {1}}" as type int[]: array has more dimensions than its type`},
+		{`{1,2}`, types.IntArray, `could not parse "{1,2}" as type int[][]: array has fewer dimensions than its type`},
+		{`{{1},2}`, types.IntArray, `could not parse "{{1},2}" as type int[][]: array has fewer dimensions than its type`},
+		{`{{1},NULL}`, types.IntArray, `could not parse "{{1},NULL}" as type int[][]: array has fewer dimensions than its type`},
+		{`{{1,2},{3}}`, types.IntArray, `could not parse "{{1,2},{3}}" as type int[][]: multidimensional arrays must have array expressions with matching dimensions`},
 		{`{hello}`, types.Int, `could not parse "{hello}" as type int[]: could not parse "hello" as type int: strconv.ParseInt: parsing "hello": invalid syntax`},
 		{`{"hello}`, types.String, `could not parse "{\"hello}" as type string[]: malformed array`},
 		// It might be unnecessary to disallow this, but Postgres does.
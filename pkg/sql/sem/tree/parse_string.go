@@ -45,6 +45,10 @@ func ParseAndRequireString(t *types.T, s string, ctx ParseTimeContext) (Datum, e
 		return ParseDIntervalWithTypeMetadata(s, itm)
 	case types.JsonFamily:
 		return ParseDJSON(s)
+	case types.TSQueryFamily:
+		return ParseDTSQuery(s)
+	case types.TSVectorFamily:
+		return ParseDTSVector(s)
 	case types.OidFamily:
 		i, err := ParseDInt(s)
 		return NewDOid(*i), err
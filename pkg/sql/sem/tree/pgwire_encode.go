@@ -92,8 +92,6 @@ func (d *DArray) pgwireFormat(ctx *FmtCtx) {
 	case oid.T_int2vector, oid.T_oidvector:
 		// vectors are serialized as a string of space-separated values.
 		sep := ""
-		// TODO(justin): add a test for nested arrays when #32552 is
-		// addressed.
 		for _, d := range d.Array {
 			ctx.WriteString(sep)
 			ctx.FormatNode(d)
@@ -117,6 +115,10 @@ func (d *DArray) pgwireFormat(ctx *FmtCtx) {
 			// double escaped.
 		case *DBytes:
 			ctx.FormatNode(dv)
+		case *DArray:
+			// Nested arrays are printed inline without quoting, e.g.
+			// {{1,2},{3,4}}.
+			dv.pgwireFormat(ctx)
 		default:
 			s := AsStringWithFlags(v, ctx.flags)
 			pgwireFormatStringInArray(&ctx.Buffer, s)
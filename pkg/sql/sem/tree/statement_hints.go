@@ -0,0 +1,283 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// CreateStatementHints represents a CREATE STATEMENT HINTS statement.
+type CreateStatementHints struct {
+	// Statement is the SQL text of the statement (or of its fingerprint) that
+	// the hints are bound to.
+	Statement string
+	// Hints is the textual form of the hints (see StatementHints).
+	Hints string
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateStatementHints) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE STATEMENT HINTS FOR ")
+	lex.EncodeSQLStringWithFlags(&ctx.Buffer, node.Statement, ctx.flags.EncodeFlags())
+	ctx.WriteString(" USING ")
+	lex.EncodeSQLStringWithFlags(&ctx.Buffer, node.Hints, ctx.flags.EncodeFlags())
+}
+
+// DropStatementHints represents a DROP STATEMENT HINTS statement.
+type DropStatementHints struct {
+	// Statement is the SQL text of the statement (or of its fingerprint) whose
+	// hints are removed.
+	Statement string
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DropStatementHints) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP STATEMENT HINTS FOR ")
+	lex.EncodeSQLStringWithFlags(&ctx.Buffer, node.Statement, ctx.flags.EncodeFlags())
+}
+
+// ShowStatementHints represents a SHOW STATEMENT HINTS statement.
+type ShowStatementHints struct{}
+
+// Format implements the NodeFormatter interface.
+func (node *ShowStatementHints) Format(ctx *FmtCtx) {
+	ctx.WriteString("SHOW STATEMENT HINTS")
+}
+
+// StatementHints is a set of optimizer hints that is bound to a statement
+// fingerprint with CREATE STATEMENT HINTS. The hints are applied whenever a
+// statement with a matching fingerprint is planned, as if the equivalent
+// inline hints had been written in the statement. Tables are referred to by
+// the name (or alias) they are given in the statement.
+//
+// The textual form of the hints is a whitespace-separated list of:
+//
+//   INDEX(<table>, <index>)  scan <table> using <index>, like <table>@<index>.
+//   HASH_JOIN(<table>)       use a hash, merge or lookup join for the join that
+//   MERGE_JOIN(<table>)      has <table> on its right side, like
+//   LOOKUP_JOIN(<table>)     INNER HASH JOIN <table>.
+//   NO_JOIN_REORDER          do not reorder joins, like reorder_joins_limit = 0.
+//
+type StatementHints struct {
+	// Indexes maps table names to the index that should be used to scan them.
+	Indexes map[Name]UnrestrictedName
+	// Joins maps table names to the algorithm (AstHash, AstMerge or AstLookup)
+	// that should be used for the join that has the table on its right side.
+	Joins map[Name]string
+	// NoJoinReorder is set if the optimizer should not reorder joins, as if the
+	// reorder_joins_limit session setting were 0.
+	NoJoinReorder bool
+}
+
+// statementHintJoins maps the join hint keywords to join algorithms.
+var statementHintJoins = map[string]string{
+	"HASH_JOIN":   AstHash,
+	"MERGE_JOIN":  AstMerge,
+	"LOOKUP_JOIN": AstLookup,
+}
+
+// ParseStatementHints parses the textual form of a set of statement hints.
+func ParseStatementHints(s string) (*StatementHints, error) {
+	toks, err := tokenizeStatementHints(s)
+	if err != nil {
+		return nil, err
+	}
+	h := &StatementHints{}
+	for len(toks) > 0 {
+		tok := toks[0]
+		toks = toks[1:]
+		if tok.punct != 0 || tok.quoted {
+			return nil, pgerror.Newf(pgcode.Syntax, "expected statement hint, found %s", tok)
+		}
+		var args []Name
+		switch kw := strings.ToUpper(tok.val); kw {
+		case "NO_JOIN_REORDER":
+			if h.NoJoinReorder {
+				return nil, pgerror.New(pgcode.Syntax, "NO_JOIN_REORDER specified multiple times")
+			}
+			h.NoJoinReorder = true
+
+		case "INDEX":
+			if args, toks, err = parseStatementHintArgs(kw, toks, 2); err != nil {
+				return nil, err
+			}
+			if _, ok := h.Indexes[args[0]]; ok {
+				return nil, pgerror.Newf(pgcode.Syntax,
+					"multiple index hints for table %s", &args[0])
+			}
+			if h.Indexes == nil {
+				h.Indexes = make(map[Name]UnrestrictedName)
+			}
+			h.Indexes[args[0]] = UnrestrictedName(args[1])
+
+		case "HASH_JOIN", "MERGE_JOIN", "LOOKUP_JOIN":
+			if args, toks, err = parseStatementHintArgs(kw, toks, 1); err != nil {
+				return nil, err
+			}
+			if _, ok := h.Joins[args[0]]; ok {
+				return nil, pgerror.Newf(pgcode.Syntax,
+					"multiple join hints for table %s", &args[0])
+			}
+			if h.Joins == nil {
+				h.Joins = make(map[Name]string)
+			}
+			h.Joins[args[0]] = statementHintJoins[kw]
+
+		default:
+			return nil, pgerror.Newf(pgcode.Syntax, "unknown statement hint %s", tok)
+		}
+	}
+	return h, nil
+}
+
+// String returns the canonical textual form of the hints, which can be parsed
+// with ParseStatementHints. Two sets of hints are equal iff their canonical
+// forms are equal.
+func (h *StatementHints) String() string {
+	if h == nil {
+		return ""
+	}
+	var parts []string
+	if h.NoJoinReorder {
+		parts = append(parts, "NO_JOIN_REORDER")
+	}
+	for _, t := range sortedHintTables(len(h.Indexes), func(fn func(Name)) {
+		for t := range h.Indexes {
+			fn(t)
+		}
+	}) {
+		idx := Name(h.Indexes[t])
+		parts = append(parts, "INDEX("+t.String()+", "+idx.String()+")")
+	}
+	for _, t := range sortedHintTables(len(h.Joins), func(fn func(Name)) {
+		for t := range h.Joins {
+			fn(t)
+		}
+	}) {
+		parts = append(parts, h.Joins[t]+"_JOIN("+t.String()+")")
+	}
+	return strings.Join(parts, " ")
+}
+
+// sortedHintTables returns the table names produced by forEach, sorted.
+func sortedHintTables(n int, forEach func(fn func(Name))) []Name {
+	res := make([]Name, 0, n)
+	forEach(func(t Name) { res = append(res, t) })
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
+
+// parseStatementHintArgs parses a parenthesized, comma-separated list of n
+// names following the statement hint keyword kw. It returns the names and the
+// remaining tokens.
+func parseStatementHintArgs(
+	kw string, toks []statementHintToken, n int,
+) ([]Name, []statementHintToken, error) {
+	expect := func(punct byte) error {
+		if len(toks) == 0 || toks[0].punct != punct {
+			return pgerror.Newf(pgcode.Syntax, "%s requires %d argument(s)", kw, n)
+		}
+		toks = toks[1:]
+		return nil
+	}
+	if err := expect('('); err != nil {
+		return nil, nil, err
+	}
+	args := make([]Name, n)
+	for i := range args {
+		if i > 0 {
+			if err := expect(','); err != nil {
+				return nil, nil, err
+			}
+		}
+		if len(toks) == 0 || toks[0].punct != 0 {
+			return nil, nil, pgerror.Newf(pgcode.Syntax, "%s requires %d argument(s)", kw, n)
+		}
+		args[i] = Name(toks[0].val)
+		toks = toks[1:]
+	}
+	if err := expect(')'); err != nil {
+		return nil, nil, err
+	}
+	return args, toks, nil
+}
+
+// statementHintToken is a token of the textual form of statement hints. It is
+// either a punctuation character (one of "(),") or an identifier.
+type statementHintToken struct {
+	punct byte
+	// val is the identifier. Unquoted identifiers are normalized to lower case,
+	// like SQL identifiers.
+	val    string
+	quoted bool
+}
+
+func (t statementHintToken) String() string {
+	if t.punct != 0 {
+		return "\"" + string(t.punct) + "\""
+	}
+	return lex.EscapeSQLString(t.val)
+}
+
+// tokenizeStatementHints splits the textual form of statement hints into
+// tokens.
+func tokenizeStatementHints(s string) ([]statementHintToken, error) {
+	var toks []statementHintToken
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c == '(' || c == ')' || c == ',':
+			toks = append(toks, statementHintToken{punct: c})
+			i++
+
+		case c == '"':
+			// A quoted identifier, where "" is an escaped double quote.
+			var b strings.Builder
+			i++
+			for {
+				if i >= len(s) {
+					return nil, pgerror.New(pgcode.Syntax, "unterminated quoted identifier in statement hints")
+				}
+				if s[i] == '"' {
+					if i+1 < len(s) && s[i+1] == '"' {
+						b.WriteByte('"')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				b.WriteByte(s[i])
+				i++
+			}
+			toks = append(toks, statementHintToken{val: b.String(), quoted: true})
+
+		case lex.IsIdentStart(int(c)):
+			j := i + 1
+			for j < len(s) && lex.IsIdentMiddle(int(s[j])) {
+				j++
+			}
+			toks = append(toks, statementHintToken{val: lex.NormalizeName(s[i:j])})
+			i = j
+
+		default:
+			return nil, pgerror.Newf(pgcode.Syntax, "unexpected character %q in statement hints", c)
+		}
+	}
+	return toks, nil
+}
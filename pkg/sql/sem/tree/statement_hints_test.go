@@ -0,0 +1,76 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+)
+
+func TestParseStatementHints(t *testing.T) {
+	testData := []struct {
+		in       string
+		expected string
+		err      string
+	}{
+		{in: ``, expected: ``},
+		{in: `NO_JOIN_REORDER`, expected: `NO_JOIN_REORDER`},
+		{in: `no_join_reorder`, expected: `NO_JOIN_REORDER`},
+		{in: `INDEX(t, idx)`, expected: `INDEX(t, idx)`},
+		{in: ` index ( T , IDX ) `, expected: `INDEX(t, idx)`},
+		{in: `INDEX("T", "my idx")`, expected: `INDEX("T", "my idx")`},
+		{in: `INDEX(t, "a""b")`, expected: `INDEX(t, "a""b")`},
+		{
+			in:       `LOOKUP_JOIN(u) INDEX(u, idx) HASH_JOIN(t) NO_JOIN_REORDER INDEX(t, primary)`,
+			expected: `NO_JOIN_REORDER INDEX(t, "primary") INDEX(u, idx) HASH_JOIN(t) LOOKUP_JOIN(u)`,
+		},
+		{in: `MERGE_JOIN(t)`, expected: `MERGE_JOIN(t)`},
+
+		{in: `FOO`, err: `unknown statement hint 'foo'`},
+		{in: `"INDEX"(t, idx)`, err: `expected statement hint, found 'INDEX'`},
+		{in: `(`, err: `expected statement hint, found "\("`},
+		{in: `INDEX`, err: `INDEX requires 2 argument\(s\)`},
+		{in: `INDEX(t)`, err: `INDEX requires 2 argument\(s\)`},
+		{in: `INDEX(t, idx, x)`, err: `INDEX requires 2 argument\(s\)`},
+		{in: `HASH_JOIN(t, u)`, err: `HASH_JOIN requires 1 argument\(s\)`},
+		{in: `INDEX(t, a) INDEX(t, b)`, err: `multiple index hints for table t`},
+		{in: `HASH_JOIN(t) MERGE_JOIN(t)`, err: `multiple join hints for table t`},
+		{in: `NO_JOIN_REORDER NO_JOIN_REORDER`, err: `NO_JOIN_REORDER specified multiple times`},
+		{in: `INDEX(t, "idx`, err: `unterminated quoted identifier`},
+		{in: `INDEX(t; idx)`, err: `unexpected character ';'`},
+	}
+	for _, d := range testData {
+		t.Run(d.in, func(t *testing.T) {
+			hints, err := ParseStatementHints(d.in)
+			if d.err != "" {
+				if !testutils.IsError(err, d.err) {
+					t.Fatalf("expected error %q, got %v", d.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := hints.String(); s != d.expected {
+				t.Fatalf("expected %q, got %q", d.expected, s)
+			}
+			// The canonical form must round-trip.
+			again, err := ParseStatementHints(hints.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := again.String(); s != d.expected {
+				t.Fatalf("expected %q after round-trip, got %q", d.expected, s)
+			}
+		})
+	}
+}
@@ -77,6 +77,12 @@ func SampleDatum(t *types.T) Datum {
 	case types.JsonFamily:
 		j, _ := ParseDJSON(`{"a": "b"}`)
 		return j
+	case types.TSVectorFamily:
+		v, _ := ParseDTSVector(`'fat':2 'cat':3A`)
+		return v
+	case types.TSQueryFamily:
+		q, _ := ParseDTSQuery(`'fat' & 'cat'`)
+		return q
 	case types.OidFamily:
 		return NewDOid(DInt(1009))
 	default:
@@ -58,6 +58,12 @@ type SemaContext struct {
 	// globally for the entire txn and this field would not be needed.
 	AsOfTimestamp *hlc.Timestamp
 
+	// AsOfBoundedStalenessMinTimestamp denotes the minimum timestamp of a
+	// bounded staleness AS OF SYSTEM TIME clause for the query, if any. It is
+	// never set at the same time as AsOfTimestamp, as the timestamp at which a
+	// bounded staleness query runs is only determined after planning.
+	AsOfBoundedStalenessMinTimestamp *hlc.Timestamp
+
 	Properties SemaProperties
 }
 
@@ -485,22 +491,40 @@ func (expr *CastExpr) TypeCheck(ctx *SemaContext, _ *types.T) (TypedExpr, error)
 
 // TypeCheck implements the Expr interface.
 func (expr *IndirectionExpr) TypeCheck(ctx *SemaContext, desired *types.T) (TypedExpr, error) {
-	for i, t := range expr.Indirection {
+	// If any of the subscripts is a slice, the result is an array of the same
+	// type as the input; otherwise each subscript removes one dimension.
+	isSlice := false
+	for _, t := range expr.Indirection {
 		if t.Slice {
-			return nil, unimplemented.NewWithIssuef(32551, "ARRAY slicing in %s", expr)
+			isSlice = true
 		}
-		if i > 0 {
-			return nil, unimplemented.NewWithIssueDetailf(32552, "ind", "multidimensional indexing: %s", expr)
+		if t.Begin != nil {
+			beginExpr, err := typeCheckAndRequire(ctx, t.Begin, types.Int, "ARRAY subscript")
+			if err != nil {
+				return nil, err
+			}
+			t.Begin = beginExpr
 		}
-
-		beginExpr, err := typeCheckAndRequire(ctx, t.Begin, types.Int, "ARRAY subscript")
-		if err != nil {
-			return nil, err
+		if t.End != nil {
+			endExpr, err := typeCheckAndRequire(ctx, t.End, types.Int, "ARRAY subscript")
+			if err != nil {
+				return nil, err
+			}
+			t.End = endExpr
 		}
-		t.Begin = beginExpr
 	}
 
-	subExpr, err := expr.Expr.TypeCheck(ctx, types.MakeArray(desired))
+	desiredArray := types.MakeArray(desired)
+	switch {
+	case desired.Family() == types.AnyFamily:
+	case isSlice:
+		desiredArray = desired
+	default:
+		for range expr.Indirection[1:] {
+			desiredArray = types.MakeArray(desiredArray)
+		}
+	}
+	subExpr, err := expr.Expr.TypeCheck(ctx, desiredArray)
 	if err != nil {
 		return nil, err
 	}
@@ -508,10 +532,22 @@ func (expr *IndirectionExpr) TypeCheck(ctx *SemaContext, desired *types.T) (Type
 	if typ.Family() != types.ArrayFamily {
 		return nil, pgerror.Newf(pgcode.DatatypeMismatch, "cannot subscript type %s because it is not an array", typ)
 	}
+	elemTyp := typ
+	for range expr.Indirection {
+		if elemTyp.Family() != types.ArrayFamily {
+			return nil, pgerror.Newf(pgcode.ArraySubscript,
+				"too many subscripts for type %s", typ)
+		}
+		elemTyp = elemTyp.ArrayContents()
+	}
 	expr.Expr = subExpr
-	expr.typ = typ.ArrayContents()
-
-	telemetry.Inc(sqltelemetry.ArraySubscriptCounter)
+	if isSlice {
+		expr.typ = typ
+		telemetry.Inc(sqltelemetry.ArraySliceCounter)
+	} else {
+		expr.typ = elemTyp
+		telemetry.Inc(sqltelemetry.ArraySubscriptCounter)
+	}
 	return expr, nil
 }
 
@@ -1386,6 +1422,14 @@ func (d *DInterval) TypeCheck(_ *SemaContext, _ *types.T) (TypedExpr, error) { r
 // identity function for Datum.
 func (d *DJSON) TypeCheck(_ *SemaContext, _ *types.T) (TypedExpr, error) { return d, nil }
 
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DTSVector) TypeCheck(_ *SemaContext, _ *types.T) (TypedExpr, error) { return d, nil }
+
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DTSQuery) TypeCheck(_ *SemaContext, _ *types.T) (TypedExpr, error) { return d, nil }
+
 // TypeCheck implements the Expr interface. It is implemented as an idempotent
 // identity function for Datum.
 func (d *DTuple) TypeCheck(_ *SemaContext, _ *types.T) (TypedExpr, error) { return d, nil }
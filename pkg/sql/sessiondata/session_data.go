@@ -58,6 +58,10 @@ type SessionData struct {
 	// ReorderJoinsLimit indicates the number of joins at which the optimizer should
 	// stop attempting to reorder.
 	ReorderJoinsLimit int
+	// GreedyJoinReorderEnabled indicates whether the optimizer should build a
+	// join order greedily for joins that have more than ReorderJoinsLimit
+	// joins, instead of keeping the order in the query.
+	GreedyJoinReorderEnabled bool
 	// RequireExplicitPrimaryKeys indicates whether CREATE TABLE statements should
 	// error out if no primary key is provided.
 	RequireExplicitPrimaryKeys bool
@@ -77,6 +77,16 @@ var supportedZoneConfigOptions = map[tree.Name]struct {
 		loadYAML(&c.LeasePreferences, string(tree.MustBeDString(d)))
 		c.InheritedLeasePreferences = false
 	}},
+	"num_voters": {types.Int, func(c *zonepb.ZoneConfig, d tree.Datum) { c.NumVoters = proto.Int32(int32(tree.MustBeDInt(d))) }},
+	"voter_constraints": {types.String, func(c *zonepb.ZoneConfig, d tree.Datum) {
+		constraintsList := zonepb.ConstraintsList{
+			Constraints: c.VoterConstraints,
+			Inherited:   c.InheritedVoterConstraints,
+		}
+		loadYAML(&constraintsList, string(tree.MustBeDString(d)))
+		c.VoterConstraints = constraintsList.Constraints
+		c.InheritedVoterConstraints = false
+	}},
 }
 
 // zoneOptionKeys contains the keys from suportedZoneConfigOptions in
@@ -634,6 +644,8 @@ func (n *setZoneConfigNode) startExec(params runParams) error {
 			// RangeMinBytes and RangeMaxBytes must be set together
 			// LeasePreferences cannot be set unless Constraints are explicitly set
 			// Per-replica constraints cannot be set unless num_replicas is explicitly set
+			// NumVoters cannot be set unless num_replicas is explicitly set
+			// VoterConstraints cannot be set unless num_voters is explicitly set
 			if err := finalZone.ValidateTandemFields(); err != nil {
 				err = errors.Wrap(err, "could not validate zone config")
 				err = pgerror.WithCandidateCode(err, pgcode.InvalidParameterValue)
@@ -709,7 +721,16 @@ type nodeGetter func(context.Context, *serverpb.NodesRequest) (*serverpb.NodesRe
 // will be rejected. Additionally, invalid constraints such as
 // [+region=us-east1, -region=us-east1] will also be rejected.
 func validateNoRepeatKeysInZone(zone *zonepb.ZoneConfig) error {
-	for _, constraints := range zone.Constraints {
+	if err := validateNoRepeatKeysInConstraints(zone.Constraints); err != nil {
+		return err
+	}
+	return validateNoRepeatKeysInConstraints(zone.VoterConstraints)
+}
+
+// validateNoRepeatKeysInConstraints ensures that no conjunction in the given
+// constraints contains incompatible constraints.
+func validateNoRepeatKeysInConstraints(constraintsList []zonepb.Constraints) error {
+	for _, constraints := range constraintsList {
 		// Because we expect to have a small number of constraints, a nested
 		// loop is probably better than allocating a map.
 		for i, curr := range constraints.Constraints {
@@ -757,7 +778,7 @@ func validateNoRepeatKeysInZone(zone *zonepb.ZoneConfig) error {
 func validateZoneAttrsAndLocalities(
 	ctx context.Context, getNodes nodeGetter, zone *zonepb.ZoneConfig,
 ) error {
-	if len(zone.Constraints) == 0 && len(zone.LeasePreferences) == 0 {
+	if len(zone.Constraints) == 0 && len(zone.VoterConstraints) == 0 && len(zone.LeasePreferences) == 0 {
 		return nil
 	}
 
@@ -787,6 +808,11 @@ func validateZoneAttrsAndLocalities(
 			addToValidate(constraint)
 		}
 	}
+	for _, constraints := range zone.VoterConstraints {
+		for _, constraint := range constraints.Constraints {
+			addToValidate(constraint)
+		}
+	}
 	for _, leasePreferences := range zone.LeasePreferences {
 		for _, constraint := range leasePreferences.Constraints {
 			addToValidate(constraint)
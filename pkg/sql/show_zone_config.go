@@ -208,6 +208,22 @@ func zoneConfigToSQL(zs *tree.ZoneSpecifier, zone *zonepb.ZoneConfig) (string, e
 	if !zone.InheritedLeasePreferences {
 		writeComma(f, useComma)
 		f.Printf("\tlease_preferences = %s", lex.EscapeSQLString(prefs))
+		useComma = true
+	}
+	if zone.NumVoters != nil {
+		writeComma(f, useComma)
+		f.Printf("\tnum_voters = %d", *zone.NumVoters)
+		useComma = true
+	}
+	if !zone.InheritedVoterConstraints && len(zone.VoterConstraints) > 0 {
+		voterConstraints, err := yamlMarshalFlow(zonepb.ConstraintsList{
+			Constraints: zone.VoterConstraints,
+			Inherited:   zone.InheritedVoterConstraints})
+		if err != nil {
+			return "", err
+		}
+		writeComma(f, useComma)
+		f.Printf("\tvoter_constraints = %s", lex.EscapeSQLString(strings.TrimSpace(voterConstraints)))
 	}
 	return f.String(), nil
 }
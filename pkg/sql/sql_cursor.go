@@ -0,0 +1,529 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec/execbuilder"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/optbuilder"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/xform"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// cursorTempStorageLimit bounds the temporary storage used by the rows of a
+// single cursor.
+var cursorTempStorageLimit = settings.RegisterPublicByteSizeSetting(
+	"sql.cursors.temp_storage_limit",
+	"maximum size of the temporary storage used by the rows of a cursor. DECLARE "+
+		"runs the query of the cursor to completion and stores all of its rows, which "+
+		"spill to temporary storage past the distsql working memory limit, before any "+
+		"of them is fetched; DECLARE fails if they exceed this size",
+	1<<30, /* 1 GiB */
+)
+
+// sqlCursor is a cursor declared with DECLARE.
+//
+// The cursor's query is planned along with the DECLARE statement, so that it
+// can use the statement's placeholders, and is run to completion when DECLARE
+// is executed, in the session's transaction and on the session's goroutine,
+// like a subquery would be. Its rows are stored in a row container that spills
+// to temporary storage past the distsql working memory limit, from which FETCH
+// and MOVE consume them. As a consequence, the rows returned by a cursor
+// reflect the state of the database as of the DECLARE statement, and errors
+// encountered by the query are returned by DECLARE, not by the FETCH that
+// would have reached the failing row.
+//
+// Unlike in Postgres, the execution of the query is not suspended until the
+// rows are fetched: it can't be, since the query would have to keep running
+// (and using the transaction) while the session executes other statements.
+// Declaring a cursor thus costs as much as running its query in full, and the
+// rows it stores are limited by sql.cursors.temp_storage_limit.
+type sqlCursor struct {
+	name tree.Name
+	// stmt is the DECLARE statement, as reported by pg_cursors.
+	stmt    string
+	created time.Time
+	cols    sqlbase.ResultColumns
+
+	// rows contains the rows of the query that haven't been consumed yet.
+	rows rowcontainer.DiskBackedRowContainer
+	// iter is the iterator over rows. It discards the rows as they are
+	// consumed.
+	iter rowcontainer.RowIterator
+	// memMonitor and diskMonitor are rows' monitors.
+	memMonitor  *mon.BytesMonitor
+	diskMonitor *mon.BytesMonitor
+	// diskLimit is the limit of diskMonitor (see cursorTempStorageLimit).
+	diskLimit int64
+
+	types      []types.T
+	scratch    sqlbase.EncDatumRow
+	datumAlloc sqlbase.DatumAlloc
+
+	// pos is the position of the cursor: 0 before the first row, n when
+	// positioned on the n-th row.
+	pos int64
+	// curRow is the row the cursor is positioned on, if any.
+	curRow tree.Datums
+	// exhausted is set once the query has no more rows to produce.
+	exhausted bool
+}
+
+// sqlCursors contains the cursors declared in a session's transaction.
+type sqlCursors struct {
+	cursors map[tree.Name]*sqlCursor
+	// mon is the parent monitor of the cursors' memory monitors. Cursors
+	// outlive the statements that declare them, so this is the session's
+	// monitor.
+	mon *mon.BytesMonitor
+}
+
+func (s *sqlCursors) get(name tree.Name) (*sqlCursor, error) {
+	c, ok := s.cursors[name]
+	if !ok {
+		return nil, pgerror.Newf(pgcode.InvalidCursorName, "cursor %q does not exist", name)
+	}
+	return c, nil
+}
+
+func (s *sqlCursors) add(c *sqlCursor) {
+	if s.cursors == nil {
+		s.cursors = make(map[tree.Name]*sqlCursor)
+	}
+	s.cursors[c.name] = c
+}
+
+func (s *sqlCursors) closeCursor(ctx context.Context, name tree.Name) error {
+	c, err := s.get(name)
+	if err != nil {
+		return err
+	}
+	c.close(ctx)
+	delete(s.cursors, name)
+	return nil
+}
+
+// closeAll closes all the cursors. It is called before the transaction in
+// which they were declared commits or rolls back, as well as once it has
+// finished for any other reason.
+func (s *sqlCursors) closeAll(ctx context.Context) {
+	for name, c := range s.cursors {
+		c.close(ctx)
+		delete(s.cursors, name)
+	}
+}
+
+func newSQLCursor(
+	params runParams, n *tree.DeclareCursor, cols sqlbase.ResultColumns, parentMon *mon.BytesMonitor,
+) *sqlCursor {
+	cfg := &params.ExecCfg().DistSQLSrv.ServerConfig
+	c := &sqlCursor{
+		name:       n.Name,
+		stmt:       n.String(),
+		created:    timeutil.Now(),
+		cols:       cols,
+		memMonitor: execinfra.NewLimitedMonitor(params.ctx, parentMon, cfg, "cursor-mem"),
+		types:      make([]types.T, len(cols)),
+		scratch:    make(sqlbase.EncDatumRow, len(cols)),
+	}
+	c.diskLimit = cursorTempStorageLimit.Get(&cfg.Settings.SV)
+	diskMonitor := mon.MakeMonitorInheritWithLimit("cursor-disk", c.diskLimit, cfg.DiskMonitor)
+	diskMonitor.Start(params.ctx, cfg.DiskMonitor, mon.BoundAccount{})
+	c.diskMonitor = &diskMonitor
+	for i := range cols {
+		c.types[i] = *cols[i].Typ
+	}
+	c.rows.Init(
+		nil, /* ordering */
+		c.types,
+		params.EvalContext(),
+		cfg.TempStorage,
+		c.memMonitor,
+		c.diskMonitor,
+		0, /* rowCapacity */
+	)
+	return c
+}
+
+// run runs the cursor's query and stores its rows.
+func (c *sqlCursor) run(params runParams, plan *planTop) error {
+	rw := newCallbackResultWriter(func(ctx context.Context, row tree.Datums) error {
+		for i := range row {
+			c.scratch[i] = sqlbase.DatumToEncDatum(&c.types[i], row[i])
+		}
+		if err := c.rows.AddRow(ctx, c.scratch); err != nil {
+			if pgerror.GetPGCode(err) == pgcode.DiskFull {
+				return errors.WithHint(pgerror.Newf(pgcode.ProgramLimitExceeded,
+					"rows of cursor %q exceed the temporary storage limit of %s",
+					c.name, humanizeutil.IBytes(c.diskLimit)),
+					"DECLARE stores all the rows of the query before they are fetched; "+
+						"consider narrowing the query or raising sql.cursors.temp_storage_limit")
+			}
+			return err
+		}
+		return nil
+	})
+	if err := runPlanInsidePlan(params, plan, rw); err != nil {
+		return err
+	}
+	c.iter = c.rows.NewFinalIterator(params.ctx)
+	c.iter.Rewind()
+	return nil
+}
+
+// close releases the rows of the cursor.
+func (c *sqlCursor) close(ctx context.Context) {
+	if c.iter != nil {
+		c.iter.Close()
+	}
+	c.rows.Close(ctx)
+	c.memMonitor.Stop(ctx)
+	c.diskMonitor.Stop(ctx)
+}
+
+// read returns the next row of the query, or nil once there are no more rows.
+func (c *sqlCursor) read() (tree.Datums, error) {
+	if ok, err := c.iter.Valid(); err != nil || !ok {
+		return nil, err
+	}
+	encRow, err := c.iter.Row()
+	if err != nil {
+		return nil, err
+	}
+	row := make(tree.Datums, len(encRow))
+	for i := range encRow {
+		if err := encRow[i].EnsureDecoded(&c.types[i], &c.datumAlloc); err != nil {
+			return nil, err
+		}
+		row[i] = encRow[i].Datum
+	}
+	c.iter.Next()
+	return row, nil
+}
+
+// next advances the cursor by one row and returns that row, or nil once the
+// cursor is positioned after the last row.
+func (c *sqlCursor) next(ctx context.Context) (tree.Datums, error) {
+	if !c.exhausted {
+		row, err := c.read()
+		if err != nil {
+			return nil, err
+		}
+		if row != nil {
+			c.pos++
+			c.curRow = row
+			return row, nil
+		}
+		c.exhausted = true
+	}
+	if c.curRow != nil {
+		// Move past the last row.
+		c.pos++
+		c.curRow = nil
+	}
+	return nil, nil
+}
+
+// cursorMove describes how a FETCH or MOVE statement advances a cursor. Only
+// forward moves are supported, since the rows of a cursor are not kept around.
+type cursorMove struct {
+	// current is set if the row the cursor is positioned on is returned first.
+	current bool
+	// last is set if the cursor moves to its last row, which is returned.
+	last bool
+	// skip is the number of rows skipped before rows are returned.
+	skip int64
+	// count is the number of rows returned after skipping, or -1 for all of
+	// them.
+	count int64
+}
+
+var errCursorBackward = pgerror.New(pgcode.ObjectNotInPrerequisiteState,
+	"cursor can only scan forward")
+
+// makeCursorMove translates a FETCH or MOVE statement into a cursorMove for the
+// current position of the cursor.
+func (c *sqlCursor) makeCursorMove(s *tree.CursorStmt) (cursorMove, error) {
+	switch s.FetchType {
+	case tree.FetchNormal:
+		if s.Count > 0 {
+			return cursorMove{count: s.Count}, nil
+		} else if s.Count == 0 {
+			return cursorMove{current: true}, nil
+		}
+	case tree.FetchRelative:
+		if s.Count > 0 {
+			return cursorMove{skip: s.Count - 1, count: 1}, nil
+		} else if s.Count == 0 {
+			return cursorMove{current: true}, nil
+		}
+	case tree.FetchAbsolute:
+		switch {
+		case s.Count > c.pos:
+			return cursorMove{skip: s.Count - c.pos - 1, count: 1}, nil
+		case s.Count > 0 && s.Count == c.pos:
+			return cursorMove{current: true}, nil
+		case s.Count == 0 && c.pos == 0:
+			return cursorMove{}, nil
+		case s.Count == -1:
+			return cursorMove{last: true}, nil
+		}
+	case tree.FetchFirst:
+		if c.pos == 0 {
+			return cursorMove{count: 1}, nil
+		} else if c.pos == 1 {
+			return cursorMove{current: true}, nil
+		}
+	case tree.FetchLast:
+		return cursorMove{last: true}, nil
+	case tree.FetchAll:
+		return cursorMove{count: -1}, nil
+	case tree.FetchBackwardAll:
+		if c.pos == 0 {
+			return cursorMove{}, nil
+		}
+	}
+	return cursorMove{}, errCursorBackward
+}
+
+// step returns the next row of a FETCH or MOVE, or nil when there are no more
+// rows to return.
+func (c *sqlCursor) step(ctx context.Context, m *cursorMove) (tree.Datums, error) {
+	if m.current {
+		m.current = false
+		return c.curRow, nil
+	}
+	if m.last {
+		m.last = false
+		var last tree.Datums
+		for {
+			row, err := c.next(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if row == nil {
+				break
+			}
+			last = row
+		}
+		if last != nil {
+			// Stay positioned on the last row.
+			c.pos--
+			c.curRow = last
+		}
+		return last, nil
+	}
+	for ; m.skip > 0; m.skip-- {
+		row, err := c.next(ctx)
+		if err != nil || row == nil {
+			m.count = 0
+			return nil, err
+		}
+	}
+	if m.count == 0 {
+		return nil, nil
+	}
+	if m.count > 0 {
+		m.count--
+	}
+	return c.next(ctx)
+}
+
+// DeclareCursor implements the DECLARE statement.
+// See https://www.postgresql.org/docs/current/sql-declare.html for details.
+func (p *planner) DeclareCursor(ctx context.Context, n *tree.DeclareCursor) (planNode, error) {
+	if p.extendedEvalCtx.TxnImplicit {
+		return nil, pgerror.Newf(pgcode.NoActiveSQLTransaction,
+			"DECLARE CURSOR can only be used in transaction blocks")
+	}
+	plan, err := p.planCursorQuery(ctx, n.Select)
+	if err != nil {
+		return nil, err
+	}
+	return &declareCursorNode{n: n, plan: plan}, nil
+}
+
+// planCursorQuery plans the query of a cursor. The query is planned by an
+// optimizer of its own, since the planner's optimizer is busy building the
+// DECLARE statement. When the statement is being prepared, the query is only
+// built in order to type its placeholders, and no plan is returned.
+func (p *planner) planCursorQuery(ctx context.Context, query *tree.Select) (*planTop, error) {
+	// Unlike the arguments of the other opaque statements, the query may
+	// contain subqueries.
+	p.semaCtx.Properties.Clear()
+
+	var o xform.Optimizer
+	o.Init(p.EvalContext(), &p.optPlanningCtx.catalog)
+	f := o.Factory()
+	bld := optbuilder.New(ctx, &p.semaCtx, p.EvalContext(), &p.optPlanningCtx.catalog, f, query)
+	if err := bld.Build(); err != nil {
+		return nil, err
+	}
+	if p.isPreparing {
+		return nil, nil
+	}
+	root, err := o.Optimize()
+	if err != nil {
+		return nil, err
+	}
+	execFactory := makeExecFactory(p)
+	plan, err := execbuilder.New(
+		&execFactory, f.Memo(), &p.optPlanningCtx.catalog, root, p.EvalContext(),
+	).Build()
+	if err != nil {
+		return nil, err
+	}
+	return plan.(*planTop), nil
+}
+
+type declareCursorNode struct {
+	n *tree.DeclareCursor
+	// plan is the plan of the cursor's query. It is nil if the statement is
+	// only being prepared.
+	plan *planTop
+}
+
+func (n *declareCursorNode) startExec(params runParams) error {
+	cursors := params.p.extendedEvalCtx.SQLCursors
+	if _, ok := cursors.cursors[n.n.Name]; ok {
+		return pgerror.Newf(pgcode.DuplicateCursor, "cursor %q already exists", n.n.Name)
+	}
+	c := newSQLCursor(params, n.n, planColumns(n.plan.plan), cursors.mon)
+	if err := c.run(params, n.plan); err != nil {
+		c.close(params.ctx)
+		return err
+	}
+	cursors.add(c)
+	return nil
+}
+
+func (*declareCursorNode) Next(runParams) (bool, error) { return false, nil }
+func (*declareCursorNode) Values() tree.Datums          { return nil }
+
+func (n *declareCursorNode) Close(ctx context.Context) {
+	if n.plan != nil {
+		n.plan.close(ctx)
+	}
+}
+
+// FetchCursor implements the FETCH statement.
+// See https://www.postgresql.org/docs/current/sql-fetch.html for details.
+func (p *planner) FetchCursor(ctx context.Context, n *tree.FetchCursor) (planNode, error) {
+	c, err := p.extendedEvalCtx.SQLCursors.get(n.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &fetchCursorNode{n: n.CursorStmt, cursor: c, columns: c.cols}, nil
+}
+
+// MoveCursor implements the MOVE statement.
+// See https://www.postgresql.org/docs/current/sql-move.html for details.
+func (p *planner) MoveCursor(ctx context.Context, n *tree.MoveCursor) (planNode, error) {
+	c, err := p.extendedEvalCtx.SQLCursors.get(n.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &fetchCursorNode{n: n.CursorStmt, cursor: c, move: true}, nil
+}
+
+// fetchCursorNode implements FETCH and MOVE. FETCH returns the rows of the
+// cursor as they are fetched; MOVE discards them and only counts them.
+type fetchCursorNode struct {
+	n      tree.CursorStmt
+	cursor *sqlCursor
+	move   bool
+	// columns is empty for MOVE.
+	columns sqlbase.ResultColumns
+
+	run struct {
+		m   cursorMove
+		row tree.Datums
+		// rowsAffected is the number of rows moved over by MOVE.
+		rowsAffected int
+	}
+}
+
+func (n *fetchCursorNode) startExec(params runParams) error {
+	m, err := n.cursor.makeCursorMove(&n.n)
+	if err != nil {
+		return err
+	}
+	n.run.m = m
+	if n.move {
+		for {
+			row, err := n.cursor.step(params.ctx, &n.run.m)
+			if err != nil {
+				return err
+			}
+			if row == nil {
+				return nil
+			}
+			n.run.rowsAffected++
+		}
+	}
+	return nil
+}
+
+// FastPathResults implements the planNodeFastPath interface.
+func (n *fetchCursorNode) FastPathResults() (int, bool) {
+	return n.run.rowsAffected, n.move
+}
+
+func (n *fetchCursorNode) Next(params runParams) (bool, error) {
+	if n.move {
+		return false, nil
+	}
+	row, err := n.cursor.step(params.ctx, &n.run.m)
+	if err != nil {
+		return false, err
+	}
+	n.run.row = row
+	return row != nil, nil
+}
+
+func (n *fetchCursorNode) Values() tree.Datums   { return n.run.row }
+func (n *fetchCursorNode) Close(context.Context) {}
+
+// CloseCursor implements the CLOSE statement.
+// See https://www.postgresql.org/docs/current/sql-close.html for details.
+func (p *planner) CloseCursor(ctx context.Context, n *tree.CloseCursor) (planNode, error) {
+	return &closeCursorNode{n: n}, nil
+}
+
+type closeCursorNode struct {
+	n *tree.CloseCursor
+}
+
+func (n *closeCursorNode) startExec(params runParams) error {
+	cursors := params.p.extendedEvalCtx.SQLCursors
+	if n.n.All {
+		cursors.closeAll(params.ctx)
+		return nil
+	}
+	return cursors.closeCursor(params.ctx, n.n.Name)
+}
+
+func (*closeCursorNode) Next(runParams) (bool, error) { return false, nil }
+func (*closeCursorNode) Values() tree.Datums          { return nil }
+func (*closeCursorNode) Close(context.Context)        {}
@@ -320,7 +320,7 @@ func DecodeTableKey(
 		}
 		d, err := tree.NewDCollatedString(r, valType.Locale(), &a.env)
 		return d, rkey, err
-	case types.JsonFamily:
+	case types.JsonFamily, types.TSVectorFamily, types.TSQueryFamily:
 		return tree.DNull, []byte{}, nil
 	case types.BytesFamily:
 		var r []byte
@@ -470,6 +470,10 @@ func EncodeTableValue(
 			return nil, err
 		}
 		return encoding.EncodeJSONValue(appendTo, uint32(colID), encoded), nil
+	case *tree.DTSVector:
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), []byte(t.TSVector.String())), nil
+	case *tree.DTSQuery:
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), []byte(t.TSQuery.String())), nil
 	case *tree.DArray:
 		a, err := encodeArray(t, scratch)
 		if err != nil {
@@ -611,6 +615,20 @@ func decodeUntaggedDatum(a *DatumAlloc, t *types.T, buf []byte) (tree.Datum, []b
 			return nil, b, err
 		}
 		return a.NewDJSON(tree.DJSON{JSON: j}), b, nil
+	case types.TSVectorFamily:
+		b, data, err := encoding.DecodeUntaggedBytesValue(buf)
+		if err != nil {
+			return nil, b, err
+		}
+		d, err := tree.ParseDTSVector(string(data))
+		return d, b, err
+	case types.TSQueryFamily:
+		b, data, err := encoding.DecodeUntaggedBytesValue(buf)
+		if err != nil {
+			return nil, b, err
+		}
+		d, err := tree.ParseDTSQuery(string(data))
+		return d, b, err
 	case types.OidFamily:
 		b, data, err := encoding.DecodeUntaggedIntValue(buf)
 		return a.NewDOid(tree.MakeDOid(tree.DInt(data))), b, err
@@ -750,6 +768,16 @@ func MarshalColumnValue(col *ColumnDescriptor, val tree.Datum) (roachpb.Value, e
 			r.SetBytes(data)
 			return r, nil
 		}
+	case types.TSVectorFamily:
+		if v, ok := val.(*tree.DTSVector); ok {
+			r.SetBytes([]byte(v.TSVector.String()))
+			return r, nil
+		}
+	case types.TSQueryFamily:
+		if v, ok := val.(*tree.DTSQuery); ok {
+			r.SetBytes([]byte(v.TSQuery.String()))
+			return r, nil
+		}
 	case types.ArrayFamily:
 		if v, ok := val.(*tree.DArray); ok {
 			if err := checkElementType(v.ParamTyp, col.Type.ArrayContents()); err != nil {
@@ -930,6 +958,18 @@ func UnmarshalColumnValue(a *DatumAlloc, typ *types.T, value roachpb.Value) (tre
 			return nil, err
 		}
 		return tree.NewDJSON(jsonDatum), nil
+	case types.TSVectorFamily:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		return tree.ParseDTSVector(string(v))
+	case types.TSQueryFamily:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		return tree.ParseDTSQuery(string(v))
 	default:
 		return nil, errors.Errorf("unsupported column type: %s", typ.Family())
 	}
@@ -973,23 +1013,30 @@ func decodeTuple(a *DatumAlloc, tupTyp *types.T, b []byte) (tree.Datum, []byte,
 	return a.NewDTuple(result), b, nil
 }
 
-// encodeArray produces the value encoding for an array.
+// encodeArray produces the value encoding for an array. The elements of
+// multidimensional arrays are encoded in row-major order after the lengths of
+// each of the dimensions.
 func encodeArray(d *tree.DArray, scratch []byte) ([]byte, error) {
 	if err := d.Validate(); err != nil {
 		return scratch, err
 	}
 	scratch = scratch[0:0]
-	elementType, err := datumTypeToArrayElementEncodingType(d.ParamTyp)
+	dims := d.Dimensions()
+	if len(dims) > maxArrayDimensions {
+		return nil, errors.Errorf("arrays can have at most %d dimensions", maxArrayDimensions)
+	}
+	elems, hasNulls := d.Flatten()
+	elementType, err := datumTypeToArrayElementEncodingType(arrayElementType(d.ParamTyp))
 
 	if err != nil {
 		return nil, err
 	}
 	header := arrayHeader{
-		hasNulls: d.HasNulls,
-		// TODO(justin): support multiple dimensions.
-		numDimensions: 1,
+		hasNulls:      hasNulls,
+		numDimensions: len(dims),
+		dimensions:    dims,
 		elementType:   elementType,
-		length:        uint64(d.Len()),
+		length:        uint64(len(elems)),
 		// We don't encode the NULL bitmap in this function because we do it in lockstep with the
 		// main data.
 	}
@@ -998,14 +1045,14 @@ func encodeArray(d *tree.DArray, scratch []byte) ([]byte, error) {
 		return nil, err
 	}
 	nullBitmapStart := len(scratch)
-	if d.HasNulls {
-		for i := 0; i < numBytesInBitArray(d.Len()); i++ {
+	if hasNulls {
+		for i := 0; i < numBytesInBitArray(len(elems)); i++ {
 			scratch = append(scratch, 0)
 		}
 	}
-	for i, e := range d.Array {
+	for i, e := range elems {
 		var err error
-		if d.HasNulls && e == tree.DNull {
+		if hasNulls && e == tree.DNull {
 			setBit(scratch[nullBitmapStart:], i)
 		} else {
 			scratch, err = encodeArrayElement(scratch, e)
@@ -1017,6 +1064,19 @@ func encodeArray(d *tree.DArray, scratch []byte) ([]byte, error) {
 	return scratch, nil
 }
 
+// maxArrayDimensions is the maximum number of dimensions of an array that can
+// be stored in the array header.
+const maxArrayDimensions = 1<<4 - 1
+
+// arrayElementType returns the type of the innermost elements of an array whose
+// parameter type is t.
+func arrayElementType(t *types.T) *types.T {
+	for t.Family() == types.ArrayFamily {
+		t = t.ArrayContents()
+	}
+	return t
+}
+
 // decodeArray decodes the value encoding for an array.
 func decodeArray(a *DatumAlloc, elementType *types.T, b []byte) (tree.Datum, []byte, error) {
 	b, _, _, err := encoding.DecodeNonsortingUvarint(b)
@@ -1035,9 +1095,13 @@ func decodeArrayNoMarshalColumnValue(
 	if err != nil {
 		return nil, b, err
 	}
+	innerType := elementType
+	if header.numDimensions > 1 {
+		innerType = arrayElementType(elementType)
+	}
 	result := tree.DArray{
 		Array:    make(tree.Datums, header.length),
-		ParamTyp: elementType,
+		ParamTyp: innerType,
 	}
 	var val tree.Datum
 	for i := uint64(0); i < header.length; i++ {
@@ -1046,16 +1110,51 @@ func decodeArrayNoMarshalColumnValue(
 			result.HasNulls = true
 		} else {
 			result.HasNonNulls = true
-			val, b, err = decodeUntaggedDatum(a, elementType, b)
+			val, b, err = decodeUntaggedDatum(a, innerType, b)
 			if err != nil {
 				return nil, b, err
 			}
 			result.Array[i] = val
 		}
 	}
+	if header.numDimensions > 1 {
+		nested, _, err := unflattenArray(elementType, header.dimensions, result.Array)
+		if err != nil {
+			return nil, b, err
+		}
+		return nested, b, nil
+	}
 	return &result, b, nil
 }
 
+// unflattenArray builds an array with parameter type t and the given
+// dimensions out of the row-major ordered innermost elements. It returns the
+// elements that were not used.
+func unflattenArray(
+	t *types.T, dims []int, elems tree.Datums,
+) (*tree.DArray, tree.Datums, error) {
+	result := tree.NewDArray(t)
+	for i := 0; i < dims[0]; i++ {
+		var e tree.Datum
+		if len(dims) > 1 {
+			var err error
+			e, elems, err = unflattenArray(t.ArrayContents(), dims[1:], elems)
+			if err != nil {
+				return nil, nil, err
+			}
+		} else {
+			if len(elems) == 0 {
+				return nil, nil, errors.Errorf("array dimensions don't match the number of elements")
+			}
+			e, elems = elems[0], elems[1:]
+		}
+		if err := result.Append(e); err != nil {
+			return nil, nil, err
+		}
+	}
+	return result, elems, nil
+}
+
 // arrayHeader is a parameter passing struct between
 // encodeArray/decodeArray and encodeArrayHeader/decodeArrayHeader.
 //
@@ -1066,6 +1165,9 @@ type arrayHeader struct {
 	hasNulls bool
 	// numDimensions is the number of dimensions in the array.
 	numDimensions int
+	// dimensions is the length of each of the dimensions of a multidimensional
+	// array. It is only encoded if there is more than one dimension.
+	dimensions []int
 	// elementType is the encoding type of the array elements.
 	elementType encoding.Type
 	// length is the total number of elements encoded.
@@ -1108,7 +1210,9 @@ const hasNullFlag = 1 << 4
 // at the beginning of the value encoding.
 func encodeArrayHeader(h arrayHeader, buf []byte) ([]byte, error) {
 	// The header byte we append here is formatted as follows:
-	// * The low 4 bits encode the number of dimensions in the array.
+	// * The low 4 bits encode the number of dimensions in the array. If there
+	//   is more than one dimension, the lengths of the dimensions follow the
+	//   total number of elements.
 	// * The high 4 bits are flags, with the lowest representing whether the array
 	//   contains NULLs, and the rest reserved.
 	headerByte := h.numDimensions
@@ -1118,6 +1222,11 @@ func encodeArrayHeader(h arrayHeader, buf []byte) ([]byte, error) {
 	buf = append(buf, byte(headerByte))
 	buf = encoding.EncodeValueTag(buf, encoding.NoColumnID, h.elementType)
 	buf = encoding.EncodeNonsortingUvarint(buf, h.length)
+	if h.numDimensions > 1 {
+		for _, l := range h.dimensions {
+			buf = encoding.EncodeNonsortingUvarint(buf, uint64(l))
+		}
+	}
 	return buf, nil
 }
 
@@ -1128,6 +1237,7 @@ func decodeArrayHeader(b []byte) (arrayHeader, []byte, error) {
 		return arrayHeader{}, b, errors.Errorf("buffer too small")
 	}
 	hasNulls := b[0]&hasNullFlag != 0
+	numDimensions := int(b[0] & (hasNullFlag - 1))
 	b = b[1:]
 	_, dataOffset, _, encType, err := encoding.DecodeValueTag(b)
 	if err != nil {
@@ -1138,14 +1248,29 @@ func decodeArrayHeader(b []byte) (arrayHeader, []byte, error) {
 	if err != nil {
 		return arrayHeader{}, b, err
 	}
+	var dimensions []int
+	if numDimensions > 1 {
+		dimensions = make([]int, numDimensions)
+		for i := range dimensions {
+			var l uint64
+			b, _, l, err = encoding.DecodeNonsortingUvarint(b)
+			if err != nil {
+				return arrayHeader{}, b, err
+			}
+			dimensions[i] = int(l)
+		}
+	} else {
+		// Arrays encoded by previous versions always had a single dimension.
+		numDimensions = 1
+	}
 	nullBitmap := []byte(nil)
 	if hasNulls {
 		b, nullBitmap = makeBitVec(b, int(length))
 	}
 	return arrayHeader{
-		hasNulls: hasNulls,
-		// TODO(justin): support multiple dimensions.
-		numDimensions: 1,
+		hasNulls:      hasNulls,
+		numDimensions: numDimensions,
+		dimensions:    dimensions,
 		elementType:   encType,
 		length:        length,
 		nullBitmap:    nullBitmap,
@@ -1165,7 +1290,8 @@ func datumTypeToArrayElementEncodingType(t *types.T) (encoding.Type, error) {
 		return encoding.Float, nil
 	case types.DecimalFamily:
 		return encoding.Decimal, nil
-	case types.BytesFamily, types.StringFamily, types.CollatedStringFamily:
+	case types.BytesFamily, types.StringFamily, types.CollatedStringFamily,
+		types.TSVectorFamily, types.TSQueryFamily:
 		return encoding.Bytes, nil
 	case types.TimestampFamily, types.TimestampTZFamily:
 		return encoding.Time, nil
@@ -1247,6 +1373,10 @@ func encodeArrayElement(b []byte, d tree.Datum) ([]byte, error) {
 		return encoding.EncodeUntaggedIntValue(b, int64(t.DInt)), nil
 	case *tree.DCollatedString:
 		return encoding.EncodeUntaggedBytesValue(b, []byte(t.Contents)), nil
+	case *tree.DTSVector:
+		return encoding.EncodeUntaggedBytesValue(b, []byte(t.TSVector.String())), nil
+	case *tree.DTSQuery:
+		return encoding.EncodeUntaggedBytesValue(b, []byte(t.TSQuery.String())), nil
 	case *tree.DOidWrapper:
 		return encodeArrayElement(b, t.Wrapped)
 	default:
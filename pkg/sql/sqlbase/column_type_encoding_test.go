@@ -92,6 +92,44 @@ func TestEncodeTableValue(t *testing.T) {
 	properties.TestingRun(t)
 }
 
+func TestEncodeTableValueNestedArray(t *testing.T) {
+	a := &DatumAlloc{}
+	ctx := tree.NewTestingEvalContext(cluster.MakeTestingClusterSettings())
+	for _, tc := range []struct {
+		str string
+		typ *types.T
+	}{
+		{`{{1,2},{3,4}}`, types.IntArray},
+		{`{{1,NULL,3}}`, types.IntArray},
+		{`{{}}`, types.IntArray},
+		{`{{{a}},{{NULL}}}`, types.MakeArray(types.MakeArray(types.String))},
+	} {
+		t.Run(tc.str, func(t *testing.T) {
+			d, err := tree.ParseDArrayFromString(ctx, tc.str, tc.typ)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := EncodeTableValue(nil, 0, d, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			newD, leftoverBytes, err := DecodeTableValue(a, d.ResolvedType(), b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(leftoverBytes) > 0 {
+				t.Fatalf("leftover bytes: %v", leftoverBytes)
+			}
+			if newD.Compare(ctx, d) != 0 {
+				t.Fatalf("expected %s, got %s", d, newD)
+			}
+			if !newD.ResolvedType().Identical(d.ResolvedType()) {
+				t.Fatalf("expected type %s, got %s", d.ResolvedType(), newD.ResolvedType())
+			}
+		})
+	}
+}
+
 func TestEncodeTableKey(t *testing.T) {
 	a := &DatumAlloc{}
 	ctx := tree.NewTestingEvalContext(cluster.MakeTestingClusterSettings())
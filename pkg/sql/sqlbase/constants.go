@@ -156,5 +156,7 @@ const (
 	PgCatalogStatActivityTableID
 	PgCatalogSecurityLabelTableID
 	PgCatalogSharedSecurityLabelTableID
-	MinVirtualID = PgCatalogSharedSecurityLabelTableID
+	PgCatalogCursorsTableID
+	CrdbInternalClusterContentionEventsTableID
+	MinVirtualID = CrdbInternalClusterContentionEventsTableID
 )
@@ -134,3 +134,8 @@ func (ep *DummySessionAccessor) SetSessionVar(_ context.Context, _, _ string) er
 func (ep *DummySessionAccessor) HasAdminRole(_ context.Context) (bool, error) {
 	return false, errors.WithStack(errEvalSessionVar)
 }
+
+// NotifyChannel is part of the tree.EvalSessionAccessor interface.
+func (ep *DummySessionAccessor) NotifyChannel(_ context.Context, _, _ string) error {
+	return errors.WithStack(errEvalSessionVar)
+}
@@ -11,6 +11,7 @@
 package sqlbase
 
 import (
+	"bytes"
 	"fmt"
 	"sort"
 
@@ -22,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/errors"
 )
 
@@ -854,10 +856,11 @@ func EncodeInvertedIndexKeys(
 	return EncodeInvertedIndexTableKeys(val, keyPrefix)
 }
 
-// EncodeInvertedIndexTableKeys encodes the paths in a JSON `val` and
-// concatenates it with `inKey`and returns a list of buffers per
-// path. The encoded values is guaranteed to be lexicographically
-// sortable, but not guaranteed to be round-trippable during decoding.
+// EncodeInvertedIndexTableKeys encodes the paths in a JSON `val`, the lexemes
+// in a tsvector `val` or the elements of an array `val`, and concatenates it
+// with `inKey`and returns a list of buffers per path, lexeme or element. The
+// encoded values is guaranteed to be lexicographically sortable, but not
+// guaranteed to be round-trippable during decoding.
 func EncodeInvertedIndexTableKeys(val tree.Datum, inKey []byte) (key [][]byte, err error) {
 	if val == tree.DNull {
 		return [][]byte{encoding.EncodeNullAscending(inKey)}, nil
@@ -865,8 +868,56 @@ func EncodeInvertedIndexTableKeys(val tree.Datum, inKey []byte) (key [][]byte, e
 	switch t := tree.UnwrapDatum(nil, val).(type) {
 	case *tree.DJSON:
 		return json.EncodeInvertedIndexKeys(inKey, (t.JSON))
+	case *tree.DTSVector:
+		return encodeTSVectorInvertedIndexKeys(inKey, t.TSVector), nil
+	case *tree.DArray:
+		return encodeArrayInvertedIndexKeys(inKey, t)
 	}
-	return nil, errors.AssertionFailedf("trying to apply inverted index to non JSON type")
+	return nil, errors.AssertionFailedf("trying to apply inverted index to non JSON, tsvector or array type")
+}
+
+// encodeArrayInvertedIndexKeys returns one inverted index key per distinct
+// element of the array, using the ascending key encoding of the element. The
+// elements of multidimensional arrays are indexed individually. NULL elements
+// are not indexed, since they never satisfy the @> and && operators.
+func encodeArrayInvertedIndexKeys(inKey []byte, a *tree.DArray) ([][]byte, error) {
+	elems, _ := a.Flatten()
+	keys := make([][]byte, 0, len(elems))
+	for _, elem := range elems {
+		if elem == tree.DNull {
+			continue
+		}
+		// Make sure that the keys don't share the prefix's backing array.
+		prefix := inKey[:len(inKey):len(inKey)]
+		key, err := EncodeTableKey(prefix, elem, encoding.Ascending)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	// Remove duplicate elements, which would otherwise be written to the same
+	// key more than once.
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i], keys[j]) < 0 })
+	n := 0
+	for i := range keys {
+		if i == 0 || !bytes.Equal(keys[i], keys[n-1]) {
+			keys[n] = keys[i]
+			n++
+		}
+	}
+	return keys[:n], nil
+}
+
+// encodeTSVectorInvertedIndexKeys returns one inverted index key per lexeme in
+// the tsvector. Positions and weights are not part of the key.
+func encodeTSVectorInvertedIndexKeys(inKey []byte, v tsearch.TSVector) [][]byte {
+	keys := make([][]byte, len(v))
+	for i := range v {
+		// Make sure that the keys don't share the prefix's backing array.
+		prefix := inKey[:len(inKey):len(inKey)]
+		keys[i] = encoding.EncodeStringAscending(prefix, v[i].Text)
+	}
+	return keys
 }
 
 // EncodePrimaryIndex constructs a list of k/v pairs for a row encoded as a primary index.
@@ -1294,7 +1294,9 @@ func DatumTypeHasCompositeKeyEncoding(typ *types.T) bool {
 func MustBeValueEncoded(semanticType types.Family) bool {
 	return semanticType == types.ArrayFamily ||
 		semanticType == types.JsonFamily ||
-		semanticType == types.TupleFamily
+		semanticType == types.TupleFamily ||
+		semanticType == types.TSVectorFamily ||
+		semanticType == types.TSQueryFamily
 }
 
 // HasOldStoredColumns returns whether the index has stored columns in the old
@@ -2231,7 +2233,14 @@ func ColumnTypeIsIndexable(t *types.T) bool {
 // ColumnTypeIsInvertedIndexable returns whether the type t is valid to be indexed
 // using an inverted index.
 func ColumnTypeIsInvertedIndexable(t *types.T) bool {
-	return t.Family() == types.JsonFamily
+	switch t.Family() {
+	case types.JsonFamily, types.TSVectorFamily:
+		return true
+	case types.ArrayFamily:
+		// Arrays are indexed by their elements, which must be key-encodable.
+		return ColumnTypeIsIndexable(t.ArrayContents())
+	}
+	return false
 }
 
 func notIndexableError(cols []ColumnDescriptor, inverted bool) error {
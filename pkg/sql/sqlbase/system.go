@@ -241,6 +241,16 @@ CREATE TABLE system.protected_ts_records (
    verified  BOOL NOT NULL DEFAULT (false),
    FAMILY "primary" (id, ts, meta_type, meta, num_spans, spans, verified)
 );`
+
+	// statement_hints binds statement fingerprints to a set of optimizer hints
+	// that are applied whenever a statement with that fingerprint is planned.
+	StatementHintsTableSchema = `
+CREATE TABLE system.statement_hints (
+   fingerprint STRING NOT NULL PRIMARY KEY,
+   hints       STRING NOT NULL,
+   created     TIMESTAMP NOT NULL DEFAULT now(),
+   FAMILY "primary" (fingerprint, hints, created)
+);`
 )
 
 func pk(name string) IndexDescriptor {
@@ -289,6 +299,7 @@ var SystemAllowedPrivileges = map[ID]privilege.List{
 	keys.ReportsMetaTableID:                   privilege.ReadWriteData,
 	keys.ProtectedTimestampsMetaTableID:       privilege.ReadData,
 	keys.ProtectedTimestampsRecordsTableID:    privilege.ReadData,
+	keys.StatementHintsTableID:                privilege.ReadWriteData,
 }
 
 // Helpers used to make some of the TableDescriptor literals below more concise.
@@ -1241,6 +1252,33 @@ var (
 		FormatVersion:  InterleavedFormatVersion,
 		NextMutationID: 1,
 	}
+
+	StatementHintsTable = TableDescriptor{
+		Name:                    "statement_hints",
+		ID:                      keys.StatementHintsTableID,
+		ParentID:                keys.SystemDatabaseID,
+		UnexposedParentSchemaID: keys.PublicSchemaID,
+		Version:                 1,
+		Columns: []ColumnDescriptor{
+			{Name: "fingerprint", ID: 1, Type: *types.String},
+			{Name: "hints", ID: 2, Type: *types.String},
+			{Name: "created", ID: 3, Type: *types.Timestamp, DefaultExpr: &nowString},
+		},
+		NextColumnID: 4,
+		Families: []ColumnFamilyDescriptor{
+			{
+				Name:        "primary",
+				ColumnNames: []string{"fingerprint", "hints", "created"},
+				ColumnIDs:   []ColumnID{1, 2, 3},
+			},
+		},
+		NextFamilyID:   1,
+		PrimaryIndex:   pk("fingerprint"),
+		NextIndexID:    2,
+		Privileges:     NewCustomSuperuserPrivilegeDescriptor(SystemAllowedPrivileges[keys.StatementHintsTableID]),
+		FormatVersion:  InterleavedFormatVersion,
+		NextMutationID: 1,
+	}
 )
 
 // Create a kv pair for the zone config for the given key and config value.
@@ -1293,6 +1331,7 @@ func addSystemDescriptorsToSchema(target *MetadataSchema) {
 	target.AddDescriptor(keys.SystemDatabaseID, &ReplicationCriticalLocalitiesTable)
 	target.AddDescriptor(keys.SystemDatabaseID, &ProtectedTimestampsMetaTable)
 	target.AddDescriptor(keys.SystemDatabaseID, &ProtectedTimestampsRecordsTable)
+	target.AddDescriptor(keys.SystemDatabaseID, &StatementHintsTable)
 }
 
 // addSystemDatabaseToSchema populates the supplied MetadataSchema with the
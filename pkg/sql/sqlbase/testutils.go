@@ -195,6 +195,29 @@ func RandDatumWithNullChance(rng *rand.Rand, typ *types.T, nullChance int) tree.
 			return nil
 		}
 		return &tree.DJSON{JSON: j}
+	case types.TSVectorFamily:
+		var buf bytes.Buffer
+		for i, n := 0, rng.Intn(5); i < n; i++ {
+			fmt.Fprintf(&buf, "%s:%d ", randLexeme(rng), 1+rng.Intn(100))
+		}
+		d, err := tree.ParseDTSVector(buf.String())
+		if err != nil {
+			return nil
+		}
+		return d
+	case types.TSQueryFamily:
+		var buf bytes.Buffer
+		for i, n := 0, 1+rng.Intn(4); i < n; i++ {
+			if i > 0 {
+				buf.WriteString([]string{" & ", " | ", " <-> "}[rng.Intn(3)])
+			}
+			buf.WriteString(randLexeme(rng))
+		}
+		d, err := tree.ParseDTSQuery(buf.String())
+		if err != nil {
+			return nil
+		}
+		return d
 	case types.TupleFamily:
 		tuple := tree.DTuple{D: make(tree.Datums, len(typ.TupleContents()))}
 		for i := range typ.TupleContents() {
@@ -331,6 +354,16 @@ func RandDatumSimple(rng *rand.Rand, typ *types.T) tree.Datum {
 	return datum
 }
 
+// randLexeme returns a random lowercase word for use in tsvector and tsquery
+// datums.
+func randLexeme(rng *rand.Rand) string {
+	p := make([]byte, 1+rng.Intn(8))
+	for i := range p {
+		p[i] = byte('a' + rng.Intn(26))
+	}
+	return string(p)
+}
+
 func randStringSimple(rng *rand.Rand) string {
 	return string('A' + rng.Intn(simpleRange))
 }
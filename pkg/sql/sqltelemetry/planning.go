@@ -53,6 +53,15 @@ var LookupJoinHintUseCounter = telemetry.GetCounterOnce("sql.plan.hints.lookup-j
 // hint.
 var IndexHintUseCounter = telemetry.GetCounterOnce("sql.plan.hints.index")
 
+// StatementHintsUseCounter is to be incremented whenever a query is planned
+// with the hints bound to its fingerprint with CREATE STATEMENT HINTS.
+var StatementHintsUseCounter = telemetry.GetCounterOnce("sql.plan.hints.statement-hints")
+
+// StaleStatementHintsCounter is to be incremented whenever a query is planned
+// without some of the hints bound to its fingerprint, because they no longer
+// apply to the statement (for example, because the hinted index was dropped).
+var StaleStatementHintsCounter = telemetry.GetCounterOnce("sql.plan.hints.statement-hints.stale")
+
 // InterleavedTableJoinCounter is to be incremented whenever an InterleavedTableJoin is planned.
 var InterleavedTableJoinCounter = telemetry.GetCounterOnce("sql.plan.interleaved-table-join")
 
@@ -64,6 +64,10 @@ var ArrayFlattenCounter = telemetry.GetCounterOnce("sql.plan.ops.array.flatten")
 // array subscript expression x[...].
 var ArraySubscriptCounter = telemetry.GetCounterOnce("sql.plan.ops.array.ind")
 
+// ArraySliceCounter is to be incremented upon type checking an
+// array slice expression x[...:...].
+var ArraySliceCounter = telemetry.GetCounterOnce("sql.plan.ops.array.slice")
+
 // IfErrCounter is to be incremented upon type checking an
 // IFERROR(...) expression or analogous.
 var IfErrCounter = telemetry.GetCounterOnce("sql.plan.ops.iferr")
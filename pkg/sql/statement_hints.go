@@ -0,0 +1,377 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/gossip"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/optbuilder"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/xform"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+// StatementHintsCache caches the contents of system.statement_hints. The cache
+// is invalidated whenever the version of the table descriptor changes, which
+// CREATE and DROP STATEMENT HINTS bump after modifying the table. This relies
+// on descriptor leases to make the changes visible on all nodes.
+type StatementHintsCache struct {
+	syncutil.Mutex
+	tableVersion sqlbase.DescriptorVersion
+	// hints maps statement fingerprints to the hints bound to them. It is nil
+	// if the hints haven't been loaded for tableVersion yet.
+	hints map[string]*tree.StatementHints
+	// gossipedVersion is the version of the table descriptor in the most
+	// recently gossiped system config (see Start). When it matches
+	// tableVersion and there are no hints, which is the common case, statements
+	// are planned without looking up the table descriptor. System tables aren't
+	// leased, so that lookup reads the descriptor from the store.
+	gossipedVersion sqlbase.DescriptorVersion
+	// awaitedVersion is the version of the table descriptor written by the
+	// most recent CREATE or DROP STATEMENT HINTS on this node. The descriptor
+	// is always looked up until that version is gossiped, so that the change
+	// is visible to the statements that follow it on this node. If the
+	// transaction that wrote it aborts, the descriptor is looked up until the
+	// next change.
+	awaitedVersion sqlbase.DescriptorVersion
+}
+
+// Start starts a worker that keeps track of the version of the
+// system.statement_hints descriptor in the gossiped system config.
+func (c *StatementHintsCache) Start(ctx context.Context, stopper *stop.Stopper, g *gossip.Gossip) {
+	stopper.RunWorker(ctx, func(ctx context.Context) {
+		descKey := sqlbase.MakeDescMetadataKey(keys.StatementHintsTableID)
+		gossipUpdateC := g.RegisterSystemConfigChannel()
+		for {
+			select {
+			case <-gossipUpdateC:
+				val := g.GetSystemConfig().GetValue(descKey)
+				if val == nil {
+					// The table doesn't exist until the cluster is upgraded.
+					continue
+				}
+				var desc sqlbase.Descriptor
+				if err := val.GetProto(&desc); err != nil {
+					log.Warningf(ctx, "unable to unmarshal statement hints descriptor: %v", err)
+					continue
+				}
+				if table := desc.GetTable(); table != nil {
+					c.Lock()
+					c.gossipedVersion = table.Version
+					c.Unlock()
+				}
+
+			case <-stopper.ShouldStop():
+				return
+			}
+		}
+	})
+}
+
+// knownEmpty returns true if there are no statement hints as of the most
+// recently gossiped version of the table descriptor.
+func (c *StatementHintsCache) knownEmpty() bool {
+	c.Lock()
+	defer c.Unlock()
+	return c.hints != nil && len(c.hints) == 0 &&
+		c.tableVersion == c.gossipedVersion && c.gossipedVersion >= c.awaitedVersion
+}
+
+// awaitVersion records that the given version of the table descriptor was
+// written on this node (see awaitedVersion).
+func (c *StatementHintsCache) awaitVersion(version sqlbase.DescriptorVersion) {
+	c.Lock()
+	defer c.Unlock()
+	if version > c.awaitedVersion {
+		c.awaitedVersion = version
+	}
+}
+
+var statementHintsTableName = tree.MakeTableName("system", "statement_hints")
+
+var invalidClusterForStatementHintsError = pgerror.Newf(pgcode.FeatureNotSupported,
+	"statement hints can only be used once the cluster is fully upgraded to version %s",
+	cluster.VersionByKey(cluster.VersionStatementHints))
+
+type createStatementHintsNode struct {
+	fingerprint string
+	hints       string
+}
+
+// CreateStatementHints binds a set of optimizer hints to the fingerprint of a
+// statement.
+// Privileges: admin.
+func (p *planner) CreateStatementHints(
+	ctx context.Context, n *tree.CreateStatementHints,
+) (planNode, error) {
+	if !cluster.Version.IsActive(ctx, p.ExecCfg().Settings, cluster.VersionStatementHints) {
+		return nil, invalidClusterForStatementHintsError
+	}
+	if err := p.RequireAdminRole(ctx, "CREATE STATEMENT HINTS"); err != nil {
+		return nil, err
+	}
+	stmt, fingerprint, err := parseStatementHintsStatement(n.Statement)
+	if err != nil {
+		return nil, err
+	}
+	hints, err := tree.ParseStatementHints(n.Hints)
+	if err != nil {
+		return nil, err
+	}
+	hintsStr := hints.String()
+	if hintsStr == "" {
+		return nil, pgerror.New(pgcode.Syntax, "no statement hints specified")
+	}
+	if err := p.validateStatementHints(ctx, stmt, hints); err != nil {
+		return nil, err
+	}
+	return &createStatementHintsNode{fingerprint: fingerprint, hints: hintsStr}, nil
+}
+
+// validateStatementHints checks that the hints apply to the statement, i.e.
+// that every hinted table is a data source of the statement and that every
+// hinted index exists. The statement is only built, not executed.
+func (p *planner) validateStatementHints(
+	ctx context.Context, stmt parser.Statement, hints *tree.StatementHints,
+) error {
+	// The statement may contain placeholders, which don't have values.
+	semaCtx := p.semaCtx
+	semaCtx.Properties.Clear()
+	if err := semaCtx.Placeholders.Init(stmt.NumPlaceholders, nil /* typeHints */); err != nil {
+		return err
+	}
+
+	var o xform.Optimizer
+	o.Init(p.EvalContext(), &p.optPlanningCtx.catalog)
+	bld := optbuilder.New(ctx, &semaCtx, p.EvalContext(), &p.optPlanningCtx.catalog, o.Factory(), stmt.AST)
+	bld.KeepPlaceholders = true
+	bld.StatementHints = hints
+	bld.ValidateStatementHints = true
+	return bld.Build()
+}
+
+func (n *createStatementHintsNode) startExec(params runParams) error {
+	_, err := params.extendedEvalCtx.ExecCfg.InternalExecutor.Exec(
+		params.ctx,
+		"create-statement-hints",
+		params.p.txn,
+		`UPSERT INTO system.statement_hints (fingerprint, hints, created) VALUES ($1, $2, now())`,
+		n.fingerprint,
+		n.hints,
+	)
+	if err != nil {
+		return err
+	}
+	return params.p.bumpStatementHintsTableVersion(params.ctx)
+}
+
+func (*createStatementHintsNode) Next(runParams) (bool, error) { return false, nil }
+func (*createStatementHintsNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createStatementHintsNode) Close(context.Context)        {}
+
+type dropStatementHintsNode struct {
+	fingerprint string
+}
+
+// DropStatementHints removes the optimizer hints bound to the fingerprint of a
+// statement.
+// Privileges: admin.
+func (p *planner) DropStatementHints(
+	ctx context.Context, n *tree.DropStatementHints,
+) (planNode, error) {
+	if !cluster.Version.IsActive(ctx, p.ExecCfg().Settings, cluster.VersionStatementHints) {
+		return nil, invalidClusterForStatementHintsError
+	}
+	if err := p.RequireAdminRole(ctx, "DROP STATEMENT HINTS"); err != nil {
+		return nil, err
+	}
+	_, fingerprint, err := parseStatementHintsStatement(n.Statement)
+	if err != nil {
+		return nil, err
+	}
+	return &dropStatementHintsNode{fingerprint: fingerprint}, nil
+}
+
+func (n *dropStatementHintsNode) startExec(params runParams) error {
+	rowsAffected, err := params.extendedEvalCtx.ExecCfg.InternalExecutor.Exec(
+		params.ctx,
+		"drop-statement-hints",
+		params.p.txn,
+		`DELETE FROM system.statement_hints WHERE fingerprint = $1`,
+		n.fingerprint,
+	)
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return pgerror.Newf(pgcode.UndefinedObject,
+			"no statement hints found for %s", n.fingerprint)
+	}
+	return params.p.bumpStatementHintsTableVersion(params.ctx)
+}
+
+func (*dropStatementHintsNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropStatementHintsNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropStatementHintsNode) Close(context.Context)        {}
+
+// parseStatementHintsStatement parses the given statement and returns it along
+// with its fingerprint, as shown in the statement statistics.
+func parseStatementHintsStatement(sql string) (parser.Statement, string, error) {
+	stmt, err := parser.ParseOne(sql)
+	if err != nil {
+		return parser.Statement{}, "", err
+	}
+	if !statementHintsApply(stmt.AST) {
+		return parser.Statement{}, "", pgerror.Newf(pgcode.InvalidParameterValue,
+			"statement hints cannot be bound to %s statements", stmt.AST.StatementTag())
+	}
+	return stmt, anonymizeStmt(stmt.AST), nil
+}
+
+// statementHintsApply returns true if statement hints can be bound to the
+// given statement. These are the statements whose memo can be cached (see
+// optPlanningCtx.reset).
+func statementHintsApply(stmt tree.Statement) bool {
+	switch stmt.(type) {
+	case *tree.ParenSelect, *tree.Select, *tree.SelectClause, *tree.UnionClause, *tree.ValuesClause,
+		*tree.Insert, *tree.Update, *tree.Delete:
+		return true
+	}
+	return false
+}
+
+// bumpStatementHintsTableVersion increases the table version of
+// system.statement_hints, which invalidates the StatementHintsCache of all
+// nodes.
+func (p *planner) bumpStatementHintsTableVersion(ctx context.Context) error {
+	tableDesc, err := p.ResolveMutableTableDescriptor(ctx, &statementHintsTableName, true, ResolveAnyDescType)
+	if err != nil {
+		return err
+	}
+	if err := p.writeSchemaChange(ctx, tableDesc, sqlbase.InvalidMutationID); err != nil {
+		return err
+	}
+	if c := p.execCfg.StatementHintsCache; c != nil {
+		c.awaitVersion(tableDesc.Version)
+	}
+	return nil
+}
+
+// lookupStatementHints returns the hints bound to the fingerprint of the
+// statement being planned, or nil if there are none. The hints of a statement
+// also apply to EXPLAIN of that statement.
+func (p *planner) lookupStatementHints(ctx context.Context) (*tree.StatementHints, error) {
+	stmt := p.stmt.AST
+	if explain, ok := stmt.(*tree.Explain); ok {
+		stmt = explain.Statement
+	}
+	if !statementHintsApply(stmt) {
+		return nil, nil
+	}
+	// Internal queries, which include the query that loads the hints, are not
+	// subject to statement hints.
+	if strings.HasPrefix(p.SessionData().ApplicationName, sqlbase.InternalAppNamePrefix) {
+		return nil, nil
+	}
+	if !cluster.Version.IsActive(ctx, p.ExecCfg().Settings, cluster.VersionStatementHints) {
+		return nil, nil
+	}
+	// If the transaction has uncommitted DDL statements, the table version may
+	// belong to a CREATE or DROP STATEMENT HINTS that isn't visible yet. Don't
+	// populate the cache with it.
+	if p.Tables().hasUncommittedTables() {
+		return nil, nil
+	}
+	c := p.execCfg.StatementHintsCache
+	if c == nil || c.knownEmpty() {
+		return nil, nil
+	}
+
+	// Lookup table version.
+	objDesc, err := p.PhysicalSchemaAccessor().GetObjectDesc(ctx, p.txn, p.ExecCfg().Settings,
+		&statementHintsTableName, p.ObjectLookupFlags(true /*required*/, false /*requireMutable*/))
+	if err != nil {
+		return nil, err
+	}
+	tableVersion := objDesc.TableDesc().Version
+
+	// We loop in case the table version changes while we're loading the hints.
+	for {
+		c.Lock()
+		if c.tableVersion != tableVersion {
+			// Update version and drop the hints.
+			c.tableVersion = tableVersion
+			c.hints = nil
+		}
+		hints := c.hints
+		c.Unlock()
+
+		if hints != nil {
+			if len(hints) == 0 {
+				// Avoid computing the fingerprint in the common case.
+				return nil, nil
+			}
+			return hints[anonymizeStmt(stmt)], nil
+		}
+
+		// Load the hints outside the lock.
+		hints, err := p.loadStatementHints(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		c.Lock()
+		if c.tableVersion != tableVersion {
+			// Table version has changed while we were loading, unlock and start over.
+			tableVersion = c.tableVersion
+			c.Unlock()
+			continue
+		}
+		c.hints = hints
+		c.Unlock()
+	}
+}
+
+// loadStatementHints reads all the statement hints from
+// system.statement_hints. The hints are read outside of the planner's
+// transaction so that planning doesn't add the table to its read set.
+func (p *planner) loadStatementHints(
+	ctx context.Context,
+) (map[string]*tree.StatementHints, error) {
+	rows, err := p.ExecCfg().InternalExecutor.Query(
+		ctx, "load-statement-hints", nil, /* txn */
+		`SELECT fingerprint, hints FROM system.statement_hints`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]*tree.StatementHints, len(rows))
+	for _, row := range rows {
+		fingerprint := string(tree.MustBeDString(row[0]))
+		hints, err := tree.ParseStatementHints(string(tree.MustBeDString(row[1])))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid statement hints for %s", fingerprint)
+		}
+		res[fingerprint] = hints
+	}
+	return res, nil
+}
@@ -13,6 +13,7 @@ package stats
 import (
 	"math"
 	"sort"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -157,7 +158,7 @@ func estimatedDistinctValuesInRange(numRange float64, lowerBound, upperBound tre
 // maxDistinctValuesInRange returns the maximum number of distinct values in
 // the range [lowerBound, upperBound). It returns ok=false when it is not
 // possible to determine a finite value (which is the case for all types other
-// than integers and dates).
+// than integers, dates and timestamps).
 func maxDistinctValuesInRange(lowerBound, upperBound tree.Datum) (_ float64, ok bool) {
 	switch lowerBound.ResolvedType().Family() {
 	case types.IntFamily:
@@ -171,11 +172,27 @@ func maxDistinctValuesInRange(lowerBound, upperBound tree.Datum) (_ float64, ok
 		}
 		return 0, false
 
+	case types.TimestampFamily:
+		lower := lowerBound.(*tree.DTimestamp).Time
+		upper := upperBound.(*tree.DTimestamp).Time
+		return microsecondsBetween(lower, upper), true
+
+	case types.TimestampTZFamily:
+		lower := lowerBound.(*tree.DTimestampTZ).Time
+		upper := upperBound.(*tree.DTimestampTZ).Time
+		return microsecondsBetween(lower, upper), true
+
 	default:
 		return 0, false
 	}
 }
 
+// microsecondsBetween returns the number of microseconds in [lower, upper).
+func microsecondsBetween(lower, upper time.Time) float64 {
+	return float64(upper.Unix()-lower.Unix())*1e6 +
+		float64(upper.Nanosecond()/1000-lower.Nanosecond()/1000)
+}
+
 func getNextLowerBound(evalCtx *tree.EvalContext, currentUpperBound tree.Datum) tree.Datum {
 	nextLowerBound, ok := currentUpperBound.Next(evalCtx)
 	if !ok {
@@ -15,6 +15,7 @@ import (
 	"math"
 	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -233,3 +234,53 @@ func TestEquiDepthHistogram(t *testing.T) {
 		}
 	})
 }
+
+func TestMaxDistinctValuesInRange(t *testing.T) {
+	ts := func(s string) time.Time {
+		res, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	testCases := []struct {
+		lower, upper tree.Datum
+		expected     float64
+		ok           bool
+	}{
+		{
+			lower:    tree.NewDInt(1),
+			upper:    tree.NewDInt(10),
+			expected: 9,
+			ok:       true,
+		},
+		{
+			lower:    tree.MakeDTimestamp(ts("2020-01-01T00:00:00Z"), time.Microsecond),
+			upper:    tree.MakeDTimestamp(ts("2020-01-01T00:00:01.5Z"), time.Microsecond),
+			expected: 1500000,
+			ok:       true,
+		},
+		{
+			lower:    tree.MakeDTimestampTZ(ts("1700-01-01T00:00:00Z"), time.Microsecond),
+			upper:    tree.MakeDTimestampTZ(ts("2100-01-01T00:00:00Z"), time.Microsecond),
+			expected: 12622780800000000,
+			ok:       true,
+		},
+		{
+			lower: tree.NewDString("a"),
+			upper: tree.NewDString("b"),
+			ok:    false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s-%s", tc.lower, tc.upper), func(t *testing.T) {
+			res, ok := maxDistinctValuesInRange(tc.lower, tc.upper)
+			if ok != tc.ok {
+				t.Fatalf("expected ok=%t, found ok=%t", tc.ok, ok)
+			}
+			if ok && res != tc.expected {
+				t.Fatalf("expected %f, found %f", tc.expected, res)
+			}
+		})
+	}
+}
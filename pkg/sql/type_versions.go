@@ -0,0 +1,92 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// versionIsActiveForSchema returns whether the given cluster version is active,
+// for the purpose of deciding which types and indexes new schema elements can
+// use. Like MakeTableDesc, it can be called before the cluster version has been
+// initialized (or without settings, in tests), in which case the version is
+// considered active: no user schema can be created at that point.
+func versionIsActiveForSchema(
+	ctx context.Context, st *cluster.Settings, key cluster.VersionKey,
+) bool {
+	if st == nil {
+		return true
+	}
+	version := cluster.Version.ActiveVersionOrEmpty(ctx, st)
+	return version == (cluster.ClusterVersion{}) || version.IsActive(key)
+}
+
+// checkColumnTypeIsSupported returns an error if columns of the given type
+// can't be created until the cluster is upgraded, since nodes running the
+// previous version can't decode them.
+func checkColumnTypeIsSupported(ctx context.Context, st *cluster.Settings, t *types.T) error {
+	switch t.Family() {
+	case types.TSVectorFamily, types.TSQueryFamily:
+		if !versionIsActiveForSchema(ctx, st, cluster.VersionFullTextSearch) {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"type %s can only be used once the cluster is fully upgraded to version %s",
+				t.SQLString(), cluster.VersionByKey(cluster.VersionFullTextSearch))
+		}
+	case types.ArrayFamily:
+		return checkColumnTypeIsSupported(ctx, st, t.ArrayContents())
+	}
+	return nil
+}
+
+// invertedIndexVersion returns the cluster version from which inverted indexes
+// on columns of the given type are supported, if they weren't supported by the
+// previous version. Nodes running the previous version can't encode the
+// entries of these indexes, and don't know about the
+// crdb_internal.num_inverted_index_entries builtin used to validate them.
+func invertedIndexVersion(t *types.T) (cluster.VersionKey, bool) {
+	switch t.Family() {
+	case types.TSVectorFamily:
+		return cluster.VersionFullTextSearch, true
+	case types.ArrayFamily:
+		return cluster.VersionArrayInvertedIndexes, true
+	}
+	return 0, false
+}
+
+// checkInvertedIndexIsSupported returns an error if the given inverted index
+// of desc can't be created until the cluster is upgraded.
+func checkInvertedIndexIsSupported(
+	ctx context.Context,
+	st *cluster.Settings,
+	desc *sqlbase.MutableTableDescriptor,
+	idx *sqlbase.IndexDescriptor,
+) error {
+	for _, name := range idx.ColumnNames {
+		col, _, err := desc.FindColumnByName(tree.Name(name))
+		if err != nil {
+			return err
+		}
+		if key, ok := invertedIndexVersion(&col.Type); ok && !versionIsActiveForSchema(ctx, st, key) {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"inverted indexes on %s columns can only be created once the cluster is "+
+					"fully upgraded to version %s",
+				col.Type.SQLString(), cluster.VersionByKey(key))
+		}
+	}
+	return nil
+}
@@ -325,6 +325,23 @@ var (
 	INet = &T{InternalType: InternalType{
 		Family: INetFamily, Oid: oid.T_inet, Locale: &emptyLocale}}
 
+	// TSVector is the type of a full-text search document, which is a sorted
+	// list of distinct lexemes, each optionally annotated with positions and
+	// weights. For example:
+	//
+	//   'fat':2 'cat':3A
+	//
+	TSVector = &T{InternalType: InternalType{
+		Family: TSVectorFamily, Oid: oid.T_tsvector, Locale: &emptyLocale}}
+
+	// TSQuery is the type of a full-text search query, which combines lexemes
+	// using boolean and phrase operators. For example:
+	//
+	//   'fat' & ('rat' | !'cat')
+	//
+	TSQuery = &T{InternalType: InternalType{
+		Family: TSQueryFamily, Oid: oid.T_tsquery, Locale: &emptyLocale}}
+
 	// Scalar contains all types that meet this criteria:
 	//
 	//   1. Scalar type (no ArrayFamily or TupleFamily types).
@@ -349,6 +366,8 @@ var (
 		TimeTZ,
 		Jsonb,
 		VarBit,
+		TSVector,
+		TSQuery,
 	}
 
 	// Any is a special type used only during static analysis as a wildcard type
@@ -1042,6 +1061,10 @@ func (t *T) Name() string {
 		return "timestamptz"
 	case TimeTZFamily:
 		return "timetz"
+	case TSQueryFamily:
+		return "tsquery"
+	case TSVectorFamily:
+		return "tsvector"
 	case TupleFamily:
 		// Tuple types are currently anonymous, with no name.
 		return ""
@@ -1258,6 +1281,10 @@ func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
 			return "timestamp with time zone"
 		}
 		return fmt.Sprintf("timestamp(%d) with time zone", typmod)
+	case TSQueryFamily:
+		return "tsquery"
+	case TSVectorFamily:
+		return "tsvector"
 	case TupleFamily:
 		return "record"
 	case UnknownFamily:
@@ -1687,11 +1714,6 @@ func (t *T) upgradeType() error {
 			t.InternalType.Oid = calcArrayOid(t.ArrayContents())
 		}
 
-		// Marshaling/unmarshaling nested arrays is not yet supported.
-		if t.ArrayContents().Family() == ArrayFamily {
-			return errors.AssertionFailedf("nested array should never be unmarshaled")
-		}
-
 		// Zero out fields that may have been used to store information about
 		// the array element type, or which are no longer in use.
 		t.InternalType.Width = 0
@@ -1809,9 +1831,11 @@ func (t *T) downgradeType() error {
 		}
 
 	case ArrayFamily:
-		// Marshaling/unmarshaling nested arrays is not yet supported.
 		if t.ArrayContents().Family() == ArrayFamily {
-			return errors.AssertionFailedf("nested array should never be marshaled")
+			// Nested arrays were not supported by previous versions, so there is
+			// no backwards-compatible format to downgrade to. The array contents
+			// are marshaled in their own (downgraded) format.
+			break
 		}
 
 		// Downgrade to array representation used before 19.2, in which the array
@@ -2087,8 +2111,6 @@ var postgresPredefinedTypeIssues = map[string]int{
 	"pg_lsn":        -1,
 	"point":         21286,
 	"polygon":       21286,
-	"tsquery":       7821,
-	"tsvector":      7821,
 	"txid_snapshot": -1,
 	"xml":           -1,
 }
@@ -357,6 +357,16 @@ func TestTypes(t *testing.T) {
 			Family: TimeTZFamily, Oid: oid.T_timetz, Precision: 6, TimePrecisionIsSet: true, Locale: &emptyLocale}}},
 		{MakeTimeTZ(6), MakeScalar(TimeTZFamily, oid.T_timetz, 6, 0, emptyLocale)},
 
+		// TSQUERY
+		{TSQuery, &T{InternalType: InternalType{
+			Family: TSQueryFamily, Oid: oid.T_tsquery, Locale: &emptyLocale}}},
+		{TSQuery, MakeScalar(TSQueryFamily, oid.T_tsquery, 0, 0, emptyLocale)},
+
+		// TSVECTOR
+		{TSVector, &T{InternalType: InternalType{
+			Family: TSVectorFamily, Oid: oid.T_tsvector, Locale: &emptyLocale}}},
+		{TSVector, MakeScalar(TSVectorFamily, oid.T_tsvector, 0, 0, emptyLocale)},
+
 		// TIMESTAMP
 		{Timestamp, &T{InternalType: InternalType{
 			Family: TimestampFamily,
@@ -434,13 +444,7 @@ func TestTypes(t *testing.T) {
 				t.Errorf("expected <%v>, got <%v>", tc.expected.DebugString(), tc.actual.DebugString())
 			}
 
-			// Roundtrip type by marshaling, then unmarshaling. Only do this for non-
-			// nested array types (since we don't yet support marshaling/ummarshaling
-			// nested arrays).
-			if tc.actual.Family() == ArrayFamily && tc.actual.ArrayContents().Family() == ArrayFamily {
-				return
-			}
-
+			// Roundtrip type by marshaling, then unmarshaling.
 			data, err := protoutil.Marshal(tc.actual)
 			if err != nil {
 				t.Errorf("error during marshal of type <%v>: %v", tc.actual.DebugString(), err)
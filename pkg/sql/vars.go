@@ -386,6 +386,25 @@ var varGen = map[string]sessionVar{
 		},
 	},
 
+	// CockroachDB extension.
+	`experimental_enable_greedy_join_reorder`: {
+		GetStringVal: makeBoolGetStringValFn(`experimental_enable_greedy_join_reorder`),
+		Set: func(_ context.Context, m *sessionDataMutator, s string) error {
+			b, err := parsePostgresBool(s)
+			if err != nil {
+				return err
+			}
+			m.SetGreedyJoinReorderEnabled(b)
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext) string {
+			return formatBoolAsPostgresSetting(evalCtx.SessionData.GreedyJoinReorderEnabled)
+		},
+		GlobalDefault: func(sv *settings.Values) string {
+			return formatBoolAsPostgresSetting(greedyJoinReorderClusterMode.Get(sv))
+		},
+	},
+
 	// CockroachDB extension.
 	`require_explicit_primary_keys`: {
 		GetStringVal: makeBoolGetStringValFn(`require_explicit_primary_keys`),
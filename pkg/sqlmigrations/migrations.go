@@ -289,6 +289,13 @@ var backwardCompatibleMigrations = []migrationDescriptor{
 		workFn:              migrateSystemNamespace,
 		includedInBootstrap: cluster.VersionByKey(cluster.VersionNamespaceTableWithSchemas),
 	},
+	{
+		// Introduced in v20.1.
+		name:                "create system.statement_hints table",
+		workFn:              createStatementHintsTable,
+		includedInBootstrap: cluster.VersionByKey(cluster.VersionStatementHints),
+		newDescriptorIDs:    staticIDs(keys.StatementHintsTableID),
+	},
 }
 
 func staticIDs(ids ...sqlbase.ID) func(ctx context.Context, db db) ([]sqlbase.ID, error) {
@@ -699,6 +706,11 @@ func createProtectedTimestampsRecordsTable(ctx context.Context, r runner) error
 		"failed to create system.protected_ts_records")
 }
 
+func createStatementHintsTable(ctx context.Context, r runner) error {
+	return errors.Wrap(createSystemTable(ctx, r, sqlbase.StatementHintsTable),
+		"failed to create system.statement_hints")
+}
+
 func createNewSystemNamespaceDescriptor(ctx context.Context, r runner) error {
 
 	return r.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
@@ -57,6 +57,11 @@ const (
 	removeDeadReplicaPriority               float64 = 1000
 	removeDecommissioningReplicaPriority    float64 = 200
 	removeExtraReplicaPriority              float64 = 100
+	// Non-voting replicas don't affect the availability of a range, so repairs
+	// to them are always less urgent than any of the above.
+	addMissingNonVoterPriority  float64 = 60
+	removeDeadNonVoterPriority  float64 = 50
+	removeExtraNonVoterPriority float64 = 40
 )
 
 // MinLeaseTransferStatsDuration configures the minimum amount of time a
@@ -108,6 +113,8 @@ const (
 	AllocatorConsiderRebalance
 	AllocatorRangeUnavailable
 	AllocatorFinalizeAtomicReplicationChange
+	AllocatorAddNonVoter
+	AllocatorRemoveNonVoter
 )
 
 var allocatorActionNames = map[AllocatorAction]string{
@@ -122,12 +129,23 @@ var allocatorActionNames = map[AllocatorAction]string{
 	AllocatorConsiderRebalance:               "consider rebalance",
 	AllocatorRangeUnavailable:                "range unavailable",
 	AllocatorFinalizeAtomicReplicationChange: "finalize conf change",
+	AllocatorAddNonVoter:                     "add non-voter",
+	AllocatorRemoveNonVoter:                  "remove non-voter",
 }
 
 func (a AllocatorAction) String() string {
 	return allocatorActionNames[a]
 }
 
+// targetReplicaType indicates whether the allocator is making a decision about
+// a voting or a non-voting replica.
+type targetReplicaType int
+
+const (
+	voterTarget targetReplicaType = iota
+	nonVoterTarget
+)
+
 type transferDecision int
 
 const (
@@ -213,9 +231,11 @@ func makeAllocatorRand(source rand.Source) allocatorRand {
 // RangeUsageInfo contains usage information (sizes and traffic) needed by the
 // allocator to make rebalancing decisions for a given range.
 type RangeUsageInfo struct {
-	LogicalBytes     int64
-	QueriesPerSecond float64
-	WritesPerSecond  float64
+	LogicalBytes        int64
+	QueriesPerSecond    float64
+	WritesPerSecond     float64
+	WriteBytesPerSecond float64
+	CPUPerSecond        float64
 }
 
 func rangeUsageInfoForRepl(repl *Replica) RangeUsageInfo {
@@ -228,6 +248,12 @@ func rangeUsageInfoForRepl(repl *Replica) RangeUsageInfo {
 	if writesPerSecond, dur := repl.writeStats.avgQPS(); dur >= MinStatsDuration {
 		info.WritesPerSecond = writesPerSecond
 	}
+	if writeBytesPerSecond, dur := repl.writeBytesStats.avgQPS(); dur >= MinStatsDuration {
+		info.WriteBytesPerSecond = writeBytesPerSecond
+	}
+	if cpuPerSecond, dur := repl.cpuStats.avgQPS(); dur >= MinStatsDuration {
+		info.CPUPerSecond = cpuPerSecond
+	}
 	return info
 }
 
@@ -355,7 +381,13 @@ func (a *Allocator) ComputeAction(
 		return AllocatorRemoveLearner, removeLearnerReplicaPriority
 	}
 	// computeAction expects to operate only on voters.
-	return a.computeAction(ctx, zone, desc.RangeID, desc.Replicas().Voters())
+	voters := desc.Replicas().Voters()
+	action, priority := a.computeAction(ctx, zone, desc.RangeID, voters)
+	if action != AllocatorConsiderRebalance {
+		return action, priority
+	}
+	// The voters are in good shape, so take care of the non-voters.
+	return a.computeNonVoterAction(ctx, zone, desc.RangeID, voters, desc.Replicas().NonVoters())
 }
 
 func (a *Allocator) computeAction(
@@ -368,7 +400,7 @@ func (a *Allocator) computeAction(
 	have := len(voterReplicas)
 	decommissioningReplicas := a.storePool.decommissioningReplicas(rangeID, voterReplicas)
 	clusterNodes := a.storePool.ClusterNodeCount()
-	need := GetNeededReplicas(*zone.NumReplicas, clusterNodes)
+	need := GetNeededReplicas(zone.GetNumVoters(), clusterNodes)
 	desiredQuorum := computeQuorum(need)
 	quorum := computeQuorum(have)
 
@@ -456,28 +488,129 @@ func (a *Allocator) computeAction(
 	return AllocatorConsiderRebalance, 0
 }
 
+// computeNonVoterAction is like computeAction, but for the non-voting replicas
+// of a range. Since non-voters don't participate in quorum, dead or
+// decommissioning non-voters are simply removed and replaced later.
+func (a *Allocator) computeNonVoterAction(
+	ctx context.Context,
+	zone *zonepb.ZoneConfig,
+	rangeID roachpb.RangeID,
+	voterReplicas []roachpb.ReplicaDescriptor,
+	nonVoterReplicas []roachpb.ReplicaDescriptor,
+) (AllocatorAction, float64) {
+	have := len(nonVoterReplicas)
+	need := int(zone.GetNumNonVoters())
+	// Non-voters can't be placed on nodes that already have a voter.
+	if available := a.storePool.ClusterNodeCount() - len(voterReplicas); need > available {
+		need = available
+	}
+	if need < 0 {
+		need = 0
+	}
+
+	if have < need {
+		priority := addMissingNonVoterPriority
+		action := AllocatorAddNonVoter
+		log.VEventf(ctx, 3, "%s - missing non-voter need=%d, have=%d, priority=%.2f",
+			action, need, have, priority)
+		return action, priority
+	}
+
+	_, deadNonVoters := a.storePool.liveAndDeadReplicas(rangeID, nonVoterReplicas)
+	decommissioningNonVoters := a.storePool.decommissioningReplicas(rangeID, nonVoterReplicas)
+	if len(deadNonVoters) > 0 || len(decommissioningNonVoters) > 0 {
+		priority := removeDeadNonVoterPriority
+		action := AllocatorRemoveNonVoter
+		log.VEventf(ctx, 3, "%s - dead=%d, decommissioning=%d, priority=%.2f",
+			action, len(deadNonVoters), len(decommissioningNonVoters), priority)
+		return action, priority
+	}
+
+	if have > need {
+		priority := removeExtraNonVoterPriority
+		action := AllocatorRemoveNonVoter
+		log.VEventf(ctx, 3, "%s - need=%d, have=%d, priority=%.2f", action, need, have, priority)
+		return action, priority
+	}
+
+	return AllocatorConsiderRebalance, 0
+}
+
+// relevantReplicas returns the replicas that a decision about a replica of the
+// given type has to take into account. Voters are placed with respect to the
+// other voters only, while non-voters are placed with respect to all of the
+// replicas of the range.
+func relevantReplicas(
+	existingVoters, existingNonVoters []roachpb.ReplicaDescriptor, targetType targetReplicaType,
+) []roachpb.ReplicaDescriptor {
+	if targetType == voterTarget {
+		return existingVoters
+	}
+	existing := make([]roachpb.ReplicaDescriptor, 0, len(existingVoters)+len(existingNonVoters))
+	existing = append(existing, existingVoters...)
+	return append(existing, existingNonVoters...)
+}
+
+// analyzeConstraints analyzes the constraints that apply to the given replicas
+// of the given type. Voters are subject to the zone's voter constraints (which
+// default to its constraints), while the zone's constraints apply to all of the
+// range's replicas.
+func (a *Allocator) analyzeConstraints(
+	ctx context.Context,
+	zone *zonepb.ZoneConfig,
+	existing []roachpb.ReplicaDescriptor,
+	targetType targetReplicaType,
+) constraint.AnalyzedConstraints {
+	if targetType == voterTarget {
+		return constraint.AnalyzeConstraintsForReplicas(
+			ctx, a.storePool.getStoreDescriptor, existing, zone.GetNumVoters(), zone.GetVoterConstraints())
+	}
+	return constraint.AnalyzeConstraints(ctx, a.storePool.getStoreDescriptor, existing, zone)
+}
+
 type decisionDetails struct {
 	Target   string
 	Existing string `json:",omitempty"`
 }
 
-// AllocateTarget returns a suitable store for a new allocation with the
-// required attributes. Nodes already accommodating existing replicas are ruled
-// out as targets. The range ID of the replica being allocated for is also
-// passed in to ensure that we don't try to replace an existing dead replica on
-// a store.
+// AllocateTarget returns a suitable store for a new voting replica with the
+// required attributes. Nodes already accommodating existing replicas (voting or
+// not) are ruled out as targets. The range ID of the replica being allocated
+// for is also passed in to ensure that we don't try to replace an existing dead
+// replica on a store.
 //
 // TODO(tbg): AllocateReplacement?
 func (a *Allocator) AllocateTarget(
 	ctx context.Context,
 	zone *zonepb.ZoneConfig,
 	rangeID roachpb.RangeID,
-	existingReplicas []roachpb.ReplicaDescriptor,
+	existingVoters, existingNonVoters []roachpb.ReplicaDescriptor,
+) (*roachpb.StoreDescriptor, string, error) {
+	return a.allocateTarget(ctx, zone, rangeID, existingVoters, existingNonVoters, voterTarget)
+}
+
+// AllocateNonVoter is like AllocateTarget, but returns a suitable store for a
+// new non-voting replica.
+func (a *Allocator) AllocateNonVoter(
+	ctx context.Context,
+	zone *zonepb.ZoneConfig,
+	rangeID roachpb.RangeID,
+	existingVoters, existingNonVoters []roachpb.ReplicaDescriptor,
+) (*roachpb.StoreDescriptor, string, error) {
+	return a.allocateTarget(ctx, zone, rangeID, existingVoters, existingNonVoters, nonVoterTarget)
+}
+
+func (a *Allocator) allocateTarget(
+	ctx context.Context,
+	zone *zonepb.ZoneConfig,
+	rangeID roachpb.RangeID,
+	existingVoters, existingNonVoters []roachpb.ReplicaDescriptor,
+	targetType targetReplicaType,
 ) (*roachpb.StoreDescriptor, string, error) {
 	sl, aliveStoreCount, throttled := a.storePool.getStoreList(rangeID, storeFilterThrottled)
 
 	target, details := a.allocateTargetFromList(
-		ctx, sl, zone, existingReplicas, a.scorerOptions())
+		ctx, sl, zone, existingVoters, existingNonVoters, a.scorerOptions(), targetType)
 
 	if target != nil {
 		return target, details, nil
@@ -490,9 +623,13 @@ func (a *Allocator) AllocateTarget(
 			"%d matching stores are currently throttled: %v", len(throttled), throttled,
 		)
 	}
+	constraints := zone.Constraints
+	if targetType == voterTarget {
+		constraints = zone.GetVoterConstraints()
+	}
 	return nil, "", &allocatorError{
-		constraints:      zone.Constraints,
-		existingReplicas: len(existingReplicas),
+		constraints:      constraints,
+		existingReplicas: len(existingVoters) + len(existingNonVoters),
 		aliveStores:      aliveStoreCount,
 		throttledStores:  len(throttled),
 	}
@@ -502,11 +639,17 @@ func (a *Allocator) allocateTargetFromList(
 	ctx context.Context,
 	sl StoreList,
 	zone *zonepb.ZoneConfig,
-	candidateReplicas []roachpb.ReplicaDescriptor,
+	existingVoters, existingNonVoters []roachpb.ReplicaDescriptor,
 	options scorerOptions,
+	targetType targetReplicaType,
 ) (*roachpb.StoreDescriptor, string) {
-	analyzedConstraints := constraint.AnalyzeConstraints(
-		ctx, a.storePool.getStoreDescriptor, candidateReplicas, zone)
+	candidateReplicas := relevantReplicas(existingVoters, existingNonVoters, targetType)
+	if targetType == voterTarget {
+		// Voters are only placed with respect to the other voters, but still
+		// can't go on a node that already has a non-voter.
+		sl = sl.excludeNodes(existingNonVoters)
+	}
+	analyzedConstraints := a.analyzeConstraints(ctx, zone, candidateReplicas, targetType)
 	candidates := allocateCandidates(
 		sl, analyzedConstraints, candidateReplicas, a.storePool.getLocalities(candidateReplicas),
 		options,
@@ -532,6 +675,7 @@ func (a Allocator) simulateRemoveTarget(
 	candidates []roachpb.ReplicaDescriptor,
 	existingReplicas []roachpb.ReplicaDescriptor,
 	rangeUsageInfo RangeUsageInfo,
+	targetType targetReplicaType,
 ) (roachpb.ReplicaDescriptor, string, error) {
 	// Update statistics first
 	// TODO(a-robinson): This could theoretically interfere with decisions made by other goroutines,
@@ -543,19 +687,42 @@ func (a Allocator) simulateRemoveTarget(
 		a.storePool.updateLocalStoreAfterRebalance(targetStore, rangeUsageInfo, roachpb.REMOVE_REPLICA)
 	}()
 	log.VEventf(ctx, 3, "simulating which replica would be removed after adding s%d", targetStore)
-	return a.RemoveTarget(ctx, zone, candidates, existingReplicas)
+	return a.removeTarget(ctx, zone, candidates, existingReplicas, targetType)
 }
 
-// RemoveTarget returns a suitable replica to remove from the provided replica
-// set. It first attempts to randomly select a target from the set of stores
-// that have greater than the average number of replicas. Failing that, it
-// falls back to selecting a random target from any of the existing
+// RemoveTarget returns a suitable voting replica to remove from the provided
+// replica set. It first attempts to randomly select a target from the set of
+// stores that have greater than the average number of replicas. Failing that,
+// it falls back to selecting a random target from any of the existing
 // replicas.
 func (a Allocator) RemoveTarget(
 	ctx context.Context,
 	zone *zonepb.ZoneConfig,
 	candidates []roachpb.ReplicaDescriptor,
 	existingReplicas []roachpb.ReplicaDescriptor,
+) (roachpb.ReplicaDescriptor, string, error) {
+	return a.removeTarget(ctx, zone, candidates, existingReplicas, voterTarget)
+}
+
+// RemoveNonVoter is like RemoveTarget, but selects one of the candidate
+// non-voting replicas for removal.
+func (a Allocator) RemoveNonVoter(
+	ctx context.Context,
+	zone *zonepb.ZoneConfig,
+	candidates []roachpb.ReplicaDescriptor,
+	existingVoters, existingNonVoters []roachpb.ReplicaDescriptor,
+) (roachpb.ReplicaDescriptor, string, error) {
+	return a.removeTarget(
+		ctx, zone, candidates, relevantReplicas(existingVoters, existingNonVoters, nonVoterTarget),
+		nonVoterTarget)
+}
+
+func (a Allocator) removeTarget(
+	ctx context.Context,
+	zone *zonepb.ZoneConfig,
+	candidates []roachpb.ReplicaDescriptor,
+	existingReplicas []roachpb.ReplicaDescriptor,
+	targetType targetReplicaType,
 ) (roachpb.ReplicaDescriptor, string, error) {
 	if len(candidates) == 0 {
 		return roachpb.ReplicaDescriptor{}, "", errors.Errorf("must supply at least one candidate replica to allocator.RemoveTarget()")
@@ -568,8 +735,7 @@ func (a Allocator) RemoveTarget(
 	}
 	sl, _, _ := a.storePool.getStoreListFromIDs(existingStoreIDs, roachpb.RangeID(0), storeFilterNone)
 
-	analyzedConstraints := constraint.AnalyzeConstraints(
-		ctx, a.storePool.getStoreDescriptor, existingReplicas, zone)
+	analyzedConstraints := a.analyzeConstraints(ctx, zone, existingReplicas, targetType)
 	options := a.scorerOptions()
 	rankedCandidates := removeCandidates(
 		sl,
@@ -626,11 +792,47 @@ func (a Allocator) RebalanceTarget(
 	zone *zonepb.ZoneConfig,
 	raftStatus *raft.Status,
 	rangeID roachpb.RangeID,
-	existingReplicas []roachpb.ReplicaDescriptor,
+	existingVoters, existingNonVoters []roachpb.ReplicaDescriptor,
+	rangeUsageInfo RangeUsageInfo,
+	filter storeFilter,
+) (add roachpb.ReplicationTarget, remove roachpb.ReplicationTarget, details string, ok bool) {
+	return a.rebalanceTarget(
+		ctx, zone, raftStatus, rangeID, existingVoters, existingNonVoters, rangeUsageInfo, filter,
+		voterTarget)
+}
+
+// RebalanceNonVoter is like RebalanceTarget, but moves one of the non-voting
+// replicas of the range. Since non-voters don't participate in quorum, neither
+// the liveness of the existing replicas nor the raft status of the range need
+// to be taken into account.
+func (a Allocator) RebalanceNonVoter(
+	ctx context.Context,
+	zone *zonepb.ZoneConfig,
+	rangeID roachpb.RangeID,
+	existingVoters, existingNonVoters []roachpb.ReplicaDescriptor,
 	rangeUsageInfo RangeUsageInfo,
 	filter storeFilter,
+) (add roachpb.ReplicationTarget, remove roachpb.ReplicationTarget, details string, ok bool) {
+	return a.rebalanceTarget(
+		ctx, zone, nil /* raftStatus */, rangeID, existingVoters, existingNonVoters, rangeUsageInfo,
+		filter, nonVoterTarget)
+}
+
+func (a Allocator) rebalanceTarget(
+	ctx context.Context,
+	zone *zonepb.ZoneConfig,
+	raftStatus *raft.Status,
+	rangeID roachpb.RangeID,
+	existingVoters, existingNonVoters []roachpb.ReplicaDescriptor,
+	rangeUsageInfo RangeUsageInfo,
+	filter storeFilter,
+	targetType targetReplicaType,
 ) (add roachpb.ReplicationTarget, remove roachpb.ReplicationTarget, details string, ok bool) {
 	sl, _, _ := a.storePool.getStoreList(rangeID, filter)
+	existingReplicas := relevantReplicas(existingVoters, existingNonVoters, targetType)
+	if targetType == voterTarget {
+		sl = sl.excludeNodes(existingNonVoters)
+	}
 
 	zero := roachpb.ReplicationTarget{}
 
@@ -644,7 +846,7 @@ func (a Allocator) RebalanceTarget(
 	// NB: The len(replicas) > 1 check allows rebalancing of ranges with only a
 	// single replica. This is a corner case which could happen in practice and
 	// also affects tests.
-	if len(existingReplicas) > 1 {
+	if targetType == voterTarget && len(existingReplicas) > 1 {
 		var numLiveReplicas int
 		for _, s := range sl.stores {
 			for _, repl := range existingReplicas {
@@ -662,8 +864,7 @@ func (a Allocator) RebalanceTarget(
 		}
 	}
 
-	analyzedConstraints := constraint.AnalyzeConstraints(
-		ctx, a.storePool.getStoreDescriptor, existingReplicas, zone)
+	analyzedConstraints := a.analyzeConstraints(ctx, zone, existingReplicas, targetType)
 	options := a.scorerOptions()
 	results := rebalanceCandidates(
 		ctx,
@@ -702,6 +903,11 @@ func (a Allocator) RebalanceTarget(
 		existingPlusOneNew := append([]roachpb.ReplicaDescriptor(nil), existingReplicas...)
 		existingPlusOneNew = append(existingPlusOneNew, newReplica)
 		replicaCandidates := existingPlusOneNew
+		if targetType == nonVoterTarget {
+			// Only non-voters are up for removal.
+			replicaCandidates = append([]roachpb.ReplicaDescriptor(nil), existingNonVoters...)
+			replicaCandidates = append(replicaCandidates, newReplica)
+		}
 		// If we can, filter replicas as we would if we were actually removing one.
 		// If we can't (e.g. because we're the leaseholder but not the raft leader),
 		// it's better to simulate the removal with the info that we do have than to
@@ -726,6 +932,7 @@ func (a Allocator) RebalanceTarget(
 			replicaCandidates,
 			existingPlusOneNew,
 			rangeUsageInfo,
+			targetType,
 		)
 		if err != nil {
 			log.Warningf(ctx, "simulating RemoveTarget failed: %+v", err)
@@ -90,7 +90,40 @@ var rangeRebalanceThreshold = func() *settings.FloatSetting {
 type scorerOptions struct {
 	deterministic           bool
 	rangeRebalanceThreshold float64
-	qpsRebalanceThreshold   float64 // only considered if non-zero
+	qpsRebalanceThreshold   float64     // only considered if non-zero
+	loadWeights             loadWeights // only considered if qpsRebalanceThreshold is non-zero
+}
+
+// loadWeights determines how the dimensions of load tracked for stores and
+// replicas are combined into the single load value that load-based
+// rebalancing attempts to balance across stores. The combined load is
+// expressed in units of queries per second, so weighing QPS alone with a
+// weight of 1 balances stores purely on QPS.
+type loadWeights struct {
+	// qps is the load attributed to each query per second.
+	qps float64
+	// writeKiB is the load attributed to each KiB written per second.
+	writeKiB float64
+	// cpuMillis is the load attributed to each millisecond spent evaluating
+	// requests per second.
+	cpuMillis float64
+}
+
+func (w loadWeights) load(qps, writeBytes, cpuNanos float64) float64 {
+	return w.qps*qps + w.writeKiB*writeBytes/1024 + w.cpuMillis*cpuNanos/1e6
+}
+
+// storeLoad returns the combined load of a store.
+func (w loadWeights) storeLoad(c roachpb.StoreCapacity) float64 {
+	return w.load(c.QueriesPerSecond, c.WriteBytesPerSecond, c.CPUPerSecond)
+}
+
+// meanStoreLoad returns the mean combined load of the candidate stores in the
+// store list. Since the combined load is linear in each of its dimensions,
+// this is the combined load of the per-dimension means.
+func (w loadWeights) meanStoreLoad(sl StoreList) float64 {
+	return w.load(sl.candidateQueriesPerSecond.mean, sl.candidateWriteBytesPerSecond.mean,
+		sl.candidateCPUPerSecond.mean)
 }
 
 type balanceDimensions struct {
@@ -429,11 +462,13 @@ func allocateCandidates(
 		balanceScore := balanceScore(sl, s.Capacity, options)
 		var convergesScore int
 		if options.qpsRebalanceThreshold > 0 {
-			if s.Capacity.QueriesPerSecond < underfullThreshold(sl.candidateQueriesPerSecond.mean, options.qpsRebalanceThreshold) {
+			load := options.loadWeights.storeLoad(s.Capacity)
+			meanLoad := options.loadWeights.meanStoreLoad(sl)
+			if load < underfullThreshold(meanLoad, options.qpsRebalanceThreshold) {
 				convergesScore = 1
-			} else if s.Capacity.QueriesPerSecond < sl.candidateQueriesPerSecond.mean {
+			} else if load < meanLoad {
 				convergesScore = 0
-			} else if s.Capacity.QueriesPerSecond < overfullThreshold(sl.candidateQueriesPerSecond.mean, options.qpsRebalanceThreshold) {
+			} else if load < overfullThreshold(meanLoad, options.qpsRebalanceThreshold) {
 				convergesScore = -1
 			} else {
 				convergesScore = -2
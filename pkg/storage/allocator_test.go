@@ -392,6 +392,7 @@ func TestAllocatorSimpleRetrieval(t *testing.T) {
 		&simpleZoneConfig,
 		firstRangeID,
 		[]roachpb.ReplicaDescriptor{},
+		nil, /* existingNonVoters */
 	)
 	if err != nil {
 		t.Fatalf("Unable to perform allocation: %+v", err)
@@ -411,6 +412,7 @@ func TestAllocatorNoAvailableDisks(t *testing.T) {
 		&simpleZoneConfig,
 		firstRangeID,
 		[]roachpb.ReplicaDescriptor{},
+		nil, /* existingNonVoters */
 	)
 	if result != nil {
 		t.Errorf("expected nil result: %+v", result)
@@ -432,6 +434,7 @@ func TestAllocatorTwoDatacenters(t *testing.T) {
 		&multiDCConfig,
 		firstRangeID,
 		[]roachpb.ReplicaDescriptor{},
+		nil, /* existingNonVoters */
 	)
 	if err != nil {
 		t.Fatalf("Unable to perform allocation: %+v", err)
@@ -444,6 +447,7 @@ func TestAllocatorTwoDatacenters(t *testing.T) {
 			NodeID:  result1.Node.NodeID,
 			StoreID: result1.StoreID,
 		}},
+		nil, /* existingNonVoters */
 	)
 	if err != nil {
 		t.Fatalf("Unable to perform allocation: %+v", err)
@@ -468,6 +472,7 @@ func TestAllocatorTwoDatacenters(t *testing.T) {
 				StoreID: result2.StoreID,
 			},
 		},
+		nil, /* existingNonVoters */
 	)
 	if err == nil {
 		t.Errorf("expected error on allocation without available stores: %+v", result3)
@@ -500,6 +505,7 @@ func TestAllocatorExistingReplica(t *testing.T) {
 				StoreID: 2,
 			},
 		},
+		nil, /* existingNonVoters */
 	)
 	if err != nil {
 		t.Fatalf("Unable to perform allocation: %+v", err)
@@ -598,6 +604,7 @@ func TestAllocatorMultipleStoresPerNode(t *testing.T) {
 				zonepb.EmptyCompleteZoneConfig(),
 				firstRangeID,
 				tc.existing,
+				nil, /* existingNonVoters */
 			)
 			if e, a := tc.expectTarget, result != nil; e != a {
 				t.Errorf("AllocateTarget(%v) got target %v, err %v; expectTarget=%v",
@@ -613,6 +620,7 @@ func TestAllocatorMultipleStoresPerNode(t *testing.T) {
 				nil, /* raftStatus */
 				firstRangeID,
 				tc.existing,
+				nil, /* existingNonVoters */
 				rangeUsageInfo,
 				storeFilterThrottled,
 			)
@@ -687,6 +695,7 @@ func TestAllocatorRebalance(t *testing.T) {
 			nil,
 			firstRangeID,
 			[]roachpb.ReplicaDescriptor{{NodeID: 3, StoreID: 3}},
+			nil, /* existingNonVoters */
 			rangeUsageInfo,
 			storeFilterThrottled,
 		)
@@ -838,6 +847,7 @@ func TestAllocatorRebalanceTarget(t *testing.T) {
 			status,
 			firstRangeID,
 			replicas,
+			nil, /* existingNonVoters */
 			rangeUsageInfo,
 			storeFilterThrottled,
 		)
@@ -859,6 +869,7 @@ func TestAllocatorRebalanceTarget(t *testing.T) {
 			status,
 			firstRangeID,
 			replicas,
+			nil, /* existingNonVoters */
 			rangeUsageInfo,
 			storeFilterThrottled,
 		)
@@ -877,6 +888,7 @@ func TestAllocatorRebalanceTarget(t *testing.T) {
 			status,
 			firstRangeID,
 			replicas,
+			nil, /* existingNonVoters */
 			rangeUsageInfo,
 			storeFilterThrottled,
 		)
@@ -957,6 +969,7 @@ func TestAllocatorRebalanceDeadNodes(t *testing.T) {
 				nil,
 				firstRangeID,
 				c.existing,
+				nil, /* existingNonVoters */
 				rangeUsageInfo,
 				storeFilterThrottled)
 			if c.expected > 0 {
@@ -1152,6 +1165,7 @@ func TestAllocatorRebalanceByCount(t *testing.T) {
 			nil,
 			firstRangeID,
 			[]roachpb.ReplicaDescriptor{{StoreID: stores[0].StoreID}},
+			nil, /* existingNonVoters */
 			rangeUsageInfo,
 			storeFilterThrottled,
 		)
@@ -1436,6 +1450,7 @@ func TestAllocatorRebalanceDifferentLocalitySizes(t *testing.T) {
 			nil, /* raftStatus */
 			firstRangeID,
 			tc.existing,
+			nil, /* existingNonVoters */
 			rangeUsageInfo,
 			storeFilterThrottled,
 		)
@@ -1507,6 +1522,7 @@ func TestAllocatorRebalanceDifferentLocalitySizes(t *testing.T) {
 			nil, /* raftStatus */
 			firstRangeID,
 			tc.existing,
+			nil, /* existingNonVoters */
 			rangeUsageInfo,
 			storeFilterThrottled,
 		)
@@ -2092,6 +2108,7 @@ func TestAllocatorAllocateTargetLocality(t *testing.T) {
 			zonepb.EmptyCompleteZoneConfig(),
 			firstRangeID,
 			existingRepls,
+			nil, /* existingNonVoters */
 		)
 		if err != nil {
 			t.Fatal(err)
@@ -2215,6 +2232,7 @@ func TestAllocatorRebalanceTargetLocality(t *testing.T) {
 			nil,
 			firstRangeID,
 			existingRepls,
+			nil, /* existingNonVoters */
 			rangeUsageInfo,
 			storeFilterThrottled,
 		)
@@ -3608,7 +3626,8 @@ func TestRebalanceCandidatesNumReplicasConstraints(t *testing.T) {
 			// Also verify that RebalanceTarget picks out one of the best options as
 			// the final rebalance choice.
 			target, _, details, ok := a.RebalanceTarget(
-				context.Background(), zone, nil, firstRangeID, existingRepls, rangeUsageInfo, storeFilterThrottled)
+				context.Background(), zone, nil, firstRangeID, existingRepls, nil, /* existingNonVoters */
+				rangeUsageInfo, storeFilterThrottled)
 			var found bool
 			if !ok && len(tc.validTargets) == 0 {
 				found = true
@@ -4566,6 +4585,97 @@ func TestAllocatorComputeActionRemoveDead(t *testing.T) {
 	}
 }
 
+func TestAllocatorComputeActionNonVoters(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	zone := zonepb.ZoneConfig{
+		NumReplicas: proto.Int32(5),
+		NumVoters:   proto.Int32(3),
+	}
+	voters := []roachpb.ReplicaDescriptor{
+		{StoreID: 1, NodeID: 1, ReplicaID: 1},
+		{StoreID: 2, NodeID: 2, ReplicaID: 2},
+		{StoreID: 3, NodeID: 3, ReplicaID: 3},
+	}
+	withNonVoters := func(storeIDs ...roachpb.StoreID) roachpb.RangeDescriptor {
+		replicas := append([]roachpb.ReplicaDescriptor(nil), voters...)
+		for _, storeID := range storeIDs {
+			replicas = append(replicas, roachpb.ReplicaDescriptor{
+				StoreID:   storeID,
+				NodeID:    roachpb.NodeID(storeID),
+				ReplicaID: roachpb.ReplicaID(storeID),
+				Type:      roachpb.ReplicaTypeNonVoter(),
+			})
+		}
+		return roachpb.RangeDescriptor{InternalReplicas: replicas}
+	}
+
+	testCases := []struct {
+		desc           roachpb.RangeDescriptor
+		live           []roachpb.StoreID
+		dead           []roachpb.StoreID
+		expectedAction AllocatorAction
+	}{
+		// Needs two non-voters, has none.
+		{
+			desc:           withNonVoters(),
+			live:           []roachpb.StoreID{1, 2, 3, 4, 5},
+			expectedAction: AllocatorAddNonVoter,
+		},
+		// Needs two non-voters, has one.
+		{
+			desc:           withNonVoters(4),
+			live:           []roachpb.StoreID{1, 2, 3, 4, 5},
+			expectedAction: AllocatorAddNonVoter,
+		},
+		// Needs two non-voters, has two.
+		{
+			desc:           withNonVoters(4, 5),
+			live:           []roachpb.StoreID{1, 2, 3, 4, 5},
+			expectedAction: AllocatorConsiderRebalance,
+		},
+		// Needs two non-voters, has two, one is dead.
+		{
+			desc:           withNonVoters(4, 5),
+			live:           []roachpb.StoreID{1, 2, 3, 4, 6},
+			dead:           []roachpb.StoreID{5},
+			expectedAction: AllocatorRemoveNonVoter,
+		},
+		// Needs two non-voters, has three.
+		{
+			desc:           withNonVoters(4, 5, 6),
+			live:           []roachpb.StoreID{1, 2, 3, 4, 5, 6},
+			expectedAction: AllocatorRemoveNonVoter,
+		},
+		// Needs a voter, which takes precedence over the non-voters.
+		{
+			desc:           withNonVoters(4, 5),
+			live:           []roachpb.StoreID{1, 2, 4, 5, 6},
+			dead:           []roachpb.StoreID{3},
+			expectedAction: AllocatorReplaceDead,
+		},
+	}
+
+	stopper, _, sp, a, _ := createTestAllocator(10, false /* deterministic */)
+	ctx := context.Background()
+	defer stopper.Stop(ctx)
+
+	for i, tcase := range testCases {
+		mockStorePool(sp, tcase.live, nil, tcase.dead, nil, nil)
+		action, priority := a.ComputeAction(ctx, &zone, &tcase.desc)
+		if tcase.expectedAction != action {
+			t.Errorf("Test case %d expected action %q, got action %q", i, tcase.expectedAction, action)
+			continue
+		}
+		if action == AllocatorAddNonVoter || action == AllocatorRemoveNonVoter {
+			if priority >= removeExtraReplicaPriority-1 {
+				t.Errorf("Test case %d: non-voter action %q has priority %f, which is not lower "+
+					"than the priorities of all voter actions", i, action, priority)
+			}
+		}
+	}
+}
+
 func TestAllocatorComputeActionDecommission(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -5181,6 +5291,7 @@ func TestAllocatorThrottled(t *testing.T) {
 		&simpleZoneConfig,
 		firstRangeID,
 		[]roachpb.ReplicaDescriptor{},
+		nil, /* existingNonVoters */
 	)
 	if _, ok := err.(purgatoryError); !ok {
 		t.Fatalf("expected a purgatory error, got: %+v", err)
@@ -5193,6 +5304,7 @@ func TestAllocatorThrottled(t *testing.T) {
 		&simpleZoneConfig,
 		firstRangeID,
 		[]roachpb.ReplicaDescriptor{},
+		nil, /* existingNonVoters */
 	)
 	if err != nil {
 		t.Fatalf("unable to perform allocation: %+v", err)
@@ -5215,6 +5327,7 @@ func TestAllocatorThrottled(t *testing.T) {
 		&simpleZoneConfig,
 		firstRangeID,
 		[]roachpb.ReplicaDescriptor{},
+		nil, /* existingNonVoters */
 	)
 	if _, ok := err.(purgatoryError); ok {
 		t.Fatalf("expected a non purgatory error, got: %+v", err)
@@ -5536,6 +5649,7 @@ func TestAllocatorRebalanceAway(t *testing.T) {
 				nil,
 				firstRangeID,
 				existingReplicas,
+				nil, /* existingNonVoters */
 				rangeUsageInfo,
 				storeFilterThrottled,
 			)
@@ -5702,6 +5816,7 @@ func TestAllocatorFullDisks(t *testing.T) {
 						nil,
 						firstRangeID,
 						[]roachpb.ReplicaDescriptor{{NodeID: ts.Node.NodeID, StoreID: ts.StoreID}},
+						nil, /* existingNonVoters */
 						rangeUsageInfo,
 						storeFilterThrottled,
 					)
@@ -5831,6 +5946,7 @@ func Example_rebalancing() {
 				nil,
 				firstRangeID,
 				[]roachpb.ReplicaDescriptor{{NodeID: ts.Node.NodeID, StoreID: ts.StoreID}},
+				nil, /* existingNonVoters */
 				rangeUsageInfo,
 				storeFilterThrottled,
 			)
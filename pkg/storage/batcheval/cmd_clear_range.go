@@ -143,6 +143,14 @@ func computeStatsDelta(
 		if err != nil {
 			return enginepb.MVCCStats{}, err
 		}
+		// Account for the keys deleted by MVCC range tombstones, which
+		// ComputeStats considers live.
+		adjMS, err := engine.ComputeRangeTombstoneStatsAdjustment(
+			readWriter, desc.RangeID, from, to, delta.LastUpdateNanos)
+		if err != nil {
+			return enginepb.MVCCStats{}, err
+		}
+		computed.Add(adjMS)
 		// If we took the fast path but race is enabled, assert stats were correctly computed.
 		if fast {
 			delta.ContainsEstimates = computed.ContainsEstimates
@@ -15,10 +15,12 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/storage/batcheval/result"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
 	"github.com/cockroachdb/cockroach/pkg/storage/spanset"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
 )
 
 func init() {
@@ -26,7 +28,7 @@ func init() {
 }
 
 func declareKeysDeleteRange(
-	_ *roachpb.RangeDescriptor, header roachpb.Header, req roachpb.Request, spans *spanset.SpanSet,
+	desc *roachpb.RangeDescriptor, header roachpb.Header, req roachpb.Request, spans *spanset.SpanSet,
 ) {
 	args := req.(*roachpb.DeleteRangeRequest)
 	access := spanset.SpanReadWrite
@@ -36,6 +38,14 @@ func declareKeysDeleteRange(
 	} else {
 		spans.AddMVCC(access, req.Header().Span(), header.Timestamp)
 	}
+	if args.UseRangeTombstone {
+		spans.AddNonMVCC(access, roachpb.Span{
+			Key: keys.MVCCRangeTombstoneKey(roachpb.RKey(args.Key)),
+		})
+		spans.AddNonMVCC(access, roachpb.Span{
+			Key: keys.RangeMVCCRangeTombstonesKey(desc.RangeID),
+		})
+	}
 }
 
 // DeleteRange deletes the range of key/value pairs specified by
@@ -47,6 +57,24 @@ func DeleteRange(
 	h := cArgs.Header
 	reply := resp.(*roachpb.DeleteRangeResponse)
 
+	if args.UseRangeTombstone {
+		if h.Txn != nil {
+			return result.Result{}, errors.Errorf("cannot write range tombstone within a transaction")
+		}
+		if args.Inline || args.ReturnKeys {
+			return result.Result{}, errors.Errorf("cannot write range tombstone with inline or return keys set")
+		}
+		// Nodes running an older version would ignore the range tombstone and
+		// serve the deleted data again if they acquired the lease.
+		if !cluster.Version.IsActive(ctx, cArgs.EvalCtx.ClusterSettings(), cluster.VersionMVCCRangeTombstones) {
+			return result.Result{}, errors.Errorf("cannot write range tombstone before cluster version %s is active",
+				cluster.VersionByKey(cluster.VersionMVCCRangeTombstones))
+		}
+		return result.Result{}, engine.MVCCDeleteRangeUsingTombstone(
+			ctx, readWriter, cArgs.Stats, cArgs.EvalCtx.GetRangeID(), args.Key, args.EndKey, h.Timestamp,
+		)
+	}
+
 	var timestamp hlc.Timestamp
 	if !args.Inline {
 		timestamp = h.Timestamp
@@ -64,3 +92,14 @@ func DeleteRange(
 	}
 	return result.Result{}, err
 }
+
+// noRangeTombstones returns whether reads of the given key, or of the span
+// starting at it, can skip looking up MVCC range tombstones because the range
+// doesn't contain any. Range tombstones never delete local keys.
+func noRangeTombstones(reader engine.Reader, cArgs CommandArgs, key roachpb.Key) (bool, error) {
+	if keys.IsLocal(key) {
+		return true, nil
+	}
+	ok, err := engine.MVCCHasRangeTombstones(reader, cArgs.EvalCtx.GetRangeID())
+	return !ok, err
+}
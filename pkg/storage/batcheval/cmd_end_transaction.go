@@ -144,12 +144,16 @@ func declareKeysEndTxn(
 				})
 			}
 			if mt := et.InternalCommitTrigger.MergeTrigger; mt != nil {
-				// Merges copy over the RHS abort span to the LHS, and compute
-				// replicated range ID stats over the RHS in the merge trigger.
+				// Merges copy over the RHS abort span and MVCC range tombstone
+				// marker to the LHS, and compute replicated range ID stats over
+				// the RHS in the merge trigger.
 				spans.AddNonMVCC(spanset.SpanReadWrite, roachpb.Span{
 					Key:    abortspan.MinKey(mt.LeftDesc.RangeID),
 					EndKey: abortspan.MaxKey(mt.LeftDesc.RangeID).PrefixEnd(),
 				})
+				spans.AddNonMVCC(spanset.SpanReadWrite, roachpb.Span{
+					Key: keys.RangeMVCCRangeTombstonesKey(mt.LeftDesc.RangeID),
+				})
 				spans.AddNonMVCC(spanset.SpanReadOnly, roachpb.Span{
 					Key:    keys.MakeRangeIDReplicatedPrefix(mt.RightDesc.RangeID),
 					EndKey: keys.MakeRangeIDReplicatedPrefix(mt.RightDesc.RangeID).PrefixEnd(),
@@ -238,7 +242,9 @@ func EndTxn(
 				}
 				// Use alwaysReturn==true because the transaction is definitely
 				// aborted, no matter what happens to this command.
-				return result.FromEndTxn(reply.Txn, true /* alwaysReturn */, args.Poison), nil
+				res := result.FromEndTxn(reply.Txn, true /* alwaysReturn */, args.Poison)
+				res.Local.ResolvedLocks = localLockUpdates(desc, args, reply.Txn)
+				return res, nil
 			}
 			// If the transaction was previously aborted by a concurrent writer's
 			// push, any intents written are still open. It's only now that we know
@@ -377,6 +383,7 @@ func EndTxn(
 	// if the commit actually happens; otherwise, we risk losing writes.
 	intentsResult := result.FromEndTxn(reply.Txn, false /* alwaysReturn */, args.Poison)
 	intentsResult.Local.UpdatedTxns = []*roachpb.Transaction{reply.Txn}
+	intentsResult.Local.ResolvedLocks = localLockUpdates(desc, args, reply.Txn)
 	if err := pd.MergeAndDestroy(intentsResult); err != nil {
 		return result.Result{}, err
 	}
@@ -535,6 +542,31 @@ func resolveLocalIntents(
 	return externalIntents, nil
 }
 
+// localLockUpdates returns the lock updates that release the locks held by the
+// finalized transaction on the local range. Unlike the transaction's intents,
+// which are resolved synchronously only up to a maximum allowance, all of its
+// unreplicated locks on the local range are released when it is finalized.
+func localLockUpdates(
+	desc *roachpb.RangeDescriptor, args *roachpb.EndTxnRequest, txn *roachpb.Transaction,
+) []roachpb.LockUpdate {
+	if mergeTrigger := args.InternalCommitTrigger.GetMergeTrigger(); mergeTrigger != nil {
+		desc = &mergeTrigger.LeftDesc
+	}
+	var updates []roachpb.LockUpdate
+	for _, span := range args.IntentSpans {
+		if len(span.EndKey) == 0 {
+			if storagebase.ContainsKey(desc, span.Key) {
+				updates = append(updates, roachpb.MakeLockUpdateWithDur(txn, span, lock.Unreplicated))
+			}
+			continue
+		}
+		if inSpan, _ := storagebase.IntersectSpan(span, desc); inSpan != nil {
+			updates = append(updates, roachpb.MakeLockUpdateWithDur(txn, *inSpan, lock.Unreplicated))
+		}
+	}
+	return updates
+}
+
 // updateStagingTxn persists the STAGING transaction record with updated status
 // (and possibly timestamp). It persists the record with the EndTxn request's
 // declared in-flight writes along with all of the transaction's (local and
@@ -841,6 +873,23 @@ func splitTrigger(
 			split.RightDesc.StartKey, split.RightDesc.EndKey, desc)
 	}
 
+	// Copy the MVCC range tombstones that extend across the split key to the
+	// RHS, since each range only consults its own range tombstones.
+	if hasTombstones, err := engine.MVCCHasRangeTombstones(batch, desc.RangeID); err != nil {
+		return enginepb.MVCCStats{}, result.Result{}, errors.Wrap(err, "unable to copy range tombstones")
+	} else if hasTombstones {
+		if err := engine.MVCCSplitRangeTombstones(
+			ctx, batch, &bothDeltaMS, split.LeftDesc.StartKey.AsRawKey(), split.RightDesc.StartKey.AsRawKey(),
+		); err != nil {
+			return enginepb.MVCCStats{}, result.Result{}, errors.Wrap(err, "unable to copy range tombstones")
+		}
+		if err := engine.MVCCMarkRangeTombstones(
+			ctx, batch, &bothDeltaMS, split.RightDesc.RangeID,
+		); err != nil {
+			return enginepb.MVCCStats{}, result.Result{}, errors.Wrap(err, "unable to copy range tombstones")
+		}
+	}
+
 	// Compute the absolute stats for the (post-split) LHS. No more
 	// modifications to it are allowed after this line.
 
@@ -1067,10 +1116,19 @@ func mergeTrigger(
 		return result.Result{}, err
 	}
 
+	// The merged range holds the MVCC range tombstones of the RHS.
+	if hasTombstones, err := engine.MVCCHasRangeTombstones(batch, merge.RightDesc.RangeID); err != nil {
+		return result.Result{}, err
+	} else if hasTombstones {
+		if err := engine.MVCCMarkRangeTombstones(ctx, batch, ms, merge.LeftDesc.RangeID); err != nil {
+			return result.Result{}, err
+		}
+	}
+
 	// The stats for the merged range are the sum of the LHS and RHS stats, less
 	// the RHS's replicated range ID stats. The only replicated range ID keys we
-	// copy from the RHS are the keys in the abort span, and we've already
-	// accounted for those stats above.
+	// copy from the RHS are the keys in the abort span and the range tombstone
+	// marker, and we've already accounted for those stats above.
 	ms.Add(merge.RightMVCCStats)
 	{
 		ridPrefix := keys.MakeRangeIDReplicatedPrefix(merge.RightDesc.RangeID)
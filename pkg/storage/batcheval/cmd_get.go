@@ -31,9 +31,14 @@ func Get(
 	h := cArgs.Header
 	reply := resp.(*roachpb.GetResponse)
 
+	noTombstones, err := noRangeTombstones(reader, cArgs, args.Key)
+	if err != nil {
+		return result.Result{}, err
+	}
 	val, intent, err := engine.MVCCGet(ctx, reader, args.Key, h.Timestamp, engine.MVCCGetOptions{
-		Inconsistent: h.ReadConsistency != roachpb.CONSISTENT,
-		Txn:          h.Txn,
+		Inconsistent:      h.ReadConsistency != roachpb.CONSISTENT,
+		Txn:               h.Txn,
+		NoRangeTombstones: noTombstones,
 	})
 	if err != nil {
 		return result.Result{}, err
@@ -17,7 +17,10 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/stretchr/testify/require"
@@ -155,3 +158,41 @@ func TestLeaseCommandLearnerReplica(t *testing.T) {
 		`replica (n2,s2):2LEARNER of type LEARNER cannot hold lease`
 	require.EqualError(t, err, expForLearner)
 }
+
+// TestLeaseTransferCarriesLoadHistory verifies that a lease transfer hands the
+// load history of the outgoing leaseholder to the incoming one.
+func TestLeaseTransferCarriesLoadHistory(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	eng := engine.NewDefaultInMem()
+	defer eng.Close()
+
+	replicas := []roachpb.ReplicaDescriptor{
+		{NodeID: 1, StoreID: 1, Type: roachpb.ReplicaTypeVoterFull(), ReplicaID: 1},
+		{NodeID: 2, StoreID: 2, Type: roachpb.ReplicaTypeVoterFull(), ReplicaID: 2},
+	}
+	desc := roachpb.RangeDescriptor{RangeID: 1}
+	desc.SetReplicas(roachpb.MakeReplicaDescriptors(replicas))
+	cArgs := CommandArgs{
+		EvalCtx: &mockEvalCtx{
+			storeID: 1,
+			desc:    &desc,
+			qps:     123,
+			lease:   roachpb.Lease{Replica: replicas[0], Sequence: 1},
+		},
+		Args: &roachpb.TransferLeaseRequest{
+			Lease: roachpb.Lease{
+				Replica:    replicas[1],
+				Start:      hlc.Timestamp{WallTime: 1},
+				Expiration: &hlc.Timestamp{WallTime: 2},
+			},
+		},
+		Stats: &enginepb.MVCCStats{},
+	}
+
+	res, err := TransferLease(ctx, eng, cArgs, nil)
+	require.NoError(t, err)
+	require.Equal(t, replicas[1], res.Replicated.State.Lease.Replica)
+	require.Equal(t, 123.0, res.Replicated.PriorMaxSplitQPS)
+}
@@ -66,6 +66,15 @@ func TransferLease(
 
 	prevLease, _ := cArgs.EvalCtx.GetLease()
 	log.VEventf(ctx, 2, "lease transfer: prev lease: %+v, new lease: %+v", prevLease, args.Lease)
-	return evalNewLease(ctx, cArgs.EvalCtx, readWriter, cArgs.Stats,
+	res, err := evalNewLease(ctx, cArgs.EvalCtx, readWriter, cArgs.Stats,
 		args.Lease, prevLease, false /* isExtension */, true /* isTransfer */)
+	if err != nil {
+		return res, err
+	}
+	// Hand the load history of the range over to the new leaseholder so that
+	// the merge queue on its store doesn't mistake the range for an idle one.
+	if maxQPS, ok := cArgs.EvalCtx.GetMaxSplitQPS(); ok {
+		res.Replicated.PriorMaxSplitQPS = maxQPS
+	}
+	return res, nil
 }
@@ -0,0 +1,112 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package batcheval
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/batcheval/result"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+)
+
+func init() {
+	RegisterReadOnlyCommand(roachpb.QueryResolvedTimestamp, DefaultDeclareKeys, QueryResolvedTimestamp)
+}
+
+// QueryResolvedTimestamp requests the resolved timestamp of the key span it is
+// issued over. The resolved timestamp is the minimum of the replica's closed
+// timestamp and the timestamp immediately preceding that of the earliest
+// intent in the key span. Reads at or below the resolved timestamp will not
+// block on any intents and will not observe any future writes.
+func QueryResolvedTimestamp(
+	ctx context.Context, reader engine.Reader, cArgs CommandArgs, resp roachpb.Response,
+) (result.Result, error) {
+	args := cArgs.Args.(*roachpb.QueryResolvedTimestampRequest)
+	reply := resp.(*roachpb.QueryResolvedTimestampResponse)
+
+	// The closed timestamp bounds the resolved timestamp from above. Because
+	// the replica may be a follower, it is the only timestamp below which the
+	// replica is guaranteed to have applied all writes.
+	closedTS := cArgs.EvalCtx.GetClosedTimestamp(ctx)
+
+	// Any intent in the span below the closed timestamp may still be committed
+	// at its current timestamp, so the resolved timestamp must be below it.
+	// No intents can be written below the closed timestamp any more, so there
+	// is no need to look for intents if the range doesn't contain any.
+	reply.ResolvedTS = closedTS
+	if ms := cArgs.EvalCtx.GetMVCCStats(); ms.IntentCount == 0 && ms.ContainsEstimates == 0 {
+		return result.Result{}, nil
+	}
+	minIntentTS, ok, err := computeMinIntentTimestamp(
+		reader, args.Span(), queryResolvedTimestampMaxKeysScanned,
+	)
+	if err != nil {
+		return result.Result{}, err
+	}
+	if !ok {
+		// The span contains too many keys to look for intents in all of them.
+		// An empty resolved timestamp is too old for any client to use.
+		reply.ResolvedTS = hlc.Timestamp{}
+		return result.Result{}, nil
+	}
+	if !minIntentTS.IsEmpty() {
+		reply.ResolvedTS.Backward(minIntentTS.FloorPrev())
+	}
+	return result.Result{}, nil
+}
+
+// queryResolvedTimestampMaxKeysScanned bounds the number of keys that a
+// QueryResolvedTimestamp request scans while looking for intents.
+const queryResolvedTimestampMaxKeysScanned = 10000
+
+// computeMinIntentTimestamp scans the specified key span and returns the
+// minimum timestamp of any intent in it, or an empty timestamp if there are
+// none. At most maxKeys keys are scanned; if the span contains more keys,
+// false is returned.
+func computeMinIntentTimestamp(
+	reader engine.Reader, span roachpb.Span, maxKeys int,
+) (hlc.Timestamp, bool, error) {
+	iter := reader.NewIterator(engine.IterOptions{UpperBound: span.EndKey})
+	defer iter.Close()
+
+	var meta enginepb.MVCCMetadata
+	var minTS hlc.Timestamp
+	var keys int
+	for iter.SeekGE(engine.MakeMVCCMetadataKey(span.Key)); ; iter.NextKey() {
+		if ok, err := iter.Valid(); err != nil {
+			return hlc.Timestamp{}, false, err
+		} else if !ok {
+			break
+		}
+		if keys++; keys > maxKeys {
+			return hlc.Timestamp{}, false, nil
+		}
+		if iter.UnsafeKey().IsValue() {
+			// Not a metadata key, so the key has no intent.
+			continue
+		}
+		if err := protoutil.Unmarshal(iter.UnsafeValue(), &meta); err != nil {
+			return hlc.Timestamp{}, false, err
+		}
+		if meta.Txn == nil {
+			// An inline value, not an intent.
+			continue
+		}
+		if minTS.IsEmpty() || meta.Txn.WriteTimestamp.Less(minTS) {
+			minTS = meta.Txn.WriteTimestamp
+		}
+	}
+	return minTS, true, nil
+}
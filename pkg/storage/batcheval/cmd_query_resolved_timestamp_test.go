@@ -0,0 +1,159 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package batcheval
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryResolvedTimestamp(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	db := engine.NewDefaultInMem()
+	defer db.Close()
+
+	makeTS := func(ts int64) hlc.Timestamp {
+		return hlc.Timestamp{WallTime: ts}
+	}
+	var ms enginepb.MVCCStats
+	writeValue := func(k string, ts int64) {
+		v := roachpb.MakeValueFromString("val")
+		require.NoError(t, engine.MVCCPut(ctx, db, &ms, roachpb.Key(k), makeTS(ts), v, nil))
+	}
+	writeIntent := func(k string, ts int64) {
+		txn := roachpb.Transaction{
+			TxnMeta: enginepb.TxnMeta{
+				Key:            roachpb.Key(k),
+				ID:             uuid.MakeV4(),
+				WriteTimestamp: makeTS(ts),
+			},
+			ReadTimestamp: makeTS(ts),
+		}
+		v := roachpb.MakeValueFromString("val")
+		require.NoError(t, engine.MVCCPut(ctx, db, &ms, roachpb.Key(k), makeTS(ts), v, &txn))
+	}
+
+	writeValue("a", 1)
+	writeIntent("b", 20)
+	writeValue("c", 5)
+	writeIntent("d", 15)
+	writeValue("e", 25)
+
+	for _, test := range []struct {
+		name     string
+		span     [2]string
+		closedTS hlc.Timestamp
+		stats    *enginepb.MVCCStats
+		expTS    hlc.Timestamp
+	}{
+		{
+			name:     "no intents",
+			span:     [2]string{"a", "b"},
+			closedTS: makeTS(30),
+			expTS:    makeTS(30),
+		},
+		{
+			name:     "intent above closed timestamp",
+			span:     [2]string{"a", "c"},
+			closedTS: makeTS(10),
+			expTS:    makeTS(10),
+		},
+		{
+			name:     "intent below closed timestamp",
+			span:     [2]string{"a", "c"},
+			closedTS: makeTS(30),
+			expTS:    makeTS(20).FloorPrev(),
+		},
+		{
+			name:     "minimum of multiple intents",
+			span:     [2]string{"a", "f"},
+			closedTS: makeTS(30),
+			expTS:    makeTS(15).FloorPrev(),
+		},
+		{
+			name:     "empty closed timestamp",
+			span:     [2]string{"a", "f"},
+			closedTS: hlc.Timestamp{},
+			expTS:    hlc.Timestamp{},
+		},
+		{
+			// The intents are not looked for if the stats say there are none.
+			name:     "no intents in range",
+			span:     [2]string{"a", "f"},
+			closedTS: makeTS(30),
+			stats:    &enginepb.MVCCStats{},
+			expTS:    makeTS(30),
+		},
+		{
+			// The stats may be inaccurate if they contain estimates.
+			name:     "estimated stats",
+			span:     [2]string{"a", "f"},
+			closedTS: makeTS(30),
+			stats:    &enginepb.MVCCStats{ContainsEstimates: 1},
+			expTS:    makeTS(15).FloorPrev(),
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			stats := ms
+			if test.stats != nil {
+				stats = *test.stats
+			}
+			var resp roachpb.QueryResolvedTimestampResponse
+			_, err := QueryResolvedTimestamp(ctx, db, CommandArgs{
+				EvalCtx: &mockEvalCtx{closedTimestamp: test.closedTS, stats: stats},
+				Args: &roachpb.QueryResolvedTimestampRequest{
+					RequestHeader: roachpb.RequestHeader{
+						Key:    roachpb.Key(test.span[0]),
+						EndKey: roachpb.Key(test.span[1]),
+					},
+				},
+			}, &resp)
+			require.NoError(t, err)
+			require.Equal(t, test.expTS, resp.ResolvedTS)
+		})
+	}
+}
+
+func TestComputeMinIntentTimestampMaxKeys(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	db := engine.NewDefaultInMem()
+	defer db.Close()
+
+	ts := hlc.Timestamp{WallTime: 10}
+	txn := roachpb.MakeTransaction("test", roachpb.Key("a"), roachpb.NormalUserPriority, ts, 0)
+	v := roachpb.MakeValueFromString("val")
+	for _, k := range []string{"a", "b", "c"} {
+		require.NoError(t, engine.MVCCPut(ctx, db, nil, roachpb.Key(k), ts, v, &txn))
+	}
+	span := roachpb.Span{Key: roachpb.Key("a"), EndKey: roachpb.Key("d")}
+
+	minTS, ok, err := computeMinIntentTimestamp(db, span, 3 /* maxKeys */)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, ts, minTS)
+
+	// The scan gives up once it has seen more than maxKeys keys.
+	_, ok, err = computeMinIntentTimestamp(db, span, 2 /* maxKeys */)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
@@ -29,5 +29,6 @@ func RangeStats(
 	reply := resp.(*roachpb.RangeStatsResponse)
 	reply.MVCCStats = cArgs.EvalCtx.GetMVCCStats()
 	reply.QueriesPerSecond = cArgs.EvalCtx.GetSplitQPS()
+	reply.MaxQueriesPerSecond, reply.MaxQueriesPerSecondSet = cArgs.EvalCtx.GetMaxSplitQPS()
 	return result.Result{}, nil
 }
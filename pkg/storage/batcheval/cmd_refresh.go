@@ -55,9 +55,14 @@ func Refresh(
 	// specifying consistent=false. Note that we include tombstones,
 	// which must be considered as updates on refresh.
 	log.VEventf(ctx, 2, "refresh %s @[%s-%s]", args.Span(), refreshFrom, refreshTo)
+	noTombstones, err := noRangeTombstones(reader, cArgs, args.Key)
+	if err != nil {
+		return result.Result{}, err
+	}
 	val, intent, err := engine.MVCCGet(ctx, reader, args.Key, refreshTo, engine.MVCCGetOptions{
-		Inconsistent: true,
-		Tombstones:   true,
+		Inconsistent:      true,
+		Tombstones:        true,
+		NoRangeTombstones: noTombstones,
 	})
 
 	if err != nil {
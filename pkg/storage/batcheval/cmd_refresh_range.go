@@ -58,11 +58,16 @@ func RefreshRange(
 	// committed version. Note also that we include tombstones, which must be
 	// considered as updates on refresh.
 	log.VEventf(ctx, 2, "refresh %s @[%s-%s]", args.Span(), refreshFrom, refreshTo)
+	noTombstones, err := noRangeTombstones(reader, cArgs, args.Key)
+	if err != nil {
+		return result.Result{}, err
+	}
 	intents, err := engine.MVCCIterate(
 		ctx, reader, args.Key, args.EndKey, refreshTo,
 		engine.MVCCScanOptions{
-			Inconsistent: true,
-			Tombstones:   true,
+			Inconsistent:      true,
+			Tombstones:        true,
+			NoRangeTombstones: noTombstones,
 		},
 		func(kv roachpb.KeyValue) (bool, error) {
 			if ts := kv.Value.Timestamp; refreshFrom.LessEq(ts) {
@@ -89,6 +89,7 @@ func ResolveIntent(
 
 	var res result.Result
 	res.Local.Metrics = resolveToMetricType(args.Status, args.Poison)
+	res.Local.ResolvedLocks = []roachpb.LockUpdate{update}
 
 	if WriteAbortSpanOnResolve(args.Status, args.Poison, ok) {
 		if err := UpdateAbortSpan(ctx, cArgs.EvalCtx, readWriter, ms, args.IntentTxn, args.Poison); err != nil {
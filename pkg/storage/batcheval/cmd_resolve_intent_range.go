@@ -62,6 +62,7 @@ func ResolveIntentRange(
 
 	var res result.Result
 	res.Local.Metrics = resolveToMetricType(args.Status, args.Poison)
+	res.Local.ResolvedLocks = []roachpb.LockUpdate{update}
 
 	if WriteAbortSpanOnResolve(args.Status, args.Poison, numKeys > 0) {
 		if err := UpdateAbortSpan(ctx, cArgs.EvalCtx, readWriter, ms, args.IntentTxn, args.Poison); err != nil {
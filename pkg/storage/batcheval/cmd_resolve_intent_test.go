@@ -42,6 +42,7 @@ type mockEvalCtx struct {
 	qps              float64
 	abortSpan        *abortspan.AbortSpan
 	gcThreshold      hlc.Timestamp
+	closedTimestamp  hlc.Timestamp
 	term, firstIndex uint64
 	canCreateTxnFn   func() (bool, hlc.Timestamp, roachpb.TransactionAbortedReason)
 	lease            roachpb.Lease
@@ -110,6 +111,15 @@ func (m *mockEvalCtx) GetMVCCStats() enginepb.MVCCStats {
 func (m *mockEvalCtx) GetSplitQPS() float64 {
 	return m.qps
 }
+func (m *mockEvalCtx) GetMaxSplitQPS() (float64, bool) {
+	return m.qps, true
+}
+func (m *mockEvalCtx) GetClosedTimestamp(context.Context) hlc.Timestamp {
+	return m.closedTimestamp
+}
+func (m *mockEvalCtx) GetLockTableView(*enginepb.TxnMeta) engine.LockTableView {
+	panic("unimplemented")
+}
 func (m *mockEvalCtx) CanCreateTxnRecord(
 	uuid.UUID, []byte, hlc.Timestamp,
 ) (bool, hlc.Timestamp, roachpb.TransactionAbortedReason) {
@@ -16,6 +16,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/batcheval/result"
+	"github.com/cockroachdb/cockroach/pkg/storage/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
 )
 
@@ -35,13 +36,19 @@ func ReverseScan(
 	reply := resp.(*roachpb.ReverseScanResponse)
 
 	var res engine.MVCCScanResult
-	var err error
+	noTombstones, err := noRangeTombstones(reader, cArgs, args.Key)
+	if err != nil {
+		return result.Result{}, err
+	}
 
 	opts := engine.MVCCScanOptions{
-		Inconsistent: h.ReadConsistency != roachpb.CONSISTENT,
-		Txn:          h.Txn,
-		TargetBytes:  h.TargetBytes,
-		Reverse:      true,
+		Inconsistent:      h.ReadConsistency != roachpb.CONSISTENT,
+		Txn:               h.Txn,
+		TargetBytes:       h.TargetBytes,
+		SkipLocked:        h.WaitPolicy == lock.WaitPolicy_SkipLocked,
+		LockTable:         lockTableViewForScan(cArgs),
+		Reverse:           true,
+		NoRangeTombstones: noTombstones,
 	}
 
 	switch args.ScanFormat {
@@ -71,8 +78,16 @@ func ReverseScan(
 		reply.ResumeReason = roachpb.RESUME_KEY_LIMIT
 	}
 
+	pd := result.FromEncounteredIntents(res.Intents)
+	if args.KeyLocking != lock.None && h.Txn != nil {
+		pd.Local.AcquiredLocks, err = acquireUnreplicatedLocksOnKeys(h.Txn, args.ScanFormat, &res)
+		if err != nil {
+			return result.Result{}, err
+		}
+	}
+
 	if h.ReadConsistency == roachpb.READ_UNCOMMITTED {
 		reply.IntentRows, err = CollectIntentRows(ctx, reader, cArgs, res.Intents)
 	}
-	return result.FromEncounteredIntents(res.Intents), err
+	return pd, err
 }
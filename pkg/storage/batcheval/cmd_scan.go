@@ -16,7 +16,9 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/batcheval/result"
+	"github.com/cockroachdb/cockroach/pkg/storage/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
 )
 
 func init() {
@@ -35,13 +37,19 @@ func Scan(
 	reply := resp.(*roachpb.ScanResponse)
 
 	var res engine.MVCCScanResult
-	var err error
+	noTombstones, err := noRangeTombstones(reader, cArgs, args.Key)
+	if err != nil {
+		return result.Result{}, err
+	}
 
 	opts := engine.MVCCScanOptions{
-		Inconsistent: h.ReadConsistency != roachpb.CONSISTENT,
-		Txn:          h.Txn,
-		TargetBytes:  h.TargetBytes,
-		Reverse:      false,
+		Inconsistent:      h.ReadConsistency != roachpb.CONSISTENT,
+		Txn:               h.Txn,
+		TargetBytes:       h.TargetBytes,
+		SkipLocked:        h.WaitPolicy == lock.WaitPolicy_SkipLocked,
+		LockTable:         lockTableViewForScan(cArgs),
+		Reverse:           false,
+		NoRangeTombstones: noTombstones,
 	}
 
 	switch args.ScanFormat {
@@ -71,8 +79,67 @@ func Scan(
 		reply.ResumeReason = roachpb.RESUME_KEY_LIMIT
 	}
 
+	pd := result.FromEncounteredIntents(res.Intents)
+	if args.KeyLocking != lock.None && h.Txn != nil {
+		pd.Local.AcquiredLocks, err = acquireUnreplicatedLocksOnKeys(h.Txn, args.ScanFormat, &res)
+		if err != nil {
+			return result.Result{}, err
+		}
+	}
+
 	if h.ReadConsistency == roachpb.READ_UNCOMMITTED {
 		reply.IntentRows, err = CollectIntentRows(ctx, reader, cArgs, res.Intents)
 	}
-	return result.FromEncounteredIntents(res.Intents), err
+	return pd, err
+}
+
+// lockTableViewForScan returns the view of the unreplicated lock table that a
+// scan consults to skip locked keys, if it skips them.
+func lockTableViewForScan(cArgs CommandArgs) engine.LockTableView {
+	h := cArgs.Header
+	if h.WaitPolicy != lock.WaitPolicy_SkipLocked {
+		return nil
+	}
+	var txn *enginepb.TxnMeta
+	if h.Txn != nil {
+		txn = &h.Txn.TxnMeta
+	}
+	return cArgs.EvalCtx.GetLockTableView(txn)
+}
+
+// acquireUnreplicatedLocksOnKeys returns the unreplicated locks that a
+// locking scan acquires on the keys that it returns.
+func acquireUnreplicatedLocksOnKeys(
+	txn *roachpb.Transaction, scanFmt roachpb.ScanFormat, scanRes *engine.MVCCScanResult,
+) ([]roachpb.LockUpdate, error) {
+	if scanRes.NumKeys == 0 {
+		return nil, nil
+	}
+	acquiredLocks := make([]roachpb.LockUpdate, 0, scanRes.NumKeys)
+	acquireLock := func(key roachpb.Key) {
+		span := roachpb.Span{Key: key}
+		acquiredLocks = append(acquiredLocks, roachpb.MakeLockUpdateWithDur(txn, span, lock.Unreplicated))
+	}
+	switch scanFmt {
+	case roachpb.BATCH_RESPONSE:
+		for _, data := range scanRes.KVData {
+			for len(data) > 0 {
+				key, _, rest, err := enginepb.ScanDecodeKeyValueNoTS(data)
+				if err != nil {
+					return nil, err
+				}
+				// The key aliases the response, which the lock table must not
+				// retain.
+				acquireLock(append(roachpb.Key(nil), key...))
+				data = rest
+			}
+		}
+	case roachpb.KEY_VALUES:
+		for _, kv := range scanRes.KVs {
+			acquireLock(kv.Key)
+		}
+	default:
+		panic(fmt.Sprintf("Unknown scanFormat %d", scanFmt))
+	}
+	return acquiredLocks, nil
 }
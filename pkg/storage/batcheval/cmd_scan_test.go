@@ -77,6 +77,7 @@ func testScanReverseScanInner(
 	req.SetHeader(roachpb.RequestHeader{Key: k1, EndKey: roachpb.KeyMax})
 
 	cArgs := CommandArgs{
+		EvalCtx: &mockEvalCtx{desc: &roachpb.RangeDescriptor{RangeID: 1}},
 		MaxKeys: 100000,
 		Args:    req,
 		Header: roachpb.Header{
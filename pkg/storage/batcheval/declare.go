@@ -24,9 +24,12 @@ func DefaultDeclareKeys(
 	_ *roachpb.RangeDescriptor, header roachpb.Header, req roachpb.Request, spans *spanset.SpanSet,
 ) {
 	var access spanset.SpanAccess
-	if roachpb.IsReadOnly(req) {
+	if roachpb.IsReadOnly(req) && !roachpb.IsLocking(req) {
 		access = spanset.SpanReadOnly
 	} else {
+		// Locking reads declare write latches so that they synchronize with
+		// the writes and the locking reads of other transactions on the keys
+		// that they lock.
 		access = spanset.SpanReadWrite
 	}
 
@@ -89,6 +89,24 @@ type EvalContext interface {
 	// setting is disabled.
 	GetSplitQPS() float64
 
+	// GetMaxSplitQPS returns the maximum queries/s request rate for this range
+	// over a configured retention period. If the range has not been recording
+	// load for long enough, false is returned.
+	//
+	// NOTE: This should not be used when the load based splitting cluster
+	// setting is disabled.
+	GetMaxSplitQPS() (float64, bool)
+
+	// GetClosedTimestamp returns the closed timestamp of the range, i.e. the
+	// timestamp at or below which the replica is guaranteed to have seen all
+	// writes. It can be consulted on any replica, not only the leaseholder.
+	GetClosedTimestamp(ctx context.Context) hlc.Timestamp
+
+	// GetLockTableView returns a view of the unreplicated locks held on the
+	// range for a scan performed by the provided transaction, which is nil for
+	// non-transactional scans.
+	GetLockTableView(txn *enginepb.TxnMeta) engine.LockTableView
+
 	GetGCThreshold() hlc.Timestamp
 	GetLastReplicaGCTimestamp(context.Context) (hlc.Timestamp, error)
 	GetLease() (roachpb.Lease, roachpb.Lease)
@@ -32,6 +32,13 @@ type LocalResult struct {
 	// with. They should be handed off to asynchronous intent processing on
 	// the proposer, so that an attempt to resolve them is made.
 	EncounteredIntents []roachpb.Intent
+	// AcquiredLocks stores the unreplicated locks acquired by locking reads.
+	// They should be added to the unreplicated lock table on the proposer.
+	AcquiredLocks []roachpb.LockUpdate
+	// ResolvedLocks stores the spans in which the locks of a transaction were
+	// updated or released by intent resolution. The unreplicated locks in them
+	// should be updated accordingly on the proposer.
+	ResolvedLocks []roachpb.LockUpdate
 	// UpdatedTxns stores transaction records that have been updated by
 	// calls to EndTxn, PushTxn, and RecoverTxn.
 	UpdatedTxns []*roachpb.Transaction
@@ -67,6 +74,8 @@ func (lResult *LocalResult) IsZero() bool {
 	// NB: keep in order.
 	return lResult.Reply == nil &&
 		lResult.EncounteredIntents == nil &&
+		lResult.AcquiredLocks == nil &&
+		lResult.ResolvedLocks == nil &&
 		lResult.UpdatedTxns == nil &&
 		lResult.EndTxns == nil &&
 		!lResult.GossipFirstRange &&
@@ -81,10 +90,11 @@ func (lResult *LocalResult) String() string {
 		return "LocalResult: nil"
 	}
 	return fmt.Sprintf("LocalResult (reply: %v, #encountered intents: %d, "+
-		"#updated txns: %d #end txns: %d, "+
+		"#acquired locks: %d, #resolved locks: %d, #updated txns: %d #end txns: %d, "+
 		"GossipFirstRange:%t MaybeGossipSystemConfig:%t MaybeAddToSplitQueue:%t "+
 		"MaybeGossipNodeLiveness:%s MaybeWatchForMerge:%t",
 		lResult.Reply, len(lResult.EncounteredIntents),
+		len(lResult.AcquiredLocks), len(lResult.ResolvedLocks),
 		len(lResult.UpdatedTxns), len(lResult.EndTxns),
 		lResult.GossipFirstRange, lResult.MaybeGossipSystemConfig, lResult.MaybeAddToSplitQueue,
 		lResult.MaybeGossipNodeLiveness, lResult.MaybeWatchForMerge)
@@ -101,6 +111,17 @@ func (lResult *LocalResult) DetachEncounteredIntents() []roachpb.Intent {
 	return r
 }
 
+// DetachAcquiredLocks returns (and removes) the unreplicated locks acquired
+// by locking reads from the LocalEvalResult.
+func (lResult *LocalResult) DetachAcquiredLocks() []roachpb.LockUpdate {
+	if lResult == nil {
+		return nil
+	}
+	r := lResult.AcquiredLocks
+	lResult.AcquiredLocks = nil
+	return r
+}
+
 // DetachEndTxns returns (and removes) the EndTxnIntent objects from
 // the local result. If alwaysOnly is true, the slice is filtered to
 // include only those which have specified returnAlways=true, meaning
@@ -277,6 +298,13 @@ func (p *Result) MergeAndDestroy(q Result) error {
 	}
 	q.Replicated.PrevLeaseProposal = nil
 
+	if p.Replicated.PriorMaxSplitQPS == 0 {
+		p.Replicated.PriorMaxSplitQPS = q.Replicated.PriorMaxSplitQPS
+	} else if q.Replicated.PriorMaxSplitQPS != 0 {
+		return errors.New("conflicting prior max split QPS")
+	}
+	q.Replicated.PriorMaxSplitQPS = 0
+
 	if p.Local.EncounteredIntents == nil {
 		p.Local.EncounteredIntents = q.Local.EncounteredIntents
 	} else {
@@ -284,6 +312,20 @@ func (p *Result) MergeAndDestroy(q Result) error {
 	}
 	q.Local.EncounteredIntents = nil
 
+	if p.Local.AcquiredLocks == nil {
+		p.Local.AcquiredLocks = q.Local.AcquiredLocks
+	} else {
+		p.Local.AcquiredLocks = append(p.Local.AcquiredLocks, q.Local.AcquiredLocks...)
+	}
+	q.Local.AcquiredLocks = nil
+
+	if p.Local.ResolvedLocks == nil {
+		p.Local.ResolvedLocks = q.Local.ResolvedLocks
+	} else {
+		p.Local.ResolvedLocks = append(p.Local.ResolvedLocks, q.Local.ResolvedLocks...)
+	}
+	q.Local.ResolvedLocks = nil
+
 	if p.Local.UpdatedTxns == nil {
 		p.Local.UpdatedTxns = q.Local.UpdatedTxns
 	} else {
@@ -1812,7 +1812,7 @@ func TestStoreRangeMergeAddReplicaRace(t *testing.T) {
 	afterDesc := tc.LookupRangeOrFatal(t, scratchStartKey)
 
 	const acceptableMergeErr = `unexpected value: raw_bytes|ranges not collocated` +
-		`|cannot merge range with non-voter replicas`
+		`|cannot merge range with learner or joint replicas`
 	if mergeErr == nil && testutils.IsError(addErr, `descriptor changed: \[expected\]`) {
 		// Merge won the race, no add happened.
 		require.Len(t, afterDesc.Replicas().Voters(), 1)
@@ -3198,6 +3198,9 @@ func TestMergeQueue(t *testing.T) {
 	sv := &storeCfg.Settings.SV
 	storagebase.MergeQueueEnabled.Override(sv, true)
 	storage.MergeQueueInterval.Override(sv, 0) // process greedily
+	// Don't wait for the ranges to accumulate load history before merging,
+	// except in the subtest that specifically exercises that.
+	storage.SplitByLoadMergeDelay.Override(sv, 0)
 	var mtc multiTestContext
 	// This test was written before the multiTestContext started creating many
 	// system ranges at startup, and hasn't been update to take that into account.
@@ -3337,8 +3340,38 @@ func TestMergeQueue(t *testing.T) {
 		verifyMerged(t)
 	})
 
-	// TODO(jeffreyxiao): Add subtest to consider load when making merging
-	// decisions.
+	t.Run("load-history", func(t *testing.T) {
+		reset(t)
+		clearRange(t, lhsStartKey, rhsEndKey)
+
+		// The RHS was just split off and has not accumulated a full retention
+		// period of load history, so it may have been hot enough to be split by
+		// load and must not be merged yet.
+		storage.SplitByLoadMergeDelay.Override(sv, time.Hour)
+		store.MustForceMergeScanAndProcess()
+		verifyUnmerged(t)
+
+		// Once the load history covers the retention period, the ranges are
+		// merged.
+		storage.SplitByLoadMergeDelay.Override(sv, 0)
+		store.MustForceMergeScanAndProcess()
+		verifyMerged(t)
+	})
+
+	t.Run("load-above-threshold", func(t *testing.T) {
+		reset(t)
+		clearRange(t, lhsStartKey, rhsEndKey)
+
+		// The ranges are empty, but their combined load would cause the merged
+		// range to be split by load.
+		storage.SplitByLoadQPSThreshold.Override(sv, 0)
+		store.MustForceMergeScanAndProcess()
+		verifyUnmerged(t)
+
+		storage.SplitByLoadQPSThreshold.Override(sv, storage.SplitByLoadQPSThreshold.Default())
+		store.MustForceMergeScanAndProcess()
+		verifyMerged(t)
+	})
 
 	t.Run("sticky-bit", func(t *testing.T) {
 		reset(t)
@@ -3386,6 +3419,40 @@ func TestMergeQueue(t *testing.T) {
 		store.MustForceMergeScanAndProcess()
 		verifyMerged(t)
 	})
+
+	t.Run("non-voter-on-rhs", func(t *testing.T) {
+		reset(t)
+		verifyUnmerged(t)
+		// The LHS has no non-voter on the second store, so the merge queue
+		// removes the RHS's non-voter before merging.
+		if _, err := mtc.changeReplicas(rhsStartKey, 1, roachpb.ADD_NON_VOTER); err != nil {
+			t.Fatal(err)
+		}
+
+		clearRange(t, lhsStartKey, rhsEndKey)
+		store.MustForceMergeScanAndProcess()
+		verifyMerged(t)
+		require.Empty(t, lhs().Desc().Replicas().NonVoters())
+	})
+
+	t.Run("collocated-non-voters", func(t *testing.T) {
+		reset(t)
+		verifyUnmerged(t)
+		// Both sides have a non-voter on the second store, which is kept around
+		// by the merge.
+		for _, k := range []roachpb.RKey{lhsStartKey, rhsStartKey} {
+			if _, err := mtc.changeReplicas(k, 1, roachpb.ADD_NON_VOTER); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		clearRange(t, lhsStartKey, rhsEndKey)
+		store.MustForceMergeScanAndProcess()
+		verifyMerged(t)
+		nonVoters := lhs().Desc().Replicas().NonVoters()
+		require.Len(t, nonVoters, 1)
+		require.Equal(t, mtc.idents[1].StoreID, nonVoters[0].StoreID)
+	})
 }
 
 func TestInvalidSubsumeRequest(t *testing.T) {
@@ -1186,7 +1186,7 @@ func TestReplicateAfterRemoveAndSplit(t *testing.T) {
 	// Split the range.
 	splitKey := roachpb.Key("m")
 	splitArgs := adminSplitArgs(splitKey)
-	if _, err := rep1.AdminSplit(context.Background(), *splitArgs, "test"); err != nil {
+	if _, err := rep1.AdminSplit(context.Background(), *splitArgs, storagepb.ReasonAdminRequest, "test"); err != nil {
 		t.Fatal(err)
 	}
 
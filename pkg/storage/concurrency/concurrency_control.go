@@ -309,6 +309,10 @@ type Request struct {
 	// The consistency level of the request. Only set if Txn is nil.
 	ReadConsistency roachpb.ReadConsistencyType
 
+	// The policy to use when the request encounters conflicting locks held by
+	// other active transactions.
+	WaitPolicy lock.WaitPolicy
+
 	// The individual requests in the batch.
 	Requests []roachpb.RequestUnion
 
@@ -514,6 +518,16 @@ type lockTable interface {
 	//     txn.WriteTimestamp.
 	UpdateLocks(*roachpb.LockUpdate) error
 
+	// FindConflictingLocks returns the locks on keys in the span that are held
+	// by transactions other than the provided one, which is nil for
+	// non-transactional requests. At most maxLocks locks are returned.
+	//
+	// The method does not enqueue the caller in the wait-queues of the locks.
+	// It is used by requests that handle conflicting locks by pushing their
+	// holders, like they do for conflicting intents, instead of waiting in the
+	// lockTable.
+	FindConflictingLocks(span roachpb.Span, txn *enginepb.TxnMeta, maxLocks int) []roachpb.Intent
+
 	// Clear removes all locks and lock wait-queues from the lockTable.
 	Clear()
 
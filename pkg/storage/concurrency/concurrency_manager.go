@@ -221,6 +221,11 @@ func shouldAcquireLatches(req Request) bool {
 // requests want to proceed to evaluation even in the presence of conflicts
 // because they know how to handle them.
 func shouldWaitOnConflicts(req Request) bool {
+	if req.WaitPolicy == lock.WaitPolicy_SkipLocked {
+		// Requests with a SkipLocked wait policy skip over keys locked by other
+		// transactions during evaluation instead of waiting on them.
+		return false
+	}
 	for _, ru := range req.Requests {
 		arg := ru.GetInner()
 		if roachpb.IsTransactional(arg) {
@@ -1638,6 +1638,35 @@ func (t *lockTableImpl) UpdateLocks(up *roachpb.LockUpdate) error {
 	return err
 }
 
+// FindConflictingLocks implements the lockTable interface.
+func (t *lockTableImpl) FindConflictingLocks(
+	span roachpb.Span, txn *enginepb.TxnMeta, maxLocks int,
+) []roachpb.Intent {
+	ss := spanset.SpanGlobal
+	if keys.IsLocal(span.Key) {
+		ss = spanset.SpanLocal
+	}
+	tree := &t.locks[ss]
+	var intents []roachpb.Intent
+	findFunc := func(i btree.Item) bool {
+		l := i.(*lockState)
+		l.mu.Lock()
+		if holder, _, _ := l.getLockerInfo(); holder != nil && (txn == nil || holder.ID != txn.ID) {
+			intents = append(intents, roachpb.MakeIntent(holder, l.key))
+		}
+		l.mu.Unlock()
+		return len(intents) < maxLocks
+	}
+	tree.mu.RLock()
+	defer tree.mu.RUnlock()
+	if len(span.EndKey) > 0 {
+		tree.AscendRange(&lockState{key: span.Key}, &lockState{key: span.EndKey}, findFunc)
+	} else if i := tree.Get(&lockState{key: span.Key}); i != nil {
+		findFunc(i)
+	}
+	return intents
+}
+
 // Iteration helper for findNextLockAfter. Returns the next span to search
 // over, or nil if the iteration is done.
 // REQUIRES: g.mu is locked.
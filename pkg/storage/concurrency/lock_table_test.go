@@ -108,6 +108,12 @@ should-wait r=<name>
 
  Calls lockTableGuard.ShouldWait.
 
+find-conflicting txn=<name>|none span=<start>[,<end>] max=<int>
+----
+<conflicting locks>
+
+ Calls lockTable.FindConflictingLocks.
+
 clear
 ----
 <state of lock table>
@@ -311,6 +317,27 @@ func TestLockTableBasic(t *testing.T) {
 				}
 				return fmt.Sprintf("%t", g.ShouldWait())
 
+			case "find-conflicting":
+				var txnName string
+				d.ScanArgs(t, "txn", &txnName)
+				var txnMeta *enginepb.TxnMeta
+				if txnName != "none" {
+					var ok bool
+					if txnMeta, ok = txnsByName[txnName]; !ok {
+						d.Fatalf(t, "unknown txn %s", txnName)
+					}
+				}
+				var s string
+				d.ScanArgs(t, "span", &s)
+				span := getSpan(t, d, s)
+				var maxLocks int
+				d.ScanArgs(t, "max", &maxLocks)
+				var buf strings.Builder
+				for _, intent := range lt.FindConflictingLocks(span, txnMeta, maxLocks) {
+					fmt.Fprintf(&buf, "lock: %s, txn: %s\n", intent.Key, intent.Txn.ID)
+				}
+				return buf.String()
+
 			case "guard-state":
 				var reqName string
 				d.ScanArgs(t, "r", &reqName)
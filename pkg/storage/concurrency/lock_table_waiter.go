@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/storage/intentresolver"
 	"github.com/cockroachdb/cockroach/pkg/storage/spanset"
@@ -84,6 +85,15 @@ func (w *lockTableWaiterImpl) WaitOn(
 				// pusher will already push to detect coordinator failures and
 				// abandoned locks, so there's no need to do anything in this
 				// state.
+				//
+				// Requests with an Error wait policy are the exception, as they
+				// must not wait at all.
+				if req.WaitPolicy == lock.WaitPolicy_Error {
+					if err := w.pushNoWait(ctx, req, state); err != nil {
+						return err
+					}
+					continue
+				}
 				if req.Txn == nil {
 					continue
 				}
@@ -121,6 +131,12 @@ func (w *lockTableWaiterImpl) WaitOn(
 				// aborted transaction IDs that allowed us to notice and immediately
 				// resolve abandoned intents then we might be able to get rid of
 				// this state.
+				if req.WaitPolicy == lock.WaitPolicy_Error {
+					if err := w.pushNoWait(ctx, req, state); err != nil {
+						return err
+					}
+					continue
+				}
 				if err := w.pushTxn(ctx, req, state); err != nil {
 					return err
 				}
@@ -134,6 +150,9 @@ func (w *lockTableWaiterImpl) WaitOn(
 				// txnWaitQueue. Once this completes, the request should stop
 				// waiting on this lockTableGuard, as it will no longer observe
 				// lock-table state transitions.
+				if req.WaitPolicy == lock.WaitPolicy_Error {
+					return w.pushNoWait(ctx, req, state)
+				}
 				return w.pushTxn(ctx, req, state)
 
 			case waitSelf:
@@ -202,15 +221,25 @@ func (w *lockTableWaiterImpl) pushTxn(ctx context.Context, req Request, ws waiti
 	}
 
 	var pushType roachpb.PushTxnType
-	switch ws.guardAccess {
-	case spanset.SpanReadOnly:
+	switch {
+	case req.WaitPolicy == lock.WaitPolicy_Error:
+		// The request must not wait for the lock holder, but it still pushes
+		// it to find out whether the lock was abandoned. A PUSH_TOUCH push
+		// returns immediately if the lock holder is still active.
+		pushType = roachpb.PUSH_TOUCH
+	case ws.guardAccess == spanset.SpanReadOnly:
 		pushType = roachpb.PUSH_TIMESTAMP
-	case spanset.SpanReadWrite:
+	case ws.guardAccess == spanset.SpanReadWrite:
 		pushType = roachpb.PUSH_ABORT
 	}
 
 	pusheeTxn, err := w.ir.PushTransaction(ctx, ws.txn, h, pushType)
 	if err != nil {
+		if _, ok := err.GetDetail().(*roachpb.TransactionPushError); ok &&
+			req.WaitPolicy == lock.WaitPolicy_Error {
+			// The lock holder is still active.
+			return newWriteIntentErr(ws)
+		}
 		return err
 	}
 	if !ws.held {
@@ -232,6 +261,27 @@ func (w *lockTableWaiterImpl) pushTxn(ctx context.Context, req Request, ws waiti
 	return w.ir.ResolveIntent(ctx, resolve, opts)
 }
 
+// pushNoWait resolves a conflict for a request with an Error wait policy
+// without waiting. If the conflict is a lock, its holder is pushed with a
+// PUSH_TOUCH push, which removes the lock if it was abandoned and returns a
+// WriteIntentError if its holder is still active. If the conflict is a
+// reservation, the reservation holder is known to be active, so a
+// WriteIntentError is returned immediately.
+func (w *lockTableWaiterImpl) pushNoWait(ctx context.Context, req Request, ws waitingState) *Error {
+	if !ws.held {
+		return newWriteIntentErr(ws)
+	}
+	return w.pushTxn(ctx, req, ws)
+}
+
+// newWriteIntentErr returns a WriteIntentError for the conflict described by
+// the provided waitingState.
+func newWriteIntentErr(ws waitingState) *Error {
+	return roachpb.NewError(&roachpb.WriteIntentError{
+		Intents: []roachpb.Intent{roachpb.MakeIntent(ws.txn, ws.key)},
+	})
+}
+
 func hasMinPriority(txn *enginepb.TxnMeta) bool {
 	return txn.Priority == enginepb.MinTxnPriority
 }
@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/storage/intentresolver"
 	"github.com/cockroachdb/cockroach/pkg/storage/spanset"
@@ -256,6 +257,110 @@ func TestLockTableWaiterWithNonTxn(t *testing.T) {
 	})
 }
 
+// TestLockTableWaiterWithErrorWaitPolicy tests the lockTableWaiter's behavior
+// under different waiting states with an Error wait policy.
+func TestLockTableWaiterWithErrorWaitPolicy(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	observedTS := hlc.Timestamp{WallTime: 15}
+	makeReq := func() Request {
+		txn := makeTxnProto("request")
+		txn.UpdateObservedTimestamp(2, observedTS)
+		return Request{
+			Txn:        &txn,
+			Timestamp:  txn.ReadTimestamp,
+			WaitPolicy: lock.WaitPolicy_Error,
+		}
+	}
+
+	t.Run("state", func(t *testing.T) {
+		t.Run("waitFor", func(t *testing.T) {
+			testErrorWaitPush(t, waitFor, makeReq)
+		})
+
+		t.Run("waitForDistinguished", func(t *testing.T) {
+			testErrorWaitPush(t, waitForDistinguished, makeReq)
+		})
+
+		t.Run("waitElsewhere", func(t *testing.T) {
+			testErrorWaitPush(t, waitElsewhere, makeReq)
+		})
+	})
+}
+
+func testErrorWaitPush(t *testing.T, k stateKind, makeReq func() Request) {
+	ctx := context.Background()
+	keyA := roachpb.Key("keyA")
+	testutils.RunTrueAndFalse(t, "lockHeld", func(t *testing.T, lockHeld bool) {
+		testutils.RunTrueAndFalse(t, "pusheeActive", func(t *testing.T, pusheeActive bool) {
+			if !lockHeld && !pusheeActive {
+				// The reservation holder is known to be active, so the pushee
+				// is not pushed at all.
+				return
+			}
+			w, ir, g := setupLockTableWaiterTest()
+			defer w.stopper.Stop(ctx)
+			pusheeTxn := makeTxnProto("pushee")
+
+			g.state = waitingState{
+				stateKind:   k,
+				txn:         &pusheeTxn.TxnMeta,
+				ts:          pusheeTxn.WriteTimestamp,
+				key:         keyA,
+				held:        lockHeld,
+				access:      spanset.SpanReadWrite,
+				guardAccess: spanset.SpanReadWrite,
+			}
+			g.notify()
+
+			req := makeReq()
+			ir.pushTxn = func(
+				_ context.Context,
+				pusheeArg *enginepb.TxnMeta,
+				h roachpb.Header,
+				pushType roachpb.PushTxnType,
+			) (roachpb.Transaction, *Error) {
+				require.True(t, lockHeld)
+				require.Equal(t, &pusheeTxn.TxnMeta, pusheeArg)
+				require.Equal(t, req.Txn, h.Txn)
+				require.Equal(t, roachpb.PUSH_TOUCH, pushType)
+
+				resp := roachpb.Transaction{TxnMeta: *pusheeArg, Status: roachpb.PENDING}
+				if pusheeActive {
+					return roachpb.Transaction{}, roachpb.NewError(&roachpb.TransactionPushError{
+						PusheeTxn: resp,
+					})
+				}
+
+				// The lock holder is ABORTED, so we'll try to resolve its
+				// intent and continue waiting.
+				resp.Status = roachpb.ABORTED
+				ir.resolveIntent = func(_ context.Context, intent roachpb.LockUpdate) *Error {
+					require.Equal(t, keyA, intent.Key)
+					require.Equal(t, pusheeTxn.ID, intent.Txn.ID)
+					require.Equal(t, roachpb.ABORTED, intent.Status)
+					g.state = waitingState{stateKind: doneWaiting}
+					g.notify()
+					return nil
+				}
+				return resp, nil
+			}
+
+			err := w.WaitOn(ctx, req, g)
+			if pusheeActive {
+				require.NotNil(t, err)
+				wiErr, ok := err.GetDetail().(*roachpb.WriteIntentError)
+				require.True(t, ok, "expected WriteIntentError, found %v", err)
+				require.Len(t, wiErr.Intents, 1)
+				require.Equal(t, keyA, wiErr.Intents[0].Key)
+				require.Equal(t, pusheeTxn.ID, wiErr.Intents[0].Txn.ID)
+			} else {
+				require.Nil(t, err)
+			}
+		})
+	})
+}
+
 func testWaitPush(t *testing.T, k stateKind, makeReq func() Request, expPushTS hlc.Timestamp) {
 	ctx := context.Background()
 	keyA := roachpb.Key("keyA")
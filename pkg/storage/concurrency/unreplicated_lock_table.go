@@ -0,0 +1,100 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package concurrency
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/concurrency/lock"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// UnreplicatedLockTable tracks the unreplicated locks that transactions
+// acquire on a range through locking reads. It is used by ranges that don't
+// sequence their requests through a Manager yet. Requests that conflict with
+// one of its locks don't wait in the lock's wait-queue. Instead, they push the
+// lock holder, like they do for conflicting intents, and the lock is released
+// when the holder's intents are resolved.
+//
+// Unreplicated locks are only held on the leaseholder and are lost when the
+// lease changes hands. This is acceptable because they only provide a best
+// effort guarantee of mutual exclusion; serializability is still guaranteed
+// by the intents and the timestamp cache.
+type UnreplicatedLockTable struct {
+	lt lockTable
+}
+
+// NewUnreplicatedLockTable creates a new UnreplicatedLockTable.
+func NewUnreplicatedLockTable() *UnreplicatedLockTable {
+	return &UnreplicatedLockTable{lt: newLockTable(10000 /* arbitrary */)}
+}
+
+// OnLockAcquired informs the lock table that a transaction has acquired a new
+// unreplicated lock.
+func (t *UnreplicatedLockTable) OnLockAcquired(ctx context.Context, up *roachpb.LockUpdate) {
+	if up.Durability != lock.Unreplicated {
+		log.Fatal(ctx, errors.AssertionFailedf("unexpected lock durability %s", up.Durability))
+	}
+	if err := t.lt.AcquireLock(&up.Txn, up.Key, lock.Exclusive, up.Durability); err != nil {
+		log.Fatal(ctx, errors.HandleAsAssertionFailure(err))
+	}
+}
+
+// OnLockUpdated informs the lock table that a transaction has updated or
+// released the locks in a span, which happens when its intents in the span
+// are resolved.
+func (t *UnreplicatedLockTable) OnLockUpdated(ctx context.Context, up *roachpb.LockUpdate) {
+	if err := t.lt.UpdateLocks(up); err != nil {
+		log.Fatal(ctx, errors.HandleAsAssertionFailure(err))
+	}
+}
+
+// FindConflictingLocks returns the locks on keys in the span that are held by
+// transactions other than the provided one, which is nil for
+// non-transactional requests. At most maxLocks locks are returned.
+func (t *UnreplicatedLockTable) FindConflictingLocks(
+	span roachpb.Span, txn *enginepb.TxnMeta, maxLocks int,
+) []roachpb.Intent {
+	return t.lt.FindConflictingLocks(span, txn, maxLocks)
+}
+
+// Clear removes all locks from the lock table.
+func (t *UnreplicatedLockTable) Clear() {
+	t.lt.Clear()
+}
+
+// String returns a debug string representing the state of the lock table.
+func (t *UnreplicatedLockTable) String() string {
+	return t.lt.String()
+}
+
+// View returns a view of the lock table for a scan performed by the provided
+// transaction, which is nil for non-transactional scans.
+func (t *UnreplicatedLockTable) View(txn *enginepb.TxnMeta) engine.LockTableView {
+	return lockTableView{t: t, txn: txn}
+}
+
+// lockTableView implements the engine.LockTableView interface.
+type lockTableView struct {
+	t   *UnreplicatedLockTable
+	txn *enginepb.TxnMeta
+}
+
+var _ engine.LockTableView = lockTableView{}
+
+// IsKeyLockedByConflictingTxn implements the engine.LockTableView interface.
+func (v lockTableView) IsKeyLockedByConflictingTxn(key roachpb.Key) bool {
+	return len(v.t.FindConflictingLocks(roachpb.Span{Key: key}, v.txn, 1 /* maxLocks */)) > 0
+}
@@ -31,6 +31,18 @@ var consistencyCheckInterval = settings.RegisterNonNegativeDurationSetting(
 	24*time.Hour,
 )
 
+// ConsistencyCheckQuarantine is a setting that controls whether replicas that
+// the consistency checker finds to disagree with their peers are quarantined
+// instead of terminating their node.
+var ConsistencyCheckQuarantine = settings.RegisterBoolSetting(
+	"server.consistency_check.quarantine.enabled",
+	"if enabled, replicas found to be inconsistent are quarantined (they stop serving"+
+		" requests and participating in Raft, also across restarts, until an operator"+
+		" removes their marker from the store's auxiliary directory) instead of"+
+		" terminating their node",
+	false,
+)
+
 var testingAggressiveConsistencyChecks = envutil.EnvOrDefaultBool("COCKROACH_CONSISTENCY_AGGRESSIVE", false)
 
 type consistencyQueue struct {
@@ -16,6 +16,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"math/rand"
+	"os"
 	"path/filepath"
 	"testing"
 	"time"
@@ -35,6 +36,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -174,8 +176,12 @@ func TestCheckConsistencyReplay(t *testing.T) {
 
 func TestCheckConsistencyInconsistent(t *testing.T) {
 	defer leaktest.AfterTest(t)()
+	testutils.RunTrueAndFalse(t, "quarantine", testCheckConsistencyInconsistent)
+}
 
+func testCheckConsistencyInconsistent(t *testing.T, quarantine bool) {
 	sc := storage.TestStoreConfig(nil)
+	storage.ConsistencyCheckQuarantine.Override(&sc.Settings.SV, quarantine)
 	mtc := &multiTestContext{
 		storeConfig: &sc,
 		// This test was written before the multiTestContext started creating many
@@ -244,7 +250,7 @@ func TestCheckConsistencyInconsistent(t *testing.T) {
 
 			notifyReportDiff <- struct{}{}
 		}
-	// s2 (index 1) will panic.
+	// s2 (index 1) will panic, unless it is quarantined.
 	notifyFatal := make(chan struct{}, 1)
 	sc.TestingKnobs.ConsistencyTestingKnobs.OnBadChecksumFatal = func(s roachpb.StoreIdent) {
 		if s != *mtc.Store(1).Ident {
@@ -327,10 +333,25 @@ func TestCheckConsistencyInconsistent(t *testing.T) {
 	case <-time.After(5 * time.Second):
 		t.Fatal("CheckConsistency() failed to report a diff as expected")
 	}
-	select {
-	case <-notifyFatal:
-	case <-time.After(5 * time.Second):
-		t.Fatal("CheckConsistency() failed to panic as expected")
+	if quarantine {
+		repl := mtc.stores[1].LookupReplica(roachpb.RKey(diffKey))
+		testutils.SucceedsSoon(t, func() error {
+			if !repl.IsQuarantined() {
+				return errors.Errorf("%s is not quarantined", repl)
+			}
+			return nil
+		})
+		select {
+		case <-notifyFatal:
+			t.Fatal("unexpected panic")
+		default:
+		}
+	} else {
+		select {
+		case <-notifyFatal:
+		case <-time.After(5 * time.Second):
+			t.Fatal("CheckConsistency() failed to panic as expected")
+		}
 	}
 
 	// Checkpoints should have been created on all stores and they're not empty.
@@ -359,10 +380,50 @@ func TestCheckConsistencyInconsistent(t *testing.T) {
 	assert.Contains(t, resp.Result[0].Detail, `[minority]`)
 	assert.Contains(t, resp.Result[0].Detail, `stats`)
 
-	// A death rattle should have been written on s2 (store index 1).
+	// A diff report should have been written on s1 (store index 0).
+	reports, err := storage.ListConsistencyDiffReports(mtc.engines[0])
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	report, err := storage.ReadConsistencyDiffReport(mtc.engines[0], reports[0])
+	require.NoError(t, err)
+	require.Equal(t, resp.Result[0].RangeID, report.RangeID)
+	require.Len(t, report.Checksums, numStores)
+	require.Len(t, report.Diffs, 1)
+	require.Equal(t, mtc.Store(1).StoreID(), report.Diffs[0].Minority.StoreID)
+	require.Len(t, report.Diffs[0].KVs, 1)
+	require.Equal(t, roachpb.Key(diffKey), report.Diffs[0].KVs[0].Key)
+	require.Equal(t, `"e"`, report.Diffs[0].KVs[0].PrettyKey)
+
+	// Unless it was quarantined, a death rattle should have been written on s2
+	// (store index 1).
 	b, err := ioutil.ReadFile(base.PreventedStartupFile(mtc.stores[1].Engine().GetAuxiliaryDir()))
+	if !quarantine {
+		require.NoError(t, err)
+		require.NotEmpty(t, b)
+		return
+	}
+	require.True(t, os.IsNotExist(err), "unexpected error: %v", err)
+
+	// The quarantine must survive a restart of s2, and be lifted only once its
+	// marker has been removed.
+	rangeID := resp.Result[0].RangeID
+	markers, err := filepath.Glob(filepath.Join(
+		mtc.engines[1].GetAuxiliaryDir(), "quarantined-replicas", fmt.Sprintf("r%d_*", rangeID)))
+	require.NoError(t, err)
+	require.Len(t, markers, 1)
+
+	mtc.stopStore(1)
+	mtc.restartStore(1)
+	repl, err := mtc.Store(1).GetReplica(rangeID)
+	require.NoError(t, err)
+	require.True(t, repl.IsQuarantined(), "%s is no longer quarantined after a restart", repl)
+
+	require.NoError(t, mtc.engines[1].DeleteFile(markers[0]))
+	mtc.stopStore(1)
+	mtc.restartStore(1)
+	repl, err = mtc.Store(1).GetReplica(rangeID)
 	require.NoError(t, err)
-	require.NotEmpty(t, b)
+	require.False(t, repl.IsQuarantined(), "%s is still quarantined after removing its marker", repl)
 }
 
 // TestConsistencyQueueRecomputeStats is an end-to-end test of the mechanism CockroachDB
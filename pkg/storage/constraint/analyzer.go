@@ -43,18 +43,33 @@ func AnalyzeConstraints(
 	getStoreDescFn func(roachpb.StoreID) (roachpb.StoreDescriptor, bool),
 	existing []roachpb.ReplicaDescriptor,
 	zone *zonepb.ZoneConfig,
+) AnalyzedConstraints {
+	return AnalyzeConstraintsForReplicas(
+		ctx, getStoreDescFn, existing, *zone.NumReplicas, zone.Constraints)
+}
+
+// AnalyzeConstraintsForReplicas is like AnalyzeConstraints, but analyzes the
+// given constraints for a set of numReplicas replicas instead of those of the
+// zone. This is used to analyze the voter constraints of a zone against its
+// voting replicas only.
+func AnalyzeConstraintsForReplicas(
+	ctx context.Context,
+	getStoreDescFn func(roachpb.StoreID) (roachpb.StoreDescriptor, bool),
+	existing []roachpb.ReplicaDescriptor,
+	numReplicas int32,
+	constraints []zonepb.Constraints,
 ) AnalyzedConstraints {
 	result := AnalyzedConstraints{
-		Constraints: zone.Constraints,
+		Constraints: constraints,
 	}
 
-	if len(zone.Constraints) > 0 {
-		result.SatisfiedBy = make([][]roachpb.StoreID, len(zone.Constraints))
+	if len(constraints) > 0 {
+		result.SatisfiedBy = make([][]roachpb.StoreID, len(constraints))
 		result.Satisfies = make(map[roachpb.StoreID][]int)
 	}
 
 	var constrainedReplicas int32
-	for i, subConstraints := range zone.Constraints {
+	for i, subConstraints := range constraints {
 		constrainedReplicas += subConstraints.NumReplicas
 		for _, repl := range existing {
 			// If for some reason we don't have the store descriptor (which shouldn't
@@ -68,7 +83,7 @@ func AnalyzeConstraints(
 			}
 		}
 	}
-	if constrainedReplicas > 0 && constrainedReplicas < *zone.NumReplicas {
+	if constrainedReplicas > 0 && constrainedReplicas < numReplicas {
 		result.UnconstrainedReplicas = true
 	}
 	return result
@@ -180,6 +180,14 @@ type IterOptions struct {
 	// [start, end] time range. If you must guarantee that you never see a key
 	// outside of the time bounds, perform your own filtering.
 	MinTimestampHint, MaxTimestampHint hlc.Timestamp
+	// RangeTombstoneLookup indicates that the iterator is used to look up the
+	// MVCC range tombstones that apply to a span of global keys, or the marker
+	// of a range that contains any. The lookup scans keys that are not declared
+	// by the requests reading or writing the span, so wrappers that verify key
+	// accesses must not check the iterator. This is safe because range
+	// tombstones are only ever written by requests that declare the global
+	// span they delete.
+	RangeTombstoneLookup bool
 }
 
 // Reader is the read interface to an engine's data.
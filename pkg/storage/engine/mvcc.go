@@ -711,6 +711,10 @@ type MVCCGetOptions struct {
 	Tombstones       bool
 	FailOnMoreRecent bool
 	Txn              *roachpb.Transaction
+	// NoRangeTombstones can be set if the range being read is known not to
+	// contain MVCC range tombstones (see MVCCHasRangeTombstones), in which case
+	// the read doesn't look them up.
+	NoRangeTombstones bool
 }
 
 func (opts *MVCCGetOptions) validate() error {
@@ -744,12 +748,24 @@ func (opts *MVCCGetOptions) validate() error {
 // timestamp. Similarly, a WriteIntentError will be returned if the read
 // observes another transaction's intent, even if it has a timestamp above
 // the read timestamp.
+//
+// Values deleted by an MVCC range tombstone at or below the read timestamp
+// are treated like values deleted by a deletion tombstone at the range
+// tombstone's timestamp.
 func MVCCGet(
 	ctx context.Context, reader Reader, key roachpb.Key, timestamp hlc.Timestamp, opts MVCCGetOptions,
 ) (*roachpb.Value, *roachpb.Intent, error) {
 	iter := reader.NewIterator(IterOptions{Prefix: true})
 	defer iter.Close()
-	return mvccGet(ctx, iter, key, timestamp, opts)
+	value, intent, err := mvccGet(ctx, iter, key, timestamp, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	value, err = applyRangeTombstonesToGet(reader, key, timestamp, opts, value)
+	if err != nil {
+		return nil, nil, err
+	}
+	return value, intent, nil
 }
 
 func mvccGet(
@@ -2322,8 +2338,9 @@ func mvccScanToBytes(
 		return MVCCScanResult{ResumeSpan: resumeSpan}, nil
 	}
 
-	// If the iterator has a specialized implementation, defer to that.
-	if mvccIter, ok := iter.(MVCCIterator); ok && mvccIter.MVCCOpsSpecialized() {
+	// If the iterator has a specialized implementation, defer to that. The
+	// specialized implementations don't support skipping locked keys.
+	if mvccIter, ok := iter.(MVCCIterator); ok && mvccIter.MVCCOpsSpecialized() && !opts.SkipLocked {
 		return mvccIter.MVCCScan(key, endKey, max, timestamp, opts)
 	}
 
@@ -2341,6 +2358,8 @@ func mvccScanToBytes(
 		inconsistent:     opts.Inconsistent,
 		tombstones:       opts.Tombstones,
 		failOnMoreRecent: opts.FailOnMoreRecent,
+		skipLocked:       opts.SkipLocked,
+		lockTable:        opts.LockTable,
 	}
 
 	mvccScanner.init(opts.Txn)
@@ -2382,6 +2401,12 @@ func mvccScanToKvs(
 	if err != nil {
 		return MVCCScanResult{}, err
 	}
+	return mvccScanBytesToKvs(res)
+}
+
+// mvccScanBytesToKvs decodes the KVData of a scan result into its KVs.
+func mvccScanBytesToKvs(res MVCCScanResult) (MVCCScanResult, error) {
+	var err error
 	res.KVs = make([]roachpb.KeyValue, res.NumKeys)
 	kvData := res.KVData
 	res.KVData = nil
@@ -2445,6 +2470,7 @@ type MVCCScanOptions struct {
 	Tombstones       bool
 	Reverse          bool
 	FailOnMoreRecent bool
+	SkipLocked       bool
 	Txn              *roachpb.Transaction
 	// TargetBytes is a byte threshold to limit the amount of data pulled into
 	// memory during a Scan operation. Once the target is satisfied (i.e. met or
@@ -2458,6 +2484,22 @@ type MVCCScanOptions struct {
 	//
 	// The zero value indicates no limit.
 	TargetBytes int64
+	// LockTable is consulted by scans that skip locked keys to also skip the
+	// keys on which other transactions hold unreplicated locks, which are not
+	// stored in the engine like intents are. It may be nil.
+	LockTable LockTableView
+	// NoRangeTombstones can be set if the range being read is known not to
+	// contain MVCC range tombstones (see MVCCHasRangeTombstones), in which case
+	// the scan doesn't look them up.
+	NoRangeTombstones bool
+}
+
+// LockTableView provides the MVCC scanner with a view of the unreplicated
+// locks held on the keys that it reads.
+type LockTableView interface {
+	// IsKeyLockedByConflictingTxn returns whether the key is locked by a
+	// transaction other than the one performing the scan.
+	IsKeyLockedByConflictingTxn(roachpb.Key) bool
 }
 
 func (opts *MVCCScanOptions) validate() error {
@@ -2467,6 +2509,9 @@ func (opts *MVCCScanOptions) validate() error {
 	if opts.Inconsistent && opts.FailOnMoreRecent {
 		return errors.Errorf("cannot allow inconsistent reads with fail on more recent option")
 	}
+	if opts.Inconsistent && opts.SkipLocked {
+		return errors.Errorf("cannot allow inconsistent reads with skip locked option")
+	}
 	return nil
 }
 
@@ -2520,6 +2565,15 @@ type MVCCScanResult struct {
 // timestamp. Similarly, a WriteIntentError will be returned if the scan
 // observes another transaction's intent, even if it has a timestamp above
 // the read timestamp.
+//
+// When scanning in "skip locked" mode, keys that contain an intent written by
+// another transaction are omitted from the result entirely, regardless of the
+// timestamp of the intent, and no WriteIntentError is returned for them.
+//
+// Values deleted by an MVCC range tombstone at or below the read timestamp
+// are treated like values deleted by a deletion tombstone at the range
+// tombstone's timestamp. Like the latter, they don't count against the max
+// parameter unless the scan is in tombstones mode.
 func MVCCScan(
 	ctx context.Context,
 	reader Reader,
@@ -2528,9 +2582,11 @@ func MVCCScan(
 	timestamp hlc.Timestamp,
 	opts MVCCScanOptions,
 ) (MVCCScanResult, error) {
-	iter := reader.NewIterator(IterOptions{LowerBound: key, UpperBound: endKey})
-	defer iter.Close()
-	return mvccScanToKvs(ctx, iter, key, endKey, max, timestamp, opts)
+	res, err := MVCCScanToBytes(ctx, reader, key, endKey, max, timestamp, opts)
+	if err != nil {
+		return MVCCScanResult{}, err
+	}
+	return mvccScanBytesToKvs(res)
 }
 
 // MVCCScanToBytes is like MVCCScan, but it returns the results in a byte array.
@@ -2542,9 +2598,14 @@ func MVCCScanToBytes(
 	timestamp hlc.Timestamp,
 	opts MVCCScanOptions,
 ) (MVCCScanResult, error) {
+	tombstones, err := findRangeTombstonesForRead(
+		reader, key, endKey, timestamp, opts.Txn, opts.FailOnMoreRecent, opts.NoRangeTombstones)
+	if err != nil {
+		return MVCCScanResult{}, err
+	}
 	iter := reader.NewIterator(IterOptions{LowerBound: key, UpperBound: endKey})
 	defer iter.Close()
-	return mvccScanToBytes(ctx, iter, key, endKey, max, timestamp, opts)
+	return mvccScanWithRangeTombstones(ctx, iter, tombstones, key, endKey, max, timestamp, opts)
 }
 
 // MVCCIterate iterates over the key range [start,end). At each step of the
@@ -2562,17 +2623,25 @@ func MVCCIterate(
 	opts MVCCScanOptions,
 	f func(roachpb.KeyValue) (bool, error),
 ) ([]roachpb.Intent, error) {
+	tombstones, err := findRangeTombstonesForRead(
+		reader, key, endKey, timestamp, opts.Txn, opts.FailOnMoreRecent, opts.NoRangeTombstones)
+	if err != nil {
+		return nil, err
+	}
 	iter := reader.NewIterator(IterOptions{LowerBound: key, UpperBound: endKey})
 	defer iter.Close()
 
 	var intents []roachpb.Intent
 	for {
 		const maxKeysPerScan = 1000
-		res, err := mvccScanToKvs(
-			ctx, iter, key, endKey, maxKeysPerScan, timestamp, opts)
+		res, err := mvccScanWithRangeTombstones(
+			ctx, iter, tombstones, key, endKey, maxKeysPerScan, timestamp, opts)
 		if err != nil {
 			return nil, err
 		}
+		if res, err = mvccScanBytesToKvs(res); err != nil {
+			return nil, err
+		}
 
 		if len(res.Intents) > 0 {
 			if intents == nil {
@@ -3161,6 +3230,10 @@ func MVCCResolveWriteIntentRangeUsingIter(
 // keys slice. The iterator is seeked in turn to each listed
 // key, clearing all values with timestamps <= to expiration. The
 // timestamp parameter is used to compute the intent age on GC.
+//
+// The latest version of a key can only be GC'ed if it is a deletion, if it
+// is deleted by an MVCC range tombstone at or below the timestamp, or if the
+// key holds MVCC range tombstones itself.
 func MVCCGarbageCollect(
 	ctx context.Context,
 	rw ReadWriter,
@@ -3192,6 +3265,9 @@ func MVCCGarbageCollect(
 		}
 		inlinedValue := meta.IsInline()
 		implicitMeta := iter.UnsafeKey().IsValue()
+		// rangeTombstoneNanos is set if the latest version is a value that was
+		// deleted by an MVCC range tombstone at that time.
+		var rangeTombstoneNanos int64
 		// First, check whether all values of the key are being deleted.
 		//
 		// Note that we naively can't terminate GC'ing keys loop early if we
@@ -3205,16 +3281,31 @@ func MVCCGarbageCollect(
 			// not marked deleted. However, for inline values we allow it;
 			// they are internal and GCing them directly saves the extra
 			// deletion step.
-			if !meta.Deleted && !inlinedValue {
-				return errors.Errorf("request to GC non-deleted, latest value of %q", gcKey.Key)
-			}
 			if meta.Txn != nil {
 				return errors.Errorf("request to GC intent at %q", gcKey.Key)
 			}
+			if !meta.Deleted && !inlinedValue && !IsMVCCRangeTombstoneKey(gcKey.Key) {
+				tombstones, err := MVCCFindRangeTombstones(
+					rw, gcKey.Key, gcKey.Key.Next(), hlc.Timestamp(meta.Timestamp), timestamp)
+				if err != nil {
+					return err
+				}
+				metaTS := hlc.Timestamp(meta.Timestamp)
+				deletedAt, ok := mvccRangeTombstones(tombstones).deletedAbove(
+					MVCCKey{Key: gcKey.Key, Timestamp: metaTS}, metaTS)
+				if !ok {
+					return errors.Errorf("request to GC non-deleted, latest value of %q", gcKey.Key)
+				}
+				// The latest version became non-live when the oldest range
+				// tombstone above it deleted it (see updateStatsOnRangeTombstone).
+				rangeTombstoneNanos = deletedAt.WallTime
+			}
 			if ms != nil {
 				if inlinedValue {
 					updateStatsForInline(ms, gcKey.Key, metaKeySize, metaValSize, 0, 0)
 					ms.AgeTo(timestamp.WallTime)
+				} else if rangeTombstoneNanos != 0 {
+					ms.Add(updateStatsOnGC(gcKey.Key, metaKeySize, metaValSize, meta, rangeTombstoneNanos))
 				} else {
 					ms.Add(updateStatsOnGC(gcKey.Key, metaKeySize, metaValSize, meta, meta.Timestamp.WallTime))
 				}
@@ -3245,6 +3336,9 @@ func MVCCGarbageCollect(
 		// and better commented version of this logic.
 
 		prevNanos := timestamp.WallTime
+		if rangeTombstoneNanos != 0 {
+			prevNanos = rangeTombstoneNanos
+		}
 		for ; ; iter.Next() {
 			if ok, err := iter.Valid(); err != nil {
 				return err
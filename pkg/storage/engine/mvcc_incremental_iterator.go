@@ -31,6 +31,9 @@ import (
 // start time would normally make it difficult to scan timestamp 0, but
 // CockroachDB uses that as a sentinel for key metadata anyway.
 //
+// If IterOptions.LowerBound is set, the deletions implied by the MVCC range
+// tombstones in the time range are presented like deletion tombstones.
+//
 // Expected usage:
 //    iter := NewMVCCIncrementalIterator(e, IterOptions{
 //        StartTime:  startTime,
@@ -50,7 +53,7 @@ import (
 // NOTE: This is not used by CockroachDB and has been preserved to serve as an
 // oracle to prove the correctness of the new export logic.
 type MVCCIncrementalIterator struct {
-	iter Iterator
+	iter SimpleIterator
 
 	// fields used for a workaround for a bug in the time-bound iterator
 	// (#28358)
@@ -62,6 +65,8 @@ type MVCCIncrementalIterator struct {
 	endTime   hlc.Timestamp
 	err       error
 	valid     bool
+	// initErr is set if the range tombstones could not be looked up.
+	initErr error
 
 	// For allocation avoidance.
 	meta enginepb.MVCCMetadata
@@ -81,6 +86,19 @@ type MVCCIncrementalIterOptions struct {
 func NewMVCCIncrementalIterator(
 	reader Reader, opts MVCCIncrementalIterOptions,
 ) *MVCCIncrementalIterator {
+	var tombstones []MVCCRangeTombstone
+	var initErr error
+	if len(opts.IterOptions.LowerBound) > 0 {
+		tombstones, initErr = MVCCFindRangeTombstones(reader,
+			opts.IterOptions.LowerBound, opts.IterOptions.UpperBound, opts.StartTime, opts.EndTime)
+		if len(tombstones) > 0 {
+			// The deletions are only presented before an older version of the
+			// key, which a time-bound iterator may skip.
+			opts.IterOptions.MinTimestampHint = hlc.Timestamp{}
+			opts.IterOptions.MaxTimestampHint = hlc.Timestamp{}
+		}
+	}
+
 	var sanityIter Iterator
 	if !opts.IterOptions.MinTimestampHint.IsEmpty() && !opts.IterOptions.MaxTimestampHint.IsEmpty() {
 		// It is necessary for correctness that sanityIter be created before iter.
@@ -98,16 +116,22 @@ func NewMVCCIncrementalIterator(
 	return &MVCCIncrementalIterator{
 		reader:     reader,
 		upperBound: opts.IterOptions.UpperBound,
-		iter:       reader.NewIterator(opts.IterOptions),
+		iter:       NewMVCCRangeTombstoneIterator(reader.NewIterator(opts.IterOptions), tombstones),
 		startTime:  opts.StartTime,
 		endTime:    opts.EndTime,
 		sanityIter: sanityIter,
+		initErr:    initErr,
 	}
 }
 
 // SeekGE advances the iterator to the first key in the engine which is >= the
 // provided key.
 func (i *MVCCIncrementalIterator) SeekGE(startKey MVCCKey) {
+	if i.initErr != nil {
+		i.err = i.initErr
+		i.valid = false
+		return
+	}
 	i.iter.SeekGE(startKey)
 	i.err = nil
 	i.valid = true
@@ -193,7 +217,7 @@ func (i *MVCCIncrementalIterator) advance() {
 			if i.startTime.Less(metaTimestamp) && metaTimestamp.LessEq(i.endTime) {
 				i.err = &roachpb.WriteIntentError{
 					Intents: []roachpb.Intent{
-						roachpb.MakeIntent(i.meta.Txn, i.iter.Key().Key),
+						roachpb.MakeIntent(i.meta.Txn, i.Key().Key),
 					},
 				}
 				i.valid = false
@@ -250,12 +274,16 @@ func (i *MVCCIncrementalIterator) Valid() (bool, error) {
 
 // Key returns the current key.
 func (i *MVCCIncrementalIterator) Key() MVCCKey {
-	return i.iter.Key()
+	unsafeKey := i.iter.UnsafeKey()
+	return MVCCKey{
+		Key:       append(roachpb.Key(nil), unsafeKey.Key...),
+		Timestamp: unsafeKey.Timestamp,
+	}
 }
 
 // Value returns the current value as a byte slice.
 func (i *MVCCIncrementalIterator) Value() []byte {
-	return i.iter.Value()
+	return append([]byte(nil), i.iter.UnsafeValue()...)
 }
 
 // UnsafeKey returns the same key as Key, but the memory is invalidated on the
@@ -0,0 +1,765 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package engine
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/pkg/errors"
+)
+
+// An MVCCRangeTombstone deletes all versions of the keys in the span
+// [StartKey, EndKey) that are older than its timestamp, without writing a
+// deletion tombstone for each of the keys. Reads at or above the tombstone's
+// timestamp don't see the deleted versions, while reads below it see the span
+// as it was before the deletion. The deleted versions, and eventually the
+// range tombstone itself, are removed by the GC queue once the tombstone falls
+// below the GC threshold.
+//
+// Range tombstones are stored as versions of the range-local key
+// keys.MVCCRangeTombstoneKey(StartKey), which hold the end key of the deleted
+// span. They can only delete global keys and are never written
+// transactionally. Range tombstones may overlap; a version is deleted if any
+// range tombstone above it covers its key.
+type MVCCRangeTombstone struct {
+	StartKey  roachpb.Key
+	EndKey    roachpb.Key
+	Timestamp hlc.Timestamp
+}
+
+func (t MVCCRangeTombstone) String() string {
+	return fmt.Sprintf("{%s-%s}@%s", t.StartKey, t.EndKey, t.Timestamp)
+}
+
+// ContainsKey returns whether the tombstone covers the given key.
+func (t MVCCRangeTombstone) ContainsKey(key roachpb.Key) bool {
+	return t.StartKey.Compare(key) <= 0 && key.Compare(t.EndKey) < 0
+}
+
+// Deletes returns whether the tombstone deletes the given version.
+func (t MVCCRangeTombstone) Deletes(key MVCCKey) bool {
+	return key.Timestamp.Less(t.Timestamp) && t.ContainsKey(key.Key)
+}
+
+// IsMVCCRangeTombstoneKey returns whether the key is a range-local key
+// holding range tombstones.
+func IsMVCCRangeTombstoneKey(key roachpb.Key) bool {
+	if !bytes.HasPrefix(key, keys.LocalRangePrefix) {
+		return false
+	}
+	_, suffix, _, err := keys.DecodeRangeKey(key)
+	return err == nil && bytes.Equal(suffix, keys.LocalMVCCRangeTombstoneSuffix)
+}
+
+// mvccRangeTombstones is a set of range tombstones, typically those found by
+// MVCCFindRangeTombstones for a span that is being read.
+type mvccRangeTombstones []MVCCRangeTombstone
+
+// deletedAt returns the timestamp of the newest tombstone at or below the
+// read timestamp that deletes the given version. It returns false if the
+// version is visible at the read timestamp.
+func (ts mvccRangeTombstones) deletedAt(key MVCCKey, readTS hlc.Timestamp) (hlc.Timestamp, bool) {
+	var deletedAt hlc.Timestamp
+	for _, t := range ts {
+		if t.Timestamp.LessEq(readTS) && t.Deletes(key) {
+			deletedAt.Forward(t.Timestamp)
+		}
+	}
+	return deletedAt, !deletedAt.IsEmpty()
+}
+
+// deletedAbove returns the timestamp of the oldest tombstone above the read
+// timestamp that deletes the given version, if any.
+func (ts mvccRangeTombstones) deletedAbove(
+	key MVCCKey, readTS hlc.Timestamp,
+) (hlc.Timestamp, bool) {
+	var deletedAbove hlc.Timestamp
+	for _, t := range ts {
+		if readTS.Less(t.Timestamp) && t.Deletes(key) {
+			if deletedAbove.IsEmpty() || t.Timestamp.Less(deletedAbove) {
+				deletedAbove = t.Timestamp
+			}
+		}
+	}
+	return deletedAbove, !deletedAbove.IsEmpty()
+}
+
+// timestampsCovering appends the timestamps of the tombstones covering the
+// given key to buf, ordered from newest to oldest and without duplicates.
+func (ts mvccRangeTombstones) timestampsCovering(
+	buf []hlc.Timestamp, key roachpb.Key,
+) []hlc.Timestamp {
+	buf = buf[:0]
+	for _, t := range ts {
+		if t.ContainsKey(key) {
+			buf = append(buf, t.Timestamp)
+		}
+	}
+	sort.Slice(buf, func(i, j int) bool { return buf[j].Less(buf[i]) })
+	n := 0
+	for i := range buf {
+		if i == 0 || buf[i] != buf[n-1] {
+			buf[n] = buf[i]
+			n++
+		}
+	}
+	return buf[:n]
+}
+
+// MVCCFindRangeTombstones returns the range tombstones that overlap the span
+// [startKey, endKey) of global keys and whose timestamp lies in the interval
+// (minTS, maxTS]. The tombstones are ordered by start key.
+//
+// Range tombstones are stored under the range-local key of their start key,
+// so tombstones starting inside of the span are found by scanning the
+// corresponding range-local keys. Tombstones starting to the left of the span
+// are found by scanning backwards from there until the descriptor of the range
+// containing startKey is reached; range splits guarantee that every range
+// holds a copy of the tombstones that extend into it (see splitTrigger), so
+// the backwards scan is skipped if startKey is the start key of a range.
+func MVCCFindRangeTombstones(
+	reader Reader, startKey, endKey roachpb.Key, minTS, maxTS hlc.Timestamp,
+) ([]MVCCRangeTombstone, error) {
+	if keys.IsLocal(startKey) || startKey.Compare(endKey) >= 0 {
+		return nil, nil
+	}
+	iter := reader.NewIterator(IterOptions{
+		LowerBound:           keys.LocalRangePrefix,
+		UpperBound:           keys.MakeRangeKeyPrefix(roachpb.RKey(endKey)),
+		RangeTombstoneLookup: true,
+	})
+	defer iter.Close()
+
+	var tombstones []MVCCRangeTombstone
+	// maybeAdd adds the tombstone the iterator is positioned at, if any and if
+	// it overlaps the span and time interval. It returns whether the iterator
+	// is positioned at a range descriptor.
+	maybeAdd := func() (bool, error) {
+		unsafeKey := iter.UnsafeKey()
+		tombstoneStart, suffix, _, err := keys.DecodeRangeKey(unsafeKey.Key)
+		if err != nil {
+			return false, err
+		}
+		if bytes.Equal(suffix, keys.LocalRangeDescriptorSuffix) {
+			return true, nil
+		}
+		if !bytes.Equal(suffix, keys.LocalMVCCRangeTombstoneSuffix) || !unsafeKey.IsValue() ||
+			unsafeKey.Timestamp.LessEq(minTS) || maxTS.Less(unsafeKey.Timestamp) {
+			return false, nil
+		}
+		value := roachpb.Value{RawBytes: iter.UnsafeValue()}
+		tombstoneEnd, err := value.GetBytes()
+		if err != nil {
+			return false, errors.Wrapf(err, "decoding range tombstone at %s", unsafeKey)
+		}
+		if startKey.Compare(tombstoneEnd) < 0 {
+			tombstones = append(tombstones, MVCCRangeTombstone{
+				StartKey:  tombstoneStart,
+				EndKey:    append(roachpb.Key(nil), tombstoneEnd...),
+				Timestamp: unsafeKey.Timestamp,
+			})
+		}
+		return false, nil
+	}
+
+	// Look for tombstones starting to the left of the span, unless the span
+	// starts at the beginning of a range.
+	descKey := keys.RangeDescriptorKey(roachpb.RKey(startKey))
+	iter.SeekGE(MakeMVCCMetadataKey(descKey))
+	ok, err := iter.Valid()
+	if err != nil {
+		return nil, err
+	}
+	if !ok || !iter.UnsafeKey().Key.Equal(descKey) {
+		for iter.SeekLT(MakeMVCCMetadataKey(keys.MakeRangeKeyPrefix(roachpb.RKey(startKey)))); ; iter.Prev() {
+			if ok, err := iter.Valid(); err != nil {
+				return nil, err
+			} else if !ok {
+				break
+			}
+			if isDesc, err := maybeAdd(); err != nil {
+				return nil, err
+			} else if isDesc {
+				break
+			}
+		}
+	}
+	// The backwards scan found the tombstones in reverse order.
+	for i, j := 0, len(tombstones)-1; i < j; i, j = i+1, j-1 {
+		tombstones[i], tombstones[j] = tombstones[j], tombstones[i]
+	}
+
+	// Look for tombstones starting inside of the span.
+	for iter.SeekGE(MakeMVCCMetadataKey(keys.MakeRangeKeyPrefix(roachpb.RKey(startKey)))); ; iter.Next() {
+		if ok, err := iter.Valid(); err != nil {
+			return nil, err
+		} else if !ok {
+			break
+		}
+		if _, err := maybeAdd(); err != nil {
+			return nil, err
+		}
+	}
+	return tombstones, nil
+}
+
+// MVCCHasRangeTombstones returns whether the range with the given ID may
+// contain MVCC range tombstones. The first range tombstone written to a range
+// marks the range through the key keys.RangeMVCCRangeTombstonesKey, which
+// splits and merges carry over to the resulting ranges. The marker is never
+// removed, but it allows the reads of all other ranges to skip looking up
+// range tombstones.
+func MVCCHasRangeTombstones(reader Reader, rangeID roachpb.RangeID) (bool, error) {
+	// The marker is read without being declared by the reading request, like
+	// the range tombstones themselves (see IterOptions.RangeTombstoneLookup).
+	iter := reader.NewIterator(IterOptions{Prefix: true, RangeTombstoneLookup: true})
+	defer iter.Close()
+	key := MakeMVCCMetadataKey(keys.RangeMVCCRangeTombstonesKey(rangeID))
+	iter.SeekGE(key)
+	if ok, err := iter.Valid(); err != nil || !ok {
+		return false, err
+	}
+	return iter.UnsafeKey().Key.Equal(key.Key), nil
+}
+
+// MVCCMarkRangeTombstones records that the range with the given ID may contain
+// MVCC range tombstones. See MVCCHasRangeTombstones.
+func MVCCMarkRangeTombstones(
+	ctx context.Context, rw ReadWriter, ms *enginepb.MVCCStats, rangeID roachpb.RangeID,
+) error {
+	if ok, err := MVCCHasRangeTombstones(rw, rangeID); err != nil || ok {
+		return err
+	}
+	var value roachpb.Value
+	value.SetBool(true)
+	return MVCCPut(ctx, rw, ms, keys.RangeMVCCRangeTombstonesKey(rangeID), hlc.Timestamp{},
+		value, nil /* txn */)
+}
+
+// updateStatsOnRangeTombstone returns the stats delta for the deletion of the
+// live latest version of a key, whose value has the given size, by a range
+// tombstone at the given time. The version stays in place, but it is no
+// longer live and accrues GCBytesAge from then on, just like a version
+// shadowed by a deletion tombstone.
+func updateStatsOnRangeTombstone(key roachpb.Key, valSize int64, nonLiveNanos int64) enginepb.MVCCStats {
+	var ms enginepb.MVCCStats
+	ms.AgeTo(nonLiveNanos)
+	ms.LiveBytes -= int64(MakeMVCCMetadataKey(key).EncodedSize()) + MVCCVersionTimestampSize + valSize
+	ms.LiveCount--
+	return ms
+}
+
+// ComputeRangeTombstoneStatsAdjustment returns the adjustment to add to the
+// stats that ComputeStats computes for the span [startKey, endKey) of global
+// keys of the range with the given ID. ComputeStats doesn't know about MVCC
+// range tombstones and counts the latest version of a key as live even if a
+// range tombstone deleted it; the adjustment accounts for these versions as
+// non-live since the oldest range tombstone which deleted them, which is how
+// MVCCDeleteRangeUsingTombstone updates the stats.
+func ComputeRangeTombstoneStatsAdjustment(
+	reader Reader, rangeID roachpb.RangeID, startKey, endKey roachpb.Key, nowNanos int64,
+) (enginepb.MVCCStats, error) {
+	ms := enginepb.MVCCStats{LastUpdateNanos: nowNanos}
+	if ok, err := MVCCHasRangeTombstones(reader, rangeID); err != nil || !ok {
+		return ms, err
+	}
+	tombstones, err := MVCCFindRangeTombstones(reader, startKey, endKey, hlc.Timestamp{}, hlc.MaxTimestamp)
+	if err != nil || len(tombstones) == 0 {
+		return ms, err
+	}
+	iter := reader.NewIterator(IterOptions{LowerBound: startKey, UpperBound: endKey})
+	defer iter.Close()
+	for iter.SeekGE(MakeMVCCMetadataKey(startKey)); ; iter.NextKey() {
+		if ok, err := iter.Valid(); err != nil {
+			return enginepb.MVCCStats{}, err
+		} else if !ok {
+			break
+		}
+		// Skip deleted keys and keys with explicit metadata, i.e. intents and
+		// inline values, which range tombstones don't delete.
+		unsafeKey := iter.UnsafeKey()
+		if !unsafeKey.IsValue() || len(iter.UnsafeValue()) == 0 {
+			continue
+		}
+		if deletedAt, ok := mvccRangeTombstones(tombstones).deletedAbove(
+			unsafeKey, unsafeKey.Timestamp); ok {
+			ms.Add(updateStatsOnRangeTombstone(unsafeKey.Key, int64(len(iter.UnsafeValue())), deletedAt.WallTime))
+		}
+	}
+	ms.AgeTo(nowNanos)
+	return ms, nil
+}
+
+// MVCCDeleteRangeUsingTombstone deletes all keys in the span [startKey,
+// endKey) at the given timestamp by writing a single range tombstone. Unlike
+// MVCCDeleteRange, the number of writes doesn't depend on the number of keys
+// in the span, although the span is still scanned to check for conflicts.
+//
+// The deletion cannot be transactional. It fails with a WriteIntentError if
+// the span contains intents and with a WriteTooOldError if it contains
+// versions at or above the timestamp. Since range tombstones are not
+// consulted by writes, the caller must prevent later writes below the
+// timestamp; the DeleteRange command does so through the timestamp cache.
+func MVCCDeleteRangeUsingTombstone(
+	ctx context.Context,
+	rw ReadWriter,
+	ms *enginepb.MVCCStats,
+	rangeID roachpb.RangeID,
+	startKey, endKey roachpb.Key,
+	timestamp hlc.Timestamp,
+) error {
+	if len(startKey) == 0 || len(endKey) == 0 {
+		return emptyKeyError()
+	}
+	if keys.IsLocal(startKey) {
+		return errors.Errorf("cannot write range tombstone over local span %s", roachpb.Span{Key: startKey, EndKey: endKey})
+	}
+	if startKey.Compare(endKey) >= 0 {
+		return errors.Errorf("invalid range tombstone span %s", roachpb.Span{Key: startKey, EndKey: endKey})
+	}
+	if timestamp.IsEmpty() {
+		return errors.Errorf("cannot write range tombstone over %s without a timestamp",
+			roachpb.Span{Key: startKey, EndKey: endKey})
+	}
+
+	// Versions which are already deleted by an older range tombstone are
+	// accounted for as non-live in the stats and have already been reported to
+	// rangefeeds as deleted.
+	var existing mvccRangeTombstones
+	if ok, err := MVCCHasRangeTombstones(rw, rangeID); err != nil {
+		return err
+	} else if ok {
+		if existing, err = MVCCFindRangeTombstones(
+			rw, startKey, endKey, hlc.Timestamp{}, timestamp); err != nil {
+			return err
+		}
+	}
+
+	iter := rw.NewIterator(IterOptions{LowerBound: startKey, UpperBound: endKey})
+	defer iter.Close()
+
+	var meta enginepb.MVCCMetadata
+	var intents []roachpb.Intent
+	var deltaMS enginepb.MVCCStats
+	for iter.SeekGE(MakeMVCCMetadataKey(startKey)); ; iter.NextKey() {
+		if ok, err := iter.Valid(); err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+		unsafeKey := iter.UnsafeKey()
+		if !unsafeKey.IsValue() {
+			if err := protoutil.Unmarshal(iter.UnsafeValue(), &meta); err != nil {
+				return err
+			}
+			if meta.IsInline() {
+				return errors.Errorf("cannot delete inline value %q with a range tombstone", unsafeKey.Key)
+			}
+			if meta.Txn != nil {
+				intents = append(intents, roachpb.MakeIntent(meta.Txn, iter.Key().Key))
+			}
+			continue
+		}
+		if timestamp.LessEq(unsafeKey.Timestamp) {
+			return roachpb.NewWriteTooOldError(timestamp, unsafeKey.Timestamp.Next())
+		}
+		if len(iter.UnsafeValue()) == 0 {
+			continue
+		}
+		if _, ok := existing.deletedAt(unsafeKey, timestamp); ok {
+			continue
+		}
+		// The tombstone deletes a live value. Rangefeeds observe this as a
+		// deletion of the key at the tombstone's timestamp.
+		rw.LogLogicalOp(MVCCWriteValueOpType, MVCCLogicalOpDetails{
+			Key:       unsafeKey.Key,
+			Timestamp: timestamp,
+		})
+		deltaMS.Add(updateStatsOnRangeTombstone(
+			unsafeKey.Key, int64(len(iter.UnsafeValue())), timestamp.WallTime))
+	}
+	if len(intents) > 0 {
+		return &roachpb.WriteIntentError{Intents: intents}
+	}
+
+	if ms != nil {
+		ms.Add(deltaMS)
+	}
+	if err := MVCCMarkRangeTombstones(ctx, rw, ms, rangeID); err != nil {
+		return err
+	}
+	return MVCCPut(ctx, rw, ms, keys.MVCCRangeTombstoneKey(roachpb.RKey(startKey)), timestamp,
+		roachpb.MakeValueFromBytes(endKey), nil /* txn */)
+}
+
+// MVCCSplitRangeTombstones is called when the range starting at startKey is
+// split at splitKey. It copies the range tombstones which start to the left
+// of splitKey and cover it to the range-local key of splitKey, so that the
+// right-hand side of the split holds all range tombstones that apply to its
+// keys. Tombstones with the same timestamp are merged. The left-hand side
+// keeps its tombstones unchanged.
+func MVCCSplitRangeTombstones(
+	ctx context.Context, rw ReadWriter, ms *enginepb.MVCCStats, startKey, splitKey roachpb.Key,
+) error {
+	// The descriptor of the right-hand side may already exist at this point,
+	// so look up the tombstones from the start of the left-hand side.
+	tombstones, err := MVCCFindRangeTombstones(
+		rw, startKey, splitKey.Next(), hlc.Timestamp{}, hlc.MaxTimestamp)
+	if err != nil {
+		return err
+	}
+	// Compute the end key to write at each timestamp, including the
+	// tombstones which already start at splitKey.
+	ends := make(map[hlc.Timestamp]roachpb.Key)
+	needsCopy := make(map[hlc.Timestamp]bool)
+	for _, t := range tombstones {
+		if !t.ContainsKey(splitKey) {
+			continue
+		}
+		if end, ok := ends[t.Timestamp]; !ok || end.Compare(t.EndKey) < 0 {
+			ends[t.Timestamp] = t.EndKey
+			needsCopy[t.Timestamp] = t.StartKey.Compare(splitKey) < 0
+		}
+	}
+
+	key := keys.MVCCRangeTombstoneKey(roachpb.RKey(splitKey))
+	var haveKey bool
+	oldValSizes := make(map[hlc.Timestamp]int64)
+	iter := rw.NewIterator(IterOptions{Prefix: true, RangeTombstoneLookup: true})
+	defer iter.Close()
+	for iter.SeekGE(MakeMVCCMetadataKey(key)); ; iter.Next() {
+		if ok, err := iter.Valid(); err != nil {
+			return err
+		} else if !ok || !iter.UnsafeKey().Key.Equal(key) {
+			break
+		}
+		haveKey = true
+		oldValSizes[iter.UnsafeKey().Timestamp] = int64(len(iter.UnsafeValue()))
+	}
+
+	// The versions are written directly rather than through MVCCPut, which
+	// would refuse to write below or at the timestamp of an existing version.
+	// The key is a system key, so the stats are easy to maintain by hand.
+	for ts, end := range ends {
+		if !needsCopy[ts] {
+			continue
+		}
+		value := roachpb.MakeValueFromBytes(end)
+		if err := rw.Put(MVCCKey{Key: key, Timestamp: ts}, value.RawBytes); err != nil {
+			return err
+		}
+		if ms != nil {
+			if !haveKey {
+				ms.SysBytes += int64(len(key)) + 1
+				ms.SysCount++
+				haveKey = true
+			}
+			if oldValSize, ok := oldValSizes[ts]; ok {
+				ms.SysBytes -= oldValSize + MVCCVersionTimestampSize
+			}
+			ms.SysBytes += int64(len(value.RawBytes)) + MVCCVersionTimestampSize
+		}
+	}
+	return nil
+}
+
+// findRangeTombstonesForRead returns the range tombstones which can affect a
+// read of the given span at the given timestamp. Besides the tombstones at or
+// below the read timestamp, these include the tombstones in the read's
+// uncertainty interval and, when failing on more recent writes, all newer
+// tombstones. No tombstones are looked up if the reader has indicated that
+// the range doesn't contain any.
+func findRangeTombstonesForRead(
+	reader Reader,
+	startKey, endKey roachpb.Key,
+	timestamp hlc.Timestamp,
+	txn *roachpb.Transaction,
+	failOnMoreRecent bool,
+	noRangeTombstones bool,
+) (mvccRangeTombstones, error) {
+	if noRangeTombstones || keys.IsLocal(startKey) {
+		return nil, nil
+	}
+	maxTS := timestamp
+	if failOnMoreRecent {
+		maxTS = hlc.MaxTimestamp
+	} else if txn != nil {
+		maxTS.Forward(txn.MaxTimestamp)
+	}
+	return MVCCFindRangeTombstones(reader, startKey, endKey, hlc.Timestamp{}, maxTS)
+}
+
+// checkRead applies the range tombstones to a version returned by a read at
+// the given timestamp. It returns the timestamp at which the version was
+// deleted, if it was deleted at or below the read timestamp. A tombstone above
+// the read timestamp results in a WriteTooOldError when failing on more recent
+// writes and in a ReadWithinUncertaintyIntervalError if it falls into the
+// uncertainty interval of the transaction.
+func (ts mvccRangeTombstones) checkRead(
+	key MVCCKey, timestamp hlc.Timestamp, txn *roachpb.Transaction, failOnMoreRecent bool,
+) (hlc.Timestamp, bool, error) {
+	if above, ok := ts.deletedAbove(key, timestamp); ok {
+		if failOnMoreRecent {
+			return hlc.Timestamp{}, false, roachpb.NewWriteTooOldError(timestamp, above.Next())
+		}
+		if txn != nil && above.LessEq(txn.MaxTimestamp) {
+			return hlc.Timestamp{}, false, roachpb.NewReadWithinUncertaintyIntervalError(timestamp, above, txn)
+		}
+	}
+	deletedAt, ok := ts.deletedAt(key, timestamp)
+	return deletedAt, ok, nil
+}
+
+// applyRangeTombstonesToGet applies the range tombstones covering the key to
+// the result of an MVCCGet.
+func applyRangeTombstonesToGet(
+	reader Reader, key roachpb.Key, timestamp hlc.Timestamp, opts MVCCGetOptions, value *roachpb.Value,
+) (*roachpb.Value, error) {
+	if value == nil || !value.IsPresent() {
+		return value, nil
+	}
+	tombstones, err := findRangeTombstonesForRead(
+		reader, key, key.Next(), timestamp, opts.Txn, opts.FailOnMoreRecent, opts.NoRangeTombstones)
+	if err != nil || len(tombstones) == 0 {
+		return value, err
+	}
+	deletedAt, deleted, err := tombstones.checkRead(
+		MVCCKey{Key: key, Timestamp: value.Timestamp}, timestamp, opts.Txn, opts.FailOnMoreRecent)
+	if err != nil || !deleted {
+		return value, err
+	}
+	if opts.Tombstones {
+		return &roachpb.Value{Timestamp: deletedAt}, nil
+	}
+	return nil, nil
+}
+
+// applyRangeTombstonesToScan applies the range tombstones to the result of an
+// MVCCScan, removing deleted keys from its KVData or, in tombstones mode,
+// replacing them by deletion tombstones.
+func applyRangeTombstonesToScan(
+	tombstones mvccRangeTombstones,
+	res *MVCCScanResult,
+	timestamp hlc.Timestamp,
+	opts MVCCScanOptions,
+) error {
+	if len(tombstones) == 0 || res.NumKeys == 0 {
+		return nil
+	}
+	var results pebbleResults
+	for _, data := range res.KVData {
+		for len(data) > 0 {
+			key, rawBytes, rest, err := MVCCScanDecodeKeyValue(data)
+			if err != nil {
+				return err
+			}
+			data = rest
+			if len(rawBytes) > 0 {
+				deletedAt, deleted, err := tombstones.checkRead(key, timestamp, opts.Txn, opts.FailOnMoreRecent)
+				if err != nil {
+					return err
+				}
+				if deleted {
+					if !opts.Tombstones {
+						continue
+					}
+					key.Timestamp, rawBytes = deletedAt, nil
+				}
+			}
+			results.put(key, rawBytes)
+		}
+	}
+	res.KVData = results.finish()
+	res.NumKeys = results.count
+	res.NumBytes = results.bytes
+	return nil
+}
+
+// mvccScanWithRangeTombstones is like mvccScanToBytes, but applies the given
+// range tombstones to the result. The versions deleted by the tombstones are
+// only removed from the result after they have counted against max and
+// opts.TargetBytes, so the scan is resumed until the visible keys reach one
+// of these limits or the span is exhausted. Callers like DistSender rely on a
+// scan only returning a resume span once it has reached its limits.
+func mvccScanWithRangeTombstones(
+	ctx context.Context,
+	iter Iterator,
+	tombstones mvccRangeTombstones,
+	key, endKey roachpb.Key,
+	max int64,
+	timestamp hlc.Timestamp,
+	opts MVCCScanOptions,
+) (MVCCScanResult, error) {
+	res, err := mvccScanToBytes(ctx, iter, key, endKey, max, timestamp, opts)
+	if err != nil || len(tombstones) == 0 {
+		return res, err
+	}
+	if err := applyRangeTombstonesToScan(tombstones, &res, timestamp, opts); err != nil {
+		return MVCCScanResult{}, err
+	}
+	for res.ResumeSpan != nil && res.NumKeys < max &&
+		(opts.TargetBytes == 0 || res.NumBytes < opts.TargetBytes) {
+		resumeOpts := opts
+		if opts.TargetBytes > 0 {
+			resumeOpts.TargetBytes = opts.TargetBytes - res.NumBytes
+		}
+		// The resume span may point into the iterator's memory, which the next
+		// scan reuses.
+		resumeKey := append(roachpb.Key(nil), res.ResumeSpan.Key...)
+		resumeEndKey := append(roachpb.Key(nil), res.ResumeSpan.EndKey...)
+		more, err := mvccScanToBytes(
+			ctx, iter, resumeKey, resumeEndKey, max-res.NumKeys, timestamp, resumeOpts)
+		if err != nil {
+			return MVCCScanResult{}, err
+		}
+		if err := applyRangeTombstonesToScan(tombstones, &more, timestamp, opts); err != nil {
+			return MVCCScanResult{}, err
+		}
+		res.KVData = append(res.KVData, more.KVData...)
+		res.NumKeys += more.NumKeys
+		res.NumBytes += more.NumBytes
+		res.Intents = append(res.Intents, more.Intents...)
+		res.ResumeSpan = more.ResumeSpan
+	}
+	return res, nil
+}
+
+// mvccRangeTombstoneIterator wraps an iterator and interleaves the deletions
+// implied by a set of range tombstones with the versions of the underlying
+// iterator. A deletion, i.e. a version with an empty value, is emitted at the
+// timestamp of every range tombstone which covers a key and is immediately
+// above one of its versions. Only forward iteration is supported.
+type mvccRangeTombstoneIterator struct {
+	iter       SimpleIterator
+	tombstones mvccRangeTombstones
+
+	// curKey is the key the underlying iterator is positioned at, and
+	// timestamps holds the timestamps of the tombstones covering it. The
+	// timestamps before idx have already been emitted or skipped.
+	curKey     roachpb.Key
+	haveKey    bool
+	timestamps []hlc.Timestamp
+	idx        int
+	// deletion is true if the iterator is positioned at the deletion implied
+	// by timestamps[idx], which precedes the current version of the
+	// underlying iterator.
+	deletion bool
+}
+
+var _ SimpleIterator = &mvccRangeTombstoneIterator{}
+
+// NewMVCCRangeTombstoneIterator returns an iterator that emits the deletions
+// implied by the given range tombstones in addition to the versions of the
+// given iterator. The returned iterator takes ownership of iter.
+func NewMVCCRangeTombstoneIterator(
+	iter SimpleIterator, tombstones []MVCCRangeTombstone,
+) SimpleIterator {
+	if len(tombstones) == 0 {
+		return iter
+	}
+	return &mvccRangeTombstoneIterator{iter: iter, tombstones: tombstones}
+}
+
+// Close is part of the SimpleIterator interface.
+func (i *mvccRangeTombstoneIterator) Close() {
+	i.iter.Close()
+}
+
+// SeekGE is part of the SimpleIterator interface.
+func (i *mvccRangeTombstoneIterator) SeekGE(key MVCCKey) {
+	i.iter.SeekGE(key)
+	i.haveKey = false
+	i.settle(key)
+}
+
+// Valid is part of the SimpleIterator interface.
+func (i *mvccRangeTombstoneIterator) Valid() (bool, error) {
+	return i.iter.Valid()
+}
+
+// Next is part of the SimpleIterator interface.
+func (i *mvccRangeTombstoneIterator) Next() {
+	if i.deletion {
+		i.idx++
+	} else {
+		i.iter.Next()
+	}
+	i.settle(MVCCKey{})
+}
+
+// NextKey is part of the SimpleIterator interface.
+func (i *mvccRangeTombstoneIterator) NextKey() {
+	i.iter.NextKey()
+	i.settle(MVCCKey{})
+}
+
+// UnsafeKey is part of the SimpleIterator interface.
+func (i *mvccRangeTombstoneIterator) UnsafeKey() MVCCKey {
+	if i.deletion {
+		return MVCCKey{Key: i.curKey, Timestamp: i.timestamps[i.idx]}
+	}
+	return i.iter.UnsafeKey()
+}
+
+// UnsafeValue is part of the SimpleIterator interface.
+func (i *mvccRangeTombstoneIterator) UnsafeValue() []byte {
+	if i.deletion {
+		return nil
+	}
+	return i.iter.UnsafeValue()
+}
+
+// settle determines whether a deletion needs to be emitted before the current
+// version of the underlying iterator. If the iterator was just positioned by
+// a seek, seekKey is the key it was seeked to.
+func (i *mvccRangeTombstoneIterator) settle(seekKey MVCCKey) {
+	i.deletion = false
+	if ok, _ := i.iter.Valid(); !ok {
+		return
+	}
+	unsafeKey := i.iter.UnsafeKey()
+	if !i.haveKey || !unsafeKey.Key.Equal(i.curKey) {
+		i.curKey = append(i.curKey[:0], unsafeKey.Key...)
+		i.haveKey = true
+		i.timestamps = i.tombstones.timestampsCovering(i.timestamps, i.curKey)
+		i.idx = 0
+		if !seekKey.Timestamp.IsEmpty() && seekKey.Key.Equal(i.curKey) {
+			// Deletions above the timestamp of the seek key sort before it.
+			for i.idx < len(i.timestamps) && seekKey.Timestamp.Less(i.timestamps[i.idx]) {
+				i.idx++
+			}
+		}
+	}
+	if !unsafeKey.IsValue() {
+		return
+	}
+	for ; i.idx < len(i.timestamps); i.idx++ {
+		ts := i.timestamps[i.idx]
+		if unsafeKey.Timestamp.Less(ts) {
+			i.deletion = true
+			return
+		}
+		if ts.Less(unsafeKey.Timestamp) {
+			return
+		}
+		// A version at the timestamp of the tombstone isn't deleted by it, and
+		// the tombstone doesn't delete the versions below either since they are
+		// shadowed by the version.
+	}
+}
@@ -0,0 +1,461 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package engine
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+// testRangeID is the ID of the range the range tombstones are written to.
+const testRangeID = roachpb.RangeID(1)
+
+func TestMVCCFindRangeTombstones(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	for _, engineImpl := range mvccEngineImpls {
+		t.Run(engineImpl.name, func(t *testing.T) {
+			engine := engineImpl.create()
+			defer engine.Close()
+
+			ts := func(wallTime int64) hlc.Timestamp { return hlc.Timestamp{WallTime: wallTime} }
+			for _, tomb := range []MVCCRangeTombstone{
+				{StartKey: roachpb.Key("b"), EndKey: roachpb.Key("d"), Timestamp: ts(2)},
+				{StartKey: roachpb.Key("c"), EndKey: roachpb.Key("g"), Timestamp: ts(4)},
+				{StartKey: roachpb.Key("h"), EndKey: roachpb.Key("j"), Timestamp: ts(3)},
+			} {
+				if err := MVCCDeleteRangeUsingTombstone(
+					ctx, engine, nil, testRangeID, tomb.StartKey, tomb.EndKey, tomb.Timestamp,
+				); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			testCases := []struct {
+				start, end   string
+				minTS, maxTS hlc.Timestamp
+				expected     []string
+			}{
+				{"a", "z", ts(0), ts(10), []string{"{b-d}@0.000000002,0", "{c-g}@0.000000004,0", "{h-j}@0.000000003,0"}},
+				{"a", "b", ts(0), ts(10), nil},
+				{"d", "e", ts(0), ts(10), []string{"{c-g}@0.000000004,0"}},
+				{"cc", "h", ts(0), ts(10), []string{"{b-d}@0.000000002,0", "{c-g}@0.000000004,0"}},
+				{"a", "z", ts(2), ts(3), []string{"{h-j}@0.000000003,0"}},
+				{"a", "z", ts(0), ts(2), []string{"{b-d}@0.000000002,0"}},
+				{"j", "z", ts(0), ts(10), nil},
+			}
+			for _, tc := range testCases {
+				tombstones, err := MVCCFindRangeTombstones(
+					engine, roachpb.Key(tc.start), roachpb.Key(tc.end), tc.minTS, tc.maxTS)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var actual []string
+				for _, tomb := range tombstones {
+					actual = append(actual, tomb.String())
+				}
+				if !reflect.DeepEqual(tc.expected, actual) {
+					t.Errorf("[%s,%s) in (%s,%s]: expected %s, got %s",
+						tc.start, tc.end, tc.minTS, tc.maxTS, tc.expected, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestMVCCRangeTombstoneReads(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	for _, engineImpl := range mvccEngineImpls {
+		t.Run(engineImpl.name, func(t *testing.T) {
+			engine := engineImpl.create()
+			defer engine.Close()
+
+			ts1 := hlc.Timestamp{WallTime: 1}
+			ts2 := hlc.Timestamp{WallTime: 2}
+			ts3 := hlc.Timestamp{WallTime: 3}
+			for _, key := range []roachpb.Key{testKey1, testKey2, testKey3} {
+				if err := MVCCPut(ctx, engine, nil, key, ts1, value1, nil); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := MVCCDeleteRangeUsingTombstone(ctx, engine, nil, testRangeID, testKey1, testKey3, ts2); err != nil {
+				t.Fatal(err)
+			}
+			// A write above the tombstone is visible again.
+			if err := MVCCPut(ctx, engine, nil, testKey2, ts3, value2, nil); err != nil {
+				t.Fatal(err)
+			}
+
+			// The key below the tombstone is visible before, but not at or after
+			// the tombstone's timestamp.
+			if val, _, err := MVCCGet(ctx, engine, testKey1, ts1, MVCCGetOptions{}); err != nil {
+				t.Fatal(err)
+			} else if val == nil {
+				t.Fatal("expected value below range tombstone")
+			}
+			if val, _, err := MVCCGet(ctx, engine, testKey1, ts2, MVCCGetOptions{}); err != nil {
+				t.Fatal(err)
+			} else if val != nil {
+				t.Fatalf("expected deleted key, got %v", val)
+			}
+			if val, _, err := MVCCGet(
+				ctx, engine, testKey1, ts3, MVCCGetOptions{Tombstones: true},
+			); err != nil {
+				t.Fatal(err)
+			} else if val == nil || val.IsPresent() || val.Timestamp != ts2 {
+				t.Fatalf("expected deletion at %s, got %v", ts2, val)
+			}
+			// Reading below the tombstone fails when requested to fail on more
+			// recent writes.
+			if _, _, err := MVCCGet(
+				ctx, engine, testKey1, ts1, MVCCGetOptions{FailOnMoreRecent: true},
+			); !testutils.IsError(err, "WriteTooOldError") {
+				t.Fatalf("expected WriteTooOldError, got %v", err)
+			}
+
+			res, err := MVCCScan(ctx, engine, testKey1, keyMax, 10, ts3, MVCCScanOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := []roachpb.KeyValue{
+				{Key: testKey2, Value: value2},
+				{Key: testKey3, Value: value1},
+			}
+			if len(res.KVs) != len(expected) {
+				t.Fatalf("expected %d keys, got %v", len(expected), res.KVs)
+			}
+			for i, kv := range res.KVs {
+				if !kv.Key.Equal(expected[i].Key) ||
+					!kv.Value.EqualData(expected[i].Value) {
+					t.Errorf("%d: expected %s=%s, got %s=%s", i,
+						expected[i].Key, expected[i].Value.PrettyPrint(), kv.Key, kv.Value.PrettyPrint())
+				}
+			}
+
+			res, err = MVCCScan(ctx, engine, testKey1, keyMax, 10, ts1, MVCCScanOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(res.KVs) != 3 {
+				t.Fatalf("expected 3 keys below the range tombstone, got %v", res.KVs)
+			}
+
+			// The deleted keys don't count against the limit of a scan, so a
+			// limited scan only returns a resume span once it has found as many
+			// visible keys as it was allowed to.
+			res, err = MVCCScan(ctx, engine, testKey1, keyMax, 1, ts3, MVCCScanOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(res.KVs) != 1 || !res.KVs[0].Key.Equal(testKey2) {
+				t.Fatalf("expected %s, got %v", testKey2, res.KVs)
+			}
+			if res.NumKeys != 1 || res.ResumeSpan == nil || !res.ResumeSpan.Key.Equal(testKey3) {
+				t.Fatalf("expected 1 key and a resume span at %s, got %d keys and %v",
+					testKey3, res.NumKeys, res.ResumeSpan)
+			}
+			res, err = MVCCScan(ctx, engine, testKey1, testKey3, 1, ts3, MVCCScanOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(res.KVs) != 1 || res.ResumeSpan != nil {
+				t.Fatalf("expected 1 key and no resume span, got %v and %v", res.KVs, res.ResumeSpan)
+			}
+
+			// Reads that were told that the range has no range tombstones don't
+			// look them up.
+			if val, _, err := MVCCGet(
+				ctx, engine, testKey1, ts2, MVCCGetOptions{NoRangeTombstones: true},
+			); err != nil {
+				t.Fatal(err)
+			} else if val == nil {
+				t.Fatal("expected range tombstone to be ignored")
+			}
+		})
+	}
+}
+
+func TestMVCCHasRangeTombstones(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	for _, engineImpl := range mvccEngineImpls {
+		t.Run(engineImpl.name, func(t *testing.T) {
+			engine := engineImpl.create()
+			defer engine.Close()
+
+			check := func(rangeID roachpb.RangeID, expected bool) {
+				t.Helper()
+				if ok, err := MVCCHasRangeTombstones(engine, rangeID); err != nil {
+					t.Fatal(err)
+				} else if ok != expected {
+					t.Fatalf("r%d: expected %t, got %t", rangeID, expected, ok)
+				}
+			}
+			check(testRangeID, false)
+
+			ms := &enginepb.MVCCStats{}
+			for _, ts := range []hlc.Timestamp{{WallTime: 1}, {WallTime: 2}} {
+				if err := MVCCDeleteRangeUsingTombstone(
+					ctx, engine, ms, testRangeID, testKey1, testKey3, ts,
+				); err != nil {
+					t.Fatal(err)
+				}
+			}
+			check(testRangeID, true)
+			check(testRangeID+1, false)
+
+			// The marker is only written once.
+			kvs, err := Scan(engine, keys.MakeRangeIDPrefix(testRangeID),
+				keys.MakeRangeIDPrefix(testRangeID).PrefixEnd(), 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(kvs) != 1 || ms.SysCount != 2 {
+				t.Fatalf("expected a single marker key, got %v (SysCount=%d)", kvs, ms.SysCount)
+			}
+		})
+	}
+}
+
+func TestMVCCDeleteRangeUsingTombstoneConflicts(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	for _, engineImpl := range mvccEngineImpls {
+		t.Run(engineImpl.name, func(t *testing.T) {
+			engine := engineImpl.create()
+			defer engine.Close()
+
+			ts1 := hlc.Timestamp{WallTime: 1}
+			ts2 := hlc.Timestamp{WallTime: 2}
+			if err := MVCCPut(ctx, engine, nil, testKey2, ts2, value1, nil); err != nil {
+				t.Fatal(err)
+			}
+			if err := MVCCDeleteRangeUsingTombstone(
+				ctx, engine, nil, testRangeID, testKey1, testKey3, ts1,
+			); !testutils.IsError(err, "WriteTooOldError") {
+				t.Fatalf("expected WriteTooOldError, got %v", err)
+			}
+
+			txn := makeTxn(*txn1, ts2)
+			if err := MVCCPut(ctx, engine, nil, testKey3, txn.ReadTimestamp, value1, txn); err != nil {
+				t.Fatal(err)
+			}
+			if err := MVCCDeleteRangeUsingTombstone(
+				ctx, engine, nil, testRangeID, testKey1, keyMax, ts2.Next(),
+			); !testutils.IsError(err, "conflicting intents") {
+				t.Fatalf("expected WriteIntentError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestMVCCRangeTombstoneIterator(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	for _, engineImpl := range mvccEngineImpls {
+		t.Run(engineImpl.name, func(t *testing.T) {
+			engine := engineImpl.create()
+			defer engine.Close()
+
+			ts1 := hlc.Timestamp{WallTime: 1}
+			ts2 := hlc.Timestamp{WallTime: 2}
+			ts3 := hlc.Timestamp{WallTime: 3}
+			ts4 := hlc.Timestamp{WallTime: 4}
+			if err := MVCCPut(ctx, engine, nil, testKey1, ts1, value1, nil); err != nil {
+				t.Fatal(err)
+			}
+			if err := MVCCPut(ctx, engine, nil, testKey2, ts3, value2, nil); err != nil {
+				t.Fatal(err)
+			}
+			if err := MVCCDeleteRangeUsingTombstone(ctx, engine, nil, testRangeID, testKey1, keyMax, ts4); err != nil {
+				t.Fatal(err)
+			}
+			tombstones := []MVCCRangeTombstone{
+				{StartKey: testKey1, EndKey: keyMax, Timestamp: ts2},
+				{StartKey: testKey1, EndKey: keyMax, Timestamp: ts4},
+			}
+
+			iter := NewMVCCRangeTombstoneIterator(engine.NewIterator(IterOptions{
+				LowerBound: testKey1,
+				UpperBound: keyMax,
+			}), tombstones)
+			defer iter.Close()
+
+			// The deletion at ts2 is only emitted for testKey1, which has a version
+			// below it.
+			expected := []MVCCKey{
+				{Key: testKey1, Timestamp: ts4},
+				{Key: testKey1, Timestamp: ts2},
+				{Key: testKey1, Timestamp: ts1},
+				{Key: testKey2, Timestamp: ts4},
+				{Key: testKey2, Timestamp: ts3},
+			}
+			var actual []MVCCKey
+			for iter.SeekGE(MakeMVCCMetadataKey(testKey1)); ; iter.Next() {
+				if ok, err := iter.Valid(); err != nil {
+					t.Fatal(err)
+				} else if !ok {
+					break
+				}
+				key := iter.UnsafeKey()
+				isDeletion := len(iter.UnsafeValue()) == 0
+				if isDeletion != (key.Timestamp == ts2 || key.Timestamp == ts4) {
+					t.Errorf("%s: unexpected deletion status %t", key, isDeletion)
+				}
+				actual = append(actual, MVCCKey{
+					Key:       append(roachpb.Key(nil), key.Key...),
+					Timestamp: key.Timestamp,
+				})
+			}
+			if !reflect.DeepEqual(expected, actual) {
+				t.Fatalf("expected %s, got %s", expected, actual)
+			}
+		})
+	}
+}
+
+func TestMVCCIncrementalIteratorRangeTombstones(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	for _, engineImpl := range mvccEngineImpls {
+		t.Run(engineImpl.name, func(t *testing.T) {
+			engine := engineImpl.create()
+			defer engine.Close()
+
+			ts1 := hlc.Timestamp{WallTime: 1}
+			ts2 := hlc.Timestamp{WallTime: 2}
+			if err := MVCCPut(ctx, engine, nil, testKey1, ts1, value1, nil); err != nil {
+				t.Fatal(err)
+			}
+			if err := MVCCDeleteRangeUsingTombstone(ctx, engine, nil, testRangeID, testKey1, keyMax, ts2); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, tc := range []struct {
+				startTime hlc.Timestamp
+				expected  []MVCCKey
+			}{
+				{hlc.Timestamp{}, []MVCCKey{{Key: testKey1, Timestamp: ts2}, {Key: testKey1, Timestamp: ts1}}},
+				{ts1, []MVCCKey{{Key: testKey1, Timestamp: ts2}}},
+				{ts2, nil},
+			} {
+				iter := NewMVCCIncrementalIterator(engine, MVCCIncrementalIterOptions{
+					IterOptions: IterOptions{LowerBound: testKey1, UpperBound: keyMax},
+					StartTime:   tc.startTime,
+					EndTime:     ts2,
+				})
+				var actual []MVCCKey
+				for iter.SeekGE(MakeMVCCMetadataKey(testKey1)); ; iter.Next() {
+					if ok, err := iter.Valid(); err != nil {
+						t.Fatal(err)
+					} else if !ok {
+						break
+					}
+					actual = append(actual, iter.Key())
+				}
+				iter.Close()
+				if !reflect.DeepEqual(tc.expected, actual) {
+					t.Errorf("start time %s: expected %s, got %s", tc.startTime, tc.expected, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestMVCCGarbageCollectRangeTombstone(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	for _, engineImpl := range mvccEngineImpls {
+		t.Run(engineImpl.name, func(t *testing.T) {
+			engine := engineImpl.create()
+			defer engine.Close()
+
+			ms := &enginepb.MVCCStats{}
+			ts1 := hlc.Timestamp{WallTime: 1e9}
+			ts2 := hlc.Timestamp{WallTime: 2e9}
+			ts3 := hlc.Timestamp{WallTime: 3e9}
+			for _, key := range []roachpb.Key{testKey1, testKey2} {
+				if err := MVCCPut(ctx, engine, ms, key, ts1, value1, nil); err != nil {
+					t.Fatal(err)
+				}
+			}
+			checkStats := func(nowNanos int64) {
+				t.Helper()
+				iter := engine.NewIterator(IterOptions{UpperBound: roachpb.KeyMax})
+				expMS, err := iter.ComputeStats(roachpb.KeyMin, roachpb.KeyMax, nowNanos)
+				iter.Close()
+				if err != nil {
+					t.Fatal(err)
+				}
+				adjMS, err := ComputeRangeTombstoneStatsAdjustment(
+					engine, testRangeID, keys.LocalMax, roachpb.KeyMax, nowNanos)
+				if err != nil {
+					t.Fatal(err)
+				}
+				expMS.Add(adjMS)
+				actMS := *ms
+				actMS.AgeTo(nowNanos)
+				if !reflect.DeepEqual(expMS, actMS) {
+					t.Errorf("expected stats %+v, got %+v", expMS, actMS)
+				}
+			}
+
+			if err := MVCCDeleteRangeUsingTombstone(ctx, engine, ms, testRangeID, testKey1, testKey2, ts2); err != nil {
+				t.Fatal(err)
+			}
+			// The deleted version of testKey1 is no longer live.
+			if ms.LiveCount != 1 {
+				t.Fatalf("expected a live count of 1, got %d", ms.LiveCount)
+			}
+			checkStats(ts3.WallTime)
+
+			// The latest version of testKey2 is not deleted.
+			if err := MVCCGarbageCollect(ctx, engine, ms, []roachpb.GCRequest_GCKey{
+				{Key: testKey2, Timestamp: ts1},
+			}, ts3); !testutils.IsError(err, "request to GC non-deleted, latest value") {
+				t.Fatalf("expected error, got %v", err)
+			}
+
+			if err := MVCCGarbageCollect(ctx, engine, ms, []roachpb.GCRequest_GCKey{
+				{Key: testKey1, Timestamp: ts1},
+				{Key: keys.MVCCRangeTombstoneKey(roachpb.RKey(testKey1)), Timestamp: ts2},
+			}, ts3); err != nil {
+				t.Fatal(err)
+			}
+
+			// The range-ID local marker of the range tombstones is not removed.
+			kvs, err := Scan(engine, keys.LocalRangePrefix, roachpb.KeyMax, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(kvs) != 1 || !kvs[0].Key.Key.Equal(testKey2) {
+				t.Fatalf("expected only %s to remain, got %v", testKey2, kvs)
+			}
+			checkStats(ts3.WallTime)
+		})
+	}
+}
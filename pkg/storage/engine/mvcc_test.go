@@ -1787,6 +1787,116 @@ func TestMVCCScanInconsistent(t *testing.T) {
 	}
 }
 
+func TestMVCCScanSkipLocked(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	for _, engineImpl := range mvccEngineImpls {
+		t.Run(engineImpl.name, func(t *testing.T) {
+			engine := engineImpl.create()
+			defer engine.Close()
+
+			// A scan with skip locked should fail if it is inconsistent.
+			if _, err := MVCCScan(
+				ctx, engine, keyMin, keyMax, math.MaxInt64, hlc.Timestamp{WallTime: 1},
+				MVCCScanOptions{Inconsistent: true, SkipLocked: true},
+			); !testutils.IsError(err, "cannot allow inconsistent reads with skip locked option") {
+				t.Fatalf("expected an error scanning inconsistently with skip locked, found %v", err)
+			}
+
+			ts1 := hlc.Timestamp{WallTime: 1}
+			ts2 := hlc.Timestamp{WallTime: 2}
+			ts3 := hlc.Timestamp{WallTime: 3}
+			if err := MVCCPut(ctx, engine, nil, testKey1, ts1, value1, nil); err != nil {
+				t.Fatal(err)
+			}
+			txn1ts2 := makeTxn(*txn1, ts2)
+			if err := MVCCPut(ctx, engine, nil, testKey1, txn1ts2.ReadTimestamp, value2, txn1ts2); err != nil {
+				t.Fatal(err)
+			}
+			if err := MVCCPut(ctx, engine, nil, testKey2, ts1, value2, nil); err != nil {
+				t.Fatal(err)
+			}
+			txn2ts3 := makeTxn(*txn2, ts3)
+			if err := MVCCPut(ctx, engine, nil, testKey3, txn2ts3.ReadTimestamp, value3, txn2ts3); err != nil {
+				t.Fatal(err)
+			}
+			if err := MVCCPut(ctx, engine, nil, testKey4, ts1, value4, nil); err != nil {
+				t.Fatal(err)
+			}
+
+			makeTimestampedValue := func(v roachpb.Value, ts hlc.Timestamp) roachpb.Value {
+				v.Timestamp = ts
+				return v
+			}
+
+			testCases := []struct {
+				name   string
+				ts     hlc.Timestamp
+				txn    *roachpb.Transaction
+				expKVs []roachpb.KeyValue
+			}{
+				{
+					// Keys locked by other transactions are skipped.
+					name: "non-txn",
+					ts:   hlc.Timestamp{WallTime: 4},
+					expKVs: []roachpb.KeyValue{
+						{Key: testKey2, Value: makeTimestampedValue(value2, ts1)},
+						{Key: testKey4, Value: makeTimestampedValue(value4, ts1)},
+					},
+				},
+				{
+					// Keys locked by other transactions are skipped even if the
+					// scan reads below the timestamp of their intents.
+					name: "non-txn historical",
+					ts:   ts1,
+					expKVs: []roachpb.KeyValue{
+						{Key: testKey2, Value: makeTimestampedValue(value2, ts1)},
+						{Key: testKey4, Value: makeTimestampedValue(value4, ts1)},
+					},
+				},
+				{
+					// Keys locked by the scanning transaction are not skipped.
+					name: "txn",
+					ts:   ts2,
+					txn:  txn1ts2,
+					expKVs: []roachpb.KeyValue{
+						{Key: testKey1, Value: makeTimestampedValue(value2, ts2)},
+						{Key: testKey2, Value: makeTimestampedValue(value2, ts1)},
+						{Key: testKey4, Value: makeTimestampedValue(value4, ts1)},
+					},
+				},
+			}
+			for _, tc := range testCases {
+				t.Run(tc.name, func(t *testing.T) {
+					for _, reverse := range []bool{false, true} {
+						res, err := MVCCScan(
+							ctx, engine, testKey1, testKey4.Next(), math.MaxInt64, tc.ts,
+							MVCCScanOptions{Txn: tc.txn, SkipLocked: true, Reverse: reverse},
+						)
+						if err != nil {
+							t.Fatal(err)
+						}
+						if len(res.Intents) != 0 {
+							t.Fatalf("expected no intents, found %v", res.Intents)
+						}
+						expKVs := tc.expKVs
+						if reverse {
+							expKVs = make([]roachpb.KeyValue, len(tc.expKVs))
+							for i := range tc.expKVs {
+								expKVs[len(expKVs)-1-i] = tc.expKVs[i]
+							}
+						}
+						if !reflect.DeepEqual(res.KVs, expKVs) {
+							t.Errorf("reverse=%t: expected key values equal %v != %v", reverse, res.KVs, expKVs)
+						}
+					}
+				})
+			}
+		})
+	}
+}
+
 func TestMVCCDeleteRange(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
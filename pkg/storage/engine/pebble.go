@@ -1008,8 +1008,10 @@ func (p *pebbleReadOnly) NewIterator(opts IterOptions) Iterator {
 		panic("using a closed pebbleReadOnly")
 	}
 
-	if opts.MinTimestampHint != (hlc.Timestamp{}) {
-		// Iterators that specify timestamp bounds cannot be cached.
+	if opts.MinTimestampHint != (hlc.Timestamp{}) || opts.RangeTombstoneLookup {
+		// Iterators that specify timestamp bounds cannot be cached. Neither
+		// can range tombstone lookups, which may be performed while the
+		// cached iterators are in use.
 		return newPebbleIterator(p.parent.db, opts)
 	}
 
@@ -1158,6 +1160,11 @@ func pebbleExportToSst(
 	sstWriter := MakeBackupSSTWriter(sstFile)
 	defer sstWriter.Close()
 
+	if len(io.LowerBound) == 0 {
+		// The lower bound is needed to look up the range tombstones covering the
+		// exported span.
+		io.LowerBound = startKey
+	}
 	var rows RowCounter
 	iter := NewMVCCIncrementalIterator(
 		reader,
@@ -176,8 +176,10 @@ func (p *pebbleBatch) NewIterator(opts IterOptions) Iterator {
 		panic("distinct batch open")
 	}
 
-	if opts.MinTimestampHint != (hlc.Timestamp{}) {
-		// Iterators that specify timestamp bounds cannot be cached.
+	if opts.MinTimestampHint != (hlc.Timestamp{}) || opts.RangeTombstoneLookup {
+		// Iterators that specify timestamp bounds cannot be cached. Neither
+		// can range tombstone lookups, which may be performed while the
+		// cached iterators are in use.
 		return newPebbleIterator(p.batch, opts)
 	}
 
@@ -112,10 +112,13 @@ type pebbleMVCCScanner struct {
 	txnIgnoredSeqNums []enginepb.IgnoredSeqNumRange
 	// Metadata object for unmarshalling intents.
 	meta enginepb.MVCCMetadata
+	// View of the unreplicated locks, consulted when skipLocked is set.
+	lockTable LockTableView
 	// Bools copied over from MVCC{Scan,Get}Options. See the comment on the
 	// package level MVCCScan for what these mean.
 	inconsistent, tombstones bool
 	failOnMoreRecent         bool
+	skipLocked               bool
 	checkUncertainty         bool
 	keyBuf                   []byte
 	savedBuf                 []byte
@@ -268,6 +271,12 @@ func (p *pebbleMVCCScanner) uncertaintyError(ts hlc.Timestamp) bool {
 // Emit a tuple and return true if we have reason to believe iteration can
 // continue.
 func (p *pebbleMVCCScanner) getAndAdvance() bool {
+	if p.skipLocked && p.lockTable != nil && p.lockTable.IsKeyLockedByConflictingTxn(p.curKey) {
+		// The key is locked by another transaction with an unreplicated lock
+		// and we're skipping locked keys. Skip the key entirely.
+		return p.advanceKey()
+	}
+
 	mvccKey := MVCCKey{p.curKey, p.curTS}
 	if mvccKey.IsValue() {
 		if p.curTS.LessEq(p.ts) {
@@ -340,17 +349,25 @@ func (p *pebbleMVCCScanner) getAndAdvance() bool {
 	}
 	otherIntentVisible := metaTS.LessEq(maxVisibleTS) || p.failOnMoreRecent
 
+	if !ownIntent && p.skipLocked {
+		// 6. The key contains an intent which was not written by our
+		// transaction and we're skipping locked keys. Skip the key
+		// entirely, regardless of the timestamp of the intent, without
+		// returning the intent.
+		return p.advanceKey()
+	}
+
 	if !ownIntent && !otherIntentVisible {
-		// 6. The key contains an intent, but we're reading before the
+		// 7. The key contains an intent, but we're reading before the
 		// intent. Seek to the desired version. Note that if we own the
 		// intent (i.e. we're reading transactionally) we want to read
 		// the intent regardless of our read timestamp and fall into
-		// case 8 below.
+		// case 9 below.
 		return p.seekVersion(p.ts, false)
 	}
 
 	if p.inconsistent {
-		// 7. The key contains an intent and we're doing an inconsistent
+		// 8. The key contains an intent and we're doing an inconsistent
 		// read at a timestamp newer than the intent. We ignore the
 		// intent by insisting that the timestamp we're reading at is a
 		// historical timestamp < the intent timestamp. However, we
@@ -373,7 +390,7 @@ func (p *pebbleMVCCScanner) getAndAdvance() bool {
 	}
 
 	if !ownIntent {
-		// 8. The key contains an intent which was not written by our
+		// 9. The key contains an intent which was not written by our
 		// transaction and either:
 		// - our read timestamp is equal to or newer than that of the
 		//   intent
@@ -394,14 +411,14 @@ func (p *pebbleMVCCScanner) getAndAdvance() bool {
 
 	if p.txnEpoch == p.meta.Txn.Epoch {
 		if p.txnSequence >= p.meta.Txn.Sequence && !enginepb.TxnSeqIsIgnored(p.meta.Txn.Sequence, p.txnIgnoredSeqNums) {
-			// 9. We're reading our own txn's intent at an equal or higher sequence.
+			// 10. We're reading our own txn's intent at an equal or higher sequence.
 			// Note that we read at the intent timestamp, not at our read timestamp
 			// as the intent timestamp may have been pushed forward by another
 			// transaction. Txn's always need to read their own writes.
 			return p.seekVersion(metaTS, false)
 		}
 
-		// 10. We're reading our own txn's intent at a lower sequence than is
+		// 11. We're reading our own txn's intent at a lower sequence than is
 		// currently present in the intent. This means the intent we're seeing
 		// was written at a higher sequence than the read and that there may or
 		// may not be earlier versions of the intent (with lower sequence
@@ -414,7 +431,7 @@ func (p *pebbleMVCCScanner) getAndAdvance() bool {
 			}
 			return p.advanceKey()
 		}
-		// 11. If no value in the intent history has a sequence number equal to
+		// 12. If no value in the intent history has a sequence number equal to
 		// or less than the read, we must ignore the intents laid down by the
 		// transaction all together. We ignore the intent by insisting that the
 		// timestamp we're reading at is a historical timestamp < the intent
@@ -423,7 +440,7 @@ func (p *pebbleMVCCScanner) getAndAdvance() bool {
 	}
 
 	if p.txnEpoch < p.meta.Txn.Epoch {
-		// 12. We're reading our own txn's intent but the current txn has
+		// 13. We're reading our own txn's intent but the current txn has
 		// an earlier epoch than the intent. Return an error so that the
 		// earlier incarnation of our transaction aborts (presumably
 		// this is some operation that was retried).
@@ -432,7 +449,7 @@ func (p *pebbleMVCCScanner) getAndAdvance() bool {
 		return false
 	}
 
-	// 13. We're reading our own txn's intent but the current txn has a
+	// 14. We're reading our own txn's intent but the current txn has a
 	// later epoch than the intent. This can happen if the txn was
 	// restarted and an earlier iteration wrote the value we're now
 	// reading. In this case, we ignore the intent and read the
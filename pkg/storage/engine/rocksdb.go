@@ -784,6 +784,15 @@ func (r *RocksDB) ExportToSst(
 	targetSize, maxSize uint64,
 	io IterOptions,
 ) ([]byte, roachpb.BulkOpSummary, roachpb.Key, error) {
+	// The C++ export does not know about MVCC range tombstones, so fall back
+	// to the Go implementation if the exported span has any in the requested
+	// time interval.
+	if tombstones, err := MVCCFindRangeTombstones(r, startKey, endKey, startTS, endTS); err != nil {
+		return nil, roachpb.BulkOpSummary{}, nil, err
+	} else if len(tombstones) > 0 {
+		return pebbleExportToSst(r, startKey, endKey, startTS, endTS, exportAllRevisions, targetSize, maxSize, io)
+	}
+
 	start := MVCCKey{Key: startKey, Timestamp: startTS}
 	end := MVCCKey{Key: endKey, Timestamp: endTS}
 
@@ -1047,8 +1056,10 @@ func (r *rocksDBReadOnly) NewIterator(opts IterOptions) Iterator {
 	if r.isClosed {
 		panic("using a closed rocksDBReadOnly")
 	}
-	if opts.MinTimestampHint != (hlc.Timestamp{}) {
-		// Iterators that specify timestamp bounds cannot be cached.
+	if opts.MinTimestampHint != (hlc.Timestamp{}) || opts.RangeTombstoneLookup {
+		// Iterators that specify timestamp bounds cannot be cached. Neither
+		// can range tombstone lookups, which may be performed while the
+		// cached iterators are in use.
 		return newRocksDBIterator(r.parent.rdb, opts, r, r.parent)
 	}
 	iter := &r.normalIter
@@ -1396,8 +1407,10 @@ func (r *distinctBatch) Close() {
 // batch. A panic will be thrown if multiple prefix or normal (non-prefix)
 // iterators are used simultaneously on the same batch.
 func (r *distinctBatch) NewIterator(opts IterOptions) Iterator {
-	if opts.MinTimestampHint != (hlc.Timestamp{}) {
-		// Iterators that specify timestamp bounds cannot be cached.
+	if opts.MinTimestampHint != (hlc.Timestamp{}) || opts.RangeTombstoneLookup {
+		// Iterators that specify timestamp bounds cannot be cached. Neither
+		// can range tombstone lookups, which may be performed while the
+		// cached iterators are in use.
 		if r.writeOnly {
 			return newRocksDBIterator(r.parent.rdb, opts, r, r.parent)
 		}
@@ -1874,8 +1887,10 @@ func (r *rocksDBBatch) NewIterator(opts IterOptions) Iterator {
 		panic("distinct batch open")
 	}
 
-	if opts.MinTimestampHint != (hlc.Timestamp{}) {
-		// Iterators that specify timestamp bounds cannot be cached.
+	if opts.MinTimestampHint != (hlc.Timestamp{}) || opts.RangeTombstoneLookup {
+		// Iterators that specify timestamp bounds cannot be cached. Neither
+		// can range tombstone lookups, which may be performed while the
+		// cached iterators are in use.
 		r.ensureBatch()
 		iter := &batchIterator{batch: r}
 		iter.iter.init(r.batch, opts, r, r.parent)
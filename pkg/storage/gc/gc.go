@@ -272,12 +272,52 @@ func processReplicatedKeyRange(
 	// be added with that version and the batch will be sent. When the newest
 	// version for a key has been reached, if haveGarbageForThisKey, we'll add the
 	// current key to the batch with the gcTimestampForThisKey.
+	//
+	// Versions deleted by an MVCC range tombstone at or below the threshold are
+	// garbage. The range tombstones themselves are garbage once they no longer
+	// delete anything, i.e. one pass after the data they deleted was removed.
+	// The iteration visits the user keys before the range-local keys holding
+	// the range tombstones, so usedTombstones is complete by then.
+	var tombstones []engine.MVCCRangeTombstone
+	if hasTombstones, err := engine.MVCCHasRangeTombstones(snap, desc.RangeID); err != nil {
+		return err
+	} else if hasTombstones {
+		tombstones, err = engine.MVCCFindRangeTombstones(
+			snap, desc.StartKey.AsRawKey(), desc.EndKey.AsRawKey(), hlc.Timestamp{}, threshold)
+		if err != nil {
+			return err
+		}
+	}
+	usedTombstones := make([]bool, len(tombstones))
+	isDeletedByTombstone := func(key engine.MVCCKey) bool {
+		deleted := false
+		for i := range tombstones {
+			if tombstones[i].Deletes(key) {
+				usedTombstones[i] = true
+				deleted = true
+			}
+		}
+		return deleted
+	}
+	isUsedTombstone := func(start roachpb.RKey, ts hlc.Timestamp) bool {
+		for i := range tombstones {
+			if usedTombstones[i] && tombstones[i].Timestamp == ts &&
+				tombstones[i].StartKey.Equal(start.AsRawKey()) {
+				return true
+			}
+		}
+		return false
+	}
+
 	var (
 		batchGCKeys           []roachpb.GCRequest_GCKey
 		batchGCKeysBytes      int64
 		haveGarbageForThisKey bool
 		gcTimestampForThisKey hlc.Timestamp
 		sentBatchForThisKey   bool
+		// keepRangeTombstones is set once a version of a range tombstone key
+		// that is not garbage has been seen; the newer versions are kept too.
+		keepRangeTombstones bool
 	)
 	it := makeGCIterator(desc, snap)
 	defer it.close()
@@ -297,7 +337,20 @@ func processReplicatedKeyRange(
 			continue
 		}
 		isNewest := s.curIsNewest()
-		if isGarbage(threshold, s.cur, s.next, isNewest) {
+		var garbage bool
+		if engine.IsMVCCRangeTombstoneKey(s.cur.Key.Key) {
+			start, _, _, err := keys.DecodeRangeKey(s.cur.Key.Key)
+			if err != nil {
+				return err
+			}
+			garbage = !keepRangeTombstones && s.cur.Key.Timestamp.LessEq(threshold) &&
+				!isUsedTombstone(start, s.cur.Key.Timestamp)
+			keepRangeTombstones = !garbage && !isNewest
+		} else {
+			garbage = isGarbage(threshold, s.cur, s.next, isNewest) ||
+				isDeletedByTombstone(s.cur.Key)
+		}
+		if garbage {
 			keyBytes := int64(s.cur.Key.EncodedSize())
 			batchGCKeysBytes += keyBytes
 			haveGarbageForThisKey = true
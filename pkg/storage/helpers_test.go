@@ -317,6 +317,14 @@ func (r *Replica) QuotaReleaseQueueLen() int {
 	return len(r.mu.quotaReleaseQueue)
 }
 
+// IsQuarantined returns whether the replica has been quarantined by the
+// consistency checker.
+func (r *Replica) IsQuarantined() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.mu.destroyStatus.Quarantined()
+}
+
 func (r *Replica) IsFollowerActive(ctx context.Context, followerID roachpb.ReplicaID) bool {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -249,9 +249,10 @@ func (ir *IntentResolver) ProcessWriteIntentError(
 	}
 
 	// Possibly queue this processing if the write intent error is for a
-	// single intent affecting a unitary key.
+	// single intent affecting a unitary key. PUSH_TOUCH pushers never wait
+	// for the pushee, so they are never queued.
 	var cleanup func(*roachpb.WriteIntentError, *enginepb.TxnMeta)
-	if len(wiErr.Intents) == 1 {
+	if len(wiErr.Intents) == 1 && pushType != roachpb.PUSH_TOUCH {
 		var done bool
 		var pErr *roachpb.Error
 		// Note that the write intent error may be mutated here in the event
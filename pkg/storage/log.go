@@ -94,11 +94,12 @@ func (s *Store) insertRangeLogEvent(
 // logSplit logs a range split event into the event table. The affected range is
 // the range which previously existed and is being split in half; the "other"
 // range is the new range which is being created.
-//
-// TODO(mrtracy): There are several different reasons that a range split
-// could occur, and that information should be logged.
 func (s *Store) logSplit(
-	ctx context.Context, txn *client.Txn, updatedDesc, newDesc roachpb.RangeDescriptor,
+	ctx context.Context,
+	txn *client.Txn,
+	updatedDesc, newDesc roachpb.RangeDescriptor,
+	reason storagepb.RangeLogEventReason,
+	details string,
 ) error {
 	if !s.cfg.LogRangeEvents {
 		return nil
@@ -112,17 +113,20 @@ func (s *Store) logSplit(
 		Info: &storagepb.RangeLogEvent_Info{
 			UpdatedDesc: &updatedDesc,
 			NewDesc:     &newDesc,
+			Reason:      reason,
+			Details:     details,
 		},
 	})
 }
 
-// logMerge logs a range split event into the event table. The affected range is
+// logMerge logs a range merge event into the event table. The affected range is
 // the subsuming range; the "other" range is the subsumed range.
-//
-// TODO(benesch): There are several different reasons that a range merge
-// could occur, and that information should be logged.
 func (s *Store) logMerge(
-	ctx context.Context, txn *client.Txn, updatedLHSDesc, rhsDesc roachpb.RangeDescriptor,
+	ctx context.Context,
+	txn *client.Txn,
+	updatedLHSDesc, rhsDesc roachpb.RangeDescriptor,
+	reason storagepb.RangeLogEventReason,
+	details string,
 ) error {
 	if !s.cfg.LogRangeEvents {
 		return nil
@@ -136,6 +140,8 @@ func (s *Store) logMerge(
 		Info: &storagepb.RangeLogEvent_Info{
 			UpdatedDesc: &updatedLHSDesc,
 			RemovedDesc: &rhsDesc,
+			Reason:      reason,
+			Details:     details,
 		},
 	})
 }
@@ -160,7 +166,7 @@ func (s *Store) logChange(
 	var logType storagepb.RangeLogEventType
 	var info storagepb.RangeLogEvent_Info
 	switch changeType {
-	case roachpb.ADD_REPLICA:
+	case roachpb.ADD_REPLICA, roachpb.ADD_NON_VOTER:
 		logType = storagepb.RangeLogEventType_add
 		info = storagepb.RangeLogEvent_Info{
 			AddedReplica: &replica,
@@ -168,7 +174,7 @@ func (s *Store) logChange(
 			Reason:       reason,
 			Details:      details,
 		}
-	case roachpb.REMOVE_REPLICA:
+	case roachpb.REMOVE_REPLICA, roachpb.REMOVE_NON_VOTER:
 		logType = storagepb.RangeLogEventType_remove
 		info = storagepb.RangeLogEvent_Info{
 			RemovedReplica: &replica,
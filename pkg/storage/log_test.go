@@ -103,6 +103,9 @@ func TestLogSplits(t *testing.T) {
 		if int64(info.NewDesc.RangeID) != otherRangeID.Int64 {
 			t.Errorf("recorded wrong new descriptor %s for split of range %d", info.NewDesc, rangeID)
 		}
+		if info.Reason == storagepb.ReasonUnknown {
+			t.Errorf("reason not recorded for split of range %d", rangeID)
+		}
 	}
 	if rows.Err() != nil {
 		t.Fatal(rows.Err())
@@ -227,6 +230,9 @@ func TestLogMerges(t *testing.T) {
 		if int64(info.RemovedDesc.RangeID) != otherRangeID.Int64 {
 			t.Errorf("recorded wrong new descriptor %s for merge of range %d", info.RemovedDesc, rangeID)
 		}
+		if info.Reason != storagepb.ReasonAdminRequest {
+			t.Errorf("recorded wrong reason %q for merge of range %d", info.Reason, rangeID)
+		}
 	}
 	if rows.Err() != nil {
 		t.Fatal(rows.Err())
@@ -0,0 +1,196 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package loqrecovery
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/storage/concurrency/lock"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/storage/stateloader"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/pkg/errors"
+)
+
+// PrepareReplicaReport describes a descriptor rewrite staged by
+// PrepareUpdateReplicas.
+type PrepareReplicaReport struct {
+	OldDesc roachpb.RangeDescriptor
+	NewDesc roachpb.RangeDescriptor
+	// AbortedTxn is set if the transaction that held an intent on the
+	// descriptor had to be aborted to perform the rewrite.
+	AbortedTxn *enginepb.TxnMeta
+}
+
+// PrepareUpdateReplicas stages the updates of the plan that concern the given
+// store in a batch. The store must not be in use by a running node. The
+// returned batch is nil if the plan contains no updates for the store;
+// otherwise, it is up to the caller to commit or close it.
+//
+// The descriptor found on the store is verified to match the one the plan was
+// made from, which guards against applying a plan to a store that changed
+// after its replica info was collected.
+func PrepareUpdateReplicas(
+	ctx context.Context, eng engine.Engine, plan ReplicaUpdatePlan, now hlc.Timestamp,
+) (engine.Batch, []PrepareReplicaReport, error) {
+	ident, err := storage.ReadStoreIdent(ctx, eng)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, id := range plan.DeadStoreIDs {
+		if id == ident.StoreID {
+			return nil, nil, errors.Errorf("this store's ID (%s) is marked as dead in the plan", id)
+		}
+	}
+
+	var batch engine.Batch
+	var reports []PrepareReplicaReport
+	for _, update := range plan.Updates {
+		if update.NewReplica.StoreID != ident.StoreID {
+			continue
+		}
+		if batch == nil {
+			batch = eng.NewBatch()
+		}
+		report, err := prepareUpdateReplica(ctx, batch, update, now)
+		if err != nil {
+			batch.Close()
+			return nil, nil, errors.Wrapf(err, "r%d", update.RangeID)
+		}
+		reports = append(reports, report)
+	}
+	return batch, reports, nil
+}
+
+func prepareUpdateReplica(
+	ctx context.Context, batch engine.Batch, update ReplicaUpdate, now hlc.Timestamp,
+) (PrepareReplicaReport, error) {
+	var desc roachpb.RangeDescriptor
+	ok, err := engine.MVCCGetProto(ctx, batch, keys.RangeDescriptorKey(update.StartKey),
+		hlc.MaxTimestamp, &desc, engine.MVCCGetOptions{Inconsistent: true})
+	if err != nil {
+		return PrepareReplicaReport{}, err
+	} else if !ok || desc.RangeID != update.RangeID {
+		return PrepareReplicaReport{}, errors.Errorf(
+			"range descriptor not found at key %s", update.StartKey)
+	}
+	if rd, ok := desc.GetReplicaDescriptor(update.NewReplica.StoreID); !ok || rd.ReplicaID != update.OldReplicaID {
+		return PrepareReplicaReport{}, errors.Errorf(
+			"descriptor %s does not match the plan; was the replica info collected before the "+
+				"store was last used?", &desc)
+	}
+
+	newDesc := desc
+	newDesc.SetReplicas(roachpb.MakeReplicaDescriptors([]roachpb.ReplicaDescriptor{update.NewReplica}))
+	newDesc.NextReplicaID = update.NextReplicaID
+	abortedTxn, err := RewriteRangeDescriptor(ctx, batch, newDesc, now)
+	if err != nil {
+		return PrepareReplicaReport{}, err
+	}
+	return PrepareReplicaReport{OldDesc: desc, NewDesc: newDesc, AbortedTxn: abortedTxn}, nil
+}
+
+// RewriteRangeDescriptor overwrites the range-local copy of the given
+// descriptor. If the descriptor key holds an intent, the transaction that
+// wrote it is aborted and returned.
+func RewriteRangeDescriptor(
+	ctx context.Context, rw engine.ReadWriter, desc roachpb.RangeDescriptor, now hlc.Timestamp,
+) (*enginepb.TxnMeta, error) {
+	// Write the rewritten descriptor to the range-local descriptor
+	// key. We do not update the meta copies of the descriptor.
+	// Instead, we leave them in a temporarily inconsistent state and
+	// they will be overwritten when the cluster recovers and
+	// up-replicates this range from its single copy to multiple
+	// copies. We rely on the fact that all range descriptor updates
+	// start with a CPut on the range-local copy followed by a blind
+	// Put to the meta copy.
+	//
+	// For example, if we have replicas on s1-s4 but s3 and s4 are
+	// dead, we will rewrite the replica on s2 to have s2 as its only
+	// member only. When the cluster is restarted (and the dead nodes
+	// remain dead), the rewritten replica will be the only one able
+	// to make progress. It will elect itself leader and upreplicate.
+	//
+	// The old replica on s1 is untouched by this process. It will
+	// eventually either be overwritten by a new replica when s2
+	// upreplicates, or it will be destroyed by the replica GC queue
+	// after upreplication has happened and s1 is no longer a member.
+	// (Note that in the latter case, consistency between s1 and s2 no
+	// longer matters; the consistency checker will only run on nodes
+	// that the new leader believes are members of the range).
+	//
+	// Note that this does not guarantee fully consistent results; the
+	// most recent writes to the raft log may have been lost. In the
+	// most unfortunate cases, this means that we would be "winding
+	// back" a split or a merge, which is almost certainly to result in
+	// irrecoverable corruption (for example, not only will individual
+	// values stored in the meta ranges diverge, but there will be keys
+	// not represented by any ranges or vice versa).
+	key := keys.RangeDescriptorKey(desc.StartKey)
+	sl := stateloader.Make(desc.RangeID)
+	ms, err := sl.LoadMVCCStats(ctx, rw)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading MVCCStats")
+	}
+	var abortedTxn *enginepb.TxnMeta
+	err = engine.MVCCPutProto(ctx, rw, &ms, key, now, nil /* txn */, &desc)
+	if wiErr, ok := err.(*roachpb.WriteIntentError); ok {
+		if len(wiErr.Intents) != 1 {
+			return nil, errors.Errorf("expected 1 intent, found %d: %s", len(wiErr.Intents), wiErr)
+		}
+		intent := wiErr.Intents[0]
+		// We rely on the property that transactions involving the range
+		// descriptor always start on the range-local descriptor's key.
+		// This guarantees that when the transaction commits, the intent
+		// will be resolved synchronously. If we see an intent on this
+		// key, we know that the transaction did not commit and we can
+		// abort it.
+		//
+		// TODO(nvanbenschoten): This need updating for parallel
+		// commits. If the transaction record is in the STAGING state,
+		// we can't just delete it. Simplest solution to this is to
+		// avoid parallel commits for membership change transactions; if
+		// we can't do that I don't think we'll be able to recover them
+		// with an offline tool.
+		abortedTxn = &intent.Txn
+
+		// A crude form of the intent resolution process: abort the
+		// transaction by deleting its record.
+		txnKey := keys.TransactionKey(intent.Txn.Key, intent.Txn.ID)
+		if err := engine.MVCCDelete(ctx, rw, &ms, txnKey, hlc.Timestamp{}, nil); err != nil {
+			return nil, err
+		}
+		update := roachpb.LockUpdate{
+			Span:       roachpb.Span{Key: intent.Key},
+			Txn:        intent.Txn,
+			Status:     roachpb.ABORTED,
+			Durability: lock.Replicated,
+		}
+		if _, err := engine.MVCCResolveWriteIntent(ctx, rw, &ms, update); err != nil {
+			return nil, err
+		}
+		// With the intent resolved, we can try again.
+		if err := engine.MVCCPutProto(ctx, rw, &ms, key, now,
+			nil /* txn */, &desc); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	if err := sl.SetMVCCStats(ctx, rw, &ms); err != nil {
+		return nil, errors.Wrap(err, "updating MVCCStats")
+	}
+	return abortedTxn, nil
+}
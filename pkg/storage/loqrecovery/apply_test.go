@@ -0,0 +1,117 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package loqrecovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/storage/stateloader"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/etcd/raft/raftpb"
+)
+
+// makeStore creates an in-memory store with the given ID that holds a replica
+// of each of the given descriptors.
+func makeStore(
+	t *testing.T, storeID roachpb.StoreID, appliedIndex uint64, descs ...roachpb.RangeDescriptor,
+) engine.Engine {
+	ctx := context.Background()
+	eng := engine.NewDefaultInMem()
+	ident := roachpb.StoreIdent{NodeID: roachpb.NodeID(storeID), StoreID: storeID}
+	require.NoError(t, engine.MVCCPutProto(
+		ctx, eng, nil /* ms */, keys.StoreIdentKey(), hlc.Timestamp{}, nil /* txn */, &ident))
+	for i := range descs {
+		desc := &descs[i]
+		require.NoError(t, engine.MVCCPutProto(
+			ctx, eng, nil /* ms */, keys.RangeDescriptorKey(desc.StartKey),
+			hlc.Timestamp{WallTime: 1}, nil /* txn */, desc))
+		rsl := stateloader.Make(desc.RangeID)
+		require.NoError(t, rsl.SetRangeAppliedState(
+			ctx, eng, appliedIndex, 1 /* leaseAppliedIndex */, &enginepb.MVCCStats{}))
+		require.NoError(t, rsl.SetHardState(ctx, eng, raftpb.HardState{Commit: appliedIndex + 1}))
+	}
+	return eng
+}
+
+func TestCollectAndApply(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	r1 := makeDesc(1, roachpb.RKeyMin, roachpb.RKeyMax, 1, 2, 3)
+	// s4 holds a replica of r1 that was removed but not yet garbage collected.
+	s3 := makeStore(t, 3, 10, r1)
+	defer s3.Close()
+	s4 := makeStore(t, 4, 20, r1)
+	defer s4.Close()
+
+	info3, err := CollectReplicaInfo(ctx, s3)
+	require.NoError(t, err)
+	require.Equal(t, []ReplicaInfo{{
+		NodeID:             3,
+		StoreID:            3,
+		Desc:               r1,
+		RaftAppliedIndex:   10,
+		RaftCommittedIndex: 11,
+	}}, info3)
+	info4, err := CollectReplicaInfo(ctx, s4)
+	require.NoError(t, err)
+	require.Empty(t, info4)
+
+	plan, problems, err := PlanReplicas(ctx, []NodeReplicaInfo{
+		{Replicas: info3}, {Replicas: info4},
+	}, nil /* deadStoreIDs */)
+	require.NoError(t, err)
+	require.Empty(t, problems)
+	require.Len(t, plan.Updates, 1)
+
+	// Leave an intent from an abandoned replication change on the descriptor.
+	// It must be aborted for the rewrite to go through.
+	txn := roachpb.MakeTransaction(
+		"test", r1.StartKey.AsRawKey(), roachpb.NormalUserPriority, hlc.Timestamp{WallTime: 2}, 0)
+	require.NoError(t, engine.MVCCPutProto(
+		ctx, s3, nil /* ms */, keys.RangeDescriptorKey(r1.StartKey), txn.WriteTimestamp, &txn, &r1))
+
+	// The plan doesn't concern s4.
+	batch, reports, err := PrepareUpdateReplicas(ctx, s4, plan, hlc.Timestamp{WallTime: 3})
+	require.NoError(t, err)
+	require.Nil(t, batch)
+	require.Empty(t, reports)
+
+	batch, reports, err = PrepareUpdateReplicas(ctx, s3, plan, hlc.Timestamp{WallTime: 3})
+	require.NoError(t, err)
+	require.NotNil(t, batch)
+	defer batch.Close()
+	require.Len(t, reports, 1)
+	require.NotNil(t, reports[0].AbortedTxn)
+	require.Equal(t, txn.ID, reports[0].AbortedTxn.ID)
+	require.NoError(t, batch.Commit(true /* sync */))
+
+	var desc roachpb.RangeDescriptor
+	ok, err := engine.MVCCGetProto(ctx, s3, keys.RangeDescriptorKey(r1.StartKey),
+		hlc.MaxTimestamp, &desc, engine.MVCCGetOptions{})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, reports[0].NewDesc, desc)
+	require.Equal(t, []roachpb.ReplicaDescriptor{{NodeID: 3, StoreID: 3, ReplicaID: 4}},
+		desc.Replicas().All())
+	require.Equal(t, roachpb.ReplicaID(5), desc.NextReplicaID)
+
+	// The plan can't be applied again, since the descriptor no longer matches.
+	_, _, err = PrepareUpdateReplicas(ctx, s3, plan, hlc.Timestamp{WallTime: 4})
+	require.Error(t, err)
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package loqrecovery
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/stateloader"
+	"github.com/pkg/errors"
+)
+
+// CollectReplicaInfo captures the descriptor and raft state of every replica
+// found on the given store. The store must not be in use by a running node.
+//
+// Descriptors of replicas that have been removed from their range but not yet
+// garbage collected are skipped, as such replicas can't take part in the
+// recovery.
+//
+// Stores keeping their Raft log in a separate engine are not supported, as
+// their HardStates are not found in eng.
+func CollectReplicaInfo(ctx context.Context, eng engine.Engine) ([]ReplicaInfo, error) {
+	ident, err := storage.ReadStoreIdent(ctx, eng)
+	if err != nil {
+		return nil, err
+	}
+	if separate, err := storage.UsesSeparateRaftEngine(ctx, eng); err != nil {
+		return nil, err
+	} else if separate {
+		return nil, errors.Errorf("s%d keeps its Raft log in a separate engine, which is not supported",
+			ident.StoreID)
+	}
+
+	var replicas []ReplicaInfo
+	err = storage.IterateRangeDescriptors(ctx, eng, func(desc roachpb.RangeDescriptor) (bool, error) {
+		if _, ok := desc.GetReplicaDescriptor(ident.StoreID); !ok {
+			return false, nil
+		}
+		rsl := stateloader.Make(desc.RangeID)
+		raftAppliedIndex, _, err := rsl.LoadAppliedIndex(ctx, eng)
+		if err != nil {
+			return false, errors.Wrapf(err, "loading applied index of r%d", desc.RangeID)
+		}
+		hs, err := rsl.LoadHardState(ctx, eng)
+		if err != nil {
+			return false, errors.Wrapf(err, "loading hard state of r%d", desc.RangeID)
+		}
+		replicas = append(replicas, ReplicaInfo{
+			NodeID:             ident.NodeID,
+			StoreID:            ident.StoreID,
+			Desc:               desc,
+			RaftAppliedIndex:   raftAppliedIndex,
+			RaftCommittedIndex: hs.Commit,
+		})
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return replicas, nil
+}
@@ -0,0 +1,222 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package loqrecovery
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/pkg/errors"
+)
+
+// PlanReplicas computes the plan that recovers all ranges that lost quorum,
+// given the replica info collected from all surviving stores.
+//
+// Stores that are referenced by the collected descriptors but from which no
+// info was collected are considered dead. If deadStoreIDs is non-empty, it must
+// cover all such stores, and none of its stores may have had info collected.
+//
+// For every range, the surviving voter that has applied the most of the raft
+// log is picked as the source of truth. Only replicas that are VOTER_FULL or
+// VOTER_INCOMING in their own descriptor are considered, and an error is
+// returned if a range has no such replica. If the descriptor of the chosen
+// replica indicates that the range can't make progress with the surviving
+// replicas, the plan turns the replica into the only member of its range.
+//
+// Besides the plan, a list of problems found in the surviving replicas is
+// returned. Problems indicate that the descriptors of the chosen replicas do
+// not cover the keyspace exactly once, for instance because all replicas of a
+// range were lost or because the chosen replicas disagree about a split or
+// merge. Applying a plan that has problems is likely to corrupt the cluster.
+func PlanReplicas(
+	ctx context.Context, nodes []NodeReplicaInfo, deadStoreIDs []roachpb.StoreID,
+) (ReplicaUpdatePlan, []string, error) {
+	type rangeStore struct {
+		rangeID roachpb.RangeID
+		storeID roachpb.StoreID
+	}
+	liveStores := make(map[roachpb.StoreID]struct{})
+	seen := make(map[rangeStore]struct{})
+	replicasByRange := make(map[roachpb.RangeID][]ReplicaInfo)
+	for _, node := range nodes {
+		for _, r := range node.Replicas {
+			key := rangeStore{rangeID: r.Desc.RangeID, storeID: r.StoreID}
+			if _, ok := seen[key]; ok {
+				return ReplicaUpdatePlan{}, nil, errors.Errorf(
+					"replica info for r%d on s%d was collected more than once", r.Desc.RangeID, r.StoreID)
+			}
+			seen[key] = struct{}{}
+			liveStores[r.StoreID] = struct{}{}
+			replicasByRange[r.Desc.RangeID] = append(replicasByRange[r.Desc.RangeID], r)
+		}
+	}
+
+	missingStores := make(map[roachpb.StoreID]struct{})
+	for _, replicas := range replicasByRange {
+		for _, r := range replicas {
+			for _, rd := range r.Desc.Replicas().All() {
+				if _, ok := liveStores[rd.StoreID]; !ok {
+					missingStores[rd.StoreID] = struct{}{}
+				}
+			}
+		}
+	}
+	if len(deadStoreIDs) > 0 {
+		deadStores := make(map[roachpb.StoreID]struct{}, len(deadStoreIDs))
+		for _, id := range deadStoreIDs {
+			if _, ok := liveStores[id]; ok {
+				return ReplicaUpdatePlan{}, nil, errors.Errorf(
+					"s%d was marked as dead, but replica info was collected from it", id)
+			}
+			deadStores[id] = struct{}{}
+		}
+		for id := range missingStores {
+			if _, ok := deadStores[id]; !ok {
+				return ReplicaUpdatePlan{}, nil, errors.Errorf(
+					"no replica info was collected from s%d, but it was not marked as dead", id)
+			}
+		}
+	} else {
+		for id := range missingStores {
+			deadStoreIDs = append(deadStoreIDs, id)
+		}
+	}
+	deadStoreIDs = append([]roachpb.StoreID(nil), deadStoreIDs...)
+	sort.Sort(roachpb.StoreIDSlice(deadStoreIDs))
+
+	rangeIDs := make([]roachpb.RangeID, 0, len(replicasByRange))
+	for rangeID := range replicasByRange {
+		rangeIDs = append(rangeIDs, rangeID)
+	}
+	sort.Sort(roachpb.RangeIDSlice(rangeIDs))
+
+	isLive := func(rd roachpb.ReplicaDescriptor) bool {
+		_, ok := liveStores[rd.StoreID]
+		return ok
+	}
+	plan := ReplicaUpdatePlan{DeadStoreIDs: deadStoreIDs}
+	chosen := make([]ReplicaInfo, 0, len(rangeIDs))
+	for _, rangeID := range rangeIDs {
+		replicas := replicasByRange[rangeID]
+		// Learners and non-voters don't take part in the quorum, so they may be
+		// missing committed entries, and a replica that isn't in its own
+		// descriptor has no replica ID that could be replaced. Neither can be
+		// the source of truth.
+		var voters []ReplicaInfo
+		for _, r := range replicas {
+			if isVoterInOwnDesc(r) {
+				voters = append(voters, r)
+			}
+		}
+		if len(voters) == 0 {
+			storeIDs := make([]roachpb.StoreID, 0, len(replicas))
+			for _, r := range replicas {
+				storeIDs = append(storeIDs, r.StoreID)
+			}
+			sort.Sort(roachpb.StoreIDSlice(storeIDs))
+			return ReplicaUpdatePlan{}, nil, errors.Errorf(
+				"none of the surviving replicas of r%d (on stores %v) is a voter in its own descriptor",
+				rangeID, storeIDs)
+		}
+		// Pick the voter that has applied the most of the raft log, breaking
+		// ties by store ID so that the choice is deterministic.
+		sort.Slice(voters, func(i, j int) bool {
+			if voters[i].RaftAppliedIndex != voters[j].RaftAppliedIndex {
+				return voters[i].RaftAppliedIndex > voters[j].RaftAppliedIndex
+			}
+			return voters[i].StoreID > voters[j].StoreID
+		})
+		winner := voters[0]
+		chosen = append(chosen, winner)
+
+		desc := winner.Desc
+		if desc.Replicas().CanMakeProgress(isLive) {
+			continue
+		}
+		// Other surviving replicas may have seen a more recent descriptor than
+		// the winner. Make sure the new replica ID is not one that any of them
+		// could have handed out already.
+		nextReplicaID := desc.NextReplicaID
+		for _, r := range replicas {
+			if r.Desc.NextReplicaID > nextReplicaID {
+				nextReplicaID = r.Desc.NextReplicaID
+			}
+		}
+		oldReplica, _ := desc.GetReplicaDescriptor(winner.StoreID)
+		update := ReplicaUpdate{
+			RangeID:      rangeID,
+			StartKey:     desc.StartKey,
+			OldReplicaID: oldReplica.ReplicaID,
+			NewReplica: roachpb.ReplicaDescriptor{
+				NodeID:    winner.NodeID,
+				StoreID:   winner.StoreID,
+				ReplicaID: nextReplicaID,
+			},
+			NextReplicaID: nextReplicaID + 1,
+		}
+		log.Infof(ctx, "recovering r%d using replica %s (applied index %d)",
+			rangeID, update.NewReplica, winner.RaftAppliedIndex)
+		plan.Updates = append(plan.Updates, update)
+	}
+
+	return plan, checkKeyspaceCoverage(chosen), nil
+}
+
+// isVoterInOwnDesc returns whether the replica is a VOTER_FULL or a
+// VOTER_INCOMING according to the descriptor it has.
+func isVoterInOwnDesc(r ReplicaInfo) bool {
+	rd, ok := r.Desc.GetReplicaDescriptor(r.StoreID)
+	if !ok {
+		return false
+	}
+	switch rd.GetType() {
+	case roachpb.VOTER_FULL, roachpb.VOTER_INCOMING:
+		return true
+	}
+	return false
+}
+
+// checkKeyspaceCoverage verifies that the descriptors of the given replicas
+// cover the keyspace exactly once, and returns a description of each gap and
+// overlap found.
+func checkKeyspaceCoverage(replicas []ReplicaInfo) []string {
+	descs := make([]roachpb.RangeDescriptor, len(replicas))
+	for i := range replicas {
+		descs[i] = replicas[i].Desc
+	}
+	sort.Slice(descs, func(i, j int) bool {
+		return descs[i].StartKey.Less(descs[j].StartKey)
+	})
+
+	var problems []string
+	prevEnd := roachpb.RKeyMin
+	for i := range descs {
+		desc := &descs[i]
+		if prevEnd.Less(desc.StartKey) {
+			problems = append(problems, fmt.Sprintf(
+				"key range [%s, %s) is not covered by any surviving replica", prevEnd, desc.StartKey))
+		} else if desc.StartKey.Less(prevEnd) {
+			problems = append(problems, fmt.Sprintf(
+				"r%d %s overlaps with a preceding range ending at %s", desc.RangeID, desc.RSpan(), prevEnd))
+		}
+		if prevEnd.Less(desc.EndKey) {
+			prevEnd = desc.EndKey
+		}
+	}
+	if prevEnd.Less(roachpb.RKeyMax) {
+		problems = append(problems, fmt.Sprintf(
+			"key range [%s, %s) is not covered by any surviving replica", prevEnd, roachpb.RKeyMax))
+	}
+	return problems
+}
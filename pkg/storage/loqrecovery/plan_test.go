@@ -0,0 +1,173 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package loqrecovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func makeDesc(
+	rangeID roachpb.RangeID, start, end roachpb.RKey, storeIDs ...roachpb.StoreID,
+) roachpb.RangeDescriptor {
+	desc := roachpb.RangeDescriptor{
+		RangeID:       rangeID,
+		StartKey:      start,
+		EndKey:        end,
+		NextReplicaID: 1,
+	}
+	for _, storeID := range storeIDs {
+		desc.AddReplica(roachpb.NodeID(storeID), storeID, roachpb.VOTER_FULL)
+	}
+	return desc
+}
+
+func makeReplicaInfo(
+	storeID roachpb.StoreID, desc roachpb.RangeDescriptor, appliedIndex uint64,
+) ReplicaInfo {
+	return ReplicaInfo{
+		NodeID:             roachpb.NodeID(storeID),
+		StoreID:            storeID,
+		Desc:               desc,
+		RaftAppliedIndex:   appliedIndex,
+		RaftCommittedIndex: appliedIndex,
+	}
+}
+
+func TestPlanReplicas(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	splitKey := roachpb.RKey("m")
+	// r1 lost two of its three replicas, r2 only lost one.
+	r1 := makeDesc(1, roachpb.RKeyMin, splitKey, 1, 2, 3)
+	r2 := makeDesc(2, splitKey, roachpb.RKeyMax, 1, 4, 5)
+
+	t.Run("recover", func(t *testing.T) {
+		nodes := []NodeReplicaInfo{
+			{Replicas: []ReplicaInfo{makeReplicaInfo(4, r2, 20)}},
+			{Replicas: []ReplicaInfo{makeReplicaInfo(5, r2, 20), makeReplicaInfo(3, r1, 10)}},
+		}
+		plan, problems, err := PlanReplicas(ctx, nodes, nil /* deadStoreIDs */)
+		require.NoError(t, err)
+		require.Empty(t, problems)
+		require.Equal(t, []roachpb.StoreID{1, 2}, plan.DeadStoreIDs)
+		require.Equal(t, []ReplicaUpdate{{
+			RangeID:       1,
+			StartKey:      roachpb.RKeyMin,
+			OldReplicaID:  3,
+			NewReplica:    roachpb.ReplicaDescriptor{NodeID: 3, StoreID: 3, ReplicaID: 4},
+			NextReplicaID: 5,
+		}}, plan.Updates)
+	})
+
+	t.Run("most advanced replica", func(t *testing.T) {
+		r3 := makeDesc(3, roachpb.RKeyMin, roachpb.RKeyMax, 1, 2, 3, 4, 5)
+		nodes := []NodeReplicaInfo{
+			{Replicas: []ReplicaInfo{makeReplicaInfo(2, r3, 15)}},
+			{Replicas: []ReplicaInfo{makeReplicaInfo(3, r3, 12)}},
+		}
+		plan, problems, err := PlanReplicas(ctx, nodes, nil /* deadStoreIDs */)
+		require.NoError(t, err)
+		require.Empty(t, problems)
+		require.Len(t, plan.Updates, 1)
+		require.Equal(t, roachpb.StoreID(2), plan.Updates[0].NewReplica.StoreID)
+		require.Equal(t, roachpb.ReplicaID(2), plan.Updates[0].OldReplicaID)
+	})
+
+	t.Run("only voters are chosen", func(t *testing.T) {
+		// The voters on s1 and s2 were lost. The surviving replica on s6 has
+		// applied more of the log than the voter on s3, but it isn't a voter in
+		// its own descriptor.
+		learner := makeDesc(4, roachpb.RKeyMin, roachpb.RKeyMax, 1, 2, 3)
+		learner.AddReplica(6, 6, roachpb.LEARNER)
+		nonVoter := makeDesc(4, roachpb.RKeyMin, roachpb.RKeyMax, 1, 2, 3)
+		nonVoter.AddReplica(6, 6, roachpb.NON_VOTER)
+		// The replica on s6 was removed from the range, but hasn't been GC'ed.
+		removed := makeDesc(4, roachpb.RKeyMin, roachpb.RKeyMax, 1, 2, 3)
+		removed.NextReplicaID = 5
+		for name, s6Desc := range map[string]roachpb.RangeDescriptor{
+			"learner":   learner,
+			"non-voter": nonVoter,
+			"removed":   removed,
+		} {
+			t.Run(name, func(t *testing.T) {
+				nodes := []NodeReplicaInfo{
+					{Replicas: []ReplicaInfo{makeReplicaInfo(3, learner, 10)}},
+					{Replicas: []ReplicaInfo{makeReplicaInfo(6, s6Desc, 30)}},
+				}
+				plan, problems, err := PlanReplicas(ctx, nodes, nil /* deadStoreIDs */)
+				require.NoError(t, err)
+				require.Empty(t, problems)
+				require.Equal(t, []ReplicaUpdate{{
+					RangeID:       4,
+					StartKey:      roachpb.RKeyMin,
+					OldReplicaID:  3,
+					NewReplica:    roachpb.ReplicaDescriptor{NodeID: 3, StoreID: 3, ReplicaID: 5},
+					NextReplicaID: 6,
+				}}, plan.Updates)
+
+				// Without the voter on s3, there is nothing to recover from.
+				nodes = nodes[1:]
+				_, _, err = PlanReplicas(ctx, nodes, nil /* deadStoreIDs */)
+				require.EqualError(t, err,
+					"none of the surviving replicas of r4 (on stores [6]) is a voter in its own descriptor")
+			})
+		}
+	})
+
+	t.Run("dead stores", func(t *testing.T) {
+		nodes := []NodeReplicaInfo{
+			{Replicas: []ReplicaInfo{makeReplicaInfo(3, r1, 10), makeReplicaInfo(4, r2, 20)}},
+		}
+		_, _, err := PlanReplicas(ctx, nodes, []roachpb.StoreID{1, 2})
+		require.EqualError(t, err, "no replica info was collected from s5, but it was not marked as dead")
+		_, _, err = PlanReplicas(ctx, nodes, []roachpb.StoreID{1, 2, 4, 5})
+		require.EqualError(t, err, "s4 was marked as dead, but replica info was collected from it")
+		plan, _, err := PlanReplicas(ctx, nodes, []roachpb.StoreID{5, 2, 1})
+		require.NoError(t, err)
+		require.Equal(t, []roachpb.StoreID{1, 2, 5}, plan.DeadStoreIDs)
+	})
+
+	t.Run("duplicate info", func(t *testing.T) {
+		nodes := []NodeReplicaInfo{
+			{Replicas: []ReplicaInfo{makeReplicaInfo(3, r1, 10)}},
+			{Replicas: []ReplicaInfo{makeReplicaInfo(3, r1, 10)}},
+		}
+		_, _, err := PlanReplicas(ctx, nodes, nil /* deadStoreIDs */)
+		require.EqualError(t, err, "replica info for r1 on s3 was collected more than once")
+	})
+
+	t.Run("keyspace coverage", func(t *testing.T) {
+		// All replicas of r2 were lost.
+		nodes := []NodeReplicaInfo{
+			{Replicas: []ReplicaInfo{makeReplicaInfo(3, r1, 10)}},
+		}
+		_, problems, err := PlanReplicas(ctx, nodes, nil /* deadStoreIDs */)
+		require.NoError(t, err)
+		require.Len(t, problems, 1)
+		require.Regexp(t, `^key range \[.*, /Max\) is not covered by any surviving replica$`, problems[0])
+
+		// The only survivor of r1 did not learn about the split that created r2.
+		unsplit := makeDesc(1, roachpb.RKeyMin, roachpb.RKeyMax, 1, 2, 3)
+		nodes = []NodeReplicaInfo{
+			{Replicas: []ReplicaInfo{makeReplicaInfo(3, unsplit, 10), makeReplicaInfo(4, r2, 20)}},
+		}
+		_, problems, err = PlanReplicas(ctx, nodes, nil /* deadStoreIDs */)
+		require.NoError(t, err)
+		require.Len(t, problems, 1)
+		require.Regexp(t, `^r2 .* overlaps with a preceding range ending at /Max$`, problems[0])
+	})
+}
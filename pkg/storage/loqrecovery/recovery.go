@@ -0,0 +1,78 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package loqrecovery implements offline recovery of ranges that have lost
+// quorum because a majority of their replicas resided on stores that are
+// permanently gone.
+//
+// Recovery proceeds in three steps, all of which operate on stores that are
+// not in use by a running node. First, CollectReplicaInfo is run against every
+// surviving store and records the descriptor and raft state of each replica
+// found there. Then, PlanReplicas consumes the info collected from all
+// surviving stores and picks, for each range that can no longer make progress,
+// the surviving replica that has applied the most of the raft log. It produces
+// a plan that turns this replica into the sole voter of its range. Finally,
+// PrepareUpdateReplicas is run against every surviving store and stages the
+// parts of the plan that concern that store.
+//
+// Once the cluster is restarted, the designated replicas elect themselves
+// leader and up-replicate the ranges back to their configured replication
+// factor. The recovery is not guaranteed to be consistent: writes that were
+// only applied on the lost replicas are gone, and the recovered ranges should be
+// checked for consistency after the restart.
+package loqrecovery
+
+import "github.com/cockroachdb/cockroach/pkg/roachpb"
+
+// ReplicaInfo describes a replica found on a surviving store.
+type ReplicaInfo struct {
+	NodeID  roachpb.NodeID          `json:"node_id"`
+	StoreID roachpb.StoreID         `json:"store_id"`
+	Desc    roachpb.RangeDescriptor `json:"desc"`
+	// RaftAppliedIndex is the index of the last raft log entry applied by the
+	// replica to its state machine.
+	RaftAppliedIndex uint64 `json:"raft_applied_index"`
+	// RaftCommittedIndex is the index of the last raft log entry known by the
+	// replica to be committed.
+	RaftCommittedIndex uint64 `json:"raft_committed_index"`
+}
+
+// NodeReplicaInfo is the replica info collected from one or more stores. It is
+// the unit in which info is passed from the collection to the planning step.
+type NodeReplicaInfo struct {
+	Replicas []ReplicaInfo `json:"replicas"`
+}
+
+// ReplicaUpdate describes the rewrite of the descriptor of a range that lost
+// quorum. The rewrite is applied on the store holding the surviving replica that
+// was chosen to recover the range, and turns that replica into the only member
+// of the range.
+type ReplicaUpdate struct {
+	RangeID  roachpb.RangeID `json:"range_id"`
+	StartKey roachpb.RKey    `json:"start_key"`
+	// OldReplicaID is the ID of the chosen replica in the descriptor found on
+	// its store. It is used to verify that the store has not changed since the
+	// replica info was collected.
+	OldReplicaID roachpb.ReplicaID `json:"old_replica_id"`
+	// NewReplica is the only replica of the rewritten descriptor. It is given a
+	// fresh replica ID so that other surviving replicas of the old incarnation
+	// of the range do not recognize it.
+	NewReplica    roachpb.ReplicaDescriptor `json:"new_replica"`
+	NextReplicaID roachpb.ReplicaID         `json:"next_replica_id"`
+}
+
+// ReplicaUpdatePlan is the set of descriptor rewrites that recovers all ranges
+// that lost quorum.
+type ReplicaUpdatePlan struct {
+	Updates []ReplicaUpdate `json:"updates"`
+	// DeadStoreIDs are the stores that were considered lost when the plan was
+	// made.
+	DeadStoreIDs []roachpb.StoreID `json:"dead_store_ids"`
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/storage/storagebase"
+	"github.com/cockroachdb/cockroach/pkg/storage/storagepb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -58,7 +59,9 @@ var MergeQueueInterval = settings.RegisterNonNegativeDurationSetting(
 // queued, the size of the right-hand neighbor will additionally be checked;
 // merges can only proceed if a) the right-hand neighbor is beneath the minimum
 // size threshold, and b) the merged range would not need to be immediately
-// split, e.g. because the new range would exceed the maximum size threshold.
+// split, e.g. because the new range would exceed the maximum size threshold or
+// because the combined load that both sides saw over the recent past would
+// trigger a load-based split.
 //
 // Note that the merge queue is not capable of initiating all possible merges.
 // Consider the example below:
@@ -194,25 +197,30 @@ func (rangeMergePurgatoryError) purgatoryErrorMarker() {}
 
 var _ purgatoryError = rangeMergePurgatoryError{}
 
+// requestRangeStats returns the descriptor, MVCC stats and maximum QPS over the
+// load-based merge retention period of the range containing the given key. The
+// returned boolean is false if the range's leaseholder has not recorded load
+// for long enough to report a maximum QPS.
 func (mq *mergeQueue) requestRangeStats(
 	ctx context.Context, key roachpb.Key,
-) (*roachpb.RangeDescriptor, enginepb.MVCCStats, float64, error) {
+) (*roachpb.RangeDescriptor, enginepb.MVCCStats, float64, bool, error) {
 	res, pErr := client.SendWrappedWith(ctx, mq.db.NonTransactionalSender(), roachpb.Header{
 		ReturnRangeInfo: true,
 	}, &roachpb.RangeStatsRequest{
 		RequestHeader: roachpb.RequestHeader{Key: key},
 	})
 	if pErr != nil {
-		return nil, enginepb.MVCCStats{}, 0, pErr.GoError()
+		return nil, enginepb.MVCCStats{}, 0, false, pErr.GoError()
 	}
 	rangeInfos := res.Header().RangeInfos
 	if len(rangeInfos) != 1 {
-		return nil, enginepb.MVCCStats{}, 0, fmt.Errorf(
+		return nil, enginepb.MVCCStats{}, 0, false, fmt.Errorf(
 			"mergeQueue.requestRangeStats: response had %d range infos but exactly one was expected",
 			len(rangeInfos))
 	}
-	return &rangeInfos[0].Desc, res.(*roachpb.RangeStatsResponse).MVCCStats,
-		res.(*roachpb.RangeStatsResponse).QueriesPerSecond, nil
+	rangeStats := res.(*roachpb.RangeStatsResponse)
+	return &rangeInfos[0].Desc, rangeStats.MVCCStats,
+		rangeStats.MaxQueriesPerSecond, rangeStats.MaxQueriesPerSecondSet, nil
 }
 
 func (mq *mergeQueue) process(
@@ -237,8 +245,8 @@ func (mq *mergeQueue) process(
 		return nil
 	}
 
-	lhsQPS := lhsRepl.GetSplitQPS()
-	rhsDesc, rhsStats, rhsQPS, err := mq.requestRangeStats(ctx, lhsDesc.EndKey.AsRawKey())
+	lhsQPS, lhsQPSOK := lhsRepl.GetMaxSplitQPS()
+	rhsDesc, rhsStats, rhsQPS, rhsQPSOK, err := mq.requestRangeStats(ctx, lhsDesc.EndKey.AsRawKey())
 	if err != nil {
 		return err
 	}
@@ -248,10 +256,11 @@ func (mq *mergeQueue) process(
 		return nil
 	}
 
-	// Range was manually split and not expired, so skip merging.
+	// Range was manually split or recently split by load and the sticky bit
+	// has not expired, so skip merging.
 	now := mq.store.Clock().Now()
 	if now.Less(rhsDesc.GetStickyBit()) {
-		log.VEventf(ctx, 2, "skipping merge: ranges were manually split and sticky bit was not expired")
+		log.VEventf(ctx, 2, "skipping merge: ranges were split manually or by load and sticky bit was not expired")
 		// TODO(jeffreyxiao): Consider returning a purgatory error to avoid
 		// repeatedly processing ranges that cannot be merged.
 		return nil
@@ -266,6 +275,21 @@ func (mq *mergeQueue) process(
 
 	var mergedQPS float64
 	if lhsRepl.SplitByLoadEnabled() {
+		// When load is a consideration for splits and, by extension, merges, the
+		// merge queue is conservative. To avoid merging ranges that were recently
+		// split by load, and to avoid reacting to temporary dips in load, it
+		// considers the maximum QPS of each side over the load-based merge
+		// retention period. If either side has not recorded load for that long,
+		// for instance because its lease recently changed hands without carrying
+		// the load history over, the merge is skipped.
+		if !lhsQPSOK {
+			log.VEventf(ctx, 2, "skipping merge: LHS QPS measurement not yet reliable")
+			return nil
+		}
+		if !rhsQPSOK {
+			log.VEventf(ctx, 2, "skipping merge: RHS QPS measurement not yet reliable")
+			return nil
+		}
 		mergedQPS = lhsQPS + rhsQPS
 	}
 
@@ -302,45 +326,27 @@ func (mq *mergeQueue) process(
 	}
 	lhsReplicas, rhsReplicas := lhsDesc.Replicas().All(), rhsDesc.Replicas().All()
 
-	// Defensive sanity check that everything is now a voter.
+	// Defensive sanity check that everything is now a full voter or a
+	// non-voter.
 	for i := range lhsReplicas {
-		if lhsReplicas[i].GetType() != roachpb.VOTER_FULL {
-			return errors.Errorf(`cannot merge non-voter replicas on lhs: %v`, lhsReplicas)
+		if typ := lhsReplicas[i].GetType(); typ != roachpb.VOTER_FULL && typ != roachpb.NON_VOTER {
+			return errors.Errorf(`cannot merge learner or joint replicas on lhs: %v`, lhsReplicas)
 		}
 	}
 	for i := range rhsReplicas {
-		if rhsReplicas[i].GetType() != roachpb.VOTER_FULL {
-			return errors.Errorf(`cannot merge non-voter replicas on rhs: %v`, rhsReplicas)
+		if typ := rhsReplicas[i].GetType(); typ != roachpb.VOTER_FULL && typ != roachpb.NON_VOTER {
+			return errors.Errorf(`cannot merge learner or joint replicas on rhs: %v`, rhsReplicas)
 		}
 	}
 
 	if !replicaSetsEqual(lhsReplicas, rhsReplicas) {
-		var targets []roachpb.ReplicationTarget
-		for _, lhsReplDesc := range lhsReplicas {
-			targets = append(targets, roachpb.ReplicationTarget{
-				NodeID: lhsReplDesc.NodeID, StoreID: lhsReplDesc.StoreID,
-			})
-		}
-		// AdminRelocateRange moves the lease to the first target in the list, so
-		// sort the existing leaseholder there to leave it unchanged.
-		lease, _ := lhsRepl.GetLease()
-		for i := range targets {
-			if targets[i].NodeID == lease.Replica.NodeID && targets[i].StoreID == lease.Replica.StoreID {
-				if i > 0 {
-					targets[0], targets[i] = targets[i], targets[0]
-				}
-				break
-			}
-		}
-		// TODO(benesch): RelocateRange can sometimes fail if it needs to move a replica
-		// from one store to another store on the same node.
-		if err := mq.store.DB().AdminRelocateRange(ctx, rhsDesc.StartKey, targets); err != nil {
+		if err := mq.collocateReplicas(ctx, lhsRepl, lhsDesc, rhsDesc); err != nil {
 			return err
 		}
 	}
 
 	log.VEventf(ctx, 2, "merging to produce range: %s-%s", mergedDesc.StartKey, mergedDesc.EndKey)
-	reason := fmt.Sprintf("lhs+rhs has (size=%s+%s=%s qps=%.2f+%.2f=%.2fqps) below threshold (size=%s, qps=%.2f)",
+	details := fmt.Sprintf("lhs+rhs has (size=%s+%s=%s qps=%.2f+%.2f=%.2fqps) below threshold (size=%s, qps=%.2f)",
 		humanizeutil.IBytes(lhsStats.Total()),
 		humanizeutil.IBytes(rhsStats.Total()),
 		humanizeutil.IBytes(mergedStats.Total()),
@@ -352,7 +358,7 @@ func (mq *mergeQueue) process(
 	)
 	_, pErr := lhsRepl.AdminMerge(ctx, roachpb.AdminMergeRequest{
 		RequestHeader: roachpb.RequestHeader{Key: lhsRepl.Desc().StartKey.AsRawKey()},
-	}, reason)
+	}, storagepb.ReasonRangeUnderutilized, details)
 	switch err := pErr.GoError(); err.(type) {
 	case nil:
 	case *roachpb.ConditionFailedError:
@@ -375,6 +381,90 @@ func (mq *mergeQueue) process(
 	return nil
 }
 
+// collocateReplicas moves the replicas of the RHS range so that they match
+// the replicas of the LHS range, which AdminMerge requires: the voters of the
+// RHS are relocated to the stores of the LHS's voters and its non-voters to
+// the stores of the LHS's non-voters.
+//
+// AdminRelocateRange only knows how to move voters, so if the voters need to
+// be relocated all of the RHS's non-voters are removed first. Otherwise, only
+// the non-voters that the LHS doesn't have are removed. The missing non-voters
+// are added once the voters are in place.
+func (mq *mergeQueue) collocateReplicas(
+	ctx context.Context, lhsRepl *Replica, lhsDesc, rhsDesc *roachpb.RangeDescriptor,
+) error {
+	lhsVoters, lhsNonVoters := lhsDesc.Replicas().Voters(), lhsDesc.Replicas().NonVoters()
+	votersEqual := replicaSetsEqual(lhsVoters, rhsDesc.Replicas().Voters())
+	startKey := rhsDesc.StartKey.AsRawKey()
+
+	// NB: unroll the non-voter changes because at the time of writing, we
+	// can't atomically add or remove multiple learners, which is what
+	// non-voters are from raft's point of view.
+	changeNonVoter := func(
+		desc *roachpb.RangeDescriptor, changeType roachpb.ReplicaChangeType, rDesc roachpb.ReplicaDescriptor,
+	) (*roachpb.RangeDescriptor, error) {
+		target := roachpb.ReplicationTarget{NodeID: rDesc.NodeID, StoreID: rDesc.StoreID}
+		return mq.store.DB().AdminChangeReplicas(
+			ctx, startKey, *desc, roachpb.MakeReplicationChanges(changeType, target),
+		)
+	}
+
+	for _, rDesc := range rhsDesc.Replicas().NonVoters() {
+		if votersEqual && storeHasReplica(rDesc.StoreID, lhsNonVoters) {
+			continue
+		}
+		var err error
+		if rhsDesc, err = changeNonVoter(rhsDesc, roachpb.REMOVE_NON_VOTER, rDesc); err != nil {
+			return err
+		}
+	}
+
+	if !votersEqual {
+		var targets []roachpb.ReplicationTarget
+		for _, lhsReplDesc := range lhsVoters {
+			targets = append(targets, roachpb.ReplicationTarget{
+				NodeID: lhsReplDesc.NodeID, StoreID: lhsReplDesc.StoreID,
+			})
+		}
+		// AdminRelocateRange moves the lease to the first target in the list, so
+		// sort the existing leaseholder there to leave it unchanged.
+		lease, _ := lhsRepl.GetLease()
+		for i := range targets {
+			if targets[i].NodeID == lease.Replica.NodeID && targets[i].StoreID == lease.Replica.StoreID {
+				if i > 0 {
+					targets[0], targets[i] = targets[i], targets[0]
+				}
+				break
+			}
+		}
+		// TODO(benesch): RelocateRange can sometimes fail if it needs to move a replica
+		// from one store to another store on the same node.
+		if err := mq.store.DB().AdminRelocateRange(ctx, startKey, targets); err != nil {
+			return err
+		}
+		if len(lhsNonVoters) == 0 {
+			return nil
+		}
+		// Fetch the descriptor that resulted from the relocation, which the
+		// non-voter additions below are conditional on.
+		var err error
+		if rhsDesc, _, _, _, err = mq.requestRangeStats(ctx, startKey); err != nil {
+			return err
+		}
+	}
+
+	for _, rDesc := range lhsNonVoters {
+		if storeHasReplica(rDesc.StoreID, rhsDesc.Replicas().NonVoters()) {
+			continue
+		}
+		var err error
+		if rhsDesc, err = changeNonVoter(rhsDesc, roachpb.ADD_NON_VOTER, rDesc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (mq *mergeQueue) timer(time.Duration) time.Duration {
 	return MergeQueueInterval.Get(&mq.store.ClusterSettings().SV)
 }
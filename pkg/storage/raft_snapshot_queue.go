@@ -113,11 +113,18 @@ func (rq *raftSnapshotQueue) processRaftSnapshot(
 	// that's adding it or it's been orphaned and it's about to be cleaned up by
 	// the replicate queue. Either way, no point in also sending it a snapshot of
 	// type RAFT.
-	if repDesc.GetType() == roachpb.LEARNER {
-		if fn := repl.store.cfg.TestingKnobs.ReplicaSkipLearnerSnapshot; fn != nil && fn() {
-			return nil
+	//
+	// The same applies to a NON_VOTER that is in the process of being added,
+	// which also receives a snapshot of type LEARNER from the node adding it.
+	// Unlike learners, non-voters are long-lived, so once that snapshot is done
+	// they receive RAFT snapshots like any other replica.
+	if typ := repDesc.GetType(); typ == roachpb.LEARNER || typ == roachpb.NON_VOTER {
+		if typ == roachpb.LEARNER {
+			if fn := repl.store.cfg.TestingKnobs.ReplicaSkipLearnerSnapshot; fn != nil && fn() {
+				return nil
+			}
+			snapType = SnapshotRequest_LEARNER
 		}
-		snapType = SnapshotRequest_LEARNER
 		if index := repl.getAndGCSnapshotLogTruncationConstraints(timeutil.Now(), repDesc.StoreID); index > 0 {
 			// There is a snapshot being transferred. It's probably a LEARNER snap, so
 			// bail for now and try again later.
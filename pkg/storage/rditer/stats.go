@@ -18,7 +18,8 @@ import (
 
 // ComputeStatsForRange computes the stats for a given range by
 // iterating over all key ranges for the given range that should
-// be accounted for in its stats.
+// be accounted for in its stats, including the effect of the
+// range's MVCC range tombstones.
 func ComputeStatsForRange(
 	d *roachpb.RangeDescriptor, reader engine.Reader, nowNanos int64,
 ) (enginepb.MVCCStats, error) {
@@ -33,5 +34,14 @@ func ComputeStatsForRange(
 		}
 		ms.Add(msDelta)
 	}
+	// Account for the keys deleted by MVCC range tombstones, which
+	// ComputeStats considers live.
+	userKeys := MakeUserKeyRange(d)
+	msDelta, err := engine.ComputeRangeTombstoneStatsAdjustment(
+		reader, d.RangeID, userKeys.Start.Key, userKeys.End.Key, nowNanos)
+	if err != nil {
+		return enginepb.MVCCStats{}, err
+	}
+	ms.Add(msDelta)
 	return ms, nil
 }
@@ -30,6 +30,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/batcheval"
 	"github.com/cockroachdb/cockroach/pkg/storage/closedts/ctpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/cockroach/pkg/storage/concurrency"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/storage/rangefeed"
@@ -179,6 +180,8 @@ type Replica struct {
 	store        *Store
 	abortSpan    *abortspan.AbortSpan // Avoids anomalous reads after abort
 	txnWaitQueue *txnwait.Queue       // Queues push txn attempts by txn ID
+	// Tracks the unreplicated locks acquired by locking reads.
+	unreplicatedLocks *concurrency.UnreplicatedLockTable
 
 	// leaseholderStats tracks all incoming BatchRequests to the replica and which
 	// localities they come from in order to aid in lease rebalancing decisions.
@@ -186,6 +189,12 @@ type Replica struct {
 	// writeStats tracks the number of keys written by applied raft commands
 	// in order to aid in replica rebalancing decisions.
 	writeStats *replicaStats
+	// writeBytesStats tracks the number of bytes written by applied raft
+	// commands in order to aid in replica rebalancing decisions.
+	writeBytesStats *replicaStats
+	// cpuStats tracks the number of nanoseconds spent evaluating requests on
+	// the replica in order to aid in lease and replica rebalancing decisions.
+	cpuStats *replicaStats
 
 	// creatingReplica is set when a replica is created as uninitialized
 	// via a raft message.
@@ -669,6 +678,12 @@ func (r *Replica) GetTxnWaitQueue() *txnwait.Queue {
 	return r.txnWaitQueue
 }
 
+// GetLockTableView returns a view of the Replica's unreplicated locks for a
+// scan performed by the provided transaction.
+func (r *Replica) GetLockTableView(txn *enginepb.TxnMeta) engine.LockTableView {
+	return r.unreplicatedLocks.View(txn)
+}
+
 // GetTerm returns the term of the given index in the raft log.
 func (r *Replica) GetTerm(i uint64) (uint64, error) {
 	r.mu.RLock()
@@ -769,6 +784,17 @@ func (r *Replica) GetSplitQPS() float64 {
 	return r.loadBasedSplitter.LastQPS(timeutil.Now())
 }
 
+// GetMaxSplitQPS returns the Replica's maximum queries/s request rate over a
+// configured retention period. If the Replica has not been recording load for
+// long enough, for instance because it only recently acquired its lease, false
+// is returned.
+//
+// NOTE: This should only be used for load based splitting, only
+// works when the load based splitting cluster setting is enabled.
+func (r *Replica) GetMaxSplitQPS() (float64, bool) {
+	return r.loadBasedSplitter.MaxQPS(timeutil.Now())
+}
+
 // ContainsKey returns whether this range contains the specified key.
 //
 // TODO(bdarnell): This is not the same as RangeDescriptor.ContainsKey.
@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/storagepb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 )
 
 // replica_application_*.go files provide concrete implementations of
@@ -256,8 +257,15 @@ func (r *Replica) handleDescResult(ctx context.Context, desc *roachpb.RangeDescr
 	r.setDescRaftMuLocked(ctx, desc)
 }
 
-func (r *Replica) handleLeaseResult(ctx context.Context, lease *roachpb.Lease) {
+func (r *Replica) handleLeaseResult(
+	ctx context.Context, lease *roachpb.Lease, priorMaxSplitQPS float64,
+) {
 	r.leasePostApply(ctx, *lease, false /* permitJump */)
+	if priorMaxSplitQPS > 0 && lease.Replica.ReplicaID == r.ReplicaID() {
+		// The lease was transferred to this replica. Carry over the load
+		// history of the previous leaseholder, which leasePostApply discarded.
+		r.loadBasedSplitter.ResetMaxQPS(timeutil.Now(), priorMaxSplitQPS)
+	}
 }
 
 func (r *Replica) handleTruncatedStateResult(
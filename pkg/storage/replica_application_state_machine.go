@@ -361,6 +361,10 @@ type replicaAppBatch struct {
 
 	// batch accumulates writes implied by the raft entries in this batch.
 	batch engine.Batch
+	// raftBatch accumulates writes to the store's Raft engine, if it is
+	// separate from the engine holding the replica state. It is committed
+	// after batch. See raftEngineWriter.
+	raftBatch engine.Batch
 	// state is this batch's view of the replica's state. It is copied from
 	// under the Replica.mu when the batch is initialized and is updated in
 	// stageTrivialReplicatedEvalResult.
@@ -385,6 +389,7 @@ type replicaAppBatch struct {
 	entries      int
 	emptyEntries int
 	mutations    int
+	writeBytes   int
 	start        time.Time
 }
 
@@ -520,6 +525,7 @@ func (b *replicaAppBatch) stageWriteBatch(ctx context.Context, cmd *replicatedCm
 	} else {
 		b.mutations += mutations
 	}
+	b.writeBytes += len(wb.Data)
 	if err := b.batch.ApplyBatchRepr(wb.Data, false); err != nil {
 		return wrapWithNonDeterministicFailure(err, "unable to apply WriteBatch")
 	}
@@ -600,6 +606,7 @@ func (b *replicaAppBatch) runPreApplyTriggersAfterStagingWriteBatch(
 		if added := res.Delta.KeyCount; added > 0 {
 			b.r.writeStats.recordCount(float64(added), 0)
 		}
+		b.r.writeBytesStats.recordCount(float64(len(res.AddSSTable.Data)), 0)
 		res.AddSSTable = nil
 	}
 
@@ -644,11 +651,19 @@ func (b *replicaAppBatch) runPreApplyTriggersAfterStagingWriteBatch(
 		); err != nil {
 			return wrapWithNonDeterministicFailure(err, "unable to destroy replica before merge")
 		}
+		if b.r.store.hasSeparateRaftEngine() {
+			if err := clearRaftState(
+				b.r.store.raftEngine, b.raftEngineWriter(), rhsRepl.RangeID,
+			); err != nil {
+				return wrapWithNonDeterministicFailure(err, "unable to clear Raft state before merge")
+			}
+		}
 	}
 
 	if res.State != nil && res.State.TruncatedState != nil {
 		if apply, err := handleTruncatedStateBelowRaft(
 			ctx, b.state.TruncatedState, res.State.TruncatedState, b.r.raftMu.stateLoader, b.batch,
+			b.raftEngineWriter(),
 		); err != nil {
 			return wrapWithNonDeterministicFailure(err, "unable to handle truncated state")
 		} else if !apply {
@@ -820,13 +835,23 @@ func (b *replicaAppBatch) ApplyToStateMachine(ctx context.Context) error {
 	// applied again upon startup. However, if we're removing the replica's data
 	// then we sync this batch as it is not safe to call postDestroyRaftMuLocked
 	// before ensuring that the replica's data has been synchronously removed.
-	// See handleChangeReplicasResult().
-	sync := b.changeRemovesReplica
+	// See handleChangeReplicasResult(). Similarly, if the store keeps its Raft
+	// log in a separate engine, the batch is synced before Raft log entries
+	// are removed from that engine, since they could otherwise be needed to
+	// reapply the batch.
+	sync := b.changeRemovesReplica || b.raftBatch != nil
 	if err := b.batch.Commit(sync); err != nil {
 		return wrapWithNonDeterministicFailure(err, "unable to commit Raft entry batch")
 	}
 	b.batch.Close()
 	b.batch = nil
+	if b.raftBatch != nil {
+		if err := b.raftBatch.Commit(false /* sync */); err != nil {
+			return wrapWithNonDeterministicFailure(err, "unable to commit Raft engine batch")
+		}
+		b.raftBatch.Close()
+		b.raftBatch = nil
+	}
 
 	// Update the replica's applied indexes and mvcc stats.
 	r.mu.Lock()
@@ -846,8 +871,10 @@ func (b *replicaAppBatch) ApplyToStateMachine(ctx context.Context) error {
 	r.store.metrics.addMVCCStats(deltaStats)
 
 	// Record the write activity, passing a 0 nodeID because replica.writeStats
-	// intentionally doesn't track the origin of the writes.
+	// and replica.writeBytesStats intentionally don't track the origin of the
+	// writes.
 	b.r.writeStats.recordCount(float64(b.mutations), 0 /* nodeID */)
+	b.r.writeBytesStats.recordCount(float64(b.writeBytes), 0 /* nodeID */)
 
 	// NB: the bootstrap store has a nil split queue.
 	// TODO(tbg): the above is probably a lie now.
@@ -926,11 +953,28 @@ func (b *replicaAppBatch) recordStatsOnCommit() {
 	b.r.store.metrics.RaftCommandCommitLatency.RecordValue(elapsed.Nanoseconds())
 }
 
+// raftEngineWriter returns the writer through which the batch updates the
+// Raft log and HardState. Unless the store keeps these in a separate engine,
+// this is the batch itself; otherwise, a second batch is lazily created which
+// is committed once the first one has been committed (and synced).
+func (b *replicaAppBatch) raftEngineWriter() engine.Writer {
+	if !b.r.store.hasSeparateRaftEngine() {
+		return b.batch
+	}
+	if b.raftBatch == nil {
+		b.raftBatch = b.r.store.raftEngine.NewWriteOnlyBatch()
+	}
+	return b.raftBatch
+}
+
 // Close implements the apply.Batch interface.
 func (b *replicaAppBatch) Close() {
 	if b.batch != nil {
 		b.batch.Close()
 	}
+	if b.raftBatch != nil {
+		b.raftBatch.Close()
+	}
 	*b = replicaAppBatch{}
 }
 
@@ -1120,8 +1164,9 @@ func (sm *replicaStateMachine) handleNonTrivialReplicatedEvalResult(
 		}
 
 		if newLease := rResult.State.Lease; newLease != nil {
-			sm.r.handleLeaseResult(ctx, newLease)
+			sm.r.handleLeaseResult(ctx, newLease, rResult.PriorMaxSplitQPS)
 			rResult.State.Lease = nil
+			rResult.PriorMaxSplitQPS = 0
 		}
 
 		if newThresh := rResult.State.GCThreshold; newThresh != nil {
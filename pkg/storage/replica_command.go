@@ -47,9 +47,13 @@ import (
 	"go.etcd.io/etcd/raft/tracker"
 )
 
-// AdminSplit divides the range into into two ranges using args.SplitKey.
+// AdminSplit divides the range into into two ranges using args.SplitKey. The
+// reason and details are recorded in the range log.
 func (r *Replica) AdminSplit(
-	ctx context.Context, args roachpb.AdminSplitRequest, reason string,
+	ctx context.Context,
+	args roachpb.AdminSplitRequest,
+	reason storagepb.RangeLogEventReason,
+	details string,
 ) (reply roachpb.AdminSplitResponse, _ *roachpb.Error) {
 	if len(args.SplitKey) == 0 {
 		return roachpb.AdminSplitResponse{}, roachpb.NewErrorf("cannot split range with no key provided")
@@ -57,7 +61,7 @@ func (r *Replica) AdminSplit(
 
 	err := r.executeAdminCommandWithDescriptor(ctx, func(desc *roachpb.RangeDescriptor) error {
 		var err error
-		reply, err = r.adminSplitWithDescriptor(ctx, args, desc, true /* delayable */, reason)
+		reply, err = r.adminSplitWithDescriptor(ctx, args, desc, true /* delayable */, reason, details)
 		return err
 	})
 	return reply, err
@@ -168,6 +172,8 @@ func splitTxnAttempt(
 	splitKey roachpb.RKey,
 	expiration hlc.Timestamp,
 	oldDesc *roachpb.RangeDescriptor,
+	reason storagepb.RangeLogEventReason,
+	details string,
 ) error {
 	txn.SetDebugName(splitTxnName)
 
@@ -205,7 +211,7 @@ func splitTxnAttempt(
 	}
 
 	// Log the split into the range event log.
-	if err := store.logSplit(ctx, txn, *leftDesc, *rightDesc); err != nil {
+	if err := store.logSplit(ctx, txn, *leftDesc, *rightDesc, reason, details); err != nil {
 		return err
 	}
 
@@ -285,6 +291,8 @@ func splitTxnStickyUpdateAttempt(
 // affirmative the descriptor is passed to AdminSplit, which performs a
 // Conditional Put on the RangeDescriptor to ensure that no other operation has
 // modified the range in the time the decision was being made.
+//
+// The reason and details of the split are recorded in the range log.
 // TODO(tschottdorf): should assert that split key is not a local key.
 //
 // See the comment on splitTrigger for details on the complexities.
@@ -293,7 +301,8 @@ func (r *Replica) adminSplitWithDescriptor(
 	args roachpb.AdminSplitRequest,
 	desc *roachpb.RangeDescriptor,
 	delayable bool,
-	reason string,
+	reason storagepb.RangeLogEventReason,
+	details string,
 ) (roachpb.AdminSplitResponse, error) {
 	var err error
 	// The split queue doesn't care about the set of replicas, so if we somehow
@@ -394,10 +403,10 @@ func (r *Replica) adminSplitWithDescriptor(
 	extra += splitSnapshotWarningStr(r.RangeID, r.RaftStatus())
 
 	log.Infof(ctx, "initiating a split of this range at key %s [r%d] (%s)%s",
-		splitKey.StringWithDirs(nil /* valDirs */, 50 /* maxLen */), rightRangeID, reason, extra)
+		splitKey.StringWithDirs(nil /* valDirs */, 50 /* maxLen */), rightRangeID, details, extra)
 
 	if err := r.store.DB().Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
-		return splitTxnAttempt(ctx, r.store, txn, rightRangeID, splitKey, args.ExpirationTime, desc)
+		return splitTxnAttempt(ctx, r.store, txn, rightRangeID, splitKey, args.ExpirationTime, desc, reason, details)
 	}); err != nil {
 		// The ConditionFailedError can occur because the descriptors acting
 		// as expected values in the CPuts used to update the left or right
@@ -556,12 +565,16 @@ func (r *Replica) executeAdminCommandWithDescriptor(
 // reassigned key range is carried out seamlessly through a merge
 // trigger carried out as part of the commit of that transaction. A
 // merge requires that the two ranges are collocated on the same set
-// of replicas.
+// of replicas. The reason and details of the merge are recorded in the
+// range log.
 //
 // The supplied RangeDescriptor is used as a form of optimistic lock. See the
 // comment of "AdminSplit" for more information on this pattern.
 func (r *Replica) AdminMerge(
-	ctx context.Context, args roachpb.AdminMergeRequest, reason string,
+	ctx context.Context,
+	args roachpb.AdminMergeRequest,
+	reason storagepb.RangeLogEventReason,
+	details string,
 ) (roachpb.AdminMergeResponse, *roachpb.Error) {
 	var reply roachpb.AdminMergeResponse
 
@@ -625,9 +638,10 @@ func (r *Replica) AdminMerge(
 		}
 		// For simplicity, don't handle learner replicas or joint states, expect
 		// the caller to resolve them first. (Defensively, we check that there
-		// are no non-voter replicas, in case some third type is later added).
-		// This behavior can be changed later if the complexity becomes worth
-		// it, but it's not right now.
+		// are only full voters and non-voters, in case some other type is later
+		// added). This behavior can be changed later if the complexity becomes
+		// worth it, but it's not right now. Non-voters are long-lived, so they
+		// are merged like voters as long as both sides agree on them.
 		//
 		// NB: the merge queue transitions out of any joint states and removes
 		// any learners it sees. It's sort of silly that we don't do that here
@@ -636,14 +650,15 @@ func (r *Replica) AdminMerge(
 		// queues should fix things up quickly).
 		lReplicas, rReplicas := origLeftDesc.Replicas(), rightDesc.Replicas()
 
-		predFullVoter := func(rDesc roachpb.ReplicaDescriptor) bool {
-			return rDesc.GetType() == roachpb.VOTER_FULL
+		predFullVoterOrNonVoter := func(rDesc roachpb.ReplicaDescriptor) bool {
+			typ := rDesc.GetType()
+			return typ == roachpb.VOTER_FULL || typ == roachpb.NON_VOTER
 		}
-		if len(lReplicas.Filter(predFullVoter)) != len(lReplicas.All()) {
-			return errors.Errorf("cannot merge range with non-voter replicas on lhs: %s", lReplicas)
+		if len(lReplicas.Filter(predFullVoterOrNonVoter)) != len(lReplicas.All()) {
+			return errors.Errorf("cannot merge range with learner or joint replicas on lhs: %s", lReplicas)
 		}
-		if len(rReplicas.Filter(predFullVoter)) != len(rReplicas.All()) {
-			return errors.Errorf("cannot merge range with non-voter replicas on rhs: %s", rReplicas)
+		if len(rReplicas.Filter(predFullVoterOrNonVoter)) != len(rReplicas.All()) {
+			return errors.Errorf("cannot merge range with learner or joint replicas on rhs: %s", rReplicas)
 		}
 		if !replicaSetsEqual(lReplicas.All(), rReplicas.All()) {
 			return errors.Errorf("ranges not collocated; %s != %s", lReplicas, rReplicas)
@@ -659,7 +674,7 @@ func (r *Replica) AdminMerge(
 		updatedLeftDesc.IncrementGeneration()
 		updatedLeftDesc.GenerationComparable = proto.Bool(true)
 		updatedLeftDesc.EndKey = rightDesc.EndKey
-		log.Infof(ctx, "initiating a merge of %s into this range (%s)", &rightDesc, reason)
+		log.Infof(ctx, "initiating a merge of %s into this range (%s)", &rightDesc, details)
 
 		// Update the range descriptor for the receiving range. It is important
 		// (for transaction record placement) that the first write inside the
@@ -686,7 +701,7 @@ func (r *Replica) AdminMerge(
 		// instead of a transaction; there's no reason this logging
 		// shouldn't be done in parallel via the batch with the updated
 		// range addressing.
-		if err := r.store.logMerge(ctx, txn, updatedLeftDesc, rightDesc); err != nil {
+		if err := r.store.logMerge(ctx, txn, updatedLeftDesc, rightDesc, reason, details); err != nil {
 			return err
 		}
 
@@ -993,7 +1008,15 @@ func (r *Replica) changeReplicasImpl(
 		return nil, err
 	}
 
-	if adds := chgs.Additions(); len(adds) > 0 {
+	if len(chgs.NonVoterAdditions()) > 0 && !cluster.Version.IsActive(
+		ctx, r.ClusterSettings(), cluster.VersionNonVotingReplicas,
+	) {
+		return nil, errors.Errorf(
+			"cannot add non-voting replicas until the cluster version is at least %s",
+			cluster.VersionByKey(cluster.VersionNonVotingReplicas))
+	}
+
+	if adds := append(chgs.Additions(), chgs.NonVoterAdditions()...); len(adds) > 0 {
 		// Lock learner snapshots even before we run the ConfChange txn to add them
 		// to prevent a race with the raft snapshot queue trying to send it first.
 		// Note that this lock needs to cover sending the snapshots which happens in
@@ -1013,7 +1036,13 @@ func (r *Replica) changeReplicasImpl(
 		// don't introduce fragility into the system). For details see:
 		_ = roachpb.ReplicaDescriptors.Learners
 		var err error
-		desc, err = addLearnerReplicas(ctx, r.store, desc, reason, details, adds)
+		desc, err = addLearnerReplicas(ctx, r.store, desc, reason, details, chgs.Additions())
+		if err != nil {
+			return nil, err
+		}
+		// Non-voting replicas are added directly as such, they are caught up
+		// below but never promoted.
+		desc, err = addNonVoterReplicas(ctx, r.store, desc, reason, details, chgs.NonVoterAdditions())
 		if err != nil {
 			return nil, err
 		}
@@ -1039,6 +1068,13 @@ func (r *Replica) changeReplicasImpl(
 				r.tryRollBackLearnerReplica(ctx, r.Desc(), target, reason, details)
 			}
 		}
+		// Similarly, don't leave behind a non-voter that never got its snapshot.
+		if targets := chgs.NonVoterAdditions(); len(targets) > 0 {
+			log.Infof(ctx, "could not add %v as non-voters, rolling back: %v", targets, err)
+			for _, target := range targets {
+				r.tryRollBackLearnerReplica(ctx, r.Desc(), target, reason, details)
+			}
+		}
 		return nil, err
 	}
 	return desc, err
@@ -1130,7 +1166,21 @@ func validateReplicationChanges(
 	for _, rDesc := range desc.Replicas().All() {
 		chg, ok := byNodeID[rDesc.NodeID]
 		delete(byNodeID, rDesc.NodeID)
-		if !ok || chg.ChangeType != roachpb.ADD_REPLICA {
+		if !ok {
+			continue
+		}
+		switch chg.ChangeType {
+		case roachpb.REMOVE_REPLICA:
+			if rDesc.GetType() == roachpb.NON_VOTER {
+				return errors.Errorf(
+					"unable to remove non-voter %v as a voter from %s", chg.Target, desc)
+			}
+			continue
+		case roachpb.REMOVE_NON_VOTER:
+			if rDesc.GetType() != roachpb.NON_VOTER {
+				return errors.Errorf(
+					"unable to remove replica %v which is not a non-voter in %s", chg.Target, desc)
+			}
 			continue
 		}
 		// We're adding a replica that's already there. This isn't allowed, even
@@ -1148,6 +1198,10 @@ func validateReplicationChanges(
 			return errors.Errorf(
 				"unable to add replica %v which is already present as a learner in %s", chg.Target, desc)
 		}
+		if rDesc.GetType() == roachpb.NON_VOTER {
+			return errors.Errorf(
+				"unable to add replica %v which is already present as a non-voter in %s", chg.Target, desc)
+		}
 
 		// Otherwise, we already had a full voter replica. Can't add another to
 		// this store.
@@ -1156,7 +1210,7 @@ func validateReplicationChanges(
 
 	// Any removals left in the map now refer to nonexisting replicas, and we refuse them.
 	for _, chg := range byNodeID {
-		if chg.ChangeType != roachpb.REMOVE_REPLICA {
+		if chg.ChangeType != roachpb.REMOVE_REPLICA && chg.ChangeType != roachpb.REMOVE_NON_VOTER {
 			continue
 		}
 		return errors.Errorf("removing %v which is not in %s", chg.Target, desc)
@@ -1192,6 +1246,29 @@ func addLearnerReplicas(
 	return desc, nil
 }
 
+// addNonVoterReplicas adds non-voting replicas to the given replication
+// targets. Like learners, they are added one at a time.
+func addNonVoterReplicas(
+	ctx context.Context,
+	store *Store,
+	desc *roachpb.RangeDescriptor,
+	reason storagepb.RangeLogEventReason,
+	details string,
+	targets []roachpb.ReplicationTarget,
+) (*roachpb.RangeDescriptor, error) {
+	for _, target := range targets {
+		iChgs := []internalReplicationChange{{target: target, typ: internalChangeTypeAddNonVoter}}
+		var err error
+		desc, err = execChangeReplicasTxn(
+			ctx, store, desc, reason, details, iChgs,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return desc, nil
+}
+
 // lockLearnerSnapshot stops the raft snapshot queue from sending snapshots to
 // the soon-to-be added learner replicas to prevent duplicate snapshots from
 // being sent. This lock is best effort because it times out and it is a node
@@ -1293,6 +1370,24 @@ func (r *Replica) atomicReplicationChange(
 		}
 	}
 
+	// Non-voters have been added as such already and only need to be caught up.
+	// They don't take part in the atomic membership change below.
+	for _, target := range chgs.NonVoterAdditions() {
+		rDesc, ok := desc.GetReplicaDescriptor(target.StoreID)
+		if !ok {
+			return nil, errors.Errorf("programming error: replica %v not found in %v", target, desc)
+		}
+		if rDesc.GetType() != roachpb.NON_VOTER {
+			return nil, errors.Errorf("programming error: cannot catch up replica of type %s as non-voter", rDesc.Type)
+		}
+		if fn := r.store.cfg.TestingKnobs.ReplicaSkipLearnerSnapshot; fn != nil && fn() {
+			continue
+		}
+		if err := r.sendSnapshot(ctx, rDesc, SnapshotRequest_LEARNER, priority); err != nil {
+			return nil, err
+		}
+	}
+
 	if adds := chgs.Additions(); len(adds) > 0 {
 		if fn := r.store.cfg.TestingKnobs.ReplicaAddStopAfterLearnerSnapshot; fn != nil && fn(adds) {
 			return desc, nil
@@ -1307,6 +1402,15 @@ func (r *Replica) atomicReplicationChange(
 		}
 		iChgs = append(iChgs, internalReplicationChange{target: target, typ: typ})
 	}
+	// Non-voters don't have a say in quorum, so they are removed outright.
+	for _, target := range chgs.NonVoterRemovals() {
+		iChgs = append(iChgs, internalReplicationChange{target: target, typ: internalChangeTypeRemove})
+	}
+
+	if len(iChgs) == 0 {
+		// Nothing left to do, which is the case when only non-voters were added.
+		return desc, nil
+	}
 
 	var err error
 	desc, err = execChangeReplicasTxn(ctx, r.store, desc, reason, details, iChgs)
@@ -1324,8 +1428,9 @@ func (r *Replica) atomicReplicationChange(
 }
 
 // tryRollbackLearnerReplica attempts to remove a learner specified by the
-// target. If no such learner is found in the descriptor (including when it is a
-// voter instead), no action is taken. Otherwise, a single time-limited
+// target. Non-voters are treated like learners here since they are rolled
+// back the same way. If no such learner is found in the descriptor (including
+// when it is a voter instead), no action is taken. Otherwise, a single time-limited
 // best-effort attempt at removing the learner is made.
 func (r *Replica) tryRollBackLearnerReplica(
 	ctx context.Context,
@@ -1335,7 +1440,7 @@ func (r *Replica) tryRollBackLearnerReplica(
 	details string,
 ) {
 	repDesc, ok := desc.GetReplicaDescriptor(target.StoreID)
-	if !ok || repDesc.GetType() != roachpb.LEARNER {
+	if typ := repDesc.GetType(); !ok || (typ != roachpb.LEARNER && typ != roachpb.NON_VOTER) {
 		// There's no learner to roll back.
 		log.Event(ctx, "learner to roll back not found; skipping")
 		return
@@ -1372,6 +1477,9 @@ const (
 	_ internalChangeType = iota + 1
 	internalChangeTypeAddLearner
 	internalChangeTypePromoteLearner
+	// internalChangeTypeAddNonVoter adds a NON_VOTER replica. Non-voters are
+	// learners from raft's point of view, but are never promoted.
+	internalChangeTypeAddNonVoter
 	// internalChangeTypeDemote changes a voter to a learner. This will
 	// necessarily go through joint consensus since it requires two individual
 	// changes (only one changes the quorum, so we could allow it in a simple
@@ -1434,6 +1542,9 @@ func prepareChangeReplicasTrigger(
 			case internalChangeTypeAddLearner:
 				added = append(added,
 					updatedDesc.AddReplica(chg.target.NodeID, chg.target.StoreID, roachpb.LEARNER))
+			case internalChangeTypeAddNonVoter:
+				added = append(added,
+					updatedDesc.AddReplica(chg.target.NodeID, chg.target.StoreID, roachpb.NON_VOTER))
 			case internalChangeTypePromoteLearner:
 				typ := roachpb.VOTER_FULL
 				if useJoint {
@@ -1450,7 +1561,7 @@ func prepareChangeReplicasTrigger(
 					return nil, errors.Errorf("target %s not found", chg.target)
 				}
 				prevTyp := rDesc.GetType()
-				if !useJoint || prevTyp == roachpb.LEARNER {
+				if !useJoint || prevTyp == roachpb.LEARNER || prevTyp == roachpb.NON_VOTER {
 					rDesc, _ = updatedDesc.RemoveReplica(chg.target.NodeID, chg.target.StoreID)
 				} else if prevTyp != roachpb.VOTER_FULL {
 					// NB: prevTyp is already known to be VOTER_FULL because of
@@ -1893,19 +2004,25 @@ func (r *Replica) sendSnapshot(
 }
 
 // replicaSetsEqual is used in AdminMerge to ensure that the ranges are
-// all collocate on the same set of replicas.
+// all collocate on the same set of replicas. Replicas on the same store are
+// only considered equal if they are of the same type, so that a voter on one
+// side is never matched up with a non-voter on the other.
 func replicaSetsEqual(a, b []roachpb.ReplicaDescriptor) bool {
 	if len(a) != len(b) {
 		return false
 	}
 
-	set := make(map[roachpb.StoreID]int)
+	type storeAndType struct {
+		storeID roachpb.StoreID
+		typ     roachpb.ReplicaType
+	}
+	set := make(map[storeAndType]int)
 	for _, replica := range a {
-		set[replica.StoreID]++
+		set[storeAndType{replica.StoreID, replica.GetType()}]++
 	}
 
 	for _, replica := range b {
-		set[replica.StoreID]--
+		set[storeAndType{replica.StoreID, replica.GetType()}]--
 	}
 
 	for _, value := range set {
@@ -2164,6 +2281,11 @@ func (s *Store) relocateOne(
 	ctx context.Context, desc *roachpb.RangeDescriptor, targets []roachpb.ReplicationTarget,
 ) ([]roachpb.ReplicationChange, *roachpb.ReplicationTarget, error) {
 	rangeReplicas := desc.Replicas().All()
+	if len(desc.Replicas().NonVoters()) > 0 {
+		// Relocation only knows how to move voters around.
+		return nil, nil, errors.Errorf(
+			`unable to relocate range %s with non-voting replicas: %v`, desc, desc.Replicas())
+	}
 	if len(rangeReplicas) != len(desc.Replicas().Voters()) {
 		// The caller removed all the learners, so there shouldn't be anything but
 		// voters.
@@ -2260,7 +2382,9 @@ func (s *Store) relocateOne(
 			storeList,
 			zone,
 			rangeReplicas,
-			s.allocator.scorerOptions())
+			nil, /* existingNonVoters */
+			s.allocator.scorerOptions(),
+			voterTarget)
 		if targetStore == nil {
 			return nil, nil, fmt.Errorf("none of the remaining targets %v are legal additions to %v",
 				addTargets, desc.Replicas())
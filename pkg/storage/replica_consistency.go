@@ -85,7 +85,9 @@ type ReplicaChecksum struct {
 //
 // When args.Mode is CHECK_VIA_QUEUE and an inconsistency is detected and no
 // diff was requested, the consistency check will be re-run to collect a diff,
-// which is then printed before calling `log.Fatal`. This behavior should be
+// which is then printed and written to a ConsistencyDiffReport before the
+// minority replicas call `log.Fatal` (or are quarantined, if
+// server.consistency_check.quarantine.enabled is set). This behavior should be
 // lifted to the consistency checker queue in the future.
 func (r *Replica) CheckConsistency(
 	ctx context.Context, args roachpb.CheckConsistencyRequest,
@@ -139,12 +141,23 @@ func (r *Replica) CheckConsistency(
 
 	if minoritySHA != "" {
 		var buf bytes.Buffer
+		report := &ConsistencyDiffReport{
+			RangeID:  r.RangeID,
+			StartKey: r.Desc().StartKey,
+			EndKey:   r.Desc().EndKey,
+			Time:     timeutil.Now(),
+		}
 		for sha, idxs := range shaToIdxs {
 			minority := ""
 			if sha == minoritySHA {
 				minority = " [minority]"
 			}
 			for _, idx := range idxs {
+				report.Checksums = append(report.Checksums, ConsistencyDiffChecksum{
+					Replica:  results[idx].Replica,
+					Checksum: fmt.Sprintf("%x", sha),
+					Minority: sha == minoritySHA,
+				})
 				_, _ = fmt.Fprintf(&buf, "%s: checksum %x%s\n"+
 					"- stats: %+v\n"+
 					"- stats.Sub(recomputation): %+v\n",
@@ -164,11 +177,24 @@ func (r *Replica) CheckConsistency(
 				}
 				_, _ = fmt.Fprintf(&buf, "====== diff(%x, [minority]) ======\n", sha)
 				_, _ = diff.WriteTo(&buf)
+				report.Diffs = append(report.Diffs, makeConsistencyDiff(
+					results[shaToIdxs[sha][0]].Replica, results[shaToIdxs[minoritySHA][0]].Replica, diff,
+				))
 			}
 		}
 
 		if isQueue {
 			log.Error(ctx, buf.String())
+			// Also persist the diff in a structured form, which outlives the
+			// log files and can be rendered with `cockroach debug
+			// consistency-diff`.
+			if len(report.Diffs) > 0 {
+				if path, err := writeConsistencyDiffReport(r.store.engine, report); err != nil {
+					log.Warningf(ctx, "unable to write consistency diff report: %+v", err)
+				} else {
+					log.Errorf(ctx, "wrote consistency diff report to %s", path)
+				}
+			}
 		}
 		res.Detail += buf.String()
 	} else {
@@ -282,7 +308,11 @@ func (r *Replica) CheckConsistency(
 	for _, idxs := range shaToIdxs[minoritySHA] {
 		args.Terminate = append(args.Terminate, results[idxs].Replica)
 	}
-	log.Errorf(ctx, "consistency check failed; fetching details and shutting down minority %v", args.Terminate)
+	if ConsistencyCheckQuarantine.Get(&r.store.ClusterSettings().SV) {
+		log.Errorf(ctx, "consistency check failed; fetching details and quarantining minority %v", args.Terminate)
+	} else {
+		log.Errorf(ctx, "consistency check failed; fetching details and shutting down minority %v", args.Terminate)
+	}
 
 	// We've noticed in practice that if the snapshot diff is large, the log
 	// file in it is promptly rotated away, so up the limits while the diff
@@ -571,6 +601,15 @@ func (r *Replica) sha512(
 			}
 			ms.Add(spanMS)
 		}
+		// Account for the keys deleted by MVCC range tombstones, which
+		// ComputeStatsGo considers live.
+		userKeys := rditer.MakeUserKeyRange(&desc)
+		adjMS, err := engine.ComputeRangeTombstoneStatsAdjustment(
+			snap, desc.RangeID, userKeys.Start.Key, userKeys.End.Key, 0 /* nowNanos */)
+		if err != nil {
+			return nil, err
+		}
+		ms.Add(adjMS)
 	}
 
 	var result replicaHash
@@ -12,12 +12,18 @@ package storage
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
+	"path/filepath"
+	"sort"
+	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/pkg/errors"
 )
 
 // ReplicaSnapshotDiff is a part of a []ReplicaSnapshotDiff which represents a diff between
@@ -79,6 +85,175 @@ func (rsds ReplicaSnapshotDiffSlice) String() string {
 	return buf.String()
 }
 
+// consistencyDiffDir is the name of the directory, relative to a store's
+// auxiliary directory, that consistency diff reports are written to.
+const consistencyDiffDir = "consistency-diffs"
+
+// ConsistencyDiffReport is the structured record of an inconsistency found by
+// the consistency checker. It is written, as JSON, to the auxiliary directory
+// of the store that ran the check (see writeConsistencyDiffReport) and can be
+// rendered with `cockroach debug consistency-diff`.
+type ConsistencyDiffReport struct {
+	RangeID  roachpb.RangeID `json:"range_id"`
+	StartKey roachpb.RKey    `json:"start_key"`
+	EndKey   roachpb.RKey    `json:"end_key"`
+	// Time is the time at which the report was created.
+	Time time.Time `json:"time"`
+	// Checksums contains the checksum computed by each replica.
+	Checksums []ConsistencyDiffChecksum `json:"checksums"`
+	// Diffs contains, for each checksum that disagrees with that of the
+	// minority, the diff between the data of a replica that computed it and
+	// that of a minority replica.
+	Diffs []ConsistencyDiff `json:"diffs"`
+}
+
+// ConsistencyDiffChecksum is the checksum a replica computed during a
+// consistency check.
+type ConsistencyDiffChecksum struct {
+	Replica  roachpb.ReplicaDescriptor `json:"replica"`
+	Checksum string                    `json:"checksum"`
+	Minority bool                      `json:"minority"`
+}
+
+// ConsistencyDiff is the diff between the data of two replicas. Entries that
+// are only present on Replica are marked with LeaseHolder, matching the
+// convention of ReplicaSnapshotDiff.
+type ConsistencyDiff struct {
+	Replica  roachpb.ReplicaDescriptor `json:"replica"`
+	Minority roachpb.ReplicaDescriptor `json:"minority"`
+	KVs      []ConsistencyDiffKV       `json:"kvs"`
+}
+
+// ConsistencyDiffKV is a ReplicaSnapshotDiff along with the pretty-printed
+// forms of its key and value, which spare readers of the report from having
+// to decode them by hand.
+type ConsistencyDiffKV struct {
+	LeaseHolder bool          `json:"leaseholder"`
+	Key         roachpb.Key   `json:"key"`
+	Timestamp   hlc.Timestamp `json:"timestamp"`
+	Value       []byte        `json:"value"`
+	PrettyKey   string        `json:"pretty_key"`
+	PrettyValue string        `json:"pretty_value"`
+}
+
+// makeConsistencyDiff converts a ReplicaSnapshotDiffSlice into a
+// ConsistencyDiff.
+func makeConsistencyDiff(
+	replica, minority roachpb.ReplicaDescriptor, diff ReplicaSnapshotDiffSlice,
+) ConsistencyDiff {
+	cd := ConsistencyDiff{Replica: replica, Minority: minority}
+	for _, d := range diff {
+		kv := engine.MVCCKeyValue{
+			Key:   engine.MVCCKey{Key: d.Key, Timestamp: d.Timestamp},
+			Value: d.Value,
+		}
+		cd.KVs = append(cd.KVs, ConsistencyDiffKV{
+			LeaseHolder: d.LeaseHolder,
+			Key:         d.Key,
+			Timestamp:   d.Timestamp,
+			Value:       d.Value,
+			PrettyKey:   keys.PrettyPrint(nil /* valDirs */, d.Key),
+			PrettyValue: SprintKeyValue(kv, false /* printKey */),
+		})
+	}
+	return cd
+}
+
+// SnapshotDiff returns the ReplicaSnapshotDiffSlice the diff was created from.
+func (cd ConsistencyDiff) SnapshotDiff() ReplicaSnapshotDiffSlice {
+	diff := make(ReplicaSnapshotDiffSlice, 0, len(cd.KVs))
+	for _, kv := range cd.KVs {
+		diff = append(diff, ReplicaSnapshotDiff{
+			LeaseHolder: kv.LeaseHolder,
+			Key:         kv.Key,
+			Timestamp:   kv.Timestamp,
+			Value:       kv.Value,
+		})
+	}
+	return diff
+}
+
+// WriteTo writes a human-readable representation of the report to the given
+// writer. The diffs are rendered in the format used by the consistency checker
+// for its log messages.
+func (r *ConsistencyDiffReport) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	_, _ = fmt.Fprintf(&buf, "r%d [%s,%s) inconsistent at %s\n",
+		r.RangeID, r.StartKey, r.EndKey, r.Time.UTC().Format(time.RFC3339Nano))
+	for _, c := range r.Checksums {
+		minority := ""
+		if c.Minority {
+			minority = " [minority]"
+		}
+		_, _ = fmt.Fprintf(&buf, "%s: checksum %s%s\n", c.Replica, c.Checksum, minority)
+	}
+	for _, d := range r.Diffs {
+		_, _ = fmt.Fprintf(&buf, "====== diff(%s, [minority] %s) ======\n", d.Replica, d.Minority)
+		if _, err := d.SnapshotDiff().WriteTo(&buf); err != nil {
+			return 0, err
+		}
+	}
+	return buf.WriteTo(w)
+}
+
+// writeConsistencyDiffReport writes the report to a new file in the
+// consistency diff directory below the engine's auxiliary directory and returns
+// its path. The file is written through the engine so that, on encrypted
+// stores, it is encrypted like the data it contains.
+func writeConsistencyDiffReport(eng engine.Engine, r *ConsistencyDiffReport) (string, error) {
+	dir := filepath.Join(eng.GetAuxiliaryDir(), consistencyDiffDir)
+	if err := eng.CreateDir(dir); err != nil {
+		return "", err
+	}
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("r%d_%s.json", r.RangeID, r.Time.UTC().Format("20060102T150405.000000000Z"))
+	path := filepath.Join(dir, name)
+	if err := eng.WriteFile(path, b); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// ListConsistencyDiffReports returns the paths of the reports written by the
+// consistency checker to the given engine.
+func ListConsistencyDiffReports(eng engine.Engine) ([]string, error) {
+	dir := filepath.Join(eng.GetAuxiliaryDir(), consistencyDiffDir)
+	names, err := eng.ListDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, name := range names {
+		if filepath.Ext(name) == ".json" {
+			paths = append(paths, filepath.Join(dir, name))
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// ReadConsistencyDiffReport reads a report written by the consistency checker
+// through the given engine. The report is identified either by its path or by
+// its name within the engine's consistency diff directory.
+func ReadConsistencyDiffReport(eng engine.Engine, name string) (*ConsistencyDiffReport, error) {
+	path := name
+	if filepath.Base(name) == name {
+		path = filepath.Join(eng.GetAuxiliaryDir(), consistencyDiffDir, name)
+	}
+	b, err := eng.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var r ConsistencyDiffReport
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, errors.Wrapf(err, "%s is not a consistency diff report", path)
+	}
+	return &r, nil
+}
+
 // diffs the two kv dumps between the lease holder and the replica.
 func diffRange(l, r *roachpb.RaftSnapshotData) ReplicaSnapshotDiffSlice {
 	if l == nil || r == nil {
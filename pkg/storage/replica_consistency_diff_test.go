@@ -0,0 +1,76 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConsistencyDiffReport verifies that a ConsistencyDiffReport survives
+// being written to and read back through an engine, and that its rendering
+// contains the diff as the consistency checker logs it.
+func TestConsistencyDiffReport(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	eng := engine.NewDefaultInMem()
+	defer eng.Close()
+
+	leaseholder := roachpb.ReplicaDescriptor{NodeID: 1, StoreID: 1, ReplicaID: 1}
+	follower := roachpb.ReplicaDescriptor{NodeID: 2, StoreID: 2, ReplicaID: 2}
+	diff := ReplicaSnapshotDiffSlice{
+		{LeaseHolder: true, Key: roachpb.Key("a"), Timestamp: hlc.Timestamp{WallTime: 123}, Value: []byte("x")},
+		{LeaseHolder: false, Key: roachpb.Key("a"), Timestamp: hlc.Timestamp{WallTime: 123}, Value: []byte("y")},
+	}
+	report := &ConsistencyDiffReport{
+		RangeID:  7,
+		StartKey: roachpb.RKey("a"),
+		EndKey:   roachpb.RKey("b"),
+		Time:     time.Unix(0, 123).UTC(),
+		Checksums: []ConsistencyDiffChecksum{
+			{Replica: leaseholder, Checksum: "aa"},
+			{Replica: follower, Checksum: "bb", Minority: true},
+		},
+		Diffs: []ConsistencyDiff{makeConsistencyDiff(leaseholder, follower, diff)},
+	}
+	require.Equal(t, `"a"`, report.Diffs[0].KVs[0].PrettyKey)
+	require.Equal(t, diff, report.Diffs[0].SnapshotDiff())
+
+	path, err := writeConsistencyDiffReport(eng, report)
+	require.NoError(t, err)
+	paths, err := ListConsistencyDiffReports(eng)
+	require.NoError(t, err)
+	require.Equal(t, []string{path}, paths)
+	read, err := ReadConsistencyDiffReport(eng, path)
+	require.NoError(t, err)
+	require.Equal(t, report, read)
+	// Reports can also be referred to by their name alone.
+	read, err = ReadConsistencyDiffReport(eng, filepath.Base(path))
+	require.NoError(t, err)
+	require.Equal(t, report, read)
+
+	var buf bytes.Buffer
+	_, err = read.WriteTo(&buf)
+	require.NoError(t, err)
+	out := buf.String()
+	require.True(t, strings.HasPrefix(out, `r7 ["a","b") inconsistent at 1970-01-01T00:00:00.000000123Z`), out)
+	require.Contains(t, out, "(n2,s2):2: checksum bb [minority]\n")
+	require.Contains(t, out, "====== diff((n1,s1):1, [minority] (n2,s2):2) ======\n"+diff.String())
+}
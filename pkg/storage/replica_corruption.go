@@ -15,10 +15,13 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/pkg/errors"
 )
 
 // maybeSetCorrupt is a stand-in for proper handling of failing replicas. Such a
@@ -77,3 +80,92 @@ A file preventing this node from restarting was placed at:
 	log.FatalfDepth(ctx, 1, "replica is corrupted: %s", cErr)
 	return roachpb.NewError(cErr)
 }
+
+// quarantinedReplicasDir is the name of the directory, relative to a store's
+// auxiliary directory, that holds a marker file for each quarantined replica.
+// A replica whose marker is found when the store starts is quarantined again
+// before it gets to serve; removing the marker and restarting the node is how
+// an operator lifts the quarantine.
+const quarantinedReplicasDir = "quarantined-replicas"
+
+// quarantineMarkerName returns the name of the marker file of the given
+// replica. It includes the replica ID so that the marker of a replica that has
+// since been removed doesn't affect a later replica of the same range.
+func quarantineMarkerName(rangeID roachpb.RangeID, replicaID roachpb.ReplicaID) string {
+	return fmt.Sprintf("r%d_%d", rangeID, replicaID)
+}
+
+// loadQuarantineMarkers returns the names of the quarantine markers present on
+// the given engine.
+func loadQuarantineMarkers(eng engine.Engine) (map[string]struct{}, error) {
+	dir := filepath.Join(eng.GetAuxiliaryDir(), quarantinedReplicasDir)
+	if err := eng.CreateDir(dir); err != nil {
+		return nil, err
+	}
+	names, err := eng.ListDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	markers := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		markers[name] = struct{}{}
+	}
+	return markers, nil
+}
+
+// setQuarantinedLocked marks the replica as quarantined. Quarantined replicas
+// refuse to create a Raft group (see withRaftGroupLocked), so this is all it
+// takes for a replica that doesn't have one yet.
+func (r *Replica) setQuarantinedLocked() {
+	cErr := roachpb.NewReplicaCorruptionError(errors.Errorf(
+		"replica %s was found to be inconsistent and has been quarantined", r))
+	cErr.Processed = true
+	r.mu.destroyStatus.Set(cErr, destroyReasonQuarantined)
+}
+
+// quarantine stops the replica from serving requests and participating in Raft
+// after the consistency checker found its data to differ from that of the
+// majority of its peers. Unlike setCorruptRaftMuLocked, it doesn't terminate
+// the node and doesn't touch the replica's data, which thus remains available
+// for inspection. The quarantine is persisted as a marker file in the store's
+// auxiliary directory, so that the replica stays quarantined across restarts
+// until an operator removes the marker.
+func (r *Replica) quarantine(ctx context.Context) {
+	r.raftMu.Lock()
+	defer r.raftMu.Unlock()
+
+	r.mu.Lock()
+	if !r.mu.destroyStatus.IsAlive() {
+		r.mu.Unlock()
+		return
+	}
+	r.setQuarantinedLocked()
+	replicaID := r.mu.replicaID
+	r.mu.Unlock()
+
+	r.disconnectReplicationRaftMuLocked(ctx)
+
+	eng := r.store.engine
+	dir := filepath.Join(eng.GetAuxiliaryDir(), quarantinedReplicasDir)
+	path := filepath.Join(dir, quarantineMarkerName(r.RangeID, replicaID))
+
+	quarantineMsg := fmt.Sprintf(`ATTENTION:
+
+replica %s was found to be inconsistent with its peers and has been
+quarantined. It will neither serve requests nor participate in Raft, even
+after this node restarts, for as long as this file exists.
+Please contact the CockroachDB support team.
+
+Once the inconsistency has been dealt with, the quarantine can be lifted by
+removing this file and restarting the node:
+%s
+`, r, path)
+
+	if err := eng.CreateDir(dir); err != nil {
+		log.Errorf(ctx, "unable to persist quarantine of replica: %s", err)
+	} else if err := eng.WriteFile(path, []byte(quarantineMsg)); err != nil {
+		log.Errorf(ctx, "unable to persist quarantine of replica: %s", err)
+	}
+	log.Errorf(ctx, "quarantined replica after it was found to be inconsistent with its peers; "+
+		"it will remain quarantined until %s is removed and the node is restarted", path)
+}
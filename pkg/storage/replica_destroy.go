@@ -38,6 +38,10 @@ const (
 	// The replica has been merged into its left-hand neighbor, but its left-hand
 	// neighbor hasn't yet subsumed it.
 	destroyReasonMergePending
+	// The replica was found to be inconsistent with its peers and has been
+	// quarantined. It neither serves requests nor participates in Raft, but its
+	// data is left in place.
+	destroyReasonQuarantined
 )
 
 type destroyStatus struct {
@@ -64,6 +68,11 @@ func (s destroyStatus) Removed() bool {
 	return s.reason == destroyReasonRemoved
 }
 
+// Quarantined returns whether the replica has been quarantined.
+func (s destroyStatus) Quarantined() bool {
+	return s.reason == destroyReasonQuarantined
+}
+
 // removePreemptiveSnapshot is a migration put in place during the 20.1 release
 // to remove any on-disk preemptive snapshots which may still be resident on a
 // 19.2 node's store due to a rapid upgrade.
@@ -163,6 +172,13 @@ func (r *Replica) postDestroyRaftMuLocked(ctx context.Context, ms enginepb.MVCCS
 		})
 	}
 
+	// If the Raft log and HardState live in a separate engine, they were not
+	// removed along with the rest of the replica's data. A crash before they
+	// are is handled at store startup (see clearLeakedRaftEngineData).
+	if err := r.store.clearRaftEngineData(r.RangeID); err != nil {
+		return err
+	}
+
 	// NB: we need the nil check below because it's possible that we're GC'ing a
 	// Replica without a replicaID, in which case it does not have a sideloaded
 	// storage.
@@ -228,7 +244,8 @@ func (r *Replica) destroyRaftMuLocked(ctx context.Context, nextReplicaID roachpb
 	return nil
 }
 
-// disconnectReplicationRaftMuLocked is called when a Replica is being removed.
+// disconnectReplicationRaftMuLocked is called when a Replica is being removed
+// or quarantined.
 // It cancels all outstanding proposals, closes the proposalQuota if there
 // is one, and removes the in-memory raft state.
 func (r *Replica) disconnectReplicationRaftMuLocked(ctx context.Context) {
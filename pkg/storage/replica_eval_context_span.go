@@ -145,6 +145,22 @@ func (rec SpanSetReplicaEvalContext) GetSplitQPS() float64 {
 	return rec.i.GetSplitQPS()
 }
 
+// GetMaxSplitQPS returns the Replica's maximum queries/s rate for splitting and
+// merging purposes.
+func (rec SpanSetReplicaEvalContext) GetMaxSplitQPS() (float64, bool) {
+	return rec.i.GetMaxSplitQPS()
+}
+
+// GetClosedTimestamp returns the closed timestamp of the Replica's range.
+func (rec SpanSetReplicaEvalContext) GetClosedTimestamp(ctx context.Context) hlc.Timestamp {
+	return rec.i.GetClosedTimestamp(ctx)
+}
+
+// GetLockTableView returns a view of the Replica's unreplicated locks.
+func (rec SpanSetReplicaEvalContext) GetLockTableView(txn *enginepb.TxnMeta) engine.LockTableView {
+	return rec.i.GetLockTableView(txn)
+}
+
 // CanCreateTxnRecord determines whether a transaction record can be created
 // for the provided transaction information. See Replica.CanCreateTxnRecord
 // for details about its arguments, return values, and preconditions.
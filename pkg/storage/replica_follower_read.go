@@ -37,20 +37,30 @@ var FollowerReadsEnabled = settings.RegisterPublicBoolSetting(
 func (r *Replica) canServeFollowerRead(
 	ctx context.Context, ba *roachpb.BatchRequest, pErr *roachpb.Error,
 ) *roachpb.Error {
-	// There's no known reason that a non-VOTER_FULL replica couldn't serve follower
-	// reads (or RangeFeed), but as of the time of writing, these are expected
-	// to be short-lived, so it's not worth working out the edge-cases. Revisit if
-	// we add long-lived learners or feel that incoming/outgoing voters also need
-	// to be able to serve follower reads.
+	// There's no known reason that a learner or an incoming/outgoing voter
+	// couldn't serve follower reads (or RangeFeed), but as of the time of
+	// writing, these are expected to be short-lived, so it's not worth working
+	// out the edge-cases. Non-voting replicas, on the other hand, are long-lived
+	// and exist precisely to serve follower reads.
 	repDesc, err := r.GetReplicaDescriptor()
 	if err != nil {
 		return roachpb.NewError(err)
 	}
-	if typ := repDesc.GetType(); typ != roachpb.VOTER_FULL {
+	if typ := repDesc.GetType(); typ != roachpb.VOTER_FULL && typ != roachpb.NON_VOTER {
 		log.Eventf(ctx, "%s replicas cannot serve follower reads", typ)
 		return pErr
 	}
 
+	// QueryResolvedTimestamp requests report on the closed timestamp of the
+	// replica that serves them instead of relying on it, so they can always be
+	// served by a follower.
+	if _, ok := pErr.GetDetail().(*roachpb.NotLeaseHolderError); ok &&
+		ba.IsAllQueryResolvedTimestamp() &&
+		FollowerReadsEnabled.Get(&r.store.cfg.Settings.SV) {
+		log.Event(ctx, "serving resolved timestamp query via follower")
+		return nil
+	}
+
 	canServeFollowerRead := false
 	if lErr, ok := pErr.GetDetail().(*roachpb.NotLeaseHolderError); ok &&
 		lErr.LeaseHolder != nil && lErr.Lease.Type() == roachpb.LeaseEpoch &&
@@ -113,3 +123,10 @@ func (r *Replica) maxClosed(ctx context.Context) hlc.Timestamp {
 	maxClosed.Forward(initialMaxClosed)
 	return maxClosed
 }
+
+// GetClosedTimestamp returns the maximum closed timestamp for this range.
+//
+// GetClosedTimestamp is part of the EvalContext interface.
+func (r *Replica) GetClosedTimestamp(ctx context.Context) hlc.Timestamp {
+	return r.maxClosed(ctx)
+}
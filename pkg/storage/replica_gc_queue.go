@@ -151,8 +151,11 @@ func (rgcq *replicaGCQueue) shouldQueue(
 	// command which sets it to VOTER_OUTGOING we would conservatively wait
 	// 10 days before removing the node. Finally we consider replicas which are
 	// VOTER_INCOMING as suspect because no replica should stay in that state for
-	// too long and being conservative here doesn't seem worthwhile.
-	isSuspect := replDesc.GetType() != roachpb.VOTER_FULL
+	// too long and being conservative here doesn't seem worthwhile. Non-voters,
+	// on the other hand, are expected to stay around for long and never become
+	// candidates either, so they are treated like full voters.
+	typ := replDesc.GetType()
+	isSuspect := typ != roachpb.VOTER_FULL && typ != roachpb.NON_VOTER
 	if raftStatus := repl.RaftStatus(); raftStatus != nil {
 		isSuspect = isSuspect ||
 			(raftStatus.SoftState.RaftState == raft.StateCandidate ||
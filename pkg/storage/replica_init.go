@@ -18,6 +18,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/rpc"
 	"github.com/cockroachdb/cockroach/pkg/storage/abortspan"
+	"github.com/cockroachdb/cockroach/pkg/storage/concurrency"
 	"github.com/cockroachdb/cockroach/pkg/storage/spanlatch"
 	"github.com/cockroachdb/cockroach/pkg/storage/split"
 	"github.com/cockroachdb/cockroach/pkg/storage/stateloader"
@@ -72,6 +73,7 @@ func newUnloadedReplica(
 		abortSpan:      abortspan.New(desc.RangeID),
 	}
 	r.txnWaitQueue = txnwait.NewQueue(store, r)
+	r.unreplicatedLocks = concurrency.NewUnreplicatedLockTable()
 	r.mu.pendingLeaseRequest = makePendingLeaseRequest(r)
 	r.mu.stateLoader = stateloader.Make(desc.RangeID)
 	r.mu.quiescent = true
@@ -79,6 +81,8 @@ func newUnloadedReplica(
 	r.mu.replicaID = replicaID
 	split.Init(&r.loadBasedSplitter, rand.Intn, func() float64 {
 		return float64(SplitByLoadQPSThreshold.Get(&store.cfg.Settings.SV))
+	}, func() time.Duration {
+		return SplitByLoadMergeDelay.Get(&store.cfg.Settings.SV)
 	})
 	r.latchMgr = spanlatch.Make(r.store.stopper, r.store.metrics.SlowLatchRequests)
 	r.mu.proposals = map[storagebase.CmdIDKey]*ProposalData{}
@@ -92,8 +96,10 @@ func newUnloadedReplica(
 		r.leaseholderStats = newReplicaStats(store.Clock(), store.cfg.StorePool.getNodeLocalityString)
 	}
 	// Pass nil for the localityOracle because we intentionally don't track the
-	// origin locality of write load.
+	// origin locality of write load or CPU usage.
 	r.writeStats = newReplicaStats(store.Clock(), nil)
+	r.writeBytesStats = newReplicaStats(store.Clock(), nil)
+	r.cpuStats = newReplicaStats(store.Clock(), nil)
 
 	// Init rangeStr with the range ID.
 	r.rangeStr.store(replicaID, &roachpb.RangeDescriptor{RangeID: desc.RangeID})
@@ -139,7 +145,12 @@ func (r *Replica) loadRaftMuLockedReplicaMuLocked(desc *roachpb.RangeDescriptor)
 	if r.mu.state, err = r.mu.stateLoader.Load(ctx, r.Engine(), desc); err != nil {
 		return err
 	}
-	r.mu.lastIndex, err = r.mu.stateLoader.LoadLastIndex(ctx, r.Engine())
+	if r.store.hasSeparateRaftEngine() {
+		if err := r.reconcileRaftEngineRaftMuLocked(ctx); err != nil {
+			return errors.Wrap(err, "while reconciling Raft engine")
+		}
+	}
+	r.mu.lastIndex, err = r.mu.stateLoader.LoadLastIndex(ctx, r.Engine(), r.store.RaftEngine())
 	if err != nil {
 		return err
 	}
@@ -178,14 +189,15 @@ func (r *Replica) loadRaftMuLockedReplicaMuLocked(desc *roachpb.RangeDescriptor)
 		}
 	}
 
-	ssBase := r.Engine().GetAuxiliaryDir()
+	// Sideloaded payloads are part of the Raft log and live alongside it.
+	ssBase := r.store.RaftEngine().GetAuxiliaryDir()
 	if r.raftMu.sideloaded, err = newDiskSideloadStorage(
 		r.store.cfg.Settings,
 		desc.RangeID,
 		replicaID,
 		ssBase,
 		r.store.limiters.BulkIOWriteRate,
-		r.store.engine,
+		r.store.RaftEngine(),
 	); err != nil {
 		return errors.Wrap(err, "while initializing sideloaded storage")
 	}
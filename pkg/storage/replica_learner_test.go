@@ -883,11 +883,11 @@ func TestLearnerAndJointConfigAdminMerge(t *testing.T) {
 
 	checkFails := func() {
 		err := tc.Server(0).DB().AdminMerge(ctx, scratchStartKey)
-		if exp := `cannot merge range with non-voter replicas on`; !testutils.IsError(err, exp) {
+		if exp := `cannot merge range with learner or joint replicas on`; !testutils.IsError(err, exp) {
 			t.Fatalf(`expected "%s" error got: %+v`, exp, err)
 		}
 		err = tc.Server(0).DB().AdminMerge(ctx, splitKey1)
-		if exp := `cannot merge range with non-voter replicas on`; !testutils.IsError(err, exp) {
+		if exp := `cannot merge range with learner or joint replicas on`; !testutils.IsError(err, exp) {
 			t.Fatalf(`expected "%s" error got: %+v`, exp, err)
 		}
 	}
@@ -949,6 +949,9 @@ func TestMergeQueueSeesLearnerOrJointConfig(t *testing.T) {
 	db := sqlutils.MakeSQLRunner(tc.ServerConn(0))
 	// TestCluster currently overrides this when used with ReplicationManual.
 	db.Exec(t, `SET CLUSTER SETTING kv.range_merge.queue_enabled = true`)
+	// Don't make the merge queue wait for the freshly split RHS to accumulate
+	// load history.
+	db.Exec(t, `SET CLUSTER SETTING kv.range_split.by_load_merge_delay = '0s'`)
 
 	scratchStartKey := tc.ScratchRange(t)
 	origDesc := tc.LookupRangeOrFatal(t, scratchStartKey)
@@ -12,12 +12,14 @@ package storage
 
 import (
 	"context"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/storagepb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"go.etcd.io/etcd/raft"
 )
 
@@ -239,6 +241,31 @@ func (r *Replica) WritesPerSecond() float64 {
 	return wps
 }
 
+// WriteBytesPerSecond returns the range's average bytes written per second, as
+// measured by the size of the WriteBatches (and ingested SSTables) applied by
+// Raft.
+func (r *Replica) WriteBytesPerSecond() float64 {
+	wbps, _ := r.writeBytesStats.avgQPS()
+	return wbps
+}
+
+// CPUPerSecond returns the range's average number of nanoseconds spent
+// evaluating requests per second. Like QueriesPerSecond, this only accounts
+// for requests evaluated by this replica, which in practice means that it is
+// only meaningful on the leaseholder.
+func (r *Replica) CPUPerSecond() float64 {
+	cpu, _ := r.cpuStats.avgQPS()
+	return cpu
+}
+
+// recordRequestCPU records the time elapsed since start as CPU time spent
+// evaluating a request. The Go runtime doesn't expose per-goroutine CPU usage,
+// so the wall time of evaluation, which doesn't include waiting on latches or
+// replication, is used as an approximation.
+func (r *Replica) recordRequestCPU(start time.Time) {
+	r.cpuStats.recordCount(float64(timeutil.Since(start)), 0 /* nodeID */)
+}
+
 func (r *Replica) needsSplitBySizeRLocked() bool {
 	return r.exceedsMultipleOfSplitSizeRLocked(1)
 }
@@ -249,7 +249,12 @@ func (r *Replica) computeChecksumPostApply(ctx context.Context, cc storagepb.Com
 			}
 		}
 
-		if shouldFatal {
+		if shouldFatal && ConsistencyCheckQuarantine.Get(&r.store.ClusterSettings().SV) {
+			// The operator asked for inconsistent replicas to be taken out of
+			// service rather than terminating their node. The checksum (and
+			// diff) computed above remain available to the leaseholder.
+			r.quarantine(ctx)
+		} else if shouldFatal {
 			// This node should fatal as a result of a previous consistency
 			// check (i.e. this round is carried out only to obtain a diff).
 			// If we fatal too early, the diff won't make it back to the lease-
@@ -358,6 +363,12 @@ func (r *Replica) leasePostApply(ctx context.Context, newLease roachpb.Lease, pe
 		if r.leaseholderStats != nil {
 			r.leaseholderStats.resetRequestCounts()
 		}
+
+		// Similarly, discard the load history consulted by the merge queue. It is
+		// incomplete since this replica did not serve the requests evaluated under
+		// the previous lease. Lease transfers seed it with the history of the
+		// previous leaseholder (see handleLeaseResult).
+		r.loadBasedSplitter.ResetMaxQPS(timeutil.Now(), 0 /* seedMaxQPS */)
 	}
 
 	// Sanity check to make sure that the lease sequence is moving in the right
@@ -417,6 +428,9 @@ func (r *Replica) leasePostApply(ctx context.Context, newLease roachpb.Lease, pe
 		// Also clear and disable the push transaction queue. Any waiters
 		// must be redirected to the new lease holder.
 		r.txnWaitQueue.Clear(true /* disable */)
+		// Unreplicated locks are only held on the lease holder, so they are
+		// lost when the lease changes hands.
+		r.unreplicatedLocks.Clear()
 	}
 
 	// If we're the current raft leader, may want to transfer the leadership to
@@ -609,6 +623,20 @@ func (r *Replica) handleReadWriteLocalEvalResult(ctx context.Context, lResult re
 		log.Fatalf(ctx, "LocalEvalResult.MaybeWatchForMerge should be false")
 	}
 
+	if lResult.AcquiredLocks != nil {
+		for i := range lResult.AcquiredLocks {
+			r.unreplicatedLocks.OnLockAcquired(ctx, &lResult.AcquiredLocks[i])
+		}
+		lResult.AcquiredLocks = nil
+	}
+
+	if lResult.ResolvedLocks != nil {
+		for i := range lResult.ResolvedLocks {
+			r.unreplicatedLocks.OnLockUpdated(ctx, &lResult.ResolvedLocks[i])
+		}
+		lResult.ResolvedLocks = nil
+	}
+
 	if lResult.UpdatedTxns != nil {
 		for _, txn := range lResult.UpdatedTxns {
 			r.txnWaitQueue.UpdateTxn(ctx, txn)
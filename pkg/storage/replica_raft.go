@@ -604,8 +604,10 @@ func (r *Replica) handleRaftReadyRaftMuLocked(
 
 	// Use a more efficient write-only batch because we don't need to do any
 	// reads from the batch. Any reads are performed via the "distinct" batch
-	// which passes the reads through to the underlying DB.
-	batch := r.store.Engine().NewWriteOnlyBatch()
+	// which passes the reads through to the underlying DB. The log entries and
+	// HardState go to the store's Raft engine, which may be separate from the
+	// engine holding the rest of the replica's state.
+	batch := r.store.RaftEngine().NewWriteOnlyBatch()
 	defer batch.Close()
 
 	// We know that all of the writes from here forward will be to distinct keys.
@@ -1015,7 +1017,7 @@ func (r *Replica) maybeCoalesceHeartbeat(
 		Term:          msg.Term,
 		Commit:        msg.Commit,
 		Quiesce:       quiesce,
-		ToIsLearner:   toReplica.GetType() == roachpb.LEARNER,
+		ToIsLearner:   isRaftLearner(toReplica),
 	}
 	if log.V(4) {
 		log.Infof(ctx, "coalescing beat: %+v", beat)
@@ -1306,13 +1308,14 @@ func (s pendingCmdSlice) Less(i, j int) bool {
 //
 // Requires that Replica.mu is held.
 //
-// If this Replica is in the process of being removed this method will return
-// errRemoved.
+// If this Replica is in the process of being removed or has been quarantined
+// this method will return errRemoved.
 func (r *Replica) withRaftGroupLocked(
 	mayCampaignOnWake bool, f func(r *raft.RawNode) (unquiesceAndWakeLeader bool, _ error),
 ) error {
-	if r.mu.destroyStatus.Removed() {
-		// Callers know to detect errRemoved as non-fatal.
+	if r.mu.destroyStatus.Removed() || r.mu.destroyStatus.Quarantined() {
+		// Callers know to detect errRemoved as non-fatal. A quarantined replica
+		// is treated the same way, so that it stops participating in Raft.
 		return errRemoved
 	}
 
@@ -1358,8 +1361,8 @@ func (r *Replica) withRaftGroupLocked(
 // messages (which may include MsgVotes from an election in progress,
 // and this election would be disrupted if we started our own).
 //
-// If this Replica is in the process of being removed this method will return
-// errRemoved.
+// If this Replica is in the process of being removed or has been quarantined
+// this method will return errRemoved.
 func (r *Replica) withRaftGroup(
 	mayCampaignOnWake bool, f func(r *raft.RawNode) (unquiesceAndWakeLeader bool, _ error),
 ) error {
@@ -1538,7 +1541,7 @@ func (r *Replica) acquireSplitLock(
 	rightReplDesc, _ := split.RightDesc.GetReplicaDescriptor(r.StoreID())
 	rightRng, _, err := r.store.getOrCreateReplica(ctx, split.RightDesc.RangeID,
 		rightReplDesc.ReplicaID, nil, /* creatingReplica */
-		rightReplDesc.GetType() == roachpb.LEARNER)
+		isRaftLearner(rightReplDesc))
 	// If getOrCreateReplica returns RaftGroupDeletedError we know that the RHS
 	// has already been removed. This case is handled properly in splitPostApply.
 	if _, isRaftGroupDeletedError := err.(*roachpb.RaftGroupDeletedError); isRaftGroupDeletedError {
@@ -1577,7 +1580,7 @@ func (r *Replica) acquireMergeLock(
 	rightReplDesc, _ := merge.RightDesc.GetReplicaDescriptor(r.StoreID())
 	rightRepl, _, err := r.store.getOrCreateReplica(ctx, merge.RightDesc.RangeID,
 		rightReplDesc.ReplicaID, nil, /* creatingReplica */
-		rightReplDesc.GetType() == roachpb.LEARNER)
+		isRaftLearner(rightReplDesc))
 	if err != nil {
 		return nil, err
 	}
@@ -1605,11 +1608,15 @@ func (r *Replica) acquireMergeLock(
 // the associated RaftLogDelta. It is usually expected to be true, but may not
 // be for the first truncation after on a replica that recently received a
 // snapshot.
+//
+// The truncated log entries are cleared through logWriter, which is readWriter
+// unless the store keeps its Raft log in a separate engine.
 func handleTruncatedStateBelowRaft(
 	ctx context.Context,
 	oldTruncatedState, newTruncatedState *roachpb.RaftTruncatedState,
 	loader stateloader.StateLoader,
 	readWriter engine.ReadWriter,
+	logWriter engine.Writer,
 ) (_apply bool, _ error) {
 	// If this is a log truncation, load the resulting unreplicated or legacy
 	// replicated truncated state (in that order). If the migration is happening
@@ -1629,7 +1636,10 @@ func handleTruncatedStateBelowRaft(
 	// atomically with the raft command application so that the
 	// TruncatedState index is always consistent with the state of the
 	// Raft log itself. We can use the distinct writer because we know
-	// all writes will be to distinct keys.
+	// all writes will be to distinct keys. If the Raft log lives in a
+	// separate engine, the entries are removed only after the command has
+	// been applied, and any left behind by a crash are removed when the
+	// replica is next loaded (see stateloader.ReconcileRaftState).
 	//
 	// Intentionally don't use range deletion tombstones (ClearRange())
 	// due to performance concerns connected to having many range
@@ -1641,7 +1651,7 @@ func handleTruncatedStateBelowRaft(
 		// NB: RangeIDPrefixBufs have sufficient capacity (32 bytes) to
 		// avoid allocating when constructing Raft log keys (16 bytes).
 		unsafeKey := prefixBuf.RaftLogKey(idx)
-		if err := readWriter.Clear(engine.MakeMVCCMetadataKey(unsafeKey)); err != nil {
+		if err := logWriter.Clear(engine.MakeMVCCMetadataKey(unsafeKey)); err != nil {
 			return false, errors.Wrapf(err, "unable to clear truncated Raft entries for %+v", newTruncatedState)
 		}
 	}
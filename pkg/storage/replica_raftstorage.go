@@ -60,7 +60,7 @@ var _ raft.Storage = (*replicaRaftStorage)(nil)
 // InitialState requires that r.mu is held.
 func (r *replicaRaftStorage) InitialState() (raftpb.HardState, raftpb.ConfState, error) {
 	ctx := r.AnnotateCtx(context.TODO())
-	hs, err := r.mu.stateLoader.LoadHardState(ctx, r.store.Engine())
+	hs, err := r.mu.stateLoader.LoadHardState(ctx, r.store.RaftEngine())
 	// For uninitialized ranges, membership is unknown at this point.
 	if raft.IsEmptyHardState(hs) || err != nil {
 		return raftpb.HardState{}, raftpb.ConfState{}, err
@@ -75,11 +75,13 @@ func (r *replicaRaftStorage) InitialState() (raftpb.HardState, raftpb.ConfState,
 func (r *replicaRaftStorage) Entries(lo, hi, maxBytes uint64) ([]raftpb.Entry, error) {
 	readonly := r.store.Engine().NewReadOnly()
 	defer readonly.Close()
+	raftReadonly := r.store.RaftEngine().NewReadOnly()
+	defer raftReadonly.Close()
 	ctx := r.AnnotateCtx(context.TODO())
 	if r.raftMu.sideloaded == nil {
 		return nil, errors.New("sideloaded storage is uninitialized")
 	}
-	return entries(ctx, r.mu.stateLoader, readonly, r.RangeID, r.store.raftEntryCache,
+	return entries(ctx, r.mu.stateLoader, readonly, raftReadonly, r.RangeID, r.store.raftEntryCache,
 		r.raftMu.sideloaded, lo, hi, maxBytes)
 }
 
@@ -91,11 +93,13 @@ func (r *Replica) raftEntriesLocked(lo, hi, maxBytes uint64) ([]raftpb.Entry, er
 // entries retrieves entries from the engine. To accommodate loading the term,
 // `sideloaded` can be supplied as nil, in which case sideloaded entries will
 // not be inlined, the raft entry cache will not be populated with *any* of the
-// loaded entries, and maxBytes will not be applied to the payloads.
+// loaded entries, and maxBytes will not be applied to the payloads. The log is
+// read from raftReader, and the truncated state from reader.
 func entries(
 	ctx context.Context,
 	rsl stateloader.StateLoader,
 	reader engine.Reader,
+	raftReader engine.Reader,
 	rangeID roachpb.RangeID,
 	eCache *raftentry.Cache,
 	sideloaded SideloadStorage,
@@ -165,7 +169,7 @@ func entries(
 		return exceededMaxBytes, nil
 	}
 
-	if err := iterateEntries(ctx, reader, rangeID, expectedIndex, hi, scanFunc); err != nil {
+	if err := iterateEntries(ctx, raftReader, rangeID, expectedIndex, hi, scanFunc); err != nil {
 		return nil, err
 	}
 	// Cache the fetched entries, if we may.
@@ -191,7 +195,7 @@ func entries(
 		}
 
 		// Was the missing index after the last index?
-		lastIndex, err := rsl.LoadLastIndex(ctx, reader)
+		lastIndex, err := rsl.LoadLastIndex(ctx, reader, raftReader)
 		if err != nil {
 			return nil, err
 		}
@@ -252,8 +256,10 @@ func (r *replicaRaftStorage) Term(i uint64) (uint64, error) {
 	}
 	readonly := r.store.Engine().NewReadOnly()
 	defer readonly.Close()
+	raftReadonly := r.store.RaftEngine().NewReadOnly()
+	defer raftReadonly.Close()
 	ctx := r.AnnotateCtx(context.TODO())
-	return term(ctx, r.mu.stateLoader, readonly, r.RangeID, r.store.raftEntryCache, i)
+	return term(ctx, r.mu.stateLoader, readonly, raftReadonly, r.RangeID, r.store.raftEntryCache, i)
 }
 
 // raftTermLocked requires that r.mu is locked for reading.
@@ -265,13 +271,14 @@ func term(
 	ctx context.Context,
 	rsl stateloader.StateLoader,
 	reader engine.Reader,
+	raftReader engine.Reader,
 	rangeID roachpb.RangeID,
 	eCache *raftentry.Cache,
 	i uint64,
 ) (uint64, error) {
 	// entries() accepts a `nil` sideloaded storage and will skip inlining of
 	// sideloaded entries. We only need the term, so this is what we do.
-	ents, err := entries(ctx, rsl, reader, rangeID, eCache, nil /* sideloaded */, i, i+1, math.MaxUint64 /* maxBytes */)
+	ents, err := entries(ctx, rsl, reader, raftReader, rangeID, eCache, nil /* sideloaded */, i, i+1, math.MaxUint64 /* maxBytes */)
 	if err == raft.ErrCompacted {
 		ts, _, err := rsl.LoadRaftTruncatedState(ctx, reader)
 		if err != nil {
@@ -386,6 +393,10 @@ func (r *Replica) GetSnapshot(
 	// the corresponding Raft command not applied yet).
 	r.raftMu.Lock()
 	snap := r.store.engine.NewSnapshot()
+	raftSnap := engine.Reader(snap)
+	if r.store.hasSeparateRaftEngine() {
+		raftSnap = r.store.raftEngine.NewSnapshot()
+	}
 	r.mu.Lock()
 	appliedIndex := r.mu.state.RaftAppliedIndex
 	// Cleared when OutgoingSnapshot closes.
@@ -402,6 +413,9 @@ func (r *Replica) GetSnapshot(
 		if err != nil {
 			release()
 			snap.Close()
+			if raftSnap != snap {
+				raftSnap.Close()
+			}
 		}
 	}()
 
@@ -428,7 +442,7 @@ func (r *Replica) GetSnapshot(
 	// create a new state loader.
 	snapData, err := snapshot(
 		ctx, snapUUID, stateloader.Make(rangeID), snapType,
-		snap, rangeID, r.store.raftEntryCache, withSideloaded, startKey,
+		snap, raftSnap, rangeID, r.store.raftEntryCache, withSideloaded, startKey,
 	)
 	if err != nil {
 		log.Errorf(ctx, "error generating snapshot: %+v", err)
@@ -447,6 +461,10 @@ type OutgoingSnapshot struct {
 	RaftSnap raftpb.Snapshot
 	// The RocksDB snapshot that will be streamed from.
 	EngineSnap engine.Reader
+	// The snapshot of the engine holding the Raft log, from which the log
+	// entries included in the snapshot are read. This is EngineSnap unless the
+	// store keeps its Raft log in a separate engine.
+	RaftEngineSnap engine.Reader
 	// The complete range iterator for the snapshot to stream.
 	Iter *rditer.ReplicaDataIterator
 	// The replica state within the snapshot.
@@ -469,6 +487,9 @@ func (s *OutgoingSnapshot) String() string {
 func (s *OutgoingSnapshot) Close() {
 	s.Iter.Close()
 	s.EngineSnap.Close()
+	if s.RaftEngineSnap != s.EngineSnap {
+		s.RaftEngineSnap.Close()
+	}
 	if s.onClose != nil {
 		s.onClose()
 	}
@@ -507,6 +528,7 @@ func snapshot(
 	rsl stateloader.StateLoader,
 	snapType SnapshotRequest_Type,
 	snap engine.Reader,
+	raftSnap engine.Reader,
 	rangeID roachpb.RangeID,
 	eCache *raftentry.Cache,
 	withSideloaded func(func(SideloadStorage) error) error,
@@ -532,7 +554,7 @@ func snapshot(
 		return OutgoingSnapshot{}, err
 	}
 
-	term, err := term(ctx, rsl, snap, rangeID, eCache, appliedIndex)
+	term, err := term(ctx, rsl, snap, raftSnap, rangeID, eCache, appliedIndex)
 	if err != nil {
 		return OutgoingSnapshot{}, errors.Errorf("failed to fetch term of %d: %s", appliedIndex, err)
 	}
@@ -551,6 +573,7 @@ func snapshot(
 		RaftEntryCache: eCache,
 		WithSideloaded: withSideloaded,
 		EngineSnap:     snap,
+		RaftEngineSnap: raftSnap,
 		Iter:           iter,
 		State:          state,
 		SnapUUID:       snapUUID,
@@ -815,9 +838,24 @@ func (r *Replica) applySnapshot(
 		return errors.Wrapf(err, "error clearing range of unreplicated SST writer")
 	}
 
+	// The HardState and Raft entries go into the unreplicated SST, unless the
+	// store keeps them in a separate engine. In that case, they're written to
+	// a batch which replaces the replica's prior Raft state once the SSTs have
+	// been ingested.
+	raftWriter := engine.Writer(&unreplicatedSST)
+	var raftBatch engine.Batch
+	if r.store.hasSeparateRaftEngine() {
+		raftBatch = r.store.raftEngine.NewWriteOnlyBatch()
+		defer raftBatch.Close()
+		if err := clearRaftState(r.store.raftEngine, raftBatch, r.RangeID); err != nil {
+			return errors.Wrapf(err, "unable to clear Raft state")
+		}
+		raftWriter = raftBatch
+	}
+
 	// Update HardState.
-	if err := r.raftMu.stateLoader.SetHardState(ctx, &unreplicatedSST, hs); err != nil {
-		return errors.Wrapf(err, "unable to write HardState to unreplicated SST writer")
+	if err := r.raftMu.stateLoader.SetHardState(ctx, raftWriter, hs); err != nil {
+		return errors.Wrapf(err, "unable to write HardState")
 	}
 
 	// Update Raft entries.
@@ -837,7 +875,7 @@ func (r *Replica) applySnapshot(
 			return err
 		}
 		raftLogSize += sideloadedEntriesSize
-		_, lastTerm, raftLogSize, err = r.append(ctx, &unreplicatedSST, 0, invalidLastTerm, raftLogSize, logEntries)
+		_, lastTerm, raftLogSize, err = r.append(ctx, raftWriter, 0, invalidLastTerm, raftLogSize, logEntries)
 		if err != nil {
 			return err
 		}
@@ -918,6 +956,14 @@ func (r *Replica) applySnapshot(
 	// has not yet been updated. Any errors past this point must therefore be
 	// treated as fatal.
 
+	if raftBatch != nil {
+		// If this fails to commit durably, the Raft state is reconstructed
+		// when the replica is loaded again (see ReconcileRaftState).
+		if err := raftBatch.Commit(true /* sync */); err != nil {
+			log.Fatalf(ctx, "unable to write Raft state while applying snapshot: %+v", err)
+		}
+	}
+
 	if err := r.clearSubsumedReplicaInMemoryData(ctx, subsumedRepls, mergedTombstoneReplicaID); err != nil {
 		log.Fatalf(ctx, "failed to clear in-memory data of subsumed replicas while applying snapshot: %+v", err)
 	}
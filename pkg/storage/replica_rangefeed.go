@@ -213,8 +213,16 @@ func (r *Replica) RangeFeed(
 			// workable. See #35122 for details.
 			// MinTimestampHint: args.Timestamp,
 		})
+		// Present the deletions implied by MVCC range tombstones written after
+		// the starting timestamp as regular deletions.
+		tombstones, err := rangefeedRangeTombstones(r.Engine(), r.RangeID, args)
+		if err != nil {
+			innerIter.Close()
+			r.raftMu.Unlock()
+			return roachpb.NewError(err)
+		}
 		catchUpIter = iteratorWithCloser{
-			SimpleIterator: innerIter,
+			SimpleIterator: engine.NewMVCCRangeTombstoneIterator(innerIter, tombstones),
 			close:          iterSemRelease,
 		}
 		// Responsibility for releasing the semaphore now passes to the iterator.
@@ -435,6 +443,26 @@ func (r *Replica) numRangefeedRegistrations() int {
 	return p.Len()
 }
 
+// rangefeedRangeTombstones returns the MVCC range tombstones written to the
+// span of the rangefeed after its starting timestamp.
+func rangefeedRangeTombstones(
+	reader engine.Reader, rangeID roachpb.RangeID, args *roachpb.RangeFeedRequest,
+) ([]engine.MVCCRangeTombstone, error) {
+	if ok, err := engine.MVCCHasRangeTombstones(reader, rangeID); err != nil || !ok {
+		return nil, err
+	}
+	return engine.MVCCFindRangeTombstones(reader,
+		args.Span.Key, args.Span.EndKey, args.Timestamp, hlc.MaxTimestamp)
+}
+
+// rangefeedNoRangeTombstones returns whether the reads of the values of
+// logical ops can skip looking up MVCC range tombstones because the range
+// doesn't contain any.
+func rangefeedNoRangeTombstones(reader engine.Reader, rangeID roachpb.RangeID) (bool, error) {
+	ok, err := engine.MVCCHasRangeTombstones(reader, rangeID)
+	return !ok, err
+}
+
 // populatePrevValsInLogicalOpLogRaftMuLocked updates the provided logical op
 // log with previous values read from the reader, which is expected to reflect
 // the state of the Replica before the operations in the logical op log are
@@ -447,6 +475,12 @@ func (r *Replica) populatePrevValsInLogicalOpLogRaftMuLocked(
 		return
 	}
 
+	noTombstones, err := rangefeedNoRangeTombstones(prevReader, r.RangeID)
+	if err != nil {
+		r.disconnectRangefeedWithErr(p, roachpb.NewError(err))
+		return
+	}
+
 	// Read from the Reader to populate the PrevValue fields.
 	for _, op := range ops.Ops {
 		var key []byte
@@ -475,9 +509,9 @@ func (r *Replica) populatePrevValsInLogicalOpLogRaftMuLocked(
 
 		// Read the previous value from the prev Reader. Unlike the new value
 		// (see handleLogicalOpLogRaftMuLocked), this one may be missing.
-		prevVal, _, err := engine.MVCCGet(
-			ctx, prevReader, key, ts, engine.MVCCGetOptions{Tombstones: true, Inconsistent: true},
-		)
+		prevVal, _, err := engine.MVCCGet(ctx, prevReader, key, ts, engine.MVCCGetOptions{
+			Tombstones: true, Inconsistent: true, NoRangeTombstones: noTombstones,
+		})
 		if err != nil {
 			r.disconnectRangefeedWithErr(p, roachpb.NewErrorf(
 				"error consuming %T for key %v @ ts %v: %v", op, key, ts, err,
@@ -520,6 +554,12 @@ func (r *Replica) handleLogicalOpLogRaftMuLocked(
 		return
 	}
 
+	noTombstones, err := rangefeedNoRangeTombstones(reader, r.RangeID)
+	if err != nil {
+		r.disconnectRangefeedWithErr(p, roachpb.NewError(err))
+		return
+	}
+
 	// When reading straight from the Raft log, some logical ops will not be
 	// fully populated. Read from the Reader to populate all fields.
 	for _, op := range ops.Ops {
@@ -556,7 +596,9 @@ func (r *Replica) handleLogicalOpLogRaftMuLocked(
 		// Read the value directly from the Reader. This is performed in the
 		// same raftMu critical section that the logical op's corresponding
 		// WriteBatch is applied, so the value should exist.
-		val, _, err := engine.MVCCGet(ctx, reader, key, ts, engine.MVCCGetOptions{Tombstones: true})
+		val, _, err := engine.MVCCGet(ctx, reader, key, ts, engine.MVCCGetOptions{
+			Tombstones: true, NoRangeTombstones: noTombstones,
+		})
 		if val == nil && err == nil {
 			err = errors.New("value missing in reader")
 		}
@@ -24,17 +24,24 @@ const (
 type replicaWithStats struct {
 	repl *Replica
 	qps  float64
-	// TODO(a-robinson): Include writes-per-second and logicalBytes of storage?
+	// writeBytes is the number of bytes written to the replica per second.
+	writeBytes float64
+	// cpu is the number of nanoseconds per second spent evaluating requests on
+	// the replica.
+	cpu float64
+	// TODO(a-robinson): Include logicalBytes of storage?
 }
 
 // replicaRankings maintains top-k orderings of the replicas in a store along
-// different dimensions of concern, such as QPS, keys written per second, and
-// disk used.
+// different dimensions of concern, such as QPS, bytes written per second, and
+// request CPU time.
 type replicaRankings struct {
 	mu struct {
 		syncutil.Mutex
-		qpsAccumulator *rrAccumulator
-		byQPS          []replicaWithStats
+		accumulator  *rrAccumulator
+		byQPS        []replicaWithStats
+		byWriteBytes []replicaWithStats
+		byCPU        []replicaWithStats
 	}
 }
 
@@ -45,26 +52,51 @@ func newReplicaRankings() *replicaRankings {
 func (rr *replicaRankings) newAccumulator() *rrAccumulator {
 	res := &rrAccumulator{}
 	res.qps.val = func(r replicaWithStats) float64 { return r.qps }
+	res.writeBytes.val = func(r replicaWithStats) float64 { return r.writeBytes }
+	res.cpu.val = func(r replicaWithStats) float64 { return r.cpu }
 	return res
 }
 
 func (rr *replicaRankings) update(acc *rrAccumulator) {
 	rr.mu.Lock()
-	rr.mu.qpsAccumulator = acc
+	rr.mu.accumulator = acc
 	rr.mu.Unlock()
 }
 
+// topQPS returns the replicas with the highest QPS, in decreasing order.
 func (rr *replicaRankings) topQPS() []replicaWithStats {
 	rr.mu.Lock()
 	defer rr.mu.Unlock()
 	// If we have a new set of data, consume it. Otherwise, just return the most
 	// recently consumed data.
-	if rr.mu.qpsAccumulator.qps.Len() > 0 {
-		rr.mu.byQPS = consumeAccumulator(&rr.mu.qpsAccumulator.qps)
+	if rr.mu.accumulator.qps.Len() > 0 {
+		rr.mu.byQPS = consumeAccumulator(&rr.mu.accumulator.qps)
 	}
 	return rr.mu.byQPS
 }
 
+// topWriteBytes returns the replicas with the most bytes written per second,
+// in decreasing order.
+func (rr *replicaRankings) topWriteBytes() []replicaWithStats {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	if rr.mu.accumulator.writeBytes.Len() > 0 {
+		rr.mu.byWriteBytes = consumeAccumulator(&rr.mu.accumulator.writeBytes)
+	}
+	return rr.mu.byWriteBytes
+}
+
+// topCPU returns the replicas with the most request CPU time per second, in
+// decreasing order.
+func (rr *replicaRankings) topCPU() []replicaWithStats {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	if rr.mu.accumulator.cpu.Len() > 0 {
+		rr.mu.byCPU = consumeAccumulator(&rr.mu.accumulator.cpu)
+	}
+	return rr.mu.byCPU
+}
+
 // rrAccumulator is used to update the replicas tracked by replicaRankings.
 // The typical pattern should be to call replicaRankings.newAccumulator, add
 // all the replicas you care about to the accumulator using addReplica, then
@@ -74,22 +106,15 @@ func (rr *replicaRankings) topQPS() []replicaWithStats {
 // prevents concurrent loaders of data from messing with each other -- the last
 // `update`d accumulator will win.
 type rrAccumulator struct {
-	qps rrPriorityQueue
+	qps        rrPriorityQueue
+	writeBytes rrPriorityQueue
+	cpu        rrPriorityQueue
 }
 
 func (a *rrAccumulator) addReplica(repl replicaWithStats) {
-	// If the heap isn't full, just push the new replica and return.
-	if a.qps.Len() < numTopReplicasToTrack {
-		heap.Push(&a.qps, repl)
-		return
-	}
-
-	// Otherwise, conditionally push if the new replica is more deserving than
-	// the current tip of the heap.
-	if repl.qps > a.qps.entries[0].qps {
-		heap.Pop(&a.qps)
-		heap.Push(&a.qps, repl)
-	}
+	a.qps.maybeAdd(repl)
+	a.writeBytes.maybeAdd(repl)
+	a.cpu.maybeAdd(repl)
 }
 
 func consumeAccumulator(pq *rrPriorityQueue) []replicaWithStats {
@@ -106,6 +131,23 @@ type rrPriorityQueue struct {
 	val     func(replicaWithStats) float64
 }
 
+// maybeAdd adds the replica to the priority queue if the queue isn't full yet
+// or if the replica is more deserving than the least deserving replica in it.
+func (pq *rrPriorityQueue) maybeAdd(repl replicaWithStats) {
+	// If the heap isn't full, just push the new replica and return.
+	if pq.Len() < numTopReplicasToTrack {
+		heap.Push(pq, repl)
+		return
+	}
+
+	// Otherwise, conditionally push if the new replica is more deserving than
+	// the current tip of the heap.
+	if pq.val(repl) > pq.val(pq.entries[0]) {
+		heap.Pop(pq)
+		heap.Push(pq, repl)
+	}
+}
+
 func (pq rrPriorityQueue) Len() int { return len(pq.entries) }
 
 func (pq rrPriorityQueue) Less(i, j int) bool {
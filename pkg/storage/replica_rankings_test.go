@@ -71,3 +71,48 @@ func TestReplicaRankings(t *testing.T) {
 		}
 	}
 }
+
+func TestReplicaRankingsByDimension(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	rr := newReplicaRankings()
+	acc := rr.newAccumulator()
+	// r1 serves the most queries, r2 writes the most bytes, and r3 uses the
+	// most CPU.
+	stats := []replicaWithStats{
+		{repl: &Replica{RangeID: 1}, qps: 100, writeBytes: 10, cpu: 20},
+		{repl: &Replica{RangeID: 2}, qps: 10, writeBytes: 100, cpu: 10},
+		{repl: &Replica{RangeID: 3}, qps: 20, writeBytes: 20, cpu: 100},
+	}
+	for _, s := range stats {
+		acc.addReplica(s)
+	}
+	rr.update(acc)
+
+	rangeIDs := func(repls []replicaWithStats) []roachpb.RangeID {
+		var res []roachpb.RangeID
+		for _, r := range repls {
+			res = append(res, r.repl.RangeID)
+		}
+		return res
+	}
+	for _, tc := range []struct {
+		name string
+		top  func() []replicaWithStats
+		want []roachpb.RangeID
+	}{
+		{"qps", rr.topQPS, []roachpb.RangeID{1, 3, 2}},
+		{"writeBytes", rr.topWriteBytes, []roachpb.RangeID{2, 3, 1}},
+		{"cpu", rr.topCPU, []roachpb.RangeID{3, 1, 2}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// Consuming one dimension doesn't affect the others, and a second call
+			// returns the same rankings.
+			for i := 0; i < 2; i++ {
+				if got := rangeIDs(tc.top()); !reflect.DeepEqual(got, tc.want) {
+					t.Errorf("got %v; want %v", got, tc.want)
+				}
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/storagepb"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/kr/pretty"
 )
 
@@ -60,6 +61,14 @@ func (r *Replica) executeReadOnlyBatch(
 		return nil, roachpb.NewError(err)
 	}
 
+	// Check for conflicts of locking reads with the unreplicated locks of
+	// other transactions.
+	if ba.IsLocking() {
+		if pErr := r.checkUnreplicatedLockConflicts(ba); pErr != nil {
+			return nil, pErr
+		}
+	}
+
 	// Evaluate read-only batch command.
 	var result result.Result
 	rec := NewReplicaEvalContext(r, spans)
@@ -72,7 +81,9 @@ func (r *Replica) executeReadOnlyBatch(
 		rw = spanset.NewReadWriterAt(rw, spans, ba.Timestamp)
 	}
 	defer rw.Close()
+	evalStart := timeutil.Now()
 	br, result, pErr = evaluateBatch(ctx, storagebase.CmdIDKey(""), rw, rec, nil, ba, true /* readOnly */)
+	r.recordRequestCPU(evalStart)
 	if err := r.handleReadOnlyLocalEvalResult(ctx, ba, result.Local); err != nil {
 		pErr = roachpb.NewError(err)
 	}
@@ -105,6 +116,13 @@ func (r *Replica) handleReadOnlyLocalEvalResult(
 		lResult.MaybeWatchForMerge = false
 	}
 
+	if locks := lResult.DetachAcquiredLocks(); len(locks) > 0 {
+		log.Eventf(ctx, "acquiring %d unreplicated locks", len(locks))
+		for i := range locks {
+			r.unreplicatedLocks.OnLockAcquired(ctx, &locks[i])
+		}
+	}
+
 	if intents := lResult.DetachEncounteredIntents(); len(intents) > 0 {
 		log.Eventf(ctx, "submitting %d intents to asynchronous processing", len(intents))
 		// We only allow synchronous intent resolution for consistent requests.
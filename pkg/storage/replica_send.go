@@ -13,9 +13,12 @@ package storage
 import (
 	"context"
 	"reflect"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/batcheval"
+	"github.com/cockroachdb/cockroach/pkg/storage/concurrency/lock"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/storage/intentresolver"
 	"github.com/cockroachdb/cockroach/pkg/storage/spanlatch"
 	"github.com/cockroachdb/cockroach/pkg/storage/spanset"
@@ -23,7 +26,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/txnwait"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	opentracing "github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
 )
@@ -171,6 +176,10 @@ func (r *Replica) executeBatchWithConcurrencyRetries(
 		}
 	}()
 
+	// Record the conflicts with other transactions that the batch waits on so
+	// that they can be reported to the client.
+	var contentionEvents []roachpb.ContentionEvent
+
 	// Try to execute command; exit retry loop on success.
 	for {
 		// Exit loop if context has been canceled or timed out.
@@ -200,9 +209,13 @@ func (r *Replica) executeBatchWithConcurrencyRetries(
 		switch t := pErr.GetDetail().(type) {
 		case nil:
 			// Success.
+			br.ContentionEvents = append(br.ContentionEvents, contentionEvents...)
 			return br, nil
 		case *roachpb.WriteIntentError:
-			if cleanup, pErr = r.handleWriteIntentError(ctx, ba, pErr, t, cleanup); pErr != nil {
+			waitStart := timeutil.Now()
+			cleanup, pErr = r.handleWriteIntentError(ctx, ba, pErr, t, cleanup)
+			contentionEvents = appendContentionEvents(contentionEvents, ba.Txn, t, timeutil.Since(waitStart))
+			if pErr != nil {
 				return nil, pErr
 			}
 			// Retry...
@@ -241,7 +254,14 @@ func (r *Replica) handleWriteIntentError(
 
 	// Process and resolve write intent error.
 	var pushType roachpb.PushTxnType
-	if ba.IsWrite() {
+	if ba.WaitPolicy == lock.WaitPolicy_Error {
+		// The request must not wait for the intent holders, but it still
+		// pushes them to clean up abandoned intents. A PUSH_TOUCH push fails
+		// immediately if the pushee is still active.
+		pushType = roachpb.PUSH_TOUCH
+	} else if ba.IsWrite() || ba.IsLocking() {
+		// Writes and locking reads need the intents and locks of the pushee
+		// to be removed, which requires it to be aborted or finalized.
 		pushType = roachpb.PUSH_ABORT
 	} else {
 		pushType = roachpb.PUSH_TIMESTAMP
@@ -278,12 +298,19 @@ func (r *Replica) handleWriteIntentError(
 	if cleanup != nil {
 		cleanup(t, nil)
 	}
+	wiPErr := pErr
 	cleanup, pErr = r.store.intentResolver.ProcessWriteIntentError(ctx, pErr, h, pushType)
 	if pErr != nil {
 		// Do not propagate ambiguous results; assume success and retry original op.
 		if _, ok := pErr.GetDetail().(*roachpb.AmbiguousResultError); ok {
 			return cleanup, nil
 		}
+		// If the request has an Error wait policy and one of the intent holders
+		// is still active, return the original write intent error.
+		if _, ok := pErr.GetDetail().(*roachpb.TransactionPushError); ok &&
+			ba.WaitPolicy == lock.WaitPolicy_Error {
+			return cleanup, wiPErr
+		}
 		// Propagate new error. Preserve the error index.
 		pErr.Index = index
 		return cleanup, pErr
@@ -292,6 +319,69 @@ func (r *Replica) handleWriteIntentError(
 	return cleanup, nil
 }
 
+// maxConflictingLocksPerRequest bounds the number of conflicting unreplicated
+// locks that a request returns in a WriteIntentError.
+const maxConflictingLocksPerRequest = 100
+
+// checkUnreplicatedLockConflicts returns a WriteIntentError if a write or a
+// locking read in the batch conflicts with an unreplicated lock held by
+// another transaction. The error is handled like the ones returned by requests
+// that run into intents: the request pushes the lock holders and retries once
+// the locks have been released by the resolution of the holders' intents.
+//
+// Reads that skip locked keys don't conflict with any locks. They consult the
+// lock table during evaluation instead.
+func (r *Replica) checkUnreplicatedLockConflicts(ba *roachpb.BatchRequest) *roachpb.Error {
+	var txn *enginepb.TxnMeta
+	if ba.Txn != nil {
+		txn = &ba.Txn.TxnMeta
+	}
+	for i, union := range ba.Requests {
+		req := union.GetInner()
+		if roachpb.IsLocking(req) {
+			if ba.WaitPolicy == lock.WaitPolicy_SkipLocked {
+				continue
+			}
+		} else if !roachpb.IsTransactionWrite(req) {
+			continue
+		}
+		intents := r.unreplicatedLocks.FindConflictingLocks(
+			req.Header().Span(), txn, maxConflictingLocksPerRequest,
+		)
+		if len(intents) > 0 {
+			pErr := roachpb.NewError(&roachpb.WriteIntentError{Intents: intents})
+			pErr.SetErrorIndex(int32(i))
+			return pErr
+		}
+	}
+	return nil
+}
+
+// appendContentionEvents appends a ContentionEvent for each of the intents in
+// the provided WriteIntentError, which the request waited on for the provided
+// duration while pushing their transactions. The events are attributed to the
+// provided waiting transaction, which is nil for non-transactional requests.
+func appendContentionEvents(
+	evs []roachpb.ContentionEvent,
+	waiter *roachpb.Transaction,
+	t *roachpb.WriteIntentError,
+	dur time.Duration,
+) []roachpb.ContentionEvent {
+	var waiterID uuid.UUID
+	if waiter != nil {
+		waiterID = waiter.ID
+	}
+	for i := range t.Intents {
+		evs = append(evs, roachpb.ContentionEvent{
+			Key:          t.Intents[i].Key,
+			TxnMeta:      t.Intents[i].Txn,
+			Duration:     dur,
+			WaitingTxnID: waiterID,
+		})
+	}
+	return evs
+}
+
 func (r *Replica) handleTransactionPushError(
 	ctx context.Context,
 	ba *roachpb.BatchRequest,
@@ -403,7 +493,7 @@ func (r *Replica) executeAdminBatch(
 	switch tArgs := args.(type) {
 	case *roachpb.AdminSplitRequest:
 		var reply roachpb.AdminSplitResponse
-		reply, pErr = r.AdminSplit(ctx, *tArgs, "manual")
+		reply, pErr = r.AdminSplit(ctx, *tArgs, storagepb.ReasonAdminRequest, "manual")
 		resp = &reply
 
 	case *roachpb.AdminUnsplitRequest:
@@ -413,7 +503,7 @@ func (r *Replica) executeAdminBatch(
 
 	case *roachpb.AdminMergeRequest:
 		var reply roachpb.AdminMergeResponse
-		reply, pErr = r.AdminMerge(ctx, *tArgs, "manual")
+		reply, pErr = r.AdminMerge(ctx, *tArgs, storagepb.ReasonAdminRequest, "manual")
 		resp = &reply
 
 	case *roachpb.AdminTransferLeaseRequest:
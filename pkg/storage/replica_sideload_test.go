@@ -879,7 +879,7 @@ func TestRaftSSTableSideloadingSnapshot(t *testing.T) {
 			}
 			rsl := stateloader.Make(tc.repl.RangeID)
 			entries, err := entries(
-				ctx, rsl, tc.store.Engine(), tc.repl.RangeID, tc.store.raftEntryCache,
+				ctx, rsl, tc.store.Engine(), tc.store.RaftEngine(), tc.repl.RangeID, tc.store.raftEntryCache,
 				ss, sideloadedIndex, sideloadedIndex+1, 1<<20,
 			)
 			if err != nil {
@@ -12,6 +12,7 @@ package storage
 
 import (
 	"context"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
@@ -33,11 +34,29 @@ var SplitByLoadQPSThreshold = settings.RegisterIntSetting(
 	2500, // 2500 req/s
 )
 
+// SplitByLoadMergeDelay wraps "kv.range_split.by_load_merge_delay".
+//
+// The setting serves two purposes. Ranges created by a load-based split are
+// not merged away by the merge queue until the delay has passed, and the merge
+// queue only merges ranges whose load has stayed low for at least the delay.
+var SplitByLoadMergeDelay = settings.RegisterNonNegativeDurationSetting(
+	"kv.range_split.by_load_merge_delay",
+	"the delay that range splits created due to load will wait before considering being merged away",
+	5*time.Minute,
+)
+
 // SplitByLoadQPSThreshold returns the QPS request rate for a given replica.
 func (r *Replica) SplitByLoadQPSThreshold() float64 {
 	return float64(SplitByLoadQPSThreshold.Get(&r.store.cfg.Settings.SV))
 }
 
+// SplitByLoadMergeDelay returns the delay after a load-based split before the
+// resulting ranges may be merged away, which is also the period over which the
+// merge queue considers the load of a range.
+func (r *Replica) SplitByLoadMergeDelay() time.Duration {
+	return SplitByLoadMergeDelay.Get(&r.store.cfg.Settings.SV)
+}
+
 // SplitByLoadEnabled returns whether load based splitting is enabled.
 // Although this is a method of *Replica, the configuration is really global,
 // shared across all stores.
@@ -38,7 +38,10 @@ type perLocalityCounts map[string]float64
 
 // replicaStats maintains statistics about the work done by a replica. Its
 // initial use is tracking the number of requests received from each
-// cluster locality in order to inform lease transfer decisions.
+// cluster locality in order to inform lease transfer decisions. It is also
+// used, without locality information, to track the keys and bytes written to
+// a replica and the CPU time spent evaluating its requests, which inform
+// load-based rebalancing decisions.
 type replicaStats struct {
 	clock           *hlc.Clock
 	getNodeLocality localityOracle
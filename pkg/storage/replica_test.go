@@ -436,6 +436,21 @@ func createReplicaSets(replicaNumbers []roachpb.StoreID) []roachpb.ReplicaDescri
 	return result
 }
 
+// withNonVoters changes the type of the replicas on the given stores to
+// NON_VOTER.
+func withNonVoters(
+	replicas []roachpb.ReplicaDescriptor, storeIDs ...roachpb.StoreID,
+) []roachpb.ReplicaDescriptor {
+	for i := range replicas {
+		for _, storeID := range storeIDs {
+			if replicas[i].StoreID == storeID {
+				replicas[i].Type = roachpb.ReplicaTypeNonVoter()
+			}
+		}
+	}
+	return replicas
+}
+
 // TestIsOnePhaseCommit verifies the circumstances where a
 // transactional batch can be committed as an atomic write.
 func TestIsOnePhaseCommit(t *testing.T) {
@@ -6203,6 +6218,9 @@ func TestReplicaSetsEqual(t *testing.T) {
 		{true, createReplicaSets([]roachpb.StoreID{1, 1}), createReplicaSets([]roachpb.StoreID{1, 1})},
 		{false, createReplicaSets([]roachpb.StoreID{1, 1}), createReplicaSets([]roachpb.StoreID{1, 1, 1})},
 		{true, createReplicaSets([]roachpb.StoreID{1, 2, 3, 1, 2, 3}), createReplicaSets([]roachpb.StoreID{1, 1, 2, 2, 3, 3})},
+		{true, withNonVoters(createReplicaSets([]roachpb.StoreID{1, 2, 3}), 3), withNonVoters(createReplicaSets([]roachpb.StoreID{3, 2, 1}), 3)},
+		{false, withNonVoters(createReplicaSets([]roachpb.StoreID{1, 2, 3}), 3), createReplicaSets([]roachpb.StoreID{1, 2, 3})},
+		{false, withNonVoters(createReplicaSets([]roachpb.StoreID{1, 2, 3}), 3), withNonVoters(createReplicaSets([]roachpb.StoreID{1, 2, 3}), 2)},
 	}
 	for _, test := range testData {
 		if replicaSetsEqual(test.a, test.b) != test.expected {
@@ -12332,6 +12350,18 @@ func TestPrepareChangeReplicasTrigger(t *testing.T) {
 			typOp{roachpb.VOTER_FULL, noop},
 			typOp{roachpb.LEARNER, internalChangeTypeRemove},
 		),
+		// Simple addition of non-voter.
+		mk(
+			"SIMPLE(l2) ADD_REPLICA[(n200,s200):2NON_VOTER]: after=[(n100,s100):1 (n200,s200):2NON_VOTER] next=3",
+			typOp{roachpb.VOTER_FULL, noop},
+			typOp{none, internalChangeTypeAddNonVoter},
+		),
+		// Simple removal of non-voter.
+		mk(
+			"SIMPLE(r2) REMOVE_REPLICA[(n200,s200):2NON_VOTER]: after=[(n100,s100):1] next=3",
+			typOp{roachpb.VOTER_FULL, noop},
+			typOp{roachpb.NON_VOTER, internalChangeTypeRemove},
+		),
 
 		// All other cases below need to go through joint quorums (though some
 		// of them only due to limitations in etcd/raft).
@@ -100,6 +100,11 @@ func (r *Replica) executeWriteBatch(
 		return nil, roachpb.NewError(err)
 	}
 
+	// Check for conflicts with the unreplicated locks of other transactions.
+	if pErr := r.checkUnreplicatedLockConflicts(ba); pErr != nil {
+		return nil, pErr
+	}
+
 	minTS, untrack := r.store.cfg.ClosedTimestamp.Tracker.Track(ctx)
 	defer untrack(ctx, 0, 0, 0) // covers all error returns below
 
@@ -501,7 +506,9 @@ func (r *Replica) evaluateWriteBatchWrapper(
 	spans *spanset.SpanSet,
 ) (engine.Batch, *roachpb.BatchResponse, result.Result, *roachpb.Error) {
 	batch, opLogger := r.newBatchedEngine(spans)
+	evalStart := timeutil.Now()
 	br, res, pErr := evaluateBatch(ctx, idKey, batch, rec, ms, ba, false /* readOnly */)
+	r.recordRequestCPU(evalStart)
 	if pErr == nil {
 		if opLogger != nil {
 			res.LogicalOpLog = &storagepb.LogicalOpLog{
@@ -221,6 +221,7 @@ func (rq *replicateQueue) shouldQueue(
 		return true, priority
 	}
 	voterReplicas := desc.Replicas().Voters()
+	nonVoterReplicas := desc.Replicas().NonVoters()
 
 	if action == AllocatorNoop {
 		log.VEventf(ctx, 2, "no action to take")
@@ -233,11 +234,21 @@ func (rq *replicateQueue) shouldQueue(
 	if !rq.store.TestingKnobs().DisableReplicaRebalancing {
 		rangeUsageInfo := rangeUsageInfoForRepl(repl)
 		_, _, _, ok := rq.allocator.RebalanceTarget(
-			ctx, zone, repl.RaftStatus(), desc.RangeID, voterReplicas, rangeUsageInfo, storeFilterThrottled)
+			ctx, zone, repl.RaftStatus(), desc.RangeID, voterReplicas, nonVoterReplicas,
+			rangeUsageInfo, storeFilterThrottled)
 		if ok {
 			log.VEventf(ctx, 2, "rebalance target found, enqueuing")
 			return true, 0
 		}
+		if len(nonVoterReplicas) > 0 {
+			_, _, _, ok := rq.allocator.RebalanceNonVoter(
+				ctx, zone, desc.RangeID, voterReplicas, nonVoterReplicas, rangeUsageInfo,
+				storeFilterThrottled)
+			if ok {
+				log.VEventf(ctx, 2, "non-voter rebalance target found, enqueuing")
+				return true, 0
+			}
+		}
 		log.VEventf(ctx, 2, "no rebalance target found, not enqueuing")
 	}
 
@@ -410,6 +421,10 @@ func (rq *replicateQueue) processOneChange(
 		return rq.removeDead(ctx, repl, deadVoterReplicas, dryRun)
 	case AllocatorRemoveLearner:
 		return rq.removeLearner(ctx, repl, dryRun)
+	case AllocatorAddNonVoter:
+		return rq.addNonVoter(ctx, repl, dryRun)
+	case AllocatorRemoveNonVoter:
+		return rq.removeNonVoter(ctx, repl, dryRun)
 	case AllocatorConsiderRebalance:
 		return rq.considerRebalance(ctx, repl, voterReplicas, canTransferLease, dryRun)
 	case AllocatorFinalizeAtomicReplicationChange:
@@ -483,6 +498,7 @@ func (rq *replicateQueue) addOrReplace(
 		zone,
 		desc.RangeID,
 		remainingLiveReplicas,
+		desc.Replicas().NonVoters(),
 	)
 	if err != nil {
 		return false, err
@@ -496,7 +512,7 @@ func (rq *replicateQueue) addOrReplace(
 	}
 
 	clusterNodes := rq.allocator.storePool.ClusterNodeCount()
-	need := GetNeededReplicas(*zone.NumReplicas, clusterNodes)
+	need := GetNeededReplicas(zone.GetNumVoters(), clusterNodes)
 
 	// Only up-replicate if there are suitable allocation targets such that,
 	// either the replication goal is met, or it is possible to get to the next
@@ -524,6 +540,7 @@ func (rq *replicateQueue) addOrReplace(
 			zone,
 			desc.RangeID,
 			oldPlusNewReplicas,
+			desc.Replicas().NonVoters(),
 		)
 		if err != nil {
 			// It does not seem possible to go to the next odd replica state. Note
@@ -720,7 +737,7 @@ func (rq *replicateQueue) removeDecommissioning(
 ) (requeue bool, _ error) {
 	desc, _ := repl.DescAndZone()
 	decommissioningReplicas := rq.allocator.storePool.decommissioningReplicas(
-		desc.RangeID, desc.Replicas().All())
+		desc.RangeID, desc.Replicas().Voters())
 	if len(decommissioningReplicas) == 0 {
 		log.VEventf(ctx, 1, "range of replica %s was identified as having decommissioning replicas, "+
 			"but no decommissioning replicas were found", repl)
@@ -824,6 +841,103 @@ func (rq *replicateQueue) removeLearner(
 	return true, nil
 }
 
+// addNonVoter adds a non-voting replica to the range. Since non-voters don't
+// affect quorum, there are no concerns about fragile intermediate states like
+// in addOrReplace.
+func (rq *replicateQueue) addNonVoter(
+	ctx context.Context, repl *Replica, dryRun bool,
+) (requeue bool, _ error) {
+	desc, zone := repl.DescAndZone()
+	newStore, details, err := rq.allocator.AllocateNonVoter(
+		ctx,
+		zone,
+		desc.RangeID,
+		desc.Replicas().Voters(),
+		desc.Replicas().NonVoters(),
+	)
+	if err != nil {
+		return false, err
+	}
+	newReplica := roachpb.ReplicationTarget{
+		NodeID:  newStore.Node.NodeID,
+		StoreID: newStore.StoreID,
+	}
+	rq.metrics.AddReplicaCount.Inc(1)
+	log.VEventf(ctx, 1, "adding non-voter %+v: %s",
+		newReplica, rangeRaftProgress(repl.RaftStatus(), desc.Replicas().All()))
+	if err := rq.changeReplicas(
+		ctx,
+		repl,
+		roachpb.MakeReplicationChanges(roachpb.ADD_NON_VOTER, newReplica),
+		desc,
+		SnapshotRequest_RECOVERY,
+		storagepb.ReasonRangeUnderReplicated,
+		details,
+		dryRun,
+	); err != nil {
+		return false, err
+	}
+	// Always requeue to see if more work needs to be done.
+	return true, nil
+}
+
+// removeNonVoter removes a non-voting replica from the range. Dead and
+// decommissioning non-voters are removed first, otherwise the allocator picks
+// the least desirable one.
+func (rq *replicateQueue) removeNonVoter(
+	ctx context.Context, repl *Replica, dryRun bool,
+) (requeue bool, _ error) {
+	desc, zone := repl.DescAndZone()
+	nonVoterReplicas := desc.Replicas().NonVoters()
+	if len(nonVoterReplicas) == 0 {
+		log.VEventf(ctx, 1, "range of replica %s was identified as having non-voters to remove, "+
+			"but no non-voters were found", repl)
+		return true, nil
+	}
+
+	reason := storagepb.ReasonRangeOverReplicated
+	var removeReplica roachpb.ReplicaDescriptor
+	var details string
+	_, deadNonVoters := rq.allocator.storePool.liveAndDeadReplicas(desc.RangeID, nonVoterReplicas)
+	decommissioningNonVoters := rq.allocator.storePool.decommissioningReplicas(
+		desc.RangeID, nonVoterReplicas)
+	if len(deadNonVoters) > 0 {
+		removeReplica, reason = deadNonVoters[0], storagepb.ReasonStoreDead
+	} else if len(decommissioningNonVoters) > 0 {
+		removeReplica, reason = decommissioningNonVoters[0], storagepb.ReasonStoreDecommissioning
+	} else {
+		var err error
+		removeReplica, details, err = rq.allocator.RemoveNonVoter(
+			ctx, zone, nonVoterReplicas, desc.Replicas().Voters(), nonVoterReplicas)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	// NB: non-voters can't hold the lease, so there's no need to transfer it
+	// away first.
+	rq.metrics.RemoveReplicaCount.Inc(1)
+	log.VEventf(ctx, 1, "removing non-voter %+v: %s",
+		removeReplica, rangeRaftProgress(repl.RaftStatus(), desc.Replicas().All()))
+	target := roachpb.ReplicationTarget{
+		NodeID:  removeReplica.NodeID,
+		StoreID: removeReplica.StoreID,
+	}
+	if err := rq.changeReplicas(
+		ctx,
+		repl,
+		roachpb.MakeReplicationChanges(roachpb.REMOVE_NON_VOTER, target),
+		desc,
+		SnapshotRequest_UNKNOWN, // unused
+		reason,
+		details,
+		dryRun,
+	); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 func (rq *replicateQueue) considerRebalance(
 	ctx context.Context,
 	repl *Replica,
@@ -837,10 +951,17 @@ func (rq *replicateQueue) considerRebalance(
 	if !rq.store.TestingKnobs().DisableReplicaRebalancing {
 		rangeUsageInfo := rangeUsageInfoForRepl(repl)
 		addTarget, removeTarget, details, ok := rq.allocator.RebalanceTarget(
-			ctx, zone, repl.RaftStatus(), desc.RangeID, existingReplicas, rangeUsageInfo,
-			storeFilterThrottled)
+			ctx, zone, repl.RaftStatus(), desc.RangeID, existingReplicas, desc.Replicas().NonVoters(),
+			rangeUsageInfo, storeFilterThrottled)
 		if !ok {
 			log.VEventf(ctx, 1, "no suitable rebalance target")
+			if nonVoterReplicas := desc.Replicas().NonVoters(); len(nonVoterReplicas) > 0 {
+				requeue, err := rq.considerNonVoterRebalance(
+					ctx, repl, nonVoterReplicas, rangeUsageInfo, dryRun)
+				if err != nil || requeue {
+					return requeue, err
+				}
+			}
 		} else if done, err := rq.maybeTransferLeaseAway(ctx, repl, removeTarget.StoreID, dryRun); err != nil {
 			log.VEventf(ctx, 1, "want to remove self, but failed to transfer lease away: %s", err)
 		} else if done {
@@ -932,6 +1053,46 @@ func (rq *replicateQueue) considerRebalance(
 	return false, nil
 }
 
+// considerNonVoterRebalance moves a non-voting replica to a better store if the
+// allocator finds one. Non-voters don't hold the lease and don't affect quorum,
+// so the swap is always carried out atomically.
+func (rq *replicateQueue) considerNonVoterRebalance(
+	ctx context.Context,
+	repl *Replica,
+	nonVoterReplicas []roachpb.ReplicaDescriptor,
+	rangeUsageInfo RangeUsageInfo,
+	dryRun bool,
+) (requeue bool, _ error) {
+	desc, zone := repl.DescAndZone()
+	addTarget, removeTarget, details, ok := rq.allocator.RebalanceNonVoter(
+		ctx, zone, desc.RangeID, desc.Replicas().Voters(), nonVoterReplicas, rangeUsageInfo,
+		storeFilterThrottled)
+	if !ok {
+		log.VEventf(ctx, 1, "no suitable non-voter rebalance target")
+		return false, nil
+	}
+	chgs := []roachpb.ReplicationChange{
+		{Target: addTarget, ChangeType: roachpb.ADD_NON_VOTER},
+		{Target: removeTarget, ChangeType: roachpb.REMOVE_NON_VOTER},
+	}
+	rq.metrics.RebalanceReplicaCount.Inc(1)
+	log.VEventf(ctx, 1, "rebalancing non-voter %+v to %+v: %s",
+		removeTarget, addTarget, rangeRaftProgress(repl.RaftStatus(), desc.Replicas().All()))
+	if err := rq.changeReplicas(
+		ctx,
+		repl,
+		chgs,
+		desc,
+		SnapshotRequest_REBALANCE,
+		storagepb.ReasonRebalance,
+		details,
+		dryRun,
+	); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 type transferLeaseOptions struct {
 	checkTransferLeaseSource bool
 	checkCandidateFullness   bool
@@ -967,16 +1128,15 @@ func (rq *replicateQueue) findTargetAndTransferLease(
 		return false, nil
 	}
 
-	avgQPS, qpsMeasurementDur := repl.leaseholderStats.avgQPS()
-	if qpsMeasurementDur < MinStatsDuration {
-		avgQPS = 0
-	}
-	err := rq.transferLease(ctx, repl, target, avgQPS)
+	err := rq.transferLease(ctx, repl, target, rangeUsageInfoForRepl(repl))
 	return err == nil, err
 }
 
 func (rq *replicateQueue) transferLease(
-	ctx context.Context, repl *Replica, target roachpb.ReplicaDescriptor, rangeQPS float64,
+	ctx context.Context,
+	repl *Replica,
+	target roachpb.ReplicaDescriptor,
+	rangeUsageInfo RangeUsageInfo,
 ) error {
 	rq.metrics.TransferLeaseCount.Inc(1)
 	log.VEventf(ctx, 1, "transferring lease to s%d", target.StoreID)
@@ -985,7 +1145,7 @@ func (rq *replicateQueue) transferLease(
 	}
 	rq.lastLeaseTransfer.Store(timeutil.Now())
 	rq.allocator.storePool.updateLocalStoresAfterLeaseTransfer(
-		repl.store.StoreID(), target.StoreID, rangeQPS)
+		repl.store.StoreID(), target.StoreID, rangeUsageInfo)
 	return nil
 }
 
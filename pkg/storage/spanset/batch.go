@@ -338,6 +338,10 @@ func (s spanSetReader) Iterate(
 }
 
 func (s spanSetReader) NewIterator(opts engine.IterOptions) engine.Iterator {
+	if opts.RangeTombstoneLookup {
+		// See the comment on IterOptions.RangeTombstoneLookup.
+		return s.r.NewIterator(opts)
+	}
 	if s.spansOnly {
 		return NewIterator(s.r.NewIterator(opts), s.spans)
 	}
@@ -36,9 +36,14 @@ const minSplitSuggestionInterval = time.Minute
 // to carry out a split. When the split is initiated, it can obtain the suggested
 // split point from MaybeSplitKey (which may have disappeared either due to a drop
 // in qps or a change in the workload).
+//
+// The Decider additionally tracks the maximum qps observed over a retention
+// period (see MaxQPS), which is used to avoid merging ranges that were recently
+// hot enough to be split by load.
 type Decider struct {
-	intn         func(n int) int // supplied to Init
-	qpsThreshold func() float64  // supplied to Init
+	intn            func(n int) int      // supplied to Init
+	qpsThreshold    func() float64       // supplied to Init
+	maxQPSRetention func() time.Duration // supplied to Init
 
 	mu struct {
 		syncutil.Mutex
@@ -48,6 +53,8 @@ type Decider struct {
 		count               int64     // number of requests recorded since last rollover
 		splitFinder         *Finder   // populated when engaged or decided
 		lastSplitSuggestion time.Time // last stipulation to client to carry out split
+
+		maxQPS maxQPSTracker // max qps observed over the retention period
 	}
 }
 
@@ -55,9 +62,15 @@ type Decider struct {
 // embedding the Decider into a larger struct outside of the scope of this package
 // without incurring a pointer reference. This is relevant since many Deciders
 // may exist in the system at any given point in time.
-func Init(lbs *Decider, intn func(n int) int, qpsThreshold func() float64) {
+func Init(
+	lbs *Decider,
+	intn func(n int) int,
+	qpsThreshold func() float64,
+	maxQPSRetention func() time.Duration,
+) {
 	lbs.intn = intn
 	lbs.qpsThreshold = qpsThreshold
+	lbs.maxQPSRetention = maxQPSRetention
 }
 
 // Record notifies the Decider that 'n' operations are being carried out which
@@ -90,6 +103,7 @@ func (d *Decider) recordLocked(now time.Time, n int, span func() roachpb.Span) b
 		d.mu.qps = (float64(d.mu.count) / float64(elapsedSinceLastQPS)) * 1e9
 		d.mu.lastQPSRollover = now
 		d.mu.count = 0
+		d.mu.maxQPS.record(now, d.maxQPSRetention(), d.mu.qps)
 
 		// If the QPS for the range exceeds the threshold, start actively
 		// tracking potential for splitting this range based on load.
@@ -129,6 +143,40 @@ func (d *Decider) LastQPS(now time.Time) float64 {
 	return qps
 }
 
+// MaxQPS returns the maximum QPS measurement recorded over the retention
+// period. If the Decider has not yet accumulated a full retention period of
+// history, for instance because the replica only recently acquired its lease,
+// false is returned.
+func (d *Decider) MaxQPS(now time.Time) (float64, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.recordLocked(now, 0, nil)
+	maxQPS, ok := d.mu.maxQPS.max(now, d.maxQPSRetention())
+	if !ok {
+		return 0, false
+	}
+	if d.mu.qps > maxQPS {
+		maxQPS = d.mu.qps
+	}
+	return maxQPS, true
+}
+
+// ResetMaxQPS discards the history of QPS measurements used by MaxQPS. The
+// history is seeded with the supplied maximum, which is assumed to cover a full
+// retention period, if it is positive. Otherwise, MaxQPS will not report a
+// maximum until a full retention period of history has been accumulated.
+func (d *Decider) ResetMaxQPS(now time.Time, seedMaxQPS float64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if seedMaxQPS > 0 {
+		d.mu.maxQPS.seed(now, d.maxQPSRetention(), seedMaxQPS)
+	} else {
+		d.mu.maxQPS.reset(now)
+	}
+}
+
 // MaybeSplitKey returns a key to perform a split at. The return value will be
 // nil if either the Decider hasn't decided that a split should be carried out
 // or if it wasn't able to determine a suitable split key.
@@ -182,7 +230,8 @@ func (d *Decider) MaybeSplitKey(now time.Time) roachpb.Key {
 	return key
 }
 
-// Reset deactivates any current attempt at determining a split key.
+// Reset deactivates any current attempt at determining a split key. It does
+// not discard the history of QPS measurements used by MaxQPS.
 func (d *Decider) Reset() {
 	d.mu.Lock()
 	d.mu.splitFinder = nil
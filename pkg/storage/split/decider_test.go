@@ -30,7 +30,7 @@ func TestDecider(t *testing.T) {
 	intn := rand.New(rand.NewSource(12)).Intn
 
 	var d Decider
-	Init(&d, intn, func() float64 { return 10.0 })
+	Init(&d, intn, func() float64 { return 10.0 }, func() time.Duration { return time.Minute })
 
 	ms := func(i int) time.Time {
 		ts, err := time.Parse(time.RFC3339, "2000-01-01T00:00:00Z")
@@ -180,7 +180,7 @@ func TestDeciderCallsEnsureSafeSplitKey(t *testing.T) {
 	intn := rand.New(rand.NewSource(11)).Intn
 
 	var d Decider
-	Init(&d, intn, func() float64 { return 1.0 })
+	Init(&d, intn, func() float64 { return 1.0 }, func() time.Duration { return time.Minute })
 
 	baseKey := keys.MakeTablePrefix(51)
 	for i := 0; i < 4; i++ {
@@ -213,7 +213,7 @@ func TestDeciderIgnoresEnsureSafeSplitKeyOnError(t *testing.T) {
 	intn := rand.New(rand.NewSource(11)).Intn
 
 	var d Decider
-	Init(&d, intn, func() float64 { return 1.0 })
+	Init(&d, intn, func() float64 { return 1.0 }, func() time.Duration { return time.Minute })
 
 	baseKey := keys.MakeTablePrefix(51)
 	for i := 0; i < 4; i++ {
@@ -244,3 +244,50 @@ func TestDeciderIgnoresEnsureSafeSplitKeyOnError(t *testing.T) {
 
 	require.Equal(t, c1().Key, k)
 }
+
+func TestDeciderMaxQPS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	intn := rand.New(rand.NewSource(11)).Intn
+
+	var d Decider
+	Init(&d, intn, func() float64 { return 100.0 }, func() time.Duration { return time.Minute })
+
+	now := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	op := func() roachpb.Span { return roachpb.Span{Key: roachpb.Key("a")} }
+
+	// Record 10 operations per second for ten seconds.
+	for i := 0; i < 10; i++ {
+		d.Record(now, 10, op)
+		now = now.Add(time.Second)
+	}
+
+	// The Decider does not have a full retention period of history yet.
+	qps, ok := d.MaxQPS(now)
+	require.False(t, ok)
+	require.Zero(t, qps)
+
+	// Once it does, the maximum over the retention period is reported even
+	// though the range has since become idle.
+	now = now.Add(time.Minute)
+	qps, ok = d.MaxQPS(now)
+	require.True(t, ok)
+	require.Equal(t, 10.0, qps)
+	require.Zero(t, d.LastQPS(now))
+
+	// Resetting the split finder does not affect the history.
+	d.Reset()
+	qps, ok = d.MaxQPS(now)
+	require.True(t, ok)
+	require.Equal(t, 10.0, qps)
+
+	// Resetting the history without a seed discards it.
+	d.ResetMaxQPS(now, 0)
+	_, ok = d.MaxQPS(now)
+	require.False(t, ok)
+
+	// Resetting the history with a seed replaces it.
+	d.ResetMaxQPS(now, 42)
+	qps, ok = d.MaxQPS(now)
+	require.True(t, ok)
+	require.Equal(t, 42.0, qps)
+}
@@ -0,0 +1,101 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package split
+
+import "time"
+
+// maxQPSTrackerWindows is the number of time windows that a maxQPSTracker
+// partitions its retention period into.
+const maxQPSTrackerWindows = 6
+
+// maxQPSTracker collects a series of queries-per-second measurements and
+// tracks the maximum observed over a configurable retention period.
+//
+// The tracker internally partitions the retention period into a ring of
+// windows, each of which holds the maximum QPS recorded while it was current.
+// Windows age out of the ring as time advances, so the tracked maximum decays
+// once the load that produced it is older than the retention period. The ring
+// holds one more window than the retention period is divided into, which
+// guarantees that the maximum always covers at least the full retention
+// period.
+type maxQPSTracker struct {
+	windows   [maxQPSTrackerWindows]float64
+	curIdx    int
+	curStart  time.Time // start time of the current window
+	lastReset time.Time // time at which the tracker began accumulating history
+}
+
+// reset discards all history accumulated by the tracker. The tracker will not
+// report a maximum until it has accumulated a full retention period of history
+// again.
+func (t *maxQPSTracker) reset(now time.Time) {
+	*t = maxQPSTracker{curStart: now, lastReset: now}
+}
+
+// seed discards all history accumulated by the tracker and replaces it with
+// the supplied maximum, which is assumed to cover a full retention period. It
+// is used to carry load history over from another tracker, for instance one
+// owned by a previous leaseholder of the range.
+func (t *maxQPSTracker) seed(now time.Time, minRetention time.Duration, qps float64) {
+	t.reset(now)
+	t.lastReset = now.Add(-minRetention)
+	t.windows[t.curIdx] = qps
+}
+
+// record records a QPS measurement at the supplied time.
+func (t *maxQPSTracker) record(now time.Time, minRetention time.Duration, qps float64) {
+	t.maybeRotate(now, minRetention)
+	if qps > t.windows[t.curIdx] {
+		t.windows[t.curIdx] = qps
+	}
+}
+
+// max returns the maximum QPS measurement recorded over the retention period.
+// If the tracker has not yet accumulated a full retention period of history,
+// false is returned.
+func (t *maxQPSTracker) max(now time.Time, minRetention time.Duration) (float64, bool) {
+	t.maybeRotate(now, minRetention)
+	if now.Sub(t.lastReset) < minRetention {
+		return 0, false
+	}
+	var max float64
+	for _, qps := range t.windows {
+		if qps > max {
+			max = qps
+		}
+	}
+	return max, true
+}
+
+func (t *maxQPSTracker) maybeRotate(now time.Time, minRetention time.Duration) {
+	if t.lastReset.IsZero() {
+		// The tracker was never reset. Start accumulating history now.
+		t.reset(now)
+		return
+	}
+	windowWidth := minRetention / (maxQPSTrackerWindows - 1)
+	if windowWidth <= 0 {
+		windowWidth = 1
+	}
+	sinceWindowStart := now.Sub(t.curStart)
+	if sinceWindowStart < windowWidth {
+		return
+	}
+	shift := int(sinceWindowStart / windowWidth)
+	if shift > maxQPSTrackerWindows {
+		shift = maxQPSTrackerWindows
+	}
+	for i := 0; i < shift; i++ {
+		t.curIdx = (t.curIdx + 1) % maxQPSTrackerWindows
+		t.windows[t.curIdx] = 0
+	}
+	t.curStart = t.curStart.Add(sinceWindowStart.Truncate(windowWidth))
+}
@@ -0,0 +1,66 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package split
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxQPSTracker(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const retention = 5 * time.Minute
+	start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+
+	var tr maxQPSTracker
+	assertMax := func(d time.Duration, expQPS float64, expOK bool) {
+		t.Helper()
+		qps, ok := tr.max(at(d), retention)
+		require.Equal(t, expOK, ok)
+		require.Equal(t, expQPS, qps)
+	}
+
+	// The tracker starts accumulating history when it is first used and does
+	// not report a maximum until it has a full retention period of history.
+	assertMax(0, 0, false)
+	tr.record(at(10*time.Second), retention, 10)
+	tr.record(at(time.Minute), retention, 20)
+	tr.record(at(90*time.Second), retention, 15)
+	assertMax(4*time.Minute, 0, false)
+	assertMax(5*time.Minute, 20, true)
+
+	// Measurements age out of the maximum once they are older than the
+	// retention period.
+	tr.record(at(6*time.Minute), retention, 5)
+	assertMax(6*time.Minute, 20, true)
+	assertMax(7*time.Minute, 5, true)
+	assertMax(20*time.Minute, 0, true)
+
+	// Resetting the tracker discards its history.
+	tr.record(at(20*time.Minute), retention, 30)
+	tr.reset(at(20 * time.Minute))
+	assertMax(20*time.Minute, 0, false)
+	assertMax(25*time.Minute, 0, true)
+
+	// Seeding the tracker replaces its history with a maximum covering a full
+	// retention period.
+	tr.seed(at(25*time.Minute), retention, 40)
+	assertMax(25*time.Minute, 40, true)
+	tr.record(at(26*time.Minute), retention, 50)
+	assertMax(26*time.Minute, 50, true)
+	assertMax(31*time.Minute, 50, true)
+	assertMax(32*time.Minute, 0, true)
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/storage/storagepb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -171,6 +172,7 @@ func (sq *splitQueue) processAttempt(
 			},
 			desc,
 			false, /* delayable */
+			storagepb.ReasonZoneConfig,
 			"zone config",
 		); err != nil {
 			return errors.Wrapf(err, "unable to split %s at key %q", r, splitKey)
@@ -189,6 +191,7 @@ func (sq *splitQueue) processAttempt(
 			roachpb.AdminSplitRequest{},
 			desc,
 			false, /* delayable */
+			storagepb.ReasonRangeSizeExceeded,
 			fmt.Sprintf("%s above threshold size %s", humanizeutil.IBytes(size), humanizeutil.IBytes(maxBytes)),
 		)
 		return err
@@ -206,16 +209,26 @@ func (sq *splitQueue) processAttempt(
 			batchHandledQPS,
 			raftAppliedQPS,
 		)
+		// Set the sticky bit on the new range for the duration of the merge
+		// delay. This keeps the merge queue from immediately undoing the split,
+		// which would otherwise let a hot range oscillate between being split
+		// and merged.
+		var expTime hlc.Timestamp
+		if mergeDelay := r.SplitByLoadMergeDelay(); mergeDelay > 0 {
+			expTime = r.store.Clock().Now().Add(mergeDelay.Nanoseconds(), 0)
+		}
 		if _, pErr := r.adminSplitWithDescriptor(
 			ctx,
 			roachpb.AdminSplitRequest{
 				RequestHeader: roachpb.RequestHeader{
 					Key: splitByLoadKey,
 				},
-				SplitKey: splitByLoadKey,
+				SplitKey:       splitByLoadKey,
+				ExpirationTime: expTime,
 			},
 			desc,
 			false, /* delayable */
+			storagepb.ReasonRangeLoadExceeded,
 			reason,
 		); pErr != nil {
 			return errors.Wrapf(pErr, "unable to split %s at key %q", r, splitByLoadKey)
@@ -470,10 +470,14 @@ func (rsl StateLoader) SetGCThreshold(
 
 // The rest is not technically part of ReplicaState.
 
-// LoadLastIndex loads the last index.
-func (rsl StateLoader) LoadLastIndex(ctx context.Context, reader engine.Reader) (uint64, error) {
+// LoadLastIndex loads the last index. The Raft log is read from raftReader,
+// which is the same as reader unless the store keeps its Raft log in a
+// separate engine. The truncated state is always read from reader.
+func (rsl StateLoader) LoadLastIndex(
+	ctx context.Context, reader, raftReader engine.Reader,
+) (uint64, error) {
 	prefix := rsl.RaftLogPrefix()
-	iter := reader.NewIterator(engine.IterOptions{LowerBound: prefix})
+	iter := raftReader.NewIterator(engine.IterOptions{LowerBound: prefix})
 	defer iter.Close()
 
 	var lastIndex uint64
@@ -635,3 +639,67 @@ func (rsl StateLoader) SynthesizeHardState(
 	err := rsl.SetHardState(ctx, readWriter, newHS)
 	return errors.Wrapf(err, "writing HardState %+v", &newHS)
 }
+
+// ReconcileRaftState brings the Raft log and HardState stored in raftRW in
+// line with the replica state in reader. It is only needed when the two live
+// in different engines, in which case writes to them can't be atomic and a
+// crash may leave the Raft engine lagging behind the state engine.
+//
+// Log truncations remove the truncated entries from the Raft engine only after
+// the new TruncatedState has been committed, so entries at or below the
+// truncated index may have been left behind; these are removed.
+//
+// Snapshots and splits write the replica state first, so the Raft engine may
+// still hold the HardState (and, for snapshots, the log) of the replica's
+// prior incarnation. This is detected by a HardState whose Commit index trails
+// the applied index, which can't happen otherwise since entries are only
+// applied once their commit index is durable. Applying a snapshot discards the
+// entire log, so we do the same and synthesize a HardState matching the
+// applied index.
+//
+// The repair is idempotent and a no-op for a replica that has no replica
+// state (i.e. an uninitialized one).
+func (rsl StateLoader) ReconcileRaftState(
+	ctx context.Context, reader engine.Reader, raftRW engine.ReadWriter,
+) error {
+	appliedIndex, _, err := rsl.LoadAppliedIndex(ctx, reader)
+	if err != nil || appliedIndex == 0 {
+		return err
+	}
+	truncState, _, err := rsl.LoadRaftTruncatedState(ctx, reader)
+	if err != nil {
+		return err
+	}
+	hs, err := rsl.LoadHardState(ctx, raftRW)
+	if err != nil {
+		return err
+	}
+	if hs.Commit >= appliedIndex {
+		return rsl.clearRaftLogUpTo(raftRW, truncState.Index+1)
+	}
+	log.Eventf(ctx, "discarding Raft log: HardState commit index %d trails applied index %d",
+		hs.Commit, appliedIndex)
+	if err := rsl.clearRaftLogUpTo(raftRW, math.MaxUint64); err != nil {
+		return err
+	}
+	return rsl.SynthesizeHardState(ctx, raftRW, hs, truncState, appliedIndex)
+}
+
+// clearRaftLogUpTo removes all Raft log entries with an index below end. Only
+// entries actually present are cleared, which in the common case is none.
+func (rsl StateLoader) clearRaftLogUpTo(raftRW engine.ReadWriter, end uint64) error {
+	prefix := rsl.RaftLogPrefix()
+	endKey := rsl.RaftLogKey(end)
+	iter := raftRW.NewIterator(engine.IterOptions{UpperBound: endKey})
+	defer iter.Close()
+	for iter.SeekGE(engine.MakeMVCCMetadataKey(prefix)); ; iter.Next() {
+		if ok, err := iter.Valid(); err != nil {
+			return err
+		} else if !ok {
+			return nil
+		}
+		if err := raftRW.Clear(iter.UnsafeKey()); err != nil {
+			return err
+		}
+	}
+}
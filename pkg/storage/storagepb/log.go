@@ -23,4 +23,8 @@ const (
 	ReasonRebalance            RangeLogEventReason = "rebalance"
 	ReasonAdminRequest         RangeLogEventReason = "admin request"
 	ReasonAbandonedLearner     RangeLogEventReason = "abandoned learner replica"
+	ReasonZoneConfig           RangeLogEventReason = "zone config"
+	ReasonRangeSizeExceeded    RangeLogEventReason = "range size exceeded"
+	ReasonRangeLoadExceeded    RangeLogEventReason = "range load exceeded"
+	ReasonRangeUnderutilized   RangeLogEventReason = "range size and load below threshold"
 )
@@ -365,6 +365,7 @@ type Store struct {
 	cfg                StoreConfig
 	db                 *client.DB
 	engine             engine.Engine        // The underlying key-value store
+	raftEngine         engine.Engine        // Holds the Raft log and HardState; may be engine
 	compactor          *compactor.Compactor // Schedules compaction of the engine
 	tsCache            tscache.Cache        // Most recent timestamps for keys / key ranges
 	allocator          Allocator            // Makes allocation decisions
@@ -764,6 +765,18 @@ func (sc *StoreConfig) LeaseExpiration() int64 {
 // NewStore returns a new instance of a store.
 func NewStore(
 	ctx context.Context, cfg StoreConfig, eng engine.Engine, nodeDesc *roachpb.NodeDescriptor,
+) *Store {
+	return NewStoreWithRaftEngine(ctx, cfg, eng, eng, nodeDesc)
+}
+
+// NewStoreWithRaftEngine returns a new instance of a store which keeps the
+// Raft log and HardState of its replicas in raftEng rather than in eng. If
+// raftEng is eng, the store is equivalent to one returned by NewStore.
+func NewStoreWithRaftEngine(
+	ctx context.Context,
+	cfg StoreConfig,
+	eng, raftEng engine.Engine,
+	nodeDesc *roachpb.NodeDescriptor,
 ) *Store {
 	// TODO(tschottdorf): find better place to set these defaults.
 	cfg.SetDefaults()
@@ -772,11 +785,12 @@ func NewStore(
 		log.Fatalf(ctx, "invalid store configuration: %+v", &cfg)
 	}
 	s := &Store{
-		cfg:      cfg,
-		db:       cfg.DB, // TODO(tschottdorf): remove redundancy.
-		engine:   eng,
-		nodeDesc: nodeDesc,
-		metrics:  newStoreMetrics(cfg.HistogramWindowInterval),
+		cfg:        cfg,
+		db:         cfg.DB, // TODO(tschottdorf): remove redundancy.
+		engine:     eng,
+		raftEngine: raftEng,
+		nodeDesc:   nodeDesc,
+		metrics:    newStoreMetrics(cfg.HistogramWindowInterval),
 	}
 	if cfg.RPCContext != nil {
 		s.allocator = MakeAllocator(cfg.StorePool, cfg.RPCContext.RemoteClocks.Latency)
@@ -1288,6 +1302,12 @@ func (s *Store) Start(ctx context.Context, stopper *stop.Stopper) error {
 		s.cfg.Gossip.NodeID.Set(ctx, s.Ident.NodeID)
 	}
 
+	// Move the Raft state into the store's Raft engine, if it was configured
+	// with a separate one for the first time.
+	if err := s.initRaftEngine(ctx); err != nil {
+		return errors.Wrap(err, "while initializing Raft engine")
+	}
+
 	// Create ID allocators.
 	idAlloc, err := idalloc.NewAllocator(
 		s.cfg.AmbientCtx, keys.RangeIDGenerator, s.db, rangeIDAllocCount, s.stopper,
@@ -1329,6 +1349,10 @@ func (s *Store) Start(ctx context.Context, stopper *stop.Stopper) error {
 	// concurrently. Note that while we can perform this initialization
 	// concurrently, all of the initialization must be performed before we start
 	// listening for Raft messages and starting the process Raft loop.
+	quarantineMarkers, err := loadQuarantineMarkers(s.engine)
+	if err != nil {
+		return errors.Wrap(err, "while loading quarantined replicas")
+	}
 	err = IterateRangeDescriptors(ctx, s.engine,
 		func(desc roachpb.RangeDescriptor) (bool, error) {
 			if !desc.IsInitialized() {
@@ -1353,6 +1377,16 @@ func (s *Store) Start(ctx context.Context, stopper *stop.Stopper) error {
 				return false, err
 			}
 
+			// A replica that was quarantined by the consistency checker stays
+			// quarantined until an operator removes its marker.
+			if _, ok := quarantineMarkers[quarantineMarkerName(desc.RangeID, replicaDesc.ReplicaID)]; ok {
+				rep.mu.Lock()
+				rep.setQuarantinedLocked()
+				rep.mu.Unlock()
+				log.Errorf(ctx, "replica %s remains quarantined; see %s", rep,
+					filepath.Join(s.engine.GetAuxiliaryDir(), quarantinedReplicasDir))
+			}
+
 			// We can't lock s.mu across NewReplica due to the lock ordering
 			// constraint (*Replica).raftMu < (*Store).mu. See the comment on
 			// (Store).mu.
@@ -1387,6 +1421,10 @@ func (s *Store) Start(ctx context.Context, stopper *stop.Stopper) error {
 		return err
 	}
 
+	if err := s.clearLeakedRaftEngineData(ctx); err != nil {
+		return errors.Wrap(err, "while removing leaked Raft state")
+	}
+
 	// Start Raft processing goroutines.
 	s.cfg.Transport.Listen(s.StoreID(), s)
 	s.processRaft(ctx)
@@ -2043,6 +2081,11 @@ func (s *Store) Clock() *hlc.Clock { return s.cfg.Clock }
 // Engine accessor.
 func (s *Store) Engine() engine.Engine { return s.engine }
 
+// RaftEngine returns the engine holding the Raft log and HardState of the
+// store's replicas. Unless the store was configured with a separate Raft
+// engine, this is the same as Engine().
+func (s *Store) RaftEngine() engine.Engine { return s.raftEngine }
+
 // DB accessor.
 func (s *Store) DB() *client.DB { return s.cfg.DB }
 
@@ -2102,6 +2145,8 @@ func (s *Store) Capacity(useCached bool) (roachpb.StoreCapacity, error) {
 	var logicalBytes int64
 	var totalQueriesPerSecond float64
 	var totalWritesPerSecond float64
+	var totalWriteBytesPerSecond float64
+	var totalCPUPerSecond float64
 	replicaCount := s.metrics.ReplicaCount.Value()
 	bytesPerReplica := make([]float64, 0, replicaCount)
 	writesPerReplica := make([]float64, 0, replicaCount)
@@ -2128,9 +2173,21 @@ func (s *Store) Capacity(useCached bool) (roachpb.StoreCapacity, error) {
 			totalWritesPerSecond += wps
 			writesPerReplica = append(writesPerReplica, wps)
 		}
+		var writeBytes float64
+		if wbps, dur := r.writeBytesStats.avgQPS(); dur >= MinStatsDuration {
+			writeBytes = wbps
+			totalWriteBytesPerSecond += wbps
+		}
+		var cpu float64
+		if cpuPerSecond, dur := r.cpuStats.avgQPS(); dur >= MinStatsDuration {
+			cpu = cpuPerSecond
+			totalCPUPerSecond += cpuPerSecond
+		}
 		rankingsAccumulator.addReplica(replicaWithStats{
-			repl: r,
-			qps:  qps,
+			repl:       r,
+			qps:        qps,
+			writeBytes: writeBytes,
+			cpu:        cpu,
 		})
 		return true
 	})
@@ -2139,6 +2196,8 @@ func (s *Store) Capacity(useCached bool) (roachpb.StoreCapacity, error) {
 	capacity.LogicalBytes = logicalBytes
 	capacity.QueriesPerSecond = totalQueriesPerSecond
 	capacity.WritesPerSecond = totalWritesPerSecond
+	capacity.WriteBytesPerSecond = totalWriteBytesPerSecond
+	capacity.CPUPerSecond = totalCPUPerSecond
 	capacity.BytesPerReplica = roachpb.PercentilesFromData(bytesPerReplica)
 	capacity.WritesPerReplica = roachpb.PercentilesFromData(writesPerReplica)
 	s.recordNewPerSecondStats(totalQueriesPerSecond, totalWritesPerSecond)
@@ -2245,6 +2304,8 @@ func (s *Store) updateReplicationGauges(ctx context.Context) error {
 		quiescentCount                int64
 		averageQueriesPerSecond       float64
 		averageWritesPerSecond        float64
+		averageWriteBytesPerSecond    float64
+		averageCPUNanosPerSecond      float64
 
 		rangeCount                int64
 		unavailableRangeCount     int64
@@ -2301,6 +2362,12 @@ func (s *Store) updateReplicationGauges(ctx context.Context) error {
 		if wps, dur := rep.writeStats.avgQPS(); dur >= MinStatsDuration {
 			averageWritesPerSecond += wps
 		}
+		if wbps, dur := rep.writeBytesStats.avgQPS(); dur >= MinStatsDuration {
+			averageWriteBytesPerSecond += wbps
+		}
+		if cpu, dur := rep.cpuStats.avgQPS(); dur >= MinStatsDuration {
+			averageCPUNanosPerSecond += cpu
+		}
 		if mc := rep.maxClosed(ctx); minMaxClosedTS.IsEmpty() || mc.Less(minMaxClosedTS) {
 			minMaxClosedTS = mc
 		}
@@ -2315,6 +2382,8 @@ func (s *Store) updateReplicationGauges(ctx context.Context) error {
 	s.metrics.QuiescentCount.Update(quiescentCount)
 	s.metrics.AverageQueriesPerSecond.Update(averageQueriesPerSecond)
 	s.metrics.AverageWritesPerSecond.Update(averageWritesPerSecond)
+	s.metrics.AverageWriteBytesPerSecond.Update(averageWriteBytesPerSecond)
+	s.metrics.AverageCPUNanosPerSecond.Update(averageCPUNanosPerSecond)
 	s.recordNewPerSecondStats(averageQueriesPerSecond, averageWritesPerSecond)
 
 	s.metrics.RangeCount.Update(rangeCount)
@@ -371,11 +371,12 @@ func (sp *StorePool) updateLocalStoreAfterRebalance(
 		return
 	}
 	switch changeType {
-	case roachpb.ADD_REPLICA:
+	case roachpb.ADD_REPLICA, roachpb.ADD_NON_VOTER:
 		detail.desc.Capacity.RangeCount++
 		detail.desc.Capacity.LogicalBytes += rangeUsageInfo.LogicalBytes
 		detail.desc.Capacity.WritesPerSecond += rangeUsageInfo.WritesPerSecond
-	case roachpb.REMOVE_REPLICA:
+		detail.desc.Capacity.WriteBytesPerSecond += rangeUsageInfo.WriteBytesPerSecond
+	case roachpb.REMOVE_REPLICA, roachpb.REMOVE_NON_VOTER:
 		detail.desc.Capacity.RangeCount--
 		if detail.desc.Capacity.LogicalBytes <= rangeUsageInfo.LogicalBytes {
 			detail.desc.Capacity.LogicalBytes = 0
@@ -387,14 +388,20 @@ func (sp *StorePool) updateLocalStoreAfterRebalance(
 		} else {
 			detail.desc.Capacity.WritesPerSecond -= rangeUsageInfo.WritesPerSecond
 		}
+		if detail.desc.Capacity.WriteBytesPerSecond <= rangeUsageInfo.WriteBytesPerSecond {
+			detail.desc.Capacity.WriteBytesPerSecond = 0
+		} else {
+			detail.desc.Capacity.WriteBytesPerSecond -= rangeUsageInfo.WriteBytesPerSecond
+		}
 	}
 	sp.detailsMu.storeDetails[storeID] = &detail
 }
 
 // updateLocalStoresAfterLeaseTransfer is used to update the local copies of the
-// involved store descriptors immediately after a lease transfer.
+// involved store descriptors immediately after a lease transfer. Only the load
+// that follows the lease (QPS and request CPU) is moved between the stores.
 func (sp *StorePool) updateLocalStoresAfterLeaseTransfer(
-	from roachpb.StoreID, to roachpb.StoreID, rangeQPS float64,
+	from roachpb.StoreID, to roachpb.StoreID, rangeUsageInfo RangeUsageInfo,
 ) {
 	sp.detailsMu.Lock()
 	defer sp.detailsMu.Unlock()
@@ -402,10 +409,15 @@ func (sp *StorePool) updateLocalStoresAfterLeaseTransfer(
 	fromDetail := *sp.getStoreDetailLocked(from)
 	if fromDetail.desc != nil {
 		fromDetail.desc.Capacity.LeaseCount--
-		if fromDetail.desc.Capacity.QueriesPerSecond < rangeQPS {
+		if fromDetail.desc.Capacity.QueriesPerSecond < rangeUsageInfo.QueriesPerSecond {
 			fromDetail.desc.Capacity.QueriesPerSecond = 0
 		} else {
-			fromDetail.desc.Capacity.QueriesPerSecond -= rangeQPS
+			fromDetail.desc.Capacity.QueriesPerSecond -= rangeUsageInfo.QueriesPerSecond
+		}
+		if fromDetail.desc.Capacity.CPUPerSecond < rangeUsageInfo.CPUPerSecond {
+			fromDetail.desc.Capacity.CPUPerSecond = 0
+		} else {
+			fromDetail.desc.Capacity.CPUPerSecond -= rangeUsageInfo.CPUPerSecond
 		}
 		sp.detailsMu.storeDetails[from] = &fromDetail
 	}
@@ -413,7 +425,8 @@ func (sp *StorePool) updateLocalStoresAfterLeaseTransfer(
 	toDetail := *sp.getStoreDetailLocked(to)
 	if toDetail.desc != nil {
 		toDetail.desc.Capacity.LeaseCount++
-		toDetail.desc.Capacity.QueriesPerSecond += rangeQPS
+		toDetail.desc.Capacity.QueriesPerSecond += rangeUsageInfo.QueriesPerSecond
+		toDetail.desc.Capacity.CPUPerSecond += rangeUsageInfo.CPUPerSecond
 		sp.detailsMu.storeDetails[to] = &toDetail
 	}
 }
@@ -571,6 +584,14 @@ type StoreList struct {
 	// candidateWritesPerSecond tracks writes-per-second stats for stores that are
 	// eligible to be rebalance targets.
 	candidateWritesPerSecond stat
+
+	// candidateWriteBytesPerSecond tracks write-bytes-per-second stats for
+	// stores that are eligible to be rebalance targets.
+	candidateWriteBytesPerSecond stat
+
+	// candidateCPUPerSecond tracks request CPU stats for stores that are
+	// eligible to be rebalance targets.
+	candidateCPUPerSecond stat
 }
 
 // Generates a new store list based on the passed in descriptors. It will
@@ -585,6 +606,8 @@ func makeStoreList(descriptors []roachpb.StoreDescriptor) StoreList {
 		sl.candidateLogicalBytes.update(float64(desc.Capacity.LogicalBytes))
 		sl.candidateQueriesPerSecond.update(desc.Capacity.QueriesPerSecond)
 		sl.candidateWritesPerSecond.update(desc.Capacity.WritesPerSecond)
+		sl.candidateWriteBytesPerSecond.update(desc.Capacity.WriteBytesPerSecond)
+		sl.candidateCPUPerSecond.update(desc.Capacity.CPUPerSecond)
 	}
 	return sl
 }
@@ -626,6 +649,22 @@ func (sl StoreList) filter(constraints []zonepb.Constraints) StoreList {
 	return makeStoreList(filteredDescs)
 }
 
+// excludeNodes returns the store list without the stores located on the nodes
+// of the given replicas. It maintains the original order of the passed in store
+// list.
+func (sl StoreList) excludeNodes(replicas []roachpb.ReplicaDescriptor) StoreList {
+	if len(replicas) == 0 {
+		return sl
+	}
+	var filteredDescs []roachpb.StoreDescriptor
+	for _, store := range sl.stores {
+		if !nodeHasReplica(store.Node.NodeID, replicas) {
+			filteredDescs = append(filteredDescs, store)
+		}
+	}
+	return makeStoreList(filteredDescs)
+}
+
 type storeFilter int
 
 const (
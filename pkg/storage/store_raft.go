@@ -60,6 +60,13 @@ func (s *Store) HandleSnapshot(
 	})
 }
 
+// isRaftLearner returns whether the replica is a learner from raft's point of
+// view, which is the case for both LEARNER and NON_VOTER replicas.
+func isRaftLearner(repDesc roachpb.ReplicaDescriptor) bool {
+	typ := repDesc.GetType()
+	return typ == roachpb.LEARNER || typ == roachpb.NON_VOTER
+}
+
 // learnerType exists to avoid allocating on every coalesced beat to a learner.
 var learnerType = roachpb.LEARNER
 
@@ -189,7 +196,7 @@ func (s *Store) withReplicaForRequest(
 		req.RangeID,
 		req.ToReplica.ReplicaID,
 		&req.FromReplica,
-		req.ToReplica.GetType() == roachpb.LEARNER,
+		isRaftLearner(req.ToReplica),
 	)
 	if err != nil {
 		return roachpb.NewError(err)
@@ -240,7 +247,11 @@ func (s *Store) processRaftRequestWithReplica(
 
 	drop := maybeDropMsgApp(ctx, (*replicaMsgAppDropper)(r), &req.Message, req.RangeStartKey)
 	if !drop {
-		if err := r.stepRaftGroup(req); err != nil {
+		if err := r.stepRaftGroup(req); err == errRemoved {
+			// The replica can't be in the process of being removed since we hold
+			// its raftMu, so it has been quarantined. Drop the message.
+			return nil
+		} else if err != nil {
 			return roachpb.NewError(err)
 		}
 	}
@@ -0,0 +1,361 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/stateloader"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/pkg/errors"
+)
+
+// A store may keep the Raft logs and HardStates of its replicas in an engine
+// separate from the one holding the rest of the replica state (see
+// NewStoreWithRaftEngine). Writes to the two engines are not atomic with
+// respect to each other, so the Raft engine is only ever allowed to lag behind
+// the state engine in ways that can be repaired when a replica is loaded (see
+// stateloader.ReconcileRaftState). Entries are appended and HardStates written
+// to the Raft engine, and synced, before any of them are applied to the state
+// engine. Log truncations, snapshots and splits, on the other hand, commit to
+// the state engine first and update the Raft engine afterwards. Similarly,
+// replicas are destroyed in the state engine first and their Raft state is
+// removed afterwards; Raft state leaked by a crash in between is cleaned up
+// when the store starts (see clearLeakedRaftEngineData).
+//
+// The following are kept in the state engine even in that configuration: the
+// RaftTruncatedState (which is part of the replica state and may still be
+// replicated), the range tombstone and all other range-ID local keys.
+
+// raftEngineMigrationBatchSize is the size above which the batch copying Raft
+// state into the Raft engine is committed and a new one started.
+const raftEngineMigrationBatchSize = 32 << 20 // 32 MiB
+
+// hasSeparateRaftEngine returns whether the store keeps its Raft logs and
+// HardStates in an engine separate from the rest of its data.
+func (s *Store) hasSeparateRaftEngine() bool {
+	return s.raftEngine != s.engine
+}
+
+// UsesSeparateRaftEngine returns whether the store backed by the given engine
+// has moved its Raft logs and HardStates into a separate engine. It is meant
+// for offline tools, which only have access to the store's main engine.
+func UsesSeparateRaftEngine(ctx context.Context, reader engine.Reader) (bool, error) {
+	var marker roachpb.StoreIdent
+	return engine.MVCCGetProto(ctx, reader, keys.StoreRaftEngineKey(), hlc.Timestamp{},
+		&marker, engine.MVCCGetOptions{})
+}
+
+// initRaftEngine prepares the store's Raft engine for use. It is called on
+// store startup, before any replicas are loaded.
+//
+// If the store is configured to use a separate Raft engine for the first
+// time, the Raft logs, HardStates and sideloaded payloads of all replicas are
+// moved into it. The move is idempotent and completes with the (synchronous)
+// write of a marker to the store's main engine, so that a crash in the middle
+// of it causes it to be redone on the next start. Once the marker has been
+// written, the store can't be started without its Raft engine.
+func (s *Store) initRaftEngine(ctx context.Context) error {
+	moved, err := UsesSeparateRaftEngine(ctx, s.engine)
+	if err != nil {
+		return err
+	}
+	if !s.hasSeparateRaftEngine() {
+		if moved {
+			return errors.Errorf("%s keeps its Raft log in a separate engine, but none was configured; "+
+				"moving the Raft log back into the store's engine is not supported", s)
+		}
+		return nil
+	}
+
+	raftIdent, err := ReadStoreIdent(ctx, s.raftEngine)
+	if _, ok := err.(*NotBootstrappedError); ok {
+		if moved {
+			return errors.Errorf("%s keeps its Raft log in a separate engine, but the configured one is empty", s)
+		}
+	} else if err != nil {
+		return err
+	} else if raftIdent != *s.Ident {
+		return errors.Errorf("Raft engine belongs to store %+v, not %+v", raftIdent, *s.Ident)
+	}
+	if moved {
+		return nil
+	}
+
+	log.Infof(ctx, "moving Raft logs into separate engine")
+	n, err := s.copyRaftStateToRaftEngine(ctx)
+	if err != nil {
+		return errors.Wrap(err, "while copying Raft state")
+	}
+	if err := s.copySideloadedToRaftEngine(); err != nil {
+		return errors.Wrap(err, "while copying sideloaded payloads")
+	}
+
+	// Remove the Raft state from the main engine and mark the move as done in
+	// the same batch.
+	batch := s.engine.NewBatch()
+	defer batch.Close()
+	if err := forEachRangeIDWithLocalData(s.engine, func(rangeID roachpb.RangeID) error {
+		return clearRaftState(s.engine, batch, rangeID)
+	}); err != nil {
+		return err
+	}
+	if err := engine.MVCCBlindPutProto(ctx, batch, nil /* ms */, keys.StoreRaftEngineKey(),
+		hlc.Timestamp{}, s.Ident, nil /* txn */); err != nil {
+		return err
+	}
+	if err := batch.Commit(true /* sync */); err != nil {
+		return err
+	}
+
+	// The sideloaded payloads in the main engine are now unreferenced.
+	if err := removeSideloadedDir(s.engine); err != nil {
+		log.Warningf(ctx, "unable to remove sideloaded payloads from store engine: %+v", err)
+	}
+	log.Infof(ctx, "moved Raft state of %d replicas into separate engine", n)
+	return nil
+}
+
+// copyRaftStateToRaftEngine copies the Raft logs and HardStates of all range
+// IDs from the main engine into the Raft engine, replacing whatever Raft state
+// the latter held. The Raft engine is then initialized with the store's ident
+// and synced. It returns the number of range IDs whose state was copied.
+func (s *Store) copyRaftStateToRaftEngine(ctx context.Context) (int, error) {
+	batch := s.raftEngine.NewWriteOnlyBatch()
+	defer func() {
+		batch.Close()
+	}()
+	// Discard the remainders of any earlier, aborted attempt. In particular,
+	// the store may have been started without its Raft engine in the meantime.
+	if err := batch.ClearRange(
+		engine.MakeMVCCMetadataKey(keys.LocalRangeIDPrefix.AsRawKey()),
+		engine.MakeMVCCMetadataKey(keys.LocalRangeIDPrefix.PrefixEnd().AsRawKey()),
+	); err != nil {
+		return 0, err
+	}
+
+	var n int
+	copySpan := func(start, end roachpb.Key) error {
+		iter := s.engine.NewIterator(engine.IterOptions{UpperBound: end})
+		defer iter.Close()
+		for iter.SeekGE(engine.MakeMVCCMetadataKey(start)); ; iter.Next() {
+			if ok, err := iter.Valid(); err != nil || !ok {
+				return err
+			}
+			if err := batch.Put(iter.UnsafeKey(), iter.UnsafeValue()); err != nil {
+				return err
+			}
+		}
+	}
+	if err := forEachRangeIDWithLocalData(s.engine, func(rangeID roachpb.RangeID) error {
+		hsKey := keys.RaftHardStateKey(rangeID)
+		if err := copySpan(hsKey, hsKey.Next()); err != nil {
+			return err
+		}
+		logPrefix := keys.RaftLogPrefix(rangeID)
+		if err := copySpan(logPrefix, logPrefix.PrefixEnd()); err != nil {
+			return err
+		}
+		n++
+		if batch.Len() < raftEngineMigrationBatchSize {
+			return nil
+		}
+		if err := batch.Commit(false /* sync */); err != nil {
+			return err
+		}
+		batch.Close()
+		batch = s.raftEngine.NewWriteOnlyBatch()
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	if err := engine.MVCCBlindPutProto(ctx, batch, nil /* ms */, keys.StoreIdentKey(),
+		hlc.Timestamp{}, s.Ident, nil /* txn */); err != nil {
+		return 0, err
+	}
+	return n, batch.Commit(true /* sync */)
+}
+
+// copySideloadedToRaftEngine copies the sideloaded payloads of all replicas
+// from the main engine's auxiliary directory into the Raft engine's. Only
+// payloads stored on disk are copied, which is the case for all stores that
+// can be configured with a separate Raft engine.
+func (s *Store) copySideloadedToRaftEngine() error {
+	from := filepath.Join(s.engine.GetAuxiliaryDir(), "sideloading")
+	to := filepath.Join(s.raftEngine.GetAuxiliaryDir(), "sideloading")
+	return filepath.Walk(from, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == from {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+		data, err := s.engine.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(to, rel)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		return s.raftEngine.WriteFile(target, data)
+	})
+}
+
+// removeSideloadedDir removes all sideloaded payloads from the given engine.
+func removeSideloadedDir(eng engine.Engine) error {
+	dir := filepath.Join(eng.GetAuxiliaryDir(), "sideloading")
+	if err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		return eng.DeleteFile(path)
+	}); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+// clearLeakedRaftEngineData removes the Raft state of replicas which were
+// destroyed, but whose Raft state was not removed from the Raft engine before
+// the process crashed. It is called on store startup, after all replicas have
+// been loaded.
+//
+// The Raft state of a range ID is considered leaked if the range ID has a
+// tombstone but no replica on this store, and if it holds a log or a nonzero
+// commit index. The latter condition preserves the HardState (in particular,
+// the vote) of uninitialized replicas, which have neither.
+func (s *Store) clearLeakedRaftEngineData(ctx context.Context) error {
+	if !s.hasSeparateRaftEngine() {
+		return nil
+	}
+	batch := s.raftEngine.NewWriteOnlyBatch()
+	defer batch.Close()
+	var leaked []roachpb.RangeID
+	if err := forEachRangeIDWithLocalData(s.raftEngine, func(rangeID roachpb.RangeID) error {
+		if _, err := s.GetReplica(rangeID); err == nil {
+			return nil
+		}
+		var tombstone roachpb.RangeTombstone
+		if ok, err := engine.MVCCGetProto(ctx, s.engine, keys.RangeTombstoneKey(rangeID),
+			hlc.Timestamp{}, &tombstone, engine.MVCCGetOptions{}); err != nil || !ok {
+			return err
+		}
+		rsl := stateloader.Make(rangeID)
+		hs, err := rsl.LoadHardState(ctx, s.raftEngine)
+		if err != nil {
+			return err
+		}
+		if hs.Commit == 0 {
+			// Check for log entries, which an uninitialized replica can't have.
+			lastIndex, err := rsl.LoadLastIndex(ctx, s.engine, s.raftEngine)
+			if err != nil || lastIndex == 0 {
+				return err
+			}
+		}
+		leaked = append(leaked, rangeID)
+		return clearRaftState(s.raftEngine, batch, rangeID)
+	}); err != nil {
+		return err
+	}
+	if len(leaked) == 0 {
+		return nil
+	}
+	if err := batch.Commit(false /* sync */); err != nil {
+		return err
+	}
+	for _, rangeID := range leaked {
+		ss := sideloadedPath(s.raftEngine.GetAuxiliaryDir(), rangeID)
+		if err := s.raftEngine.DeleteDirAndFiles(ss); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	log.Infof(ctx, "removed leaked Raft state of %d replicas", len(leaked))
+	return nil
+}
+
+// clearRaftEngineData removes the Raft log and HardState of the given range ID
+// from the store's Raft engine, if it is separate. It is used when a replica
+// is destroyed, after the state engine has been updated.
+func (s *Store) clearRaftEngineData(rangeID roachpb.RangeID) error {
+	if !s.hasSeparateRaftEngine() {
+		return nil
+	}
+	batch := s.raftEngine.NewWriteOnlyBatch()
+	defer batch.Close()
+	if err := clearRaftState(s.raftEngine, batch, rangeID); err != nil {
+		return err
+	}
+	return batch.Commit(false /* sync */)
+}
+
+// clearRaftState removes the Raft log and HardState of the given range ID.
+func clearRaftState(reader engine.Reader, writer engine.Writer, rangeID roachpb.RangeID) error {
+	if err := writer.Clear(engine.MakeMVCCMetadataKey(keys.RaftHardStateKey(rangeID))); err != nil {
+		return err
+	}
+	prefix := keys.RaftLogPrefix(rangeID)
+	return engine.ClearRangeWithHeuristic(reader, writer, prefix, prefix.PrefixEnd())
+}
+
+// forEachRangeIDWithLocalData calls fn for each range ID that has range-ID
+// local keys in the given reader, in ascending order.
+func forEachRangeIDWithLocalData(
+	reader engine.Reader, fn func(rangeID roachpb.RangeID) error,
+) error {
+	iter := reader.NewIterator(engine.IterOptions{
+		UpperBound: keys.LocalRangeIDPrefix.PrefixEnd().AsRawKey(),
+	})
+	defer iter.Close()
+	key := keys.LocalRangeIDPrefix.AsRawKey()
+	for {
+		iter.SeekGE(engine.MakeMVCCMetadataKey(key))
+		if ok, err := iter.Valid(); err != nil || !ok {
+			return err
+		}
+		rangeID, _, _, _, err := keys.DecodeRangeIDKey(iter.UnsafeKey().Key)
+		if err != nil {
+			return err
+		}
+		if err := fn(rangeID); err != nil {
+			return err
+		}
+		key = keys.MakeRangeIDPrefix(rangeID + 1)
+	}
+}
+
+// reconcileRaftEngineRaftMuLocked repairs the replica's Raft log and HardState
+// in the store's separate Raft engine after a crash left them lagging behind
+// the replica state. See stateloader.ReconcileRaftState.
+func (r *Replica) reconcileRaftEngineRaftMuLocked(ctx context.Context) error {
+	batch := r.store.raftEngine.NewBatch()
+	defer batch.Close()
+	if err := r.mu.stateLoader.ReconcileRaftState(ctx, r.store.engine, batch); err != nil {
+		return err
+	}
+	if batch.Empty() {
+		return nil
+	}
+	return batch.Commit(true /* sync */)
+}
@@ -0,0 +1,139 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/stateloader"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"go.etcd.io/etcd/raft/raftpb"
+)
+
+// TestStoreMoveRaftStateToRaftEngine verifies that starting a store with a
+// separate Raft engine for the first time moves the Raft state of its
+// replicas into that engine, and that the store can't be started without it
+// afterwards.
+func TestStoreMoveRaftStateToRaftEngine(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	cfg := TestStoreConfig(hlc.NewClock(func() int64 { return 123 }, time.Nanosecond))
+	stopper := stop.NewStopper()
+	ctx := context.TODO()
+	defer stopper.Stop(ctx)
+	eng := engine.NewDefaultInMem()
+	stopper.AddCloser(eng)
+	raftEng := engine.NewDefaultInMem()
+	stopper.AddCloser(raftEng)
+	cfg.Transport = NewDummyRaftTransport(cfg.Settings)
+	factory := &testSenderFactory{}
+	cfg.DB = client.NewDB(cfg.AmbientCtx, factory, cfg.Clock)
+	cfg.TestingKnobs.DisableScanner = true
+
+	if err := InitEngine(
+		ctx, eng, testIdent,
+		cluster.ClusterVersion{Version: cluster.BinaryServerVersion},
+	); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteInitialClusterData(
+		ctx, eng, nil /* initialValues */, cluster.BinaryServerVersion,
+		1 /* numStores */, nil /* splits */, cfg.Clock.PhysicalNow(),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	// Append an entry to the log of r1 so that there is something to move
+	// besides the HardState.
+	const rangeID = roachpb.RangeID(1)
+	rsl := stateloader.Make(rangeID)
+	hs, err := rsl.LoadHardState(ctx, eng)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lastIndex, err := rsl.LoadLastIndex(ctx, eng, eng)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entryKey := keys.RaftLogKey(rangeID, lastIndex+1)
+	ent := raftpb.Entry{Index: lastIndex + 1, Term: hs.Term, Type: raftpb.EntryNormal}
+	if err := engine.MVCCPutProto(
+		ctx, eng, nil /* ms */, entryKey, hlc.Timestamp{}, nil /* txn */, &ent,
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewStoreWithRaftEngine(ctx, cfg, eng, raftEng, &roachpb.NodeDescriptor{NodeID: 1})
+	factory.setStore(store)
+	if err := store.Start(ctx, stopper); err != nil {
+		t.Fatalf("failure starting store with separate Raft engine: %+v", err)
+	}
+
+	if moved, err := UsesSeparateRaftEngine(ctx, eng); err != nil {
+		t.Fatal(err)
+	} else if !moved {
+		t.Fatal("expected store engine to be marked as using a separate Raft engine")
+	}
+	if ident, err := ReadStoreIdent(ctx, raftEng); err != nil {
+		t.Fatal(err)
+	} else if ident != testIdent {
+		t.Fatalf("expected Raft engine ident %+v, got %+v", testIdent, ident)
+	}
+
+	// The HardState and the log entry now only live in the Raft engine.
+	for _, key := range []roachpb.Key{keys.RaftHardStateKey(rangeID), entryKey} {
+		if b, err := eng.Get(engine.MakeMVCCMetadataKey(key)); err != nil {
+			t.Fatal(err)
+		} else if b != nil {
+			t.Errorf("expected %s to be removed from the store engine", key)
+		}
+		if b, err := raftEng.Get(engine.MakeMVCCMetadataKey(key)); err != nil {
+			t.Fatal(err)
+		} else if b == nil {
+			t.Errorf("expected %s to be present in the Raft engine", key)
+		}
+	}
+	if newHS, err := rsl.LoadHardState(ctx, raftEng); err != nil {
+		t.Fatal(err)
+	} else if newHS.Term < hs.Term || newHS.Commit < hs.Commit {
+		t.Errorf("expected HardState %+v to be at least %+v", newHS, hs)
+	}
+	if newLastIndex, err := rsl.LoadLastIndex(ctx, eng, raftEng); err != nil {
+		t.Fatal(err)
+	} else if newLastIndex < ent.Index {
+		t.Errorf("expected last index of at least %d, got %d", ent.Index, newLastIndex)
+	}
+
+	// Once moved, the Raft state can't be moved back, and an empty Raft engine
+	// is refused.
+	if err := NewStore(
+		ctx, cfg, eng, &roachpb.NodeDescriptor{NodeID: 1},
+	).Start(ctx, stopper); !testutils.IsError(err, "moving the Raft log back .* is not supported") {
+		t.Errorf("unexpected error starting store without its Raft engine: %+v", err)
+	}
+	emptyEng := engine.NewDefaultInMem()
+	stopper.AddCloser(emptyEng)
+	if err := NewStoreWithRaftEngine(
+		ctx, cfg, eng, emptyEng, &roachpb.NodeDescriptor{NodeID: 1},
+	).Start(ctx, stopper); !testutils.IsError(err, "the configured one is empty") {
+		t.Errorf("unexpected error starting store with empty Raft engine: %+v", err)
+	}
+}
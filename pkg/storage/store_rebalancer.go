@@ -40,6 +40,14 @@ const (
 	// by less than this amount even if the amount is greater than the percentage
 	// threshold. This avoids too many lease transfers in lightly loaded clusters.
 	minQPSThresholdDifference = 100
+
+	// minWriteBytesThresholdDifference is the analog of
+	// minQPSThresholdDifference for bytes written per second.
+	minWriteBytesThresholdDifference = 1 << 20 // 1 MiB/s
+
+	// minCPUThresholdDifference is the analog of minQPSThresholdDifference for
+	// the nanoseconds spent evaluating requests per second.
+	minCPUThresholdDifference = float64(100 * time.Millisecond)
 )
 
 var (
@@ -85,10 +93,10 @@ var LoadBasedRebalancingMode = settings.RegisterPublicEnumSetting(
 )
 
 // qpsRebalanceThreshold is much like rangeRebalanceThreshold, but for
-// QPS rather than range count. This should be set higher than
-// rangeRebalanceThreshold because QPS can naturally vary over time as
-// workloads change and clients come and go, so we need to be a little more
-// forgiving to avoid thrashing.
+// load (which by default is QPS) rather than range count. This should be set
+// higher than rangeRebalanceThreshold because load can naturally vary over
+// time as workloads change and clients come and go, so we need to be a little
+// more forgiving to avoid thrashing.
 var qpsRebalanceThreshold = func() *settings.FloatSetting {
 	s := settings.RegisterNonNegativeFloatSetting(
 		"kv.allocator.qps_rebalance_threshold",
@@ -99,6 +107,42 @@ var qpsRebalanceThreshold = func() *settings.FloatSetting {
 	return s
 }()
 
+// The load that the store rebalancer balances across stores is a weighted sum
+// of the QPS, bytes written per second, and request CPU time of each store,
+// expressed in units of queries per second. By default only QPS is taken into
+// account; giving write bytes a weight allows write-heavy ranges with few but
+// large requests to be moved, and giving CPU a weight accounts for requests
+// that are much more expensive to evaluate than others.
+var (
+	qpsLoadWeight = func() *settings.FloatSetting {
+		s := settings.RegisterNonNegativeFloatSetting(
+			"kv.allocator.load_based_rebalancing.qps_weight",
+			"the load attributed to each query per second when rebalancing based on load",
+			1,
+		)
+		s.SetVisibility(settings.Public)
+		return s
+	}()
+	writeKiBLoadWeight = func() *settings.FloatSetting {
+		s := settings.RegisterNonNegativeFloatSetting(
+			"kv.allocator.load_based_rebalancing.write_kib_weight",
+			"the load, relative to a query per second, attributed to each KiB written per second when rebalancing based on load",
+			0,
+		)
+		s.SetVisibility(settings.Public)
+		return s
+	}()
+	cpuMillisLoadWeight = func() *settings.FloatSetting {
+		s := settings.RegisterNonNegativeFloatSetting(
+			"kv.allocator.load_based_rebalancing.cpu_ms_weight",
+			"the load, relative to a query per second, attributed to each millisecond of request evaluation per second when rebalancing based on load",
+			0,
+		)
+		s.SetVisibility(settings.Public)
+		return s
+	}()
+)
+
 // LBRebalancingMode controls if and when we do store-level rebalancing
 // based on load.
 type LBRebalancingMode int64
@@ -205,13 +249,15 @@ func (sr *StoreRebalancer) Start(ctx context.Context, stopper *stop.Stopper) {
 func (sr *StoreRebalancer) rebalanceStore(
 	ctx context.Context, mode LBRebalancingMode, storeList StoreList,
 ) {
+	w := sr.loadWeights()
 	qpsThresholdFraction := qpsRebalanceThreshold.Get(&sr.st.SV)
 
-	// First check if we should transfer leases away to better balance QPS.
-	qpsMinThreshold := math.Min(storeList.candidateQueriesPerSecond.mean*(1-qpsThresholdFraction),
-		storeList.candidateQueriesPerSecond.mean-minQPSThresholdDifference)
-	qpsMaxThreshold := math.Max(storeList.candidateQueriesPerSecond.mean*(1+qpsThresholdFraction),
-		storeList.candidateQueriesPerSecond.mean+minQPSThresholdDifference)
+	// First check if we should transfer leases away to better balance load.
+	meanLoad := w.meanStoreLoad(storeList)
+	minLoadDifference := w.load(minQPSThresholdDifference, minWriteBytesThresholdDifference,
+		minCPUThresholdDifference)
+	minLoadThreshold := math.Min(meanLoad*(1-qpsThresholdFraction), meanLoad-minLoadDifference)
+	maxLoadThreshold := math.Max(meanLoad*(1+qpsThresholdFraction), meanLoad+minLoadDifference)
 
 	var localDesc *roachpb.StoreDescriptor
 	for i := range storeList.stores {
@@ -224,9 +270,9 @@ func (sr *StoreRebalancer) rebalanceStore(
 		return
 	}
 
-	if !(localDesc.Capacity.QueriesPerSecond > qpsMaxThreshold) {
-		log.VEventf(ctx, 1, "local QPS %.2f is below max threshold %.2f (mean=%.2f); no rebalancing needed",
-			localDesc.Capacity.QueriesPerSecond, qpsMaxThreshold, storeList.candidateQueriesPerSecond.mean)
+	if !(w.storeLoad(localDesc.Capacity) > maxLoadThreshold) {
+		log.VEventf(ctx, 1, "local load %.2f is below max threshold %.2f (mean=%.2f); no rebalancing needed",
+			w.storeLoad(localDesc.Capacity), maxLoadThreshold, meanLoad)
 		return
 	}
 
@@ -234,23 +280,26 @@ func (sr *StoreRebalancer) rebalanceStore(
 	storeMap := storeListToMap(storeList)
 
 	log.Infof(ctx,
-		"considering load-based lease transfers for s%d with %.2f qps (mean=%.2f, upperThreshold=%.2f)",
-		localDesc.StoreID, localDesc.Capacity.QueriesPerSecond, storeList.candidateQueriesPerSecond.mean, qpsMaxThreshold)
+		"considering load-based lease transfers for s%d with %.2f load (mean=%.2f, upperThreshold=%.2f)",
+		localDesc.StoreID, w.storeLoad(localDesc.Capacity), meanLoad, maxLoadThreshold)
 
-	hottestRanges := sr.replRankings.topQPS()
-	for localDesc.Capacity.QueriesPerSecond > qpsMaxThreshold {
+	hottestRanges := sr.hottestReplicas(w)
+	for w.storeLoad(localDesc.Capacity) > maxLoadThreshold {
 		replWithStats, target, considerForRebalance := sr.chooseLeaseToTransfer(
-			ctx, &hottestRanges, localDesc, storeList, storeMap, qpsMinThreshold, qpsMaxThreshold)
+			ctx, &hottestRanges, localDesc, storeList, storeMap, minLoadThreshold, maxLoadThreshold)
 		replicasToMaybeRebalance = append(replicasToMaybeRebalance, considerForRebalance...)
 		if replWithStats.repl == nil {
 			break
 		}
 
-		log.VEventf(ctx, 1, "transferring r%d (%.2f qps) to s%d to better balance load",
-			replWithStats.repl.RangeID, replWithStats.qps, target.StoreID)
+		log.VEventf(ctx, 1, "transferring r%d (%.2f load) to s%d to better balance load",
+			replWithStats.repl.RangeID, w.leaseLoad(replWithStats), target.StoreID)
 		timeout := sr.rq.processTimeoutFunc(sr.st, replWithStats.repl)
 		if err := contextutil.RunWithTimeout(ctx, "transfer lease", timeout, func(ctx context.Context) error {
-			return sr.rq.transferLease(ctx, replWithStats.repl, target, replWithStats.qps)
+			return sr.rq.transferLease(ctx, replWithStats.repl, target, RangeUsageInfo{
+				QueriesPerSecond: replWithStats.qps,
+				CPUPerSecond:     replWithStats.cpu,
+			})
 		}); err != nil {
 			log.Errorf(ctx, "unable to transfer lease to s%d: %+v", target.StoreID, err)
 			continue
@@ -262,52 +311,54 @@ func (sr *StoreRebalancer) rebalanceStore(
 		// up-to-date info. The StorePool copies are updated by transferLease.
 		localDesc.Capacity.LeaseCount--
 		localDesc.Capacity.QueriesPerSecond -= replWithStats.qps
+		localDesc.Capacity.CPUPerSecond -= replWithStats.cpu
 		if otherDesc := storeMap[target.StoreID]; otherDesc != nil {
 			otherDesc.Capacity.LeaseCount++
 			otherDesc.Capacity.QueriesPerSecond += replWithStats.qps
+			otherDesc.Capacity.CPUPerSecond += replWithStats.cpu
 		}
 	}
 
-	if !(localDesc.Capacity.QueriesPerSecond > qpsMaxThreshold) {
+	if !(w.storeLoad(localDesc.Capacity) > maxLoadThreshold) {
 		log.Infof(ctx,
-			"load-based lease transfers successfully brought s%d down to %.2f qps (mean=%.2f, upperThreshold=%.2f)",
-			localDesc.StoreID, localDesc.Capacity.QueriesPerSecond, storeList.candidateQueriesPerSecond.mean, qpsMaxThreshold)
+			"load-based lease transfers successfully brought s%d down to %.2f load (mean=%.2f, upperThreshold=%.2f)",
+			localDesc.StoreID, w.storeLoad(localDesc.Capacity), meanLoad, maxLoadThreshold)
 		return
 	}
 
 	if mode != LBRebalancingLeasesAndReplicas {
 		log.Infof(ctx,
-			"ran out of leases worth transferring and qps (%.2f) is still above desired threshold (%.2f)",
-			localDesc.Capacity.QueriesPerSecond, qpsMaxThreshold)
+			"ran out of leases worth transferring and load (%.2f) is still above desired threshold (%.2f)",
+			w.storeLoad(localDesc.Capacity), maxLoadThreshold)
 		return
 	}
 	log.Infof(ctx,
-		"ran out of leases worth transferring and qps (%.2f) is still above desired threshold (%.2f); considering load-based replica rebalances",
-		localDesc.Capacity.QueriesPerSecond, qpsMaxThreshold)
+		"ran out of leases worth transferring and load (%.2f) is still above desired threshold (%.2f); considering load-based replica rebalances",
+		w.storeLoad(localDesc.Capacity), maxLoadThreshold)
 
 	// Re-combine replicasToMaybeRebalance with what remains of hottestRanges so
 	// that we'll reconsider them for replica rebalancing.
 	replicasToMaybeRebalance = append(replicasToMaybeRebalance, hottestRanges...)
 
-	for localDesc.Capacity.QueriesPerSecond > qpsMaxThreshold {
+	for w.storeLoad(localDesc.Capacity) > maxLoadThreshold {
 		replWithStats, targets := sr.chooseReplicaToRebalance(
 			ctx,
 			&replicasToMaybeRebalance,
 			localDesc,
 			storeList,
 			storeMap,
-			qpsMinThreshold,
-			qpsMaxThreshold)
+			minLoadThreshold,
+			maxLoadThreshold)
 		if replWithStats.repl == nil {
 			log.Infof(ctx,
-				"ran out of replicas worth transferring and qps (%.2f) is still above desired threshold (%.2f); will check again soon",
-				localDesc.Capacity.QueriesPerSecond, qpsMaxThreshold)
+				"ran out of replicas worth transferring and load (%.2f) is still above desired threshold (%.2f); will check again soon",
+				w.storeLoad(localDesc.Capacity), maxLoadThreshold)
 			return
 		}
 
 		descBeforeRebalance := replWithStats.repl.Desc()
-		log.VEventf(ctx, 1, "rebalancing r%d (%.2f qps) from %v to %v to better balance load",
-			replWithStats.repl.RangeID, replWithStats.qps, descBeforeRebalance.Replicas(), targets)
+		log.VEventf(ctx, 1, "rebalancing r%d (%.2f load) from %v to %v to better balance load",
+			replWithStats.repl.RangeID, w.replicaLoad(replWithStats), descBeforeRebalance.Replicas(), targets)
 		timeout := sr.rq.processTimeoutFunc(sr.st, replWithStats.repl)
 		if err := contextutil.RunWithTimeout(ctx, "relocate range", timeout, func(ctx context.Context) error {
 			return sr.rq.store.AdminRelocateRange(ctx, *descBeforeRebalance, targets)
@@ -319,7 +370,8 @@ func (sr *StoreRebalancer) rebalanceStore(
 
 		// Finally, update our local copies of the descriptors so that if
 		// additional transfers are needed we'll be making the decisions with more
-		// up-to-date info.
+		// up-to-date info. Write load follows each replica, while QPS and CPU
+		// follow the lease.
 		//
 		// TODO(a-robinson): This just updates the copies used locally by the
 		// storeRebalancer. We may also want to update the copies in the StorePool
@@ -328,24 +380,79 @@ func (sr *StoreRebalancer) rebalanceStore(
 		for i := range replicasBeforeRebalance {
 			if storeDesc := storeMap[replicasBeforeRebalance[i].StoreID]; storeDesc != nil {
 				storeDesc.Capacity.RangeCount--
+				storeDesc.Capacity.WriteBytesPerSecond -= replWithStats.writeBytes
 			}
 		}
 		localDesc.Capacity.LeaseCount--
 		localDesc.Capacity.QueriesPerSecond -= replWithStats.qps
+		localDesc.Capacity.CPUPerSecond -= replWithStats.cpu
 		for i := range targets {
 			if storeDesc := storeMap[targets[i].StoreID]; storeDesc != nil {
 				storeDesc.Capacity.RangeCount++
+				storeDesc.Capacity.WriteBytesPerSecond += replWithStats.writeBytes
 				if i == 0 {
 					storeDesc.Capacity.LeaseCount++
 					storeDesc.Capacity.QueriesPerSecond += replWithStats.qps
+					storeDesc.Capacity.CPUPerSecond += replWithStats.cpu
 				}
 			}
 		}
 	}
 
 	log.Infof(ctx,
-		"load-based replica transfers successfully brought s%d down to %.2f qps (mean=%.2f, upperThreshold=%.2f)",
-		localDesc.StoreID, localDesc.Capacity.QueriesPerSecond, storeList.candidateQueriesPerSecond.mean, qpsMaxThreshold)
+		"load-based replica transfers successfully brought s%d down to %.2f load (mean=%.2f, upperThreshold=%.2f)",
+		localDesc.StoreID, w.storeLoad(localDesc.Capacity), meanLoad, maxLoadThreshold)
+}
+
+// loadWeights returns the weights used to combine the dimensions of load into
+// the single load value that the store rebalancer balances across stores.
+func (sr *StoreRebalancer) loadWeights() loadWeights {
+	return loadWeights{
+		qps:       qpsLoadWeight.Get(&sr.st.SV),
+		writeKiB:  writeKiBLoadWeight.Get(&sr.st.SV),
+		cpuMillis: cpuMillisLoadWeight.Get(&sr.st.SV),
+	}
+}
+
+// hottestReplicas returns the store's hottest replicas along each dimension
+// of load with a non-zero weight, ordered by decreasing combined load.
+func (sr *StoreRebalancer) hottestReplicas(w loadWeights) []replicaWithStats {
+	var hottest []replicaWithStats
+	seen := make(map[*Replica]struct{})
+	add := func(repls []replicaWithStats) {
+		for _, replWithStats := range repls {
+			if _, ok := seen[replWithStats.repl]; ok {
+				continue
+			}
+			seen[replWithStats.repl] = struct{}{}
+			hottest = append(hottest, replWithStats)
+		}
+	}
+	if w.qps > 0 {
+		add(sr.replRankings.topQPS())
+	}
+	if w.writeKiB > 0 {
+		add(sr.replRankings.topWriteBytes())
+	}
+	if w.cpuMillis > 0 {
+		add(sr.replRankings.topCPU())
+	}
+	sort.SliceStable(hottest, func(i, j int) bool {
+		return w.replicaLoad(hottest[i]) > w.replicaLoad(hottest[j])
+	})
+	return hottest
+}
+
+// leaseLoad returns the portion of a replica's load that moves along with its
+// lease, i.e. everything but the write load that all replicas incur.
+func (w loadWeights) leaseLoad(replWithStats replicaWithStats) float64 {
+	return w.load(replWithStats.qps, 0 /* writeBytes */, replWithStats.cpu)
+}
+
+// replicaLoad returns the load that a leaseholder replica places on its store,
+// all of which moves away from the store along with the replica.
+func (w loadWeights) replicaLoad(replWithStats replicaWithStats) float64 {
+	return w.load(replWithStats.qps, replWithStats.writeBytes, replWithStats.cpu)
 }
 
 // TODO(a-robinson): Should we take the number of leases on each store into
@@ -356,11 +463,12 @@ func (sr *StoreRebalancer) chooseLeaseToTransfer(
 	localDesc *roachpb.StoreDescriptor,
 	storeList StoreList,
 	storeMap map[roachpb.StoreID]*roachpb.StoreDescriptor,
-	minQPS float64,
-	maxQPS float64,
+	minLoad float64,
+	maxLoad float64,
 ) (replicaWithStats, roachpb.ReplicaDescriptor, []replicaWithStats) {
 	var considerForRebalance []replicaWithStats
 	now := sr.rq.store.Clock().Now()
+	w := sr.loadWeights()
 	for {
 		if len(*hottestRanges) == 0 {
 			return replicaWithStats{}, roachpb.ReplicaDescriptor{}, considerForRebalance
@@ -373,39 +481,49 @@ func (sr *StoreRebalancer) chooseLeaseToTransfer(
 			return replicaWithStats{}, roachpb.ReplicaDescriptor{}, considerForRebalance
 		}
 
-		if shouldNotMoveAway(ctx, replWithStats, localDesc, now, minQPS) {
+		leaseLoad := w.leaseLoad(replWithStats)
+		if shouldNotMoveAway(ctx, w, replWithStats, leaseLoad, localDesc, now, minLoad) {
 			continue
 		}
 
-		// Don't bother moving leases whose QPS is below some small fraction of the
-		// store's QPS (unless the store has extra leases to spare anyway). It's
-		// just unnecessary churn with no benefit to move leases responsible for,
-		// for example, 1 qps on a store with 5000 qps.
-		const minQPSFraction = .001
-		if replWithStats.qps < localDesc.Capacity.QueriesPerSecond*minQPSFraction &&
+		// Transferring the lease of a range whose load comes entirely from writes
+		// wouldn't move any load off of this store, so consider moving the range's
+		// replica instead.
+		if leaseLoad == 0 && w.replicaLoad(replWithStats) > 0 {
+			considerForRebalance = append(considerForRebalance, replWithStats)
+			continue
+		}
+
+		// Don't bother moving leases whose load is below some small fraction of
+		// the store's load (unless the store has extra leases to spare anyway).
+		// It's just unnecessary churn with no benefit to move leases responsible
+		// for, for example, 1 qps on a store with 5000 qps.
+		const minLoadFraction = .001
+		localLoad := w.storeLoad(localDesc.Capacity)
+		if leaseLoad < localLoad*minLoadFraction &&
 			float64(localDesc.Capacity.LeaseCount) <= storeList.candidateLeases.mean {
-			log.VEventf(ctx, 5, "r%d's %.2f qps is too little to matter relative to s%d's %.2f total qps",
-				replWithStats.repl.RangeID, replWithStats.qps, localDesc.StoreID, localDesc.Capacity.QueriesPerSecond)
+			log.VEventf(ctx, 5, "r%d's %.2f load is too little to matter relative to s%d's %.2f total load",
+				replWithStats.repl.RangeID, leaseLoad, localDesc.StoreID, localLoad)
 			continue
 		}
 
 		desc, zone := replWithStats.repl.DescAndZone()
-		log.VEventf(ctx, 3, "considering lease transfer for r%d with %.2f qps",
-			desc.RangeID, replWithStats.qps)
+		log.VEventf(ctx, 3, "considering lease transfer for r%d with %.2f load",
+			desc.RangeID, leaseLoad)
 
-		// Check all the other replicas in order of increasing qps. Learner replicas
-		// aren't allowed to become the leaseholder or raft leader, so only consider
-		// the `Voters` replicas.
+		// Check all the other replicas in order of increasing load. Learner
+		// replicas aren't allowed to become the leaseholder or raft leader, so only
+		// consider the `Voters` replicas.
 		candidates := desc.Replicas().DeepCopy().Voters()
 		sort.Slice(candidates, func(i, j int) bool {
-			var iQPS, jQPS float64
+			var iLoad, jLoad float64
 			if desc := storeMap[candidates[i].StoreID]; desc != nil {
-				iQPS = desc.Capacity.QueriesPerSecond
+				iLoad = w.storeLoad(desc.Capacity)
 			}
 			if desc := storeMap[candidates[j].StoreID]; desc != nil {
-				jQPS = desc.Capacity.QueriesPerSecond
+				jLoad = w.storeLoad(desc.Capacity)
 			}
-			return iQPS < jQPS
+			return iLoad < jLoad
 		})
 
 		var raftStatus *raft.Status
@@ -416,8 +534,10 @@ func (sr *StoreRebalancer) chooseLeaseToTransfer(
 				continue
 			}
 
-			meanQPS := storeList.candidateQueriesPerSecond.mean
-			if shouldNotMoveTo(ctx, storeMap, replWithStats, candidate.StoreID, meanQPS, minQPS, maxQPS) {
+			meanLoad := w.meanStoreLoad(storeList)
+			if shouldNotMoveTo(
+				ctx, storeMap, w, replWithStats, leaseLoad, candidate.StoreID, meanLoad, minLoad, maxLoad,
+			) {
 				continue
 			}
 
@@ -465,10 +585,11 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 	localDesc *roachpb.StoreDescriptor,
 	storeList StoreList,
 	storeMap map[roachpb.StoreID]*roachpb.StoreDescriptor,
-	minQPS float64,
-	maxQPS float64,
+	minLoad float64,
+	maxLoad float64,
 ) (replicaWithStats, []roachpb.ReplicationTarget) {
 	now := sr.rq.store.Clock().Now()
+	w := sr.loadWeights()
 	for {
 		if len(*hottestRanges) == 0 {
 			return replicaWithStats{}, nil
@@ -480,25 +601,34 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 			return replicaWithStats{}, nil
 		}
 
-		if shouldNotMoveAway(ctx, replWithStats, localDesc, now, minQPS) {
+		replLoad := w.replicaLoad(replWithStats)
+		if shouldNotMoveAway(ctx, w, replWithStats, replLoad, localDesc, now, minLoad) {
 			continue
 		}
 
-		// Don't bother moving ranges whose QPS is below some small fraction of the
-		// store's QPS (unless the store has extra ranges to spare anyway). It's
-		// just unnecessary churn with no benefit to move ranges responsible for,
-		// for example, 1 qps on a store with 5000 qps.
-		const minQPSFraction = .001
-		if replWithStats.qps < localDesc.Capacity.QueriesPerSecond*minQPSFraction &&
+		// Don't bother moving ranges whose load is below some small fraction of
+		// the store's load (unless the store has extra ranges to spare anyway).
+		// It's just unnecessary churn with no benefit to move ranges responsible
+		// for, for example, 1 qps on a store with 5000 qps.
+		const minLoadFraction = .001
+		localLoad := w.storeLoad(localDesc.Capacity)
+		if replLoad < localLoad*minLoadFraction &&
 			float64(localDesc.Capacity.RangeCount) <= storeList.candidateRanges.mean {
-			log.VEventf(ctx, 5, "r%d's %.2f qps is too little to matter relative to s%d's %.2f total qps",
-				replWithStats.repl.RangeID, replWithStats.qps, localDesc.StoreID, localDesc.Capacity.QueriesPerSecond)
+			log.VEventf(ctx, 5, "r%d's %.2f load is too little to matter relative to s%d's %.2f total load",
+				replWithStats.repl.RangeID, replLoad, localDesc.StoreID, localLoad)
 			continue
 		}
 
 		desc, zone := replWithStats.repl.DescAndZone()
-		log.VEventf(ctx, 3, "considering replica rebalance for r%d with %.2f qps",
-			desc.RangeID, replWithStats.qps)
+		log.VEventf(ctx, 3, "considering replica rebalance for r%d with %.2f load",
+			desc.RangeID, replLoad)
+
+		// The relocation below treats all replicas as voters, so leave ranges
+		// with non-voters to the replicate queue.
+		if len(desc.Replicas().NonVoters()) > 0 {
+			log.VEventf(ctx, 3, "not rebalancing r%d because it has non-voting replicas", desc.RangeID)
+			continue
+		}
 
 		clusterNodes := sr.rq.allocator.storePool.ClusterNodeCount()
 		desiredReplicas := GetNeededReplicas(*zone.NumReplicas, clusterNodes)
@@ -507,7 +637,7 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 		currentReplicas := desc.Replicas().All()
 
 		// Check the range's existing diversity score, since we want to ensure we
-		// don't hurt locality diversity just to improve QPS.
+		// don't hurt locality diversity just to improve load.
 		curDiversity := rangeDiversityScore(
 			sr.rq.allocator.storePool.getLocalities(currentReplicas))
 
@@ -516,11 +646,11 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 			if currentReplicas[i].StoreID == localDesc.StoreID {
 				continue
 			}
-			// Keep the replica in the range if we don't know its QPS or if its QPS
+			// Keep the replica in the range if we don't know its load or if its load
 			// is below the upper threshold. Punishing stores not in our store map
 			// could cause mass evictions if the storePool gets out of sync.
 			storeDesc, ok := storeMap[currentReplicas[i].StoreID]
-			if !ok || storeDesc.Capacity.QueriesPerSecond < maxQPS {
+			if !ok || w.storeLoad(storeDesc.Capacity) < maxLoad {
 				targets = append(targets, roachpb.ReplicationTarget{
 					NodeID:  currentReplicas[i].NodeID,
 					StoreID: currentReplicas[i].StoreID,
@@ -535,6 +665,7 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 		// Then pick out which new stores to add the remaining replicas to.
 		options := sr.rq.allocator.scorerOptions()
 		options.qpsRebalanceThreshold = qpsRebalanceThreshold.Get(&sr.st.SV)
+		options.loadWeights = w
 		for len(targets) < desiredReplicas {
 			// Use the preexisting AllocateTarget logic to ensure that considerations
 			// such as zone constraints, locality diversity, and full disk come
@@ -544,7 +675,9 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 				storeList,
 				zone,
 				targetReplicas,
+				nil, /* existingNonVoters */
 				options,
+				voterTarget,
 			)
 			if target == nil {
 				log.VEventf(ctx, 3, "no rebalance targets found to replace the current store for r%d",
@@ -552,8 +685,10 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 				break
 			}
 
-			meanQPS := storeList.candidateQueriesPerSecond.mean
-			if shouldNotMoveTo(ctx, storeMap, replWithStats, target.StoreID, meanQPS, minQPS, maxQPS) {
+			meanLoad := w.meanStoreLoad(storeList)
+			if shouldNotMoveTo(
+				ctx, storeMap, w, replWithStats, replLoad, target.StoreID, meanLoad, minLoad, maxLoad,
+			) {
 				break
 			}
 
@@ -572,7 +707,7 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 		// TODO(a-robinson): Support more incremental improvements -- move what we
 		// can if it makes things better even if it isn't great. For example,
 		// moving one of the other existing replicas that's on a store with less
-		// qps than the max threshold but above the mean would help in certain
+		// load than the max threshold but above the mean would help in certain
 		// locality configurations.
 		if len(targets) < desiredReplicas {
 			log.VEventf(ctx, 3, "couldn't find enough rebalance targets for r%d (%d/%d)",
@@ -587,10 +722,10 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 			continue
 		}
 
-		// Pick the replica with the least QPS to be leaseholder;
+		// Pick the replica with the least load to be leaseholder;
 		// RelocateRange transfers the lease to the first provided target.
 		newLeaseIdx := 0
-		newLeaseQPS := math.MaxFloat64
+		newLeaseLoad := math.MaxFloat64
 		var raftStatus *raft.Status
 		for i := 0; i < len(targets); i++ {
 			// Ensure we don't transfer the lease to an existing replica that is behind
@@ -605,9 +740,9 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 			}
 
 			storeDesc, ok := storeMap[targets[i].StoreID]
-			if ok && storeDesc.Capacity.QueriesPerSecond < newLeaseQPS {
+			if ok && w.storeLoad(storeDesc.Capacity) < newLeaseLoad {
 				newLeaseIdx = i
-				newLeaseQPS = storeDesc.Capacity.QueriesPerSecond
+				newLeaseLoad = w.storeLoad(storeDesc.Capacity)
 			}
 		}
 		targets[0], targets[newLeaseIdx] = targets[newLeaseIdx], targets[0]
@@ -615,33 +750,43 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 	}
 }
 
+// shouldNotMoveAway returns whether moving replLoad, the portion of the
+// replica's load that would be moved, off of the local store would bring it
+// below minLoad.
 func shouldNotMoveAway(
 	ctx context.Context,
+	w loadWeights,
 	replWithStats replicaWithStats,
+	replLoad float64,
 	localDesc *roachpb.StoreDescriptor,
 	now hlc.Timestamp,
-	minQPS float64,
+	minLoad float64,
 ) bool {
 	if !replWithStats.repl.OwnsValidLease(now) {
 		log.VEventf(ctx, 3, "store doesn't own the lease for r%d", replWithStats.repl.RangeID)
 		return true
 	}
-	if localDesc.Capacity.QueriesPerSecond-replWithStats.qps < minQPS {
-		log.VEventf(ctx, 3, "moving r%d's %.2f qps would bring s%d below the min threshold (%.2f)",
-			replWithStats.repl.RangeID, replWithStats.qps, localDesc.StoreID, minQPS)
+	if w.storeLoad(localDesc.Capacity)-replLoad < minLoad {
+		log.VEventf(ctx, 3, "moving r%d's %.2f load would bring s%d below the min threshold (%.2f)",
+			replWithStats.repl.RangeID, replLoad, localDesc.StoreID, minLoad)
 		return true
 	}
 	return false
 }
 
+// shouldNotMoveTo returns whether moving replLoad, the portion of the
+// replica's load that would be moved, to the candidate store would overload
+// it.
 func shouldNotMoveTo(
 	ctx context.Context,
 	storeMap map[roachpb.StoreID]*roachpb.StoreDescriptor,
+	w loadWeights,
 	replWithStats replicaWithStats,
+	replLoad float64,
 	candidateStore roachpb.StoreID,
-	meanQPS float64,
-	minQPS float64,
-	maxQPS float64,
+	meanLoad float64,
+	minLoad float64,
+	maxLoad float64,
 ) bool {
 	storeDesc, ok := storeMap[candidateStore]
 	if !ok {
@@ -649,18 +794,19 @@ func shouldNotMoveTo(
 		return true
 	}
 
-	newCandidateQPS := storeDesc.Capacity.QueriesPerSecond + replWithStats.qps
-	if storeDesc.Capacity.QueriesPerSecond < minQPS {
-		if newCandidateQPS > maxQPS {
+	candidateLoad := w.storeLoad(storeDesc.Capacity)
+	newCandidateLoad := candidateLoad + replLoad
+	if candidateLoad < minLoad {
+		if newCandidateLoad > maxLoad {
 			log.VEventf(ctx, 3,
-				"r%d's %.2f qps would push s%d over the max threshold (%.2f) with %.2f qps afterwards",
-				replWithStats.repl.RangeID, replWithStats.qps, candidateStore, maxQPS, newCandidateQPS)
+				"r%d's %.2f load would push s%d over the max threshold (%.2f) with %.2f load afterwards",
+				replWithStats.repl.RangeID, replLoad, candidateStore, maxLoad, newCandidateLoad)
 			return true
 		}
-	} else if newCandidateQPS > meanQPS {
+	} else if newCandidateLoad > meanLoad {
 		log.VEventf(ctx, 3,
-			"r%d's %.2f qps would push s%d over the mean (%.2f) with %.2f qps afterwards",
-			replWithStats.repl.RangeID, replWithStats.qps, candidateStore, meanQPS, newCandidateQPS)
+			"r%d's %.2f load would push s%d over the mean (%.2f) with %.2f load afterwards",
+			replWithStats.repl.RangeID, replLoad, candidateStore, meanLoad, newCandidateLoad)
 		return true
 	}
 
@@ -72,8 +72,9 @@ var (
 
 type testRange struct {
 	// The first storeID in the list will be the leaseholder.
-	storeIDs []roachpb.StoreID
-	qps      float64
+	storeIDs   []roachpb.StoreID
+	qps        float64
+	writeBytes float64
 }
 
 func loadRanges(rr *replicaRankings, s *Store, ranges []testRange) {
@@ -99,8 +100,9 @@ func loadRanges(rr *replicaRankings, s *Store, ranges []testRange) {
 		repl.leaseholderStats = newReplicaStats(s.Clock(), nil)
 		repl.writeStats = newReplicaStats(s.Clock(), nil)
 		acc.addReplica(replicaWithStats{
-			repl: repl,
-			qps:  r.qps,
+			repl:       repl,
+			qps:        r.qps,
+			writeBytes: r.writeBytes,
 		})
 	}
 	rr.update(acc)
@@ -374,3 +376,96 @@ func TestNoLeaseTransferToBehindReplicas(t *testing.T) {
 			targets, sr.getRaftStatusFn(repl), expectTargets)
 	}
 }
+
+func TestChooseReplicaToRebalanceByWriteBytes(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	// The stores all serve the same QPS, but s1 is overloaded in terms of bytes
+	// written per second.
+	const mib = 1 << 20
+	var stores []*roachpb.StoreDescriptor
+	for i, writeBytes := range []float64{15 * mib, 11 * mib, 10 * mib, 9 * mib, 5 * mib} {
+		stores = append(stores, &roachpb.StoreDescriptor{
+			StoreID: roachpb.StoreID(i + 1),
+			Node:    roachpb.NodeDescriptor{NodeID: roachpb.NodeID(i + 1)},
+			Capacity: roachpb.StoreCapacity{
+				QueriesPerSecond:    1000,
+				WriteBytesPerSecond: writeBytes,
+			},
+		})
+	}
+
+	stopper, g, _, a, _ := createTestAllocator(10, false /* deterministic */)
+	defer stopper.Stop(context.Background())
+	gossiputil.NewStoreGossiper(g).GossipStores(stores, t)
+	storeList, _, _ := a.storePool.getStoreList(firstRangeID, storeFilterThrottled)
+	storeMap := storeListToMap(storeList)
+
+	localDesc := *stores[0]
+	cfg := TestStoreConfig(nil)
+	s := createTestStoreWithoutStart(t, stopper, testStoreOpts{createSystemRanges: true}, &cfg)
+	s.Ident = &roachpb.StoreIdent{StoreID: localDesc.StoreID}
+	s.cfg.DefaultZoneConfig.NumReplicas = proto.Int32(1)
+	rq := newReplicateQueue(s, g, a)
+	rr := newReplicaRankings()
+
+	sr := NewStoreRebalancer(cfg.AmbientCtx, cfg.Settings, rq, rr)
+	sr.getRaftStatusFn = func(r *Replica) *raft.Status {
+		status := &raft.Status{
+			Progress: make(map[uint64]tracker.Progress),
+		}
+		status.Lead = uint64(r.ReplicaID())
+		status.Commit = 1
+		for _, replica := range r.Desc().InternalReplicas {
+			status.Progress[uint64(replica.ReplicaID)] = tracker.Progress{
+				Match: 1,
+				State: tracker.StateReplicate,
+			}
+		}
+		return status
+	}
+
+	// A range that receives no queries but writes 2 MiB/s.
+	ranges := []testRange{{storeIDs: []roachpb.StoreID{1}, writeBytes: 2 * mib}}
+
+	// When balancing on QPS alone, the range isn't worth moving.
+	loadRanges(rr, s, ranges)
+	w := sr.loadWeights()
+	minLoad, maxLoad := 0.75*w.meanStoreLoad(storeList), 1.25*w.meanStoreLoad(storeList)
+	hottestRanges := sr.hottestReplicas(w)
+	if _, targets := sr.chooseReplicaToRebalance(
+		ctx, &hottestRanges, &localDesc, storeList, storeMap, minLoad, maxLoad,
+	); len(targets) != 0 {
+		t.Fatalf("expected no rebalance targets when balancing on QPS, got %v", targets)
+	}
+
+	// When balancing on write bytes, moving the lease wouldn't help, but moving
+	// the replica to the store with the least write load would.
+	qpsLoadWeight.Override(&cfg.Settings.SV, 0)
+	writeKiBLoadWeight.Override(&cfg.Settings.SV, 1)
+	loadRanges(rr, s, ranges)
+	w = sr.loadWeights()
+	minLoad, maxLoad = 0.75*w.meanStoreLoad(storeList), 1.25*w.meanStoreLoad(storeList)
+	hottestRanges = sr.hottestReplicas(w)
+	if len(hottestRanges) != 1 {
+		t.Fatalf("expected 1 hot range, got %v", hottestRanges)
+	}
+	_, target, considerForRebalance := sr.chooseLeaseToTransfer(
+		ctx, &hottestRanges, &localDesc, storeList, storeMap, minLoad, maxLoad)
+	if target.StoreID != 0 {
+		t.Errorf("expected no lease transfer target, got s%d", target.StoreID)
+	}
+	if len(considerForRebalance) != 1 {
+		t.Fatalf("expected range to be considered for rebalancing, got %v", considerForRebalance)
+	}
+	_, targets := sr.chooseReplicaToRebalance(
+		ctx, &considerForRebalance, &localDesc, storeList, storeMap, minLoad, maxLoad)
+	expectTargets := []roachpb.ReplicationTarget{{NodeID: 5, StoreID: 5}}
+	if !reflect.DeepEqual(targets, expectTargets) {
+		t.Errorf("got targets %v; want %v", targets, expectTargets)
+	}
+}
@@ -81,7 +81,10 @@ func splitPreApply(
 		// we read the HardState to preserve it, clear everything and write back
 		// the HardState and tombstone. Note that we only do this if rightRepl
 		// exists; if it doesn't, there's no Raft state to massage (when rightRepl
-		// was removed, a tombstone was written instead).
+		// was removed, a tombstone was written instead). If the store keeps its
+		// HardStates in a separate engine, there's nothing to preserve here
+		// since clearing the range data doesn't touch that engine.
+		separateRaftEngine := r.store.hasSeparateRaftEngine()
 		var hs raftpb.HardState
 		if rightRepl != nil {
 			// Assert that the rightRepl is not initialized. We're about to clear out
@@ -90,6 +93,8 @@ func splitPreApply(
 			if rightRepl.IsInitialized() {
 				log.Fatalf(ctx, "unexpectedly found initialized newer RHS of split: %v", rightRepl.Desc())
 			}
+		}
+		if rightRepl != nil && !separateRaftEngine {
 			hs, err = rightRepl.raftMu.stateLoader.LoadHardState(ctx, readWriter)
 			if err != nil {
 				log.Fatalf(ctx, "failed to load hard state for removed rhs: %v", err)
@@ -100,7 +105,7 @@ func splitPreApply(
 		if err := clearRangeData(&split.RightDesc, readWriter, readWriter, rangeIDLocalOnly, mustUseClearRange); err != nil {
 			log.Fatalf(ctx, "failed to clear range data for removed rhs: %v", err)
 		}
-		if rightRepl != nil {
+		if rightRepl != nil && !separateRaftEngine {
 			if err := rightRepl.raftMu.stateLoader.SetHardState(ctx, readWriter, hs); err != nil {
 				log.Fatalf(ctx, "failed to set hard state with 0 commit index for removed rhs: %v", err)
 			}
@@ -111,6 +116,13 @@ func splitPreApply(
 	// Update the raft HardState with the new Commit value now that the
 	// replica is initialized (combining it with existing or default
 	// Term and Vote). This is the common case.
+	//
+	// If the store keeps its HardStates in a separate engine, this happens
+	// once the split has been committed, when the RHS is loaded (see
+	// ReconcileRaftState).
+	if r.store.hasSeparateRaftEngine() {
+		return
+	}
 	rsl := stateloader.Make(split.RightDesc.RangeID)
 	if err := rsl.SynthesizeRaftState(ctx, readWriter); err != nil {
 		log.Fatal(ctx, err)
@@ -282,6 +294,9 @@ func (s *Store) SplitRange(
 	// to ensure that no pre-split commands are inserted into the
 	// txnWaitQueue after we clear it.
 	leftRepl.txnWaitQueue.Clear(false /* disable */)
+	// Similarly, clear the LHS unreplicated locks. Those in the RHS would
+	// otherwise never be released.
+	leftRepl.unreplicatedLocks.Clear()
 
 	// The rangefeed processor will no longer be provided logical ops for
 	// its entire range, so it needs to be shut down and all registrations
@@ -300,9 +315,15 @@ func (s *Store) SplitRange(
 	if rightReplOrNil == nil {
 		throwawayRightWriteStats := new(replicaStats)
 		leftRepl.writeStats.splitRequestCounts(throwawayRightWriteStats)
+		throwawayRightWriteBytesStats := new(replicaStats)
+		leftRepl.writeBytesStats.splitRequestCounts(throwawayRightWriteBytesStats)
+		throwawayRightCPUStats := new(replicaStats)
+		leftRepl.cpuStats.splitRequestCounts(throwawayRightCPUStats)
 	} else {
 		rightRepl := rightReplOrNil
 		leftRepl.writeStats.splitRequestCounts(rightRepl.writeStats)
+		leftRepl.writeBytesStats.splitRequestCounts(rightRepl.writeBytesStats)
+		leftRepl.cpuStats.splitRequestCounts(rightRepl.cpuStats)
 		if err := s.addReplicaInternalLocked(rightRepl); err != nil {
 			return errors.Errorf("unable to add replica %v: %s", rightRepl, err)
 		}
@@ -427,6 +427,14 @@ var charts = []sectionDescription{
 				Title:   "QPS",
 				Metrics: []string{"rebalancing.queriespersecond"},
 			},
+			{
+				Title:   "Write Bytes/Sec",
+				Metrics: []string{"rebalancing.writebytespersecond"},
+			},
+			{
+				Title:   "CPU Nanos/Sec",
+				Metrics: []string{"rebalancing.cpunanospersecond"},
+			},
 		},
 	},
 	{
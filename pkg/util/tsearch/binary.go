@@ -0,0 +1,264 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// This file implements the Postgres binary wire formats of tsvector and
+// tsquery (see tsvectorsend and tsquerysend in the Postgres sources).
+
+// Item and operator tags of the tsquery binary format.
+const (
+	binaryItemOperand  = 1
+	binaryItemOperator = 2
+
+	binaryOpNot    = 1
+	binaryOpAnd    = 2
+	binaryOpOr     = 3
+	binaryOpPhrase = 4
+)
+
+// EncodeBinary appends the Postgres binary representation of the vector to b.
+// Each position is sent as a 16-bit integer with the weight in the two high
+// bits.
+func (v TSVector) EncodeBinary(b []byte) []byte {
+	b = appendUint32(b, uint32(len(v)))
+	for _, l := range v {
+		b = append(b, l.Text...)
+		b = append(b, 0)
+		b = appendUint16(b, uint16(len(l.Positions)))
+		for _, p := range l.Positions {
+			b = appendUint16(b, uint16(p.Weight)<<14|p.Pos)
+		}
+	}
+	return b
+}
+
+// DecodeTSVectorBinary decodes the Postgres binary representation of a
+// tsvector.
+func DecodeTSVectorBinary(b []byte) (TSVector, error) {
+	r := binaryReader{b: b}
+	n := r.uint32()
+	var lexemes []Lexeme
+	for i := uint32(0); i < n && r.err == nil; i++ {
+		l := Lexeme{Text: r.cstring()}
+		if r.err == nil && (len(l.Text) == 0 || len(l.Text) > MaxLexemeLength) {
+			return nil, pgerror.Newf(pgcode.InvalidBinaryRepresentation,
+				"invalid tsvector: lexeme length %d", len(l.Text))
+		}
+		npos := r.uint16()
+		for j := uint16(0); j < npos && r.err == nil; j++ {
+			wep := r.uint16()
+			p := Position{Pos: wep & MaxPosition, Weight: Weight(wep >> 14)}
+			if p.Pos == 0 {
+				return nil, pgerror.New(pgcode.InvalidBinaryRepresentation,
+					"wrong position info in tsvector")
+			}
+			l.Positions = append(l.Positions, p)
+		}
+		lexemes = append(lexemes, l)
+	}
+	if err := r.finish(); err != nil {
+		return nil, err
+	}
+	return makeTSVector(lexemes), nil
+}
+
+// EncodeBinary appends the Postgres binary representation of the query to b.
+// Nodes are sent in prefix order, with the right operand of a binary operator
+// preceding its left operand.
+func (q TSQuery) EncodeBinary(b []byte) []byte {
+	var count int
+	q.walk(func(*Node) { count++ })
+	b = appendUint32(b, uint32(count))
+	if q.Root != nil {
+		b = q.Root.encodeBinary(b)
+	}
+	return b
+}
+
+func (n *Node) encodeBinary(b []byte) []byte {
+	switch n.Op {
+	case OpLexeme:
+		b = append(b, binaryItemOperand, byte(n.Weights))
+		if n.Prefix {
+			b = append(b, 1)
+		} else {
+			b = append(b, 0)
+		}
+		b = append(b, n.Lexeme...)
+		return append(b, 0)
+	case OpNot:
+		b = append(b, binaryItemOperator, binaryOpNot)
+		return n.Left.encodeBinary(b)
+	case OpAnd:
+		b = append(b, binaryItemOperator, binaryOpAnd)
+	case OpOr:
+		b = append(b, binaryItemOperator, binaryOpOr)
+	case OpPhrase:
+		b = append(b, binaryItemOperator, binaryOpPhrase)
+		b = appendUint16(b, uint16(n.Distance))
+	}
+	b = n.Right.encodeBinary(b)
+	return n.Left.encodeBinary(b)
+}
+
+// DecodeTSQueryBinary decodes the Postgres binary representation of a tsquery.
+func DecodeTSQueryBinary(b []byte) (TSQuery, error) {
+	r := binaryReader{b: b}
+	n := r.uint32()
+	var q TSQuery
+	if n > 0 {
+		q.Root = r.node()
+	}
+	if err := r.finish(); err != nil {
+		return TSQuery{}, err
+	}
+	var count uint32
+	q.walk(func(*Node) { count++ })
+	if count != n {
+		return TSQuery{}, pgerror.New(pgcode.InvalidBinaryRepresentation, "incorrect binary data format")
+	}
+	return q, nil
+}
+
+// binaryReader decodes the binary formats. The first error encountered is
+// stored in err, after which all reads return zero values.
+type binaryReader struct {
+	b   []byte
+	err error
+}
+
+func (r *binaryReader) fail() {
+	if r.err == nil {
+		r.err = pgerror.New(pgcode.InvalidBinaryRepresentation, "insufficient data left in message")
+	}
+	r.b = nil
+}
+
+func (r *binaryReader) byte() byte {
+	if len(r.b) < 1 {
+		r.fail()
+		return 0
+	}
+	c := r.b[0]
+	r.b = r.b[1:]
+	return c
+}
+
+func (r *binaryReader) uint16() uint16 {
+	if len(r.b) < 2 {
+		r.fail()
+		return 0
+	}
+	v := binary.BigEndian.Uint16(r.b)
+	r.b = r.b[2:]
+	return v
+}
+
+func (r *binaryReader) uint32() uint32 {
+	if len(r.b) < 4 {
+		r.fail()
+		return 0
+	}
+	v := binary.BigEndian.Uint32(r.b)
+	r.b = r.b[4:]
+	return v
+}
+
+func (r *binaryReader) cstring() string {
+	i := bytes.IndexByte(r.b, 0)
+	if i < 0 {
+		r.fail()
+		return ""
+	}
+	s := string(r.b[:i])
+	r.b = r.b[i+1:]
+	return s
+}
+
+// node decodes a tsquery node and its operands.
+func (r *binaryReader) node() *Node {
+	if r.err != nil {
+		return nil
+	}
+	switch r.byte() {
+	case binaryItemOperand:
+		n := &Node{Op: OpLexeme, Weights: WeightMask(r.byte())}
+		n.Prefix = r.byte() != 0
+		n.Lexeme = r.cstring()
+		if r.err == nil && (len(n.Lexeme) == 0 || len(n.Lexeme) > MaxLexemeLength) {
+			r.err = pgerror.Newf(pgcode.InvalidBinaryRepresentation,
+				"invalid tsquery: operand length %d", len(n.Lexeme))
+		}
+		return n
+	case binaryItemOperator:
+		n := &Node{}
+		switch op := r.byte(); op {
+		case binaryOpNot:
+			n.Op = OpNot
+			n.Left = r.node()
+			return n
+		case binaryOpAnd:
+			n.Op = OpAnd
+		case binaryOpOr:
+			n.Op = OpOr
+		case binaryOpPhrase:
+			n.Op = OpPhrase
+			n.Distance = int(r.uint16())
+			if n.Distance > MaxPhraseDistance {
+				r.err = pgerror.New(pgcode.InvalidBinaryRepresentation,
+					"distance in phrase operator should not be greater than 16384")
+			}
+		default:
+			if r.err == nil {
+				r.err = pgerror.Newf(pgcode.InvalidBinaryRepresentation,
+					"invalid tsquery: unrecognized operator type %d", op)
+			}
+			return nil
+		}
+		n.Right = r.node()
+		n.Left = r.node()
+		return n
+	default:
+		if r.err == nil {
+			r.err = pgerror.New(pgcode.InvalidBinaryRepresentation,
+				"invalid tsquery: unrecognized item type")
+		}
+		return nil
+	}
+}
+
+// finish returns the decoding error, if any, or an error if there is data
+// left over.
+func (r *binaryReader) finish() error {
+	if r.err != nil {
+		return r.err
+	}
+	if len(r.b) != 0 {
+		return pgerror.New(pgcode.InvalidBinaryRepresentation, "incorrect binary data format")
+	}
+	return nil
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	return append(b, byte(v>>8), byte(v))
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
@@ -0,0 +1,93 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	for _, s := range []string{
+		``,
+		`a fat cat`,
+		`'a':1 'cat':3A,5 'fat':2B 'don''t':4C`,
+	} {
+		v, err := ParseTSVector(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v2, err := DecodeTSVectorBinary(v.EncodeBinary(nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.Compare(v2) != 0 {
+			t.Errorf("%s did not round-trip, got %s", v, v2)
+		}
+	}
+
+	for _, s := range []string{
+		``,
+		`cat`,
+		`fat & (rat | !cat)`,
+		`super:*AB <3> cat:C`,
+		`fat <-> (rat <-> cat)`,
+	} {
+		q, err := ParseTSQuery(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q2, err := DecodeTSQueryBinary(q.EncodeBinary(nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q.Compare(q2) != 0 {
+			t.Errorf("%s did not round-trip, got %s", q, q2)
+		}
+	}
+}
+
+func TestBinaryFormat(t *testing.T) {
+	// Compare against the output of tsvectorsend and tsquerysend in Postgres.
+	v, err := ParseTSVector(`cat:3A`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, e := v.EncodeBinary(nil), []byte{0, 0, 0, 1, 'c', 'a', 't', 0, 0, 1, 0xc0, 3}; !bytes.Equal(b, e) {
+		t.Errorf("expected %x, got %x", e, b)
+	}
+
+	q, err := ParseTSQuery(`fat & !cat:B`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := []byte{
+		0, 0, 0, 4,
+		2, 2,
+		2, 1,
+		1, 4, 0, 'c', 'a', 't', 0,
+		1, 0, 0, 'f', 'a', 't', 0,
+	}
+	if b := q.EncodeBinary(nil); !bytes.Equal(b, e) {
+		t.Errorf("expected %x, got %x", e, b)
+	}
+
+	for _, b := range [][]byte{
+		{0, 0, 0, 1},
+		{0, 0, 0, 1, 2, 2, 1, 0, 0, 'a', 0},
+		{0, 0, 0, 2, 1, 0, 0, 'a', 0},
+		{0, 0, 0, 1, 1, 0, 0, 'a', 0, 0},
+	} {
+		if _, err := DecodeTSQueryBinary(b); err == nil {
+			t.Errorf("%x: expected error", b)
+		}
+	}
+}
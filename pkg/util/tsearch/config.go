@@ -0,0 +1,205 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// DefaultConfig is the name of the configuration used by the text search
+// functions when none is specified.
+const DefaultConfig = "english"
+
+// Config is a text search configuration, which determines how a document is
+// split into words and how the words are normalized into lexemes.
+//
+// Words are maximal sequences of letters and digits. This is simpler than the
+// Postgres parser, which recognizes many more token types (e.g. URLs, email
+// addresses and hyphenated words), but it agrees with it on plain text.
+type Config struct {
+	name      string
+	stopWords map[string]struct{}
+	stem      func(string) string
+}
+
+var configs = map[string]*Config{
+	"simple": {
+		name: "simple",
+	},
+	"english": {
+		name:      "english",
+		stopWords: englishStopWords,
+		stem:      stemEnglish,
+	},
+}
+
+// GetConfig returns the configuration with the given name. The name can be
+// qualified with the pg_catalog schema.
+func GetConfig(name string) (*Config, error) {
+	lookup := strings.TrimPrefix(strings.ToLower(name), "pg_catalog.")
+	if c, ok := configs[lookup]; ok {
+		return c, nil
+	}
+	return nil, pgerror.Newf(pgcode.UndefinedObject,
+		"text search configuration %q does not exist", name)
+}
+
+// Name returns the name of the configuration.
+func (c *Config) Name() string {
+	return c.name
+}
+
+// word is a word of a document, along with its location in the document.
+type word struct {
+	text       string
+	start, end int
+}
+
+// splitWords splits a document into words.
+func splitWords(doc string) []word {
+	var words []word
+	start := -1
+	for i, r := range doc {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			words = append(words, word{text: doc[start:i], start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, word{text: doc[start:], start: start, end: len(doc)})
+	}
+	return words
+}
+
+// normalizeWord returns the lexeme for the given word, or false if the word
+// is a stop word or is too long to be indexed.
+func (c *Config) normalizeWord(w string) (string, bool) {
+	w = strings.ToLower(w)
+	if len(w) > MaxLexemeLength {
+		return "", false
+	}
+	if _, ok := c.stopWords[w]; ok {
+		return "", false
+	}
+	if c.stem != nil {
+		w = c.stem(w)
+	}
+	return w, true
+}
+
+// normalizeText returns the lexemes for all the words in the given text.
+func (c *Config) normalizeText(text string) []string {
+	var res []string
+	for _, w := range splitWords(text) {
+		if l, ok := c.normalizeWord(w.text); ok {
+			res = append(res, l)
+		}
+	}
+	return res
+}
+
+// ToTSVector converts a document into a tsvector. Every word of the document,
+// including stop words, occupies a position.
+func (c *Config) ToTSVector(doc string) TSVector {
+	var lexemes []Lexeme
+	for i, w := range splitWords(doc) {
+		l, ok := c.normalizeWord(w.text)
+		if !ok {
+			continue
+		}
+		pos := i + 1
+		if pos > MaxPosition {
+			pos = MaxPosition
+		}
+		lexemes = append(lexemes, Lexeme{Text: l, Positions: []Position{{Pos: uint16(pos)}}})
+	}
+	return makeTSVector(lexemes)
+}
+
+// ToTSQuery parses a query written in the tsquery syntax, normalizing every
+// operand into lexemes. Operands that consist only of stop words are removed
+// from the query.
+func (c *Config) ToTSQuery(query string) (TSQuery, error) {
+	return parseTSQuery(query, c.normalizeText)
+}
+
+// PlainToTSQuery converts plain text into a query that matches documents
+// containing all of its lexemes. Punctuation in the text is ignored.
+func (c *Config) PlainToTSQuery(text string) TSQuery {
+	var root *Node
+	for _, l := range c.normalizeText(text) {
+		root = combine(OpAnd, root, &Node{Op: OpLexeme, Lexeme: l}, 0 /* distance */)
+	}
+	return TSQuery{Root: root}
+}
+
+// PhraseToTSQuery converts plain text into a query that matches documents
+// containing all of its lexemes in the same order, as a phrase.
+func (c *Config) PhraseToTSQuery(text string) TSQuery {
+	var root *Node
+	distance := 1
+	for i, w := range splitWords(text) {
+		l, ok := c.normalizeWord(w.text)
+		if !ok {
+			// Stop words still occupy a position in the phrase.
+			if i > 0 && root != nil {
+				distance++
+			}
+			continue
+		}
+		root = combine(OpPhrase, root, &Node{Op: OpLexeme, Lexeme: l}, distance)
+		distance = 1
+	}
+	return TSQuery{Root: root}
+}
+
+// englishStopWords is the list of stop words used by the english
+// configuration. It is the same list used by Postgres.
+var englishStopWords = makeStopWords(`
+i me my myself we our ours ourselves you your yours yourself yourselves he
+him his himself she her hers herself it its itself they them their theirs
+themselves what which who whom this that these those am is are was were be
+been being have has had having do does did doing a an the and but if or
+because as until while of at by for with about against between into through
+during before after above below to from up down in out on off over under
+again further then once here there when where why how all any both each few
+more most other some such no nor not only own same so than too very s t can
+will just don should now
+`)
+
+func makeStopWords(list string) map[string]struct{} {
+	res := make(map[string]struct{})
+	for _, w := range strings.Fields(list) {
+		res[w] = struct{}{}
+	}
+	return res
+}
+
+// isASCII returns true if the string only contains ASCII characters.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,193 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import "sort"
+
+// Match returns true if the vector matches the query, which is the semantics
+// of the @@ operator. An empty query matches nothing.
+func Match(v TSVector, q TSQuery) bool {
+	if q.Root == nil {
+		return false
+	}
+	return matchNode(v, q.Root)
+}
+
+func matchNode(v TSVector, n *Node) bool {
+	switch n.Op {
+	case OpLexeme:
+		return matchPositions(v, n).matched
+	case OpNot:
+		return !matchNode(v, n.Left)
+	case OpAnd:
+		return matchNode(v, n.Left) && matchNode(v, n.Right)
+	case OpOr:
+		return matchNode(v, n.Left) || matchNode(v, n.Right)
+	case OpPhrase:
+		return matchPositions(v, n).matched
+	}
+	return false
+}
+
+// phraseMatch is the result of matching a query node as part of a phrase.
+type phraseMatch struct {
+	matched bool
+	// anyPosition is set when the match has no position information, e.g.
+	// because the lexemes were stripped of their positions. Such a match
+	// satisfies any distance constraint.
+	anyPosition bool
+	// positions are the sorted positions at which the match ends.
+	positions []uint16
+}
+
+// matchPositions matches a node in the context of a phrase, returning the
+// positions at which it matches.
+//
+// Negations inside a phrase only check whether their operand occurs anywhere
+// in the document, which is a simplification of the Postgres behavior.
+func matchPositions(v TSVector, n *Node) phraseMatch {
+	switch n.Op {
+	case OpLexeme:
+		var res phraseMatch
+		var lexemes []Lexeme
+		if n.Prefix {
+			lexemes = v.findPrefix(n.Lexeme)
+		} else if l := v.find(n.Lexeme); l != nil {
+			lexemes = []Lexeme{*l}
+		}
+		for _, l := range lexemes {
+			if len(l.Positions) == 0 {
+				res.matched, res.anyPosition = true, true
+				continue
+			}
+			for _, p := range l.Positions {
+				if n.Weights == 0 || n.Weights.Contains(p.Weight) {
+					res.matched = true
+					res.positions = append(res.positions, p.Pos)
+				}
+			}
+		}
+		if len(lexemes) > 1 {
+			sort.Slice(res.positions, func(i, j int) bool { return res.positions[i] < res.positions[j] })
+		}
+		return res
+
+	case OpNot:
+		return phraseMatch{matched: !matchNode(v, n.Left), anyPosition: true}
+
+	case OpAnd, OpOr:
+		left, right := matchPositions(v, n.Left), matchPositions(v, n.Right)
+		if n.Op == OpAnd && !(left.matched && right.matched) {
+			return phraseMatch{}
+		}
+		return unionMatches(left, right)
+
+	case OpPhrase:
+		left := matchPositions(v, n.Left)
+		if !left.matched {
+			return phraseMatch{}
+		}
+		right := matchPositions(v, n.Right)
+		if !right.matched {
+			return phraseMatch{}
+		}
+		if left.anyPosition || right.anyPosition {
+			return right
+		}
+		var res phraseMatch
+		for _, p := range right.positions {
+			if int(p) < n.Distance {
+				continue
+			}
+			want := p - uint16(n.Distance)
+			i := sort.Search(len(left.positions), func(i int) bool { return left.positions[i] >= want })
+			if i < len(left.positions) && left.positions[i] == want {
+				res.matched = true
+				res.positions = append(res.positions, p)
+			}
+		}
+		return res
+	}
+	return phraseMatch{}
+}
+
+// unionMatches combines the positions of two phrase matches.
+func unionMatches(a, b phraseMatch) phraseMatch {
+	res := phraseMatch{
+		matched:     a.matched || b.matched,
+		anyPosition: a.anyPosition || b.anyPosition,
+	}
+	res.positions = append(append(res.positions, a.positions...), b.positions...)
+	sort.Slice(res.positions, func(i, j int) bool { return res.positions[i] < res.positions[j] })
+	return res
+}
+
+// RequiredLexemes returns a set of lexemes such that every document that
+// matches the query contains at least one of them. It returns false if no
+// such set can be derived, e.g. because the query only contains negations or
+// prefix matches. This is used to constrain scans over inverted indexes.
+func (q TSQuery) RequiredLexemes() ([]string, bool) {
+	if q.Root == nil {
+		return nil, false
+	}
+	return requiredLexemes(q.Root)
+}
+
+func requiredLexemes(n *Node) ([]string, bool) {
+	switch n.Op {
+	case OpLexeme:
+		if n.Prefix {
+			return nil, false
+		}
+		return []string{n.Lexeme}, true
+	case OpAnd, OpPhrase:
+		// Either side is sufficient; prefer the one that requires fewer
+		// lexemes, as it will scan fewer index entries.
+		left, leftOk := requiredLexemes(n.Left)
+		right, rightOk := requiredLexemes(n.Right)
+		if !leftOk || (rightOk && len(right) < len(left)) {
+			return right, rightOk
+		}
+		return left, leftOk
+	case OpOr:
+		left, ok := requiredLexemes(n.Left)
+		if !ok {
+			return nil, false
+		}
+		right, ok := requiredLexemes(n.Right)
+		if !ok {
+			return nil, false
+		}
+		return append(left, right...), true
+	}
+	return nil, false
+}
+
+// positiveOperands returns the lexeme operands of the query that are not
+// negated.
+func (q TSQuery) positiveOperands() []*Node {
+	var res []*Node
+	var visit func(n *Node)
+	visit = func(n *Node) {
+		switch n.Op {
+		case OpLexeme:
+			res = append(res, n)
+		case OpNot:
+		default:
+			visit(n.Left)
+			visit(n.Right)
+		}
+	}
+	if q.Root != nil {
+		visit(q.Root)
+	}
+	return res
+}
@@ -0,0 +1,211 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	testCases := []struct {
+		vector   string
+		query    string
+		expected bool
+	}{
+		{`a fat cat`, `cat`, true},
+		{`a fat cat`, `dog`, false},
+		{`a fat cat`, ``, false},
+		{`a fat cat`, `fat & cat`, true},
+		{`a fat cat`, `fat & dog`, false},
+		{`a fat cat`, `dog | cat`, true},
+		{`a fat cat`, `!dog`, true},
+		{`a fat cat`, `fat & !cat`, false},
+		{`a fat cat`, `fa:*`, true},
+		{`a fat cat`, `fab:*`, false},
+		{`a:1 fat:2 cat:3`, `fat <-> cat`, true},
+		{`a:1 fat:2 cat:3`, `cat <-> fat`, false},
+		{`a:1 fat:2 cat:3`, `a <2> cat`, true},
+		{`a:1 fat:2 cat:3`, `a <-> cat`, false},
+		{`a:1 fat:2 cat:3`, `a <-> fat <-> cat`, true},
+		{`a:1 fat:2 cat:3`, `a <-> (fat | dog)`, true},
+		{`a:1 fat:2 cat:3`, `f:* <-> c:*`, true},
+		{`a:1 fat:2 cat:3`, `fat <-> !dog`, true},
+		{`a:1 fat:2 cat:3`, `fat <-> !cat`, false},
+		// Lexemes without positions satisfy any phrase.
+		{`a fat cat`, `cat <-> fat`, true},
+		// Weights.
+		{`fat:1A cat:2`, `fat:A`, true},
+		{`fat:1A cat:2`, `fat:BC`, false},
+		{`fat:1A cat:2`, `cat:D`, true},
+		{`fat:1A cat:2`, `fat:A <-> cat`, true},
+		{`fat:1A cat:2`, `fat:B <-> cat`, false},
+	}
+	for _, tc := range testCases {
+		v, err := ParseTSVector(tc.vector)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q, err := ParseTSQuery(tc.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res := Match(v, q); res != tc.expected {
+			t.Errorf("%s @@ %s: expected %t, got %t", tc.vector, tc.query, tc.expected, res)
+		}
+	}
+}
+
+func TestRequiredLexemes(t *testing.T) {
+	testCases := []struct {
+		query    string
+		expected []string
+	}{
+		{``, nil},
+		{`cat`, []string{"cat"}},
+		{`cat:A`, []string{"cat"}},
+		{`ca:*`, nil},
+		{`!cat`, nil},
+		{`fat & cat`, []string{"fat"}},
+		{`fat & !cat`, []string{"fat"}},
+		{`!fat & cat`, []string{"cat"}},
+		{`(fat | rat) & cat`, []string{"cat"}},
+		{`fat | rat`, []string{"fat", "rat"}},
+		{`fat | !rat`, nil},
+		{`fat <-> cat`, []string{"fat"}},
+		{`(fat | rat) <-> (cat | dog)`, []string{"fat", "rat"}},
+	}
+	for _, tc := range testCases {
+		q, err := ParseTSQuery(tc.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, ok := q.RequiredLexemes()
+		if ok != (tc.expected != nil) || !reflect.DeepEqual(res, tc.expected) {
+			t.Errorf("%s: expected %v, got %v (%t)", tc.query, tc.expected, res, ok)
+		}
+	}
+}
+
+func TestRank(t *testing.T) {
+	english, err := GetConfig("english")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		doc      string
+		query    string
+		norm     RankNormalization
+		expected string
+	}{
+		{`a fat cat`, `cat`, 0, `0.0607927`},
+		{`a fat cat`, `dog`, 0, `0`},
+		{`a fat cat sat on a mat and ate a fat rat`, `fat`, 0, `0.0759909`},
+		{`a fat cat sat on a mat and ate a fat rat`, `fat | rat`, 0, `0.0683918`},
+		{`a fat cat`, `fat & cat`, 0, `0.0991032`},
+		{`a fat cat`, `fat & dog`, 0, `1e-20`},
+		{`a fat cat`, `cat`, RankNormLength, `0.0303964`},
+		{`a fat cat`, `cat`, RankNormRDivRPlus1, `0.0573088`},
+	}
+	for _, tc := range testCases {
+		q, err := english.ToTSQuery(tc.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v := english.ToTSVector(tc.doc)
+		rank := Rank(DefaultRankWeights, v, q, tc.norm)
+		if s := fmt.Sprintf("%.6g", rank); s != tc.expected {
+			t.Errorf("%s, %s: expected %s, got %s", v, q, tc.expected, s)
+		}
+	}
+
+	if _, err := ValidateRankWeights([]float64{0.1, 0.2}); err == nil ||
+		!strings.Contains(err.Error(), "array of weight is too short") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := ValidateRankWeights([]float64{0.1, 0.2, 0.3, 1.5}); err == nil ||
+		!strings.Contains(err.Error(), "weight out of range") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	weights, err := ValidateRankWeights([]float64{-1, 0.5, 0.5, 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := [4]float64{0.1, 0.5, 0.5, 0.5}; weights != e {
+		t.Errorf("expected %v, got %v", e, weights)
+	}
+}
+
+func TestHeadline(t *testing.T) {
+	english, err := GetConfig("english")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const doc = `The most common type of search
+is to find all documents containing given query terms
+and return them in order of their similarity to the
+query.`
+	testCases := []struct {
+		doc      string
+		query    string
+		opts     string
+		expected string
+	}{
+		{`The fat cat sat.`, `cat`, ``, `The fat <b>cat</b> sat.`},
+		{`The fat cat sat.`, `dog`, ``, `The fat cat sat.`},
+		{`The fat cat sat.`, `cat | sat`, `StartSel=[, StopSel=]`, `The fat [cat] [sat].`},
+		{`The fat cat sat.`, `c:*`, ``, `The fat <b>cat</b> sat.`},
+		{`The fat cat sat.`, `fat & !cat`, ``, `The <b>fat</b> cat sat.`},
+		{doc, `query & similarity`, ``,
+			`containing given <b>query</b> terms
+and return them in order of their <b>similarity</b> to the
+<b>query</b>.`},
+		{doc, `search & term`, `MaxWords=10, MinWords=5`,
+			`<b>search</b>
+is to find all documents containing given query <b>terms</b>`},
+		{doc, `type`, `MaxWords=5, MinWords=3`, `<b>type</b> of search`},
+		{doc, `search & term`, `HighlightAll=true`,
+			`The most common type of <b>search</b>
+is to find all documents containing given query <b>terms</b>
+and return them in order of their similarity to the
+query.`},
+	}
+	for _, tc := range testCases {
+		q, err := english.ToTSQuery(tc.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		opts, err := ParseHeadlineOptions(tc.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := english.Headline(tc.doc, q, opts); s != tc.expected {
+			t.Errorf("%q, %s: expected\n%s\ngot\n%s", tc.doc, q, tc.expected, s)
+		}
+	}
+
+	for _, tc := range []struct {
+		opts string
+		err  string
+	}{
+		{`Color=red`, `unrecognized headline parameter: "Color"`},
+		{`MaxWords=x`, `invalid value for headline parameter "MaxWords"`},
+		{`MaxWords=5, MinWords=5`, `MinWords should be less than MaxWords`},
+		{`MinWords=0`, `MinWords should be positive`},
+		{`MaxFragments=2`, `headline parameter "MaxFragments" is not supported`},
+	} {
+		if _, err := ParseHeadlineOptions(tc.opts); err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s: expected error %q, got %v", tc.opts, tc.err, err)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// HeadlineOptions control the output of Headline. They correspond to the
+// options accepted by the Postgres ts_headline function.
+type HeadlineOptions struct {
+	// StartSel and StopSel delimit the query words in the headline.
+	StartSel, StopSel string
+	// MaxWords and MinWords bound the length of the headline, in words.
+	MaxWords, MinWords int
+	// ShortWord is accepted for compatibility, but is not used.
+	ShortWord int
+	// HighlightAll makes the headline the whole document.
+	HighlightAll bool
+}
+
+// DefaultHeadlineOptions returns the options used by Headline when none are
+// specified.
+func DefaultHeadlineOptions() HeadlineOptions {
+	return HeadlineOptions{
+		StartSel:  "<b>",
+		StopSel:   "</b>",
+		MaxWords:  35,
+		MinWords:  15,
+		ShortWord: 3,
+	}
+}
+
+// ParseHeadlineOptions parses a comma-separated list of option=value pairs,
+// e.g. "StartSel=<, StopSel=>, MaxWords=10", into a set of options. Options
+// that are not specified take their default value.
+func ParseHeadlineOptions(s string) (HeadlineOptions, error) {
+	opts := DefaultHeadlineOptions()
+	for _, opt := range strings.Split(s, ",") {
+		opt = strings.TrimSpace(opt)
+		if opt == "" {
+			continue
+		}
+		eq := strings.IndexAny(opt, "= ")
+		if eq < 0 {
+			return opts, pgerror.Newf(pgcode.InvalidParameterValue,
+				"invalid headline parameter: %q", opt)
+		}
+		name := strings.TrimSpace(opt[:eq])
+		value := strings.Trim(strings.TrimSpace(opt[eq+1:]), `"`)
+		var err error
+		switch strings.ToLower(name) {
+		case "startsel":
+			opts.StartSel = value
+		case "stopsel":
+			opts.StopSel = value
+		case "maxwords":
+			opts.MaxWords, err = strconv.Atoi(value)
+		case "minwords":
+			opts.MinWords, err = strconv.Atoi(value)
+		case "shortword":
+			opts.ShortWord, err = strconv.Atoi(value)
+		case "highlightall":
+			switch strings.ToLower(value) {
+			case "1", "on", "true", "t", "y", "yes":
+				opts.HighlightAll = true
+			default:
+				opts.HighlightAll = false
+			}
+		case "maxfragments", "fragmentdelimiter":
+			return opts, pgerror.Newf(pgcode.FeatureNotSupported,
+				"headline parameter %q is not supported", name)
+		default:
+			return opts, pgerror.Newf(pgcode.InvalidParameterValue,
+				"unrecognized headline parameter: %q", name)
+		}
+		if err != nil {
+			return opts, pgerror.Newf(pgcode.InvalidParameterValue,
+				"invalid value for headline parameter %q: %q", name, value)
+		}
+	}
+	if !opts.HighlightAll {
+		if opts.MinWords >= opts.MaxWords {
+			return opts, pgerror.New(pgcode.InvalidParameterValue,
+				"MinWords should be less than MaxWords")
+		}
+		if opts.MinWords <= 0 {
+			return opts, pgerror.New(pgcode.InvalidParameterValue,
+				"MinWords should be positive")
+		}
+		if opts.ShortWord < 0 {
+			return opts, pgerror.New(pgcode.InvalidParameterValue,
+				"ShortWord should be >= 0")
+		}
+	}
+	return opts, nil
+}
+
+// Headline returns an excerpt of the document in which the words matching
+// the query are highlighted. The excerpt is the fragment of the document that
+// contains the most query words while satisfying the query, extended up to
+// MinWords words and truncated to MaxWords words.
+func (c *Config) Headline(doc string, q TSQuery, opts HeadlineOptions) string {
+	words := splitWords(doc)
+	if len(words) == 0 {
+		return doc
+	}
+	// Find the words of the document that match a (non-negated) lexeme of
+	// the query.
+	operands := q.positiveOperands()
+	lexemes := make([]string, len(words))
+	matched := make([]bool, len(words))
+	for i, w := range words {
+		l, ok := c.normalizeWord(w.text)
+		if !ok {
+			continue
+		}
+		lexemes[i] = l
+		for _, op := range operands {
+			if l == op.Lexeme || (op.Prefix && strings.HasPrefix(l, op.Lexeme)) {
+				matched[i] = true
+				break
+			}
+		}
+	}
+
+	start, end := 0, len(words)-1
+	if !opts.HighlightAll {
+		start, end = c.headlineFragment(words, lexemes, matched, q, opts)
+	}
+
+	var buf strings.Builder
+	from := words[start].start
+	if start == 0 {
+		from = 0
+	}
+	for i := start; i <= end; i++ {
+		buf.WriteString(doc[from:words[i].start])
+		if matched[i] {
+			buf.WriteString(opts.StartSel)
+			buf.WriteString(words[i].text)
+			buf.WriteString(opts.StopSel)
+		} else {
+			buf.WriteString(words[i].text)
+		}
+		from = words[i].end
+	}
+	if end == len(words)-1 {
+		buf.WriteString(doc[from:])
+	}
+	return buf.String()
+}
+
+// headlineFragment returns the indexes of the first and last words of the
+// fragment of the document to use as a headline.
+func (c *Config) headlineFragment(
+	words []word, lexemes []string, matched []bool, q TSQuery, opts HeadlineOptions,
+) (start, end int) {
+	bestStart, bestEnd, bestMatches := -1, -1, -1
+	for p := range words {
+		if !matched[p] {
+			continue
+		}
+		// Find the shortest fragment starting at p that satisfies the query.
+		var fragment []Lexeme
+		cover := -1
+		for i := p; i < len(words) && i-p < opts.MaxWords; i++ {
+			if lexemes[i] != "" {
+				pos := i - p + 1
+				if pos > MaxPosition {
+					pos = MaxPosition
+				}
+				fragment = append(fragment, Lexeme{
+					Text: lexemes[i], Positions: []Position{{Pos: uint16(pos)}},
+				})
+			}
+			if matched[i] && Match(makeTSVector(copyLexemes(fragment)), q) {
+				cover = i
+				break
+			}
+		}
+		if cover < 0 {
+			continue
+		}
+		// Extend the fragment to MinWords words, towards the end of the
+		// document if possible and towards the beginning otherwise.
+		s, e := p, cover
+		for e-s+1 < opts.MinWords && e < len(words)-1 {
+			e++
+		}
+		for e-s+1 < opts.MinWords && s > 0 {
+			s--
+		}
+		var matches int
+		for i := s; i <= e; i++ {
+			if matched[i] {
+				matches++
+			}
+		}
+		if matches > bestMatches || (matches == bestMatches && e-s > bestEnd-bestStart) {
+			bestStart, bestEnd, bestMatches = s, e, matches
+		}
+	}
+	if bestStart < 0 {
+		// No fragment satisfies the query, so use the beginning of the
+		// document.
+		end = opts.MinWords - 1
+		if end >= len(words) {
+			end = len(words) - 1
+		}
+		return 0, end
+	}
+	return bestStart, bestEnd
+}
+
+// copyLexemes returns a copy of the given lexemes, which can be passed to
+// makeTSVector without affecting the original slice.
+func copyLexemes(lexemes []Lexeme) []Lexeme {
+	res := make([]Lexeme, len(lexemes))
+	for i := range lexemes {
+		res[i] = Lexeme{Text: lexemes[i].Text, Positions: append([]Position(nil), lexemes[i].Positions...)}
+	}
+	return res
+}
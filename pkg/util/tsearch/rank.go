@@ -0,0 +1,238 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"math"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// RankNormalization is a bit mask that specifies how a rank is normalized
+// by the length of the document. It has the same meaning as the
+// normalization argument of the Postgres ts_rank function.
+type RankNormalization int
+
+const (
+	// RankNormLogLength divides the rank by 1 + the logarithm of the document
+	// length.
+	RankNormLogLength RankNormalization = 1 << iota
+	// RankNormLength divides the rank by the document length.
+	RankNormLength
+	// RankNormExtDist divides the rank by the mean harmonic distance between
+	// extents. It is only used by ts_rank_cd, and is ignored here.
+	RankNormExtDist
+	// RankNormUniq divides the rank by the number of unique words in the
+	// document.
+	RankNormUniq
+	// RankNormLogUniq divides the rank by 1 + the logarithm of the number of
+	// unique words in the document.
+	RankNormLogUniq
+	// RankNormRDivRPlus1 divides the rank by itself + 1.
+	RankNormRDivRPlus1
+)
+
+// DefaultRankWeights are the weights of the D, C, B and A labels used when
+// ranking, in that order.
+var DefaultRankWeights = [4]float64{0.1, 0.2, 0.4, 1.0}
+
+// ValidateRankWeights checks that the weights given to ts_rank are valid.
+func ValidateRankWeights(weights []float64) ([4]float64, error) {
+	var res [4]float64
+	if len(weights) < len(res) {
+		return res, pgerror.New(pgcode.ArraySubscript, "array of weight is too short")
+	}
+	for i := range res {
+		switch {
+		case weights[i] > 1:
+			return res, pgerror.New(pgcode.InvalidParameterValue, "weight out of range")
+		case weights[i] < 0:
+			// Negative weights are replaced by the default ones.
+			res[i] = DefaultRankWeights[i]
+		default:
+			res[i] = weights[i]
+		}
+	}
+	return res, nil
+}
+
+const (
+	// rankLimit is the limit of sum(1/i^2) for i from 1 to infinity, which
+	// bounds the contribution of a single lexeme.
+	rankLimit = 1.64493406685
+	// nullPosition is the position used for lexemes without positions when
+	// computing proximity.
+	nullPosition = MaxPosition
+)
+
+// Rank computes the relevance of a document for a query, using the same
+// algorithm as the Postgres ts_rank function: the rank grows with the number
+// of occurrences of the query lexemes and their weights and, for queries
+// whose top-level operator is & or <->, with the proximity of the lexemes to
+// each other.
+func Rank(weights [4]float64, v TSVector, q TSQuery, norm RankNormalization) float32 {
+	items := q.uniqueLexemes()
+	if len(v) == 0 || len(items) == 0 {
+		return 0
+	}
+	var res float64
+	if op := q.Root.Op; (op == OpAnd || op == OpPhrase) && len(items) > 1 {
+		res = rankAnd(weights, v, items)
+	} else {
+		res = rankOr(weights, v, items)
+	}
+	if res < 0 {
+		res = 1e-20
+	}
+	if norm&RankNormLogLength != 0 {
+		res /= math.Log(float64(v.wordCount())+1) / math.Log(2)
+	}
+	if norm&RankNormLength != 0 {
+		if n := v.wordCount(); n > 0 {
+			res /= float64(n)
+		}
+	}
+	if norm&RankNormUniq != 0 {
+		res /= float64(len(v))
+	}
+	if norm&RankNormLogUniq != 0 {
+		res /= math.Log(float64(len(v))+1) / math.Log(2)
+	}
+	if norm&RankNormRDivRPlus1 != 0 {
+		res /= res + 1
+	}
+	return float32(res)
+}
+
+// wordCount returns the number of words in the document the vector was built
+// from. Lexemes without positions count as one word.
+func (v TSVector) wordCount() int {
+	var n int
+	for _, l := range v {
+		if len(l.Positions) == 0 {
+			n++
+		} else {
+			n += len(l.Positions)
+		}
+	}
+	return n
+}
+
+// uniqueLexemes returns the lexeme operands of the query, without duplicates.
+func (q TSQuery) uniqueLexemes() []*Node {
+	var res []*Node
+	seen := make(map[string]struct{})
+	q.walk(func(n *Node) {
+		if n.Op != OpLexeme {
+			return
+		}
+		if _, ok := seen[n.Lexeme]; ok {
+			return
+		}
+		seen[n.Lexeme] = struct{}{}
+		res = append(res, n)
+	})
+	return res
+}
+
+// operandPositions returns the positions of the lexemes matched by the given
+// operand, or nil if there are none. Lexemes without positions are given a
+// single position with the default weight.
+func (v TSVector) operandPositions(n *Node) []Position {
+	var lexemes []Lexeme
+	if n.Prefix {
+		lexemes = v.findPrefix(n.Lexeme)
+	} else if l := v.find(n.Lexeme); l != nil {
+		lexemes = []Lexeme{*l}
+	}
+	var res []Position
+	for _, l := range lexemes {
+		if len(l.Positions) == 0 {
+			res = append(res, Position{Pos: nullPosition})
+			continue
+		}
+		res = append(res, l.Positions...)
+	}
+	if len(lexemes) > 1 {
+		sort.Slice(res, func(i, j int) bool { return res[i].Pos < res[j].Pos })
+	}
+	return res
+}
+
+// rankOr sums the contributions of the occurrences of every lexeme, giving
+// less weight to each additional occurrence.
+func rankOr(weights [4]float64, v TSVector, items []*Node) float64 {
+	var res float64
+	for _, item := range items {
+		positions := v.operandPositions(item)
+		if len(positions) == 0 {
+			continue
+		}
+		var sum, maxWeight float64
+		maxIdx := 0
+		maxWeight = -1
+		for j, p := range positions {
+			w := weights[p.Weight]
+			sum += w / float64((j+1)*(j+1))
+			if w > maxWeight {
+				maxWeight = w
+				maxIdx = j
+			}
+		}
+		res += (maxWeight + sum - maxWeight/float64((maxIdx+1)*(maxIdx+1))) / rankLimit
+	}
+	return res / float64(len(items))
+}
+
+// rankAnd combines the proximity of every pair of occurrences of different
+// lexemes.
+func rankAnd(weights [4]float64, v TSVector, items []*Node) float64 {
+	positions := make([][]Position, len(items))
+	for i, item := range items {
+		positions[i] = v.operandPositions(item)
+	}
+	res := -1.0
+	for i := range items {
+		for k := 0; k < i; k++ {
+			for _, a := range positions[i] {
+				for _, b := range positions[k] {
+					dist := int(a.Pos) - int(b.Pos)
+					if dist < 0 {
+						dist = -dist
+					}
+					if dist == 0 {
+						if a.Pos != nullPosition && b.Pos != nullPosition {
+							continue
+						}
+						dist = MaxPosition + 1
+					}
+					w := math.Sqrt(weights[a.Weight] * weights[b.Weight] * wordDistance(dist))
+					if res < 0 {
+						res = w
+					} else {
+						res = 1 - (1-res)*(1-w)
+					}
+				}
+			}
+		}
+	}
+	return res
+}
+
+// wordDistance maps the distance between two words to a factor in (0, 1].
+func wordDistance(dist int) float64 {
+	if dist > 100 {
+		return 1e-30
+	}
+	return 1 / (1.005 + 0.05*math.Exp(float64(float32(dist))/1.5-2))
+}
@@ -0,0 +1,394 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import "strings"
+
+// stemEnglish reduces a lowercase English word to its stem using the Porter2
+// ("English") stemming algorithm from the Snowball project, which is the
+// stemmer used by the Postgres english configuration. See
+// https://snowballstem.org/algorithms/english/stemmer.html.
+//
+// Words containing non-ASCII characters are returned unchanged.
+func stemEnglish(w string) string {
+	if len(w) <= 2 || !isASCII(w) {
+		return w
+	}
+	if s, ok := stemExceptions[w]; ok {
+		return s
+	}
+	s := &stemmer{b: []byte(w)}
+	s.prelude()
+	if len(s.b) <= 2 {
+		return s.String()
+	}
+	s.markRegions()
+	s.step0()
+	s.step1a()
+	if _, ok := step1aInvariants[string(s.b)]; ok {
+		return s.String()
+	}
+	s.step1b()
+	s.step1c()
+	s.step2()
+	s.step3()
+	s.step4()
+	s.step5()
+	return s.String()
+}
+
+// stemExceptions are words that are not stemmed by the regular rules.
+var stemExceptions = map[string]string{
+	"skis":   "ski",
+	"skies":  "sky",
+	"dying":  "die",
+	"lying":  "lie",
+	"tying":  "tie",
+	"idly":   "idl",
+	"gently": "gentl",
+	"ugly":   "ugli",
+	"early":  "earli",
+	"only":   "onli",
+	"singly": "singl",
+	"sky":    "sky",
+	"news":   "news",
+	"howe":   "howe",
+	"atlas":  "atlas",
+	"cosmos": "cosmos",
+	"bias":   "bias",
+	"andes":  "andes",
+}
+
+// step1aInvariants are words that are left unchanged after step 1a.
+var step1aInvariants = map[string]struct{}{
+	"inning":  {},
+	"outing":  {},
+	"canning": {},
+	"herring": {},
+	"earring": {},
+	"proceed": {},
+	"exceed":  {},
+	"succeed": {},
+}
+
+// stemmer holds the state of the stemming algorithm. In the word being
+// stemmed, a 'Y' denotes a y that is treated as a consonant.
+type stemmer struct {
+	b      []byte
+	r1, r2 int
+}
+
+func (s *stemmer) String() string {
+	return strings.Replace(string(s.b), "Y", "y", -1)
+}
+
+func isVowel(c byte) bool {
+	switch c {
+	case 'a', 'e', 'i', 'o', 'u', 'y':
+		return true
+	}
+	return false
+}
+
+// prelude removes an initial apostrophe and marks the consonant y's.
+func (s *stemmer) prelude() {
+	if s.b[0] == '\'' {
+		s.b = s.b[1:]
+	}
+	if len(s.b) > 0 && s.b[0] == 'y' {
+		s.b[0] = 'Y'
+	}
+	for i := 1; i < len(s.b); i++ {
+		if s.b[i] == 'y' && isVowel(s.b[i-1]) {
+			s.b[i] = 'Y'
+		}
+	}
+}
+
+// markRegions computes R1 and R2. R1 is the region after the first non-vowel
+// following a vowel, and R2 is the region after the first non-vowel following
+// a vowel in R1.
+func (s *stemmer) markRegions() {
+	s.r1 = len(s.b)
+	for _, prefix := range []string{"gener", "commun", "arsen"} {
+		if strings.HasPrefix(string(s.b), prefix) {
+			s.r1 = len(prefix)
+			break
+		}
+	}
+	if s.r1 == len(s.b) {
+		s.r1 = s.regionAfter(0)
+	}
+	s.r2 = s.regionAfter(s.r1)
+}
+
+func (s *stemmer) regionAfter(start int) int {
+	for i := start + 1; i < len(s.b); i++ {
+		if !isVowel(s.b[i]) && isVowel(s.b[i-1]) {
+			return i + 1
+		}
+	}
+	return len(s.b)
+}
+
+func (s *stemmer) hasSuffix(suffix string) bool {
+	return strings.HasSuffix(string(s.b), suffix)
+}
+
+// longestSuffix returns the longest of the given suffixes that the word ends
+// with, or the empty string.
+func (s *stemmer) longestSuffix(suffixes ...string) string {
+	var res string
+	for _, suffix := range suffixes {
+		if len(suffix) > len(res) && s.hasSuffix(suffix) {
+			res = suffix
+		}
+	}
+	return res
+}
+
+// inR1 and inR2 return true if the given suffix lies in R1 or R2.
+func (s *stemmer) inR1(suffix string) bool {
+	return len(s.b)-len(suffix) >= s.r1
+}
+
+func (s *stemmer) inR2(suffix string) bool {
+	return len(s.b)-len(suffix) >= s.r2
+}
+
+// replace replaces the given suffix, which the word must end with.
+func (s *stemmer) replace(suffix, with string) {
+	s.b = append(s.b[:len(s.b)-len(suffix)], with...)
+}
+
+// containsVowel returns true if b[:end] contains a vowel.
+func (s *stemmer) containsVowel(end int) bool {
+	for i := 0; i < end; i++ {
+		if isVowel(s.b[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// endsInShortSyllable returns true if the word ends in a short syllable:
+// either a non-vowel, a vowel and a non-vowel other than w, x or Y, or a
+// vowel at the beginning of the word followed by a non-vowel.
+func (s *stemmer) endsInShortSyllable() bool {
+	n := len(s.b)
+	if n == 2 {
+		return isVowel(s.b[0]) && !isVowel(s.b[1])
+	}
+	if n < 3 {
+		return false
+	}
+	c := s.b[n-1]
+	return !isVowel(s.b[n-3]) && isVowel(s.b[n-2]) && !isVowel(c) &&
+		c != 'w' && c != 'x' && c != 'Y'
+}
+
+// isShort returns true if the word ends in a short syllable and R1 is empty.
+func (s *stemmer) isShort() bool {
+	return s.r1 >= len(s.b) && s.endsInShortSyllable()
+}
+
+func (s *stemmer) endsInDouble() bool {
+	n := len(s.b)
+	if n < 2 || s.b[n-1] != s.b[n-2] {
+		return false
+	}
+	switch s.b[n-1] {
+	case 'b', 'd', 'f', 'g', 'm', 'n', 'p', 'r', 't':
+		return true
+	}
+	return false
+}
+
+// step0 removes the possessive suffixes.
+func (s *stemmer) step0() {
+	if suffix := s.longestSuffix("'", "'s", "'s'"); suffix != "" {
+		s.replace(suffix, "")
+	}
+}
+
+// step1a handles plurals.
+func (s *stemmer) step1a() {
+	switch suffix := s.longestSuffix("sses", "ied", "ies", "us", "ss", "s"); suffix {
+	case "sses":
+		s.replace(suffix, "ss")
+	case "ied", "ies":
+		if len(s.b) > 4 {
+			s.replace(suffix, "i")
+		} else {
+			s.replace(suffix, "ie")
+		}
+	case "s":
+		// Delete the s if the preceding word part contains a vowel that is not
+		// immediately before the s.
+		if s.containsVowel(len(s.b) - 2) {
+			s.replace(suffix, "")
+		}
+	}
+}
+
+// step1b handles the past tense and gerund suffixes.
+func (s *stemmer) step1b() {
+	switch suffix := s.longestSuffix("eed", "eedly", "ed", "edly", "ing", "ingly"); suffix {
+	case "eed", "eedly":
+		if s.inR1(suffix) {
+			s.replace(suffix, "ee")
+		}
+	case "ed", "edly", "ing", "ingly":
+		if !s.containsVowel(len(s.b) - len(suffix)) {
+			return
+		}
+		s.replace(suffix, "")
+		switch {
+		case s.hasSuffix("at"), s.hasSuffix("bl"), s.hasSuffix("iz"):
+			s.b = append(s.b, 'e')
+		case s.endsInDouble():
+			s.b = s.b[:len(s.b)-1]
+		case s.isShort():
+			s.b = append(s.b, 'e')
+		}
+	}
+}
+
+// step1c replaces a final y by i if it follows a non-vowel that is not the
+// first letter of the word.
+func (s *stemmer) step1c() {
+	n := len(s.b)
+	if n > 2 && (s.b[n-1] == 'y' || s.b[n-1] == 'Y') && !isVowel(s.b[n-2]) {
+		s.b[n-1] = 'i'
+	}
+}
+
+var step2Suffixes = map[string]string{
+	"tional":  "tion",
+	"enci":    "ence",
+	"anci":    "ance",
+	"abli":    "able",
+	"entli":   "ent",
+	"izer":    "ize",
+	"ization": "ize",
+	"ational": "ate",
+	"ation":   "ate",
+	"ator":    "ate",
+	"alism":   "al",
+	"aliti":   "al",
+	"alli":    "al",
+	"fulness": "ful",
+	"ousli":   "ous",
+	"ousness": "ous",
+	"iveness": "ive",
+	"iviti":   "ive",
+	"biliti":  "ble",
+	"bli":     "ble",
+	"ogi":     "og",
+	"fulli":   "ful",
+	"lessli":  "less",
+	"li":      "",
+}
+
+var step2SuffixList = suffixList(step2Suffixes)
+
+func suffixList(m map[string]string) []string {
+	res := make([]string, 0, len(m))
+	for suffix := range m {
+		res = append(res, suffix)
+	}
+	return res
+}
+
+// step2 maps double suffixes to single ones.
+func (s *stemmer) step2() {
+	suffix := s.longestSuffix(step2SuffixList...)
+	if suffix == "" || !s.inR1(suffix) {
+		return
+	}
+	n := len(s.b) - len(suffix)
+	switch suffix {
+	case "ogi":
+		if n == 0 || s.b[n-1] != 'l' {
+			return
+		}
+	case "li":
+		if n == 0 || !strings.ContainsRune("cdeghkmnrt", rune(s.b[n-1])) {
+			return
+		}
+	}
+	s.replace(suffix, step2Suffixes[suffix])
+}
+
+var step3Suffixes = map[string]string{
+	"tional":  "tion",
+	"ational": "ate",
+	"alize":   "al",
+	"icate":   "ic",
+	"iciti":   "ic",
+	"ical":    "ic",
+	"ful":     "",
+	"ness":    "",
+	"ative":   "",
+}
+
+var step3SuffixList = suffixList(step3Suffixes)
+
+// step3 removes or simplifies some derivational suffixes.
+func (s *stemmer) step3() {
+	suffix := s.longestSuffix(step3SuffixList...)
+	if suffix == "" || !s.inR1(suffix) {
+		return
+	}
+	if suffix == "ative" && !s.inR2(suffix) {
+		return
+	}
+	s.replace(suffix, step3Suffixes[suffix])
+}
+
+var step4SuffixList = []string{
+	"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement", "ment",
+	"ent", "ism", "ate", "iti", "ous", "ive", "ize", "ion",
+}
+
+// step4 removes the remaining derivational suffixes in R2.
+func (s *stemmer) step4() {
+	suffix := s.longestSuffix(step4SuffixList...)
+	if suffix == "" || !s.inR2(suffix) {
+		return
+	}
+	if suffix == "ion" {
+		n := len(s.b) - len(suffix)
+		if n == 0 || (s.b[n-1] != 's' && s.b[n-1] != 't') {
+			return
+		}
+	}
+	s.replace(suffix, "")
+}
+
+// step5 removes a final e or a doubled l.
+func (s *stemmer) step5() {
+	switch {
+	case s.hasSuffix("e"):
+		if s.inR2("e") {
+			s.replace("e", "")
+		} else if s.inR1("e") {
+			s.b = s.b[:len(s.b)-1]
+			if s.endsInShortSyllable() {
+				s.b = append(s.b, 'e')
+			}
+		}
+	case s.hasSuffix("ll"):
+		if s.inR2("l") {
+			s.replace("l", "")
+		}
+	}
+}
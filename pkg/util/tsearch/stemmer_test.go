@@ -0,0 +1,109 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import "testing"
+
+func TestStemEnglish(t *testing.T) {
+	// These are taken from the sample vocabulary of the Snowball English
+	// stemmer.
+	testCases := map[string]string{
+		"a":             "a",
+		"at":            "at",
+		"cats":          "cat",
+		"caresses":      "caress",
+		"ponies":        "poni",
+		"ties":          "tie",
+		"cries":         "cri",
+		"gas":           "gas",
+		"gaps":          "gap",
+		"kiwis":         "kiwi",
+		"consign":       "consign",
+		"consigned":     "consign",
+		"consigning":    "consign",
+		"consignment":   "consign",
+		"consist":       "consist",
+		"consisted":     "consist",
+		"consistency":   "consist",
+		"consistent":    "consist",
+		"consistently":  "consist",
+		"consisting":    "consist",
+		"consists":      "consist",
+		"consolation":   "consol",
+		"consolations":  "consol",
+		"consolatory":   "consolatori",
+		"console":       "consol",
+		"consoled":      "consol",
+		"consoles":      "consol",
+		"consolidate":   "consolid",
+		"consolidated":  "consolid",
+		"consolidating": "consolid",
+		"consoling":     "consol",
+		"consolingly":   "consol",
+		"knack":         "knack",
+		"knackeries":    "knackeri",
+		"knaves":        "knave",
+		"knavish":       "knavish",
+		"kneaded":       "knead",
+		"kneel":         "kneel",
+		"knelt":         "knelt",
+		"knew":          "knew",
+		"knife":         "knife",
+		"knight":        "knight",
+		"knightly":      "knight",
+		"knights":       "knight",
+		"knitted":       "knit",
+		"knives":        "knive",
+		"knock":         "knock",
+		"knocked":       "knock",
+		"knocking":      "knock",
+		"running":       "run",
+		"jumped":        "jump",
+		"hoped":         "hope",
+		"hopping":       "hop",
+		"happy":         "happi",
+		"cry":           "cri",
+		"say":           "say",
+		"generously":    "generous",
+		"generation":    "generat",
+		"communism":     "communism",
+		"skies":         "sky",
+		"dying":         "die",
+		"news":          "news",
+		"inning":        "inning",
+		"innings":       "inning",
+		"proceed":       "proceed",
+		"succeeded":     "succeed",
+		"agreed":        "agre",
+		"feed":          "feed",
+		"relational":    "relat",
+		"conditional":   "condit",
+		"rational":      "ration",
+		"valenci":       "valenc",
+		"digitizer":     "digit",
+		"operator":      "oper",
+		"radically":     "radic",
+		"hopefulness":   "hope",
+		"goodness":      "good",
+		"electrical":    "electr",
+		"adjustable":    "adjust",
+		"controll":      "control",
+		"rolling":       "roll",
+		"yelling":       "yell",
+		"youth":         "youth",
+		"café":          "café",
+	}
+	for word, expected := range testCases {
+		if s := stemEnglish(word); s != expected {
+			t.Errorf("%s: expected %s, got %s", word, expected, s)
+		}
+	}
+}
@@ -0,0 +1,394 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// MaxPhraseDistance is the largest distance allowed in a phrase operator.
+const MaxPhraseDistance = 16384
+
+// Operator is the kind of a tsquery node.
+type Operator uint8
+
+const (
+	// OpLexeme is a leaf node that matches a single lexeme.
+	OpLexeme Operator = iota
+	// OpNot matches documents that don't match its operand.
+	OpNot
+	// OpPhrase matches documents in which the right operand follows the left
+	// operand at a given distance.
+	OpPhrase
+	// OpAnd matches documents that match both operands.
+	OpAnd
+	// OpOr matches documents that match either operand.
+	OpOr
+)
+
+// precedence returns the binding strength of the operator; higher values
+// bind more tightly.
+func (o Operator) precedence() int {
+	switch o {
+	case OpOr:
+		return 1
+	case OpAnd:
+		return 2
+	case OpPhrase:
+		return 3
+	case OpNot:
+		return 4
+	}
+	return 5
+}
+
+// WeightMask is a set of weights. A lexeme operand with a non-empty mask only
+// matches positions having one of the weights in the mask.
+type WeightMask uint8
+
+// Contains returns true if the given weight is in the mask.
+func (m WeightMask) Contains(w Weight) bool {
+	return m&(1<<w) != 0
+}
+
+// Node is a node of a tsquery tree.
+type Node struct {
+	Op Operator
+
+	// Lexeme, Prefix and Weights are set for OpLexeme nodes.
+	Lexeme  string
+	Prefix  bool
+	Weights WeightMask
+
+	// Distance is set for OpPhrase nodes.
+	Distance int
+
+	// Left is set for all operators; Right is set for binary operators.
+	Left  *Node
+	Right *Node
+}
+
+// TSQuery is a full-text search query. An empty query, e.g. one produced by
+// to_tsquery from only stop words, has a nil root and matches nothing.
+type TSQuery struct {
+	Root *Node
+}
+
+// String returns the Postgres text representation of the query, e.g.
+// 'fat' & ( 'rat' | 'cat' ).
+func (q TSQuery) String() string {
+	if q.Root == nil {
+		return ""
+	}
+	var buf strings.Builder
+	q.Root.format(&buf)
+	return buf.String()
+}
+
+func (n *Node) format(buf *strings.Builder) {
+	switch n.Op {
+	case OpLexeme:
+		writeQuotedLexeme(buf, n.Lexeme)
+		if n.Prefix || n.Weights != 0 {
+			buf.WriteByte(':')
+			if n.Prefix {
+				buf.WriteByte('*')
+			}
+			for w := WeightA; ; w-- {
+				if n.Weights.Contains(w) {
+					buf.WriteString(w.String())
+				}
+				if w == WeightD {
+					break
+				}
+			}
+		}
+	case OpNot:
+		buf.WriteByte('!')
+		n.formatOperand(buf, n.Left, false /* right */)
+	default:
+		n.formatOperand(buf, n.Left, false /* right */)
+		switch n.Op {
+		case OpAnd:
+			buf.WriteString(" & ")
+		case OpOr:
+			buf.WriteString(" | ")
+		case OpPhrase:
+			if n.Distance == 1 {
+				buf.WriteString(" <-> ")
+			} else {
+				buf.WriteString(" <")
+				buf.WriteString(strconv.Itoa(n.Distance))
+				buf.WriteString("> ")
+			}
+		}
+		n.formatOperand(buf, n.Right, true /* right */)
+	}
+}
+
+// formatOperand formats an operand of n, adding parentheses when needed to
+// preserve the structure of the tree.
+func (n *Node) formatOperand(buf *strings.Builder, operand *Node, right bool) {
+	parens := operand.Op.precedence() < n.Op.precedence() ||
+		(right && operand.Op == n.Op && n.Op == OpPhrase)
+	if parens {
+		buf.WriteString("( ")
+	}
+	operand.format(buf)
+	if parens {
+		buf.WriteString(" )")
+	}
+}
+
+// Compare compares two queries, returning -1, 0 or 1. The ordering is only
+// meant to be consistent; it has no meaning beyond that.
+func (q TSQuery) Compare(other TSQuery) int {
+	return strings.Compare(q.String(), other.String())
+}
+
+// Size returns an estimate of the memory used by the query, in bytes.
+func (q TSQuery) Size() uintptr {
+	var sz uintptr
+	q.walk(func(n *Node) {
+		sz += nodeSize + uintptr(len(n.Lexeme))
+	})
+	return sz
+}
+
+const nodeSize = 56
+
+// walk calls fn on every node of the query, in prefix order.
+func (q TSQuery) walk(fn func(n *Node)) {
+	var visit func(n *Node)
+	visit = func(n *Node) {
+		if n == nil {
+			return
+		}
+		fn(n)
+		visit(n.Left)
+		visit(n.Right)
+	}
+	visit(q.Root)
+}
+
+// ParseTSQuery parses the Postgres text representation of a tsquery. Unlike
+// to_tsquery, no normalization is performed on the lexemes.
+func ParseTSQuery(s string) (TSQuery, error) {
+	return parseTSQuery(s, nil /* normalize */)
+}
+
+// parseTSQuery parses a tsquery. If normalize is not nil, it is called on
+// the text of every operand and returns the lexemes the operand stands for.
+// Operands without any lexemes (e.g. stop words) are removed from the query,
+// and operands with more than one lexeme are replaced by a phrase.
+func parseTSQuery(s string, normalize func(string) []string) (TSQuery, error) {
+	p := tsqueryParser{lexer: lexer{s: s}, normalize: normalize}
+	p.skipSpace()
+	if p.done() {
+		return TSQuery{}, nil
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return TSQuery{}, err
+	}
+	p.skipSpace()
+	if !p.done() {
+		return TSQuery{}, p.syntaxError()
+	}
+	return TSQuery{Root: root}, nil
+}
+
+type tsqueryParser struct {
+	lexer
+	normalize func(string) []string
+}
+
+func (p *tsqueryParser) syntaxError() error {
+	return pgerror.Newf(pgcode.Syntax, "syntax error in tsquery: %q", p.s)
+}
+
+// next skips whitespace and returns true if the input continues with the
+// given operator, consuming it.
+func (p *tsqueryParser) next(op string) bool {
+	p.skipSpace()
+	if strings.HasPrefix(p.s[p.pos:], op) {
+		p.pos += len(op)
+		return true
+	}
+	return false
+}
+
+// parseOr parses a sequence of operands separated by |.
+func (p *tsqueryParser) parseOr() (*Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.next("|") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = combine(OpOr, left, right, 0 /* distance */)
+	}
+	return left, nil
+}
+
+// parseAnd parses a sequence of operands separated by &.
+func (p *tsqueryParser) parseAnd() (*Node, error) {
+	left, err := p.parsePhrase()
+	if err != nil {
+		return nil, err
+	}
+	for p.next("&") {
+		right, err := p.parsePhrase()
+		if err != nil {
+			return nil, err
+		}
+		left = combine(OpAnd, left, right, 0 /* distance */)
+	}
+	return left, nil
+}
+
+// parsePhrase parses a sequence of operands separated by <-> or <N>.
+func (p *tsqueryParser) parsePhrase() (*Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		distance, ok, err := p.phraseOperator()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = combine(OpPhrase, left, right, distance)
+	}
+}
+
+// phraseOperator consumes a <-> or <N> operator and returns its distance.
+func (p *tsqueryParser) phraseOperator() (distance int, ok bool, _ error) {
+	if p.next("<->") {
+		return 1, true, nil
+	}
+	if !p.next("<") {
+		return 0, false, nil
+	}
+	start := p.pos
+	for !p.done() && p.peek() >= '0' && p.peek() <= '9' {
+		p.pos++
+	}
+	if start == p.pos || p.done() || p.peek() != '>' {
+		return 0, false, p.syntaxError()
+	}
+	distance, err := strconv.Atoi(p.s[start:p.pos])
+	if err != nil || distance > MaxPhraseDistance {
+		return 0, false, pgerror.Newf(pgcode.InvalidParameterValue,
+			"distance in phrase operator should not be greater than %d", MaxPhraseDistance)
+	}
+	p.pos++
+	return distance, true, nil
+}
+
+// parseUnary parses a negation, a parenthesized expression or an operand.
+func (p *tsqueryParser) parseUnary() (*Node, error) {
+	p.skipSpace()
+	if p.done() {
+		return nil, p.syntaxError()
+	}
+	switch p.peek() {
+	case '!':
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil || operand == nil {
+			return nil, err
+		}
+		return &Node{Op: OpNot, Left: operand}, nil
+	case '(':
+		p.pos++
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.next(")") {
+			return nil, p.syntaxError()
+		}
+		return n, nil
+	}
+	return p.parseOperand()
+}
+
+// tsqueryDelimiters returns true for the characters that terminate an
+// unquoted operand in a tsquery.
+func tsqueryDelimiters(c byte) bool {
+	switch c {
+	case ':', '&', '|', '!', '(', ')', '<':
+		return true
+	}
+	return isSpace(c)
+}
+
+// parseOperand parses a lexeme operand along with its optional prefix and
+// weight annotations, e.g. 'super':*AB.
+func (p *tsqueryParser) parseOperand() (*Node, error) {
+	text, err := p.lexeme(tsqueryDelimiters)
+	if err != nil {
+		return nil, p.syntaxError()
+	}
+	var prefix bool
+	var weights WeightMask
+	if !p.done() && p.peek() == ':' {
+		p.pos++
+		for !p.done() {
+			if p.peek() == '*' {
+				prefix = true
+			} else if w, ok := parseWeight(p.peek()); ok {
+				weights |= 1 << w
+			} else {
+				break
+			}
+			p.pos++
+		}
+	}
+	lexemes := []string{text}
+	if p.normalize != nil {
+		lexemes = p.normalize(text)
+	}
+	var res *Node
+	for _, l := range lexemes {
+		n := &Node{Op: OpLexeme, Lexeme: l, Prefix: prefix, Weights: weights}
+		res = combine(OpPhrase, res, n, 1 /* distance */)
+	}
+	return res, nil
+}
+
+// combine builds a binary node from two operands, either of which may be nil
+// if it was removed during normalization. In that case the other operand is
+// returned.
+func combine(op Operator, left, right *Node, distance int) *Node {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+	return &Node{Op: op, Left: left, Right: right, Distance: distance}
+}
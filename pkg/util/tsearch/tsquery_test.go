@@ -0,0 +1,109 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTSQuery(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+		err      string
+	}{
+		{``, ``, ``},
+		{`cat`, `'cat'`, ``},
+		{`fat & rat`, `'fat' & 'rat'`, ``},
+		{`fat & (rat | cat)`, `'fat' & ( 'rat' | 'cat' )`, ``},
+		{`fat | rat & cat`, `'fat' | 'rat' & 'cat'`, ``},
+		{`(fat | rat) & cat`, `( 'fat' | 'rat' ) & 'cat'`, ``},
+		{`fat & rat & !cat`, `'fat' & 'rat' & !'cat'`, ``},
+		{`!(fat | rat)`, `!( 'fat' | 'rat' )`, ``},
+		{`!!cat`, `!!'cat'`, ``},
+		{`fat <-> rat`, `'fat' <-> 'rat'`, ``},
+		{`fat <2> rat <-> cat`, `'fat' <2> 'rat' <-> 'cat'`, ``},
+		{`fat <-> (rat <-> cat)`, `'fat' <-> ( 'rat' <-> 'cat' )`, ``},
+		{`fat <-> (rat & cat)`, `'fat' <-> ( 'rat' & 'cat' )`, ``},
+		{`fat <0> rat`, `'fat' <0> 'rat'`, ``},
+		{`super:*`, `'super':*`, ``},
+		{`super:*ab & cat:C`, `'super':*AB & 'cat':C`, ``},
+		{`'fat rat' & 'don''t'`, `'fat rat' & 'don''t'`, ``},
+		{`fat &`, ``, `syntax error in tsquery`},
+		{`fat rat`, ``, `syntax error in tsquery`},
+		{`(fat`, ``, `syntax error in tsquery`},
+		{`fat)`, ``, `syntax error in tsquery`},
+		{`fat <x> rat`, ``, `syntax error in tsquery`},
+		{`fat <99999> rat`, ``, `distance in phrase operator should not be greater than 16384`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			q, err := ParseTSQuery(tc.input)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected error %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := q.String(); s != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, s)
+			}
+			// The output must round-trip.
+			q2, err := ParseTSQuery(q.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if q.Compare(q2) != 0 {
+				t.Fatalf("%s did not round-trip, got %s", q, q2)
+			}
+		})
+	}
+}
+
+func TestToTSQuery(t *testing.T) {
+	english, err := GetConfig("english")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{`Cats & Rats`, `'cat' & 'rat'`},
+		{`the & cats`, `'cat'`},
+		{`the | a`, ``},
+		{`!the & cats`, `'cat'`},
+		{`running:*B`, `'run':*B`},
+		{`fat-rats | mice`, `'fat' <-> 'rat' | 'mice'`},
+		{`jumping <2> dogs`, `'jump' <2> 'dog'`},
+	}
+	for _, tc := range testCases {
+		q, err := english.ToTSQuery(tc.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := q.String(); s != tc.expected {
+			t.Errorf("%q: expected %s, got %s", tc.input, tc.expected, s)
+		}
+	}
+
+	if s, e := english.PlainToTSQuery("The Fat & Rats!").String(), `'fat' & 'rat'`; s != e {
+		t.Errorf("expected %s, got %s", e, s)
+	}
+	if s, e := english.PhraseToTSQuery("The fat rats ate the cheese").String(),
+		`'fat' <-> 'rat' <-> 'ate' <2> 'chees'`; s != e {
+		t.Errorf("expected %s, got %s", e, s)
+	}
+}
@@ -0,0 +1,439 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package tsearch implements the tsvector and tsquery data types and the
+// full-text search functions that operate on them.
+package tsearch
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+const (
+	// MaxPosition is the largest position that can be stored in a tsvector.
+	// Larger positions are silently clamped to this value, as in Postgres.
+	MaxPosition = 16383
+
+	// MaxPositionsPerLexeme is the maximum number of positions stored for a
+	// single lexeme. Additional positions are silently dropped.
+	MaxPositionsPerLexeme = 256
+
+	// MaxLexemeLength is the maximum length in bytes of a single lexeme.
+	MaxLexemeLength = 2047
+)
+
+// Weight is the weight label attached to a lexeme position. Weights are used
+// to mark lexemes coming from different parts of a document (e.g. the title
+// versus the body), and to influence ranking.
+type Weight uint8
+
+// Weights, from the lowest to the highest. D is the default weight and is not
+// displayed.
+const (
+	WeightD Weight = iota
+	WeightC
+	WeightB
+	WeightA
+)
+
+// String returns the label of the weight.
+func (w Weight) String() string {
+	return string("DCBA"[w])
+}
+
+// ParseWeight parses a weight label, e.g. "A".
+func ParseWeight(s string) (Weight, error) {
+	if len(s) == 1 {
+		if w, ok := parseWeight(s[0]); ok {
+			return w, nil
+		}
+	}
+	return 0, pgerror.Newf(pgcode.InvalidParameterValue, "unrecognized weight: %q", s)
+}
+
+func parseWeight(c byte) (Weight, bool) {
+	switch c {
+	case 'a', 'A':
+		return WeightA, true
+	case 'b', 'B':
+		return WeightB, true
+	case 'c', 'C':
+		return WeightC, true
+	case 'd', 'D':
+		return WeightD, true
+	}
+	return 0, false
+}
+
+// Position is the position of a lexeme in a document, along with its weight.
+type Position struct {
+	Pos    uint16
+	Weight Weight
+}
+
+// Lexeme is a normalized word and the positions at which it occurs in a
+// document. A lexeme may have no positions, e.g. if it was created from a
+// literal without position information.
+type Lexeme struct {
+	Text      string
+	Positions []Position
+}
+
+// TSVector is a full-text search document. It is a list of distinct lexemes,
+// sorted by their text, each with a sorted list of distinct positions.
+type TSVector []Lexeme
+
+// makeTSVector sorts and de-duplicates the given lexemes, merging the
+// positions of duplicate lexemes.
+func makeTSVector(lexemes []Lexeme) TSVector {
+	if len(lexemes) == 0 {
+		return TSVector{}
+	}
+	sort.SliceStable(lexemes, func(i, j int) bool {
+		return lexemes[i].Text < lexemes[j].Text
+	})
+	res := lexemes[:1]
+	for _, l := range lexemes[1:] {
+		last := &res[len(res)-1]
+		if l.Text == last.Text {
+			last.Positions = append(last.Positions, l.Positions...)
+			continue
+		}
+		res = append(res, l)
+	}
+	for i := range res {
+		res[i].Positions = normalizePositions(res[i].Positions)
+	}
+	return TSVector(res)
+}
+
+// normalizePositions sorts the positions and removes duplicates. When the
+// same position occurs more than once, the highest weight is kept.
+func normalizePositions(positions []Position) []Position {
+	if len(positions) == 0 {
+		return nil
+	}
+	sort.SliceStable(positions, func(i, j int) bool {
+		return positions[i].Pos < positions[j].Pos
+	})
+	res := positions[:1]
+	for _, p := range positions[1:] {
+		last := &res[len(res)-1]
+		if p.Pos == last.Pos {
+			if p.Weight > last.Weight {
+				last.Weight = p.Weight
+			}
+			continue
+		}
+		res = append(res, p)
+	}
+	if len(res) > MaxPositionsPerLexeme {
+		res = res[:MaxPositionsPerLexeme]
+	}
+	return res
+}
+
+// find returns the lexeme with the given text, or nil if there is none.
+func (v TSVector) find(text string) *Lexeme {
+	i := sort.Search(len(v), func(i int) bool { return v[i].Text >= text })
+	if i < len(v) && v[i].Text == text {
+		return &v[i]
+	}
+	return nil
+}
+
+// findPrefix returns all the lexemes that start with the given prefix.
+func (v TSVector) findPrefix(prefix string) []Lexeme {
+	i := sort.Search(len(v), func(i int) bool { return v[i].Text >= prefix })
+	j := i
+	for j < len(v) && strings.HasPrefix(v[j].Text, prefix) {
+		j++
+	}
+	return v[i:j]
+}
+
+// Length returns the number of lexemes in the vector.
+func (v TSVector) Length() int {
+	return len(v)
+}
+
+// Strip returns a copy of the vector with all position information removed.
+func (v TSVector) Strip() TSVector {
+	res := make(TSVector, len(v))
+	for i := range v {
+		res[i] = Lexeme{Text: v[i].Text}
+	}
+	return res
+}
+
+// SetWeight returns a copy of the vector in which all positions have the
+// given weight.
+func (v TSVector) SetWeight(w Weight) TSVector {
+	res := make(TSVector, len(v))
+	for i := range v {
+		res[i].Text = v[i].Text
+		if len(v[i].Positions) > 0 {
+			res[i].Positions = make([]Position, len(v[i].Positions))
+			for j, p := range v[i].Positions {
+				res[i].Positions[j] = Position{Pos: p.Pos, Weight: w}
+			}
+		}
+	}
+	return res
+}
+
+// Concat returns the concatenation of two vectors. The positions of the
+// second vector are shifted by the largest position of the first one, as in
+// Postgres.
+func (v TSVector) Concat(other TSVector) TSVector {
+	var maxPos uint16
+	for _, l := range v {
+		for _, p := range l.Positions {
+			if p.Pos > maxPos {
+				maxPos = p.Pos
+			}
+		}
+	}
+	lexemes := make([]Lexeme, 0, len(v)+len(other))
+	for _, l := range v {
+		lexemes = append(lexemes, Lexeme{Text: l.Text, Positions: append([]Position(nil), l.Positions...)})
+	}
+	for _, l := range other {
+		n := Lexeme{Text: l.Text}
+		for _, p := range l.Positions {
+			pos := int(p.Pos) + int(maxPos)
+			if pos > MaxPosition {
+				pos = MaxPosition
+			}
+			n.Positions = append(n.Positions, Position{Pos: uint16(pos), Weight: p.Weight})
+		}
+		lexemes = append(lexemes, n)
+	}
+	return makeTSVector(lexemes)
+}
+
+// Compare compares two vectors, returning -1, 0 or 1. The ordering is only
+// meant to be consistent; it has no meaning beyond that.
+func (v TSVector) Compare(other TSVector) int {
+	return strings.Compare(v.String(), other.String())
+}
+
+// Size returns an estimate of the memory used by the vector, in bytes.
+func (v TSVector) Size() uintptr {
+	sz := uintptr(len(v)) * uintptr(lexemeOverhead)
+	for _, l := range v {
+		sz += uintptr(len(l.Text)) + uintptr(len(l.Positions))*positionSize
+	}
+	return sz
+}
+
+const (
+	lexemeOverhead = 40
+	positionSize   = 4
+)
+
+// String returns the Postgres text representation of the vector, e.g.
+// 'cat':3 'fat':2,4A.
+func (v TSVector) String() string {
+	var buf strings.Builder
+	for i, l := range v {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		writeQuotedLexeme(&buf, l.Text)
+		for j, p := range l.Positions {
+			if j == 0 {
+				buf.WriteByte(':')
+			} else {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(strconv.Itoa(int(p.Pos)))
+			if p.Weight != WeightD {
+				buf.WriteString(p.Weight.String())
+			}
+		}
+	}
+	return buf.String()
+}
+
+// writeQuotedLexeme writes the lexeme surrounded by single quotes, doubling
+// any embedded quotes and backslashes.
+func writeQuotedLexeme(buf *strings.Builder, s string) {
+	buf.WriteByte('\'')
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\'', '\\':
+			buf.WriteByte(s[i])
+		}
+		buf.WriteByte(s[i])
+	}
+	buf.WriteByte('\'')
+}
+
+// ParseTSVector parses the Postgres text representation of a tsvector. Unlike
+// to_tsvector, no normalization is performed on the lexemes.
+func ParseTSVector(s string) (TSVector, error) {
+	var lexemes []Lexeme
+	l := lexer{s: s}
+	for {
+		l.skipSpace()
+		if l.done() {
+			break
+		}
+		text, err := l.lexeme(tsvectorDelimiters)
+		if err != nil {
+			return nil, makeTSVectorSyntaxError(s)
+		}
+		lexeme := Lexeme{Text: text}
+		if !l.done() && l.peek() == ':' {
+			l.pos++
+			if lexeme.Positions, err = l.positions(); err != nil {
+				return nil, err
+			}
+		}
+		lexemes = append(lexemes, lexeme)
+	}
+	return makeTSVector(lexemes), nil
+}
+
+// tsvectorDelimiters returns true for the characters that terminate an
+// unquoted lexeme in a tsvector.
+func tsvectorDelimiters(c byte) bool {
+	return c == ':' || isSpace(c)
+}
+
+func makeTSVectorSyntaxError(s string) error {
+	return pgerror.Newf(pgcode.Syntax, "syntax error in tsvector: %q", s)
+}
+
+// lexer contains helpers shared by the tsvector and tsquery parsers.
+type lexer struct {
+	s   string
+	pos int
+}
+
+func (l *lexer) done() bool {
+	return l.pos >= len(l.s)
+}
+
+func (l *lexer) peek() byte {
+	return l.s[l.pos]
+}
+
+func (l *lexer) skipSpace() {
+	for !l.done() && isSpace(l.peek()) {
+		l.pos++
+	}
+}
+
+func isSpace(c byte) bool {
+	return c < 128 && unicode.IsSpace(rune(c))
+}
+
+// lexeme reads a quoted or unquoted lexeme. An unquoted lexeme ends at the
+// first unescaped character for which isDelim returns true. Backslash
+// escapes the following character in both forms, and a doubled quote
+// represents a single quote inside a quoted lexeme.
+func (l *lexer) lexeme(isDelim func(c byte) bool) (string, error) {
+	var buf strings.Builder
+	if l.peek() == '\'' {
+		l.pos++
+		for {
+			if l.done() {
+				return "", errUnterminatedLexeme
+			}
+			c := l.peek()
+			l.pos++
+			switch {
+			case c == '\\':
+				if l.done() {
+					return "", errUnterminatedLexeme
+				}
+				buf.WriteByte(l.peek())
+				l.pos++
+			case c == '\'' && !l.done() && l.peek() == '\'':
+				buf.WriteByte('\'')
+				l.pos++
+			case c == '\'':
+				if buf.Len() == 0 {
+					return "", errEmptyLexeme
+				}
+				return buf.String(), nil
+			default:
+				buf.WriteByte(c)
+			}
+		}
+	}
+	for !l.done() && !isDelim(l.peek()) {
+		c := l.peek()
+		l.pos++
+		if c == '\\' {
+			if l.done() {
+				return "", errUnterminatedLexeme
+			}
+			c = l.peek()
+			l.pos++
+		}
+		buf.WriteByte(c)
+	}
+	if buf.Len() == 0 {
+		return "", errEmptyLexeme
+	}
+	return buf.String(), nil
+}
+
+var (
+	errUnterminatedLexeme = pgerror.New(pgcode.Syntax, "unterminated lexeme")
+	errEmptyLexeme        = pgerror.New(pgcode.Syntax, "empty lexeme")
+)
+
+// positions reads a comma-separated list of positions, each optionally
+// followed by a weight label, e.g. 1,3A,5.
+func (l *lexer) positions() ([]Position, error) {
+	var res []Position
+	for {
+		start := l.pos
+		for !l.done() && l.peek() >= '0' && l.peek() <= '9' {
+			l.pos++
+		}
+		if start == l.pos {
+			return nil, makeTSVectorSyntaxError(l.s)
+		}
+		n, err := strconv.Atoi(l.s[start:l.pos])
+		if err != nil || n > MaxPosition {
+			n = MaxPosition
+		}
+		if n == 0 {
+			return nil, pgerror.Newf(pgcode.Syntax, "wrong position info in tsvector: %q", l.s)
+		}
+		p := Position{Pos: uint16(n)}
+		if !l.done() {
+			if w, ok := parseWeight(l.peek()); ok {
+				p.Weight = w
+				l.pos++
+			}
+		}
+		res = append(res, p)
+		if l.done() || l.peek() != ',' {
+			break
+		}
+		l.pos++
+	}
+	if !l.done() && !isSpace(l.peek()) {
+		return nil, makeTSVectorSyntaxError(l.s)
+	}
+	return res, nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTSVector(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+		err      string
+	}{
+		{``, ``, ``},
+		{`a fat cat`, `'a' 'cat' 'fat'`, ``},
+		{`  cat   cat  `, `'cat'`, ``},
+		{`a:1 fat:2 cat:3`, `'a':1 'cat':3 'fat':2`, ``},
+		{`cat:3,1,2 fat:2B,4A`, `'cat':1,2,3 'fat':2B,4A`, ``},
+		{`cat:1 cat:2A`, `'cat':1,2A`, ``},
+		{`cat:1,1A,1`, `'cat':1A`, ``},
+		{`cat:1d`, `'cat':1`, ``},
+		{`cat:99999`, `'cat':16383`, ``},
+		{`'fat rat' 'don''t' 'a\'b' a\ b`, `'a b' 'a''b' 'don''t' 'fat rat'`, ``},
+		{`'back\\slash'`, `'back\\slash'`, ``},
+		{`'unterminated`, ``, `syntax error in tsvector`},
+		{`''`, ``, `syntax error in tsvector`},
+		{`cat:`, ``, `syntax error in tsvector`},
+		{`cat:1,`, ``, `syntax error in tsvector`},
+		{`cat:x`, ``, `syntax error in tsvector`},
+		{`cat:1x`, ``, `syntax error in tsvector`},
+		{`cat:0`, ``, `wrong position info in tsvector`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			v, err := ParseTSVector(tc.input)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected error %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := v.String(); s != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, s)
+			}
+			// The output must round-trip.
+			v2, err := ParseTSVector(v.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v.Compare(v2) != 0 {
+				t.Fatalf("%s did not round-trip, got %s", v, v2)
+			}
+		})
+	}
+}
+
+func TestTSVectorOperations(t *testing.T) {
+	a, err := ParseTSVector(`fat:2,4 cat:3 rat:5A`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ParseTSVector(`fat:1 zebra:2B`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, e := a.Strip().String(), `'cat' 'fat' 'rat'`; s != e {
+		t.Errorf("expected %s, got %s", e, s)
+	}
+	if s, e := a.SetWeight(WeightB).String(), `'cat':3B 'fat':2B,4B 'rat':5B`; s != e {
+		t.Errorf("expected %s, got %s", e, s)
+	}
+	if s, e := a.Concat(b).String(), `'cat':3 'fat':2,4,6 'rat':5A 'zebra':7B`; s != e {
+		t.Errorf("expected %s, got %s", e, s)
+	}
+	if n := a.Length(); n != 3 {
+		t.Errorf("expected length 3, got %d", n)
+	}
+}
+
+func TestToTSVector(t *testing.T) {
+	testCases := []struct {
+		config   string
+		doc      string
+		expected string
+	}{
+		{"simple", "", ""},
+		{"simple", "The Fat Rats", `'fat':2 'rats':3 'the':1`},
+		{"english", "The Fat Rats", `'fat':2 'rat':3`},
+		{"english", "a fat  cat sat on a mat - it ate a fat rats",
+			`'ate':9 'cat':3 'fat':2,11 'mat':7 'rat':12 'sat':4`},
+		{"pg_catalog.english", "Running, jumped; swimming!", `'jump':2 'run':1 'swim':3`},
+		{"english", "Ünïcödé words", `'word':2 'ünïcödé':1`},
+	}
+	for _, tc := range testCases {
+		c, err := GetConfig(tc.config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := c.ToTSVector(tc.doc).String(); s != tc.expected {
+			t.Errorf("%s: %q: expected %s, got %s", tc.config, tc.doc, tc.expected, s)
+		}
+	}
+
+	if _, err := GetConfig("klingon"); err == nil ||
+		!strings.Contains(err.Error(), `text search configuration "klingon" does not exist`) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
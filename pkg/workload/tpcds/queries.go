@@ -12,7 +12,9 @@ package tpcds
 
 const numQueries = 99
 
-var queriesByNumber = map[int]string{
+// QueriesByNumber maps the numbers of the TPC-DS queries that are supported by
+// the workload to the text of the queries.
+var QueriesByNumber = map[int]string{
 	1:  query1,
 	2:  query2,
 	3:  query3,
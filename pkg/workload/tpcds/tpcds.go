@@ -100,7 +100,7 @@ func (w *tpcds) Hooks() workload.Hooks {
 					if err != nil {
 						return err
 					}
-					if _, ok := queriesByNumber[queryNum]; !ok {
+					if _, ok := QueriesByNumber[queryNum]; !ok {
 						return errors.Errorf(`unknown query: %s (probably, the query needs modifications, `+
 							`so it is disabled for now)`, queryName)
 					}
@@ -294,7 +294,7 @@ func (w *worker) run(ctx context.Context) error {
 	queryNum := w.config.selectedQueries[w.ops%len(w.config.selectedQueries)]
 	w.ops++
 
-	query := queriesByNumber[queryNum]
+	query := QueriesByNumber[queryNum]
 
 	var rows *gosql.Rows
 	var err error
@@ -10,7 +10,8 @@
 
 package tpch
 
-var queriesByName = map[string]string{
+// QueriesByName maps the TPC-H query numbers to the text of the queries.
+var QueriesByName = map[string]string{
 	`1`:  query1,
 	`2`:  query2,
 	`3`:  query3,
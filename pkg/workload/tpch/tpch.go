@@ -130,7 +130,7 @@ func (w *tpch) Hooks() workload.Hooks {
 				w.disableChecks = true
 			}
 			for _, queryName := range strings.Split(w.queriesRaw, `,`) {
-				if _, ok := queriesByName[queryName]; !ok {
+				if _, ok := QueriesByName[queryName]; !ok {
 					return errors.Errorf(`unknown query: %s`, queryName)
 				}
 				w.selectedQueries = append(w.selectedQueries, queryName)
@@ -328,7 +328,7 @@ func (w *worker) run(ctx context.Context) error {
 	queryName := w.config.selectedQueries[w.ops%len(w.config.selectedQueries)]
 	w.ops++
 
-	query := fmt.Sprintf("SET vectorize = '%s'; %s", w.config.vectorize, queriesByName[queryName])
+	query := fmt.Sprintf("SET vectorize = '%s'; %s", w.config.vectorize, QueriesByName[queryName])
 
 	vals := make([]interface{}, maxCols)
 	for i := range vals {